@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"hotel-soa/db"
+	"hotel-soa/migrations"
+	"os"
+	"strconv"
+)
+
+const usage = `usage: migrate <command>
+
+commands:
+  up        apply every pending migration
+  down      roll back the last applied migration
+  status    list migrations and whether they are applied
+  to N      migrate up or down until version N (0 rolls back everything)`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Println(usage)
+		os.Exit(2)
+	}
+
+	migrator, err := migrations.NewMigrator(db.GetDB())
+	if err != nil {
+		fail(err)
+	}
+
+	switch os.Args[1] {
+	case "up":
+		err = migrator.Up()
+	case "down":
+		err = migrator.Down()
+	case "to":
+		if len(os.Args) < 3 {
+			fmt.Println(usage)
+			os.Exit(2)
+		}
+		version, convErr := strconv.Atoi(os.Args[2])
+		if convErr != nil {
+			fail(fmt.Errorf("invalid version: %s", os.Args[2]))
+		}
+		err = migrator.To(version)
+	case "status":
+		err = printStatus(migrator)
+	default:
+		fmt.Println(usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		fail(err)
+	}
+
+	if os.Args[1] != "status" {
+		fmt.Println("Migrations completed.")
+		printStatus(migrator)
+	}
+}
+
+func printStatus(migrator *migrations.Migrator) error {
+	status, err := migrator.Status()
+	if err != nil {
+		return err
+	}
+	for _, s := range status {
+		applied := "pending"
+		if s.Applied {
+			applied = "applied at " + s.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+		fmt.Printf("%04d  %-40s %s\n", s.Version, s.Name, applied)
+	}
+	return nil
+}
+
+func fail(err error) {
+	fmt.Println("Error:", err)
+	os.Exit(1)
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"hotel-soa/dao"
+	"hotel-soa/db"
+	"hotel-soa/helper"
+	"hotel-soa/migrations"
+	"hotel-soa/model"
+	"hotel-soa/service"
+	"os"
+)
+
+// Fecha a data de negócio no Postgres: lança as diárias das reservas em casa, grava o
+// resumo do dia e trava as noites até a data. Pode ser repetido para a mesma data.
+func main() {
+	date := flag.String("date", "", "business date to close (YYYY-MM-DD); defaults to the day after the last closed one")
+	flag.Parse()
+	if err := model.SetCurrency(helper.GetCurrency()); err != nil {
+		fail(err)
+	}
+
+	conn := db.GetDB()
+	migrator, err := migrations.NewMigrator(conn)
+	if err != nil {
+		fail(err)
+	}
+	if err := migrator.EnsureUpToDate(); err != nil {
+		fail(err)
+	}
+
+	audits := service.NewNightAuditService(dao.NewPostgresNightAuditRepository(conn), dao.NewPostgresReservationRepository(conn), dao.NewPostgresRoomRepository(conn))
+	audit, closed, err := audits.Run(*date, model.Actor{Username: "night-audit"})
+	if err != nil {
+		fail(err)
+	}
+
+	if closed {
+		fmt.Printf("Business date %s closed.\n", audit.BusinessDate)
+	} else {
+		fmt.Printf("Business date %s was already closed at %s by %s.\n",
+			audit.BusinessDate, audit.ClosedAt.Format("2006-01-02 15:04:05"), audit.ClosedBy)
+	}
+	summary := audit.Summary
+	fmt.Printf("  charges posted   %d\n", audit.ChargesPosted)
+	fmt.Printf("  rooms occupied   %d of %d (%.2f%%)\n", summary.RoomsOccupied, summary.RoomsAvailable, summary.OccupancyRate)
+	fmt.Printf("  room revenue     %s %s (ADR %s)\n", summary.RoomRevenue, summary.RoomRevenue.Currency(), summary.ADR)
+	fmt.Printf("  arrivals         %d\n", summary.Arrivals)
+	fmt.Printf("  departures       %d\n", summary.Departures)
+	fmt.Printf("  no-shows         %d\n", summary.NoShows)
+}
+
+func fail(err error) {
+	fmt.Println("Error:", err)
+	os.Exit(1)
+}
@@ -3,7 +3,10 @@ package main
 import (
 	"fmt"
 	"hotel-soa/db"
+	"hotel-soa/helper"
+	"hotel-soa/migrations"
 	"hotel-soa/model"
+	"os"
 	"time"
 
 	"github.com/google/uuid"
@@ -11,54 +14,31 @@ import (
 
 func main() {
 	fmt.Println("Starting setup...")
+	if err := model.SetCurrency(helper.GetCurrency()); err != nil {
+		fmt.Println("Error reading CURRENCY:", err)
+		os.Exit(1)
+	}
 	createTables()
 	seedTables()
 	fmt.Println("Setup completed.")
 }
 
 func createTables() {
-	fmt.Println("Creating tables...")
-	createRoomTable()
-	createReservationTable()
-}
-
-func createRoomTable() {
-	fmt.Println("Creating room table...")
-	query := `CREATE TABLE IF NOT EXISTS rooms (
-		id CHAR(36) PRIMARY KEY,
-		number INT NOT NULL UNIQUE,
-		type VARCHAR(20) NOT NULL,
-		capacity INT NOT NULL,
-		price_per_night DECIMAL(10,2) NOT NULL,
-		status VARCHAR(20) NOT NULL
-	);`
-	_, err := db.GetDB().Exec(query)
-	if err != nil {
-		fmt.Println("Error creating room table:", err)
+	fmt.Println("Applying migrations...")
+	migrator, err := migrations.NewMigrator(db.GetDB())
+	if err == nil {
+		err = migrator.Up()
 	}
-}
-
-func createReservationTable() {
-	fmt.Println("Creating reservation table...")
-	query := `CREATE TABLE IF NOT EXISTS reservations (
-		id CHAR(36) PRIMARY KEY,
-		room_id CHAR(36) NOT NULL,
-		guest_name VARCHAR(120) NOT NULL,
-		checkin_expected DATE NOT NULL,
-		checkout_expected DATE NOT NULL,
-		status VARCHAR(20) NOT NULL,
-		total_amount DECIMAL(10,2),
-		CONSTRAINT fk_reservation_room FOREIGN KEY (room_id) REFERENCES rooms(id)
-	);`
-	_, err := db.GetDB().Exec(query)
 	if err != nil {
-		fmt.Println("Error creating reservation table:", err)
+		fmt.Println("Error applying migrations:", err)
+		os.Exit(1)
 	}
 }
 
 func seedTables() {
 	fmt.Println("Seeding tables...")
 	seedRoomTable()
+	seedGuestTable()
 	seedReservationTable()
 }
 
@@ -67,18 +47,18 @@ func seedRoomTable() {
 	fmt.Println("Seeding room table...")
 
 	rooms := []model.Room{
-		{ID: uuid.NewString(), Number: 101, Type: "STANDARD", Capacity: 1, PricePerNight: 120.50, Status: "ATIVO"},
-		{ID: uuid.NewString(), Number: 102, Type: "STANDARD", Capacity: 2, PricePerNight: 180.00, Status: "ATIVO"},
-		{ID: uuid.NewString(), Number: 201, Type: "DELUXE", Capacity: 3, PricePerNight: 250.00, Status: "ATIVO"},
-		{ID: uuid.NewString(), Number: 202, Type: "DELUXE", Capacity: 2, PricePerNight: 300.00, Status: "INATIVO"},
-		{ID: uuid.NewString(), Number: 301, Type: "SUITE", Capacity: 4, PricePerNight: 500.00, Status: "ATIVO"},
+		{ID: uuid.NewString(), Number: 101, Type: "STANDARD", Capacity: 1, PricePerNight: model.NewMoney(12050), Status: "ATIVO"},
+		{ID: uuid.NewString(), Number: 102, Type: "STANDARD", Capacity: 2, PricePerNight: model.NewMoney(18000), Status: "ATIVO"},
+		{ID: uuid.NewString(), Number: 201, Type: "DELUXE", Capacity: 3, PricePerNight: model.NewMoney(25000), Status: "ATIVO"},
+		{ID: uuid.NewString(), Number: 202, Type: "DELUXE", Capacity: 2, PricePerNight: model.NewMoney(30000), Status: "INATIVO"},
+		{ID: uuid.NewString(), Number: 301, Type: "SUITE", Capacity: 4, PricePerNight: model.NewMoney(50000), Status: "ATIVO"},
 	}
 
 	for _, r := range rooms {
 		_, err := db.GetDB().Exec(`
 			INSERT INTO rooms (id, number, type, capacity, price_per_night, status)
 			VALUES ($1, $2, $3, $4, $5, $6)
-			ON CONFLICT (number) DO NOTHING;`,
+			ON CONFLICT (number) WHERE deleted_at IS NULL DO NOTHING;`,
 			r.ID, r.Number, r.Type, r.Capacity, r.PricePerNight, r.Status)
 		if err != nil {
 			fmt.Println("Error seeding room:", err)
@@ -88,6 +68,32 @@ func seedRoomTable() {
 	fmt.Println("Rooms seeded successfully.")
 }
 
+// Seeder de hóspedes
+func seedGuestTable() {
+	fmt.Println("Seeding guest table...")
+
+	guests := []model.Guest{
+		{ID: uuid.NewString(), Name: "Alice Silva", DocumentNumber: "111.444.777-35", Email: "alice@example.com", Nationality: "Brasileira"},
+		{ID: uuid.NewString(), Name: "Bruno Lima", DocumentNumber: "222.555.888-46", Email: "bruno@example.com", Nationality: "Brasileira"},
+		{ID: uuid.NewString(), Name: "Carla Souza", DocumentNumber: "333.666.999-57", Email: "carla@example.com", Nationality: "Brasileira"},
+		{ID: uuid.NewString(), Name: "Daniel Rocha", DocumentNumber: "FX123456", Email: "daniel@example.com", Nationality: "Portuguesa"},
+		{ID: uuid.NewString(), Name: "Elisa Costa", DocumentNumber: "444.777.000-68", Email: "elisa@example.com", Nationality: "Brasileira"},
+	}
+
+	for _, g := range guests {
+		_, err := db.GetDB().Exec(`
+			INSERT INTO guests (id, name, document_number, email, nationality)
+			VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (document_number) DO NOTHING;`,
+			g.ID, g.Name, g.DocumentNumber, g.Email, g.Nationality)
+		if err != nil {
+			fmt.Println("Error seeding guest:", err)
+		}
+	}
+
+	fmt.Println("Guests seeded successfully.")
+}
+
 // Seeder de reservas
 func seedReservationTable() {
 	fmt.Println("Seeding reservation table...")
@@ -111,24 +117,51 @@ func seedReservationTable() {
 		return
 	}
 
+	guestIDs := map[string]string{}
+	guestRows, err := db.GetDB().Query("SELECT id, name FROM guests;")
+	if err != nil {
+		fmt.Println("Error fetching guests for reservation:", err)
+		return
+	}
+	defer guestRows.Close()
+	for guestRows.Next() {
+		var id, name string
+		guestRows.Scan(&id, &name)
+		guestIDs[name] = id
+	}
+
 	today := time.Now().Format("2006-01-02")
 	twoDays := time.Now().AddDate(0, 0, 2).Format("2006-01-02")
 	threeDays := time.Now().AddDate(0, 0, 3).Format("2006-01-02")
 
 	reservations := []model.Reservation{
-		{ID: uuid.NewString(), RoomID: roomIDs[0], GuestName: "Alice Silva", CheckinExpected: today, CheckoutExpected: twoDays, Status: "CREATED", TotalAmount: 240.00},
-		{ID: uuid.NewString(), RoomID: roomIDs[1], GuestName: "Bruno Lima", CheckinExpected: today, CheckoutExpected: threeDays, Status: "CHECKED_IN", TotalAmount: 540.00},
-		{ID: uuid.NewString(), RoomID: roomIDs[2], GuestName: "Carla Souza", CheckinExpected: today, CheckoutExpected: twoDays, Status: "CHECKED_OUT", TotalAmount: 500.00},
-		{ID: uuid.NewString(), RoomID: roomIDs[3], GuestName: "Daniel Rocha", CheckinExpected: today, CheckoutExpected: threeDays, Status: "CREATED", TotalAmount: 900.00},
-		{ID: uuid.NewString(), RoomID: roomIDs[4], GuestName: "Elisa Costa", CheckinExpected: today, CheckoutExpected: twoDays, Status: "CANCELED", TotalAmount: 0.00},
+		{ID: uuid.NewString(), RoomID: roomIDs[0], GuestName: "Alice Silva", CheckinExpected: today, CheckoutExpected: twoDays, Status: "CREATED"},
+		{ID: uuid.NewString(), RoomID: roomIDs[1], GuestName: "Bruno Lima", CheckinExpected: today, CheckoutExpected: threeDays, Status: "CHECKED_IN"},
+		{ID: uuid.NewString(), RoomID: roomIDs[2], GuestName: "Carla Souza", CheckinExpected: today, CheckoutExpected: twoDays, Status: "CHECKED_OUT"},
+		{ID: uuid.NewString(), RoomID: roomIDs[3], GuestName: "Daniel Rocha", CheckinExpected: today, CheckoutExpected: threeDays, Status: "CREATED"},
+		{ID: uuid.NewString(), RoomID: roomIDs[4], GuestName: "Elisa Costa", CheckinExpected: today, CheckoutExpected: twoDays, Status: "CANCELED"},
 	}
 
+	// as noites saem do preço do quarto e total_amount é a soma delas, como na API
 	for _, r := range reservations {
 		_, err := db.GetDB().Exec(`
-			INSERT INTO reservations (id, room_id, guest_name, checkin_expected, checkout_expected, status, total_amount)
-			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			INSERT INTO reservations (id, room_id, room_type, guest_id, guest_name, checkin_expected, checkout_expected, status, total_amount)
+			SELECT $1, $2, rm.type, $3, $4, $5, $6, $7, 0 FROM rooms rm WHERE rm.id = $2
 			ON CONFLICT (id) DO NOTHING;`,
-			r.ID, r.RoomID, r.GuestName, r.CheckinExpected, r.CheckoutExpected, r.Status, r.TotalAmount)
+			r.ID, r.RoomID, guestIDs[r.GuestName], r.GuestName, r.CheckinExpected, r.CheckoutExpected, r.Status)
+		if err == nil {
+			_, err = db.GetDB().Exec(`
+				INSERT INTO reservation_nights (reservation_id, night, price)
+				SELECT $1, d::date, rm.price_per_night
+				FROM generate_series($2::date, $3::date - 1, interval '1 day') d, rooms rm
+				WHERE rm.id = $4;`,
+				r.ID, r.CheckinExpected, r.CheckoutExpected, r.RoomID)
+		}
+		if err == nil {
+			_, err = db.GetDB().Exec(`UPDATE reservations SET total_amount =
+				(SELECT COALESCE(SUM(price), 0) FROM reservation_nights WHERE reservation_id = $1)
+				WHERE id = $1;`, r.ID)
+		}
 		if err != nil {
 			fmt.Println("Error seeding reservation:", err)
 		}
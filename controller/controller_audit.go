@@ -0,0 +1,43 @@
+package controller
+
+import (
+	"net/http"
+
+	"hotel-soa/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AuditController expõe o audit_log para consulta
+type AuditController struct {
+	service service.AuditService
+}
+
+// NewAuditController cria um novo AuditController
+func NewAuditController(s service.AuditService) *AuditController {
+	return &AuditController{service: s}
+}
+
+// @Summary Histórico de alterações de uma entidade
+// @Description Lista, em ordem de gravação, quem alterou o quarto ou a reserva, quando, em qual requisição e o que mudou. Exige audit:read
+// @Tags audit
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param entity query string true "Entidade" Enums(room, reservation)
+// @Param id query string true "ID da entidade (UUID)"
+// @Success 200 {array} model.AuditEntry
+// @Failure 400 {object} model.Problem
+// @Failure 401 {object} model.Problem
+// @Failure 403 {object} model.Problem
+// @Failure 500 {object} model.Problem
+// @Router /audit [get]
+func (ac *AuditController) List(c *gin.Context) {
+	entries, err := ac.service.List(c.Query("entity"), c.Query("id"))
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, entries)
+}
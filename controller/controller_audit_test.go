@@ -0,0 +1,47 @@
+package controller
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"hotel-soa/dao"
+	"hotel-soa/model"
+	"hotel-soa/service"
+)
+
+func TestAuditEndpoint(t *testing.T) {
+	repos := dao.NewMemoryRepositories()
+	rc := NewRoomController(service.NewRoomService(repos.Rooms, repos.Policies))
+	ac := NewAuditController(service.NewAuditService(repos.Audit))
+	r := newTestRouter()
+	r.POST("/rooms", rc.Create)
+	r.GET("/audit", ac.List)
+
+	// o X-Request-ID do cliente chega ao audit_log e volta na resposta
+	req := httptest.NewRequest(http.MethodPost, "/rooms", strings.NewReader(`{"number":101,"type":"STANDARD","capacity":2,"price_per_night":100,"status":"ATIVO"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(requestIDHeader, "req-123")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusCreated || w.Header().Get(requestIDHeader) != "req-123" {
+		t.Fatalf("expected 201 echoing the request id, got %d %q: %s", w.Code, w.Header().Get(requestIDHeader), w.Body)
+	}
+	var room model.Room
+	decodeBody(t, w, &room)
+
+	w = performRequest(r, http.MethodGet, "/audit?entity=room&id="+room.ID, "")
+	var entries []model.AuditEntry
+	decodeBody(t, w, &entries)
+	if w.Code != http.StatusOK || len(entries) != 1 {
+		t.Fatalf("expected one entry, got %d %s", w.Code, w.Body)
+	}
+	if entry := entries[0]; entry.Action != model.AuditActionCreate || entry.Actor != model.RoleAdmin || entry.RequestID != "req-123" {
+		t.Fatalf("unexpected entry %+v", entry)
+	}
+
+	if w := performRequest(r, http.MethodGet, "/audit?entity=guest&id="+room.ID, ""); w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for an unknown entity, got %d: %s", w.Code, w.Body)
+	}
+}
@@ -0,0 +1,322 @@
+package controller
+
+import (
+	"net/http"
+	"strings"
+
+	"hotel-soa/model"
+	"hotel-soa/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// principalKey guarda o model.Principal autenticado no contexto do gin
+const principalKey = "principal"
+
+// apiKeyHeader é o header alternativo ao Authorization para chaves de API
+const apiKeyHeader = "X-API-Key"
+
+var errMissingCredentials = &service.Error{
+	Kind:   service.ErrUnauthorized,
+	Code:   "missing_credentials",
+	Detail: "send a bearer token in Authorization or an api key in " + apiKeyHeader,
+}
+
+// AuthController gerencia login, tokens, usuários e chaves de API
+type AuthController struct {
+	service service.AuthService
+}
+
+// NewAuthController cria um novo AuthController
+func NewAuthController(s service.AuthService) *AuthController {
+	return &AuthController{service: s}
+}
+
+// RequireAuth aceita um access token (Authorization: Bearer) ou uma chave de API
+// (X-API-Key) e guarda o Principal no contexto; sem credencial válida responde 401
+func (ac *AuthController) RequireAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var (
+			principal model.Principal
+			err       error
+		)
+		if key := c.GetHeader(apiKeyHeader); key != "" {
+			principal, err = ac.service.AuthenticateAPIKey(key)
+		} else if token, ok := bearerToken(c.GetHeader("Authorization")); ok {
+			principal, err = ac.service.Authenticate(token)
+		} else {
+			err = errMissingCredentials
+		}
+		if err != nil {
+			writeProblem(c, err)
+			return
+		}
+
+		c.Set(principalKey, principal)
+		c.Next()
+	}
+}
+
+// RequirePermission responde 403 citando a permissão quando o principal não a tem;
+// deve vir depois de RequireAuth
+func RequirePermission(permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !currentPrincipal(c).Can(permission) {
+			writeProblem(c, &model.PermissionError{Permission: permission})
+			return
+		}
+		c.Next()
+	}
+}
+
+func bearerToken(header string) (string, bool) {
+	scheme, token, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+// currentPrincipal retorna quem está autenticado; só é válido atrás de RequireAuth
+func currentPrincipal(c *gin.Context) model.Principal {
+	principal, _ := c.MustGet(principalKey).(model.Principal)
+	return principal
+}
+
+// @Summary Autentica um usuário
+// @Description Confere usuário e senha e devolve access e refresh tokens
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param credentials body model.LoginRequest true "Credenciais"
+// @Success 200 {object} model.TokenPair
+// @Failure 400 {object} model.Problem
+// @Failure 401 {object} model.Problem
+// @Failure 500 {object} model.Problem
+// @Router /auth/login [post]
+func (ac *AuthController) Login(c *gin.Context) {
+	var req model.LoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeBindError(c, err)
+		return
+	}
+
+	tokens, err := ac.service.Login(req.Username, req.Password)
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+// @Summary Renova os tokens
+// @Description Troca um refresh token válido por um novo par de tokens
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param token body model.RefreshRequest true "Refresh token"
+// @Success 200 {object} model.TokenPair
+// @Failure 400 {object} model.Problem
+// @Failure 401 {object} model.Problem
+// @Failure 500 {object} model.Problem
+// @Router /auth/refresh [post]
+func (ac *AuthController) Refresh(c *gin.Context) {
+	var req model.RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeBindError(c, err)
+		return
+	}
+
+	tokens, err := ac.service.Refresh(req.RefreshToken)
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+// @Summary Encerra a sessão
+// @Description Invalida todos os refresh tokens já emitidos para o usuário autenticado. Os access tokens valem até expirar
+// @Tags auth
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Success 204
+// @Failure 401 {object} model.Problem
+// @Failure 500 {object} model.Problem
+// @Router /auth/logout [post]
+func (ac *AuthController) Logout(c *gin.Context) {
+	if err := ac.service.Logout(currentPrincipal(c).UserID); err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Troca a senha
+// @Description Confere a senha atual e grava a nova no usuário autenticado; os refresh tokens já emitidos deixam de valer
+// @Tags auth
+// @Accept json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param password body model.PasswordChangeRequest true "Senhas"
+// @Success 204
+// @Failure 400 {object} model.Problem
+// @Failure 401 {object} model.Problem
+// @Failure 500 {object} model.Problem
+// @Router /auth/password [put]
+func (ac *AuthController) ChangePassword(c *gin.Context) {
+	var req model.PasswordChangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeBindError(c, err)
+		return
+	}
+
+	if err := ac.service.ChangePassword(currentPrincipal(c).UserID, req); err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Retorna o usuário autenticado
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Success 200 {object} model.Principal
+// @Failure 401 {object} model.Problem
+// @Router /auth/me [get]
+func (ac *AuthController) Me(c *gin.Context) {
+	c.JSON(http.StatusOK, currentPrincipal(c))
+}
+
+// @Summary Cria um usuário
+// @Description Cadastra um usuário com senha (guardada com bcrypt) e papéis; sem papéis ele recebe read_only. Exige users:manage
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param user body model.UserRequest true "Usuário"
+// @Success 201 {object} model.User
+// @Failure 400 {object} model.Problem
+// @Failure 401 {object} model.Problem
+// @Failure 403 {object} model.Problem
+// @Failure 409 {object} model.Problem
+// @Failure 500 {object} model.Problem
+// @Router /auth/users [post]
+func (ac *AuthController) CreateUser(c *gin.Context) {
+	var req model.UserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeBindError(c, err)
+		return
+	}
+
+	user, err := ac.service.CreateUser(req)
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, user)
+}
+
+// @Summary Lista os usuários
+// @Description Lista os usuários com seus papéis. Exige users:manage
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Success 200 {array} model.User
+// @Failure 401 {object} model.Problem
+// @Failure 403 {object} model.Problem
+// @Failure 500 {object} model.Problem
+// @Router /auth/users [get]
+func (ac *AuthController) ListUsers(c *gin.Context) {
+	users, err := ac.service.ListUsers()
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, users)
+}
+
+// @Summary Cria uma chave de API
+// @Description Gera uma chave de longa duração para o usuário autenticado. A chave só é exibida nesta resposta
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param key body model.APIKeyRequest true "Chave"
+// @Success 201 {object} model.APIKeyCreated
+// @Failure 400 {object} model.Problem
+// @Failure 401 {object} model.Problem
+// @Failure 500 {object} model.Problem
+// @Router /auth/api-keys [post]
+func (ac *AuthController) CreateAPIKey(c *gin.Context) {
+	var req model.APIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeBindError(c, err)
+		return
+	}
+
+	key, err := ac.service.CreateAPIKey(currentPrincipal(c).UserID, req.Name)
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, key)
+}
+
+// @Summary Lista as chaves de API
+// @Description Lista as chaves do usuário autenticado, incluindo as revogadas
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Success 200 {array} model.APIKey
+// @Failure 401 {object} model.Problem
+// @Failure 500 {object} model.Problem
+// @Router /auth/api-keys [get]
+func (ac *AuthController) ListAPIKeys(c *gin.Context) {
+	keys, err := ac.service.ListAPIKeys(currentPrincipal(c).UserID)
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, keys)
+}
+
+// @Summary Revoga uma chave de API
+// @Tags auth
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path string true "ID da chave (UUID)"
+// @Success 204
+// @Failure 401 {object} model.Problem
+// @Failure 404 {object} model.Problem
+// @Failure 500 {object} model.Problem
+// @Router /auth/api-keys/{id} [delete]
+func (ac *AuthController) RevokeAPIKey(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		writeProblem(c, errInvalidID)
+		return
+	}
+
+	if err := ac.service.RevokeAPIKey(currentPrincipal(c).UserID, id); err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
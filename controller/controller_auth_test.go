@@ -0,0 +1,159 @@
+package controller
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"hotel-soa/dao"
+	"hotel-soa/model"
+	"hotel-soa/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// performRequestWithHeader é o performRequest com um header extra, para as credenciais
+func performRequestWithHeader(r http.Handler, method, path, body, key, value string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		req.Header.Set(key, value)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestAuthEndpoints(t *testing.T) {
+	repos := dao.NewMemoryRepositories()
+	if err := service.NewRoleService(repos.Roles, repos.Users).EnsureDefaults(); err != nil {
+		t.Fatal(err)
+	}
+	auth := service.NewAuthService(repos.Users, repos.APIKeys, repos.Roles, "test-secret", 15*time.Minute, time.Hour)
+	if err := auth.EnsureAdmin("admin", "admin password"); err != nil {
+		t.Fatal(err)
+	}
+	ac := NewAuthController(auth)
+	r := gin.New()
+	r.POST("/auth/login", ac.Login)
+	r.POST("/auth/refresh", ac.Refresh)
+	r.GET("/auth/me", ac.RequireAuth(), ac.Me)
+	r.POST("/auth/api-keys", ac.RequireAuth(), ac.CreateAPIKey)
+
+	if w := performRequest(r, http.MethodPost, "/auth/login", `{"username":"admin","password":"wrong password"}`); w.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 for a wrong password, got %d: %s", w.Code, w.Body)
+	}
+	w := performRequest(r, http.MethodPost, "/auth/login", `{"username":"admin","password":"admin password"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body)
+	}
+	var tokens model.TokenPair
+	decodeBody(t, w, &tokens)
+
+	w = performRequest(r, http.MethodPost, "/auth/refresh", `{"refresh_token":"`+tokens.RefreshToken+`"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200 refreshing, got %d: %s", w.Code, w.Body)
+	}
+
+	w = performRequestWithHeader(r, http.MethodPost, "/auth/api-keys", `{"name":"pms"}`, "Authorization", "Bearer "+tokens.AccessToken)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body)
+	}
+	var key model.APIKeyCreated
+	decodeBody(t, w, &key)
+
+	tests := []struct {
+		name          string
+		header, value string
+		status        int
+		code          string
+	}{
+		{"no credentials", "", "", http.StatusUnauthorized, "missing_credentials"},
+		{"other scheme", "Authorization", "Basic YWRtaW46YWRtaW4=", http.StatusUnauthorized, "missing_credentials"},
+		{"invalid token", "Authorization", "Bearer not-a-token", http.StatusUnauthorized, "invalid_token"},
+		{"refresh token", "Authorization", "Bearer " + tokens.RefreshToken, http.StatusUnauthorized, "invalid_token"},
+		{"invalid api key", apiKeyHeader, "hk_unknown", http.StatusUnauthorized, "invalid_api_key"},
+		{"access token", "Authorization", "bearer " + tokens.AccessToken, http.StatusOK, ""},
+		{"api key", apiKeyHeader, key.Key, http.StatusOK, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := performRequestWithHeader(r, http.MethodGet, "/auth/me", "", tt.header, tt.value)
+			if w.Code != tt.status {
+				t.Fatalf("expected %d, got %d: %s", tt.status, w.Code, w.Body)
+			}
+			if tt.code != "" {
+				var problem model.Problem
+				decodeBody(t, w, &problem)
+				if problem.Code != tt.code {
+					t.Fatalf("expected %s, got %+v", tt.code, problem)
+				}
+				return
+			}
+			var principal model.Principal
+			decodeBody(t, w, &principal)
+			if principal.Username != "admin" {
+				t.Fatalf("expected admin, got %+v", principal)
+			}
+		})
+	}
+}
+
+func TestLogoutAndChangePassword(t *testing.T) {
+	repos := dao.NewMemoryRepositories()
+	if err := service.NewRoleService(repos.Roles, repos.Users).EnsureDefaults(); err != nil {
+		t.Fatal(err)
+	}
+	auth := service.NewAuthService(repos.Users, repos.APIKeys, repos.Roles, "test-secret", 15*time.Minute, time.Hour)
+	if err := auth.EnsureAdmin("admin", "admin password"); err != nil {
+		t.Fatal(err)
+	}
+	ac := NewAuthController(auth)
+	r := gin.New()
+	r.POST("/auth/login", ac.Login)
+	r.POST("/auth/refresh", ac.Refresh)
+	r.POST("/auth/logout", ac.RequireAuth(), ac.Logout)
+	r.PUT("/auth/password", ac.RequireAuth(), ac.ChangePassword)
+
+	login := func(password string) model.TokenPair {
+		t.Helper()
+		w := performRequest(r, http.MethodPost, "/auth/login", `{"username":"admin","password":"`+password+`"}`)
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", w.Code, w.Body)
+		}
+		var tokens model.TokenPair
+		decodeBody(t, w, &tokens)
+		return tokens
+	}
+	expectRevoked := func(tokens model.TokenPair) {
+		t.Helper()
+		w := performRequest(r, http.MethodPost, "/auth/refresh", `{"refresh_token":"`+tokens.RefreshToken+`"}`)
+		var problem model.Problem
+		decodeBody(t, w, &problem)
+		if w.Code != http.StatusUnauthorized || problem.Code != "token_revoked" {
+			t.Fatalf("expected 401 token_revoked, got %d: %+v", w.Code, problem)
+		}
+	}
+
+	tokens := login("admin password")
+	if w := performRequestWithHeader(r, http.MethodPost, "/auth/logout", "", "Authorization", "Bearer "+tokens.AccessToken); w.Code != http.StatusNoContent {
+		t.Fatalf("expected 204 logging out, got %d: %s", w.Code, w.Body)
+	}
+	expectRevoked(tokens)
+
+	tokens = login("admin password")
+	bearer := "Bearer " + tokens.AccessToken
+	if w := performRequestWithHeader(r, http.MethodPut, "/auth/password", `{"current_password":"wrong password","new_password":"new password"}`, "Authorization", bearer); w.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 for a wrong current password, got %d: %s", w.Code, w.Body)
+	}
+	if w := performRequestWithHeader(r, http.MethodPut, "/auth/password", `{"current_password":"admin password","new_password":"short"}`, "Authorization", bearer); w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for a short password, got %d: %s", w.Code, w.Body)
+	}
+	if w := performRequestWithHeader(r, http.MethodPut, "/auth/password", `{"current_password":"admin password","new_password":"new password"}`, "Authorization", bearer); w.Code != http.StatusNoContent {
+		t.Fatalf("expected 204 changing the password, got %d: %s", w.Code, w.Body)
+	}
+	expectRevoked(tokens)
+	login("new password")
+}
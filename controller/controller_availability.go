@@ -0,0 +1,84 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"hotel-soa/model"
+	"hotel-soa/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AvailabilityController gerencia a busca de disponibilidade
+type AvailabilityController struct {
+	service service.AvailabilityService
+}
+
+// NewAvailabilityController cria um novo AvailabilityController
+func NewAvailabilityController(s service.AvailabilityService) *AvailabilityController {
+	return &AvailabilityController{service: s}
+}
+
+// @Summary Busca quartos disponíveis
+// @Description Retorna os quartos ativos, com capacidade suficiente e sem reserva no período, com o preço da estadia. Os quartos de um tipo esgotado por reservas ainda sem quarto ficam de fora
+// @Tags availability
+// @Produce json
+// @Param checkin query string true "Data de checkin (YYYY-MM-DD)"
+// @Param checkout query string true "Data de checkout (YYYY-MM-DD)"
+// @Param guests query int false "Quantidade de hóspedes (padrão 1)"
+// @Param type query string false "Tipo de quarto (STANDARD, DELUXE, SUITE)"
+// @Success 200 {array} model.AvailableRoom
+// @Failure 400 {object} model.Problem
+// @Failure 500 {object} model.Problem
+// @Router /availability [get]
+func (ac *AvailabilityController) Search(c *gin.Context) {
+	query := model.AvailabilityQuery{
+		Checkin:  c.Query("checkin"),
+		Checkout: c.Query("checkout"),
+		Type:     c.Query("type"),
+	}
+	if guests := c.Query("guests"); guests != "" {
+		n, err := strconv.Atoi(guests)
+		if err != nil || n <= 0 {
+			var v model.ValidationError
+			v.Add("guests", "out_of_range", "must be a positive integer")
+			writeProblem(c, v.Err())
+			return
+		}
+		query.Guests = n
+	}
+
+	rooms, err := ac.service.Search(query)
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, rooms)
+}
+
+// @Summary Busca a disponibilidade por tipo de quarto
+// @Description Retorna, por tipo, os quartos ATIVO não arquivados (total_rooms), os que o check-in pode atribuir, e quantos ainda podem ser reservados no período (available): o total menos as reservas, com ou sem quarto, e os bloqueios da noite mais cheia. O preço é o de uma reserva feita só pelo tipo e não vem quando um plano tarifário barra a estadia
+// @Tags availability
+// @Produce json
+// @Param checkin query string true "Data de checkin (YYYY-MM-DD)"
+// @Param checkout query string true "Data de checkout (YYYY-MM-DD)"
+// @Param type query string false "Tipo de quarto (STANDARD, DELUXE, SUITE)"
+// @Success 200 {array} model.RoomTypeAvailability
+// @Failure 400 {object} model.Problem
+// @Failure 500 {object} model.Problem
+// @Router /availability/room-types [get]
+func (ac *AvailabilityController) RoomTypes(c *gin.Context) {
+	types, err := ac.service.RoomTypes(model.AvailabilityQuery{
+		Checkin:  c.Query("checkin"),
+		Checkout: c.Query("checkout"),
+		Type:     c.Query("type"),
+	})
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, types)
+}
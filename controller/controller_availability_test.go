@@ -0,0 +1,65 @@
+package controller
+
+import (
+	"net/http"
+	"testing"
+
+	"hotel-soa/dao"
+	"hotel-soa/model"
+	"hotel-soa/service"
+)
+
+func TestAvailabilitySearchEndpoint(t *testing.T) {
+	repos := dao.NewMemoryRepositories()
+	if _, err := repos.Rooms.InsertRoom(model.Room{Number: 101, Type: "STANDARD", Capacity: 2, PricePerNight: model.NewMoney(10000), Status: "ATIVO"}, testActor); err != nil {
+		t.Fatal(err)
+	}
+	ac := NewAvailabilityController(service.NewAvailabilityService(repos.Rooms, repos.RatePlans))
+	r := newTestRouter()
+	r.GET("/availability", ac.Search)
+
+	w := performRequest(r, http.MethodGet, "/availability?checkin=2026-06-10&checkout=2026-06-12&guests=2&type=STANDARD", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body)
+	}
+	var found []model.AvailableRoom
+	decodeBody(t, w, &found)
+	if len(found) != 1 || !found[0].TotalAmount.Equal(model.NewMoney(20000)) || len(found[0].Nights) != 2 {
+		t.Fatalf("unexpected rooms %+v", found)
+	}
+
+	for _, path := range []string{
+		"/availability?checkin=2026-06-10&checkout=2026-06-12&guests=0",
+		"/availability?checkin=2026-06-10&checkout=2026-06-12&guests=two",
+		"/availability?checkin=2026-06-12&checkout=2026-06-10",
+		"/availability",
+	} {
+		if w := performRequest(r, http.MethodGet, path, ""); w.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400 for %s, got %d: %s", path, w.Code, w.Body)
+		}
+	}
+}
+
+func TestAvailabilityRoomTypesEndpoint(t *testing.T) {
+	repos := dao.NewMemoryRepositories()
+	if _, err := repos.Rooms.InsertRoom(model.Room{Number: 101, Type: "STANDARD", Capacity: 2, PricePerNight: model.NewMoney(10000), Status: "ATIVO"}, testActor); err != nil {
+		t.Fatal(err)
+	}
+	ac := NewAvailabilityController(service.NewAvailabilityService(repos.Rooms, repos.RatePlans))
+	r := newTestRouter()
+	r.GET("/availability/room-types", ac.RoomTypes)
+
+	w := performRequest(r, http.MethodGet, "/availability/room-types?checkin=2026-06-10&checkout=2026-06-12", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body)
+	}
+	var types []model.RoomTypeAvailability
+	decodeBody(t, w, &types)
+	if len(types) != 1 || types[0].RoomType != "STANDARD" || types[0].Available != 1 || !types[0].TotalAmount.Equal(model.NewMoney(20000)) {
+		t.Fatalf("unexpected room types %+v", types)
+	}
+
+	if w := performRequest(r, http.MethodGet, "/availability/room-types?checkin=2026-06-12&checkout=2026-06-10", ""); w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d: %s", w.Code, w.Body)
+	}
+}
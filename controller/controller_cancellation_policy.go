@@ -0,0 +1,184 @@
+package controller
+
+import (
+	"net/http"
+
+	"hotel-soa/model"
+	"hotel-soa/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CancellationPolicyController gerencia endpoints de políticas de cancelamento
+type CancellationPolicyController struct {
+	service service.CancellationPolicyService
+}
+
+// NewCancellationPolicyController cria um novo CancellationPolicyController
+func NewCancellationPolicyController(s service.CancellationPolicyService) *CancellationPolicyController {
+	return &CancellationPolicyController{service: s}
+}
+
+// @Summary Cria uma nova política de cancelamento
+// @Description Cria uma política com prazo de cancelamento grátis e multa em percentual ou noites, ou uma política não reembolsável
+// @Tags cancellation-policies
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param policy body model.CancellationPolicyRequest true "Política de cancelamento"
+// @Success 201 {object} model.CancellationPolicy
+// @Failure 400 {object} model.Problem
+// @Failure 401 {object} model.Problem
+// @Failure 403 {object} model.Problem
+// @Failure 500 {object} model.Problem
+// @Router /cancellation-policies [post]
+func (rc *CancellationPolicyController) Create(c *gin.Context) {
+	var req model.CancellationPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeBindError(c, err)
+		return
+	}
+
+	policy := req.CancellationPolicy()
+	if err := policy.Validate(); err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	id, err := rc.service.Create(*policy)
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	policy.ID = id
+	c.JSON(http.StatusCreated, policy)
+}
+
+// @Summary Atualiza uma política de cancelamento existente
+// @Description Atualiza uma política pelo ID. A mudança vale também para as reservas ainda não canceladas que usam a política
+// @Tags cancellation-policies
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path string true "ID da Política (UUID)"
+// @Param policy body model.CancellationPolicyRequest true "Política de cancelamento atualizada"
+// @Success 200 {object} model.CancellationPolicy
+// @Failure 400 {object} model.Problem
+// @Failure 401 {object} model.Problem
+// @Failure 403 {object} model.Problem
+// @Failure 404 {object} model.Problem
+// @Failure 500 {object} model.Problem
+// @Router /cancellation-policies/{id} [put]
+func (rc *CancellationPolicyController) Update(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		writeProblem(c, errInvalidID)
+		return
+	}
+
+	var req model.CancellationPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeBindError(c, err)
+		return
+	}
+
+	req.ID = id
+	policy := req.CancellationPolicy()
+	if err := policy.Validate(); err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	if err := rc.service.Update(*policy); err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, policy)
+}
+
+// @Summary Deleta uma política de cancelamento
+// @Description Deleta uma política pelo ID; recusa políticas ainda ligadas a quartos, planos tarifários ou reservas
+// @Tags cancellation-policies
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path string true "ID da Política (UUID)"
+// @Success 204
+// @Failure 400 {object} model.Problem
+// @Failure 401 {object} model.Problem
+// @Failure 403 {object} model.Problem
+// @Failure 404 {object} model.Problem
+// @Failure 409 {object} model.Problem
+// @Failure 500 {object} model.Problem
+// @Router /cancellation-policies/{id} [delete]
+func (rc *CancellationPolicyController) Delete(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		writeProblem(c, errInvalidID)
+		return
+	}
+
+	if err := rc.service.Delete(id); err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Busca política de cancelamento pelo ID
+// @Description Retorna uma política de cancelamento pelo seu ID
+// @Tags cancellation-policies
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path string true "ID da Política (UUID)"
+// @Success 200 {object} model.CancellationPolicy
+// @Failure 400 {object} model.Problem
+// @Failure 401 {object} model.Problem
+// @Failure 403 {object} model.Problem
+// @Failure 404 {object} model.Problem
+// @Router /cancellation-policies/{id} [get]
+func (rc *CancellationPolicyController) GetByID(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		writeProblem(c, errInvalidID)
+		return
+	}
+
+	policy, err := rc.service.GetByID(id)
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, policy)
+}
+
+// @Summary Lista todas as políticas de cancelamento
+// @Description Retorna todas as políticas de cancelamento cadastradas
+// @Tags cancellation-policies
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Success 200 {array} model.CancellationPolicy
+// @Success 204 "No Content"
+// @Failure 401 {object} model.Problem
+// @Failure 403 {object} model.Problem
+// @Failure 500 {object} model.Problem
+// @Router /cancellation-policies [get]
+func (rc *CancellationPolicyController) GetAll(c *gin.Context) {
+	policies, err := rc.service.GetAll()
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	if len(policies) == 0 {
+		c.Status(http.StatusNoContent)
+		return
+	}
+
+	c.JSON(http.StatusOK, policies)
+}
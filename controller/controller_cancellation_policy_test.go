@@ -0,0 +1,92 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"hotel-soa/dao"
+	"hotel-soa/gateway"
+	"hotel-soa/model"
+	"hotel-soa/service"
+)
+
+func TestCancellationPolicyEndpoints(t *testing.T) {
+	repos := dao.NewMemoryRepositories()
+	pc := NewCancellationPolicyController(service.NewCancellationPolicyService(repos.Policies))
+	roomController := NewRoomController(service.NewRoomService(repos.Rooms, repos.Policies))
+	reservationController := NewReservationController(service.NewReservationService(repos.Reservations, repos.Rooms, repos.RatePlans, repos.Guests, repos.Policies, repos.Taxes, repos.Folios, repos.Holds, gateway.NewStubGateway(), 30))
+	r := newTestRouter()
+	r.POST("/cancellation-policies", pc.Create)
+	r.PUT("/cancellation-policies/:id", pc.Update)
+	r.DELETE("/cancellation-policies/:id", pc.Delete)
+	r.GET("/cancellation-policies/:id", pc.GetByID)
+	r.GET("/cancellation-policies", pc.GetAll)
+	r.POST("/rooms", roomController.Create)
+	r.POST("/reservation", reservationController.Create)
+	r.GET("/reservation/:id/cancellation-quote", reservationController.CancellationQuote)
+
+	if w := performRequest(r, http.MethodGet, "/cancellation-policies", ""); w.Code != http.StatusNoContent {
+		t.Fatalf("expected 204 without policies, got %d: %s", w.Code, w.Body)
+	}
+
+	w := performRequest(r, http.MethodPost, "/cancellation-policies", `{"name":"Strict","free_until_days":400,"penalty_value":100}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body)
+	}
+	var policy model.CancellationPolicy
+	decodeBody(t, w, &policy)
+	if policy.ID == "" || policy.PenaltyType != model.PenaltyPercent {
+		t.Fatalf("unexpected policy %+v", policy)
+	}
+
+	w = performRequest(r, http.MethodPost, "/rooms", fmt.Sprintf(`{"number":101,"type":"STANDARD","capacity":2,"price_per_night":100,"status":"ATIVO","cancellation_policy_id":%q}`, policy.ID))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body)
+	}
+	var room model.Room
+	decodeBody(t, w, &room)
+	checkin := time.Now().AddDate(0, 1, 0)
+	w = performRequest(r, http.MethodPost, "/reservation", fmt.Sprintf(`{"room_id":%q,"guest_name":"Ana","checkin_expected":%q,"checkout_expected":%q,"status":"CREATED"}`,
+		room.ID, checkin.Format("2006-01-02"), checkin.AddDate(0, 0, 2).Format("2006-01-02")))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body)
+	}
+	var res model.Reservation
+	decodeBody(t, w, &res)
+
+	// dentro do prazo da política a multa é o total
+	w = performRequest(r, http.MethodGet, "/reservation/"+res.ID+"/cancellation-quote", "")
+	var quote model.CancellationQuote
+	decodeBody(t, w, &quote)
+	if w.Code != http.StatusOK || quote.CancellationPolicyID != policy.ID || !quote.Fee.Equal(model.NewMoney(20000)) || !quote.RefundAmount.Equal(model.NewMoney(0)) {
+		t.Fatalf("expected a fee of 200, got %d: %s", w.Code, w.Body)
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		status int
+	}{
+		{"missing name", http.MethodPost, "/cancellation-policies", `{"penalty_type":"PERCENT"}`, http.StatusBadRequest},
+		{"percent above 100", http.MethodPost, "/cancellation-policies", `{"name":"Bad","penalty_value":150}`, http.StatusBadRequest},
+		{"unknown penalty type", http.MethodPut, "/cancellation-policies/" + policy.ID, `{"name":"Bad","penalty_type":"FIXED"}`, http.StatusBadRequest},
+		{"update", http.MethodPut, "/cancellation-policies/" + policy.ID, `{"name":"Strict","free_until_days":7,"penalty_type":"NIGHTS","penalty_value":1}`, http.StatusOK},
+		{"update unknown", http.MethodPut, "/cancellation-policies/00000000-0000-0000-0000-000000000000", `{"name":"Strict"}`, http.StatusNotFound},
+		{"get", http.MethodGet, "/cancellation-policies/" + policy.ID, "", http.StatusOK},
+		{"list", http.MethodGet, "/cancellation-policies", "", http.StatusOK},
+		{"delete in use", http.MethodDelete, "/cancellation-policies/" + policy.ID, "", http.StatusConflict},
+		{"quote unknown reservation", http.MethodGet, "/reservation/00000000-0000-0000-0000-000000000000/cancellation-quote", "", http.StatusNotFound},
+		{"room with unknown policy", http.MethodPost, "/rooms", `{"number":102,"type":"STANDARD","capacity":2,"price_per_night":100,"status":"ATIVO","cancellation_policy_id":"00000000-0000-0000-0000-000000000000"}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if w := performRequest(r, tt.method, tt.path, tt.body); w.Code != tt.status {
+				t.Fatalf("expected %d, got %d: %s", tt.status, w.Code, w.Body)
+			}
+		})
+	}
+}
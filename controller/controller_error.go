@@ -0,0 +1,243 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"hotel-soa/model"
+	"hotel-soa/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// problemTypePrefix forma o campo type do Problem a partir do code
+const problemTypePrefix = "urn:hotel-soa:problem:"
+
+// internalErrorCode é o code dos erros sem tradução, registrados no log por writeProblem
+const internalErrorCode = "internal_error"
+
+// errInvalidID é devolvido quando a rota chega sem o parâmetro id
+var errInvalidID = &service.Error{Kind: service.ErrInvalid, Code: "invalid_id", Detail: "invalid id"}
+
+// kindStatuses mapeia as categorias de erro do service para status HTTP e o code
+// usado quando o erro não traz um mais específico
+var kindStatuses = []struct {
+	kind   error
+	status int
+	code   string
+}{
+	{service.ErrInvalid, http.StatusBadRequest, "invalid_request"},
+	{service.ErrNotFound, http.StatusNotFound, "not_found"},
+	{service.ErrConflict, http.StatusConflict, "conflict"},
+	{service.ErrInvalidTransition, http.StatusConflict, "invalid_transition"},
+	{service.ErrUnauthorized, http.StatusUnauthorized, "unauthorized"},
+}
+
+func init() {
+	// os erros de binding citam o nome do campo no JSON, não o da struct
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(func(field reflect.StructField) string {
+			name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+			if name == "-" {
+				return ""
+			}
+			return name
+		})
+	}
+}
+
+// writeProblem é o único ponto que transforma erros em respostas HTTP
+func writeProblem(c *gin.Context, err error) {
+	problem := problemFor(err)
+	if problem.Code == internalErrorCode {
+		log.Printf("%s %s: %v", c.Request.Method, c.Request.URL.Path, err)
+	}
+	problem.Type = problemTypePrefix + problem.Code
+	problem.Title = http.StatusText(problem.Status)
+	problem.Instance = c.Request.URL.Path
+
+	if problem.Status == http.StatusUnauthorized {
+		c.Header("WWW-Authenticate", `Bearer realm="hotel-soa"`)
+	}
+	c.Header("Content-Type", model.ProblemContentType)
+	c.AbortWithStatusJSON(problem.Status, problem)
+}
+
+func problemFor(err error) model.Problem {
+	var validation *model.ValidationError
+	if errors.As(err, &validation) {
+		return model.Problem{Status: http.StatusBadRequest, Code: "validation_failed", Detail: err.Error(), Errors: validation.Errors}
+	}
+
+	var reservationConflict *model.ReservationConflictError
+	if errors.As(err, &reservationConflict) {
+		return model.Problem{
+			Status:                   http.StatusConflict,
+			Code:                     "reservation_conflict",
+			Detail:                   err.Error(),
+			ConflictingReservationID: reservationConflict.ReservationID,
+		}
+	}
+
+	var roomHeld *model.RoomHeldError
+	if errors.As(err, &roomHeld) {
+		return model.Problem{
+			Status:            http.StatusConflict,
+			Code:              "room_on_hold",
+			Detail:            err.Error(),
+			ConflictingHoldID: roomHeld.HoldID,
+			HoldExpiresAt:     &roomHeld.ExpiresAt,
+		}
+	}
+
+	var soldOut *model.RoomTypeSoldOutError
+	if errors.As(err, &soldOut) {
+		return model.Problem{
+			Status:      http.StatusConflict,
+			Code:        "room_type_sold_out",
+			Detail:      err.Error(),
+			RoomType:    soldOut.RoomType,
+			SoldOutDate: soldOut.Date,
+		}
+	}
+
+	var roomInUse *model.RoomInUseError
+	if errors.As(err, &roomInUse) {
+		return model.Problem{
+			Status:       http.StatusConflict,
+			Code:         "room_has_reservations",
+			Detail:       err.Error(),
+			Reservations: roomInUse.Reservations,
+		}
+	}
+
+	var balanceDue *model.BalanceDueError
+	if errors.As(err, &balanceDue) {
+		return model.Problem{
+			Status:  http.StatusConflict,
+			Code:    "folio_balance_due",
+			Detail:  err.Error(),
+			Balance: &balanceDue.Balance,
+		}
+	}
+
+	var paymentDeclined *model.PaymentDeclinedError
+	if errors.As(err, &paymentDeclined) {
+		return model.Problem{
+			Status:          http.StatusPaymentRequired,
+			Code:            "payment_declined",
+			Detail:          err.Error(),
+			ReservationID:   paymentDeclined.ReservationID,
+			AuthorizationID: paymentDeclined.AuthorizationID,
+			DeclineCode:     paymentDeclined.DeclineCode,
+		}
+	}
+
+	var paymentTimeout *model.PaymentTimeoutError
+	if errors.As(err, &paymentTimeout) {
+		return model.Problem{
+			Status:          http.StatusGatewayTimeout,
+			Code:            "payment_gateway_timeout",
+			Detail:          err.Error(),
+			ReservationID:   paymentTimeout.ReservationID,
+			AuthorizationID: paymentTimeout.AuthorizationID,
+		}
+	}
+
+	var constraint *model.ConstraintViolationError
+	if errors.As(err, &constraint) {
+		return model.Problem{Status: http.StatusConflict, Code: "constraint_violation", Detail: err.Error()}
+	}
+
+	var permission *model.PermissionError
+	if errors.As(err, &permission) {
+		return model.Problem{
+			Status:            http.StatusForbidden,
+			Code:              "missing_permission",
+			Detail:            err.Error(),
+			MissingPermission: permission.Permission,
+		}
+	}
+
+	for _, k := range kindStatuses {
+		if errors.Is(err, k.kind) {
+			code := k.code
+			var serviceErr *service.Error
+			if errors.As(err, &serviceErr) && serviceErr.Code != "" {
+				code = serviceErr.Code
+			}
+			return model.Problem{Status: k.status, Code: code, Detail: err.Error()}
+		}
+	}
+
+	// a mensagem de erros inesperados (driver, SQL, fmt internos) só vai para o log
+	return model.Problem{Status: http.StatusInternalServerError, Code: internalErrorCode, Detail: "an unexpected error occurred"}
+}
+
+// writeBindError traduz falhas de ShouldBindJSON em erros por campo; corpo que não é
+// JSON válido vira malformed_body
+func writeBindError(c *gin.Context, err error) {
+	var v model.ValidationError
+	var fieldErrs validator.ValidationErrors
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &fieldErrs):
+		for _, fe := range fieldErrs {
+			v.Add(fe.Field(), bindingCode(fe.Tag()), bindingMessage(fe))
+		}
+	case errors.As(err, &typeErr) && typeErr.Type == reflect.TypeOf(model.Money{}):
+		// o campo de um erro vindo de UnmarshalJSON só é preenchido por algumas versões do encoding/json
+		if typeErr.Field == "" {
+			detail := strings.TrimPrefix(typeErr.Value, "number ") + " " + moneyMessage()
+			writeProblem(c, &service.Error{Kind: service.ErrInvalid, Code: "malformed_body", Detail: detail})
+			return
+		}
+		v.Add(typeErr.Field, "invalid_type", moneyMessage())
+	case errors.As(err, &typeErr):
+		v.Add(typeErr.Field, "invalid_type", "must be of type "+typeErr.Type.String())
+	default:
+		writeProblem(c, &service.Error{Kind: service.ErrInvalid, Code: "malformed_body", Detail: err.Error()})
+		return
+	}
+	writeProblem(c, v.Err())
+}
+
+// moneyMessage descreve um valor monetário aceito na moeda da propriedade
+func moneyMessage() string {
+	return fmt.Sprintf("must be a decimal amount with at most %d decimal places (%s)", model.DecimalPlaces(), model.Currency())
+}
+
+func bindingCode(tag string) string {
+	switch tag {
+	case "required":
+		return "required"
+	case "gt", "gte", "lt", "lte", "min", "max":
+		return "out_of_range"
+	default:
+		return "invalid_value"
+	}
+}
+
+func bindingMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "gt":
+		return "must be greater than " + fe.Param()
+	case "gte", "min":
+		return "must be at least " + fe.Param()
+	case "lt":
+		return "must be less than " + fe.Param()
+	case "lte", "max":
+		return "must be at most " + fe.Param()
+	default:
+		return "failed the " + fe.Tag() + " rule"
+	}
+}
@@ -0,0 +1,99 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"hotel-soa/dao"
+	"hotel-soa/model"
+	"hotel-soa/service"
+)
+
+func TestProblemFor(t *testing.T) {
+	var validation model.ValidationError
+	validation.Add("capacity", "out_of_range", "must be greater than 0")
+
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		code   string
+	}{
+		{"validation", validation.Err(), http.StatusBadRequest, "validation_failed"},
+		{"reservation conflict", &model.ReservationConflictError{RoomID: "r", ReservationID: "x"}, http.StatusConflict, "reservation_conflict"},
+		{"service code", &service.Error{Kind: service.ErrNotFound, Code: "room_not_found", Detail: "room not found"}, http.StatusNotFound, "room_not_found"},
+		{"kind without code", &service.Error{Kind: service.ErrConflict, Detail: "busy"}, http.StatusConflict, "conflict"},
+		{"wrapped kind", fmt.Errorf("moving: %w", service.ErrInvalidTransition), http.StatusConflict, "invalid_transition"},
+		{"constraint violation", &model.ConstraintViolationError{Err: errors.New("duplicate key value violates unique constraint")}, http.StatusConflict, "constraint_violation"},
+		{"unknown error", errors.New("connection refused"), http.StatusInternalServerError, "internal_error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			problem := problemFor(tt.err)
+			if problem.Status != tt.status || problem.Code != tt.code {
+				t.Fatalf("expected %d %s, got %d %s", tt.status, tt.code, problem.Status, problem.Code)
+			}
+			// a mensagem do driver fica só no log
+			if strings.Contains(problem.Detail, "refused") || strings.Contains(problem.Detail, "duplicate key") {
+				t.Fatalf("expected the driver message to stay on the server, got %q", problem.Detail)
+			}
+		})
+	}
+}
+
+func TestProblemResponses(t *testing.T) {
+	repos := dao.NewMemoryRepositories()
+	rc := NewRoomController(service.NewRoomService(repos.Rooms, repos.Policies))
+	r := newTestRouter()
+	r.POST("/rooms", rc.Create)
+	r.GET("/rooms/:id", rc.GetByID)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		status int
+		code   string
+		fields []string
+	}{
+		// price_per_night é um Money: o valor ausente é zero e só cai na validação do quarto
+		{"missing fields", http.MethodPost, "/rooms", `{"number":101}`, http.StatusBadRequest, "validation_failed",
+			[]string{"type", "capacity", "status"}},
+		{"wrong type", http.MethodPost, "/rooms", `{"number":"101"}`, http.StatusBadRequest, "validation_failed", []string{"number"}},
+		{"rule violations", http.MethodPost, "/rooms", `{"number":101,"type":"PENTHOUSE","capacity":-1,"price_per_night":10,"status":"ATIVO"}`,
+			http.StatusBadRequest, "validation_failed", []string{"capacity", "type"}},
+		{"too many decimals", http.MethodPost, "/rooms", `{"number":101,"type":"STANDARD","capacity":2,"price_per_night":10.005,"status":"ATIVO"}`,
+			http.StatusBadRequest, "malformed_body", nil},
+		{"malformed body", http.MethodPost, "/rooms", `{"number":`, http.StatusBadRequest, "malformed_body", nil},
+		{"unknown room", http.MethodGet, "/rooms/missing", "", http.StatusNotFound, "room_not_found", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := performRequest(r, tt.method, tt.path, tt.body)
+			if w.Code != tt.status || w.Header().Get("Content-Type") != model.ProblemContentType {
+				t.Fatalf("expected %d %s, got %d %s: %s", tt.status, model.ProblemContentType, w.Code, w.Header().Get("Content-Type"), w.Body)
+			}
+			var problem model.Problem
+			decodeBody(t, w, &problem)
+			if problem.Code != tt.code || problem.Type != problemTypePrefix+tt.code || problem.Status != tt.status || problem.Instance != tt.path {
+				t.Fatalf("unexpected problem %+v", problem)
+			}
+			fields := map[string]bool{}
+			for _, fe := range problem.Errors {
+				fields[fe.Field] = true
+			}
+			if len(fields) != len(tt.fields) {
+				t.Fatalf("expected errors for %v, got %+v", tt.fields, problem.Errors)
+			}
+			for _, field := range tt.fields {
+				if !fields[field] {
+					t.Fatalf("expected an error for %s, got %+v", field, problem.Errors)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package controller
+
+import (
+	"net/http"
+
+	"hotel-soa/model"
+	"hotel-soa/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FolioController gerencia a conta (folio) das reservas
+type FolioController struct {
+	service service.FolioService
+}
+
+// NewFolioController cria um novo FolioController
+func NewFolioController(s service.FolioService) *FolioController {
+	return &FolioController{service: s}
+}
+
+// @Summary Busca a conta de uma reserva
+// @Description Lista as noites da reserva (com posted_at quando o night audit já as lançou), os consumos, taxas e descontos, os pagamentos e estornos, e o saldo. total_charges é o total_amount da reserva
+// @Tags folio
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path string true "ID da Reserva (UUID)"
+// @Success 200 {object} model.Folio
+// @Failure 400 {object} model.Problem
+// @Failure 401 {object} model.Problem
+// @Failure 403 {object} model.Problem
+// @Failure 404 {object} model.Problem
+// @Failure 500 {object} model.Problem
+// @Router /reservations/{id}/folio [get]
+func (fc *FolioController) Get(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		writeProblem(c, errInvalidID)
+		return
+	}
+
+	folio, err := fc.service.Get(id)
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, folio)
+}
+
+// @Summary Lança um consumo, taxa ou desconto na conta
+// @Description Lança MINIBAR, RESTAURANT, TAX, DISCOUNT ou OTHER numa reserva CREATED, CONFIRMED ou CHECKED_IN e recalcula o total_amount. amount é sempre positivo; descontos entram na conta com valor negativo. Exige folio:write
+// @Tags folio
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path string true "ID da Reserva (UUID)"
+// @Param charge body model.FolioChargeRequest true "Lançamento"
+// @Success 201 {object} model.FolioCharge
+// @Failure 400 {object} model.Problem
+// @Failure 401 {object} model.Problem
+// @Failure 403 {object} model.Problem
+// @Failure 404 {object} model.Problem
+// @Failure 409 {object} model.Problem
+// @Failure 500 {object} model.Problem
+// @Router /reservations/{id}/folio/charges [post]
+func (fc *FolioController) PostCharge(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		writeProblem(c, errInvalidID)
+		return
+	}
+
+	var req model.FolioChargeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeBindError(c, err)
+		return
+	}
+
+	charge, err := fc.service.PostCharge(id, req, currentActor(c))
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, charge)
+}
+
+// @Summary Registra um pagamento ou estorno na conta
+// @Description Registra um PAYMENT (padrão) ou REFUND em CASH, CARD, PIX ou TRANSFER feito fora do sistema. Um REFUND CARD com authorization_id é feito pelo gateway, contra a captura do depósito, e não pode passar do valor capturado. O estorno não pode passar do valor já pago. Exige folio:write
+// @Tags folio
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path string true "ID da Reserva (UUID)"
+// @Param payment body model.PaymentRequest true "Pagamento"
+// @Success 201 {object} model.Payment
+// @Failure 400 {object} model.Problem
+// @Failure 401 {object} model.Problem
+// @Failure 403 {object} model.Problem
+// @Failure 404 {object} model.Problem
+// @Failure 402 {object} model.Problem
+// @Failure 409 {object} model.Problem
+// @Failure 500 {object} model.Problem
+// @Failure 504 {object} model.Problem
+// @Router /reservations/{id}/folio/payments [post]
+func (fc *FolioController) PostPayment(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		writeProblem(c, errInvalidID)
+		return
+	}
+
+	var req model.PaymentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeBindError(c, err)
+		return
+	}
+
+	payment, err := fc.service.PostPayment(id, req, currentActor(c))
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, payment)
+}
@@ -0,0 +1,70 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"hotel-soa/dao"
+	"hotel-soa/gateway"
+	"hotel-soa/model"
+	"hotel-soa/service"
+)
+
+func TestFolioEndpoints(t *testing.T) {
+	repos := dao.NewMemoryRepositories()
+	roomID, err := repos.Rooms.InsertRoom(model.Room{Number: 101, Type: "STANDARD", Capacity: 2, PricePerNight: model.NewMoney(10000), Status: "ATIVO"}, testActor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rc := NewReservationController(service.NewReservationService(repos.Reservations, repos.Rooms, repos.RatePlans, repos.Guests, repos.Policies, repos.Taxes, repos.Folios, repos.Holds, gateway.NewStubGateway(), 30))
+	fc := NewFolioController(service.NewFolioService(repos.Folios, repos.Reservations, gateway.NewStubGateway()))
+	r := newTestRouter()
+	r.POST("/reservation", rc.Create)
+	r.GET("/reservation/:id/folio", fc.Get)
+	r.POST("/reservation/:id/folio/charges", fc.PostCharge)
+	r.POST("/reservation/:id/folio/payments", fc.PostPayment)
+
+	checkin := time.Now().AddDate(0, 1, 0)
+	w := performRequest(r, http.MethodPost, "/reservation", fmt.Sprintf(`{"room_id":%q,"guest_name":"Ana","checkin_expected":%q,"checkout_expected":%q,"status":"CREATED"}`,
+		roomID, checkin.Format("2006-01-02"), checkin.AddDate(0, 0, 2).Format("2006-01-02")))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body)
+	}
+	var res model.Reservation
+	decodeBody(t, w, &res)
+	folioPath := "/reservation/" + res.ID + "/folio"
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		status int
+	}{
+		{"minibar", http.MethodPost, folioPath + "/charges", `{"type":"MINIBAR","description":"2x água","amount":12.5}`, http.StatusCreated},
+		{"discount", http.MethodPost, folioPath + "/charges", `{"type":"DISCOUNT","amount":2.5}`, http.StatusCreated},
+		{"room charge", http.MethodPost, folioPath + "/charges", `{"type":"ROOM","amount":100}`, http.StatusBadRequest},
+		{"zero amount", http.MethodPost, folioPath + "/charges", `{"type":"MINIBAR","amount":0}`, http.StatusBadRequest},
+		{"discount above the total", http.MethodPost, folioPath + "/charges", `{"type":"DISCOUNT","amount":1000}`, http.StatusConflict},
+		{"payment", http.MethodPost, folioPath + "/payments", `{"method":"PIX","amount":200,"reference":"E123"}`, http.StatusCreated},
+		{"unknown method", http.MethodPost, folioPath + "/payments", `{"method":"CHEQUE","amount":10}`, http.StatusBadRequest},
+		{"refund above the paid", http.MethodPost, folioPath + "/payments", `{"type":"REFUND","method":"PIX","amount":500}`, http.StatusConflict},
+		{"unknown reservation", http.MethodGet, "/reservation/00000000-0000-0000-0000-000000000000/folio", "", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if w := performRequest(r, tt.method, tt.path, tt.body); w.Code != tt.status {
+				t.Fatalf("expected %d, got %d: %s", tt.status, w.Code, w.Body)
+			}
+		})
+	}
+
+	w = performRequest(r, http.MethodGet, folioPath, "")
+	var folio model.Folio
+	decodeBody(t, w, &folio)
+	if w.Code != http.StatusOK || len(folio.Charges) != 4 || !folio.TotalCharges.Equal(model.NewMoney(21000)) || !folio.TotalPaid.Equal(model.NewMoney(20000)) || !folio.Balance.Equal(model.NewMoney(1000)) {
+		t.Fatalf("expected 210 charged, 200 paid and 10 due, got %d: %s", w.Code, w.Body)
+	}
+}
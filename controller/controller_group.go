@@ -0,0 +1,369 @@
+package controller
+
+import (
+	"net/http"
+
+	"hotel-soa/model"
+	"hotel-soa/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GroupController gerencia os grupos, os blocos de quartos, a rooming list e a conta master
+type GroupController struct {
+	service service.GroupService
+}
+
+// NewGroupController cria um novo GroupController
+func NewGroupController(s service.GroupService) *GroupController {
+	return &GroupController{service: s}
+}
+
+// @Summary Cria um grupo com bloco de quartos
+// @Description Segura rooms quartos de cada tipo em todas as noites entre checkin_expected e checkout_expected. Os quartos do bloco saem na hora do estoque do tipo; o bloco que não cabe em alguma noite é recusado com 409 room_type_sold_out. cutoff_date não pode ser depois da chegada: passado o cutoff, os quartos não usados voltam ao estoque (release)
+// @Tags groups
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param group body model.GroupRequest true "Grupo"
+// @Success 201 {object} model.Group
+// @Failure 400 {object} model.Problem
+// @Failure 401 {object} model.Problem
+// @Failure 403 {object} model.Problem
+// @Failure 409 {object} model.Problem
+// @Failure 500 {object} model.Problem
+// @Router /groups [post]
+func (gc *GroupController) Create(c *gin.Context) {
+	var req model.GroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeBindError(c, err)
+		return
+	}
+
+	group, err := gc.service.Create(*req.Group(), currentActor(c))
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, group)
+}
+
+// @Summary Lista os grupos
+// @Description Lista os grupos por data de chegada, com os blocos e quantos quartos de cada tipo já foram usados (picked_up)
+// @Tags groups
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Success 200 {array} model.Group
+// @Failure 401 {object} model.Problem
+// @Failure 403 {object} model.Problem
+// @Failure 500 {object} model.Problem
+// @Router /groups [get]
+func (gc *GroupController) GetAll(c *gin.Context) {
+	groups, err := gc.service.GetAll()
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, groups)
+}
+
+// @Summary Busca um grupo
+// @Tags groups
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path string true "ID do Grupo (UUID)"
+// @Success 200 {object} model.Group
+// @Failure 400 {object} model.Problem
+// @Failure 401 {object} model.Problem
+// @Failure 403 {object} model.Problem
+// @Failure 404 {object} model.Problem
+// @Failure 500 {object} model.Problem
+// @Router /groups/{id} [get]
+func (gc *GroupController) GetByID(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		writeProblem(c, errInvalidID)
+		return
+	}
+
+	group, err := gc.service.GetByID(id)
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, group)
+}
+
+// @Summary Lista as reservas do grupo
+// @Description Lista as reservas do grupo, de qualquer status, por data de chegada
+// @Tags groups
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path string true "ID do Grupo (UUID)"
+// @Success 200 {array} model.Reservation
+// @Failure 400 {object} model.Problem
+// @Failure 401 {object} model.Problem
+// @Failure 403 {object} model.Problem
+// @Failure 404 {object} model.Problem
+// @Failure 500 {object} model.Problem
+// @Router /groups/{id}/reservations [get]
+func (gc *GroupController) GetReservations(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		writeProblem(c, errInvalidID)
+		return
+	}
+
+	reservations, err := gc.service.Reservations(id)
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, reservations)
+}
+
+// @Summary Cria uma reserva do grupo
+// @Description Cria uma reserva do grupo pelo tipo (pick-up). room_type pode ser omitido quando o bloco tem um só tipo e as datas, quando são as do bloco. Enquanto o grupo está ACTIVE e o bloco do tipo tem quarto livre em todas as noites, a reserva usa um quarto do bloco; depois do release, ou além do bloco, passa pelo estoque do tipo (409 room_type_sold_out). As noites e os impostos da reserva são cobrados na conta master do grupo. Grupo cancelado: 409 group_canceled
+// @Tags groups
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path string true "ID do Grupo (UUID)"
+// @Param reservation body model.GroupPickupRequest true "Hóspede"
+// @Success 201 {object} model.Reservation
+// @Failure 400 {object} model.Problem
+// @Failure 401 {object} model.Problem
+// @Failure 403 {object} model.Problem
+// @Failure 404 {object} model.Problem
+// @Failure 409 {object} model.Problem
+// @Failure 500 {object} model.Problem
+// @Router /groups/{id}/reservations [post]
+func (gc *GroupController) PickUp(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		writeProblem(c, errInvalidID)
+		return
+	}
+
+	var req model.GroupPickupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeBindError(c, err)
+		return
+	}
+
+	res, err := gc.service.PickUp(id, req, currentActor(c))
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, res)
+}
+
+// @Summary Importa a rooming list do grupo
+// @Description Cria uma reserva do grupo para cada linha de guests, na ordem, como POST /groups/{id}/reservations. Uma linha recusada não impede as demais: ela volta em errors com a posição, o code e o detail que a criação individual devolveria
+// @Tags groups
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path string true "ID do Grupo (UUID)"
+// @Param rooming_list body model.RoomingListRequest true "Rooming list"
+// @Success 200 {object} model.RoomingListResult
+// @Failure 400 {object} model.Problem
+// @Failure 401 {object} model.Problem
+// @Failure 403 {object} model.Problem
+// @Failure 404 {object} model.Problem
+// @Failure 409 {object} model.Problem
+// @Failure 500 {object} model.Problem
+// @Router /groups/{id}/rooming-list [post]
+func (gc *GroupController) RoomingList(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		writeProblem(c, errInvalidID)
+		return
+	}
+
+	var req model.RoomingListRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeBindError(c, err)
+		return
+	}
+	if err := req.Validate(); err != nil {
+		writeProblem(c, err)
+		return
+	}
+	if _, err := gc.service.GetByID(id); err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	result := model.RoomingListResult{Reservations: []model.Reservation{}, Errors: []model.RoomingListError{}}
+	for i, guest := range req.Guests {
+		res, err := gc.service.PickUp(id, guest, currentActor(c))
+		if err != nil {
+			// uma falha do servidor interrompe a lista; as recusas ficam na linha
+			problem := problemFor(err)
+			if problem.Status >= http.StatusInternalServerError {
+				writeProblem(c, err)
+				return
+			}
+			result.Errors = append(result.Errors, model.RoomingListError{Index: i, Code: problem.Code, Detail: problem.Detail, Errors: problem.Errors})
+			continue
+		}
+		result.Reservations = append(result.Reservations, res)
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// @Summary Libera os quartos não usados do bloco
+// @Description Passa o grupo ACTIVE para RELEASED: os quartos do bloco ainda não usados voltam ao estoque do tipo. As reservas do grupo continuam, e as novas passam pelo estoque do tipo. O release é feito sozinho quando o cutoff passa
+// @Tags groups
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path string true "ID do Grupo (UUID)"
+// @Success 200 {object} model.Group
+// @Failure 400 {object} model.Problem
+// @Failure 401 {object} model.Problem
+// @Failure 403 {object} model.Problem
+// @Failure 404 {object} model.Problem
+// @Failure 409 {object} model.Problem
+// @Failure 500 {object} model.Problem
+// @Router /groups/{id}/release [post]
+func (gc *GroupController) Release(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		writeProblem(c, errInvalidID)
+		return
+	}
+
+	group, err := gc.service.Release(id, currentActor(c))
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, group)
+}
+
+// @Summary Cancela um grupo
+// @Description Passa o grupo para CANCELED e cancela as reservas do grupo CREATED ou CONFIRMED, cada uma com a multa e o reembolso da sua política de cancelamento. As que já fizeram check-in ou check-out ficam como estão (kept). Repetido, só termina de cancelar as reservas que tenham ficado para trás
+// @Tags groups
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path string true "ID do Grupo (UUID)"
+// @Param cancel body model.CancelRequest false "Motivo do cancelamento"
+// @Success 200 {object} model.GroupCancellation
+// @Failure 400 {object} model.Problem
+// @Failure 401 {object} model.Problem
+// @Failure 403 {object} model.Problem
+// @Failure 404 {object} model.Problem
+// @Failure 409 {object} model.Problem
+// @Failure 500 {object} model.Problem
+// @Router /groups/{id}/cancel [post]
+func (gc *GroupController) Cancel(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		writeProblem(c, errInvalidID)
+		return
+	}
+
+	// o corpo é opcional: sem ele o grupo é cancelado sem motivo
+	var req model.CancelRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			writeBindError(c, err)
+			return
+		}
+	}
+
+	result, err := gc.service.Cancel(id, req.Reason, currentActor(c))
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// @Summary Consulta a conta master do grupo
+// @Description Traz as noites (ROOM) e os impostos cobrados à parte (TAX) de todas as reservas ativas do grupo, com o reservation_id de cada linha, e os pagamentos feitos ao grupo. Os demais lançamentos, como frigobar, ficam na conta de cada reserva
+// @Tags groups
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path string true "ID do Grupo (UUID)"
+// @Success 200 {object} model.GroupFolio
+// @Failure 400 {object} model.Problem
+// @Failure 401 {object} model.Problem
+// @Failure 403 {object} model.Problem
+// @Failure 404 {object} model.Problem
+// @Failure 500 {object} model.Problem
+// @Router /groups/{id}/folio [get]
+func (gc *GroupController) Folio(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		writeProblem(c, errInvalidID)
+		return
+	}
+
+	folio, err := gc.service.Folio(id)
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, folio)
+}
+
+// @Summary Registra um pagamento ou estorno na conta master
+// @Description Registra um PAYMENT (padrão) ou REFUND em CASH, CARD, PIX ou TRANSFER na conta master do grupo. Pagamentos pelo gateway (authorization_id) não são aceitos. O estorno não pode passar do valor já pago. Exige folio:write
+// @Tags groups
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path string true "ID do Grupo (UUID)"
+// @Param payment body model.PaymentRequest true "Pagamento"
+// @Success 201 {object} model.Payment
+// @Failure 400 {object} model.Problem
+// @Failure 401 {object} model.Problem
+// @Failure 403 {object} model.Problem
+// @Failure 404 {object} model.Problem
+// @Failure 409 {object} model.Problem
+// @Failure 500 {object} model.Problem
+// @Router /groups/{id}/folio/payments [post]
+func (gc *GroupController) PostPayment(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		writeProblem(c, errInvalidID)
+		return
+	}
+
+	var req model.PaymentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeBindError(c, err)
+		return
+	}
+
+	payment, err := gc.service.PostPayment(id, req, currentActor(c))
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, payment)
+}
@@ -0,0 +1,94 @@
+package controller
+
+import (
+	"net/http"
+	"testing"
+
+	"hotel-soa/dao"
+	"hotel-soa/gateway"
+	"hotel-soa/model"
+	"hotel-soa/service"
+)
+
+func TestGroupEndpoints(t *testing.T) {
+	repos := dao.NewMemoryRepositories()
+	for _, number := range []int{201, 202} {
+		if _, err := repos.Rooms.InsertRoom(model.Room{Number: number, Type: "DELUXE", Capacity: 2, PricePerNight: model.NewMoney(25000), Status: "ATIVO"}, testActor); err != nil {
+			t.Fatal(err)
+		}
+	}
+	reservations := service.NewReservationService(repos.Reservations, repos.Rooms, repos.RatePlans, repos.Guests, repos.Policies, repos.Taxes, repos.Folios, repos.Holds, gateway.NewStubGateway(), 30)
+	gc := NewGroupController(service.NewGroupService(repos.Groups, reservations))
+	r := newTestRouter()
+	r.POST("/groups", gc.Create)
+	r.GET("/groups", gc.GetAll)
+	r.GET("/groups/:id", gc.GetByID)
+	r.GET("/groups/:id/reservations", gc.GetReservations)
+	r.POST("/groups/:id/reservations", gc.PickUp)
+	r.POST("/groups/:id/rooming-list", gc.RoomingList)
+	r.POST("/groups/:id/release", gc.Release)
+	r.POST("/groups/:id/cancel", gc.Cancel)
+	r.GET("/groups/:id/folio", gc.Folio)
+	r.POST("/groups/:id/folio/payments", gc.PostPayment)
+
+	body := `{"name":"Congresso","contact_name":"Ana","checkin_expected":"2030-06-10","checkout_expected":"2030-06-12","cutoff_date":"2030-06-01","blocks":[{"room_type":"DELUXE","rooms":1}]}`
+	w := performRequest(r, http.MethodPost, "/groups", body)
+	var group model.Group
+	decodeBody(t, w, &group)
+	if w.Code != http.StatusCreated || group.Status != model.GroupActive || len(group.Blocks) != 1 {
+		t.Fatalf("expected an ACTIVE group, got %d: %s", w.Code, w.Body)
+	}
+
+	// o primeiro hóspede usa o quarto do bloco, o segundo o último DELUXE e o terceiro não cabe
+	w = performRequest(r, http.MethodPost, "/groups/"+group.ID+"/rooming-list", `{"guests":[{"guest_name":"João"},{"guest_name":"Maria"},{"guest_name":"Bia"},{"guest_name":"Leo","room_type":"SUITE"}]}`)
+	var result model.RoomingListResult
+	decodeBody(t, w, &result)
+	if w.Code != http.StatusOK || len(result.Reservations) != 2 || len(result.Errors) != 2 {
+		t.Fatalf("expected two reservations and two refused lines, got %d: %s", w.Code, w.Body)
+	}
+	if result.Errors[0].Index != 2 || result.Errors[0].Code != "room_type_sold_out" || result.Errors[1].Index != 3 || result.Errors[1].Code != "validation_failed" {
+		t.Fatalf("unexpected refused lines %+v", result.Errors)
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		status int
+	}{
+		{"invalid group", http.MethodPost, "/groups", `{"name":"X","contact_name":"Ana","checkin_expected":"2030-06-10","checkout_expected":"2030-06-12","cutoff_date":"2030-06-11","blocks":[{"room_type":"DELUXE","rooms":1}]}`, http.StatusBadRequest},
+		{"block over the stock", http.MethodPost, "/groups", body, http.StatusConflict},
+		{"list", http.MethodGet, "/groups", "", http.StatusOK},
+		{"get", http.MethodGet, "/groups/" + group.ID, "", http.StatusOK},
+		{"get unknown", http.MethodGet, "/groups/00000000-0000-0000-0000-000000000000", "", http.StatusNotFound},
+		{"reservations", http.MethodGet, "/groups/" + group.ID + "/reservations", "", http.StatusOK},
+		{"empty rooming list", http.MethodPost, "/groups/" + group.ID + "/rooming-list", `{"guests":[]}`, http.StatusBadRequest},
+		{"rooming list of an unknown group", http.MethodPost, "/groups/00000000-0000-0000-0000-000000000000/rooming-list", `{"guests":[{"guest_name":"Ana"}]}`, http.StatusNotFound},
+		{"folio", http.MethodGet, "/groups/" + group.ID + "/folio", "", http.StatusOK},
+		{"payment", http.MethodPost, "/groups/" + group.ID + "/folio/payments", `{"method":"PIX","amount":100}`, http.StatusCreated},
+		{"refund over paid", http.MethodPost, "/groups/" + group.ID + "/folio/payments", `{"type":"REFUND","method":"PIX","amount":200}`, http.StatusConflict},
+		{"release", http.MethodPost, "/groups/" + group.ID + "/release", "", http.StatusOK},
+		{"release twice", http.MethodPost, "/groups/" + group.ID + "/release", "", http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if w := performRequest(r, tt.method, tt.path, tt.body); w.Code != tt.status {
+				t.Fatalf("expected %d, got %d: %s", tt.status, w.Code, w.Body)
+			}
+		})
+	}
+
+	w = performRequest(r, http.MethodPost, "/groups/"+group.ID+"/cancel", "")
+	var cancellation model.GroupCancellation
+	decodeBody(t, w, &cancellation)
+	if w.Code != http.StatusOK || cancellation.Group.Status != model.GroupCanceled || len(cancellation.Canceled) != 2 {
+		t.Fatalf("expected both reservations canceled, got %d: %s", w.Code, w.Body)
+	}
+	w = performRequest(r, http.MethodPost, "/groups/"+group.ID+"/reservations", `{"guest_name":"Ana"}`)
+	var problem model.Problem
+	decodeBody(t, w, &problem)
+	if w.Code != http.StatusConflict || problem.Code != "group_canceled" {
+		t.Fatalf("expected 409 group_canceled, got %d: %s", w.Code, w.Body)
+	}
+}
@@ -0,0 +1,216 @@
+package controller
+
+import (
+	"net/http"
+
+	"hotel-soa/model"
+	"hotel-soa/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GuestController gerencia endpoints de hóspedes
+type GuestController struct {
+	service service.GuestService
+}
+
+// NewGuestController cria um novo GuestController
+func NewGuestController(s service.GuestService) *GuestController {
+	return &GuestController{service: s}
+}
+
+// @Summary Cria um novo hóspede
+// @Description Cadastra um hóspede com dados de contato e preferências
+// @Tags guests
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param guest body model.GuestRequest true "Hóspede"
+// @Success 201 {object} model.Guest
+// @Failure 400 {object} model.Problem
+// @Failure 401 {object} model.Problem
+// @Failure 403 {object} model.Problem
+// @Failure 409 {object} model.Problem
+// @Failure 500 {object} model.Problem
+// @Router /guests [post]
+func (gc *GuestController) Create(c *gin.Context) {
+	var req model.GuestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeBindError(c, err)
+		return
+	}
+
+	guest := req.Guest()
+	if err := guest.Validate(); err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	id, err := gc.service.Create(*guest)
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	guest.ID = id
+	c.JSON(http.StatusCreated, guest)
+}
+
+// @Summary Atualiza um hóspede existente
+// @Description Atualiza os dados de um hóspede pelo ID
+// @Tags guests
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path string true "ID do Hóspede (UUID)"
+// @Param guest body model.GuestRequest true "Hóspede atualizado"
+// @Success 200 {object} model.Guest
+// @Failure 400 {object} model.Problem
+// @Failure 401 {object} model.Problem
+// @Failure 403 {object} model.Problem
+// @Failure 404 {object} model.Problem
+// @Failure 409 {object} model.Problem
+// @Failure 500 {object} model.Problem
+// @Router /guests/{id} [put]
+func (gc *GuestController) Update(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		writeProblem(c, errInvalidID)
+		return
+	}
+
+	var req model.GuestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeBindError(c, err)
+		return
+	}
+
+	req.ID = id
+	guest := req.Guest()
+	if err := guest.Validate(); err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	if err := gc.service.Update(*guest); err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, guest)
+}
+
+// @Summary Deleta um hóspede
+// @Description Deleta um hóspede pelo ID
+// @Tags guests
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path string true "ID do Hóspede (UUID)"
+// @Success 204
+// @Failure 400 {object} model.Problem
+// @Failure 401 {object} model.Problem
+// @Failure 403 {object} model.Problem
+// @Failure 500 {object} model.Problem
+// @Router /guests/{id} [delete]
+func (gc *GuestController) Delete(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		writeProblem(c, errInvalidID)
+		return
+	}
+
+	if err := gc.service.Delete(id); err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Busca hóspede pelo ID
+// @Description Retorna um hóspede pelo seu ID
+// @Tags guests
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path string true "ID do Hóspede (UUID)"
+// @Success 200 {object} model.Guest
+// @Failure 400 {object} model.Problem
+// @Failure 401 {object} model.Problem
+// @Failure 403 {object} model.Problem
+// @Failure 404 {object} model.Problem
+// @Router /guests/{id} [get]
+func (gc *GuestController) GetByID(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		writeProblem(c, errInvalidID)
+		return
+	}
+
+	guest, err := gc.service.GetByID(id)
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, guest)
+}
+
+// @Summary Lista todos os hóspedes
+// @Description Retorna todos os hóspedes cadastrados
+// @Tags guests
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Success 200 {array} model.Guest
+// @Success 204 "No Content"
+// @Failure 401 {object} model.Problem
+// @Failure 403 {object} model.Problem
+// @Failure 500 {object} model.Problem
+// @Router /guests [get]
+func (gc *GuestController) GetAll(c *gin.Context) {
+	guests, err := gc.service.GetAll()
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	if len(guests) == 0 {
+		c.Status(http.StatusNoContent)
+		return
+	}
+
+	c.JSON(http.StatusOK, guests)
+}
+
+// @Summary Histórico de estadias do hóspede
+// @Description Retorna as reservas do hóspede, da mais recente para a mais antiga
+// @Tags guests
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path string true "ID do Hóspede (UUID)"
+// @Success 200 {array} model.Reservation
+// @Failure 400 {object} model.Problem
+// @Failure 401 {object} model.Problem
+// @Failure 403 {object} model.Problem
+// @Failure 404 {object} model.Problem
+// @Failure 500 {object} model.Problem
+// @Router /guests/{id}/reservations [get]
+func (gc *GuestController) GetReservations(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		writeProblem(c, errInvalidID)
+		return
+	}
+
+	reservations, err := gc.service.GetReservations(id)
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+	if reservations == nil {
+		reservations = []model.Reservation{}
+	}
+
+	c.JSON(http.StatusOK, reservations)
+}
@@ -0,0 +1,71 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"hotel-soa/dao"
+	"hotel-soa/gateway"
+	"hotel-soa/model"
+	"hotel-soa/service"
+)
+
+func TestGuestEndpoints(t *testing.T) {
+	repos := dao.NewMemoryRepositories()
+	roomID, err := repos.Rooms.InsertRoom(model.Room{Number: 101, Type: "STANDARD", Capacity: 2, PricePerNight: model.NewMoney(10000), Status: "ATIVO"}, testActor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gc := NewGuestController(service.NewGuestService(repos.Guests, repos.Reservations))
+	rc := NewReservationController(service.NewReservationService(repos.Reservations, repos.Rooms, repos.RatePlans, repos.Guests, repos.Policies, repos.Taxes, repos.Folios, repos.Holds, gateway.NewStubGateway(), 30))
+	r := newTestRouter()
+	r.POST("/guests", gc.Create)
+	r.PUT("/guests/:id", gc.Update)
+	r.GET("/guests/:id/reservations", gc.GetReservations)
+	r.POST("/reservations", rc.Create)
+
+	w := performRequest(r, http.MethodPost, "/guests", `{"name":" Ana Souza ","document_number":"AB123","email":"ana@example.com"}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body)
+	}
+	var ana model.Guest
+	decodeBody(t, w, &ana)
+	if ana.ID == "" || ana.Name != "Ana Souza" {
+		t.Fatalf("unexpected guest %+v", ana)
+	}
+
+	for _, dates := range [][2]string{{"2026-03-01", "2026-03-03"}, {"2026-06-01", "2026-06-03"}} {
+		body := fmt.Sprintf(`{"room_id":%q,"guest_id":%q,"checkin_expected":%q,"checkout_expected":%q,"status":"CREATED"}`, roomID, ana.ID, dates[0], dates[1])
+		if w := performRequest(r, http.MethodPost, "/reservations", body); w.Code != http.StatusCreated {
+			t.Fatalf("expected 201, got %d: %s", w.Code, w.Body)
+		}
+	}
+	w = performRequest(r, http.MethodGet, "/guests/"+ana.ID+"/reservations", "")
+	var history []model.Reservation
+	decodeBody(t, w, &history)
+	if w.Code != http.StatusOK || len(history) != 2 || history[0].CheckinExpected != "2026-06-01" || history[0].GuestName != "Ana Souza" {
+		t.Fatalf("unexpected history %d %+v", w.Code, history)
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		status int
+	}{
+		{"duplicate document", http.MethodPost, "/guests", `{"name":"Other Ana","document_number":"AB123"}`, http.StatusConflict},
+		{"invalid email", http.MethodPost, "/guests", `{"name":"Ana","email":"ana"}`, http.StatusBadRequest},
+		{"missing name", http.MethodPost, "/guests", `{"email":"ana@example.com"}`, http.StatusBadRequest},
+		{"unknown guest", http.MethodPut, "/guests/missing", `{"name":"Nobody"}`, http.StatusNotFound},
+		{"history of an unknown guest", http.MethodGet, "/guests/missing/reservations", "", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if w := performRequest(r, tt.method, tt.path, tt.body); w.Code != tt.status {
+				t.Fatalf("expected %d, got %d: %s", tt.status, w.Code, w.Body)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package controller
+
+import (
+	"net/http"
+
+	"hotel-soa/model"
+	"hotel-soa/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HoldController gerencia endpoints de bloqueios temporários de quarto
+type HoldController struct {
+	service service.HoldService
+}
+
+// NewHoldController cria um novo HoldController
+func NewHoldController(s service.HoldService) *HoldController {
+	return &HoldController{service: s}
+}
+
+// @Summary Bloqueia um quarto por um tempo
+// @Description Segura o quarto no período enquanto o hóspede paga, por ttl_seconds (60 a 3600) ou pelo HOLD_TTL do servidor. Enquanto vale, o bloqueio conta como reserva nas checagens de conflito e some da disponibilidade; vencido, deixa de valer sozinho. O token é devolvido só aqui e vira a reserva em POST /reservation com hold_token. Quarto INATIVO: 409 room_inactive. Exige reservations:create
+// @Tags holds
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param hold body model.HoldRequest true "Bloqueio"
+// @Success 201 {object} model.RoomHoldCreated
+// @Failure 400 {object} model.Problem
+// @Failure 401 {object} model.Problem
+// @Failure 403 {object} model.Problem
+// @Failure 409 {object} model.Problem
+// @Failure 500 {object} model.Problem
+// @Router /holds [post]
+func (hc *HoldController) Create(c *gin.Context) {
+	var req model.HoldRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeBindError(c, err)
+		return
+	}
+
+	hold, err := hc.service.Create(req, currentActor(c))
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, hold)
+}
+
+// @Summary Busca bloqueio pelo ID
+// @Description Retorna um bloqueio ainda válido, sem o token
+// @Tags holds
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path string true "ID do Bloqueio (UUID)"
+// @Success 200 {object} model.RoomHold
+// @Failure 400 {object} model.Problem
+// @Failure 401 {object} model.Problem
+// @Failure 403 {object} model.Problem
+// @Failure 404 {object} model.Problem
+// @Failure 500 {object} model.Problem
+// @Router /holds/{id} [get]
+func (hc *HoldController) GetByID(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		writeProblem(c, errInvalidID)
+		return
+	}
+
+	hold, err := hc.service.GetByID(id)
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, hold)
+}
+
+// @Summary Libera um bloqueio
+// @Description Libera o quarto antes do vencimento do bloqueio, quando o hóspede desiste do pagamento
+// @Tags holds
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path string true "ID do Bloqueio (UUID)"
+// @Success 204
+// @Failure 400 {object} model.Problem
+// @Failure 401 {object} model.Problem
+// @Failure 403 {object} model.Problem
+// @Failure 404 {object} model.Problem
+// @Failure 500 {object} model.Problem
+// @Router /holds/{id} [delete]
+func (hc *HoldController) Delete(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		writeProblem(c, errInvalidID)
+		return
+	}
+
+	if err := hc.service.Release(id); err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,71 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"hotel-soa/dao"
+	"hotel-soa/gateway"
+	"hotel-soa/model"
+	"hotel-soa/service"
+)
+
+func TestHoldEndpoints(t *testing.T) {
+	repos := dao.NewMemoryRepositories()
+	roomID, err := repos.Rooms.InsertRoom(model.Room{Number: 101, Type: "STANDARD", Capacity: 2, PricePerNight: model.NewMoney(10000), Status: "ATIVO"}, testActor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hc := NewHoldController(service.NewHoldService(repos.Holds, repos.Rooms, repos.RatePlans, 10*time.Minute))
+	rc := NewReservationController(service.NewReservationService(repos.Reservations, repos.Rooms, repos.RatePlans, repos.Guests, repos.Policies, repos.Taxes, repos.Folios, repos.Holds, gateway.NewStubGateway(), 30))
+	r := newTestRouter()
+	r.POST("/holds", hc.Create)
+	r.GET("/holds/:id", hc.GetByID)
+	r.DELETE("/holds/:id", hc.Delete)
+	r.POST("/reservations", rc.Create)
+
+	checkin := time.Now().AddDate(0, 1, 0)
+	dates := fmt.Sprintf(`"checkin_expected":%q,"checkout_expected":%q`, checkin.Format("2006-01-02"), checkin.AddDate(0, 0, 2).Format("2006-01-02"))
+	holdBody := fmt.Sprintf(`{"room_id":%q,%s}`, roomID, dates)
+	w := performRequest(r, http.MethodPost, "/holds", holdBody)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body)
+	}
+	var hold model.RoomHoldCreated
+	decodeBody(t, w, &hold)
+	if hold.ID == "" || hold.Token == "" {
+		t.Fatalf("expected an id and a token, got %+v", hold)
+	}
+
+	w = performRequest(r, http.MethodPost, "/holds", holdBody)
+	var problem model.Problem
+	decodeBody(t, w, &problem)
+	if w.Code != http.StatusConflict || problem.Code != "room_on_hold" || problem.ConflictingHoldID != hold.ID || problem.HoldExpiresAt == nil {
+		t.Fatalf("expected 409 room_on_hold, got %d: %s", w.Code, w.Body)
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		status int
+	}{
+		{"ttl out of range", http.MethodPost, "/holds", fmt.Sprintf(`{"room_id":%q,%s,"ttl_seconds":10}`, roomID, dates), http.StatusBadRequest},
+		{"get", http.MethodGet, "/holds/" + hold.ID, "", http.StatusOK},
+		{"reservation without the token", http.MethodPost, "/reservations", fmt.Sprintf(`{"room_id":%q,"guest_name":"Ana","status":"CREATED",%s}`, roomID, dates), http.StatusConflict},
+		{"unknown token", http.MethodPost, "/reservations", fmt.Sprintf(`{"room_id":%q,"guest_name":"Ana","status":"CREATED",%s,"hold_token":"hold_x"}`, roomID, dates), http.StatusConflict},
+		{"conversion", http.MethodPost, "/reservations", fmt.Sprintf(`{"room_id":%q,"guest_name":"Ana","status":"CREATED",%s,"hold_token":%q}`, roomID, dates, hold.Token), http.StatusCreated},
+		{"consumed", http.MethodGet, "/holds/" + hold.ID, "", http.StatusNotFound},
+		{"release unknown", http.MethodDelete, "/holds/" + hold.ID, "", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if w := performRequest(r, tt.method, tt.path, tt.body); w.Code != tt.status {
+				t.Fatalf("expected %d, got %d: %s", tt.status, w.Code, w.Body)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package controller
+
+import (
+	"net/http"
+
+	"hotel-soa/model"
+	"hotel-soa/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NightAuditController expõe o fechamento do dia (night audit)
+type NightAuditController struct {
+	service service.NightAuditService
+}
+
+// NewNightAuditController cria um novo NightAuditController
+func NewNightAuditController(s service.NightAuditService) *NightAuditController {
+	return &NightAuditController{service: s}
+}
+
+// @Summary Executa o night audit
+// @Description Fecha a data de negócio: lança a diária de cada reserva em casa, grava o resumo do dia e impede alterações nas noites até a data. Sem business_date fecha o dia seguinte ao último fechamento. Repetir uma data já fechada devolve o fechamento gravado com 200. Exige night_audit:run
+// @Tags night-audit
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param audit body model.NightAuditRequest false "Data de negócio a fechar"
+// @Success 201 {object} model.NightAudit
+// @Success 200 {object} model.NightAudit
+// @Failure 400 {object} model.Problem
+// @Failure 401 {object} model.Problem
+// @Failure 403 {object} model.Problem
+// @Failure 409 {object} model.Problem
+// @Failure 500 {object} model.Problem
+// @Router /night-audit [post]
+func (nc *NightAuditController) Run(c *gin.Context) {
+	// o corpo é opcional: sem ele fecha o próximo dia em aberto
+	var req model.NightAuditRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			writeBindError(c, err)
+			return
+		}
+	}
+
+	audit, closed, err := nc.service.Run(req.BusinessDate, currentActor(c))
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	status := http.StatusOK
+	if closed {
+		status = http.StatusCreated
+	}
+	c.JSON(status, audit)
+}
+
+// @Summary Lista os fechamentos
+// @Description Lista os dias fechados pelo night audit, do mais recente para o mais antigo. Exige night_audit:run
+// @Tags night-audit
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Success 200 {array} model.NightAudit
+// @Failure 401 {object} model.Problem
+// @Failure 403 {object} model.Problem
+// @Failure 500 {object} model.Problem
+// @Router /night-audit [get]
+func (nc *NightAuditController) GetAll(c *gin.Context) {
+	audits, err := nc.service.GetAll()
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, audits)
+}
+
+// @Summary Busca o fechamento de uma data
+// @Description Retorna o resumo gravado pelo night audit da data de negócio. Exige night_audit:run
+// @Tags night-audit
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param date path string true "Data de negócio (YYYY-MM-DD)"
+// @Success 200 {object} model.NightAudit
+// @Failure 400 {object} model.Problem
+// @Failure 401 {object} model.Problem
+// @Failure 403 {object} model.Problem
+// @Failure 404 {object} model.Problem
+// @Failure 500 {object} model.Problem
+// @Router /night-audit/{date} [get]
+func (nc *NightAuditController) GetByDate(c *gin.Context) {
+	audit, err := nc.service.GetByDate(c.Param("date"))
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, audit)
+}
@@ -0,0 +1,56 @@
+package controller
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"hotel-soa/dao"
+	"hotel-soa/model"
+	"hotel-soa/service"
+)
+
+func TestNightAuditEndpoints(t *testing.T) {
+	repos := dao.NewMemoryRepositories()
+	nc := NewNightAuditController(service.NewNightAuditService(repos.NightAudits, repos.Reservations, repos.Rooms))
+	r := newTestRouter()
+	r.POST("/night-audit", nc.Run)
+	r.GET("/night-audit", nc.GetAll)
+	r.GET("/night-audit/:date", nc.GetByDate)
+
+	if w := performRequest(r, http.MethodGet, "/night-audit", ""); w.Code != http.StatusOK || w.Body.String() != "[]" {
+		t.Fatalf("expected an empty list, got %d: %s", w.Code, w.Body)
+	}
+
+	// sem corpo fecha o dia de hoje
+	today := time.Now().UTC().Format("2006-01-02")
+	w := performRequest(r, http.MethodPost, "/night-audit", "")
+	var audit model.NightAudit
+	decodeBody(t, w, &audit)
+	if w.Code != http.StatusCreated || audit.BusinessDate != today || audit.ClosedBy == "" {
+		t.Fatalf("expected 201 closing %s, got %d: %s", today, w.Code, w.Body)
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		status int
+	}{
+		{"repeat", http.MethodPost, "/night-audit", `{"business_date":"` + today + `"}`, http.StatusOK},
+		{"closed date", http.MethodPost, "/night-audit", `{"business_date":"2000-01-01"}`, http.StatusConflict},
+		{"future date", http.MethodPost, "/night-audit", `{"business_date":"2999-01-01"}`, http.StatusBadRequest},
+		{"malformed body", http.MethodPost, "/night-audit", `{"business_date":1}`, http.StatusBadRequest},
+		{"get", http.MethodGet, "/night-audit/" + today, "", http.StatusOK},
+		{"get open date", http.MethodGet, "/night-audit/2999-01-01", "", http.StatusNotFound},
+		{"get bad date", http.MethodGet, "/night-audit/today", "", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if w := performRequest(r, tt.method, tt.path, tt.body); w.Code != tt.status {
+				t.Fatalf("expected %d, got %d: %s", tt.status, w.Code, w.Body)
+			}
+		})
+	}
+}
@@ -0,0 +1,233 @@
+package controller
+
+import (
+	"net/http"
+
+	"hotel-soa/model"
+	"hotel-soa/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OverbookingController gerencia as regras de overbooking, o walk list e os alertas de limite
+type OverbookingController struct {
+	service service.OverbookingService
+}
+
+// NewOverbookingController cria um novo OverbookingController
+func NewOverbookingController(s service.OverbookingService) *OverbookingController {
+	return &OverbookingController{service: s}
+}
+
+// @Summary Cria uma regra de overbooking
+// @Description Permite vender percent% (até 50) a mais dos quartos de um tipo nas noites entre start_date e end_date (inclusivos). Os quartos extras são floor(quartos * percent / 100) e valem só para reservas por tipo e para o estoque do tipo; um quarto específico nunca recebe duas reservas. Regras do mesmo tipo não podem se sobrepor (409 overbooking_rule_overlap). Vale na hora para as reservas novas
+// @Tags overbooking-rules
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param rule body model.OverbookingRuleRequest true "Regra de overbooking"
+// @Success 201 {object} model.OverbookingRule
+// @Failure 400 {object} model.Problem
+// @Failure 401 {object} model.Problem
+// @Failure 403 {object} model.Problem
+// @Failure 409 {object} model.Problem
+// @Failure 500 {object} model.Problem
+// @Router /overbooking-rules [post]
+func (oc *OverbookingController) Create(c *gin.Context) {
+	var req model.OverbookingRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeBindError(c, err)
+		return
+	}
+
+	rule := req.OverbookingRule()
+	if err := rule.Validate(); err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	id, err := oc.service.Create(*rule)
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	rule.ID = id
+	c.JSON(http.StatusCreated, rule)
+}
+
+// @Summary Atualiza uma regra de overbooking
+// @Description Atualiza uma regra pelo ID. Reduzir o limite não cancela nada: as reservas já vendidas acima dele continuam e aparecem no walk list
+// @Tags overbooking-rules
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path string true "ID da Regra (UUID)"
+// @Param rule body model.OverbookingRuleRequest true "Regra de overbooking atualizada"
+// @Success 200 {object} model.OverbookingRule
+// @Failure 400 {object} model.Problem
+// @Failure 401 {object} model.Problem
+// @Failure 403 {object} model.Problem
+// @Failure 404 {object} model.Problem
+// @Failure 409 {object} model.Problem
+// @Failure 500 {object} model.Problem
+// @Router /overbooking-rules/{id} [put]
+func (oc *OverbookingController) Update(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		writeProblem(c, errInvalidID)
+		return
+	}
+
+	var req model.OverbookingRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeBindError(c, err)
+		return
+	}
+
+	req.ID = id
+	rule := req.OverbookingRule()
+	if err := rule.Validate(); err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	if err := oc.service.Update(*rule); err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, rule)
+}
+
+// @Summary Deleta uma regra de overbooking
+// @Description Deleta uma regra pelo ID; as noites voltam a aceitar só os quartos físicos do tipo
+// @Tags overbooking-rules
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path string true "ID da Regra (UUID)"
+// @Success 204
+// @Failure 400 {object} model.Problem
+// @Failure 401 {object} model.Problem
+// @Failure 403 {object} model.Problem
+// @Failure 404 {object} model.Problem
+// @Failure 500 {object} model.Problem
+// @Router /overbooking-rules/{id} [delete]
+func (oc *OverbookingController) Delete(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		writeProblem(c, errInvalidID)
+		return
+	}
+
+	if err := oc.service.Delete(id); err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Busca regra de overbooking pelo ID
+// @Description Retorna uma regra de overbooking pelo seu ID
+// @Tags overbooking-rules
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path string true "ID da Regra (UUID)"
+// @Success 200 {object} model.OverbookingRule
+// @Failure 400 {object} model.Problem
+// @Failure 401 {object} model.Problem
+// @Failure 403 {object} model.Problem
+// @Failure 404 {object} model.Problem
+// @Router /overbooking-rules/{id} [get]
+func (oc *OverbookingController) GetByID(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		writeProblem(c, errInvalidID)
+		return
+	}
+
+	rule, err := oc.service.GetByID(id)
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, rule)
+}
+
+// @Summary Lista todas as regras de overbooking
+// @Description Retorna todas as regras de overbooking cadastradas, por tipo de quarto e data de início
+// @Tags overbooking-rules
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Success 200 {array} model.OverbookingRule
+// @Success 204 "No Content"
+// @Failure 401 {object} model.Problem
+// @Failure 403 {object} model.Problem
+// @Failure 500 {object} model.Problem
+// @Router /overbooking-rules [get]
+func (oc *OverbookingController) GetAll(c *gin.Context) {
+	rules, err := oc.service.GetAll()
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	if len(rules) == 0 {
+		c.Status(http.StatusNoContent)
+		return
+	}
+
+	c.JSON(http.StatusOK, rules)
+}
+
+// @Summary Walk list do período
+// @Description Lista as noites entre from e to (inclusive, até 92 dias) em que as reservas ativas de um tipo passam dos quartos físicos. excess é quantos hóspedes ficarão sem quarto se todos chegarem e candidates traz até excess reservas a realocar: primeiro as sem quarto, depois as CREATED antes das CONFIRMED e as de chegada mais tarde. Exige reports:read
+// @Tags reports
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param from query string true "Primeira noite (YYYY-MM-DD)"
+// @Param to query string true "Última noite (YYYY-MM-DD)"
+// @Success 200 {object} model.WalkListReport
+// @Failure 400 {object} model.Problem
+// @Failure 401 {object} model.Problem
+// @Failure 403 {object} model.Problem
+// @Failure 500 {object} model.Problem
+// @Router /reports/walk-list [get]
+func (oc *OverbookingController) WalkList(c *gin.Context) {
+	report, err := oc.service.WalkList(c.Query("from"), c.Query("to"))
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// @Summary Alertas de limite de overbooking
+// @Description Lista as noites entre from e to (inclusive, até 92 dias) em que um tipo com regra de overbooking chegou ao limite: reservas (booked) e bloqueios ativos (held) ocupam os quartos físicos e todos os extras. Sem from, considera hoje; sem to, 30 dias depois de from. Exige reports:read
+// @Tags reports
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param from query string false "Primeira noite (YYYY-MM-DD)"
+// @Param to query string false "Última noite (YYYY-MM-DD)"
+// @Success 200 {array} model.RoomTypeNight
+// @Failure 400 {object} model.Problem
+// @Failure 401 {object} model.Problem
+// @Failure 403 {object} model.Problem
+// @Failure 500 {object} model.Problem
+// @Router /reports/overbooking-alerts [get]
+func (oc *OverbookingController) Alerts(c *gin.Context) {
+	alerts, err := oc.service.Alerts(c.Query("from"), c.Query("to"))
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, alerts)
+}
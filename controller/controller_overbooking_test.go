@@ -0,0 +1,91 @@
+package controller
+
+import (
+	"net/http"
+	"testing"
+
+	"hotel-soa/dao"
+	"hotel-soa/model"
+	"hotel-soa/service"
+)
+
+func TestOverbookingEndpoints(t *testing.T) {
+	repos := dao.NewMemoryRepositories()
+	roomID, err := repos.Rooms.InsertRoom(model.Room{Number: 201, Type: "DELUXE", Capacity: 2, PricePerNight: model.NewMoney(25000), Status: "ATIVO"}, testActor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	guestID, err := repos.Guests.InsertGuest(model.Guest{Name: "Ana"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repos.Reservations.InsertReservation(model.Reservation{RoomID: roomID, RoomType: "DELUXE", GuestID: guestID, GuestName: "Ana", CheckinExpected: "2026-12-31",
+		CheckoutExpected: "2027-01-01", Status: "CREATED", TotalAmount: model.NewMoney(25000)}, testActor); err != nil {
+		t.Fatal(err)
+	}
+	oc := NewOverbookingController(service.NewOverbookingService(repos.Overbooking, repos.Reservations))
+	r := newTestRouter()
+	r.POST("/overbooking-rules", oc.Create)
+	r.PUT("/overbooking-rules/:id", oc.Update)
+	r.DELETE("/overbooking-rules/:id", oc.Delete)
+	r.GET("/overbooking-rules/:id", oc.GetByID)
+	r.GET("/overbooking-rules", oc.GetAll)
+	r.GET("/reports/walk-list", oc.WalkList)
+	r.GET("/reports/overbooking-alerts", oc.Alerts)
+
+	if w := performRequest(r, http.MethodGet, "/overbooking-rules", ""); w.Code != http.StatusNoContent {
+		t.Fatalf("expected 204 without rules, got %d: %s", w.Code, w.Body)
+	}
+	w := performRequest(r, http.MethodPost, "/overbooking-rules", `{"name":"Réveillon","room_type":"DELUXE","start_date":"2026-12-31","end_date":"2026-12-31","percent":10}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body)
+	}
+	var rule model.OverbookingRule
+	decodeBody(t, w, &rule)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		status int
+	}{
+		{"missing fields", http.MethodPost, "/overbooking-rules", `{"name":"X"}`, http.StatusBadRequest},
+		{"percent out of range", http.MethodPost, "/overbooking-rules", `{"name":"X","room_type":"SUITE","start_date":"2026-12-31","end_date":"2026-12-31","percent":80}`, http.StatusBadRequest},
+		{"overlap", http.MethodPost, "/overbooking-rules", `{"name":"X","room_type":"DELUXE","start_date":"2026-12-30","end_date":"2027-01-01","percent":5}`, http.StatusConflict},
+		{"update", http.MethodPut, "/overbooking-rules/" + rule.ID, `{"name":"Réveillon","room_type":"DELUXE","start_date":"2026-12-30","end_date":"2026-12-31","percent":50}`, http.StatusOK},
+		{"update unknown", http.MethodPut, "/overbooking-rules/00000000-0000-0000-0000-000000000000", `{"name":"X","room_type":"SUITE","start_date":"2026-12-31","end_date":"2026-12-31","percent":5}`, http.StatusNotFound},
+		{"get", http.MethodGet, "/overbooking-rules/" + rule.ID, "", http.StatusOK},
+		{"list", http.MethodGet, "/overbooking-rules", "", http.StatusOK},
+		{"walk list without dates", http.MethodGet, "/reports/walk-list", "", http.StatusBadRequest},
+		{"alerts out of range", http.MethodGet, "/reports/overbooking-alerts?from=2026-01-01&to=2026-12-31", "", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if w := performRequest(r, tt.method, tt.path, tt.body); w.Code != tt.status {
+				t.Fatalf("expected %d, got %d: %s", tt.status, w.Code, w.Body)
+			}
+		})
+	}
+
+	// com 50% de um quarto não sobra quarto extra, então a única reserva não gera alerta
+	w = performRequest(r, http.MethodGet, "/reports/overbooking-alerts?from=2026-12-28&to=2027-01-02", "")
+	var alerts []model.RoomTypeNight
+	decodeBody(t, w, &alerts)
+	if w.Code != http.StatusOK || len(alerts) != 0 {
+		t.Fatalf("expected no alerts, got %d: %s", w.Code, w.Body)
+	}
+	w = performRequest(r, http.MethodGet, "/reports/walk-list?from=2026-12-28&to=2027-01-02", "")
+	var report model.WalkListReport
+	decodeBody(t, w, &report)
+	if w.Code != http.StatusOK || report.From != "2026-12-28" || len(report.Nights) != 0 {
+		t.Fatalf("expected an empty walk list, got %d: %s", w.Code, w.Body)
+	}
+
+	if w := performRequest(r, http.MethodDelete, "/overbooking-rules/"+rule.ID, ""); w.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d: %s", w.Code, w.Body)
+	}
+	if w := performRequest(r, http.MethodGet, "/overbooking-rules/"+rule.ID, ""); w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 after delete, got %d: %s", w.Code, w.Body)
+	}
+}
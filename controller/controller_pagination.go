@@ -0,0 +1,74 @@
+package controller
+
+import (
+	"fmt"
+	"strconv"
+
+	"hotel-soa/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+// totalCountHeader carrega o total de itens que atendem aos filtros de uma listagem
+const totalCountHeader = "X-Total-Count"
+
+// parsePageRequest lê limit, cursor e sort da query string; o cursor é conferido contra
+// o tipo do campo em kinds. Os problemas voltam como *model.ValidationError
+func parsePageRequest(c *gin.Context, fallbackSort string, allowed []string, kinds map[string]model.SortKind) (model.PageRequest, error) {
+	var v model.ValidationError
+	page := model.PageRequest{Limit: model.DefaultPageLimit}
+
+	if limit := c.Query("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n <= 0 || n > model.MaxPageLimit {
+			v.Add("limit", "out_of_range", fmt.Sprintf("must be an integer between 1 and %d", model.MaxPageLimit))
+		}
+		page.Limit = n
+	}
+
+	sort, err := model.ParseSort(c.Query("sort"), fallbackSort, allowed)
+	if err != nil {
+		v.Add("sort", "invalid_value", err.Error())
+		return page, v.Err()
+	}
+	page.Sort = sort
+
+	cursor, err := model.DecodeCursor(c.Query("cursor"))
+	if err != nil {
+		v.Add("cursor", "invalid_value", err.Error())
+	} else if cursor != nil && cursor.Sort != sort.String() {
+		v.Add("cursor", "invalid_value", fmt.Sprintf("was issued for sort %q, not %q", cursor.Sort, sort.String()))
+	} else if cursor != nil {
+		if err := cursor.Check(kinds[sort.Field]); err != nil {
+			v.Add("cursor", "invalid_value", err.Error())
+		}
+	}
+	page.Cursor = cursor
+	return page, v.Err()
+}
+
+// queryInt lê um inteiro opcional da query string, registrando o erro em v
+func queryInt(c *gin.Context, v *model.ValidationError, key string) int {
+	value := c.Query(key)
+	if value == "" {
+		return 0
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		v.Add(key, "invalid_type", "must be an integer")
+	}
+	return n
+}
+
+// queryMoney lê um valor opcional da query string, registrando o erro em v
+func queryMoney(c *gin.Context, v *model.ValidationError, key string) model.Money {
+	value := c.Query(key)
+	if value == "" {
+		return model.Money{}
+	}
+	amount, err := model.ParseMoney(value)
+	if err != nil {
+		v.Add(key, "invalid_type", moneyMessage())
+	}
+	return amount
+}
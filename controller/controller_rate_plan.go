@@ -0,0 +1,182 @@
+package controller
+
+import (
+	"net/http"
+
+	"hotel-soa/model"
+	"hotel-soa/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RatePlanController gerencia endpoints de planos tarifários
+type RatePlanController struct {
+	service service.RatePlanService
+}
+
+// NewRatePlanController cria um novo RatePlanController
+func NewRatePlanController(s service.RatePlanService) *RatePlanController {
+	return &RatePlanController{service: s}
+}
+
+// @Summary Cria um novo plano tarifário
+// @Description Cria um plano tarifário para um tipo de quarto num intervalo de datas
+// @Tags rate-plans
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param ratePlan body model.RatePlanRequest true "Plano tarifário"
+// @Success 201 {object} model.RatePlan
+// @Failure 400 {object} model.Problem
+// @Failure 401 {object} model.Problem
+// @Failure 403 {object} model.Problem
+// @Failure 500 {object} model.Problem
+// @Router /rate-plans [post]
+func (rc *RatePlanController) Create(c *gin.Context) {
+	var req model.RatePlanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeBindError(c, err)
+		return
+	}
+
+	plan := req.RatePlan()
+	if err := plan.Validate(); err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	id, err := rc.service.Create(*plan)
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	plan.ID = id
+	c.JSON(http.StatusCreated, plan)
+}
+
+// @Summary Atualiza um plano tarifário existente
+// @Description Atualiza os dados de um plano tarifário pelo ID
+// @Tags rate-plans
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path string true "ID do Plano (UUID)"
+// @Param ratePlan body model.RatePlanRequest true "Plano tarifário atualizado"
+// @Success 200 {object} model.RatePlan
+// @Failure 400 {object} model.Problem
+// @Failure 401 {object} model.Problem
+// @Failure 403 {object} model.Problem
+// @Failure 404 {object} model.Problem
+// @Failure 500 {object} model.Problem
+// @Router /rate-plans/{id} [put]
+func (rc *RatePlanController) Update(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		writeProblem(c, errInvalidID)
+		return
+	}
+
+	var req model.RatePlanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeBindError(c, err)
+		return
+	}
+
+	req.ID = id
+	plan := req.RatePlan()
+	if err := plan.Validate(); err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	if err := rc.service.Update(*plan); err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, plan)
+}
+
+// @Summary Deleta um plano tarifário
+// @Description Deleta um plano tarifário pelo ID
+// @Tags rate-plans
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path string true "ID do Plano (UUID)"
+// @Success 204
+// @Failure 400 {object} model.Problem
+// @Failure 401 {object} model.Problem
+// @Failure 403 {object} model.Problem
+// @Failure 500 {object} model.Problem
+// @Router /rate-plans/{id} [delete]
+func (rc *RatePlanController) Delete(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		writeProblem(c, errInvalidID)
+		return
+	}
+
+	if err := rc.service.Delete(id); err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Busca plano tarifário pelo ID
+// @Description Retorna um plano tarifário pelo seu ID
+// @Tags rate-plans
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path string true "ID do Plano (UUID)"
+// @Success 200 {object} model.RatePlan
+// @Failure 400 {object} model.Problem
+// @Failure 401 {object} model.Problem
+// @Failure 403 {object} model.Problem
+// @Failure 404 {object} model.Problem
+// @Router /rate-plans/{id} [get]
+func (rc *RatePlanController) GetByID(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		writeProblem(c, errInvalidID)
+		return
+	}
+
+	plan, err := rc.service.GetByID(id)
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, plan)
+}
+
+// @Summary Lista todos os planos tarifários
+// @Description Retorna todos os planos tarifários cadastrados
+// @Tags rate-plans
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Success 200 {array} model.RatePlan
+// @Success 204 "No Content"
+// @Failure 401 {object} model.Problem
+// @Failure 403 {object} model.Problem
+// @Failure 500 {object} model.Problem
+// @Router /rate-plans [get]
+func (rc *RatePlanController) GetAll(c *gin.Context) {
+	plans, err := rc.service.GetAll()
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	if len(plans) == 0 {
+		c.Status(http.StatusNoContent)
+		return
+	}
+
+	c.JSON(http.StatusOK, plans)
+}
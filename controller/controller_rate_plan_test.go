@@ -0,0 +1,51 @@
+package controller
+
+import (
+	"net/http"
+	"testing"
+
+	"hotel-soa/dao"
+	"hotel-soa/model"
+	"hotel-soa/service"
+)
+
+func TestRatePlanEndpoints(t *testing.T) {
+	repos := dao.NewMemoryRepositories()
+	rc := NewRatePlanController(service.NewRatePlanService(repos.RatePlans, repos.Policies))
+	r := newTestRouter()
+	r.POST("/rate-plans", rc.Create)
+	r.PUT("/rate-plans/:id", rc.Update)
+	r.GET("/rate-plans/:id", rc.GetByID)
+
+	w := performRequest(r, http.MethodPost, "/rate-plans", `{"name":"June","room_type":"STANDARD","start_date":"2026-06-01","end_date":"2026-06-30",
+		"base_rate":200,"weekday_multipliers":{"saturday":1.5},"closed_to_arrival":["sunday"]}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body)
+	}
+	var created model.RatePlan
+	decodeBody(t, w, &created)
+	if created.ID == "" || created.WeekdayMultipliers["saturday"] != 1.5 {
+		t.Fatalf("unexpected plan %+v", created)
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		status int
+	}{
+		{"missing fields", http.MethodPost, "/rate-plans", `{"name":"June"}`, http.StatusBadRequest},
+		{"end before start", http.MethodPost, "/rate-plans", `{"name":"June","room_type":"STANDARD","start_date":"2026-06-30","end_date":"2026-06-01"}`, http.StatusBadRequest},
+		{"unknown weekday", http.MethodPut, "/rate-plans/" + created.ID, `{"name":"June","room_type":"STANDARD","start_date":"2026-06-01","end_date":"2026-06-30","weekday_multipliers":{"funday":2}}`, http.StatusBadRequest},
+		{"update", http.MethodPut, "/rate-plans/" + created.ID, `{"name":"June","room_type":"STANDARD","start_date":"2026-06-01","end_date":"2026-06-30","base_rate":210}`, http.StatusOK},
+		{"get", http.MethodGet, "/rate-plans/" + created.ID, "", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if w := performRequest(r, tt.method, tt.path, tt.body); w.Code != tt.status {
+				t.Fatalf("expected %d, got %d: %s", tt.status, w.Code, w.Body)
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package controller
+
+import (
+	"hotel-soa/model"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// requestIDHeader identifica a requisição nos logs e no audit_log
+const requestIDHeader = "X-Request-ID"
+
+// requestIDKey guarda o ID da requisição no contexto do gin
+const requestIDKey = "request_id"
+
+// RequestID reaproveita o X-Request-ID enviado pelo cliente (ou proxy) ou gera um novo,
+// e o devolve na resposta
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(requestIDHeader)
+		if id == "" || len(id) > 100 {
+			id = uuid.NewString()
+		}
+		c.Set(requestIDKey, id)
+		c.Header(requestIDHeader, id)
+		c.Next()
+	}
+}
+
+// currentActor identifica o autor de uma alteração para o audit_log
+func currentActor(c *gin.Context) model.Actor {
+	principal := currentPrincipal(c)
+	return model.Actor{UserID: principal.UserID, Username: principal.Username, RequestID: c.GetString(requestIDKey)}
+}
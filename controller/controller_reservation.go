@@ -2,6 +2,8 @@ package controller
 
 import (
 	"net/http"
+	"strconv"
+	"time"
 
 	"hotel-soa/model"
 	"hotel-soa/service"
@@ -20,84 +22,196 @@ func NewReservationController(s service.ReservationService) *ReservationControll
 }
 
 // @Summary Cria uma nova reserva
-// @Description Cria uma nova reserva com os dados fornecidos
+// @Description Cria uma nova reserva com os dados fornecidos. Só com room_type, sem room_id, a reserva é de um quarto qualquer do tipo, com o preço do quarto mais barato do tipo, e o quarto é atribuído depois (PUT /reservation/{id}/room, POST /reservation/room-assignments ou no check-in); o tipo esgotado em alguma noite responde 409 room_type_sold_out. Com hold_token, o bloqueio criado em /holds vira a reserva, que precisa ter o mesmo quarto e as mesmas datas (409 hold_expired se o bloqueio venceu). Com payment.card_token, o depósito (DEPOSIT_PERCENT do total) é pré-autorizado no cartão e a reserva nasce CONFIRMED. Se o gateway recusar (402 payment_declined) ou não responder (504 payment_gateway_timeout), a reserva fica gravada como CREATED e o reservation_id do erro serve para tentar de novo em /reservation/{id}/payment-authorizations
 // @Tags reservations
 // @Accept json
 // @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Param reservation body model.ReservationResponse true "Reserva"
 // @Success 201 {object} model.Reservation
-// @Failure 400 {object} model.ErrorResponse
-// @Failure 500 {object} model.ErrorResponse
+// @Failure 400 {object} model.Problem
+// @Failure 401 {object} model.Problem
+// @Failure 402 {object} model.Problem
+// @Failure 403 {object} model.Problem
+// @Failure 409 {object} model.Problem
+// @Failure 500 {object} model.Problem
+// @Failure 504 {object} model.Problem
 // @Router /reservations [post]
 func (rc *ReservationController) Create(c *gin.Context) {
 	var req model.ReservationResponse
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		writeBindError(c, err)
 		return
 	}
 
-	res := req.Reservation()
-	id, status, err := rc.service.Create(*res)
+	res, err := rc.service.Create(*req.Reservation(), req.HoldToken, req.Payment, currentActor(c))
 	if err != nil {
-		c.JSON(status, gin.H{"error": err.Error()})
+		writeProblem(c, err)
 		return
 	}
 
-	res.ID = id
 	c.JSON(http.StatusCreated, res)
 }
 
 // @Summary Atualiza uma reserva existente
-// @Description Atualiza os dados de uma reserva pelo ID
+// @Description Atualiza os dados de uma reserva pelo ID. As datas, o tipo e o quarto de uma reserva CANCELED, CHECKED_OUT ou NO_SHOW não mudam mais (409 invalid_transition). Um status novo é aplicado depois dos demais campos pela ação própria dele, com as mesmas regras e a mesma permissão: CONFIRMED pré-autoriza o depósito com payment (folio:write), CHECKED_IN e CHECKED_OUT fazem o check-in e o check-out com o usuário como operador, CANCELED cancela (reservations:delete) e NO_SHOW, depois do dia de chegada, fecha a reserva com a multa de no-show (reservations:delete)
 // @Tags reservations
 // @Accept json
 // @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Param id path string true "ID da Reserva (UUID)"
 // @Param reservation body model.ReservationResponse true "Reserva atualizada"
 // @Success 200 {object} model.Reservation
-// @Failure 400 {object} model.ErrorResponse
-// @Failure 500 {object} model.ErrorResponse
+// @Failure 400 {object} model.Problem
+// @Failure 401 {object} model.Problem
+// @Failure 403 {object} model.Problem
+// @Failure 404 {object} model.Problem
+// @Failure 409 {object} model.Problem
+// @Failure 500 {object} model.Problem
 // @Router /reservations/{id} [put]
 func (rc *ReservationController) Update(c *gin.Context) {
 	id := c.Param("id")
 	if id == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		writeProblem(c, errInvalidID)
 		return
 	}
 
 	var req model.ReservationResponse
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		writeBindError(c, err)
 		return
 	}
 
+	// reservations:update permite editar a reserva, mas a mudança de status pede a
+	// permissão da ação para onde é encaminhada
+	if permission, ok := statusPermissions[req.Status]; ok && !currentPrincipal(c).Can(permission) {
+		current, err := rc.service.GetByID(id)
+		if err != nil {
+			writeProblem(c, err)
+			return
+		}
+		if current.Status != req.Status {
+			writeProblem(c, &model.PermissionError{Permission: permission})
+			return
+		}
+	}
+
 	req.ID = id
-	res := req.Reservation()
-	if status, err := rc.service.Update(*res); err != nil {
-		c.JSON(status, gin.H{"error": err.Error()})
+	res, err := rc.service.Update(*req.Reservation(), req.Payment, currentActor(c))
+	if err != nil {
+		writeProblem(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, res)
 }
 
-// @Summary Deleta uma reserva
-// @Description Deleta uma reserva pelo ID
+// statusPermissions são as permissões das ações que fazem cada mudança de status do PUT
+var statusPermissions = map[string]string{
+	"CONFIRMED":   model.PermFolioWrite,
+	"CHECKED_IN":  model.PermReservationsCheckIn,
+	"CHECKED_OUT": model.PermReservationsCheckOut,
+	"CANCELED":    model.PermReservationsDelete,
+	"NO_SHOW":     model.PermReservationsDelete,
+}
+
+// @Summary Cancela uma reserva
+// @Description Cancela uma reserva CREATED ou CONFIRMED. A reserva não é apagada: fica com status CANCELED, o motivo informado, quem cancelou e quando, e a multa e o reembolso calculados pela política de cancelamento. A multa é capturada do depósito pré-autorizado, até o valor autorizado, e o resto é liberado no cartão
 // @Tags reservations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Param id path string true "ID da Reserva (UUID)"
-// @Success 204
-// @Failure 400 {object} model.ErrorResponse
-// @Failure 500 {object} model.ErrorResponse
+// @Param cancel body model.CancelRequest false "Motivo do cancelamento"
+// @Success 200 {object} model.Reservation
+// @Failure 400 {object} model.Problem
+// @Failure 401 {object} model.Problem
+// @Failure 403 {object} model.Problem
+// @Failure 404 {object} model.Problem
+// @Failure 409 {object} model.Problem
+// @Failure 500 {object} model.Problem
 // @Router /reservations/{id} [delete]
 func (rc *ReservationController) Delete(c *gin.Context) {
 	id := c.Param("id")
 	if id == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		writeProblem(c, errInvalidID)
+		return
+	}
+
+	// o corpo é opcional: sem ele a reserva é cancelada sem motivo
+	var req model.CancelRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			writeBindError(c, err)
+			return
+		}
+	}
+
+	res, err := rc.service.Cancel(id, req.Reason, currentActor(c))
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// @Summary Simula o cancelamento de uma reserva
+// @Description Calcula a multa e o reembolso de um cancelamento feito agora, pela política fixada na reserva, sem cancelar
+// @Tags reservations
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path string true "ID da Reserva (UUID)"
+// @Success 200 {object} model.CancellationQuote
+// @Failure 400 {object} model.Problem
+// @Failure 401 {object} model.Problem
+// @Failure 403 {object} model.Problem
+// @Failure 404 {object} model.Problem
+// @Failure 409 {object} model.Problem
+// @Failure 500 {object} model.Problem
+// @Router /reservations/{id}/cancellation-quote [get]
+func (rc *ReservationController) CancellationQuote(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		writeProblem(c, errInvalidID)
 		return
 	}
 
-	if err := rc.service.Delete(id); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	quote, err := rc.service.QuoteCancellation(id)
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, quote)
+}
+
+// @Summary Apaga uma reserva definitivamente
+// @Description Remove a reserva do banco, para registros lançados por engano. O histórico no audit_log é mantido. Exige records:purge
+// @Tags reservations
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path string true "ID da Reserva (UUID)"
+// @Success 204
+// @Failure 400 {object} model.Problem
+// @Failure 401 {object} model.Problem
+// @Failure 403 {object} model.Problem
+// @Failure 404 {object} model.Problem
+// @Failure 500 {object} model.Problem
+// @Router /reservations/{id}/purge [delete]
+func (rc *ReservationController) Purge(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		writeProblem(c, errInvalidID)
+		return
+	}
+
+	if err := rc.service.Purge(id, currentActor(c)); err != nil {
+		writeProblem(c, err)
 		return
 	}
 
@@ -107,45 +221,305 @@ func (rc *ReservationController) Delete(c *gin.Context) {
 // @Summary Busca reserva pelo ID
 // @Description Retorna uma reserva pelo seu ID
 // @Tags reservations
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Param id path string true "ID da Reserva (UUID)"
 // @Success 200 {object} model.Reservation
-// @Failure 400 {object} model.ErrorResponse
-// @Failure 404 {object} model.ErrorResponse
+// @Failure 400 {object} model.Problem
+// @Failure 401 {object} model.Problem
+// @Failure 403 {object} model.Problem
+// @Failure 404 {object} model.Problem
 // @Router /reservations/{id} [get]
 func (rc *ReservationController) GetByID(c *gin.Context) {
 	id := c.Param("id")
 	if id == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		writeProblem(c, errInvalidID)
 		return
 	}
 
 	res, err := rc.service.GetByID(id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+		writeProblem(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, res)
 }
 
-// @Summary Lista todas as reservas
-// @Description Retorna todas as reservas cadastradas
+// @Summary Lista as reservas
+// @Description Retorna as reservas paginadas por cursor, com filtros e ordenação. O total de itens vem no header X-Total-Count.
 // @Tags reservations
-// @Success 200 {array} model.Reservation
-// @Success 204 "No Content"
-// @Failure 500 {object} model.ErrorResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param room_id query string false "ID do quarto"
+// @Param room_type query string false "Tipo de quarto reservado"
+// @Param status query string false "Status da reserva"
+// @Param guest_name query string false "Prefixo do nome do hóspede"
+// @Param from query string false "Estadias que terminam depois desta data (YYYY-MM-DD)"
+// @Param to query string false "Estadias que começam antes desta data (YYYY-MM-DD)"
+// @Param sort query string false "checkin_expected, checkout_expected, guest_name ou total_amount (prefixo - para decrescente)"
+// @Param limit query int false "Itens por página (padrão 50, máximo 200)"
+// @Param cursor query string false "next_cursor da página anterior"
+// @Success 200 {object} model.ReservationPage
+// @Failure 400 {object} model.Problem
+// @Failure 401 {object} model.Problem
+// @Failure 403 {object} model.Problem
+// @Failure 500 {object} model.Problem
 // @Router /reservations [get]
 func (rc *ReservationController) GetAll(c *gin.Context) {
-	reservations, err := rc.service.GetAll()
+	page, err := parsePageRequest(c, "checkin_expected", model.ReservationSortFields, model.ReservationSortKinds)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeProblem(c, err)
+		return
+	}
+
+	filter := model.ReservationFilter{
+		RoomID:          c.Query("room_id"),
+		RoomType:        c.Query("room_type"),
+		Status:          c.Query("status"),
+		GuestNamePrefix: c.Query("guest_name"),
+		From:            c.Query("from"),
+		To:              c.Query("to"),
+		Page:            page,
+	}
+	var v model.ValidationError
+	for _, key := range []string{"from", "to"} {
+		if value := c.Query(key); value != "" {
+			if _, err := time.Parse("2006-01-02", value); err != nil {
+				v.Add(key, "invalid_format", "expected YYYY-MM-DD")
+			}
+		}
+	}
+	if err := v.Err(); err != nil {
+		writeProblem(c, err)
 		return
 	}
 
-	if len(reservations) == 0 {
-		c.Status(http.StatusNoContent)
+	reservations, total, err := rc.service.List(filter)
+	if err != nil {
+		writeProblem(c, err)
 		return
 	}
 
+	c.Header(totalCountHeader, strconv.Itoa(total))
 	c.JSON(http.StatusOK, reservations)
 }
+
+// @Summary Lista os alertas de reservas
+// @Description Retorna os hóspedes ainda CHECKED_IN que o agendador sinalizou por passarem do horário de saída (CHECKOUT_DEADLINE_HOUR do dia de checkout_expected)
+// @Tags reservations
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Success 200 {array} model.ReservationAlert
+// @Failure 401 {object} model.Problem
+// @Failure 403 {object} model.Problem
+// @Failure 500 {object} model.Problem
+// @Router /reservations/alerts [get]
+func (rc *ReservationController) Alerts(c *gin.Context) {
+	alerts, err := rc.service.Alerts()
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, alerts)
+}
+
+// @Summary Faz o check-in de uma reserva
+// @Description Marca a reserva CREATED ou CONFIRMED como CHECKED_IN, registrando horário e operador. Recusa antes da data de chegada ou em quarto INATIVO. Com room_id, o quarto é atribuído ou trocado na chegada; sem ele, a reserva ainda sem quarto recebe o quarto ATIVO livre do tipo que deixa o menor buraco entre as reservas (409 no_room_available se nenhum estiver livre na estadia inteira).
+// @Tags reservations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path string true "ID da Reserva (UUID)"
+// @Param action body model.CheckInRequest true "Operador e quarto"
+// @Success 200 {object} model.Reservation
+// @Failure 400 {object} model.Problem
+// @Failure 401 {object} model.Problem
+// @Failure 403 {object} model.Problem
+// @Failure 404 {object} model.Problem
+// @Failure 409 {object} model.Problem
+// @Failure 500 {object} model.Problem
+// @Router /reservations/{id}/check-in [post]
+func (rc *ReservationController) CheckIn(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		writeProblem(c, errInvalidID)
+		return
+	}
+
+	var req model.CheckInRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeBindError(c, err)
+		return
+	}
+
+	res, err := rc.service.CheckIn(id, req.Operator, req.RoomID, currentActor(c))
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// @Summary Faz o check-out de uma reserva
+// @Description Fecha a reserva CHECKED_IN como CHECKED_OUT, registrando horário e operador. Na saída antecipada a estadia é encurtada e só as noites ocupadas são cobradas. A conta final precisa fechar em zero com os pagamentos e o depósito pré-autorizado; senão responde 409 folio_balance_due com o saldo que sobra em balance, sem capturar nada. O depósito é então capturado até o saldo da conta final e lançado como pagamento CARD; recusa na captura responde 402 payment_declined. Se o check-out falhar depois da captura, ela é estornada.
+// @Tags reservations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path string true "ID da Reserva (UUID)"
+// @Param action body model.StayActionRequest true "Operador"
+// @Success 200 {object} model.Reservation
+// @Failure 400 {object} model.Problem
+// @Failure 401 {object} model.Problem
+// @Failure 403 {object} model.Problem
+// @Failure 404 {object} model.Problem
+// @Failure 402 {object} model.Problem
+// @Failure 409 {object} model.Problem
+// @Failure 500 {object} model.Problem
+// @Failure 504 {object} model.Problem
+// @Router /reservations/{id}/check-out [post]
+func (rc *ReservationController) CheckOut(c *gin.Context) {
+	rc.stayAction(c, rc.service.CheckOut)
+}
+
+// @Summary Pré-autoriza o depósito de uma reserva
+// @Description Pré-autoriza no cartão o depósito (DEPOSIT_PERCENT do total) de uma reserva CREATED, criada sem payment ou recusada antes. Aprovada, a reserva passa a CONFIRMED; a tentativa fica registrada na conta mesmo quando o gateway recusa (402 payment_declined, com decline_code) ou não responde (504 payment_gateway_timeout). Exige folio:write
+// @Tags reservations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path string true "ID da Reserva (UUID)"
+// @Param payment body model.CardPaymentRequest true "Cartão tokenizado"
+// @Success 201 {object} model.PaymentAuthorization
+// @Failure 400 {object} model.Problem
+// @Failure 401 {object} model.Problem
+// @Failure 402 {object} model.Problem
+// @Failure 403 {object} model.Problem
+// @Failure 404 {object} model.Problem
+// @Failure 409 {object} model.Problem
+// @Failure 500 {object} model.Problem
+// @Failure 504 {object} model.Problem
+// @Router /reservations/{id}/payment-authorizations [post]
+func (rc *ReservationController) Authorize(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		writeProblem(c, errInvalidID)
+		return
+	}
+
+	var req model.CardPaymentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeBindError(c, err)
+		return
+	}
+
+	auth, err := rc.service.Authorize(id, req, currentActor(c))
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, auth)
+}
+
+// @Summary Atribui o quarto de uma reserva
+// @Description Atribui um quarto à reserva CREATED ou CONFIRMED feita só pelo tipo, ou troca o quarto atribuído. O quarto precisa ser do tipo reservado e estar livre na estadia, pelas mesmas checagens da criação (409 reservation_conflict ou room_on_hold). O preço não muda
+// @Tags reservations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path string true "ID da Reserva (UUID)"
+// @Param assignment body model.RoomAssignmentRequest true "Quarto"
+// @Success 200 {object} model.Reservation
+// @Failure 400 {object} model.Problem
+// @Failure 401 {object} model.Problem
+// @Failure 403 {object} model.Problem
+// @Failure 404 {object} model.Problem
+// @Failure 409 {object} model.Problem
+// @Failure 500 {object} model.Problem
+// @Router /reservations/{id}/room [put]
+func (rc *ReservationController) AssignRoom(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		writeProblem(c, errInvalidID)
+		return
+	}
+
+	var req model.RoomAssignmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeBindError(c, err)
+		return
+	}
+
+	res, err := rc.service.AssignRoom(id, req.RoomID, currentActor(c))
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// @Summary Atribui quartos automaticamente
+// @Description Atribui quartos às reservas CREATED ou CONFIRMED ainda sem quarto com chegada entre from e to (inclusive, no máximo 31 dias; vazio é hoje). As chegadas são tratadas por data e das estadias mais longas para as mais curtas, e cada uma recebe o quarto ATIVO livre do tipo que deixa o menor buraco entre as reservas já atribuídas. As que não couberem em nenhum quarto voltam em unassigned
+// @Tags reservations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param range body model.AutoAssignRequest false "Período de chegadas"
+// @Success 200 {object} model.RoomAssignmentRun
+// @Failure 400 {object} model.Problem
+// @Failure 401 {object} model.Problem
+// @Failure 403 {object} model.Problem
+// @Failure 500 {object} model.Problem
+// @Router /reservations/room-assignments [post]
+func (rc *ReservationController) AutoAssign(c *gin.Context) {
+	// o corpo é opcional: sem ele vale a chegada de hoje
+	var req model.AutoAssignRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			writeBindError(c, err)
+			return
+		}
+	}
+
+	run, err := rc.service.AutoAssign(req, currentActor(c))
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, run)
+}
+
+// stayAction lê o operador e executa o check-out
+func (rc *ReservationController) stayAction(c *gin.Context, action func(id, operator string, actor model.Actor) (model.Reservation, error)) {
+	id := c.Param("id")
+	if id == "" {
+		writeProblem(c, errInvalidID)
+		return
+	}
+
+	var req model.StayActionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeBindError(c, err)
+		return
+	}
+
+	res, err := action(id, req.Operator, currentActor(c))
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
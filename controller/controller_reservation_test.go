@@ -0,0 +1,381 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"hotel-soa/dao"
+	"hotel-soa/gateway"
+	"hotel-soa/model"
+	"hotel-soa/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestCreateReservationConflict(t *testing.T) {
+	repos := dao.NewMemoryRepositories()
+	roomID, err := repos.Rooms.InsertRoom(model.Room{Number: 101, Type: "STANDARD", Capacity: 2, PricePerNight: model.NewMoney(10000), Status: "ATIVO"}, testActor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rc := NewReservationController(service.NewReservationService(repos.Reservations, repos.Rooms, repos.RatePlans, repos.Guests, repos.Policies, repos.Taxes, repos.Folios, repos.Holds, gateway.NewStubGateway(), 30))
+	r := newTestRouter()
+	r.POST("/reservations", rc.Create)
+
+	body := func(checkin, checkout string) string {
+		return fmt.Sprintf(`{"room_id":%q,"guest_name":"Ana","checkin_expected":%q,"checkout_expected":%q,"status":"CREATED","total_amount":1}`,
+			roomID, checkin, checkout)
+	}
+	w := performRequest(r, http.MethodPost, "/reservations", body("2026-06-10", "2026-06-12"))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body)
+	}
+	var booked model.Reservation
+	decodeBody(t, w, &booked)
+	// o total enviado é trocado pelo calculado a partir das noites
+	if !booked.TotalAmount.Equal(model.NewMoney(20000)) || len(booked.Nights) != 2 {
+		t.Fatalf("expected a total of 200 over 2 nights, got %+v", booked)
+	}
+
+	w = performRequest(r, http.MethodPost, "/reservations", body("2026-06-11", "2026-06-13"))
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d: %s", w.Code, w.Body)
+	}
+	var conflict model.Problem
+	decodeBody(t, w, &conflict)
+	if conflict.Code != "reservation_conflict" || conflict.ConflictingReservationID != booked.ID {
+		t.Fatalf("expected conflicting_reservation_id %s, got %+v", booked.ID, conflict)
+	}
+
+	w = performRequest(r, http.MethodPost, "/reservations", body("2026-06-12", "2026-06-14"))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected a booking starting on the departure day to succeed, got %d: %s", w.Code, w.Body)
+	}
+}
+
+func TestListReservationsEndpoint(t *testing.T) {
+	repos := dao.NewMemoryRepositories()
+	rc := NewReservationController(service.NewReservationService(repos.Reservations, repos.Rooms, repos.RatePlans, repos.Guests, repos.Policies, repos.Taxes, repos.Folios, repos.Holds, gateway.NewStubGateway(), 30))
+	r := newTestRouter()
+	r.GET("/reservation", rc.GetAll)
+
+	w := performRequest(r, http.MethodGet, "/reservation", "")
+	var page model.ReservationPage
+	decodeBody(t, w, &page)
+	if w.Code != http.StatusOK || len(page.Data) != 0 || w.Header().Get(totalCountHeader) != "0" {
+		t.Fatalf("expected an empty page, got %d %s", w.Code, w.Body)
+	}
+
+	for _, query := range []string{"from=2026-13-01", "to=tomorrow", "sort=room_id", "limit=-1"} {
+		t.Run(query, func(t *testing.T) {
+			if w := performRequest(r, http.MethodGet, "/reservation?"+query, ""); w.Code != http.StatusBadRequest {
+				t.Fatalf("expected 400, got %d: %s", w.Code, w.Body)
+			}
+		})
+	}
+}
+
+func TestStayActionEndpoints(t *testing.T) {
+	repos := dao.NewMemoryRepositories()
+	roomID, err := repos.Rooms.InsertRoom(model.Room{Number: 101, Type: "STANDARD", Capacity: 2, PricePerNight: model.NewMoney(10000), Status: "ATIVO"}, testActor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rc := NewReservationController(service.NewReservationService(repos.Reservations, repos.Rooms, repos.RatePlans, repos.Guests, repos.Policies, repos.Taxes, repos.Folios, repos.Holds, gateway.NewStubGateway(), 30))
+	r := newTestRouter()
+	r.POST("/reservations", rc.Create)
+	r.POST("/reservations/:id/check-in", rc.CheckIn)
+	r.POST("/reservations/:id/check-out", rc.CheckOut)
+	r.POST("/reservations/:id/folio/payments", NewFolioController(service.NewFolioService(repos.Folios, repos.Reservations, gateway.NewStubGateway())).PostPayment)
+
+	today := time.Now()
+	body := fmt.Sprintf(`{"room_id":%q,"guest_name":"Ana","checkin_expected":%q,"checkout_expected":%q,"status":"CREATED"}`,
+		roomID, today.Format("2006-01-02"), today.AddDate(0, 0, 2).Format("2006-01-02"))
+	w := performRequest(r, http.MethodPost, "/reservations", body)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body)
+	}
+	var res model.Reservation
+	decodeBody(t, w, &res)
+
+	tests := []struct {
+		name   string
+		path   string
+		body   string
+		status int
+	}{
+		{"missing operator", "/reservations/" + res.ID + "/check-in", `{}`, http.StatusBadRequest},
+		{"unknown reservation", "/reservations/00000000-0000-0000-0000-000000000000/check-in", `{"operator":"maria"}`, http.StatusNotFound},
+		{"check-out before check-in", "/reservations/" + res.ID + "/check-out", `{"operator":"maria"}`, http.StatusConflict},
+		{"check-in", "/reservations/" + res.ID + "/check-in", `{"operator":"maria"}`, http.StatusOK},
+		{"check-in twice", "/reservations/" + res.ID + "/check-in", `{"operator":"maria"}`, http.StatusConflict},
+		{"check-out with balance due", "/reservations/" + res.ID + "/check-out", `{"operator":"joao"}`, http.StatusConflict},
+		{"payment", "/reservations/" + res.ID + "/folio/payments", `{"method":"CARD","amount":100}`, http.StatusCreated},
+		{"check-out", "/reservations/" + res.ID + "/check-out", `{"operator":"joao"}`, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if w := performRequest(r, http.MethodPost, tt.path, tt.body); w.Code != tt.status {
+				t.Fatalf("expected %d, got %d: %s", tt.status, w.Code, w.Body)
+			}
+		})
+	}
+}
+
+func TestAlertsEndpoint(t *testing.T) {
+	repos := dao.NewMemoryRepositories()
+	rc := NewReservationController(service.NewReservationService(repos.Reservations, repos.Rooms, repos.RatePlans, repos.Guests, repos.Policies, repos.Taxes, repos.Folios, repos.Holds, gateway.NewStubGateway(), 30))
+	r := newTestRouter()
+	r.GET("/reservation/alerts", rc.Alerts)
+
+	// sem alertas a resposta é uma lista vazia, não null
+	w := performRequest(r, http.MethodGet, "/reservation/alerts", "")
+	if w.Code != http.StatusOK || w.Body.String() != "[]" {
+		t.Fatalf("expected an empty list, got %d: %s", w.Code, w.Body)
+	}
+
+	roomID, err := repos.Rooms.InsertRoom(model.Room{Number: 101, Type: "STANDARD", Capacity: 2, PricePerNight: model.NewMoney(10000), Status: "ATIVO"}, testActor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	guestID, err := repos.Guests.InsertGuest(model.Guest{Name: "Ana"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, err := repos.Reservations.InsertReservation(model.Reservation{RoomID: roomID, GuestID: guestID, RoomType: "STANDARD", GuestName: "Ana", CheckinExpected: "2026-06-08",
+		CheckoutExpected: "2026-06-10", Status: "CREATED", TotalAmount: model.NewMoney(20000)}, testActor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := time.Date(2026, 6, 10, 13, 0, 0, 0, time.UTC)
+	if err := repos.Reservations.CheckInReservation(id, at.AddDate(0, 0, -2), "maria", testActor); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.NewStayMonitor(repos.Reservations, repos.Policies, repos.Folios, gateway.NewStubGateway(), 6, 12).Run(at); err != nil {
+		t.Fatal(err)
+	}
+
+	w = performRequest(r, http.MethodGet, "/reservation/alerts", "")
+	var alerts []model.ReservationAlert
+	decodeBody(t, w, &alerts)
+	if w.Code != http.StatusOK || len(alerts) != 1 || alerts[0].ReservationID != id || alerts[0].Type != model.AlertOverstay {
+		t.Fatalf("expected an overstay alert for %s, got %d: %s", id, w.Code, w.Body)
+	}
+}
+
+func TestPaymentAuthorizationEndpoints(t *testing.T) {
+	repos := dao.NewMemoryRepositories()
+	roomID, err := repos.Rooms.InsertRoom(model.Room{Number: 101, Type: "STANDARD", Capacity: 2, PricePerNight: model.NewMoney(10000), Status: "ATIVO"}, testActor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rc := NewReservationController(service.NewReservationService(repos.Reservations, repos.Rooms, repos.RatePlans, repos.Guests, repos.Policies, repos.Taxes, repos.Folios, repos.Holds, gateway.NewStubGateway(), 30))
+	r := newTestRouter()
+	r.POST("/reservations", rc.Create)
+	r.POST("/reservations/:id/payment-authorizations", rc.Authorize)
+
+	checkin := time.Now().AddDate(0, 1, 0)
+	create := func(offset int, payment string) *httptest.ResponseRecorder {
+		return performRequest(r, http.MethodPost, "/reservations", fmt.Sprintf(`{"room_id":%q,"guest_name":"Ana","checkin_expected":%q,"checkout_expected":%q,"status":"CREATED","payment":%s}`,
+			roomID, checkin.AddDate(0, 0, offset).Format("2006-01-02"), checkin.AddDate(0, 0, offset+2).Format("2006-01-02"), payment))
+	}
+
+	w := create(0, `{"card_token":"tok_visa"}`)
+	var res model.Reservation
+	decodeBody(t, w, &res)
+	if w.Code != http.StatusCreated || res.Status != "CONFIRMED" {
+		t.Fatalf("expected a CONFIRMED reservation, got %d: %s", w.Code, w.Body)
+	}
+
+	w = create(3, `{"card_token":"tok_declined"}`)
+	var problem model.Problem
+	decodeBody(t, w, &problem)
+	if w.Code != http.StatusPaymentRequired || problem.Code != "payment_declined" || problem.DeclineCode != "card_declined" || problem.ReservationID == "" || problem.AuthorizationID == "" {
+		t.Fatalf("expected 402 payment_declined, got %d: %s", w.Code, w.Body)
+	}
+	declinedID := problem.ReservationID
+
+	tests := []struct {
+		name   string
+		path   string
+		body   string
+		status int
+		code   string
+	}{
+		{"missing card token", "/reservations/" + declinedID + "/payment-authorizations", `{}`, http.StatusBadRequest, "validation_failed"},
+		{"gateway timeout", "/reservations/" + declinedID + "/payment-authorizations", `{"card_token":"tok_timeout"}`, http.StatusGatewayTimeout, "payment_gateway_timeout"},
+		{"unknown reservation", "/reservations/00000000-0000-0000-0000-000000000000/payment-authorizations", `{"card_token":"tok_visa"}`, http.StatusNotFound, "reservation_not_found"},
+		{"approved", "/reservations/" + declinedID + "/payment-authorizations", `{"card_token":"tok_visa"}`, http.StatusCreated, ""},
+		{"already confirmed", "/reservations/" + declinedID + "/payment-authorizations", `{"card_token":"tok_visa"}`, http.StatusConflict, "invalid_transition"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := performRequest(r, http.MethodPost, tt.path, tt.body)
+			if w.Code != tt.status {
+				t.Fatalf("expected %d, got %d: %s", tt.status, w.Code, w.Body)
+			}
+			if tt.code == "" {
+				var auth model.PaymentAuthorization
+				decodeBody(t, w, &auth)
+				if auth.Status != model.AuthorizationAuthorized || !auth.Amount.Equal(model.NewMoney(6000)) {
+					t.Fatalf("expected an authorization of 60.00, got %+v", auth)
+				}
+				return
+			}
+			var problem model.Problem
+			decodeBody(t, w, &problem)
+			if problem.Code != tt.code {
+				t.Fatalf("expected %s, got %+v", tt.code, problem)
+			}
+		})
+	}
+}
+
+func TestRoomAssignmentEndpoints(t *testing.T) {
+	repos := dao.NewMemoryRepositories()
+	roomID, err := repos.Rooms.InsertRoom(model.Room{Number: 101, Type: "STANDARD", Capacity: 2, PricePerNight: model.NewMoney(10000), Status: "ATIVO"}, testActor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rc := NewReservationController(service.NewReservationService(repos.Reservations, repos.Rooms, repos.RatePlans, repos.Guests, repos.Policies, repos.Taxes, repos.Folios, repos.Holds, gateway.NewStubGateway(), 30))
+	r := newTestRouter()
+	r.POST("/reservations", rc.Create)
+	r.PUT("/reservations/:id/room", rc.AssignRoom)
+	r.POST("/reservations/room-assignments", rc.AutoAssign)
+	r.POST("/reservations/:id/check-in", rc.CheckIn)
+
+	today := time.Now()
+	body := fmt.Sprintf(`{"room_type":"STANDARD","guest_name":"Ana","checkin_expected":%q,"checkout_expected":%q,"status":"CREATED"}`,
+		today.Format("2006-01-02"), today.AddDate(0, 0, 2).Format("2006-01-02"))
+	w := performRequest(r, http.MethodPost, "/reservations", body)
+	var res model.Reservation
+	decodeBody(t, w, &res)
+	if w.Code != http.StatusCreated || res.RoomID != "" || res.RoomType != "STANDARD" {
+		t.Fatalf("expected an unassigned reservation, got %d: %s", w.Code, w.Body)
+	}
+
+	w = performRequest(r, http.MethodPost, "/reservations", body)
+	var problem model.Problem
+	decodeBody(t, w, &problem)
+	if w.Code != http.StatusConflict || problem.Code != "room_type_sold_out" || problem.RoomType != "STANDARD" || problem.SoldOutDate != today.Format("2006-01-02") {
+		t.Fatalf("expected 409 room_type_sold_out, got %d: %s", w.Code, w.Body)
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		status int
+	}{
+		{"assign without a room", http.MethodPut, "/reservations/" + res.ID + "/room", `{}`, http.StatusBadRequest},
+		{"assign an unknown room", http.MethodPut, "/reservations/" + res.ID + "/room", `{"room_id":"missing"}`, http.StatusBadRequest},
+		{"assign to an unknown reservation", http.MethodPut, "/reservations/missing/room", fmt.Sprintf(`{"room_id":%q}`, roomID), http.StatusNotFound},
+		{"auto assign out of range", http.MethodPost, "/reservations/room-assignments", `{"from":"2026-06-10","to":"2026-06-01"}`, http.StatusBadRequest},
+		{"check-in without operator", http.MethodPost, "/reservations/" + res.ID + "/check-in", `{}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if w := performRequest(r, tt.method, tt.path, tt.body); w.Code != tt.status {
+				t.Fatalf("expected %d, got %d: %s", tt.status, w.Code, w.Body)
+			}
+		})
+	}
+
+	// sem corpo, a atribuição automática cobre as chegadas de hoje
+	w = performRequest(r, http.MethodPost, "/reservations/room-assignments", "")
+	var run model.RoomAssignmentRun
+	decodeBody(t, w, &run)
+	if w.Code != http.StatusOK || len(run.Assigned) != 1 || run.Assigned[0].RoomID != roomID || run.Assigned[0].RoomNumber != 101 {
+		t.Fatalf("expected room 101 to be assigned, got %d: %s", w.Code, w.Body)
+	}
+
+	w = performRequest(r, http.MethodPut, "/reservations/"+res.ID+"/room", fmt.Sprintf(`{"room_id":%q}`, roomID))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200 assigning the same room, got %d: %s", w.Code, w.Body)
+	}
+	w = performRequest(r, http.MethodPost, "/reservations/"+res.ID+"/check-in", fmt.Sprintf(`{"operator":"maria","room_id":%q}`, roomID))
+	decodeBody(t, w, &res)
+	if w.Code != http.StatusOK || res.Status != "CHECKED_IN" || res.RoomID != roomID {
+		t.Fatalf("expected a check-in in room 101, got %d: %s", w.Code, w.Body)
+	}
+}
+
+func TestReservationWithoutStatus(t *testing.T) {
+	repos := dao.NewMemoryRepositories()
+	roomID, err := repos.Rooms.InsertRoom(model.Room{Number: 101, Type: "STANDARD", Capacity: 2, PricePerNight: model.NewMoney(10000), Status: "ATIVO"}, testActor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rc := NewReservationController(service.NewReservationService(repos.Reservations, repos.Rooms, repos.RatePlans, repos.Guests, repos.Policies, repos.Taxes, repos.Folios, repos.Holds, gateway.NewStubGateway(), 30))
+	r := newTestRouter()
+	r.POST("/reservations", rc.Create)
+	r.PUT("/reservations/:id", rc.Update)
+
+	body := fmt.Sprintf(`{"room_id":%q,"guest_name":"Ana","checkin_expected":"2026-06-10","checkout_expected":"2026-06-12"}`, roomID)
+	w := performRequest(r, http.MethodPost, "/reservations", body)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body)
+	}
+	var created model.Reservation
+	decodeBody(t, w, &created)
+	if created.Status != "CREATED" {
+		t.Fatalf("expected a CREATED reservation, got %+v", created)
+	}
+
+	// sem status, o PUT mantém o atual
+	body = fmt.Sprintf(`{"room_id":%q,"guest_name":"Ana","checkin_expected":"2026-06-10","checkout_expected":"2026-06-13"}`, roomID)
+	w = performRequest(r, http.MethodPut, "/reservations/"+created.ID, body)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body)
+	}
+	var updated model.Reservation
+	decodeBody(t, w, &updated)
+	if updated.Status != "CREATED" || updated.CheckoutExpected != "2026-06-13" {
+		t.Fatalf("expected a CREATED reservation until 2026-06-13, got %+v", updated)
+	}
+}
+
+func TestUpdateStatusPermissions(t *testing.T) {
+	repos := dao.NewMemoryRepositories()
+	roomID, err := repos.Rooms.InsertRoom(model.Room{Number: 101, Type: "STANDARD", Capacity: 2, PricePerNight: model.NewMoney(10000), Status: "ATIVO"}, testActor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reservations := service.NewReservationService(repos.Reservations, repos.Rooms, repos.RatePlans, repos.Guests, repos.Policies, repos.Taxes, repos.Folios, repos.Holds, gateway.NewStubGateway(), 30)
+	res, err := reservations.Create(model.Reservation{RoomID: roomID, GuestName: "Ana", CheckinExpected: "2030-06-10", CheckoutExpected: "2030-06-12"}, "", nil, testActor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rc := NewReservationController(reservations)
+	body := func(status string) string {
+		return fmt.Sprintf(`{"room_id":%q,"guest_name":"Ana","checkin_expected":"2030-06-10","checkout_expected":"2030-06-12","status":%q}`, roomID, status)
+	}
+
+	// a recepção edita a reserva, mas cancelar pede reservations:delete
+	frontDesk := gin.New()
+	frontDesk.Use(RequestID(), withPrincipal(principalWithRole(model.RoleFrontDesk)))
+	frontDesk.PUT("/reservation/:id", rc.Update)
+	if w := performRequest(frontDesk, http.MethodPut, "/reservation/"+res.ID, body("CREATED")); w.Code != http.StatusOK {
+		t.Fatalf("expected 200 keeping the status, got %d: %s", w.Code, w.Body)
+	}
+	w := performRequest(frontDesk, http.MethodPut, "/reservation/"+res.ID, body("CANCELED"))
+	var problem model.Problem
+	decodeBody(t, w, &problem)
+	if w.Code != http.StatusForbidden || problem.Code != "missing_permission" {
+		t.Fatalf("expected 403 missing_permission, got %d: %s", w.Code, w.Body)
+	}
+
+	manager := gin.New()
+	manager.Use(RequestID(), withPrincipal(principalWithRole(model.RoleManager)))
+	manager.PUT("/reservation/:id", rc.Update)
+	w = performRequest(manager, http.MethodPut, "/reservation/"+res.ID, body("CANCELED"))
+	var canceled model.Reservation
+	decodeBody(t, w, &canceled)
+	if w.Code != http.StatusOK || canceled.Status != "CANCELED" {
+		t.Fatalf("expected 200 with a CANCELED reservation, got %d: %s", w.Code, w.Body)
+	}
+}
@@ -0,0 +1,166 @@
+package controller
+
+import (
+	"net/http"
+
+	"hotel-soa/model"
+	"hotel-soa/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RoleController gerencia papéis, permissões e a atribuição de papéis a usuários
+type RoleController struct {
+	service service.RoleService
+}
+
+// NewRoleController cria um novo RoleController
+func NewRoleController(s service.RoleService) *RoleController {
+	return &RoleController{service: s}
+}
+
+// @Summary Lista as permissões
+// @Description Lista todas as permissões que podem ser concedidas a um papel
+// @Tags roles
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Success 200 {array} string
+// @Failure 401 {object} model.Problem
+// @Router /auth/permissions [get]
+func (rc *RoleController) GetPermissions(c *gin.Context) {
+	c.JSON(http.StatusOK, model.Permissions)
+}
+
+// @Summary Lista os papéis
+// @Description Lista os papéis com suas permissões
+// @Tags roles
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Success 200 {array} model.Role
+// @Failure 401 {object} model.Problem
+// @Failure 500 {object} model.Problem
+// @Router /auth/roles [get]
+func (rc *RoleController) GetAll(c *gin.Context) {
+	roles, err := rc.service.List()
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, roles)
+}
+
+// @Summary Busca um papel
+// @Tags roles
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param name path string true "Nome do papel"
+// @Success 200 {object} model.Role
+// @Failure 401 {object} model.Problem
+// @Failure 404 {object} model.Problem
+// @Failure 500 {object} model.Problem
+// @Router /auth/roles/{name} [get]
+func (rc *RoleController) GetByName(c *gin.Context) {
+	role, err := rc.service.GetByName(c.Param("name"))
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, role)
+}
+
+// @Summary Cria ou substitui um papel
+// @Description Define a descrição e as permissões do papel. O admin não pode ser alterado. Exige roles:manage
+// @Tags roles
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param name path string true "Nome do papel"
+// @Param role body model.RoleRequest true "Papel"
+// @Success 200 {object} model.Role
+// @Failure 400 {object} model.Problem
+// @Failure 401 {object} model.Problem
+// @Failure 403 {object} model.Problem
+// @Failure 409 {object} model.Problem
+// @Failure 500 {object} model.Problem
+// @Router /auth/roles/{name} [put]
+func (rc *RoleController) Save(c *gin.Context) {
+	var req model.RoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeBindError(c, err)
+		return
+	}
+
+	role, err := rc.service.Save(req.Role(c.Param("name")))
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, role)
+}
+
+// @Summary Remove um papel
+// @Description Remove um papel criado pela API; os usuários perdem o papel. Papéis padrão não podem ser removidos. Exige roles:manage
+// @Tags roles
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param name path string true "Nome do papel"
+// @Success 204
+// @Failure 401 {object} model.Problem
+// @Failure 403 {object} model.Problem
+// @Failure 404 {object} model.Problem
+// @Failure 409 {object} model.Problem
+// @Failure 500 {object} model.Problem
+// @Router /auth/roles/{name} [delete]
+func (rc *RoleController) Delete(c *gin.Context) {
+	if err := rc.service.Delete(c.Param("name")); err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Define os papéis de um usuário
+// @Description Substitui todos os papéis do usuário. Exige users:manage
+// @Tags roles
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path string true "ID do usuário (UUID)"
+// @Param roles body model.UserRolesRequest true "Papéis"
+// @Success 200 {object} model.User
+// @Failure 400 {object} model.Problem
+// @Failure 401 {object} model.Problem
+// @Failure 403 {object} model.Problem
+// @Failure 404 {object} model.Problem
+// @Failure 500 {object} model.Problem
+// @Router /auth/users/{id}/roles [put]
+func (rc *RoleController) SetUserRoles(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		writeProblem(c, errInvalidID)
+		return
+	}
+
+	var req model.UserRolesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeBindError(c, err)
+		return
+	}
+
+	user, err := rc.service.SetUserRoles(id, req.Roles)
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
@@ -0,0 +1,124 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"hotel-soa/dao"
+	"hotel-soa/model"
+	"hotel-soa/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// withPrincipal substitui RequireAuth nos testes, autenticando sempre o principal dado
+func withPrincipal(principal model.Principal) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(principalKey, principal)
+		c.Next()
+	}
+}
+
+// principalWithRole monta um principal com as permissões do papel padrão
+func principalWithRole(name string) model.Principal {
+	for _, role := range model.DefaultRoles() {
+		if role.Name == name {
+			return model.Principal{Username: name, Roles: []string{name}, Permissions: role.Permissions}
+		}
+	}
+	panic("unknown role " + name)
+}
+
+func TestRequirePermission(t *testing.T) {
+	repos := dao.NewMemoryRepositories()
+	roomID, err := repos.Rooms.InsertRoom(model.Room{Number: 101, Type: "STANDARD", Capacity: 2, PricePerNight: model.NewMoney(10000), Status: "ATIVO"}, testActor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rc := NewRoomController(service.NewRoomService(repos.Rooms, repos.Policies))
+	routes := func(role string) *gin.Engine {
+		r := gin.New()
+		rooms := r.Group("/rooms", withPrincipal(principalWithRole(role)))
+		rooms.PUT("/:id", RequirePermission(model.PermRoomsUpdate), rc.Update)
+		rooms.DELETE("/:id", RequirePermission(model.PermRoomsDelete), rc.Delete)
+		return r
+	}
+	room := func(capacity int, price float64) string {
+		return fmt.Sprintf(`{"number":101,"type":"STANDARD","capacity":%d,"price_per_night":%v,"status":"ATIVO"}`, capacity, price)
+	}
+
+	tests := []struct {
+		name       string
+		role       string
+		method     string
+		body       string
+		status     int
+		permission string
+	}{
+		{"read only cannot update", model.RoleReadOnly, http.MethodPut, room(3, 100), http.StatusForbidden, model.PermRoomsUpdate},
+		{"front desk updates the capacity", model.RoleFrontDesk, http.MethodPut, room(3, 100), http.StatusOK, ""},
+		{"front desk cannot change the price", model.RoleFrontDesk, http.MethodPut, room(3, 150), http.StatusForbidden, model.PermRoomsUpdatePrice},
+		{"manager changes the price", model.RoleManager, http.MethodPut, room(3, 150), http.StatusOK, ""},
+		{"housekeeping cannot delete", model.RoleHousekeeping, http.MethodDelete, "", http.StatusForbidden, model.PermRoomsDelete},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := performRequest(routes(tt.role), tt.method, "/rooms/"+roomID, tt.body)
+			if w.Code != tt.status {
+				t.Fatalf("expected %d, got %d: %s", tt.status, w.Code, w.Body)
+			}
+			if tt.permission != "" {
+				var problem model.Problem
+				decodeBody(t, w, &problem)
+				if problem.Code != "missing_permission" || problem.MissingPermission != tt.permission {
+					t.Fatalf("expected missing_permission %s, got %+v", tt.permission, problem)
+				}
+			}
+		})
+	}
+}
+
+func TestRoleEndpoints(t *testing.T) {
+	repos := dao.NewMemoryRepositories()
+	roles := service.NewRoleService(repos.Roles, repos.Users)
+	if err := roles.EnsureDefaults(); err != nil {
+		t.Fatal(err)
+	}
+	userID, err := repos.Users.InsertUser(model.User{Username: "ana", PasswordHash: "hash"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rc := NewRoleController(roles)
+	r := gin.New()
+	r.GET("/auth/roles/:name", rc.GetByName)
+	r.PUT("/auth/roles/:name", rc.Save)
+	r.DELETE("/auth/roles/:name", rc.Delete)
+	r.PUT("/auth/users/:id/roles", rc.SetUserRoles)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		status int
+	}{
+		{"create", http.MethodPut, "/auth/roles/night_audit", `{"permissions":["reservations:read","rooms:read"]}`, http.StatusOK},
+		{"get", http.MethodGet, "/auth/roles/night_audit", "", http.StatusOK},
+		{"unknown permission", http.MethodPut, "/auth/roles/night_audit", `{"permissions":["rooms:burn"]}`, http.StatusBadRequest},
+		{"invalid name", http.MethodPut, "/auth/roles/Night", `{"permissions":[]}`, http.StatusBadRequest},
+		{"edit admin", http.MethodPut, "/auth/roles/admin", `{"permissions":[]}`, http.StatusConflict},
+		{"assign", http.MethodPut, "/auth/users/" + userID + "/roles", `{"roles":["night_audit"]}`, http.StatusOK},
+		{"assign unknown role", http.MethodPut, "/auth/users/" + userID + "/roles", `{"roles":["ghost"]}`, http.StatusBadRequest},
+		{"delete built in", http.MethodDelete, "/auth/roles/front_desk", "", http.StatusConflict},
+		{"delete", http.MethodDelete, "/auth/roles/night_audit", "", http.StatusNoContent},
+		{"get deleted", http.MethodGet, "/auth/roles/night_audit", "", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if w := performRequest(r, tt.method, tt.path, tt.body); w.Code != tt.status {
+				t.Fatalf("expected %d, got %d: %s", tt.status, w.Code, w.Body)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package controller
 
 import (
 	"net/http"
+	"strconv"
 
 	"hotel-soa/model"
 	"hotel-soa/service"
@@ -20,31 +21,36 @@ func NewRoomController(s service.RoomService) *RoomController {
 }
 
 // @Summary Cria um novo quarto
-// @Description Cria um novo quarto com os dados fornecidos
+// @Description Cria um novo quarto com os dados fornecidos. O número já usado por outro quarto não arquivado é recusado com 409 room_number_taken
 // @Tags rooms
 // @Accept json
 // @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Param room body model.RoomRequest true "Quarto"
 // @Success 201 {object} model.Room
-// @Failure 400 {object} model.ErrorResponse
-// @Failure 500 {object} model.ErrorResponse
+// @Failure 400 {object} model.Problem
+// @Failure 401 {object} model.Problem
+// @Failure 403 {object} model.Problem
+// @Failure 409 {object} model.Problem
+// @Failure 500 {object} model.Problem
 // @Router /rooms [post]
 func (rc *RoomController) Create(c *gin.Context) {
 	var req model.RoomRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		writeBindError(c, err)
 		return
 	}
 
 	room := req.Room()
 	if err := room.Validate(); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		writeProblem(c, err)
 		return
 	}
 
-	id, err := rc.service.Create(*room)
+	id, err := rc.service.Create(*room, currentActor(c))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeProblem(c, err)
 		return
 	}
 
@@ -53,61 +59,115 @@ func (rc *RoomController) Create(c *gin.Context) {
 }
 
 // @Summary Atualiza um quarto existente
-// @Description Atualiza os dados de um quarto pelo ID
+// @Description Atualiza os dados de um quarto pelo ID. Alterar price_per_night exige rooms:update_price. O tipo não muda enquanto houver reservas CREATED, CONFIRMED ou CHECKED_IN do tipo antigo no quarto (409 room_has_reservations)
 // @Tags rooms
 // @Accept json
 // @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Param id path string true "ID do Quarto (UUID)"
 // @Param room body model.RoomRequest true "Quarto atualizado"
 // @Success 200 {object} model.Room
-// @Failure 400 {object} model.ErrorResponse
-// @Failure 500 {object} model.ErrorResponse
+// @Failure 400 {object} model.Problem
+// @Failure 401 {object} model.Problem
+// @Failure 403 {object} model.Problem
+// @Failure 404 {object} model.Problem
+// @Failure 409 {object} model.Problem
+// @Failure 500 {object} model.Problem
 // @Router /rooms/{id} [put]
 func (rc *RoomController) Update(c *gin.Context) {
 	id := c.Param("id")
 	if id == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		writeProblem(c, errInvalidID)
 		return
 	}
 
 	var req model.RoomRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		writeBindError(c, err)
 		return
 	}
 
 	req.ID = id
 	room := req.Room()
 	if err := room.Validate(); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		writeProblem(c, err)
 		return
 	}
 
-	if err := rc.service.Update(*room); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	// rooms:update permite mudar o quarto, mas o preço tem permissão própria
+	if !currentPrincipal(c).Can(model.PermRoomsUpdatePrice) {
+		current, err := rc.service.GetByID(id)
+		if err != nil {
+			writeProblem(c, err)
+			return
+		}
+		if !current.PricePerNight.Equal(room.PricePerNight) {
+			writeProblem(c, &model.PermissionError{Permission: model.PermRoomsUpdatePrice})
+			return
+		}
+	}
+
+	if err := rc.service.Update(*room, currentActor(c)); err != nil {
+		writeProblem(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, room)
 }
 
-// @Summary Deleta um quarto
-// @Description Deleta um quarto pelo ID
+// @Summary Arquiva um quarto
+// @Description Arquiva o quarto: ele deixa de aparecer nas listagens e na disponibilidade, mas continua no banco para o histórico. Reservas ativas que ainda não terminaram impedem o arquivamento e são listadas no 409
 // @Tags rooms
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Param id path string true "ID do Quarto (UUID)"
 // @Success 204
-// @Failure 400 {object} model.ErrorResponse
-// @Failure 500 {object} model.ErrorResponse
+// @Failure 400 {object} model.Problem
+// @Failure 401 {object} model.Problem
+// @Failure 403 {object} model.Problem
+// @Failure 404 {object} model.Problem
+// @Failure 409 {object} model.Problem
+// @Failure 500 {object} model.Problem
 // @Router /rooms/{id} [delete]
 func (rc *RoomController) Delete(c *gin.Context) {
 	id := c.Param("id")
 	if id == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		writeProblem(c, errInvalidID)
 		return
 	}
 
-	if err := rc.service.Delete(id); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := rc.service.Archive(id, currentActor(c)); err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Apaga um quarto definitivamente
+// @Description Remove o quarto do banco, para registros lançados por engano. Só é possível sem nenhuma reserva no quarto; as existentes são listadas no 409. Exige records:purge
+// @Tags rooms
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path string true "ID do Quarto (UUID)"
+// @Success 204
+// @Failure 400 {object} model.Problem
+// @Failure 401 {object} model.Problem
+// @Failure 403 {object} model.Problem
+// @Failure 404 {object} model.Problem
+// @Failure 409 {object} model.Problem
+// @Failure 500 {object} model.Problem
+// @Router /rooms/{id}/purge [delete]
+func (rc *RoomController) Purge(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		writeProblem(c, errInvalidID)
+		return
+	}
+
+	if err := rc.service.Purge(id, currentActor(c)); err != nil {
+		writeProblem(c, err)
 		return
 	}
 
@@ -117,45 +177,79 @@ func (rc *RoomController) Delete(c *gin.Context) {
 // @Summary Busca quarto pelo ID
 // @Description Retorna um quarto pelo seu ID
 // @Tags rooms
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Param id path string true "ID do Quarto (UUID)"
 // @Success 200 {object} model.Room
-// @Failure 400 {object} model.ErrorResponse
-// @Failure 404 {object} model.ErrorResponse
+// @Failure 400 {object} model.Problem
+// @Failure 401 {object} model.Problem
+// @Failure 403 {object} model.Problem
+// @Failure 404 {object} model.Problem
 // @Router /rooms/{id} [get]
 func (rc *RoomController) GetByID(c *gin.Context) {
 	id := c.Param("id")
 	if id == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		writeProblem(c, errInvalidID)
 		return
 	}
 
 	room, err := rc.service.GetByID(id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+		writeProblem(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, room)
 }
 
-// @Summary Lista todos os quartos
-// @Description Retorna todos os quartos cadastrados
+// @Summary Lista os quartos
+// @Description Retorna os quartos paginados por cursor, com filtros e ordenação. O total de itens vem no header X-Total-Count.
 // @Tags rooms
-// @Success 200 {array} model.Room
-// @Success 204 "No Content"
-// @Failure 500 {object} model.ErrorResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param status query string false "Status (ATIVO, INATIVO)"
+// @Param type query string false "Tipo (STANDARD, DELUXE, SUITE)"
+// @Param min_capacity query int false "Capacidade mínima"
+// @Param max_capacity query int false "Capacidade máxima"
+// @Param min_price query number false "Preço mínimo por noite"
+// @Param max_price query number false "Preço máximo por noite"
+// @Param sort query string false "number, type, capacity ou price_per_night (prefixo - para decrescente)"
+// @Param limit query int false "Itens por página (padrão 50, máximo 200)"
+// @Param cursor query string false "next_cursor da página anterior"
+// @Success 200 {object} model.RoomPage
+// @Failure 400 {object} model.Problem
+// @Failure 401 {object} model.Problem
+// @Failure 403 {object} model.Problem
+// @Failure 500 {object} model.Problem
 // @Router /rooms [get]
 func (rc *RoomController) GetAll(c *gin.Context) {
-	rooms, err := rc.service.GetAll()
+	page, err := parsePageRequest(c, "number", model.RoomSortFields, model.RoomSortKinds)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeProblem(c, err)
 		return
 	}
 
-	if len(rooms) == 0 {
-		c.Status(http.StatusNoContent)
+	var v model.ValidationError
+	filter := model.RoomFilter{
+		Status:      c.Query("status"),
+		Type:        c.Query("type"),
+		MinCapacity: queryInt(c, &v, "min_capacity"),
+		MaxCapacity: queryInt(c, &v, "max_capacity"),
+		MinPrice:    queryMoney(c, &v, "min_price"),
+		MaxPrice:    queryMoney(c, &v, "max_price"),
+		Page:        page,
+	}
+	if err := v.Err(); err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	rooms, total, err := rc.service.List(filter)
+	if err != nil {
+		writeProblem(c, err)
 		return
 	}
 
+	c.Header(totalCountHeader, strconv.Itoa(total))
 	c.JSON(http.StatusOK, rooms)
 }
@@ -0,0 +1,174 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"hotel-soa/dao"
+	"hotel-soa/gateway"
+	"hotel-soa/model"
+	"hotel-soa/service"
+)
+
+func TestListRoomsEndpoint(t *testing.T) {
+	repos := dao.NewMemoryRepositories()
+	for number := 101; number <= 105; number++ {
+		room := model.Room{Number: number, Type: "STANDARD", Capacity: 2, PricePerNight: model.NewMoney(int64(number) * 100), Status: "ATIVO"}
+		if _, err := repos.Rooms.InsertRoom(room, testActor); err != nil {
+			t.Fatal(err)
+		}
+	}
+	rc := NewRoomController(service.NewRoomService(repos.Rooms, repos.Policies))
+	r := newTestRouter()
+	r.GET("/rooms", rc.GetAll)
+
+	// segue next_cursor até a última página
+	var numbers []int
+	path := "/rooms?limit=2&sort=-number"
+	for pages := 0; path != ""; pages++ {
+		if pages > 3 {
+			t.Fatal("pagination did not end")
+		}
+		w := performRequest(r, http.MethodGet, path, "")
+		if w.Code != http.StatusOK || w.Header().Get(totalCountHeader) != "5" {
+			t.Fatalf("expected 200 with %s 5, got %d %q: %s", totalCountHeader, w.Code, w.Header().Get(totalCountHeader), w.Body)
+		}
+		var page model.RoomPage
+		decodeBody(t, w, &page)
+		for _, room := range page.Data {
+			numbers = append(numbers, room.Number)
+		}
+		path = ""
+		if page.NextCursor != "" {
+			path = "/rooms?limit=2&sort=-number&cursor=" + url.QueryEscape(page.NextCursor)
+		}
+	}
+	if want := []int{105, 104, 103, 102, 101}; !reflect.DeepEqual(numbers, want) {
+		t.Fatalf("expected %v, got %v", want, numbers)
+	}
+
+	w := performRequest(r, http.MethodGet, "/rooms?type=SUITE", "")
+	var empty model.RoomPage
+	decodeBody(t, w, &empty)
+	if w.Code != http.StatusOK || empty.Data == nil || len(empty.Data) != 0 || empty.NextCursor != "" {
+		t.Fatalf("expected an empty page, got %d %s", w.Code, w.Body)
+	}
+
+	id := "00000000-0000-0000-0000-000000000001"
+	other := model.Cursor{Sort: "number", Value: "101", ID: id}.Encode()
+	// cursores forjados com valor ou ID que o banco não aceitaria
+	forged := []string{
+		model.Cursor{Sort: "number", Value: "abc", ID: id}.Encode(),
+		model.Cursor{Sort: "number", Value: "99999999999", ID: id}.Encode(),
+		model.Cursor{Sort: "price_per_night", Value: "cheap", ID: id}.Encode(),
+		model.Cursor{Sort: "type", Value: "A\x00", ID: id}.Encode(),
+		model.Cursor{Sort: "number", Value: "101", ID: "x"}.Encode(),
+	}
+	queries := []string{"limit=0", "limit=201", "sort=status", "min_capacity=two", "max_price=cheap", "cursor=broken", "sort=-number&cursor=" + other,
+		"cursor=" + forged[0], "cursor=" + forged[1], "sort=price_per_night&cursor=" + forged[2], "sort=type&cursor=" + forged[3], "cursor=" + forged[4]}
+	for _, query := range queries {
+		t.Run(query, func(t *testing.T) {
+			if w := performRequest(r, http.MethodGet, "/rooms?"+query, ""); w.Code != http.StatusBadRequest {
+				t.Fatalf("expected 400, got %d: %s", w.Code, w.Body)
+			}
+		})
+	}
+}
+
+func TestDeleteEndpoints(t *testing.T) {
+	repos := dao.NewMemoryRepositories()
+	roomID, err := repos.Rooms.InsertRoom(model.Room{Number: 101, Type: "STANDARD", Capacity: 2, PricePerNight: model.NewMoney(10000), Status: "ATIVO"}, testActor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	roomController := NewRoomController(service.NewRoomService(repos.Rooms, repos.Policies))
+	reservationController := NewReservationController(service.NewReservationService(repos.Reservations, repos.Rooms, repos.RatePlans, repos.Guests, repos.Policies, repos.Taxes, repos.Folios, repos.Holds, gateway.NewStubGateway(), 30))
+	r := newTestRouter()
+	r.DELETE("/rooms/:id", roomController.Delete)
+	r.DELETE("/rooms/:id/purge", roomController.Purge)
+	r.POST("/reservation", reservationController.Create)
+	r.DELETE("/reservation/:id", reservationController.Delete)
+	r.DELETE("/reservation/:id/purge", reservationController.Purge)
+
+	checkin := time.Now().AddDate(0, 1, 0)
+	w := performRequest(r, http.MethodPost, "/reservation", fmt.Sprintf(`{"room_id":%q,"guest_name":"Ana","checkin_expected":%q,"checkout_expected":%q,"status":"CREATED"}`,
+		roomID, checkin.Format("2006-01-02"), checkin.AddDate(0, 0, 2).Format("2006-01-02")))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body)
+	}
+	var res model.Reservation
+	decodeBody(t, w, &res)
+
+	// a reserva futura impede o arquivamento e é citada no problema
+	w = performRequest(r, http.MethodDelete, "/rooms/"+roomID, "")
+	var problem model.Problem
+	decodeBody(t, w, &problem)
+	if w.Code != http.StatusConflict || problem.Code != "room_has_reservations" || len(problem.Reservations) != 1 || problem.Reservations[0].ID != res.ID {
+		t.Fatalf("expected 409 room_has_reservations citing %s, got %d: %s", res.ID, w.Code, w.Body)
+	}
+
+	w = performRequest(r, http.MethodDelete, "/reservation/"+res.ID, `{"reason":"guest request"}`)
+	var canceled model.Reservation
+	decodeBody(t, w, &canceled)
+	if w.Code != http.StatusOK || canceled.Status != "CANCELED" || canceled.CancellationReason != "guest request" || canceled.CanceledBy == "" {
+		t.Fatalf("expected the canceled reservation, got %d: %s", w.Code, w.Body)
+	}
+
+	tests := []struct {
+		name   string
+		path   string
+		body   string
+		status int
+	}{
+		{"cancel twice", "/reservation/" + res.ID, "", http.StatusConflict},
+		{"malformed reason", "/reservation/" + res.ID, `{"reason":1}`, http.StatusBadRequest},
+		{"cancel unknown", "/reservation/00000000-0000-0000-0000-000000000000", "", http.StatusNotFound},
+		{"archive", "/rooms/" + roomID, "", http.StatusNoContent},
+		{"archive twice", "/rooms/" + roomID, "", http.StatusConflict},
+		{"purge room with reservations", "/rooms/" + roomID + "/purge", "", http.StatusConflict},
+		{"purge reservation", "/reservation/" + res.ID + "/purge", "", http.StatusNoContent},
+		{"purge room", "/rooms/" + roomID + "/purge", "", http.StatusNoContent},
+		{"purge unknown room", "/rooms/" + roomID + "/purge", "", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if w := performRequest(r, http.MethodDelete, tt.path, tt.body); w.Code != tt.status {
+				t.Fatalf("expected %d, got %d: %s", tt.status, w.Code, w.Body)
+			}
+		})
+	}
+}
+
+func TestCreateRoomDuplicateNumber(t *testing.T) {
+	repos := dao.NewMemoryRepositories()
+	rc := NewRoomController(service.NewRoomService(repos.Rooms, repos.Policies))
+	r := newTestRouter()
+	r.POST("/rooms", rc.Create)
+	r.PUT("/rooms/:id", rc.Update)
+
+	body := `{"number":101,"type":"STANDARD","capacity":2,"price_per_night":100,"status":"ATIVO"}`
+	if w := performRequest(r, http.MethodPost, "/rooms", body); w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body)
+	}
+	w := performRequest(r, http.MethodPost, "/rooms", body)
+	var problem model.Problem
+	decodeBody(t, w, &problem)
+	if w.Code != http.StatusConflict || problem.Code != "room_number_taken" {
+		t.Fatalf("expected 409 room_number_taken, got %d: %s", w.Code, w.Body)
+	}
+
+	w = performRequest(r, http.MethodPost, "/rooms", `{"number":102,"type":"STANDARD","capacity":2,"price_per_night":100,"status":"ATIVO"}`)
+	var created struct {
+		ID string `json:"id"`
+	}
+	decodeBody(t, w, &created)
+	w = performRequest(r, http.MethodPut, "/rooms/"+created.ID, body)
+	decodeBody(t, w, &problem)
+	if w.Code != http.StatusConflict || problem.Code != "room_number_taken" {
+		t.Fatalf("expected 409 room_number_taken on update, got %d: %s", w.Code, w.Body)
+	}
+}
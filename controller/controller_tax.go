@@ -0,0 +1,210 @@
+package controller
+
+import (
+	"net/http"
+
+	"hotel-soa/model"
+	"hotel-soa/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TaxController gerencia as regras de impostos e o relatório de impostos
+type TaxController struct {
+	service service.TaxService
+}
+
+// NewTaxController cria um novo TaxController
+func NewTaxController(s service.TaxService) *TaxController {
+	return &TaxController{service: s}
+}
+
+// @Summary Cria uma regra de imposto
+// @Description Cria um imposto ou taxa, como ISS, taxa de turismo ou taxa de serviço: PERCENT sobre o preço das noites ou FIXED por noite (PER_NIGHT) ou por estadia (PER_STAY). inclusive indica que o imposto já está no preço do quarto (só PERCENT); os demais somam ao total da reserva. Vale para as reservas criadas depois
+// @Tags tax-rules
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param rule body model.TaxRuleRequest true "Regra de imposto"
+// @Success 201 {object} model.TaxRule
+// @Failure 400 {object} model.Problem
+// @Failure 401 {object} model.Problem
+// @Failure 403 {object} model.Problem
+// @Failure 409 {object} model.Problem
+// @Failure 500 {object} model.Problem
+// @Router /tax-rules [post]
+func (tc *TaxController) Create(c *gin.Context) {
+	var req model.TaxRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeBindError(c, err)
+		return
+	}
+
+	rule := req.TaxRule()
+	if err := rule.Validate(); err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	id, err := tc.service.Create(*rule)
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	rule.ID = id
+	c.JSON(http.StatusCreated, rule)
+}
+
+// @Summary Atualiza uma regra de imposto
+// @Description Atualiza uma regra pelo ID. A mudança vale para as reservas criadas ou com datas alteradas depois; os impostos já calculados não mudam
+// @Tags tax-rules
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path string true "ID da Regra (UUID)"
+// @Param rule body model.TaxRuleRequest true "Regra de imposto atualizada"
+// @Success 200 {object} model.TaxRule
+// @Failure 400 {object} model.Problem
+// @Failure 401 {object} model.Problem
+// @Failure 403 {object} model.Problem
+// @Failure 404 {object} model.Problem
+// @Failure 409 {object} model.Problem
+// @Failure 500 {object} model.Problem
+// @Router /tax-rules/{id} [put]
+func (tc *TaxController) Update(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		writeProblem(c, errInvalidID)
+		return
+	}
+
+	var req model.TaxRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeBindError(c, err)
+		return
+	}
+
+	req.ID = id
+	rule := req.TaxRule()
+	if err := rule.Validate(); err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	if err := tc.service.Update(*rule); err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, rule)
+}
+
+// @Summary Deleta uma regra de imposto
+// @Description Deleta uma regra pelo ID. Os impostos já calculados nas reservas guardam uma cópia da regra e continuam cobrados; para deixar de cobrar sem apagar, use active false
+// @Tags tax-rules
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path string true "ID da Regra (UUID)"
+// @Success 204
+// @Failure 400 {object} model.Problem
+// @Failure 401 {object} model.Problem
+// @Failure 403 {object} model.Problem
+// @Failure 404 {object} model.Problem
+// @Failure 500 {object} model.Problem
+// @Router /tax-rules/{id} [delete]
+func (tc *TaxController) Delete(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		writeProblem(c, errInvalidID)
+		return
+	}
+
+	if err := tc.service.Delete(id); err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Busca regra de imposto pelo ID
+// @Description Retorna uma regra de imposto pelo seu ID
+// @Tags tax-rules
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path string true "ID da Regra (UUID)"
+// @Success 200 {object} model.TaxRule
+// @Failure 400 {object} model.Problem
+// @Failure 401 {object} model.Problem
+// @Failure 403 {object} model.Problem
+// @Failure 404 {object} model.Problem
+// @Router /tax-rules/{id} [get]
+func (tc *TaxController) GetByID(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		writeProblem(c, errInvalidID)
+		return
+	}
+
+	rule, err := tc.service.GetByID(id)
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, rule)
+}
+
+// @Summary Lista todas as regras de imposto
+// @Description Retorna todas as regras de imposto cadastradas, ordenadas por código
+// @Tags tax-rules
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Success 200 {array} model.TaxRule
+// @Success 204 "No Content"
+// @Failure 401 {object} model.Problem
+// @Failure 403 {object} model.Problem
+// @Failure 500 {object} model.Problem
+// @Router /tax-rules [get]
+func (tc *TaxController) GetAll(c *gin.Context) {
+	rules, err := tc.service.GetAll()
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	if len(rules) == 0 {
+		c.Status(http.StatusNoContent)
+		return
+	}
+
+	c.JSON(http.StatusOK, rules)
+}
+
+// @Summary Relatório de impostos do período
+// @Description Soma, por código, os impostos das noites entre from e to (inclusive) cuja diária o night audit já lançou. group_by day ou month separa os totais por período. total soma os impostos cobrados à parte; total_inclusive os que já estão na receita de diárias. Exige reports:read
+// @Tags reports
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param from query string true "Primeira noite (YYYY-MM-DD)"
+// @Param to query string true "Última noite (YYYY-MM-DD)"
+// @Param group_by query string false "Agrupamento: day ou month"
+// @Success 200 {object} model.TaxReport
+// @Failure 400 {object} model.Problem
+// @Failure 401 {object} model.Problem
+// @Failure 403 {object} model.Problem
+// @Failure 500 {object} model.Problem
+// @Router /reports/taxes [get]
+func (tc *TaxController) Report(c *gin.Context) {
+	report, err := tc.service.Report(c.Query("from"), c.Query("to"), c.Query("group_by"))
+	if err != nil {
+		writeProblem(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,96 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"hotel-soa/dao"
+	"hotel-soa/gateway"
+	"hotel-soa/model"
+	"hotel-soa/service"
+)
+
+func TestTaxEndpoints(t *testing.T) {
+	repos := dao.NewMemoryRepositories()
+	roomID, err := repos.Rooms.InsertRoom(model.Room{Number: 101, Type: "STANDARD", Capacity: 2, PricePerNight: model.NewMoney(10500), Status: "ATIVO"}, testActor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tc := NewTaxController(service.NewTaxService(repos.Taxes))
+	rc := NewReservationController(service.NewReservationService(repos.Reservations, repos.Rooms, repos.RatePlans, repos.Guests, repos.Policies, repos.Taxes, repos.Folios, repos.Holds, gateway.NewStubGateway(), 30))
+	fc := NewFolioController(service.NewFolioService(repos.Folios, repos.Reservations, gateway.NewStubGateway()))
+	r := newTestRouter()
+	r.POST("/tax-rules", tc.Create)
+	r.PUT("/tax-rules/:id", tc.Update)
+	r.DELETE("/tax-rules/:id", tc.Delete)
+	r.GET("/tax-rules/:id", tc.GetByID)
+	r.GET("/tax-rules", tc.GetAll)
+	r.GET("/reports/taxes", tc.Report)
+	r.POST("/reservation", rc.Create)
+	r.GET("/reservation/:id/folio", fc.Get)
+
+	if w := performRequest(r, http.MethodGet, "/tax-rules", ""); w.Code != http.StatusNoContent {
+		t.Fatalf("expected 204 without rules, got %d: %s", w.Code, w.Body)
+	}
+	w := performRequest(r, http.MethodPost, "/tax-rules", `{"code":"ISS","name":"ISS","percent":5,"inclusive":true}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body)
+	}
+	var iss model.TaxRule
+	decodeBody(t, w, &iss)
+	if iss.ID == "" || iss.Calculation != model.TaxPercent || iss.Basis != model.TaxPerNight || !iss.Active {
+		t.Fatalf("expected the defaults to be filled, got %+v", iss)
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		status int
+	}{
+		{"tourism tax", http.MethodPost, "/tax-rules", `{"code":"TOUR","name":"Turismo","calculation":"FIXED","amount":5,"basis":"PER_STAY"}`, http.StatusCreated},
+		{"duplicate code", http.MethodPost, "/tax-rules", `{"code":"ISS","name":"Outro","percent":2}`, http.StatusConflict},
+		{"fixed inclusive", http.MethodPost, "/tax-rules", `{"code":"X","name":"X","calculation":"FIXED","amount":5,"inclusive":true}`, http.StatusBadRequest},
+		{"update", http.MethodPut, "/tax-rules/" + iss.ID, `{"code":"ISS","name":"Imposto Sobre Serviços","percent":5,"inclusive":true}`, http.StatusOK},
+		{"update unknown", http.MethodPut, "/tax-rules/00000000-0000-0000-0000-000000000000", `{"code":"Y","name":"Y","percent":1}`, http.StatusNotFound},
+		{"get", http.MethodGet, "/tax-rules/" + iss.ID, "", http.StatusOK},
+		{"report without dates", http.MethodGet, "/reports/taxes", "", http.StatusBadRequest},
+		{"report", http.MethodGet, "/reports/taxes?from=2026-01-01&to=2026-01-31&group_by=month", "", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if w := performRequest(r, tt.method, tt.path, tt.body); w.Code != tt.status {
+				t.Fatalf("expected %d, got %d: %s", tt.status, w.Code, w.Body)
+			}
+		})
+	}
+
+	// 105,00 por noite já com o ISS, mais a taxa de turismo à parte
+	checkin := time.Now().AddDate(0, 1, 0)
+	w = performRequest(r, http.MethodPost, "/reservation", fmt.Sprintf(`{"room_id":%q,"guest_name":"Ana","checkin_expected":%q,"checkout_expected":%q,"status":"CREATED"}`,
+		roomID, checkin.Format("2006-01-02"), checkin.AddDate(0, 0, 2).Format("2006-01-02")))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body)
+	}
+	var res model.Reservation
+	decodeBody(t, w, &res)
+	if res.TotalAmount.Minor() != 21500 || len(res.Taxes) != 3 {
+		t.Fatalf("expected a total of 215.00 with 3 tax lines, got %s %+v", res.TotalAmount, res.Taxes)
+	}
+	w = performRequest(r, http.MethodGet, "/reservation/"+res.ID+"/folio", "")
+	var folio model.Folio
+	decodeBody(t, w, &folio)
+	if len(folio.Taxes) != 2 || folio.Taxes[0].Code != "ISS" || folio.Taxes[0].Amount.Minor() != 1000 || folio.Taxes[1].Amount.Minor() != 500 {
+		t.Fatalf("expected ISS 10.00 and TOUR 5.00 in the folio, got %+v", folio.Taxes)
+	}
+
+	if w := performRequest(r, http.MethodDelete, "/tax-rules/"+iss.ID, ""); w.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d: %s", w.Code, w.Body)
+	}
+	if w := performRequest(r, http.MethodGet, "/tax-rules/"+iss.ID, ""); w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 after delete, got %d", w.Code)
+	}
+}
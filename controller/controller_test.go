@@ -0,0 +1,45 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"hotel-soa/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+// testActor é o autor registrado no audit_log pelas escritas dos testes
+var testActor = model.Actor{Username: "test"}
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// newTestRouter cria um router em que toda requisição já chega autenticada como admin,
+// como se tivesse passado por RequireAuth
+func newTestRouter() *gin.Engine {
+	r := gin.New()
+	r.Use(RequestID(), withPrincipal(principalWithRole(model.RoleAdmin)))
+	return r
+}
+
+// performRequest envia uma requisição JSON para o router e devolve a resposta gravada
+func performRequest(r http.Handler, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+// decodeBody interpreta o corpo JSON da resposta em out
+func decodeBody(t *testing.T, w *httptest.ResponseRecorder, out any) {
+	t.Helper()
+	if err := json.Unmarshal(w.Body.Bytes(), out); err != nil {
+		t.Fatalf("invalid JSON body %q: %v", w.Body.String(), err)
+	}
+}
@@ -0,0 +1,113 @@
+package dao
+
+import (
+	"database/sql"
+	"encoding/json"
+	"hotel-soa/model"
+	"reflect"
+	"time"
+)
+
+type postgresAuditRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresAuditRepository cria um AuditRepository apoiado no Postgres
+func NewPostgresAuditRepository(conn *sql.DB) AuditRepository {
+	return &postgresAuditRepository{db: conn}
+}
+
+func (r *postgresAuditRepository) GetAuditLog(entity, entityID string) ([]model.AuditEntry, error) {
+	rows, err := r.db.Query(`SELECT id, entity, entity_id, action, actor_id, actor, request_id, at, before, after, changes
+		FROM audit_log WHERE entity = $1 AND entity_id = $2 ORDER BY id;`, entity, entityID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []model.AuditEntry
+	for rows.Next() {
+		var entry model.AuditEntry
+		var before, after, changes []byte
+		err := rows.Scan(&entry.ID, &entry.Entity, &entry.EntityID, &entry.Action, &entry.ActorID,
+			&entry.Actor, &entry.RequestID, &entry.At, &before, &after, &changes)
+		if err != nil {
+			return nil, err
+		}
+		entry.Before, entry.After = before, after
+		if err := json.Unmarshal(changes, &entry.Changes); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+// insertAudit grava a entrada na mesma transação da alteração, então as duas são
+// confirmadas ou desfeitas juntas
+func insertAudit(tx *sql.Tx, entry model.AuditEntry) error {
+	changes, err := json.Marshal(entry.Changes)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(`INSERT INTO audit_log
+		(entity, entity_id, action, actor_id, actor, request_id, at, before, after, changes)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8::jsonb, $9::jsonb, $10::jsonb);`,
+		entry.Entity, entry.EntityID, entry.Action, entry.ActorID, entry.Actor, entry.RequestID, entry.At,
+		nullJSON(entry.Before), nullJSON(entry.After), string(changes))
+	return err
+}
+
+func nullJSON(raw json.RawMessage) sql.NullString {
+	return sql.NullString{String: string(raw), Valid: raw != nil}
+}
+
+// newAuditEntry serializa os estados antes e depois (nil quando não existem) e calcula
+// o diff campo a campo
+func newAuditEntry(entity, entityID, action string, actor model.Actor, before, after any) (model.AuditEntry, error) {
+	entry := model.AuditEntry{
+		Entity:    entity,
+		EntityID:  entityID,
+		Action:    action,
+		ActorID:   actor.UserID,
+		Actor:     actor.Username,
+		RequestID: actor.RequestID,
+		At:        time.Now().UTC(),
+	}
+
+	var err error
+	var beforeFields, afterFields map[string]any
+	if entry.Before, beforeFields, err = snapshot(before); err != nil {
+		return model.AuditEntry{}, err
+	}
+	if entry.After, afterFields, err = snapshot(after); err != nil {
+		return model.AuditEntry{}, err
+	}
+
+	entry.Changes = make(map[string]model.FieldChange)
+	for field, from := range beforeFields {
+		if to := afterFields[field]; !reflect.DeepEqual(from, to) {
+			entry.Changes[field] = model.FieldChange{From: from, To: to}
+		}
+	}
+	for field, to := range afterFields {
+		if _, ok := beforeFields[field]; !ok {
+			entry.Changes[field] = model.FieldChange{To: to}
+		}
+	}
+	return entry, nil
+}
+
+// snapshot devolve o JSON do estado e seus campos de primeiro nível; um ponteiro nil
+// vira estado ausente
+func snapshot(state any) (json.RawMessage, map[string]any, error) {
+	raw, err := json.Marshal(state)
+	if err != nil || string(raw) == "null" {
+		return nil, nil, err
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, nil, err
+	}
+	return raw, fields, nil
+}
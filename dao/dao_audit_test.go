@@ -0,0 +1,125 @@
+package dao
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"hotel-soa/db/dbtest"
+	"hotel-soa/migrations"
+	"hotel-soa/model"
+)
+
+func TestNewAuditEntry(t *testing.T) {
+	before := model.Room{ID: "r1", Number: 101, Type: "STANDARD", Capacity: 2, PricePerNight: model.NewMoney(10000), Status: "ATIVO"}
+	after := before
+	after.Capacity, after.Status = 3, "INATIVO"
+	actor := model.Actor{UserID: "u1", Username: "ana", RequestID: "req-1"}
+
+	tests := []struct {
+		name          string
+		before, after any
+		changes       map[string]model.FieldChange
+	}{
+		{"create", nil, &before, map[string]model.FieldChange{
+			"id": {To: "r1"}, "number": {To: 101.0}, "type": {To: "STANDARD"}, "capacity": {To: 2.0},
+			"price_per_night": {To: 100.0}, "status": {To: "ATIVO"},
+		}},
+		{"update", &before, &after, map[string]model.FieldChange{
+			"capacity": {From: 2.0, To: 3.0}, "status": {From: "ATIVO", To: "INATIVO"},
+		}},
+		{"no change", &before, &before, map[string]model.FieldChange{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry, err := newAuditEntry(model.AuditEntityRoom, "r1", model.AuditActionUpdate, actor, tt.before, tt.after)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(entry.Changes, tt.changes) {
+				t.Fatalf("expected changes %v, got %v", tt.changes, entry.Changes)
+			}
+			if (tt.before == nil) != (entry.Before == nil) || entry.ActorID != "u1" || entry.Actor != "ana" || entry.RequestID != "req-1" {
+				t.Fatalf("unexpected entry %+v", entry)
+			}
+		})
+	}
+}
+
+func TestAuditLog(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, repos Repositories) {
+		actor := model.Actor{UserID: "u1", Username: "ana", RequestID: "req-1"}
+		room := model.Room{Number: 101, Type: "STANDARD", Capacity: 2, PricePerNight: model.NewMoney(10000), Status: "ATIVO"}
+		id, err := repos.Rooms.InsertRoom(room, actor)
+		if err != nil {
+			t.Fatal(err)
+		}
+		room.ID, room.Capacity = id, 3
+		if err := repos.Rooms.UpdateRoom(room, model.Actor{Username: "bruno"}); err != nil {
+			t.Fatal(err)
+		}
+
+		res := newTestReservation(t, repos, id, "2026-06-10", "2026-06-12")
+		res.ID, err = repos.Reservations.InsertReservation(res, actor)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// a escrita recusada por conflito não deixa rastro
+		clash := insertTestReservation(t, repos, id, "2026-06-20", "2026-06-22")
+		clash.CheckinExpected = "2026-06-11"
+		var conflict *model.ReservationConflictError
+		if err := repos.Reservations.UpdateReservation(clash, actor); !errors.As(err, &conflict) {
+			t.Fatalf("expected a conflict, got %v", err)
+		}
+		if err := repos.Reservations.PurgeReservation(res.ID, actor); err != nil {
+			t.Fatal(err)
+		}
+
+		entries, err := repos.Audit.GetAuditLog(model.AuditEntityRoom, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) != 2 || entries[0].Action != model.AuditActionCreate || entries[1].Action != model.AuditActionUpdate {
+			t.Fatalf("expected create and update, got %+v", entries)
+		}
+		if entries[0].Actor != "ana" || entries[0].RequestID != "req-1" || entries[0].Before != nil || entries[1].Actor != "bruno" {
+			t.Fatalf("unexpected actors %+v", entries)
+		}
+		if change := entries[1].Changes["capacity"]; len(entries[1].Changes) != 1 || change.From != 2.0 || change.To != 3.0 {
+			t.Fatalf("expected only the capacity change, got %+v", entries[1].Changes)
+		}
+
+		entries, err = repos.Audit.GetAuditLog(model.AuditEntityReservation, res.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) != 2 || entries[0].Action != model.AuditActionCreate || entries[1].Action != model.AuditActionPurge || entries[1].After != nil {
+			t.Fatalf("expected create and delete, got %+v", entries)
+		}
+		if entries, err := repos.Audit.GetAuditLog(model.AuditEntityReservation, clash.ID); err != nil || len(entries) != 1 {
+			t.Fatalf("expected only the create entry for the refused update, got %+v, %v", entries, err)
+		}
+	})
+}
+
+// TestAuditLogAppendOnly confere os triggers que recusam UPDATE, DELETE e TRUNCATE
+func TestAuditLogAppendOnly(t *testing.T) {
+	conn := dbtest.Open(t)
+	migrator, err := migrations.NewMigrator(conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := migrator.Up(); err != nil {
+		t.Fatal(err)
+	}
+	repos := NewPostgresRepositories(conn)
+	if _, err := repos.Rooms.InsertRoom(model.Room{Number: 101, Type: "STANDARD", Capacity: 2, PricePerNight: model.NewMoney(10000), Status: "ATIVO"}, testActor); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, stmt := range []string{"UPDATE audit_log SET actor = 'someone';", "DELETE FROM audit_log;", "TRUNCATE audit_log;"} {
+		if _, err := conn.Exec(stmt); err == nil {
+			t.Fatalf("expected %q to be refused", stmt)
+		}
+	}
+}
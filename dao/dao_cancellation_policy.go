@@ -0,0 +1,88 @@
+package dao
+
+import (
+	"database/sql"
+	"errors"
+	"hotel-soa/model"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// ErrCancellationPolicyInUse indica que a política ainda está ligada a um quarto, plano tarifário ou reserva
+var ErrCancellationPolicyInUse = errors.New("cancellation policy is still used by a room, rate plan or reservation")
+
+type postgresCancellationPolicyRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresCancellationPolicyRepository cria um CancellationPolicyRepository apoiado no Postgres
+func NewPostgresCancellationPolicyRepository(conn *sql.DB) CancellationPolicyRepository {
+	return &postgresCancellationPolicyRepository{db: conn}
+}
+
+const cancellationPolicyColumns = `id, name, free_until_days, penalty_type, penalty_value, non_refundable`
+
+func (r *postgresCancellationPolicyRepository) InsertCancellationPolicy(policy model.CancellationPolicy) (string, error) {
+	id := uuid.NewString()
+	query := `INSERT INTO cancellation_policies (` + cancellationPolicyColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6);`
+	_, err := r.db.Exec(query, id, policy.Name, policy.FreeUntilDays, policy.PenaltyType, policy.PenaltyValue, policy.NonRefundable)
+	if err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+func (r *postgresCancellationPolicyRepository) UpdateCancellationPolicy(policy model.CancellationPolicy) error {
+	query := `UPDATE cancellation_policies
+		SET name = $1, free_until_days = $2, penalty_type = $3, penalty_value = $4, non_refundable = $5
+		WHERE id = $6;`
+	_, err := r.db.Exec(query, policy.Name, policy.FreeUntilDays, policy.PenaltyType, policy.PenaltyValue, policy.NonRefundable, policy.ID)
+	return err
+}
+
+func (r *postgresCancellationPolicyRepository) DeleteCancellationPolicy(id string) error {
+	_, err := r.db.Exec("DELETE FROM cancellation_policies WHERE id = $1;", id)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+		return ErrCancellationPolicyInUse
+	}
+	return err
+}
+
+func (r *postgresCancellationPolicyRepository) GetAllCancellationPolicies() ([]model.CancellationPolicy, error) {
+	rows, err := r.db.Query(`SELECT ` + cancellationPolicyColumns + ` FROM cancellation_policies ORDER BY name;`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var policies []model.CancellationPolicy
+	for rows.Next() {
+		policy, err := scanCancellationPolicy(rows)
+		if err != nil {
+			return nil, err
+		}
+		policies = append(policies, policy)
+	}
+	return policies, rows.Err()
+}
+
+func (r *postgresCancellationPolicyRepository) GetCancellationPolicyByID(id string) (model.CancellationPolicy, error) {
+	query := `SELECT ` + cancellationPolicyColumns + ` FROM cancellation_policies WHERE id = $1;`
+	policy, err := scanCancellationPolicy(r.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return model.CancellationPolicy{}, nil
+		}
+		return model.CancellationPolicy{}, err
+	}
+	return policy, nil
+}
+
+func scanCancellationPolicy(row rowScanner) (model.CancellationPolicy, error) {
+	var policy model.CancellationPolicy
+	err := row.Scan(&policy.ID, &policy.Name, &policy.FreeUntilDays, &policy.PenaltyType, &policy.PenaltyValue, &policy.NonRefundable)
+	return policy, err
+}
@@ -0,0 +1,65 @@
+package dao
+
+import (
+	"errors"
+	"testing"
+
+	"hotel-soa/model"
+)
+
+func TestCancellationPolicyRepository(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, repos Repositories) {
+		policy := model.CancellationPolicy{Name: "Flexible", FreeUntilDays: 2, PenaltyType: model.PenaltyNights, PenaltyValue: 1}
+		id, err := repos.Policies.InsertCancellationPolicy(policy)
+		if err != nil {
+			t.Fatal(err)
+		}
+		policy.ID = id
+
+		policy.FreeUntilDays, policy.NonRefundable = 7, true
+		if err := repos.Policies.UpdateCancellationPolicy(policy); err != nil {
+			t.Fatal(err)
+		}
+		if got, err := repos.Policies.GetCancellationPolicyByID(id); err != nil || got != policy {
+			t.Fatalf("expected %+v, got %+v, %v", policy, got, err)
+		}
+		if all, err := repos.Policies.GetAllCancellationPolicies(); err != nil || len(all) != 1 || all[0] != policy {
+			t.Fatalf("expected only %+v, got %+v, %v", policy, all, err)
+		}
+
+		// a política em uso por um quarto não pode ser removida
+		room := insertTestRoom(t, repos, "STANDARD")
+		room.CancellationPolicyID = id
+		if err := repos.Rooms.UpdateRoom(room, testActor); err != nil {
+			t.Fatal(err)
+		}
+		if err := repos.Policies.DeleteCancellationPolicy(id); !errors.Is(err, ErrCancellationPolicyInUse) {
+			t.Fatalf("expected ErrCancellationPolicyInUse, got %v", err)
+		}
+
+		// nem a fixada numa reserva
+		res := newTestReservation(t, repos, room.ID, "2026-06-10", "2026-06-12")
+		res.CancellationPolicyID = id
+		if _, err := repos.Reservations.InsertReservation(res, testActor); err != nil {
+			t.Fatal(err)
+		}
+		room.CancellationPolicyID = ""
+		if err := repos.Rooms.UpdateRoom(room, testActor); err != nil {
+			t.Fatal(err)
+		}
+		if err := repos.Policies.DeleteCancellationPolicy(id); !errors.Is(err, ErrCancellationPolicyInUse) {
+			t.Fatalf("expected ErrCancellationPolicyInUse for a reservation, got %v", err)
+		}
+
+		unused, err := repos.Policies.InsertCancellationPolicy(model.CancellationPolicy{Name: "Unused", PenaltyType: model.PenaltyPercent})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := repos.Policies.DeleteCancellationPolicy(unused); err != nil {
+			t.Fatal(err)
+		}
+		if got, err := repos.Policies.GetCancellationPolicyByID(unused); err != nil || got.ID != "" {
+			t.Fatalf("expected an empty policy after delete, got %+v, %v", got, err)
+		}
+	})
+}
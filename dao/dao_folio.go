@@ -0,0 +1,275 @@
+package dao
+
+import (
+	"database/sql"
+	"errors"
+	"hotel-soa/model"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrFolioClosed indica um lançamento numa reserva que já terminou: CHECKED_OUT,
+	// CANCELED ou NO_SHOW
+	ErrFolioClosed = errors.New("charges can only be posted to CREATED, CONFIRMED or CHECKED_IN reservations")
+	// ErrDiscountExceedsTotal indica um desconto maior que o total da conta
+	ErrDiscountExceedsTotal = errors.New("discount is larger than the folio total")
+	// ErrRefundExceedsPaid indica um estorno maior que o valor pago
+	ErrRefundExceedsPaid = errors.New("refund is larger than the amount paid")
+)
+
+type postgresFolioRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresFolioRepository cria um FolioRepository apoiado no Postgres
+func NewPostgresFolioRepository(conn *sql.DB) FolioRepository {
+	return &postgresFolioRepository{db: conn}
+}
+
+func (r *postgresFolioRepository) GetFolio(reservationID string) (model.Folio, error) {
+	folio := model.Folio{ReservationID: reservationID}
+	err := r.db.QueryRow("SELECT status, COALESCE(group_id, '') FROM reservations WHERE id = $1;", reservationID).Scan(&folio.Status, &folio.GroupID)
+	if err == sql.ErrNoRows {
+		return model.Folio{}, nil
+	}
+	if err != nil {
+		return model.Folio{}, err
+	}
+
+	// 1. e 2. Noites e impostos cobrados à parte
+	if folio.Charges, err = getStayCharges(r.db, "SELECT $1::char(36)", reservationID); err != nil {
+		return model.Folio{}, err
+	}
+
+	// 3. Resumo dos impostos, inclusive os que já estão no preço das noites
+	if folio.Taxes, err = getTaxSummary(r.db, reservationID); err != nil {
+		return model.Folio{}, err
+	}
+
+	// 4. Demais lançamentos, na ordem em que foram feitos
+	rows, err := r.db.Query(`SELECT id, type, description, business_date, amount, posted_at, posted_by
+		FROM folio_charges WHERE reservation_id = $1 ORDER BY posted_at, id;`, reservationID)
+	if err != nil {
+		return model.Folio{}, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		charge := model.FolioCharge{ReservationID: reservationID}
+		var date, postedAt time.Time
+		if err := rows.Scan(&charge.ID, &charge.Type, &charge.Description, &date, &charge.Amount, &postedAt, &charge.PostedBy); err != nil {
+			return model.Folio{}, err
+		}
+		charge.Date, charge.PostedAt = date.Format("2006-01-02"), &postedAt
+		folio.Charges = append(folio.Charges, charge)
+	}
+	if err := rows.Err(); err != nil {
+		return model.Folio{}, err
+	}
+
+	// 5. Pagamentos e estornos
+	rows, err = r.db.Query(`SELECT id, type, method, amount, reference, COALESCE(authorization_id, ''), posted_at, posted_by
+		FROM folio_payments WHERE reservation_id = $1 ORDER BY posted_at, id;`, reservationID)
+	if err != nil {
+		return model.Folio{}, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		payment := model.Payment{ReservationID: reservationID}
+		if err := rows.Scan(&payment.ID, &payment.Type, &payment.Method, &payment.Amount, &payment.Reference,
+			&payment.AuthorizationID, &payment.PostedAt, &payment.PostedBy); err != nil {
+			return model.Folio{}, err
+		}
+		folio.Payments = append(folio.Payments, payment)
+	}
+	if err := rows.Err(); err != nil {
+		return model.Folio{}, err
+	}
+
+	// 6. Pré-autorizações de depósito
+	folio.Authorizations, err = getAuthorizations(r.db, reservationID)
+	return folio, err
+}
+
+// PostCharge trava a reserva, grava o lançamento e recalcula total_amount na mesma transação
+func (r *postgresFolioRepository) PostCharge(charge model.FolioCharge, actor model.Actor) (string, error) {
+	charge.ID = uuid.NewString()
+	err := withTx(r.db, func(tx *sql.Tx) error {
+		before, err := lockReservation(tx, charge.ReservationID)
+		if err != nil {
+			return err
+		}
+		if before == nil || !folioOpen(before.Status) {
+			return ErrFolioClosed
+		}
+		_, err = tx.Exec(`INSERT INTO folio_charges (id, reservation_id, type, description, business_date, amount, posted_at, posted_by)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8);`,
+			charge.ID, charge.ReservationID, charge.Type, charge.Description, charge.Date, charge.Amount, charge.PostedAt, charge.PostedBy)
+		if err != nil {
+			return err
+		}
+		total, err := refreshTotal(tx, charge.ReservationID)
+		if err != nil {
+			return err
+		}
+		if total.IsNegative() {
+			return ErrDiscountExceedsTotal
+		}
+		return auditFolio(tx, charge.ReservationID, model.AuditActionPostCharge, actor, charge)
+	})
+	if err != nil {
+		return "", err
+	}
+	return charge.ID, nil
+}
+
+// PostPayment trava a reserva para que estornos concorrentes não passem do valor pago
+func (r *postgresFolioRepository) PostPayment(payment model.Payment, actor model.Actor) (string, error) {
+	payment.ID = uuid.NewString()
+	err := withTx(r.db, func(tx *sql.Tx) error {
+		before, err := lockReservation(tx, payment.ReservationID)
+		if err != nil {
+			return err
+		}
+		if before == nil {
+			return ErrReservationStatusChanged
+		}
+		return insertPayment(tx, payment, actor)
+	})
+	if err != nil {
+		return "", err
+	}
+	return payment.ID, nil
+}
+
+// ---------------- HELPERS ----------------
+
+// insertPayment grava o pagamento ou estorno e o registra na trilha da reserva; o estorno
+// não pode passar do que foi pago. Exige a reserva travada
+func insertPayment(tx *sql.Tx, payment model.Payment, actor model.Actor) error {
+	if payment.Type == model.PaymentTypeRefund {
+		var paid model.Money
+		err := tx.QueryRow(`SELECT COALESCE(SUM(CASE WHEN type = 'PAYMENT' THEN amount ELSE -amount END), 0)
+			FROM folio_payments WHERE reservation_id = $1;`, payment.ReservationID).Scan(&paid)
+		if err != nil {
+			return err
+		}
+		if payment.Amount.Cmp(paid) > 0 {
+			return ErrRefundExceedsPaid
+		}
+	}
+	_, err := tx.Exec(`INSERT INTO folio_payments (id, reservation_id, type, method, amount, reference, authorization_id, posted_at, posted_by)
+		VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''), $8, $9);`,
+		payment.ID, payment.ReservationID, payment.Type, payment.Method, payment.Amount, payment.Reference,
+		payment.AuthorizationID, payment.PostedAt, payment.PostedBy)
+	if err != nil {
+		return err
+	}
+	return auditFolio(tx, payment.ReservationID, model.AuditActionPostPayment, actor, payment)
+}
+
+// roomChargeDescription descreve as linhas ROOM da conta
+const roomChargeDescription = "Room night"
+
+// getStayCharges retorna as linhas ROOM das noites e as TAX dos impostos cobrados à
+// parte das reservas listadas por reservations, uma subquery com o argumento arg, com o
+// lançamento do night audit quando a noite já foi fechada
+func getStayCharges(q rowsQueryer, reservations string, arg any) ([]model.FolioCharge, error) {
+	var charges []model.FolioCharge
+	rows, err := q.Query(`SELECT n.reservation_id, n.night, n.price, rc.posted_at, COALESCE(na.closed_by, '')
+		FROM reservation_nights n
+		LEFT JOIN room_charges rc ON rc.reservation_id = n.reservation_id AND rc.business_date = n.night
+		LEFT JOIN night_audits na ON na.business_date = rc.business_date
+		WHERE n.reservation_id IN (`+reservations+`)
+		ORDER BY n.night, n.reservation_id;`, arg)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		charge := model.FolioCharge{Type: model.FolioRoom, Description: roomChargeDescription}
+		var night time.Time
+		var postedAt sql.NullTime
+		if err := rows.Scan(&charge.ReservationID, &night, &charge.Amount, &postedAt, &charge.PostedBy); err != nil {
+			return nil, err
+		}
+		charge.Date = night.Format("2006-01-02")
+		if postedAt.Valid {
+			charge.PostedAt = &postedAt.Time
+		}
+		charges = append(charges, charge)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = q.Query(`SELECT t.reservation_id, t.name, t.night, t.amount, rc.posted_at, COALESCE(na.closed_by, '')
+		FROM reservation_taxes t
+		LEFT JOIN room_charges rc ON rc.reservation_id = t.reservation_id AND rc.business_date = t.night
+		LEFT JOIN night_audits na ON na.business_date = rc.business_date
+		WHERE t.reservation_id IN (`+reservations+`) AND NOT t.inclusive
+		ORDER BY t.night, t.reservation_id, t.code;`, arg)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		charge := model.FolioCharge{Type: model.FolioTax}
+		var night time.Time
+		var postedAt sql.NullTime
+		if err := rows.Scan(&charge.ReservationID, &charge.Description, &night, &charge.Amount, &postedAt, &charge.PostedBy); err != nil {
+			return nil, err
+		}
+		charge.Date = night.Format("2006-01-02")
+		if postedAt.Valid {
+			charge.PostedAt = &postedAt.Time
+		}
+		charges = append(charges, charge)
+	}
+	return charges, rows.Err()
+}
+
+// refreshTotal recalcula total_amount como a soma das noites, dos impostos cobrados à
+// parte e dos lançamentos da conta
+func refreshTotal(tx *sql.Tx, reservationID string) (model.Money, error) {
+	var total model.Money
+	err := tx.QueryRow(`UPDATE reservations SET total_amount =
+			(SELECT COALESCE(SUM(price), 0) FROM reservation_nights WHERE reservation_id = $1) +
+			(SELECT COALESCE(SUM(amount), 0) FROM reservation_taxes WHERE reservation_id = $1 AND NOT inclusive) +
+			(SELECT COALESCE(SUM(amount), 0) FROM folio_charges WHERE reservation_id = $1)
+		WHERE id = $1
+		RETURNING total_amount;`, reservationID).Scan(&total)
+	return total, err
+}
+
+// requireSettled recusa a ação quando total_amount, descontados os pagamentos e somados
+// os estornos, não fecha em zero. Numa reserva de grupo, as noites e os impostos são da
+// conta master e ficam de fora
+func requireSettled(tx *sql.Tx, reservationID string) error {
+	var balance model.Money
+	err := tx.QueryRow(`SELECT r.total_amount - COALESCE(
+			(SELECT SUM(CASE WHEN p.type = 'PAYMENT' THEN p.amount ELSE -p.amount END)
+			 FROM folio_payments p WHERE p.reservation_id = r.id), 0)
+		     - CASE WHEN r.group_id IS NULL THEN 0 ELSE
+			(SELECT COALESCE(SUM(price), 0) FROM reservation_nights WHERE reservation_id = r.id) +
+			(SELECT COALESCE(SUM(amount), 0) FROM reservation_taxes WHERE reservation_id = r.id AND NOT inclusive)
+		       END
+		FROM reservations r WHERE r.id = $1;`, reservationID).Scan(&balance)
+	if err != nil {
+		return err
+	}
+	if !balance.IsZero() {
+		return &model.BalanceDueError{ReservationID: reservationID, Balance: balance}
+	}
+	return nil
+}
+
+// auditFolio registra o lançamento na trilha da reserva, como uma criação
+func auditFolio(tx *sql.Tx, reservationID, action string, actor model.Actor, entry any) error {
+	audit, err := newAuditEntry(model.AuditEntityReservation, reservationID, action, actor, nil, entry)
+	if err != nil {
+		return err
+	}
+	return insertAudit(tx, audit)
+}
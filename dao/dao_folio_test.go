@@ -0,0 +1,77 @@
+package dao
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"hotel-soa/model"
+)
+
+func TestFolioRepository(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, repos Repositories) {
+		room := insertTestRoom(t, repos, "STANDARD")
+		res := newTestReservation(t, repos, room.ID, "2026-06-10", "2026-06-12")
+		res.Nights = []model.NightlyRate{{Date: "2026-06-10", Price: model.NewMoney(10000)}, {Date: "2026-06-11", Price: model.NewMoney(10000)}}
+		id, err := repos.Reservations.InsertReservation(res, testActor)
+		if err != nil {
+			t.Fatal(err)
+		}
+		at := time.Date(2026, 6, 10, 20, 0, 0, 0, time.UTC)
+		charge := func(chargeType string, amount int64) error {
+			_, err := repos.Folios.PostCharge(model.FolioCharge{ReservationID: id, Type: chargeType, Date: "2026-06-10",
+				Amount: model.NewMoney(amount), PostedAt: &at, PostedBy: "maria"}, testActor)
+			return err
+		}
+		pay := func(paymentType string, amount int64) error {
+			_, err := repos.Folios.PostPayment(model.Payment{ReservationID: id, Type: paymentType, Method: "CARD",
+				Amount: model.NewMoney(amount), PostedAt: at, PostedBy: "maria"}, testActor)
+			return err
+		}
+
+		if err := charge(model.FolioMinibar, 3000); err != nil {
+			t.Fatal(err)
+		}
+		if err := charge(model.FolioDiscount, -2000); err != nil {
+			t.Fatal(err)
+		}
+		// o desconto não pode deixar o total negativo
+		if err := charge(model.FolioDiscount, -50000); !errors.Is(err, ErrDiscountExceedsTotal) {
+			t.Fatalf("expected ErrDiscountExceedsTotal, got %v", err)
+		}
+		if err := pay(model.PaymentTypePayment, 15000); err != nil {
+			t.Fatal(err)
+		}
+		if err := pay(model.PaymentTypeRefund, 20000); !errors.Is(err, ErrRefundExceedsPaid) {
+			t.Fatalf("expected ErrRefundExceedsPaid, got %v", err)
+		}
+		if err := pay(model.PaymentTypeRefund, 5000); err != nil {
+			t.Fatal(err)
+		}
+
+		folio, err := repos.Folios.GetFolio(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// as duas noites entram como linhas ROOM antes dos lançamentos
+		if folio.Status != "CREATED" || len(folio.Charges) != 4 || folio.Charges[0].Type != model.FolioRoom ||
+			folio.Charges[2].Type != model.FolioMinibar || len(folio.Payments) != 2 {
+			t.Fatalf("unexpected folio %+v", folio)
+		}
+		// total_amount acompanha a conta
+		if got, _ := repos.Reservations.GetReservationByID(id); !got.TotalAmount.Equal(model.NewMoney(21000)) {
+			t.Fatalf("expected a total of 210, got %v", got.TotalAmount)
+		}
+
+		// reserva encerrada não aceita lançamentos
+		if err := repos.Reservations.CancelReservation(id, at, "", model.CancellationQuote{}, testActor); err != nil {
+			t.Fatal(err)
+		}
+		if err := charge(model.FolioOther, 1000); !errors.Is(err, ErrFolioClosed) {
+			t.Fatalf("expected ErrFolioClosed, got %v", err)
+		}
+		if folio, err := repos.Folios.GetFolio("00000000-0000-0000-0000-000000000000"); err != nil || folio.ReservationID != "" {
+			t.Fatalf("expected an empty folio for an unknown reservation, got %+v, %v", folio, err)
+		}
+	})
+}
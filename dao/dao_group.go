@@ -0,0 +1,308 @@
+package dao
+
+import (
+	"database/sql"
+	"errors"
+	"hotel-soa/model"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrGroupCanceled indica que a reserva nova aponta para um grupo cancelado ou inexistente
+var ErrGroupCanceled = errors.New("group was canceled")
+
+// ErrGroupStatusChanged indica que o grupo não estava mais no status esperado quando a
+// escrita foi feita
+var ErrGroupStatusChanged = errors.New("group status changed, reload and try again")
+
+type postgresGroupRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresGroupRepository cria um GroupRepository apoiado no Postgres
+func NewPostgresGroupRepository(conn *sql.DB) GroupRepository {
+	return &postgresGroupRepository{db: conn}
+}
+
+// InsertGroup trava os quartos de cada tipo do bloco, em ordem, como InsertReservation, e
+// confere que os quartos cabem no estoque do tipo em todas as noites do bloco
+func (r *postgresGroupRepository) InsertGroup(group model.Group, actor model.Actor) (string, error) {
+	group.ID = uuid.NewString()
+	err := withTx(r.db, func(tx *sql.Tx) error {
+		blocks := append([]model.GroupBlock(nil), group.Blocks...)
+		sort.Slice(blocks, func(i, j int) bool { return blocks[i].RoomType < blocks[j].RoomType })
+		for _, block := range blocks {
+			if err := lockRoomType(tx, block.RoomType, ""); err != nil {
+				return err
+			}
+		}
+		for _, block := range blocks {
+			total, err := roomTypeTotal(tx, block.RoomType)
+			if err != nil {
+				return err
+			}
+			free, night, err := typeHeadroom(tx, block.RoomType, total, group.CheckinExpected, group.CheckoutExpected, "", "")
+			if err != nil {
+				return err
+			}
+			if free < block.Rooms {
+				return &model.RoomTypeSoldOutError{RoomType: block.RoomType, Date: night}
+			}
+		}
+
+		_, err := tx.Exec(`INSERT INTO booking_groups
+			(id, name, contact_name, contact_email, contact_phone, checkin_expected, checkout_expected, cutoff_date,
+			 status, created_at, created_by)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11);`,
+			group.ID, group.Name, group.ContactName, group.ContactEmail, group.ContactPhone,
+			group.CheckinExpected, group.CheckoutExpected, group.CutoffDate,
+			group.Status, group.CreatedAt, group.CreatedBy)
+		if err != nil {
+			return err
+		}
+		for _, block := range group.Blocks {
+			if _, err := tx.Exec("INSERT INTO group_blocks (group_id, room_type, rooms) VALUES ($1, $2, $3);",
+				group.ID, block.RoomType, block.Rooms); err != nil {
+				return err
+			}
+		}
+		return auditGroup(tx, group.ID, model.AuditActionCreate, actor, nil, &group)
+	})
+	if err != nil {
+		return "", err
+	}
+	return group.ID, nil
+}
+
+func (r *postgresGroupRepository) GetGroupByID(id string) (model.Group, error) {
+	groups, err := r.getGroups("id = $1", id)
+	if err != nil || len(groups) == 0 {
+		return model.Group{}, err
+	}
+	return groups[0], nil
+}
+
+func (r *postgresGroupRepository) GetAllGroups() ([]model.Group, error) {
+	return r.getGroups("TRUE")
+}
+
+func (r *postgresGroupRepository) GetGroupsPastCutoff(date time.Time) ([]model.Group, error) {
+	return r.getGroups("status = 'ACTIVE' AND cutoff_date < $1", date)
+}
+
+func (r *postgresGroupRepository) GetGroupReservations(id string) ([]model.Reservation, error) {
+	query := `SELECT ` + reservationColumns + ` FROM reservations
+		WHERE group_id = $1 ORDER BY checkin_expected, guest_name, id;`
+	return (&postgresReservationRepository{db: r.db}).queryReservations(query, id)
+}
+
+func (r *postgresGroupRepository) ReleaseGroup(id string, at time.Time, actor model.Actor) error {
+	return withTx(r.db, func(tx *sql.Tx) error {
+		before, err := lockGroup(tx, id)
+		if err != nil {
+			return err
+		}
+		if before == nil || before.Status != model.GroupActive {
+			return ErrGroupStatusChanged
+		}
+		if _, err := tx.Exec("UPDATE booking_groups SET status = 'RELEASED', released_at = $2 WHERE id = $1;", id, at); err != nil {
+			return err
+		}
+		group := *before
+		group.Status, group.ReleasedAt = model.GroupReleased, &at
+		return auditGroup(tx, id, model.AuditActionRelease, actor, before, &group)
+	})
+}
+
+// CancelGroup trava a linha do grupo; as reservas novas do grupo esperam o cancelamento
+// terminar e então são recusadas por groupAllotment
+func (r *postgresGroupRepository) CancelGroup(id string, at time.Time, reason string, actor model.Actor) error {
+	return withTx(r.db, func(tx *sql.Tx) error {
+		before, err := lockGroup(tx, id)
+		if err != nil {
+			return err
+		}
+		if before == nil || before.Status == model.GroupCanceled {
+			return ErrGroupStatusChanged
+		}
+		_, err = tx.Exec(`UPDATE booking_groups SET status = 'CANCELED', canceled_at = $2, canceled_by = $3,
+			cancellation_reason = $4 WHERE id = $1;`, id, at, actor.Username, reason)
+		if err != nil {
+			return err
+		}
+		group := *before
+		group.Status, group.CanceledAt, group.CanceledBy, group.CancellationReason = model.GroupCanceled, &at, actor.Username, reason
+		return auditGroup(tx, id, model.AuditActionCancel, actor, before, &group)
+	})
+}
+
+func (r *postgresGroupRepository) GetGroupFolio(id string) (model.GroupFolio, error) {
+	folio := model.GroupFolio{GroupID: id}
+	err := r.db.QueryRow("SELECT status FROM booking_groups WHERE id = $1;", id).Scan(&folio.Status)
+	if err == sql.ErrNoRows {
+		return model.GroupFolio{}, nil
+	}
+	if err != nil {
+		return model.GroupFolio{}, err
+	}
+
+	// 1. Noites e impostos cobrados à parte das reservas ativas do grupo
+	folio.Charges, err = getStayCharges(r.db, `SELECT id FROM reservations
+		WHERE group_id = $1 AND status NOT IN ('CANCELED', 'NO_SHOW')`, id)
+	if err != nil {
+		return model.GroupFolio{}, err
+	}
+
+	// 2. Pagamentos e estornos
+	rows, err := r.db.Query(`SELECT id, type, method, amount, reference, posted_at, posted_by
+		FROM group_payments WHERE group_id = $1 ORDER BY posted_at, id;`, id)
+	if err != nil {
+		return model.GroupFolio{}, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		payment := model.Payment{GroupID: id}
+		if err := rows.Scan(&payment.ID, &payment.Type, &payment.Method, &payment.Amount, &payment.Reference,
+			&payment.PostedAt, &payment.PostedBy); err != nil {
+			return model.GroupFolio{}, err
+		}
+		folio.Payments = append(folio.Payments, payment)
+	}
+	return folio, rows.Err()
+}
+
+// PostGroupPayment trava o grupo para que estornos concorrentes não passem do valor pago
+func (r *postgresGroupRepository) PostGroupPayment(payment model.Payment, actor model.Actor) (string, error) {
+	payment.ID = uuid.NewString()
+	err := withTx(r.db, func(tx *sql.Tx) error {
+		before, err := lockGroup(tx, payment.GroupID)
+		if err != nil {
+			return err
+		}
+		if before == nil {
+			return ErrGroupStatusChanged
+		}
+		if payment.Type == model.PaymentTypeRefund {
+			var paid model.Money
+			err := tx.QueryRow(`SELECT COALESCE(SUM(CASE WHEN type = 'PAYMENT' THEN amount ELSE -amount END), 0)
+				FROM group_payments WHERE group_id = $1;`, payment.GroupID).Scan(&paid)
+			if err != nil {
+				return err
+			}
+			if payment.Amount.Cmp(paid) > 0 {
+				return ErrRefundExceedsPaid
+			}
+		}
+		_, err = tx.Exec(`INSERT INTO group_payments (id, group_id, type, method, amount, reference, posted_at, posted_by)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8);`,
+			payment.ID, payment.GroupID, payment.Type, payment.Method, payment.Amount, payment.Reference,
+			payment.PostedAt, payment.PostedBy)
+		if err != nil {
+			return err
+		}
+		return auditGroup(tx, payment.GroupID, model.AuditActionPostPayment, actor, nil, payment)
+	})
+	if err != nil {
+		return "", err
+	}
+	return payment.ID, nil
+}
+
+// getGroups lista os grupos que atendem a where, por data de chegada, com os blocos
+func (r *postgresGroupRepository) getGroups(where string, args ...any) ([]model.Group, error) {
+	rows, err := r.db.Query(`SELECT `+groupColumns+` FROM booking_groups WHERE `+where+`
+		ORDER BY checkin_expected, name, id;`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	groups := []model.Group{}
+	index := map[string]int{}
+	for rows.Next() {
+		group, err := scanGroup(rows)
+		if err != nil {
+			return nil, err
+		}
+		index[group.ID] = len(groups)
+		groups = append(groups, group)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// picked_up conta as reservas ativas do grupo no tipo
+	rows, err = r.db.Query(`SELECT b.group_id, b.room_type, b.rooms,
+			(SELECT COUNT(*) FROM reservations p
+			  WHERE p.group_id = b.group_id AND p.room_type = b.room_type AND p.status NOT IN ('CANCELED', 'NO_SHOW'))
+		FROM group_blocks b
+		WHERE b.group_id IN (SELECT id FROM booking_groups WHERE `+where+`)
+		ORDER BY b.group_id, b.room_type;`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var groupID string
+		var block model.GroupBlock
+		if err := rows.Scan(&groupID, &block.RoomType, &block.Rooms, &block.PickedUp); err != nil {
+			return nil, err
+		}
+		if i, ok := index[groupID]; ok {
+			groups[i].Blocks = append(groups[i].Blocks, block)
+		}
+	}
+	return groups, rows.Err()
+}
+
+// ---------------- HELPERS ----------------
+
+// groupColumns é a lista de colunas lida por scanGroup
+const groupColumns = `id, name, contact_name, contact_email, contact_phone, checkin_expected, checkout_expected,
+		cutoff_date, status, created_at, created_by, released_at, canceled_at, COALESCE(canceled_by, ''),
+		COALESCE(cancellation_reason, '')`
+
+// scanGroup lê o grupo sem os blocos
+func scanGroup(row rowScanner) (model.Group, error) {
+	group := model.Group{Blocks: []model.GroupBlock{}}
+	var checkin, checkout, cutoff time.Time
+	var released, canceled sql.NullTime
+	if err := row.Scan(&group.ID, &group.Name, &group.ContactName, &group.ContactEmail, &group.ContactPhone,
+		&checkin, &checkout, &cutoff, &group.Status, &group.CreatedAt, &group.CreatedBy,
+		&released, &canceled, &group.CanceledBy, &group.CancellationReason); err != nil {
+		return model.Group{}, err
+	}
+	group.CheckinExpected = checkin.Format("2006-01-02")
+	group.CheckoutExpected = checkout.Format("2006-01-02")
+	group.CutoffDate = cutoff.Format("2006-01-02")
+	if released.Valid {
+		group.ReleasedAt = &released.Time
+	}
+	if canceled.Valid {
+		group.CanceledAt = &canceled.Time
+	}
+	return group, nil
+}
+
+// lockGroup lê o grupo, sem os blocos, travando a linha até o fim da transação; nil
+// quando não existe
+func lockGroup(tx *sql.Tx, id string) (*model.Group, error) {
+	group, err := scanGroup(tx.QueryRow(`SELECT `+groupColumns+` FROM booking_groups WHERE id = $1 FOR UPDATE;`, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &group, nil
+}
+
+func auditGroup(tx *sql.Tx, groupID, action string, actor model.Actor, before, after any) error {
+	audit, err := newAuditEntry(model.AuditEntityGroup, groupID, action, actor, before, after)
+	if err != nil {
+		return err
+	}
+	return insertAudit(tx, audit)
+}
@@ -0,0 +1,123 @@
+package dao
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"hotel-soa/model"
+)
+
+// newTestGroup monta um grupo ACTIVE de 10 a 13 com cutoff em 1º de junho
+func newTestGroup(blocks ...model.GroupBlock) model.Group {
+	return model.Group{Name: "Congresso", ContactName: "Ana", CheckinExpected: "2026-06-10", CheckoutExpected: "2026-06-13", CutoffDate: "2026-06-01",
+		Status: model.GroupActive, Blocks: blocks, CreatedAt: time.Now().UTC(), CreatedBy: testActor.Username}
+}
+
+func TestGroupRepository(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, repos Repositories) {
+		insertTestRoom(t, repos, "DELUXE")
+		insertTestRoom(t, repos, "DELUXE")
+		insertTestRoom(t, repos, "STANDARD")
+
+		var soldOut *model.RoomTypeSoldOutError
+		if _, err := repos.Groups.InsertGroup(newTestGroup(model.GroupBlock{RoomType: "DELUXE", Rooms: 3}), testActor); !errors.As(err, &soldOut) || soldOut.RoomType != "DELUXE" {
+			t.Fatalf("expected a RoomTypeSoldOutError for a block over the stock, got %v", err)
+		}
+		groupID, err := repos.Groups.InsertGroup(newTestGroup(model.GroupBlock{RoomType: "DELUXE", Rooms: 1}, model.GroupBlock{RoomType: "STANDARD", Rooms: 1}), testActor)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		reservation := func(groupID, checkin, checkout string) model.Reservation {
+			return model.Reservation{GroupID: groupID, RoomType: "DELUXE", GuestID: insertTestGuest(t, repos, "Test Guest"), GuestName: "Test Guest",
+				CheckinExpected: checkin, CheckoutExpected: checkout, Status: "CREATED", TotalAmount: model.NewMoney(20000),
+				Nights: []model.NightlyRate{{Date: checkin, Price: model.NewMoney(20000)}}}
+		}
+		// o bloco tira um DELUXE do estoque: sobra um para quem não é do grupo
+		if _, err := repos.Reservations.InsertReservation(reservation("", "2026-06-10", "2026-06-11"), testActor); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := repos.Reservations.InsertReservation(reservation("", "2026-06-10", "2026-06-11"), testActor); !errors.As(err, &soldOut) {
+			t.Fatalf("expected the block to hold its room, got %v", err)
+		}
+		// a reserva do grupo usa o quarto do bloco; a segunda passa pelo estoque esgotado
+		picked, err := repos.Reservations.InsertReservation(reservation(groupID, "2026-06-10", "2026-06-11"), testActor)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := repos.Reservations.InsertReservation(reservation(groupID, "2026-06-10", "2026-06-11"), testActor); !errors.As(err, &soldOut) {
+			t.Fatalf("expected a pickup over the block to use the type stock, got %v", err)
+		}
+
+		group, err := repos.Groups.GetGroupByID(groupID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if group.Status != model.GroupActive || len(group.Blocks) != 2 || group.Blocks[0].RoomType != "DELUXE" || group.Blocks[0].PickedUp != 1 || group.Blocks[1].PickedUp != 0 {
+			t.Fatalf("expected the DELUXE block picked up once, got %+v", group)
+		}
+		if reservations, _ := repos.Groups.GetGroupReservations(groupID); len(reservations) != 1 || reservations[0].ID != picked {
+			t.Fatalf("expected the group reservation, got %+v", reservations)
+		}
+		if past, _ := repos.Groups.GetGroupsPastCutoff(date("2026-06-01")); len(past) != 0 {
+			t.Fatalf("expected no group on the cutoff day, got %+v", past)
+		}
+		if past, _ := repos.Groups.GetGroupsPastCutoff(date("2026-06-02")); len(past) != 1 || past[0].ID != groupID {
+			t.Fatalf("expected the group after the cutoff, got %+v", past)
+		}
+
+		// a conta master traz as noites do grupo e os pagamentos feitos a ele
+		folio, err := repos.Groups.GetGroupFolio(groupID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(folio.Charges) != 1 || folio.Charges[0].ReservationID != picked || folio.Charges[0].Type != model.FolioRoom {
+			t.Fatalf("expected the group night in the master folio, got %+v", folio)
+		}
+		payment := model.Payment{GroupID: groupID, Type: model.PaymentTypePayment, Method: "TRANSFER", Amount: model.NewMoney(10000), PostedAt: time.Now().UTC(), PostedBy: testActor.Username}
+		if _, err := repos.Groups.PostGroupPayment(payment, testActor); err != nil {
+			t.Fatal(err)
+		}
+		payment.Type, payment.Amount = model.PaymentTypeRefund, model.NewMoney(10001)
+		if _, err := repos.Groups.PostGroupPayment(payment, testActor); !errors.Is(err, ErrRefundExceedsPaid) {
+			t.Fatalf("expected ErrRefundExceedsPaid, got %v", err)
+		}
+		if folio, _ := repos.Groups.GetGroupFolio(groupID); len(folio.Payments) != 1 || !folio.Payments[0].Amount.Equal(model.NewMoney(10000)) {
+			t.Fatalf("expected the group payment, got %+v", folio.Payments)
+		}
+
+		// o release devolve o STANDARD não usado ao estoque
+		if err := repos.Groups.ReleaseGroup(groupID, time.Now().UTC(), testActor); err != nil {
+			t.Fatal(err)
+		}
+		if err := repos.Groups.ReleaseGroup(groupID, time.Now().UTC(), testActor); !errors.Is(err, ErrGroupStatusChanged) {
+			t.Fatalf("expected ErrGroupStatusChanged releasing twice, got %v", err)
+		}
+		types, err := repos.Rooms.GetRoomTypeAvailability(date("2026-06-11"), date("2026-06-13"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, tt := range types {
+			if tt.Available != tt.TotalRooms {
+				t.Fatalf("expected every room back in stock after the release, got %+v", types)
+			}
+		}
+
+		if err := repos.Groups.CancelGroup(groupID, time.Now().UTC(), "evento adiado", testActor); err != nil {
+			t.Fatal(err)
+		}
+		if err := repos.Groups.CancelGroup(groupID, time.Now().UTC(), "", testActor); !errors.Is(err, ErrGroupStatusChanged) {
+			t.Fatalf("expected ErrGroupStatusChanged canceling twice, got %v", err)
+		}
+		if _, err := repos.Reservations.InsertReservation(reservation(groupID, "2026-06-12", "2026-06-13"), testActor); !errors.Is(err, ErrGroupCanceled) {
+			t.Fatalf("expected ErrGroupCanceled, got %v", err)
+		}
+		if group, _ := repos.Groups.GetGroupByID(groupID); group.Status != model.GroupCanceled || group.CancellationReason != "evento adiado" || group.ReleasedAt == nil {
+			t.Fatalf("expected a CANCELED group, got %+v", group)
+		}
+		if missing, err := repos.Groups.GetGroupByID("00000000-0000-0000-0000-000000000000"); err != nil || missing.ID != "" {
+			t.Fatalf("expected an empty group, got %+v (%v)", missing, err)
+		}
+	})
+}
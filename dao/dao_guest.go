@@ -0,0 +1,98 @@
+package dao
+
+import (
+	"database/sql"
+	"errors"
+	"hotel-soa/model"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// ErrDuplicateDocument indica que já existe hóspede com o mesmo document_number
+var ErrDuplicateDocument = errors.New("document_number already registered")
+
+type postgresGuestRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresGuestRepository cria um GuestRepository apoiado no Postgres
+func NewPostgresGuestRepository(conn *sql.DB) GuestRepository {
+	return &postgresGuestRepository{db: conn}
+}
+
+const guestColumns = `id, name, COALESCE(document_number, ''), COALESCE(email, ''),
+		COALESCE(phone, ''), COALESCE(nationality, ''), COALESCE(preferences, ''), guest_type`
+
+func (r *postgresGuestRepository) InsertGuest(guest model.Guest) (string, error) {
+	id := uuid.NewString()
+	query := `INSERT INTO guests (id, name, document_number, email, phone, nationality, preferences, guest_type)
+		VALUES ($1, $2, NULLIF($3, ''), NULLIF($4, ''), NULLIF($5, ''), NULLIF($6, ''), NULLIF($7, ''), COALESCE(NULLIF($8, ''), 'INDIVIDUAL'));`
+	_, err := r.db.Exec(query, id, guest.Name, guest.DocumentNumber, guest.Email, guest.Phone, guest.Nationality, guest.Preferences, guest.Type)
+	if err != nil {
+		return "", mapGuestError(err)
+	}
+	return id, nil
+}
+
+func (r *postgresGuestRepository) UpdateGuest(guest model.Guest) error {
+	query := `UPDATE guests
+		SET name = $1, document_number = NULLIF($2, ''), email = NULLIF($3, ''),
+		    phone = NULLIF($4, ''), nationality = NULLIF($5, ''), preferences = NULLIF($6, ''),
+		    guest_type = COALESCE(NULLIF($7, ''), 'INDIVIDUAL')
+		WHERE id = $8;`
+	_, err := r.db.Exec(query, guest.Name, guest.DocumentNumber, guest.Email, guest.Phone, guest.Nationality, guest.Preferences, guest.Type, guest.ID)
+	return mapGuestError(err)
+}
+
+func (r *postgresGuestRepository) DeleteGuest(id string) error {
+	_, err := r.db.Exec("DELETE FROM guests WHERE id = $1;", id)
+	return err
+}
+
+func (r *postgresGuestRepository) GetAllGuests() ([]model.Guest, error) {
+	rows, err := r.db.Query(`SELECT ` + guestColumns + ` FROM guests ORDER BY name;`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var guests []model.Guest
+	for rows.Next() {
+		guest, err := scanGuest(rows)
+		if err != nil {
+			return nil, err
+		}
+		guests = append(guests, guest)
+	}
+	return guests, rows.Err()
+}
+
+func (r *postgresGuestRepository) GetGuestByID(id string) (model.Guest, error) {
+	guest, err := scanGuest(r.db.QueryRow(`SELECT `+guestColumns+` FROM guests WHERE id = $1;`, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return model.Guest{}, nil
+		}
+		return model.Guest{}, err
+	}
+	return guest, nil
+}
+
+// ---------------- HELPERS ----------------
+
+func scanGuest(row rowScanner) (model.Guest, error) {
+	var guest model.Guest
+	err := row.Scan(&guest.ID, &guest.Name, &guest.DocumentNumber, &guest.Email,
+		&guest.Phone, &guest.Nationality, &guest.Preferences, &guest.Type)
+	return guest, err
+}
+
+// mapGuestError traduz a violação de unicidade de document_number
+func mapGuestError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return ErrDuplicateDocument
+	}
+	return err
+}
@@ -0,0 +1,89 @@
+package dao
+
+import (
+	"errors"
+	"testing"
+
+	"hotel-soa/model"
+)
+
+func TestGuestRepository(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, repos Repositories) {
+		guest := model.Guest{Name: "Ana Souza", DocumentNumber: "123.456.789-00", Email: "ana@example.com", Phone: "+55 11 99999-0000", Nationality: "BR", Preferences: "high floor"}
+		id, err := repos.Guests.InsertGuest(guest)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// sem tipo, o hóspede é gravado como INDIVIDUAL
+		guest.ID, guest.Type = id, model.GuestIndividual
+		if got, err := repos.Guests.GetGuestByID(id); err != nil || got != guest {
+			t.Fatalf("expected %+v, got %+v, %v", guest, got, err)
+		}
+
+		guest.Email, guest.Preferences, guest.Type = "", "", model.GuestCorporate
+		if err := repos.Guests.UpdateGuest(guest); err != nil {
+			t.Fatal(err)
+		}
+		if got, _ := repos.Guests.GetGuestByID(id); got != guest {
+			t.Fatalf("expected %+v, got %+v", guest, got)
+		}
+
+		if err := repos.Guests.DeleteGuest(id); err != nil {
+			t.Fatal(err)
+		}
+		if got, err := repos.Guests.GetGuestByID(id); err != nil || got.ID != "" {
+			t.Fatalf("expected an empty guest after delete, got %+v, %v", got, err)
+		}
+	})
+}
+
+func TestGuestDocumentIsUnique(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, repos Repositories) {
+		if _, err := repos.Guests.InsertGuest(model.Guest{Name: "Ana", DocumentNumber: "AB123"}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := repos.Guests.InsertGuest(model.Guest{Name: "Other Ana", DocumentNumber: "AB123"}); !errors.Is(err, ErrDuplicateDocument) {
+			t.Fatalf("expected ErrDuplicateDocument, got %v", err)
+		}
+
+		// sem documento não há unicidade
+		first := insertTestGuest(t, repos, "No Document")
+		insertTestGuest(t, repos, "No Document")
+
+		if err := repos.Guests.UpdateGuest(model.Guest{ID: first, Name: "No Document", DocumentNumber: "AB123"}); !errors.Is(err, ErrDuplicateDocument) {
+			t.Fatalf("expected ErrDuplicateDocument on update, got %v", err)
+		}
+	})
+}
+
+func TestGetReservationsByGuest(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, repos Repositories) {
+		room := insertTestRoom(t, repos, "STANDARD")
+		guestID := insertTestGuest(t, repos, "Ana")
+		for _, dates := range [][2]string{{"2026-03-01", "2026-03-03"}, {"2026-09-10", "2026-09-12"}, {"2026-06-01", "2026-06-05"}} {
+			res := newTestReservation(t, repos, room.ID, dates[0], dates[1])
+			res.GuestID = guestID
+			if _, err := repos.Reservations.InsertReservation(res, testActor); err != nil {
+				t.Fatal(err)
+			}
+		}
+		insertTestReservation(t, repos, room.ID, "2026-07-01", "2026-07-03")
+
+		history, err := repos.Reservations.GetReservationsByGuest(guestID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var arrivals []string
+		for _, res := range history {
+			arrivals = append(arrivals, day(res.CheckinExpected))
+		}
+		// da estadia mais recente para a mais antiga
+		if len(arrivals) != 3 || arrivals[0] != "2026-09-10" || arrivals[1] != "2026-06-01" || arrivals[2] != "2026-03-01" {
+			t.Fatalf("unexpected history %v", arrivals)
+		}
+
+		if err := repos.Guests.DeleteGuest(guestID); err == nil {
+			t.Fatal("expected an error deleting a guest with reservations")
+		}
+	})
+}
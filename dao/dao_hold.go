@@ -0,0 +1,149 @@
+package dao
+
+import (
+	"database/sql"
+	"errors"
+	"hotel-soa/model"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrHoldExpired indica que o bloqueio convertido em reserva venceu ou já foi usado
+var ErrHoldExpired = errors.New("hold token is invalid or has expired")
+
+type postgresHoldRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresHoldRepository cria um HoldRepository apoiado no Postgres
+func NewPostgresHoldRepository(conn *sql.DB) HoldRepository {
+	return &postgresHoldRepository{db: conn}
+}
+
+// InsertHold trava os quartos do tipo como InsertReservation, de modo que bloqueios e
+// reservas do mesmo tipo são checados uns contra os outros em série
+func (r *postgresHoldRepository) InsertHold(hold model.RoomHold) (string, error) {
+	hold.ID = uuid.NewString()
+	err := withTx(r.db, func(tx *sql.Tx) error {
+		var roomType string
+		err := tx.QueryRow("SELECT type FROM rooms WHERE id = $1;", hold.RoomID).Scan(&roomType)
+		if err == sql.ErrNoRows {
+			return roomMismatch(hold.RoomID, "", "")
+		}
+		if err != nil {
+			return err
+		}
+		if err := lockRoomType(tx, roomType, hold.RoomID); err != nil {
+			return err
+		}
+
+		conflictID, err := findReservationConflict(tx, hold.RoomID, hold.CheckinExpected, hold.CheckoutExpected, "")
+		if err != nil {
+			return err
+		}
+		if conflictID != "" {
+			return &model.ReservationConflictError{RoomID: hold.RoomID, ReservationID: conflictID}
+		}
+		if err := checkHoldConflict(tx, hold.RoomID, hold.CheckinExpected, hold.CheckoutExpected, ""); err != nil {
+			return err
+		}
+		if err := checkTypeInventory(tx, roomType, hold.CheckinExpected, hold.CheckoutExpected, "", ""); err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(`INSERT INTO room_holds (`+holdColumns+`)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8);`,
+			hold.ID, hold.RoomID, hold.CheckinExpected, hold.CheckoutExpected, hold.TokenHash,
+			hold.ExpiresAt, hold.CreatedAt, hold.CreatedBy)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	return hold.ID, nil
+}
+
+func (r *postgresHoldRepository) GetHoldByID(id string) (model.RoomHold, error) {
+	return r.getHold("id = $1", id)
+}
+
+func (r *postgresHoldRepository) GetHoldByTokenHash(hash string) (model.RoomHold, error) {
+	return r.getHold("token_hash = $1", hash)
+}
+
+func (r *postgresHoldRepository) ReleaseHold(id string) (bool, error) {
+	result, err := r.db.Exec("DELETE FROM room_holds WHERE id = $1;", id)
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	return n > 0, err
+}
+
+func (r *postgresHoldRepository) DeleteExpiredHolds(at time.Time) (int, error) {
+	result, err := r.db.Exec("DELETE FROM room_holds WHERE expires_at <= $1;", at)
+	if err != nil {
+		return 0, err
+	}
+	n, err := result.RowsAffected()
+	return int(n), err
+}
+
+func (r *postgresHoldRepository) getHold(where string, arg any) (model.RoomHold, error) {
+	hold, err := scanHold(r.db.QueryRow(`SELECT `+holdColumns+` FROM room_holds WHERE `+where+`;`, arg))
+	if err == sql.ErrNoRows {
+		return model.RoomHold{}, nil
+	}
+	return hold, err
+}
+
+// ---------------- HELPERS ----------------
+
+// holdColumns é a lista de colunas lida por scanHold
+const holdColumns = `id, room_id, checkin_expected, checkout_expected, token_hash, expires_at, created_at, created_by`
+
+func scanHold(row rowScanner) (model.RoomHold, error) {
+	var hold model.RoomHold
+	var checkin, checkout time.Time
+	if err := row.Scan(&hold.ID, &hold.RoomID, &checkin, &checkout, &hold.TokenHash,
+		&hold.ExpiresAt, &hold.CreatedAt, &hold.CreatedBy); err != nil {
+		return model.RoomHold{}, err
+	}
+	hold.CheckinExpected = checkin.Format("2006-01-02")
+	hold.CheckoutExpected = checkout.Format("2006-01-02")
+	return hold, nil
+}
+
+// checkHoldConflict recusa o período que colide com um bloqueio ainda ativo do quarto,
+// exceto excludeID, o bloqueio sendo convertido; a validade é conferida pelo relógio do banco
+func checkHoldConflict(q queryer, roomID string, checkin, checkout any, excludeID string) error {
+	hold, err := scanHold(q.QueryRow(`SELECT `+holdColumns+` FROM room_holds
+		WHERE room_id = $1
+		  AND id != $2
+		  AND expires_at > now()
+		  AND (checkin_expected, checkout_expected) OVERLAPS ($3::date, $4::date)
+		ORDER BY checkin_expected
+		LIMIT 1;`, roomID, excludeID, checkin, checkout))
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return &model.RoomHeldError{RoomID: roomID, HoldID: hold.ID, ExpiresAt: hold.ExpiresAt}
+}
+
+// consumeHold apaga o bloqueio convertido em reserva, que precisa ainda estar ativo
+func consumeHold(tx *sql.Tx, id, roomID string) error {
+	result, err := tx.Exec("DELETE FROM room_holds WHERE id = $1 AND room_id = $2 AND expires_at > now();", id, roomID)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return ErrHoldExpired
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package dao
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"hotel-soa/model"
+)
+
+func TestHoldRepository(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, repos Repositories) {
+		room := insertTestRoom(t, repos, "STANDARD")
+		// a validade é comparada com o relógio real, como o now() do Postgres
+		now := time.Now().UTC().Truncate(time.Second)
+		hold := model.RoomHold{RoomID: room.ID, CheckinExpected: "2026-06-10", CheckoutExpected: "2026-06-12", TokenHash: "hash-1",
+			ExpiresAt: now.Add(10 * time.Minute), CreatedAt: now, CreatedBy: "maria"}
+		id, err := repos.Holds.InsertHold(hold)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := repos.Holds.GetHoldByTokenHash("hash-1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.ID != id || got.RoomID != room.ID || day(got.CheckinExpected) != "2026-06-10" || !got.ExpiresAt.Equal(hold.ExpiresAt) || got.CreatedBy != "maria" {
+			t.Fatalf("unexpected hold %+v", got)
+		}
+		if got, err := repos.Holds.GetHoldByTokenHash("other"); err != nil || got.ID != "" {
+			t.Fatalf("expected an empty hold for an unknown token, got %+v, %v", got, err)
+		}
+
+		// o bloqueio ativo conta como reserva para outro bloqueio, para a reserva e na disponibilidade
+		overlapping := hold
+		overlapping.CheckinExpected, overlapping.TokenHash = "2026-06-11", "hash-2"
+		var held *model.RoomHeldError
+		if _, err := repos.Holds.InsertHold(overlapping); !errors.As(err, &held) || held.HoldID != id {
+			t.Fatalf("expected a RoomHeldError for %s, got %v", id, err)
+		}
+		res := newTestReservation(t, repos, room.ID, "2026-06-11", "2026-06-13")
+		if _, err := repos.Reservations.InsertReservation(res, testActor); !errors.As(err, &held) {
+			t.Fatalf("expected a RoomHeldError for the reservation, got %v", err)
+		}
+		if conflict, err := repos.Reservations.HasReservationConflict(room.ID, date("2026-06-11"), date("2026-06-12"), ""); err != nil || !conflict {
+			t.Fatalf("expected the hold to conflict, got %v, %v", conflict, err)
+		}
+		if available, _ := repos.Rooms.GetAvailableRooms(date("2026-06-10"), date("2026-06-11"), 1, ""); len(available) != 0 {
+			t.Fatalf("expected the held room to be unavailable, got %+v", available)
+		}
+
+		// a reserva que converte o bloqueio o consome na mesma transação
+		converted := newTestReservation(t, repos, room.ID, "2026-06-10", "2026-06-12")
+		converted.HoldID = id
+		if _, err := repos.Reservations.InsertReservation(converted, testActor); err != nil {
+			t.Fatal(err)
+		}
+		if got, _ := repos.Holds.GetHoldByID(id); got.ID != "" {
+			t.Fatalf("expected the hold to be consumed, got %+v", got)
+		}
+		again := newTestReservation(t, repos, room.ID, "2026-06-20", "2026-06-22")
+		again.HoldID = id
+		if _, err := repos.Reservations.InsertReservation(again, testActor); !errors.Is(err, ErrHoldExpired) {
+			t.Fatalf("expected ErrHoldExpired converting a consumed hold, got %v", err)
+		}
+	})
+}
+
+func TestReleaseAndSweepHolds(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, repos Repositories) {
+		room := insertTestRoom(t, repos, "STANDARD")
+		now := time.Now().UTC().Truncate(time.Second)
+		insert := func(checkin, checkout, hash string, expires time.Time) string {
+			t.Helper()
+			id, err := repos.Holds.InsertHold(model.RoomHold{RoomID: room.ID, CheckinExpected: checkin, CheckoutExpected: checkout,
+				TokenHash: hash, ExpiresAt: expires, CreatedAt: now, CreatedBy: "maria"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			return id
+		}
+		expired := insert("2026-06-10", "2026-06-12", "hash-1", now.Add(-time.Minute))
+		// vencido, o bloqueio já não impede outro no mesmo período
+		active := insert("2026-06-10", "2026-06-12", "hash-2", now.Add(time.Hour))
+		other := insert("2026-06-20", "2026-06-22", "hash-3", now.Add(time.Hour))
+
+		if released, err := repos.Holds.ReleaseHold(other); err != nil || !released {
+			t.Fatalf("expected the hold to be released, got %v, %v", released, err)
+		}
+		if released, err := repos.Holds.ReleaseHold(other); err != nil || released {
+			t.Fatalf("expected nothing to release twice, got %v, %v", released, err)
+		}
+
+		if swept, err := repos.Holds.DeleteExpiredHolds(now); err != nil || swept != 1 {
+			t.Fatalf("expected 1 expired hold, got %d, %v", swept, err)
+		}
+		if got, _ := repos.Holds.GetHoldByID(expired); got.ID != "" {
+			t.Fatalf("expected the expired hold to be deleted, got %+v", got)
+		}
+		if got, _ := repos.Holds.GetHoldByID(active); got.ID != active {
+			t.Fatalf("expected the active hold to be kept, got %+v", got)
+		}
+	})
+}
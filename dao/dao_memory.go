@@ -0,0 +1,2369 @@
+package dao
+
+import (
+	"fmt"
+	"hotel-soa/model"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MemoryStore guarda quartos e reservas em memória, protegidos por um único lock
+// para que as verificações entre entidades (FK, conflitos) sejam atômicas
+type MemoryStore struct {
+	mu           sync.RWMutex
+	rooms        map[string]model.Room
+	reservations map[string]model.Reservation
+	ratePlans    map[string]model.RatePlan
+	policies     map[string]model.CancellationPolicy
+	taxRules     map[string]model.TaxRule
+	overbooking  map[string]model.OverbookingRule
+	guests       map[string]model.Guest
+	users        map[string]model.User
+	apiKeys      map[string]model.APIKey
+	roles        map[string]model.Role
+	userRoles    map[string][]string
+	audit        []model.AuditEntry
+	nightAudits  map[string]model.NightAudit
+	// roomCharges é indexado por reserva e data, como a chave primária de room_charges
+	roomCharges    map[string]model.RoomCharge
+	folioCharges   []model.FolioCharge
+	payments       []model.Payment
+	authorizations []model.PaymentAuthorization
+	holds          map[string]model.RoomHold
+	// groups guarda os blocos sem picked_up, calculado na leitura
+	groups        map[string]model.Group
+	groupPayments []model.Payment
+}
+
+// NewMemoryStore cria um MemoryStore vazio
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		rooms:        make(map[string]model.Room),
+		reservations: make(map[string]model.Reservation),
+		ratePlans:    make(map[string]model.RatePlan),
+		policies:     make(map[string]model.CancellationPolicy),
+		taxRules:     make(map[string]model.TaxRule),
+		overbooking:  make(map[string]model.OverbookingRule),
+		guests:       make(map[string]model.Guest),
+		users:        make(map[string]model.User),
+		apiKeys:      make(map[string]model.APIKey),
+		roles:        make(map[string]model.Role),
+		userRoles:    make(map[string][]string),
+		nightAudits:  make(map[string]model.NightAudit),
+		roomCharges:  make(map[string]model.RoomCharge),
+		holds:        make(map[string]model.RoomHold),
+		groups:       make(map[string]model.Group),
+	}
+}
+
+// Rooms retorna um RoomRepository apoiado neste store
+func (s *MemoryStore) Rooms() RoomRepository {
+	return &memoryRoomRepository{store: s}
+}
+
+// Reservations retorna um ReservationRepository apoiado neste store
+func (s *MemoryStore) Reservations() ReservationRepository {
+	return &memoryReservationRepository{store: s}
+}
+
+// RatePlans retorna um RatePlanRepository apoiado neste store
+func (s *MemoryStore) RatePlans() RatePlanRepository {
+	return &memoryRatePlanRepository{store: s}
+}
+
+// CancellationPolicies retorna um CancellationPolicyRepository apoiado neste store
+func (s *MemoryStore) CancellationPolicies() CancellationPolicyRepository {
+	return &memoryCancellationPolicyRepository{store: s}
+}
+
+// Taxes retorna um TaxRepository apoiado neste store
+func (s *MemoryStore) Taxes() TaxRepository {
+	return &memoryTaxRepository{store: s}
+}
+
+// Overbooking retorna um OverbookingRepository apoiado neste store
+func (s *MemoryStore) Overbooking() OverbookingRepository {
+	return &memoryOverbookingRepository{store: s}
+}
+
+// Guests retorna um GuestRepository apoiado neste store
+func (s *MemoryStore) Guests() GuestRepository {
+	return &memoryGuestRepository{store: s}
+}
+
+// Users retorna um UserRepository apoiado neste store
+func (s *MemoryStore) Users() UserRepository {
+	return &memoryUserRepository{store: s}
+}
+
+// APIKeys retorna um APIKeyRepository apoiado neste store
+func (s *MemoryStore) APIKeys() APIKeyRepository {
+	return &memoryAPIKeyRepository{store: s}
+}
+
+// Roles retorna um RoleRepository apoiado neste store
+func (s *MemoryStore) Roles() RoleRepository {
+	return &memoryRoleRepository{store: s}
+}
+
+// Audit retorna um AuditRepository apoiado neste store
+func (s *MemoryStore) Audit() AuditRepository {
+	return &memoryAuditRepository{store: s}
+}
+
+// NightAudits retorna um NightAuditRepository apoiado neste store
+func (s *MemoryStore) NightAudits() NightAuditRepository {
+	return &memoryNightAuditRepository{store: s}
+}
+
+// Folios retorna um FolioRepository apoiado neste store
+func (s *MemoryStore) Folios() FolioRepository {
+	return &memoryFolioRepository{store: s}
+}
+
+// Holds retorna um HoldRepository apoiado neste store
+func (s *MemoryStore) Holds() HoldRepository {
+	return &memoryHoldRepository{store: s}
+}
+
+// Groups retorna um GroupRepository apoiado neste store
+func (s *MemoryStore) Groups() GroupRepository {
+	return &memoryGroupRepository{store: s}
+}
+
+// ---------------- ROOMS ----------------
+
+type memoryRoomRepository struct {
+	store *MemoryStore
+}
+
+func (r *memoryRoomRepository) InsertRoom(room model.Room, actor model.Actor) (string, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, existing := range r.store.rooms {
+		if existing.Number == room.Number && existing.DeletedAt == nil {
+			return "", ErrDuplicateRoomNumber
+		}
+	}
+	id := uuid.NewString()
+	room.ID = id
+	if err := r.store.appendAudit(model.AuditEntityRoom, id, model.AuditActionCreate, actor, nil, &room); err != nil {
+		return "", err
+	}
+	r.store.rooms[id] = room
+	return id, nil
+}
+
+func (r *memoryRoomRepository) UpdateRoom(room model.Room, actor model.Actor) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	before, ok := r.store.rooms[room.ID]
+	if !ok {
+		return nil
+	}
+	for _, existing := range r.store.rooms {
+		if existing.ID != room.ID && existing.Number == room.Number && existing.DeletedAt == nil {
+			return ErrDuplicateRoomNumber
+		}
+	}
+	if room.Type != before.Type {
+		pinned := r.store.roomReservations(room.ID, func(res model.Reservation) bool {
+			return folioOpen(res.Status) && res.RoomType == before.Type
+		})
+		if len(pinned) > 0 {
+			return &model.RoomInUseError{RoomID: room.ID, Reservations: pinned}
+		}
+	}
+	room.DeletedAt = before.DeletedAt
+	if err := r.store.appendAudit(model.AuditEntityRoom, room.ID, model.AuditActionUpdate, actor, &before, &room); err != nil {
+		return err
+	}
+	r.store.rooms[room.ID] = room
+	return nil
+}
+
+func (r *memoryRoomRepository) ArchiveRoom(id string, at time.Time, actor model.Actor) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	before, ok := r.store.rooms[id]
+	if !ok || before.DeletedAt != nil {
+		return nil
+	}
+	today := at.Format("2006-01-02")
+	blocking := r.store.roomReservations(id, func(res model.Reservation) bool {
+		return (awaitingArrival(res.Status) || res.Status == "CHECKED_IN") && res.CheckoutExpected > today
+	})
+	if len(blocking) > 0 {
+		return &model.RoomInUseError{RoomID: id, Reservations: blocking}
+	}
+	room := before
+	room.DeletedAt = &at
+	if err := r.store.appendAudit(model.AuditEntityRoom, id, model.AuditActionArchive, actor, &before, &room); err != nil {
+		return err
+	}
+	r.store.rooms[id] = room
+	return nil
+}
+
+func (r *memoryRoomRepository) PurgeRoom(id string, actor model.Actor) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	before, ok := r.store.rooms[id]
+	if !ok {
+		return nil
+	}
+	// mesmo comportamento da fk_reservation_room
+	referencing := r.store.roomReservations(id, func(model.Reservation) bool { return true })
+	if len(referencing) > 0 {
+		return &model.RoomInUseError{RoomID: id, Reservations: referencing}
+	}
+	if err := r.store.appendAudit(model.AuditEntityRoom, id, model.AuditActionPurge, actor, &before, nil); err != nil {
+		return err
+	}
+	delete(r.store.rooms, id)
+	// reproduz o ON DELETE CASCADE de room_holds
+	for holdID, hold := range r.store.holds {
+		if hold.RoomID == id {
+			delete(r.store.holds, holdID)
+		}
+	}
+	return nil
+}
+
+func (r *memoryRoomRepository) GetAllRooms() ([]model.Room, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var rooms []model.Room
+	for _, room := range r.store.rooms {
+		if room.DeletedAt == nil {
+			rooms = append(rooms, room)
+		}
+	}
+	sort.Slice(rooms, func(i, j int) bool { return rooms[i].Number < rooms[j].Number })
+	return rooms, nil
+}
+
+func (r *memoryRoomRepository) ListRooms(filter model.RoomFilter) ([]model.Room, int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var rooms []model.Room
+	for _, room := range r.store.rooms {
+		if room.DeletedAt != nil ||
+			(filter.Status != "" && room.Status != filter.Status) ||
+			(filter.Type != "" && room.Type != filter.Type) ||
+			(filter.MinCapacity > 0 && room.Capacity < filter.MinCapacity) ||
+			(filter.MaxCapacity > 0 && room.Capacity > filter.MaxCapacity) ||
+			(filter.MinPrice.IsPositive() && room.PricePerNight.Cmp(filter.MinPrice) < 0) ||
+			(filter.MaxPrice.IsPositive() && room.PricePerNight.Cmp(filter.MaxPrice) > 0) {
+			continue
+		}
+		rooms = append(rooms, room)
+	}
+
+	page := paginate(rooms, roomSortColumns[filter.Page.Sort.Field], filter.Page,
+		model.Room.SortValue, func(room model.Room) string { return room.ID })
+	return page, len(rooms), nil
+}
+
+func (r *memoryRoomRepository) GetRoomByID(id string) (model.Room, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return r.store.rooms[id], nil
+}
+
+func (r *memoryRoomRepository) GetAvailableRooms(checkin, checkout time.Time, guests int, roomType string) ([]model.Room, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	totals := r.store.roomTypeTotals()
+	soldOut := map[string]bool{}
+	var rooms []model.Room
+	for _, room := range r.store.rooms {
+		if room.DeletedAt != nil || room.Status != "ATIVO" || room.Capacity < guests || (roomType != "" && room.Type != roomType) {
+			continue
+		}
+		full, ok := soldOut[room.Type]
+		if !ok {
+			free, _ := r.store.typeHeadroom(room.Type, totals[room.Type], checkin, checkout, "", "")
+			full = free <= 0
+			soldOut[room.Type] = full
+		}
+		if full {
+			continue
+		}
+		conflictID, err := r.store.findReservationConflict(room.ID, checkin, checkout, "")
+		if err != nil {
+			return nil, err
+		}
+		hold, err := r.store.findHoldConflict(room.ID, checkin, checkout, "")
+		if err != nil {
+			return nil, err
+		}
+		if conflictID == "" && hold.ID == "" {
+			rooms = append(rooms, room)
+		}
+	}
+	sort.Slice(rooms, func(i, j int) bool {
+		if rooms[i].Type != rooms[j].Type {
+			return rooms[i].Type < rooms[j].Type
+		}
+		return rooms[i].Number < rooms[j].Number
+	})
+	return rooms, nil
+}
+
+func (r *memoryRoomRepository) GetRoomTypeAvailability(checkin, checkout time.Time) ([]model.RoomTypeAvailability, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var types []model.RoomTypeAvailability
+	for roomType, total := range r.store.roomTypeTotals() {
+		free, _ := r.store.typeHeadroom(roomType, total, checkin, checkout, "", "")
+		types = append(types, model.RoomTypeAvailability{RoomType: roomType, TotalRooms: total, Available: max(free, 0)})
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i].RoomType < types[j].RoomType })
+	return types, nil
+}
+
+// ---------------- RESERVATIONS ----------------
+
+type memoryReservationRepository struct {
+	store *MemoryStore
+}
+
+func (r *memoryReservationRepository) InsertReservation(res model.Reservation, actor model.Actor) (string, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if err := r.store.checkReservation(res, ""); err != nil {
+		return "", err
+	}
+	if res.HoldID != "" {
+		hold, ok := r.store.holds[res.HoldID]
+		if !ok || hold.RoomID != res.RoomID || !hold.Active(time.Now()) {
+			return "", ErrHoldExpired
+		}
+		delete(r.store.holds, res.HoldID)
+	}
+	res.ID = uuid.NewString()
+	if err := r.store.appendAudit(model.AuditEntityReservation, res.ID, model.AuditActionCreate, actor, nil, &res); err != nil {
+		return "", err
+	}
+	r.store.reservations[res.ID] = res
+	return res.ID, nil
+}
+
+func (r *memoryReservationRepository) UpdateReservation(res model.Reservation, actor model.Actor) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.reservations[res.ID]; !ok {
+		return nil
+	}
+	if err := r.store.checkReservation(res, res.ID); err != nil {
+		return err
+	}
+	current := r.store.reservations[res.ID]
+	if err := checkClosedNights(current, res, r.store.lastClosedDate()); err != nil {
+		return err
+	}
+	if res.Nights == nil {
+		res.Nights = current.Nights
+	}
+	if res.Taxes == nil {
+		res.Taxes = current.Taxes
+	}
+	// assim como o UPDATE do Postgres, não altera os registros de check-in, check-out e cancelamento
+	res.CheckedInAt, res.CheckedInBy = current.CheckedInAt, current.CheckedInBy
+	res.CheckedOutAt, res.CheckedOutBy = current.CheckedOutAt, current.CheckedOutBy
+	res.CanceledAt, res.CanceledBy, res.CancellationReason = current.CanceledAt, current.CanceledBy, current.CancellationReason
+	res.CancellationFee, res.RefundAmount = current.CancellationFee, current.RefundAmount
+	res.NoShowAt, res.NoShowFee = current.NoShowAt, current.NoShowFee
+	res.ConfirmedAt = current.ConfirmedAt
+	res.GroupID = current.GroupID
+	if res.CheckoutExpected == current.CheckoutExpected {
+		res.OverstayFlaggedAt = current.OverstayFlaggedAt
+	}
+	res.TotalAmount = r.store.folioTotal(res)
+	if err := r.store.appendAudit(model.AuditEntityReservation, res.ID, model.AuditActionUpdate, actor, &current, &res); err != nil {
+		return err
+	}
+	r.store.reservations[res.ID] = res
+	return nil
+}
+
+func (r *memoryReservationRepository) CheckInReservation(id string, at time.Time, operator string, actor model.Actor) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	before, ok := r.store.reservations[id]
+	if !ok || !awaitingArrival(before.Status) {
+		return ErrReservationStatusChanged
+	}
+	res := before
+	res.Status, res.CheckedInAt, res.CheckedInBy = "CHECKED_IN", &at, operator
+	if err := r.store.appendAudit(model.AuditEntityReservation, id, model.AuditActionCheckIn, actor, &before, &res); err != nil {
+		return err
+	}
+	r.store.reservations[id] = res
+	return nil
+}
+
+func (r *memoryReservationRepository) AssignRoom(id, roomID string, actor model.Actor) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	before, ok := r.store.reservations[id]
+	if !ok || !awaitingArrival(before.Status) {
+		return ErrReservationStatusChanged
+	}
+	res := before
+	res.RoomID = roomID
+	if err := r.store.checkReservation(res, id); err != nil {
+		return err
+	}
+	if err := r.store.appendAudit(model.AuditEntityReservation, id, model.AuditActionAssignRoom, actor, &before, &res); err != nil {
+		return err
+	}
+	r.store.reservations[id] = res
+	return nil
+}
+
+func (r *memoryReservationRepository) CheckOutReservation(res model.Reservation, at time.Time, operator string, actor model.Actor) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	before, ok := r.store.reservations[res.ID]
+	if !ok || before.Status != "CHECKED_IN" {
+		return ErrReservationStatusChanged
+	}
+	current := before
+	current.Status, current.CheckedOutAt, current.CheckedOutBy = "CHECKED_OUT", &at, operator
+	current.CheckoutExpected = res.CheckoutExpected
+	if res.Nights != nil {
+		current.Nights = res.Nights
+	}
+	if res.Taxes != nil {
+		current.Taxes = res.Taxes
+	}
+	current.TotalAmount = r.store.folioTotal(current)
+	if balance := r.store.folioBalance(current); !balance.IsZero() {
+		return &model.BalanceDueError{ReservationID: res.ID, Balance: balance}
+	}
+	if err := r.store.appendAudit(model.AuditEntityReservation, res.ID, model.AuditActionCheckOut, actor, &before, &current); err != nil {
+		return err
+	}
+	r.store.reservations[res.ID] = current
+	return nil
+}
+
+func (r *memoryReservationRepository) CancelReservation(id string, at time.Time, reason string, quote model.CancellationQuote, actor model.Actor) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	before, ok := r.store.reservations[id]
+	if !ok || !awaitingArrival(before.Status) {
+		return ErrReservationStatusChanged
+	}
+	res := before
+	res.Status, res.CanceledAt, res.CanceledBy, res.CancellationReason = "CANCELED", &at, actor.Username, reason
+	res.CancellationFee, res.RefundAmount = &quote.Fee, &quote.RefundAmount
+	if err := r.store.appendAudit(model.AuditEntityReservation, id, model.AuditActionCancel, actor, &before, &res); err != nil {
+		return err
+	}
+	r.store.reservations[id] = res
+	return nil
+}
+
+func (r *memoryReservationRepository) MarkNoShow(id string, at time.Time, quote model.CancellationQuote, actor model.Actor) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	before, ok := r.store.reservations[id]
+	if !ok || !awaitingArrival(before.Status) {
+		return ErrReservationStatusChanged
+	}
+	res := before
+	res.Status, res.NoShowAt, res.NoShowFee, res.RefundAmount = "NO_SHOW", &at, &quote.Fee, &quote.RefundAmount
+	if err := r.store.appendAudit(model.AuditEntityReservation, id, model.AuditActionNoShow, actor, &before, &res); err != nil {
+		return err
+	}
+	r.store.reservations[id] = res
+	return nil
+}
+
+func (r *memoryReservationRepository) FlagOverstays(checkoutBy, at time.Time, actor model.Actor) (int, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	deadline := checkoutBy.Format("2006-01-02")
+	flagged := 0
+	for id, before := range r.store.reservations {
+		if before.Status != "CHECKED_IN" || before.CheckoutExpected > deadline || before.OverstayFlaggedAt != nil {
+			continue
+		}
+		res := before
+		res.OverstayFlaggedAt = &at
+		if err := r.store.appendAudit(model.AuditEntityReservation, id, model.AuditActionOverstay, actor, &before, &res); err != nil {
+			return flagged, err
+		}
+		r.store.reservations[id] = res
+		flagged++
+	}
+	return flagged, nil
+}
+
+func (r *memoryReservationRepository) GetUnarrivedReservations(arrivalBy time.Time) ([]model.Reservation, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	limit := arrivalBy.Format("2006-01-02")
+	var reservations []model.Reservation
+	for _, res := range r.store.reservations {
+		if awaitingArrival(res.Status) && res.CheckinExpected <= limit {
+			reservations = append(reservations, res)
+		}
+	}
+	sort.Slice(reservations, func(i, j int) bool {
+		return reservations[i].CheckinExpected < reservations[j].CheckinExpected
+	})
+	return reservations, nil
+}
+
+func (r *memoryReservationRepository) GetReservationsForDate(date time.Time) ([]model.Reservation, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	day := date.Format("2006-01-02")
+	var reservations []model.Reservation
+	for _, res := range r.store.reservations {
+		if res.CheckinExpected <= day && res.CheckoutExpected >= day {
+			reservations = append(reservations, res)
+		}
+	}
+	sort.Slice(reservations, func(i, j int) bool {
+		if reservations[i].CheckinExpected != reservations[j].CheckinExpected {
+			return reservations[i].CheckinExpected < reservations[j].CheckinExpected
+		}
+		return reservations[i].ID < reservations[j].ID
+	})
+	return reservations, nil
+}
+
+func (r *memoryReservationRepository) GetUnassignedReservations(from, to time.Time) ([]model.Reservation, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	first, last := from.Format("2006-01-02"), to.Format("2006-01-02")
+	var reservations []model.Reservation
+	for _, res := range r.store.reservations {
+		if awaitingArrival(res.Status) && res.RoomID == "" && res.CheckinExpected >= first && res.CheckinExpected <= last {
+			reservations = append(reservations, res)
+		}
+	}
+	sort.Slice(reservations, func(i, j int) bool {
+		a, b := reservations[i], reservations[j]
+		if a.CheckinExpected != b.CheckinExpected {
+			return a.CheckinExpected < b.CheckinExpected
+		}
+		if a.CheckoutExpected != b.CheckoutExpected {
+			return a.CheckoutExpected > b.CheckoutExpected
+		}
+		return a.ID < b.ID
+	})
+	return reservations, nil
+}
+
+func (r *memoryReservationRepository) GetRoomTypeBookings(roomType string, from, to time.Time) ([]model.Reservation, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var reservations []model.Reservation
+	for _, res := range r.store.reservations {
+		if res.RoomType != roomType || !holdsRoom(res.Status) {
+			continue
+		}
+		start, end, err := parseStoredDates(res.CheckinExpected, res.CheckoutExpected)
+		if err != nil {
+			return nil, err
+		}
+		if overlaps(start, end, from, to) {
+			reservations = append(reservations, res)
+		}
+	}
+	sort.Slice(reservations, func(i, j int) bool {
+		if reservations[i].CheckinExpected != reservations[j].CheckinExpected {
+			return reservations[i].CheckinExpected < reservations[j].CheckinExpected
+		}
+		return reservations[i].ID < reservations[j].ID
+	})
+	return reservations, nil
+}
+
+func (r *memoryReservationRepository) GetOverstayAlerts() ([]model.ReservationAlert, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var alerts []model.ReservationAlert
+	for _, res := range r.store.reservations {
+		if res.Status == "CHECKED_IN" && res.OverstayFlaggedAt != nil {
+			alerts = append(alerts, model.ReservationAlert{
+				Type:             model.AlertOverstay,
+				ReservationID:    res.ID,
+				RoomID:           res.RoomID,
+				GuestName:        res.GuestName,
+				CheckoutExpected: res.CheckoutExpected,
+				FlaggedAt:        *res.OverstayFlaggedAt,
+			})
+		}
+	}
+	sort.Slice(alerts, func(i, j int) bool {
+		if !alerts[i].FlaggedAt.Equal(alerts[j].FlaggedAt) {
+			return alerts[i].FlaggedAt.Before(alerts[j].FlaggedAt)
+		}
+		return alerts[i].ReservationID < alerts[j].ReservationID
+	})
+	return alerts, nil
+}
+
+func (r *memoryReservationRepository) PurgeReservation(id string, actor model.Actor) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	before, ok := r.store.reservations[id]
+	if !ok {
+		return nil
+	}
+	if err := r.store.appendAudit(model.AuditEntityReservation, id, model.AuditActionPurge, actor, &before, nil); err != nil {
+		return err
+	}
+	delete(r.store.reservations, id)
+	// reproduz o ON DELETE CASCADE de room_charges, folio_charges, folio_payments e
+	// payment_authorizations
+	for key, charge := range r.store.roomCharges {
+		if charge.ReservationID == id {
+			delete(r.store.roomCharges, key)
+		}
+	}
+	r.store.folioCharges = filterByReservation(r.store.folioCharges, id, func(c model.FolioCharge) string { return c.ReservationID })
+	r.store.payments = filterByReservation(r.store.payments, id, func(p model.Payment) string { return p.ReservationID })
+	r.store.authorizations = filterByReservation(r.store.authorizations, id, func(a model.PaymentAuthorization) string { return a.ReservationID })
+	return nil
+}
+
+func (r *memoryReservationRepository) GetAllReservations() ([]model.Reservation, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var reservations []model.Reservation
+	for _, res := range r.store.reservations {
+		// assim como no Postgres, a listagem não traz o detalhamento por noite nem os impostos
+		res.Nights, res.Taxes = nil, nil
+		reservations = append(reservations, res)
+	}
+	sort.Slice(reservations, func(i, j int) bool {
+		if reservations[i].CheckinExpected != reservations[j].CheckinExpected {
+			return reservations[i].CheckinExpected < reservations[j].CheckinExpected
+		}
+		return reservations[i].ID < reservations[j].ID
+	})
+	return reservations, nil
+}
+
+func (r *memoryReservationRepository) GetReservationByID(id string) (model.Reservation, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return r.store.reservations[id], nil
+}
+
+func (r *memoryReservationRepository) ListReservations(filter model.ReservationFilter) ([]model.Reservation, int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	prefix := strings.ToLower(filter.GuestNamePrefix)
+	var reservations []model.Reservation
+	for _, res := range r.store.reservations {
+		if (filter.RoomID != "" && res.RoomID != filter.RoomID) ||
+			(filter.RoomType != "" && res.RoomType != filter.RoomType) ||
+			(filter.Status != "" && res.Status != filter.Status) ||
+			(prefix != "" && !strings.HasPrefix(strings.ToLower(res.GuestName), prefix)) ||
+			(filter.From != "" && res.CheckoutExpected <= filter.From) ||
+			(filter.To != "" && res.CheckinExpected >= filter.To) {
+			continue
+		}
+		res.Nights, res.Taxes = nil, nil
+		reservations = append(reservations, res)
+	}
+
+	page := paginate(reservations, reservationSortColumns[filter.Page.Sort.Field], filter.Page,
+		model.Reservation.SortValue, func(res model.Reservation) string { return res.ID })
+	return page, len(reservations), nil
+}
+
+func (r *memoryReservationRepository) GetReservationsByGuest(guestID string) ([]model.Reservation, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var reservations []model.Reservation
+	for _, res := range r.store.reservations {
+		if res.GuestID == guestID {
+			res.Nights, res.Taxes = nil, nil
+			reservations = append(reservations, res)
+		}
+	}
+	sort.Slice(reservations, func(i, j int) bool {
+		return reservations[i].CheckinExpected > reservations[j].CheckinExpected
+	})
+	return reservations, nil
+}
+
+func (r *memoryReservationRepository) HasReservationConflict(roomID string, checkin, checkout time.Time, excludeID string) (bool, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	conflictID, err := r.store.findReservationConflict(roomID, checkin, checkout, excludeID)
+	if err != nil || conflictID != "" {
+		return conflictID != "", err
+	}
+	hold, err := r.store.findHoldConflict(roomID, checkin, checkout, "")
+	return hold.ID != "", err
+}
+
+// ---------------- RATE PLANS ----------------
+
+type memoryRatePlanRepository struct {
+	store *MemoryStore
+}
+
+func (r *memoryRatePlanRepository) InsertRatePlan(plan model.RatePlan) (string, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	plan.ID = uuid.NewString()
+	r.store.ratePlans[plan.ID] = plan
+	return plan.ID, nil
+}
+
+func (r *memoryRatePlanRepository) UpdateRatePlan(plan model.RatePlan) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.ratePlans[plan.ID]; ok {
+		r.store.ratePlans[plan.ID] = plan
+	}
+	return nil
+}
+
+func (r *memoryRatePlanRepository) DeleteRatePlan(id string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	delete(r.store.ratePlans, id)
+	return nil
+}
+
+func (r *memoryRatePlanRepository) GetAllRatePlans() ([]model.RatePlan, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var plans []model.RatePlan
+	for _, plan := range r.store.ratePlans {
+		plans = append(plans, plan)
+	}
+	sort.Slice(plans, func(i, j int) bool {
+		if plans[i].RoomType != plans[j].RoomType {
+			return plans[i].RoomType < plans[j].RoomType
+		}
+		return plans[i].StartDate < plans[j].StartDate
+	})
+	return plans, nil
+}
+
+func (r *memoryRatePlanRepository) GetRatePlanByID(id string) (model.RatePlan, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return r.store.ratePlans[id], nil
+}
+
+func (r *memoryRatePlanRepository) GetRatePlansForPeriod(roomType string, from, to time.Time) ([]model.RatePlan, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	fromStr, toStr := from.Format("2006-01-02"), to.Format("2006-01-02")
+	var plans []model.RatePlan
+	for _, plan := range r.store.ratePlans {
+		if plan.RoomType == roomType && plan.StartDate <= toStr && plan.EndDate >= fromStr {
+			plans = append(plans, plan)
+		}
+	}
+	sort.Slice(plans, func(i, j int) bool {
+		if plans[i].Priority != plans[j].Priority {
+			return plans[i].Priority > plans[j].Priority
+		}
+		return plans[i].StartDate > plans[j].StartDate
+	})
+	return plans, nil
+}
+
+// ---------------- CANCELLATION POLICIES ----------------
+
+type memoryCancellationPolicyRepository struct {
+	store *MemoryStore
+}
+
+func (r *memoryCancellationPolicyRepository) InsertCancellationPolicy(policy model.CancellationPolicy) (string, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	policy.ID = uuid.NewString()
+	r.store.policies[policy.ID] = policy
+	return policy.ID, nil
+}
+
+func (r *memoryCancellationPolicyRepository) UpdateCancellationPolicy(policy model.CancellationPolicy) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.policies[policy.ID]; ok {
+		r.store.policies[policy.ID] = policy
+	}
+	return nil
+}
+
+func (r *memoryCancellationPolicyRepository) DeleteCancellationPolicy(id string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	// mesmo comportamento das FKs de rooms, rate_plans e reservations
+	for _, room := range r.store.rooms {
+		if room.CancellationPolicyID == id {
+			return ErrCancellationPolicyInUse
+		}
+	}
+	for _, plan := range r.store.ratePlans {
+		if plan.CancellationPolicyID == id {
+			return ErrCancellationPolicyInUse
+		}
+	}
+	for _, res := range r.store.reservations {
+		if res.CancellationPolicyID == id {
+			return ErrCancellationPolicyInUse
+		}
+	}
+	delete(r.store.policies, id)
+	return nil
+}
+
+func (r *memoryCancellationPolicyRepository) GetAllCancellationPolicies() ([]model.CancellationPolicy, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var policies []model.CancellationPolicy
+	for _, policy := range r.store.policies {
+		policies = append(policies, policy)
+	}
+	sort.Slice(policies, func(i, j int) bool { return policies[i].Name < policies[j].Name })
+	return policies, nil
+}
+
+func (r *memoryCancellationPolicyRepository) GetCancellationPolicyByID(id string) (model.CancellationPolicy, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return r.store.policies[id], nil
+}
+
+// ---------------- TAXES ----------------
+
+type memoryTaxRepository struct {
+	store *MemoryStore
+}
+
+func (r *memoryTaxRepository) InsertTaxRule(rule model.TaxRule) (string, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if r.store.hasTaxCode(rule.Code, "") {
+		return "", ErrDuplicateTaxCode
+	}
+	rule.ID = uuid.NewString()
+	r.store.taxRules[rule.ID] = rule
+	return rule.ID, nil
+}
+
+func (r *memoryTaxRepository) UpdateTaxRule(rule model.TaxRule) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.taxRules[rule.ID]; !ok {
+		return nil
+	}
+	if r.store.hasTaxCode(rule.Code, rule.ID) {
+		return ErrDuplicateTaxCode
+	}
+	r.store.taxRules[rule.ID] = rule
+	return nil
+}
+
+func (r *memoryTaxRepository) DeleteTaxRule(id string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	delete(r.store.taxRules, id)
+	return nil
+}
+
+func (r *memoryTaxRepository) GetAllTaxRules() ([]model.TaxRule, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var rules []model.TaxRule
+	for _, rule := range r.store.taxRules {
+		rules = append(rules, rule)
+	}
+	sort.Slice(rules, func(i, j int) bool { return rules[i].Code < rules[j].Code })
+	return rules, nil
+}
+
+func (r *memoryTaxRepository) GetTaxRuleByID(id string) (model.TaxRule, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return r.store.taxRules[id], nil
+}
+
+func (r *memoryTaxRepository) GetPostedTaxes(from, to time.Time) ([]model.TaxLine, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	first, last := from.Format("2006-01-02"), to.Format("2006-01-02")
+	var lines []model.TaxLine
+	for _, res := range r.store.reservations {
+		for _, tax := range res.Taxes {
+			if tax.Date < first || tax.Date > last {
+				continue
+			}
+			if _, ok := r.store.roomCharges[res.ID+"/"+tax.Date]; ok {
+				lines = append(lines, tax)
+			}
+		}
+	}
+	sort.Slice(lines, func(i, j int) bool {
+		if lines[i].Date != lines[j].Date {
+			return lines[i].Date < lines[j].Date
+		}
+		return lines[i].Code < lines[j].Code
+	})
+	return lines, nil
+}
+
+// hasTaxCode reproduz a constraint UNIQUE de tax_rules.code; exige o lock
+func (s *MemoryStore) hasTaxCode(code, excludeID string) bool {
+	for id, rule := range s.taxRules {
+		if id != excludeID && rule.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+// ---------------- OVERBOOKING ----------------
+
+type memoryOverbookingRepository struct {
+	store *MemoryStore
+}
+
+func (r *memoryOverbookingRepository) InsertOverbookingRule(rule model.OverbookingRule) (string, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if r.store.overbookingOverlaps(rule) {
+		return "", ErrOverbookingRuleOverlap
+	}
+	rule.ID = uuid.NewString()
+	r.store.overbooking[rule.ID] = rule
+	return rule.ID, nil
+}
+
+func (r *memoryOverbookingRepository) UpdateOverbookingRule(rule model.OverbookingRule) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.overbooking[rule.ID]; !ok {
+		return nil
+	}
+	if r.store.overbookingOverlaps(rule) {
+		return ErrOverbookingRuleOverlap
+	}
+	r.store.overbooking[rule.ID] = rule
+	return nil
+}
+
+func (r *memoryOverbookingRepository) DeleteOverbookingRule(id string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	delete(r.store.overbooking, id)
+	return nil
+}
+
+func (r *memoryOverbookingRepository) GetAllOverbookingRules() ([]model.OverbookingRule, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var rules []model.OverbookingRule
+	for _, rule := range r.store.overbooking {
+		rules = append(rules, rule)
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		if rules[i].RoomType != rules[j].RoomType {
+			return rules[i].RoomType < rules[j].RoomType
+		}
+		return rules[i].StartDate < rules[j].StartDate
+	})
+	return rules, nil
+}
+
+func (r *memoryOverbookingRepository) GetOverbookingRuleByID(id string) (model.OverbookingRule, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return r.store.overbooking[id], nil
+}
+
+func (r *memoryOverbookingRepository) GetRoomTypeNights(from, to time.Time) ([]model.RoomTypeNight, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	totals := r.store.roomTypeTotals()
+	types := make([]string, 0, len(totals))
+	for roomType := range totals {
+		types = append(types, roomType)
+	}
+	sort.Strings(types)
+
+	now := time.Now()
+	var nights []model.RoomTypeNight
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		date := day.Format("2006-01-02")
+		for _, roomType := range types {
+			night := model.RoomTypeNight{Date: date, RoomType: roomType, TotalRooms: totals[roomType]}
+			night.Limit = night.TotalRooms + r.store.overbookingAllowance(roomType, night.TotalRooms, date)
+			for _, res := range r.store.reservations {
+				if res.RoomType == roomType && holdsRoom(res.Status) && res.CheckinExpected <= date && res.CheckoutExpected > date {
+					night.Booked++
+				}
+			}
+			for _, hold := range r.store.holds {
+				if r.store.rooms[hold.RoomID].Type == roomType && hold.Active(now) &&
+					hold.CheckinExpected <= date && hold.CheckoutExpected > date {
+					night.Held++
+				}
+			}
+			night.Blocked = r.store.groupBlocked(roomType, date, "")
+			nights = append(nights, night)
+		}
+	}
+	return nights, nil
+}
+
+// overbookingOverlaps reproduz a constraint overbooking_rules_no_overlap; exige o lock
+func (s *MemoryStore) overbookingOverlaps(rule model.OverbookingRule) bool {
+	for id, other := range s.overbooking {
+		if id != rule.ID && other.RoomType == rule.RoomType &&
+			other.StartDate <= rule.EndDate && other.EndDate >= rule.StartDate {
+			return true
+		}
+	}
+	return false
+}
+
+// overbookingAllowance retorna os quartos extras do tipo na noite; exige o lock
+func (s *MemoryStore) overbookingAllowance(roomType string, total int, date string) int {
+	for _, rule := range s.overbooking {
+		if rule.RoomType == roomType && rule.StartDate <= date && rule.EndDate >= date {
+			return model.OverbookingAllowance(total, rule.Percent)
+		}
+	}
+	return 0
+}
+
+// ---------------- GUESTS ----------------
+
+type memoryGuestRepository struct {
+	store *MemoryStore
+}
+
+func (r *memoryGuestRepository) InsertGuest(guest model.Guest) (string, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if r.store.hasDocument(guest.DocumentNumber, "") {
+		return "", ErrDuplicateDocument
+	}
+	guest.ID = uuid.NewString()
+	if guest.Type == "" {
+		guest.Type = model.GuestIndividual
+	}
+	r.store.guests[guest.ID] = guest
+	return guest.ID, nil
+}
+
+func (r *memoryGuestRepository) UpdateGuest(guest model.Guest) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.guests[guest.ID]; !ok {
+		return nil
+	}
+	if r.store.hasDocument(guest.DocumentNumber, guest.ID) {
+		return ErrDuplicateDocument
+	}
+	if guest.Type == "" {
+		guest.Type = model.GuestIndividual
+	}
+	r.store.guests[guest.ID] = guest
+	return nil
+}
+
+func (r *memoryGuestRepository) DeleteGuest(id string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	// mesmo comportamento da fk_reservation_guest
+	for _, res := range r.store.reservations {
+		if res.GuestID == id {
+			return fmt.Errorf("guest %s is still referenced by reservation %s", id, res.ID)
+		}
+	}
+	delete(r.store.guests, id)
+	return nil
+}
+
+func (r *memoryGuestRepository) GetAllGuests() ([]model.Guest, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var guests []model.Guest
+	for _, guest := range r.store.guests {
+		guests = append(guests, guest)
+	}
+	sort.Slice(guests, func(i, j int) bool { return guests[i].Name < guests[j].Name })
+	return guests, nil
+}
+
+func (r *memoryGuestRepository) GetGuestByID(id string) (model.Guest, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return r.store.guests[id], nil
+}
+
+// ---------------- USERS ----------------
+
+type memoryUserRepository struct {
+	store *MemoryStore
+}
+
+func (r *memoryUserRepository) InsertUser(user model.User) (string, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, existing := range r.store.users {
+		if existing.Username == user.Username {
+			return "", ErrDuplicateUsername
+		}
+	}
+	user.ID = uuid.NewString()
+	r.store.users[user.ID] = user
+	return user.ID, nil
+}
+
+func (r *memoryUserRepository) GetUserByID(id string) (model.User, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return r.store.users[id], nil
+}
+
+func (r *memoryUserRepository) GetUserByUsername(username string) (model.User, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, user := range r.store.users {
+		if user.Username == username {
+			return user, nil
+		}
+	}
+	return model.User{}, nil
+}
+
+func (r *memoryUserRepository) GetAllUsers() ([]model.User, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var users []model.User
+	for _, user := range r.store.users {
+		users = append(users, user)
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].Username < users[j].Username })
+	return users, nil
+}
+
+func (r *memoryUserRepository) UpdatePassword(id, hash string) (bool, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	user, ok := r.store.users[id]
+	if !ok {
+		return false, nil
+	}
+	user.PasswordHash = hash
+	user.TokenVersion++
+	r.store.users[id] = user
+	return true, nil
+}
+
+func (r *memoryUserRepository) RevokeTokens(id string) (bool, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	user, ok := r.store.users[id]
+	if !ok {
+		return false, nil
+	}
+	user.TokenVersion++
+	r.store.users[id] = user
+	return true, nil
+}
+
+// ---------------- API KEYS ----------------
+
+type memoryAPIKeyRepository struct {
+	store *MemoryStore
+}
+
+func (r *memoryAPIKeyRepository) InsertAPIKey(key model.APIKey) (string, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.users[key.UserID]; !ok {
+		return "", fmt.Errorf("user %s does not exist", key.UserID)
+	}
+	key.ID = uuid.NewString()
+	r.store.apiKeys[key.ID] = key
+	return key.ID, nil
+}
+
+func (r *memoryAPIKeyRepository) GetAPIKeyByHash(hash string) (model.APIKey, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, key := range r.store.apiKeys {
+		if key.KeyHash == hash {
+			return key, nil
+		}
+	}
+	return model.APIKey{}, nil
+}
+
+func (r *memoryAPIKeyRepository) GetAPIKeysByUser(userID string) ([]model.APIKey, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var keys []model.APIKey
+	for _, key := range r.store.apiKeys {
+		if key.UserID == userID {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].CreatedAt.Before(keys[j].CreatedAt) })
+	return keys, nil
+}
+
+func (r *memoryAPIKeyRepository) RevokeAPIKey(id, userID string, at time.Time) (bool, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	key, ok := r.store.apiKeys[id]
+	if !ok || key.UserID != userID || key.RevokedAt != nil {
+		return false, nil
+	}
+	key.RevokedAt = &at
+	r.store.apiKeys[id] = key
+	return true, nil
+}
+
+func (r *memoryAPIKeyRepository) TouchAPIKey(id string, at time.Time) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if key, ok := r.store.apiKeys[id]; ok {
+		key.LastUsedAt = &at
+		r.store.apiKeys[id] = key
+	}
+	return nil
+}
+
+// ---------------- ROLES ----------------
+
+type memoryRoleRepository struct {
+	store *MemoryStore
+}
+
+func (r *memoryRoleRepository) GetAllRoles() ([]model.Role, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	roles := make([]model.Role, 0, len(r.store.roles))
+	for _, role := range r.store.roles {
+		roles = append(roles, copyRole(role))
+	}
+	sort.Slice(roles, func(i, j int) bool { return roles[i].Name < roles[j].Name })
+	return roles, nil
+}
+
+func (r *memoryRoleRepository) GetRole(name string) (model.Role, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	role, ok := r.store.roles[name]
+	if !ok {
+		return model.Role{}, nil
+	}
+	return copyRole(role), nil
+}
+
+func (r *memoryRoleRepository) GetUserRoles(userID string) ([]model.Role, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var roles []model.Role
+	for _, name := range r.store.userRoles[userID] {
+		if role, ok := r.store.roles[name]; ok {
+			roles = append(roles, copyRole(role))
+		}
+	}
+	sort.Slice(roles, func(i, j int) bool { return roles[i].Name < roles[j].Name })
+	return roles, nil
+}
+
+func (r *memoryRoleRepository) SaveRole(role model.Role) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	role = copyRole(role)
+	sort.Strings(role.Permissions)
+	r.store.roles[role.Name] = role
+	return nil
+}
+
+func (r *memoryRoleRepository) DeleteRole(name string) (bool, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.roles[name]; !ok {
+		return false, nil
+	}
+	delete(r.store.roles, name)
+	// reproduz o ON DELETE CASCADE de user_roles
+	for userID, names := range r.store.userRoles {
+		kept := names[:0]
+		for _, n := range names {
+			if n != name {
+				kept = append(kept, n)
+			}
+		}
+		r.store.userRoles[userID] = kept
+	}
+	return true, nil
+}
+
+func (r *memoryRoleRepository) SetUserRoles(userID string, roles []string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.users[userID]; !ok {
+		return fmt.Errorf("user %s does not exist", userID)
+	}
+	for _, name := range roles {
+		if _, ok := r.store.roles[name]; !ok {
+			return fmt.Errorf("role %s does not exist", name)
+		}
+	}
+	r.store.userRoles[userID] = append([]string(nil), roles...)
+	return nil
+}
+
+// copyRole evita que quem lê o papel altere o slice de permissões guardado
+func copyRole(role model.Role) model.Role {
+	role.Permissions = append([]string{}, role.Permissions...)
+	return role
+}
+
+// ---------------- AUDIT ----------------
+
+type memoryAuditRepository struct {
+	store *MemoryStore
+}
+
+func (r *memoryAuditRepository) GetAuditLog(entity, entityID string) ([]model.AuditEntry, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var entries []model.AuditEntry
+	for _, entry := range r.store.audit {
+		if entry.Entity == entity && entry.EntityID == entityID {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+// appendAudit registra a alteração antes de aplicá-la; exige o lock de escrita, o que
+// faz da entrada e da alteração uma operação só
+func (s *MemoryStore) appendAudit(entity, entityID, action string, actor model.Actor, before, after any) error {
+	entry, err := newAuditEntry(entity, entityID, action, actor, before, after)
+	if err != nil {
+		return err
+	}
+	entry.ID = int64(len(s.audit) + 1)
+	s.audit = append(s.audit, entry)
+	return nil
+}
+
+// ---------------- FOLIOS ----------------
+
+type memoryFolioRepository struct {
+	store *MemoryStore
+}
+
+func (r *memoryFolioRepository) GetFolio(reservationID string) (model.Folio, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	res, ok := r.store.reservations[reservationID]
+	if !ok {
+		return model.Folio{}, nil
+	}
+	folio := model.Folio{ReservationID: reservationID, GroupID: res.GroupID, Status: res.Status}
+	for _, night := range res.Nights {
+		charge := model.FolioCharge{
+			ReservationID: reservationID,
+			Type:          model.FolioRoom,
+			Description:   roomChargeDescription,
+			Date:          night.Date,
+			Amount:        night.Price,
+		}
+		if posted, ok := r.store.roomCharges[reservationID+"/"+night.Date]; ok {
+			charge.PostedAt, charge.PostedBy = &posted.PostedAt, r.store.nightAudits[night.Date].ClosedBy
+		}
+		folio.Charges = append(folio.Charges, charge)
+	}
+	for _, tax := range res.Taxes {
+		if tax.Inclusive {
+			continue
+		}
+		charge := model.FolioCharge{
+			ReservationID: reservationID,
+			Type:          model.FolioTax,
+			Description:   tax.Name,
+			Date:          tax.Date,
+			Amount:        tax.Amount,
+		}
+		if posted, ok := r.store.roomCharges[reservationID+"/"+tax.Date]; ok {
+			charge.PostedAt, charge.PostedBy = &posted.PostedAt, r.store.nightAudits[tax.Date].ClosedBy
+		}
+		folio.Charges = append(folio.Charges, charge)
+	}
+	folio.Taxes = model.SummarizeTaxes(res.Taxes)
+	for _, charge := range r.store.folioCharges {
+		if charge.ReservationID == reservationID {
+			folio.Charges = append(folio.Charges, charge)
+		}
+	}
+	for _, payment := range r.store.payments {
+		if payment.ReservationID == reservationID {
+			folio.Payments = append(folio.Payments, payment)
+		}
+	}
+	folio.Authorizations = r.store.reservationAuthorizations(reservationID)
+	return folio, nil
+}
+
+func (r *memoryFolioRepository) PostCharge(charge model.FolioCharge, actor model.Actor) (string, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	before, ok := r.store.reservations[charge.ReservationID]
+	if !ok || !folioOpen(before.Status) {
+		return "", ErrFolioClosed
+	}
+	charge.ID = uuid.NewString()
+	charges := append(r.store.folioCharges, charge)
+	res := before
+	res.TotalAmount = r.store.folioTotalWith(res, charges)
+	if res.TotalAmount.IsNegative() {
+		return "", ErrDiscountExceedsTotal
+	}
+	if err := r.store.appendAudit(model.AuditEntityReservation, res.ID, model.AuditActionPostCharge, actor, nil, charge); err != nil {
+		return "", err
+	}
+	r.store.folioCharges = charges
+	r.store.reservations[res.ID] = res
+	return charge.ID, nil
+}
+
+func (r *memoryFolioRepository) PostPayment(payment model.Payment, actor model.Actor) (string, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.reservations[payment.ReservationID]; !ok {
+		return "", ErrReservationStatusChanged
+	}
+	payment.ID = uuid.NewString()
+	if err := r.store.insertPayment(payment, actor); err != nil {
+		return "", err
+	}
+	return payment.ID, nil
+}
+
+func (r *memoryFolioRepository) GetAuthorizations(reservationID string) ([]model.PaymentAuthorization, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return r.store.reservationAuthorizations(reservationID), nil
+}
+
+func (r *memoryFolioRepository) InsertAuthorization(auth model.PaymentAuthorization, actor model.Actor) (string, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	before, ok := r.store.reservations[auth.ReservationID]
+	if !ok {
+		return "", ErrReservationStatusChanged
+	}
+	// a confirmação é checada antes de gravar, como o rollback do Postgres
+	confirm := auth.Status == model.AuthorizationAuthorized
+	if confirm && before.Status != "CREATED" {
+		return "", ErrReservationStatusChanged
+	}
+	auth.ID = uuid.NewString()
+	if err := r.store.appendAudit(model.AuditEntityReservation, auth.ReservationID, model.AuditActionPaymentAuthorization, actor, nil, auth); err != nil {
+		return "", err
+	}
+	if confirm {
+		res := before
+		res.Status, res.ConfirmedAt = "CONFIRMED", &auth.CreatedAt
+		if err := r.store.appendAudit(model.AuditEntityReservation, res.ID, model.AuditActionConfirm, actor, &before, &res); err != nil {
+			return "", err
+		}
+		r.store.reservations[res.ID] = res
+	}
+	r.store.authorizations = append(r.store.authorizations, auth)
+	return auth.ID, nil
+}
+
+func (r *memoryFolioRepository) UpdateAuthorization(auth model.PaymentAuthorization, from string, payment *model.Payment, actor model.Actor) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.reservations[auth.ReservationID]; !ok {
+		return ErrReservationStatusChanged
+	}
+	i := -1
+	for j, stored := range r.store.authorizations {
+		if stored.ID == auth.ID && stored.ReservationID == auth.ReservationID && stored.Status == from {
+			i = j
+			break
+		}
+	}
+	if i < 0 {
+		return ErrAuthorizationChanged
+	}
+	if payment != nil && payment.Type == model.PaymentTypeRefund && payment.Amount.Cmp(r.store.paid(payment.ReservationID)) > 0 {
+		return ErrRefundExceedsPaid
+	}
+	// assim como o UPDATE do Postgres, só muda o status, os valores e a captura
+	stored := r.store.authorizations[i]
+	stored.Status, stored.CapturedAmount, stored.RefundedAmount = auth.Status, auth.CapturedAmount, auth.RefundedAmount
+	stored.CaptureReference, stored.UpdatedAt = auth.CaptureReference, auth.UpdatedAt
+	if err := r.store.appendAudit(model.AuditEntityReservation, auth.ReservationID, model.AuditActionPaymentAuthorization, actor, nil, stored); err != nil {
+		return err
+	}
+	if payment != nil {
+		payment.ID = uuid.NewString()
+		if err := r.store.insertPayment(*payment, actor); err != nil {
+			return err
+		}
+	}
+	r.store.authorizations[i] = stored
+	return nil
+}
+
+// insertPayment segue o insertPayment do Postgres; exige o lock de escrita
+func (s *MemoryStore) insertPayment(payment model.Payment, actor model.Actor) error {
+	if payment.Type == model.PaymentTypeRefund && payment.Amount.Cmp(s.paid(payment.ReservationID)) > 0 {
+		return ErrRefundExceedsPaid
+	}
+	if err := s.appendAudit(model.AuditEntityReservation, payment.ReservationID, model.AuditActionPostPayment, actor, nil, payment); err != nil {
+		return err
+	}
+	s.payments = append(s.payments, payment)
+	return nil
+}
+
+// reservationAuthorizations lista as autorizações da reserva na ordem de gravação; exige o lock
+func (s *MemoryStore) reservationAuthorizations(reservationID string) []model.PaymentAuthorization {
+	var authorizations []model.PaymentAuthorization
+	for _, auth := range s.authorizations {
+		if auth.ReservationID == reservationID {
+			authorizations = append(authorizations, auth)
+		}
+	}
+	return authorizations
+}
+
+// folioTotal reproduz o refreshTotal do Postgres: noites, impostos cobrados à parte e
+// lançamentos; exige o lock
+func (s *MemoryStore) folioTotal(res model.Reservation) model.Money {
+	return s.folioTotalWith(res, s.folioCharges)
+}
+
+func (s *MemoryStore) folioTotalWith(res model.Reservation, charges []model.FolioCharge) model.Money {
+	total := model.NewMoney(0)
+	for _, night := range res.Nights {
+		total = total.Add(night.Price)
+	}
+	for _, tax := range res.Taxes {
+		if !tax.Inclusive {
+			total = total.Add(tax.Amount)
+		}
+	}
+	for _, charge := range charges {
+		if charge.ReservationID == res.ID {
+			total = total.Add(charge.Amount)
+		}
+	}
+	return total
+}
+
+// paid soma os pagamentos menos os estornos da reserva; exige o lock
+func (s *MemoryStore) paid(reservationID string) model.Money {
+	paid := model.NewMoney(0)
+	for _, payment := range s.payments {
+		if payment.ReservationID != reservationID {
+			continue
+		}
+		if payment.Type == model.PaymentTypeRefund {
+			paid = paid.Sub(payment.Amount)
+		} else {
+			paid = paid.Add(payment.Amount)
+		}
+	}
+	return paid
+}
+
+// folioBalance segue a regra de requireSettled; exige o lock
+func (s *MemoryStore) folioBalance(res model.Reservation) model.Money {
+	balance := res.TotalAmount.Sub(s.paid(res.ID))
+	if res.GroupID != "" {
+		balance = balance.Sub(s.folioTotalWith(res, nil))
+	}
+	return balance
+}
+
+// filterByReservation remove os itens da reserva, como o ON DELETE CASCADE
+func filterByReservation[T any](items []T, reservationID string, owner func(T) string) []T {
+	kept := items[:0]
+	for _, item := range items {
+		if owner(item) != reservationID {
+			kept = append(kept, item)
+		}
+	}
+	return kept
+}
+
+// ---------------- HOLDS ----------------
+
+type memoryHoldRepository struct {
+	store *MemoryStore
+}
+
+func (r *memoryHoldRepository) InsertHold(hold model.RoomHold) (string, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	room, ok := r.store.rooms[hold.RoomID]
+	if !ok {
+		return "", roomMismatch(hold.RoomID, "", "")
+	}
+	checkin, checkout, err := parseStoredDates(hold.CheckinExpected, hold.CheckoutExpected)
+	if err != nil {
+		return "", err
+	}
+	if !checkout.After(checkin) {
+		return "", fmt.Errorf("checkout_expected must be after checkin_expected")
+	}
+	conflictID, err := r.store.findReservationConflict(hold.RoomID, checkin, checkout, "")
+	if err != nil {
+		return "", err
+	}
+	if conflictID != "" {
+		return "", &model.ReservationConflictError{RoomID: hold.RoomID, ReservationID: conflictID}
+	}
+	held, err := r.store.findHoldConflict(hold.RoomID, checkin, checkout, "")
+	if err != nil {
+		return "", err
+	}
+	if held.ID != "" {
+		return "", &model.RoomHeldError{RoomID: hold.RoomID, HoldID: held.ID, ExpiresAt: held.ExpiresAt}
+	}
+	if err := r.store.checkTypeInventory(room.Type, checkin, checkout, "", ""); err != nil {
+		return "", err
+	}
+
+	hold.ID = uuid.NewString()
+	r.store.holds[hold.ID] = hold
+	return hold.ID, nil
+}
+
+func (r *memoryHoldRepository) GetHoldByID(id string) (model.RoomHold, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return r.store.holds[id], nil
+}
+
+func (r *memoryHoldRepository) GetHoldByTokenHash(hash string) (model.RoomHold, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, hold := range r.store.holds {
+		if hold.TokenHash == hash {
+			return hold, nil
+		}
+	}
+	return model.RoomHold{}, nil
+}
+
+func (r *memoryHoldRepository) ReleaseHold(id string) (bool, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.holds[id]; !ok {
+		return false, nil
+	}
+	delete(r.store.holds, id)
+	return true, nil
+}
+
+func (r *memoryHoldRepository) DeleteExpiredHolds(at time.Time) (int, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var deleted int
+	for id, hold := range r.store.holds {
+		if !hold.Active(at) {
+			delete(r.store.holds, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// ---------------- GROUPS ----------------
+
+type memoryGroupRepository struct {
+	store *MemoryStore
+}
+
+func (r *memoryGroupRepository) InsertGroup(group model.Group, actor model.Actor) (string, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	checkin, checkout, err := parseStoredDates(group.CheckinExpected, group.CheckoutExpected)
+	if err != nil {
+		return "", err
+	}
+	totals := r.store.roomTypeTotals()
+	for _, block := range group.Blocks {
+		free, night := r.store.typeHeadroom(block.RoomType, totals[block.RoomType], checkin, checkout, "", "")
+		if free < block.Rooms {
+			return "", &model.RoomTypeSoldOutError{RoomType: block.RoomType, Date: night}
+		}
+	}
+	group.ID = uuid.NewString()
+	group.Blocks = append([]model.GroupBlock(nil), group.Blocks...)
+	if err := r.store.appendAudit(model.AuditEntityGroup, group.ID, model.AuditActionCreate, actor, nil, &group); err != nil {
+		return "", err
+	}
+	r.store.groups[group.ID] = group
+	return group.ID, nil
+}
+
+func (r *memoryGroupRepository) GetGroupByID(id string) (model.Group, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	group, ok := r.store.groups[id]
+	if !ok {
+		return model.Group{}, nil
+	}
+	return r.store.withPickedUp(group), nil
+}
+
+func (r *memoryGroupRepository) GetAllGroups() ([]model.Group, error) {
+	return r.listGroups(func(model.Group) bool { return true })
+}
+
+func (r *memoryGroupRepository) GetGroupsPastCutoff(date time.Time) ([]model.Group, error) {
+	day := date.Format("2006-01-02")
+	return r.listGroups(func(group model.Group) bool {
+		return group.Status == model.GroupActive && group.CutoffDate < day
+	})
+}
+
+func (r *memoryGroupRepository) GetGroupReservations(id string) ([]model.Reservation, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var reservations []model.Reservation
+	for _, res := range r.store.reservations {
+		if res.GroupID == id {
+			reservations = append(reservations, res)
+		}
+	}
+	sort.Slice(reservations, func(i, j int) bool {
+		a, b := reservations[i], reservations[j]
+		if a.CheckinExpected != b.CheckinExpected {
+			return a.CheckinExpected < b.CheckinExpected
+		}
+		if a.GuestName != b.GuestName {
+			return a.GuestName < b.GuestName
+		}
+		return a.ID < b.ID
+	})
+	return reservations, nil
+}
+
+func (r *memoryGroupRepository) ReleaseGroup(id string, at time.Time, actor model.Actor) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	before, ok := r.store.groups[id]
+	if !ok || before.Status != model.GroupActive {
+		return ErrGroupStatusChanged
+	}
+	group := before
+	group.Status, group.ReleasedAt = model.GroupReleased, &at
+	if err := r.store.appendAudit(model.AuditEntityGroup, id, model.AuditActionRelease, actor, &before, &group); err != nil {
+		return err
+	}
+	r.store.groups[id] = group
+	return nil
+}
+
+func (r *memoryGroupRepository) CancelGroup(id string, at time.Time, reason string, actor model.Actor) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	before, ok := r.store.groups[id]
+	if !ok || before.Status == model.GroupCanceled {
+		return ErrGroupStatusChanged
+	}
+	group := before
+	group.Status, group.CanceledAt, group.CanceledBy, group.CancellationReason = model.GroupCanceled, &at, actor.Username, reason
+	if err := r.store.appendAudit(model.AuditEntityGroup, id, model.AuditActionCancel, actor, &before, &group); err != nil {
+		return err
+	}
+	r.store.groups[id] = group
+	return nil
+}
+
+func (r *memoryGroupRepository) GetGroupFolio(id string) (model.GroupFolio, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	group, ok := r.store.groups[id]
+	if !ok {
+		return model.GroupFolio{}, nil
+	}
+	folio := model.GroupFolio{GroupID: id, Status: group.Status}
+	for _, res := range r.store.reservations {
+		if res.GroupID != id || !holdsRoom(res.Status) {
+			continue
+		}
+		for _, night := range res.Nights {
+			charge := model.FolioCharge{
+				ReservationID: res.ID,
+				Type:          model.FolioRoom,
+				Description:   roomChargeDescription,
+				Date:          night.Date,
+				Amount:        night.Price,
+			}
+			if posted, ok := r.store.roomCharges[res.ID+"/"+night.Date]; ok {
+				charge.PostedAt, charge.PostedBy = &posted.PostedAt, r.store.nightAudits[night.Date].ClosedBy
+			}
+			folio.Charges = append(folio.Charges, charge)
+		}
+		for _, tax := range res.Taxes {
+			if tax.Inclusive {
+				continue
+			}
+			charge := model.FolioCharge{
+				ReservationID: res.ID,
+				Type:          model.FolioTax,
+				Description:   tax.Name,
+				Date:          tax.Date,
+				Amount:        tax.Amount,
+			}
+			if posted, ok := r.store.roomCharges[res.ID+"/"+tax.Date]; ok {
+				charge.PostedAt, charge.PostedBy = &posted.PostedAt, r.store.nightAudits[tax.Date].ClosedBy
+			}
+			folio.Charges = append(folio.Charges, charge)
+		}
+	}
+	// como o ORDER BY do Postgres: as noites antes dos impostos de cada data
+	sort.SliceStable(folio.Charges, func(i, j int) bool {
+		a, b := folio.Charges[i], folio.Charges[j]
+		if a.Type != b.Type {
+			return a.Type == model.FolioRoom
+		}
+		if a.Date != b.Date {
+			return a.Date < b.Date
+		}
+		return a.ReservationID < b.ReservationID
+	})
+	for _, payment := range r.store.groupPayments {
+		if payment.GroupID == id {
+			folio.Payments = append(folio.Payments, payment)
+		}
+	}
+	return folio, nil
+}
+
+func (r *memoryGroupRepository) PostGroupPayment(payment model.Payment, actor model.Actor) (string, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.groups[payment.GroupID]; !ok {
+		return "", ErrGroupStatusChanged
+	}
+	if payment.Type == model.PaymentTypeRefund {
+		paid := model.NewMoney(0)
+		for _, p := range r.store.groupPayments {
+			if p.GroupID != payment.GroupID {
+				continue
+			}
+			if p.Type == model.PaymentTypeRefund {
+				paid = paid.Sub(p.Amount)
+			} else {
+				paid = paid.Add(p.Amount)
+			}
+		}
+		if payment.Amount.Cmp(paid) > 0 {
+			return "", ErrRefundExceedsPaid
+		}
+	}
+	payment.ID = uuid.NewString()
+	if err := r.store.appendAudit(model.AuditEntityGroup, payment.GroupID, model.AuditActionPostPayment, actor, nil, payment); err != nil {
+		return "", err
+	}
+	r.store.groupPayments = append(r.store.groupPayments, payment)
+	return payment.ID, nil
+}
+
+// listGroups lista os grupos que atendem a keep na ordem do Postgres
+func (r *memoryGroupRepository) listGroups(keep func(model.Group) bool) ([]model.Group, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	groups := []model.Group{}
+	for _, group := range r.store.groups {
+		if keep(group) {
+			groups = append(groups, r.store.withPickedUp(group))
+		}
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		a, b := groups[i], groups[j]
+		if a.CheckinExpected != b.CheckinExpected {
+			return a.CheckinExpected < b.CheckinExpected
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.ID < b.ID
+	})
+	return groups, nil
+}
+
+// withPickedUp copia o grupo com os blocos em ordem de tipo e o picked_up de cada um;
+// exige o lock
+func (s *MemoryStore) withPickedUp(group model.Group) model.Group {
+	blocks := make([]model.GroupBlock, 0, len(group.Blocks))
+	for _, block := range group.Blocks {
+		block.PickedUp = s.groupPicked(group.ID, block.RoomType, "", "")
+		blocks = append(blocks, block)
+	}
+	sort.Slice(blocks, func(i, j int) bool { return blocks[i].RoomType < blocks[j].RoomType })
+	group.Blocks = blocks
+	return group
+}
+
+// ---------------- NIGHT AUDITS ----------------
+
+type memoryNightAuditRepository struct {
+	store *MemoryStore
+}
+
+func (r *memoryNightAuditRepository) GetLastClosedDate() (string, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	return r.store.lastClosedDate(), nil
+}
+
+func (r *memoryNightAuditRepository) GetNightAudit(date string) (model.NightAudit, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	return r.store.nightAudits[date], nil
+}
+
+func (r *memoryNightAuditRepository) GetNightAudits() ([]model.NightAudit, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var audits []model.NightAudit
+	for _, audit := range r.store.nightAudits {
+		audits = append(audits, audit)
+	}
+	sort.Slice(audits, func(i, j int) bool { return audits[i].BusinessDate > audits[j].BusinessDate })
+	return audits, nil
+}
+
+func (r *memoryNightAuditRepository) CloseBusinessDate(audit model.NightAudit, charges []model.RoomCharge) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if last := r.store.lastClosedDate(); last != "" && audit.BusinessDate <= last {
+		return ErrBusinessDateClosed
+	}
+	for _, charge := range charges {
+		if _, ok := r.store.reservations[charge.ReservationID]; !ok {
+			return fmt.Errorf("reservation %s does not exist", charge.ReservationID)
+		}
+	}
+	r.store.nightAudits[audit.BusinessDate] = audit
+	for _, charge := range charges {
+		key := charge.ReservationID + "/" + charge.BusinessDate
+		if _, ok := r.store.roomCharges[key]; !ok {
+			r.store.roomCharges[key] = charge
+		}
+	}
+	return nil
+}
+
+// lastClosedDate segue o contrato de GetLastClosedDate; exige o lock
+func (s *MemoryStore) lastClosedDate() string {
+	var last string
+	for date := range s.nightAudits {
+		if date > last {
+			last = date
+		}
+	}
+	return last
+}
+
+// ---------------- HELPERS ----------------
+
+// roomReservations resume as reservas do quarto que atendem a keep; exige o lock
+func (s *MemoryStore) roomReservations(roomID string, keep func(model.Reservation) bool) []model.ReservationRef {
+	var refs []model.ReservationRef
+	for _, res := range s.reservations {
+		if res.RoomID == roomID && keep(res) {
+			refs = append(refs, model.ReservationRef{
+				ID:               res.ID,
+				CheckinExpected:  res.CheckinExpected,
+				CheckoutExpected: res.CheckoutExpected,
+				Status:           res.Status,
+			})
+		}
+	}
+	sort.Slice(refs, func(i, j int) bool { return refs[i].CheckinExpected < refs[j].CheckinExpected })
+	return refs
+}
+
+// hasDocument reproduz o índice único de document_number, que ignora valores vazios; exige o lock
+func (s *MemoryStore) hasDocument(document, excludeID string) bool {
+	if document == "" {
+		return false
+	}
+	for _, guest := range s.guests {
+		if guest.ID != excludeID && guest.DocumentNumber == document {
+			return true
+		}
+	}
+	return false
+}
+
+// checkReservation aplica as FKs, as constraints de datas e reservations_no_overlap e as
+// checagens de bloqueios e do estoque do tipo de withRoomLock; exige o lock de escrita
+func (s *MemoryStore) checkReservation(res model.Reservation, excludeID string) error {
+	if res.RoomID != "" {
+		room, ok := s.rooms[res.RoomID]
+		if !ok {
+			return roomMismatch(res.RoomID, "", res.RoomType)
+		}
+		if room.Type != res.RoomType {
+			return roomMismatch(res.RoomID, room.Type, res.RoomType)
+		}
+	}
+	if _, ok := s.guests[res.GuestID]; !ok {
+		return fmt.Errorf("guest %s does not exist", res.GuestID)
+	}
+	if !holdsRoom(res.Status) {
+		return nil
+	}
+	checkin, checkout, err := parseStoredDates(res.CheckinExpected, res.CheckoutExpected)
+	if err != nil {
+		return err
+	}
+	if !checkout.After(checkin) {
+		return fmt.Errorf("checkout_expected must be after checkin_expected")
+	}
+	if res.RoomID != "" {
+		conflictID, err := s.findReservationConflict(res.RoomID, checkin, checkout, excludeID)
+		if err != nil {
+			return err
+		}
+		if conflictID != "" {
+			return &model.ReservationConflictError{RoomID: res.RoomID, ReservationID: conflictID}
+		}
+		// um bloqueio ativo conta como reserva, menos o que está sendo convertido
+		hold, err := s.findHoldConflict(res.RoomID, checkin, checkout, res.HoldID)
+		if err != nil {
+			return err
+		}
+		if hold.ID != "" {
+			return &model.RoomHeldError{RoomID: res.RoomID, HoldID: hold.ID, ExpiresAt: hold.ExpiresAt}
+		}
+	}
+	// como countedInInventory, a reserva já contada com o mesmo tipo e as mesmas datas
+	// não é conferida de novo
+	current, ok := s.reservations[excludeID]
+	if ok && holdsRoom(current.Status) && current.RoomType == res.RoomType &&
+		current.CheckinExpected == res.CheckinExpected && current.CheckoutExpected == res.CheckoutExpected {
+		return nil
+	}
+	covered, err := s.groupAllotment(res, excludeID)
+	if err != nil || covered {
+		return err
+	}
+	return s.checkTypeInventory(res.RoomType, checkin, checkout, excludeID, res.HoldID)
+}
+
+// checkTypeInventory recusa o período em que alguma noite já tem todos os quartos do
+// tipo, somados os extras do overbooking, ocupados; exige o lock
+func (s *MemoryStore) checkTypeInventory(roomType string, checkin, checkout time.Time, excludeID, excludeHoldID string) error {
+	free, night := s.typeHeadroom(roomType, s.roomTypeTotals()[roomType], checkin, checkout, excludeID, excludeHoldID)
+	if free <= 0 {
+		return &model.RoomTypeSoldOutError{RoomType: roomType, Date: night}
+	}
+	return nil
+}
+
+// roomTypeTotals conta, para cada tipo com quarto não arquivado, os quartos que entram no
+// estoque: só os ATIVO, os mesmos que o check-in e rankRooms atribuem; exige o lock
+func (s *MemoryStore) roomTypeTotals() map[string]int {
+	totals := map[string]int{}
+	for _, room := range s.rooms {
+		if room.DeletedAt != nil {
+			continue
+		}
+		if _, ok := totals[room.Type]; !ok {
+			totals[room.Type] = 0
+		}
+		if room.Status == "ATIVO" {
+			totals[room.Type]++
+		}
+	}
+	return totals
+}
+
+// typeHeadroom retorna quantas reservas do tipo ainda cabem na noite mais apertada do
+// período, e que noite é essa: os total quartos mais os extras do overbooking, menos as
+// reservas ativas, com ou sem quarto, os bloqueios ativos em quartos do tipo e os quartos
+// ainda não usados dos blocos de grupo ACTIVE; exige o lock
+func (s *MemoryStore) typeHeadroom(roomType string, total int, checkin, checkout time.Time, excludeID, excludeHoldID string) (int, string) {
+	now := time.Now()
+	least, leastNight := total, ""
+	for night := checkin; night.Before(checkout); night = night.AddDate(0, 0, 1) {
+		day := night.Format("2006-01-02")
+		free := total + s.overbookingAllowance(roomType, total, day)
+		for _, res := range s.reservations {
+			if res.RoomType == roomType && res.ID != excludeID && holdsRoom(res.Status) &&
+				res.CheckinExpected <= day && res.CheckoutExpected > day {
+				free--
+			}
+		}
+		for _, hold := range s.holds {
+			if s.rooms[hold.RoomID].Type == roomType && hold.ID != excludeHoldID && hold.Active(now) &&
+				hold.CheckinExpected <= day && hold.CheckoutExpected > day {
+				free--
+			}
+		}
+		free -= s.groupBlocked(roomType, day, excludeID)
+		if leastNight == "" || free < least {
+			least, leastNight = free, day
+		}
+	}
+	return least, leastNight
+}
+
+// groupBlocked reproduz groupBlockSQL: os quartos dos blocos de grupo ACTIVE do tipo
+// ainda não usados na noite day; exige o lock
+func (s *MemoryStore) groupBlocked(roomType, day, excludeID string) int {
+	blocked := 0
+	for _, group := range s.groups {
+		block, ok := group.Block(roomType)
+		if !ok || group.Status != model.GroupActive || group.CheckinExpected > day || group.CheckoutExpected <= day {
+			continue
+		}
+		blocked += max(block.Rooms-s.groupPicked(group.ID, roomType, day, excludeID), 0)
+	}
+	return blocked
+}
+
+// groupPicked conta as reservas ativas do grupo no tipo na noite day, ou em qualquer
+// noite quando day é vazio; exige o lock
+func (s *MemoryStore) groupPicked(groupID, roomType, day, excludeID string) int {
+	picked := 0
+	for _, res := range s.reservations {
+		if res.GroupID == groupID && res.RoomType == roomType && res.ID != excludeID && holdsRoom(res.Status) &&
+			(day == "" || (res.CheckinExpected <= day && res.CheckoutExpected > day)) {
+			picked++
+		}
+	}
+	return picked
+}
+
+// groupAllotment segue o groupAllotment do Postgres; exige o lock
+func (s *MemoryStore) groupAllotment(res model.Reservation, excludeID string) (bool, error) {
+	if res.GroupID == "" {
+		return false, nil
+	}
+	group, ok := s.groups[res.GroupID]
+	if !ok || group.Status == model.GroupCanceled {
+		if excludeID == "" {
+			return false, ErrGroupCanceled
+		}
+		return false, nil
+	}
+	block, ok := group.Block(res.RoomType)
+	if group.Status != model.GroupActive || !ok || !group.Covers(res.CheckinExpected, res.CheckoutExpected) {
+		return false, nil
+	}
+	checkin, checkout, err := parseStoredDates(res.CheckinExpected, res.CheckoutExpected)
+	if err != nil {
+		return false, err
+	}
+	for night := checkin; night.Before(checkout); night = night.AddDate(0, 0, 1) {
+		if s.groupPicked(group.ID, res.RoomType, night.Format("2006-01-02"), excludeID) >= block.Rooms {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// findReservationConflict retorna o ID de uma reserva ativa que colide com o período; exige o lock
+func (s *MemoryStore) findReservationConflict(roomID string, checkin, checkout time.Time, excludeID string) (string, error) {
+	for _, res := range s.reservations {
+		if res.RoomID != roomID || res.ID == excludeID || !holdsRoom(res.Status) {
+			continue
+		}
+		start, end, err := parseStoredDates(res.CheckinExpected, res.CheckoutExpected)
+		if err != nil {
+			return "", err
+		}
+		if overlaps(start, end, checkin, checkout) {
+			return res.ID, nil
+		}
+	}
+	return "", nil
+}
+
+// findHoldConflict retorna um bloqueio ativo do quarto que colide com o período, exceto
+// excludeID; vazio quando não há. Exige o lock
+func (s *MemoryStore) findHoldConflict(roomID string, checkin, checkout time.Time, excludeID string) (model.RoomHold, error) {
+	now := time.Now()
+	for _, hold := range s.holds {
+		if hold.RoomID != roomID || hold.ID == excludeID || !hold.Active(now) {
+			continue
+		}
+		start, end, err := parseStoredDates(hold.CheckinExpected, hold.CheckoutExpected)
+		if err != nil {
+			return model.RoomHold{}, err
+		}
+		if overlaps(start, end, checkin, checkout) {
+			return hold, nil
+		}
+	}
+	return model.RoomHold{}, nil
+}
+
+// overlaps reproduz o operador OVERLAPS do Postgres: cada período é tratado como
+// o intervalo semiaberto [início, fim), com as pontas invertidas se vierem fora de
+// ordem, e um período de início igual ao fim representa um único instante
+func overlaps(start1, end1, start2, end2 time.Time) bool {
+	if end1.Before(start1) {
+		start1, end1 = end1, start1
+	}
+	if end2.Before(start2) {
+		start2, end2 = end2, start2
+	}
+	switch {
+	case start1.After(start2):
+		return start1.Before(end2)
+	case start1.Before(start2):
+		return start2.Before(end1)
+	default:
+		return true
+	}
+}
+
+// parseStoredDates interpreta as datas como o Postgres as receberia numa coluna DATE
+func parseStoredDates(checkinStr, checkoutStr string) (time.Time, time.Time, error) {
+	checkin, err := parseStoredDate(checkinStr)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	checkout, err := parseStoredDate(checkoutStr)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	return checkin, checkout, nil
+}
+
+func parseStoredDate(value string) (time.Time, error) {
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid stored date %q", value)
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC), nil
+}
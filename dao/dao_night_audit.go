@@ -0,0 +1,217 @@
+package dao
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hotel-soa/model"
+	"time"
+)
+
+var (
+	// ErrBusinessDateClosed indica que o night audit da data já foi executado
+	ErrBusinessDateClosed = errors.New("business date already closed by the night audit")
+	// ErrNightsClosed indica uma alteração em noites já fechadas pelo night audit
+	ErrNightsClosed = errors.New("reservation nights already closed by the night audit cannot change")
+)
+
+// nightAuditLockKey identifica o advisory lock que serializa os fechamentos
+const nightAuditLockKey int64 = 727002
+
+type postgresNightAuditRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresNightAuditRepository cria um NightAuditRepository apoiado no Postgres
+func NewPostgresNightAuditRepository(conn *sql.DB) NightAuditRepository {
+	return &postgresNightAuditRepository{db: conn}
+}
+
+func (r *postgresNightAuditRepository) GetLastClosedDate() (string, error) {
+	return lastClosedDate(r.db)
+}
+
+func (r *postgresNightAuditRepository) GetNightAudit(date string) (model.NightAudit, error) {
+	audit, err := scanNightAudit(r.db.QueryRow(`SELECT `+nightAuditColumns+` FROM night_audits WHERE business_date = $1;`, date))
+	if err == sql.ErrNoRows {
+		return model.NightAudit{}, nil
+	}
+	return audit, err
+}
+
+func (r *postgresNightAuditRepository) GetNightAudits() ([]model.NightAudit, error) {
+	rows, err := r.db.Query(`SELECT ` + nightAuditColumns + ` FROM night_audits ORDER BY business_date DESC;`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var audits []model.NightAudit
+	for rows.Next() {
+		audit, err := scanNightAudit(rows)
+		if err != nil {
+			return nil, err
+		}
+		audits = append(audits, audit)
+	}
+	return audits, rows.Err()
+}
+
+// CloseBusinessDate grava o fechamento e as diárias numa transação. O advisory lock
+// serializa execuções concorrentes, e a data precisa ser posterior à última fechada
+func (r *postgresNightAuditRepository) CloseBusinessDate(audit model.NightAudit, charges []model.RoomCharge) error {
+	return withTx(r.db, func(tx *sql.Tx) error {
+		if _, err := tx.Exec("SELECT pg_advisory_xact_lock($1);", nightAuditLockKey); err != nil {
+			return err
+		}
+		last, err := lastClosedDate(tx)
+		if err != nil {
+			return err
+		}
+		if last != "" && audit.BusinessDate <= last {
+			return ErrBusinessDateClosed
+		}
+
+		summary, err := json.Marshal(audit.Summary)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(`INSERT INTO night_audits (business_date, closed_at, closed_by, charges_posted, summary)
+			VALUES ($1, $2, $3, $4, $5::jsonb);`,
+			audit.BusinessDate, audit.ClosedAt, audit.ClosedBy, audit.ChargesPosted, string(summary))
+		if err != nil {
+			return err
+		}
+		for _, charge := range charges {
+			_, err := tx.Exec(`INSERT INTO room_charges (reservation_id, business_date, amount, rate_plan_id, posted_at)
+				VALUES ($1, $2, $3, NULLIF($4, ''), $5)
+				ON CONFLICT (reservation_id, business_date) DO NOTHING;`,
+				charge.ReservationID, charge.BusinessDate, charge.Amount, charge.RatePlanID, charge.PostedAt)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ---------------- HELPERS ----------------
+
+const nightAuditColumns = `business_date, closed_at, closed_by, charges_posted, summary`
+
+func scanNightAudit(row rowScanner) (model.NightAudit, error) {
+	var audit model.NightAudit
+	var date time.Time
+	var summary []byte
+	if err := row.Scan(&date, &audit.ClosedAt, &audit.ClosedBy, &audit.ChargesPosted, &summary); err != nil {
+		return model.NightAudit{}, err
+	}
+	audit.BusinessDate = date.Format("2006-01-02")
+	if err := json.Unmarshal(summary, &audit.Summary); err != nil {
+		return model.NightAudit{}, err
+	}
+	return audit, nil
+}
+
+// lastClosedDate retorna a última data fechada (YYYY-MM-DD), vazio quando nenhuma foi
+func lastClosedDate(q queryer) (string, error) {
+	var last sql.NullTime
+	if err := q.QueryRow("SELECT MAX(business_date) FROM night_audits;").Scan(&last); err != nil {
+		return "", err
+	}
+	if !last.Valid {
+		return "", nil
+	}
+	return last.Time.Format("2006-01-02"), nil
+}
+
+// sharedLastClosedDate lê a última data fechada segurando o advisory lock em modo
+// compartilhado, para que nenhum night audit feche uma data no meio da transação
+func sharedLastClosedDate(tx *sql.Tx) (string, error) {
+	if _, err := tx.Exec("SELECT pg_advisory_xact_lock_shared($1);", nightAuditLockKey); err != nil {
+		return "", err
+	}
+	return lastClosedDate(tx)
+}
+
+// checkClosedNights recusa a alteração se as noites até closed (inclusive) mudarem de
+// data, de preço, de impostos ou de quarto. nil em after.Nights e em after.Taxes
+// significa noites e impostos inalterados
+func checkClosedNights(before, after model.Reservation, closed string) error {
+	if closed == "" {
+		return nil
+	}
+	if after.Nights == nil {
+		after.Nights = before.Nights
+	}
+	if after.Taxes != nil && !sameTaxes(closedTaxes(before.Taxes, closed), closedTaxes(after.Taxes, closed)) {
+		return fmt.Errorf("%w (closed up to %s)", ErrNightsClosed, closed)
+	}
+	frozenBefore, err := closedNights(before, closed)
+	if err != nil {
+		return err
+	}
+	frozenAfter, err := closedNights(after, closed)
+	if err != nil {
+		return err
+	}
+	if len(frozenBefore) == 0 && len(frozenAfter) == 0 {
+		return nil
+	}
+	if before.RoomID != after.RoomID || len(frozenBefore) != len(frozenAfter) {
+		return fmt.Errorf("%w (closed up to %s)", ErrNightsClosed, closed)
+	}
+	for i := range frozenBefore {
+		if frozenBefore[i].Date != frozenAfter[i].Date || !frozenBefore[i].Price.Equal(frozenAfter[i].Price) {
+			return fmt.Errorf("%w (closed up to %s)", ErrNightsClosed, closed)
+		}
+	}
+	return nil
+}
+
+// closedTaxes lista os impostos das noites até closed
+func closedTaxes(taxes []model.TaxLine, closed string) []model.TaxLine {
+	var frozen []model.TaxLine
+	for _, tax := range taxes {
+		if tax.Date <= closed {
+			frozen = append(frozen, tax)
+		}
+	}
+	return frozen
+}
+
+// sameTaxes compara duas listas de impostos na ordem em que são gravadas (noite e código)
+func sameTaxes(a, b []model.TaxLine) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].TaxRuleID != b[i].TaxRuleID || a[i].Date != b[i].Date || a[i].Inclusive != b[i].Inclusive || !a[i].Amount.Equal(b[i].Amount) {
+			return false
+		}
+	}
+	return true
+}
+
+// closedNights lista as noites da estadia até closed, com o preço quando há detalhamento
+func closedNights(res model.Reservation, closed string) ([]model.NightlyRate, error) {
+	checkin, checkout, err := parseStoredDates(res.CheckinExpected, res.CheckoutExpected)
+	if err != nil {
+		return nil, err
+	}
+	prices := make(map[string]model.Money, len(res.Nights))
+	for _, night := range res.Nights {
+		prices[night.Date] = night.Price
+	}
+
+	var nights []model.NightlyRate
+	for night := checkin; night.Before(checkout); night = night.AddDate(0, 0, 1) {
+		date := night.Format("2006-01-02")
+		if date > closed {
+			break
+		}
+		nights = append(nights, model.NightlyRate{Date: date, Price: prices[date]})
+	}
+	return nights, nil
+}
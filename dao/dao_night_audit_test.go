@@ -0,0 +1,111 @@
+package dao
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"hotel-soa/model"
+)
+
+func TestNightAuditRepository(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, repos Repositories) {
+		if last, err := repos.NightAudits.GetLastClosedDate(); err != nil || last != "" {
+			t.Fatalf("expected no closed date, got %q, %v", last, err)
+		}
+
+		room := insertTestRoom(t, repos, "STANDARD")
+		res := insertTestReservation(t, repos, room.ID, "2026-06-10", "2026-06-12")
+		closedAt := time.Date(2026, 6, 11, 3, 0, 0, 0, time.UTC)
+		audit := model.NightAudit{BusinessDate: "2026-06-10", ClosedAt: closedAt, ClosedBy: "maria", ChargesPosted: 1,
+			Summary: model.DailySummary{RoomsAvailable: 1, RoomsOccupied: 1, OccupancyRate: 100, RoomRevenue: model.NewMoney(10000), ADR: model.NewMoney(10000), Arrivals: 1}}
+		charges := []model.RoomCharge{{ReservationID: res.ID, BusinessDate: "2026-06-10", Amount: model.NewMoney(10000), PostedAt: closedAt}}
+		if err := repos.NightAudits.CloseBusinessDate(audit, charges); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := repos.NightAudits.GetNightAudit("2026-06-10")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.BusinessDate != audit.BusinessDate || !got.ClosedAt.Equal(closedAt) || got.ClosedBy != "maria" || got.Summary != audit.Summary {
+			t.Fatalf("expected %+v, got %+v", audit, got)
+		}
+		if got, err := repos.NightAudits.GetNightAudit("2026-06-11"); err != nil || got.BusinessDate != "" {
+			t.Fatalf("expected an empty audit for an open date, got %+v, %v", got, err)
+		}
+		if last, err := repos.NightAudits.GetLastClosedDate(); err != nil || last != "2026-06-10" {
+			t.Fatalf("expected 2026-06-10, got %q, %v", last, err)
+		}
+
+		// a mesma data ou uma anterior não fecham de novo
+		for _, date := range []string{"2026-06-10", "2026-06-09"} {
+			if err := repos.NightAudits.CloseBusinessDate(model.NightAudit{BusinessDate: date, ClosedAt: closedAt}, nil); !errors.Is(err, ErrBusinessDateClosed) {
+				t.Fatalf("%s: expected ErrBusinessDateClosed, got %v", date, err)
+			}
+		}
+		if err := repos.NightAudits.CloseBusinessDate(model.NightAudit{BusinessDate: "2026-06-11", ClosedAt: closedAt}, nil); err != nil {
+			t.Fatal(err)
+		}
+		audits, err := repos.NightAudits.GetNightAudits()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(audits) != 2 || audits[0].BusinessDate != "2026-06-11" || audits[1].BusinessDate != "2026-06-10" {
+			t.Fatalf("expected the most recent audit first, got %+v", audits)
+		}
+
+		// as noites fechadas não mudam mais; o resto da reserva sim
+		moved := res
+		moved.CheckinExpected, moved.CheckoutExpected = "2026-06-11", "2026-06-13"
+		if err := repos.Reservations.UpdateReservation(moved, testActor); !errors.Is(err, ErrNightsClosed) {
+			t.Fatalf("expected ErrNightsClosed, got %v", err)
+		}
+		res.GuestName = "Renamed"
+		if err := repos.Reservations.UpdateReservation(res, testActor); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+func TestGetReservationsForDate(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, repos Repositories) {
+		room := insertTestRoom(t, repos, "STANDARD")
+		other := insertTestRoom(t, repos, "STANDARD")
+		departing := newTestReservation(t, repos, room.ID, "2026-06-08", "2026-06-10")
+		departing.Nights = []model.NightlyRate{{Date: "2026-06-08", Price: model.NewMoney(10000)}, {Date: "2026-06-09", Price: model.NewMoney(10000)}}
+		if _, err := repos.Reservations.InsertReservation(departing, testActor); err != nil {
+			t.Fatal(err)
+		}
+		arriving := insertTestReservation(t, repos, room.ID, "2026-06-10", "2026-06-12")
+		staying := newTestReservation(t, repos, other.ID, "2026-06-09", "2026-06-11")
+		staying.Nights = []model.NightlyRate{{Date: "2026-06-09", Price: model.NewMoney(12000)}, {Date: "2026-06-10", Price: model.NewMoney(13000)}}
+		var err error
+		if staying.ID, err = repos.Reservations.InsertReservation(staying, testActor); err != nil {
+			t.Fatal(err)
+		}
+		insertTestReservation(t, repos, other.ID, "2026-06-11", "2026-06-12")
+
+		found, err := repos.Reservations.GetReservationsForDate(date("2026-06-10"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		// ordenadas pela chegada; cada uma vem com as suas noites
+		if len(found) != 3 || found[1].ID != staying.ID || found[2].ID != arriving.ID {
+			t.Fatalf("expected the departing, staying and arriving reservations, got %+v", found)
+		}
+		for i, want := range [][]string{{"2026-06-08", "2026-06-09"}, {"2026-06-09", "2026-06-10"}, nil} {
+			var dates []string
+			for _, night := range found[i].Nights {
+				dates = append(dates, day(night.Date))
+			}
+			if !reflect.DeepEqual(dates, want) {
+				t.Fatalf("expected nights %v for %s, got %v", want, found[i].ID, dates)
+			}
+		}
+		if !found[1].Nights[1].Price.Equal(model.NewMoney(13000)) {
+			t.Fatalf("expected the staying reservation's own prices, got %+v", found[1].Nights)
+		}
+	})
+}
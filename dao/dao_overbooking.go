@@ -0,0 +1,139 @@
+package dao
+
+import (
+	"database/sql"
+	"errors"
+	"hotel-soa/model"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrOverbookingRuleOverlap indica que outra regra do mesmo tipo já cobre alguma das noites
+var ErrOverbookingRuleOverlap = errors.New("another overbooking rule for the room type covers some of these nights")
+
+type postgresOverbookingRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresOverbookingRepository cria um OverbookingRepository apoiado no Postgres
+func NewPostgresOverbookingRepository(conn *sql.DB) OverbookingRepository {
+	return &postgresOverbookingRepository{db: conn}
+}
+
+const overbookingRuleColumns = `id, name, room_type, start_date, end_date, percent`
+
+func (r *postgresOverbookingRepository) InsertOverbookingRule(rule model.OverbookingRule) (string, error) {
+	id := uuid.NewString()
+	query := `INSERT INTO overbooking_rules (` + overbookingRuleColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6);`
+	_, err := r.db.Exec(query, id, rule.Name, rule.RoomType, rule.StartDate, rule.EndDate, rule.Percent)
+	if err != nil {
+		return "", mapOverbookingRuleError(err)
+	}
+	return id, nil
+}
+
+func (r *postgresOverbookingRepository) UpdateOverbookingRule(rule model.OverbookingRule) error {
+	query := `UPDATE overbooking_rules
+		SET name = $1, room_type = $2, start_date = $3, end_date = $4, percent = $5
+		WHERE id = $6;`
+	_, err := r.db.Exec(query, rule.Name, rule.RoomType, rule.StartDate, rule.EndDate, rule.Percent, rule.ID)
+	return mapOverbookingRuleError(err)
+}
+
+func (r *postgresOverbookingRepository) DeleteOverbookingRule(id string) error {
+	_, err := r.db.Exec("DELETE FROM overbooking_rules WHERE id = $1;", id)
+	return err
+}
+
+func (r *postgresOverbookingRepository) GetAllOverbookingRules() ([]model.OverbookingRule, error) {
+	rows, err := r.db.Query(`SELECT ` + overbookingRuleColumns + ` FROM overbooking_rules ORDER BY room_type, start_date;`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rules []model.OverbookingRule
+	for rows.Next() {
+		rule, err := scanOverbookingRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, rows.Err()
+}
+
+func (r *postgresOverbookingRepository) GetOverbookingRuleByID(id string) (model.OverbookingRule, error) {
+	query := `SELECT ` + overbookingRuleColumns + ` FROM overbooking_rules WHERE id = $1;`
+	rule, err := scanOverbookingRule(r.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return model.OverbookingRule{}, nil
+		}
+		return model.OverbookingRule{}, err
+	}
+	return rule, nil
+}
+
+func (r *postgresOverbookingRepository) GetRoomTypeNights(from, to time.Time) ([]model.RoomTypeNight, error) {
+	query := `
+		WITH types AS (` + roomTypeTotalsSQL + `)
+		SELECT d::date, t.type, t.total,
+		       t.total + ` + overbookingAllowanceSQL("t.type", "t.total") + `,
+		       (SELECT COUNT(*) FROM reservations
+		         WHERE room_type = t.type AND status NOT IN ('CANCELED', 'NO_SHOW')
+		           AND checkin_expected <= d::date AND checkout_expected > d::date),
+		       (SELECT COUNT(*) FROM room_holds h JOIN rooms r ON r.id = h.room_id
+		         WHERE r.type = t.type AND h.expires_at > now()
+		           AND h.checkin_expected <= d::date AND h.checkout_expected > d::date),
+		       ` + groupBlockSQL("t.type", "''") + `
+		FROM generate_series($1::date, $2::date, interval '1 day') d CROSS JOIN types t
+		ORDER BY d, t.type;`
+	rows, err := r.db.Query(query, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var nights []model.RoomTypeNight
+	for rows.Next() {
+		var night model.RoomTypeNight
+		var date time.Time
+		if err := rows.Scan(&date, &night.RoomType, &night.TotalRooms, &night.Limit, &night.Booked, &night.Held, &night.Blocked); err != nil {
+			return nil, err
+		}
+		night.Date = date.Format("2006-01-02")
+		nights = append(nights, night)
+	}
+	return nights, rows.Err()
+}
+
+// ---------------- HELPERS ----------------
+
+// overbookingAllowanceSQL é a expressão dos quartos extras do tipo na noite d da
+// generate_series, como model.OverbookingAllowance
+func overbookingAllowanceSQL(roomType, total string) string {
+	return `COALESCE((SELECT MAX(floor(` + total + ` * o.percent / 100)) FROM overbooking_rules o
+		         WHERE o.room_type = ` + roomType + ` AND d::date BETWEEN o.start_date AND o.end_date), 0)::int`
+}
+
+func scanOverbookingRule(row rowScanner) (model.OverbookingRule, error) {
+	var rule model.OverbookingRule
+	var start, end time.Time
+	if err := row.Scan(&rule.ID, &rule.Name, &rule.RoomType, &start, &end, &rule.Percent); err != nil {
+		return model.OverbookingRule{}, err
+	}
+	rule.StartDate = start.Format("2006-01-02")
+	rule.EndDate = end.Format("2006-01-02")
+	return rule, nil
+}
+
+// mapOverbookingRuleError traduz a violação de overbooking_rules_no_overlap
+func mapOverbookingRuleError(err error) error {
+	if isExclusionViolation(err) {
+		return ErrOverbookingRuleOverlap
+	}
+	return err
+}
@@ -0,0 +1,121 @@
+package dao
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"hotel-soa/model"
+)
+
+func TestOverbookingRuleRepository(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, repos Repositories) {
+		rule := model.OverbookingRule{Name: "Réveillon", RoomType: "DELUXE", StartDate: "2026-12-28", EndDate: "2027-01-02", Percent: 5}
+		id, err := repos.Overbooking.InsertOverbookingRule(rule)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rule.ID = id
+		if stored, err := repos.Overbooking.GetOverbookingRuleByID(id); err != nil || stored != rule {
+			t.Fatalf("expected %+v, got %+v (%v)", rule, stored, err)
+		}
+
+		// outra regra do mesmo tipo não pode cobrir as mesmas noites; a de outro tipo pode
+		overlapping := model.OverbookingRule{Name: "Ano novo", RoomType: "DELUXE", StartDate: "2027-01-02", EndDate: "2027-01-05", Percent: 10}
+		if _, err := repos.Overbooking.InsertOverbookingRule(overlapping); !errors.Is(err, ErrOverbookingRuleOverlap) {
+			t.Fatalf("expected ErrOverbookingRuleOverlap, got %v", err)
+		}
+		overlapping.RoomType = "SUITE"
+		suiteID, err := repos.Overbooking.InsertOverbookingRule(overlapping)
+		if err != nil {
+			t.Fatal(err)
+		}
+		overlapping.ID, overlapping.RoomType = suiteID, "DELUXE"
+		if err := repos.Overbooking.UpdateOverbookingRule(overlapping); !errors.Is(err, ErrOverbookingRuleOverlap) {
+			t.Fatalf("expected ErrOverbookingRuleOverlap on update, got %v", err)
+		}
+		overlapping.StartDate = "2027-01-03"
+		if err := repos.Overbooking.UpdateOverbookingRule(overlapping); err != nil {
+			t.Fatal(err)
+		}
+
+		rules, err := repos.Overbooking.GetAllOverbookingRules()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(rules) != 2 || rules[0].ID != id || rules[1].ID != suiteID {
+			t.Fatalf("expected the rules by type and start, got %+v", rules)
+		}
+
+		if err := repos.Overbooking.DeleteOverbookingRule(suiteID); err != nil {
+			t.Fatal(err)
+		}
+		if stored, err := repos.Overbooking.GetOverbookingRuleByID(suiteID); err != nil || stored.ID != "" {
+			t.Fatalf("expected an empty rule after delete, got %+v (%v)", stored, err)
+		}
+	})
+}
+
+func TestOverbookingInventory(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, repos Repositories) {
+		first := insertTestRoom(t, repos, "DELUXE")
+		insertTestRoom(t, repos, "DELUXE")
+		insertTestRoom(t, repos, "STANDARD")
+		// 50% de dois quartos: uma reserva a mais só na noite de 31
+		if _, err := repos.Overbooking.InsertOverbookingRule(model.OverbookingRule{Name: "Réveillon", RoomType: "DELUXE", StartDate: "2026-12-31", EndDate: "2026-12-31", Percent: 50}); err != nil {
+			t.Fatal(err)
+		}
+		unassigned := func(checkin, checkout string) (string, error) {
+			return repos.Reservations.InsertReservation(model.Reservation{RoomType: "DELUXE", GuestID: insertTestGuest(t, repos, "Test Guest"), GuestName: "Test Guest",
+				CheckinExpected: checkin, CheckoutExpected: checkout, Status: "CREATED", TotalAmount: model.NewMoney(20000)}, testActor)
+		}
+		for i := 0; i < 3; i++ {
+			if _, err := unassigned("2026-12-31", "2027-01-01"); err != nil {
+				t.Fatal(err)
+			}
+		}
+		var soldOut *model.RoomTypeSoldOutError
+		if _, err := unassigned("2026-12-31", "2027-01-01"); !errors.As(err, &soldOut) || soldOut.Date != "2026-12-31" {
+			t.Fatalf("expected a RoomTypeSoldOutError past the allowance, got %v", err)
+		}
+		// um quarto específico nunca recebe duas reservas
+		insertTestReservation(t, repos, first.ID, "2026-12-30", "2026-12-31")
+		var conflict *model.ReservationConflictError
+		if _, err := repos.Reservations.InsertReservation(newTestReservation(t, repos, first.ID, "2026-12-30", "2026-12-31"), testActor); !errors.As(err, &conflict) {
+			t.Fatalf("expected a ReservationConflictError, got %v", err)
+		}
+		if _, err := repos.Holds.InsertHold(model.RoomHold{RoomID: first.ID, TokenHash: "h", CheckinExpected: "2027-01-01", CheckoutExpected: "2027-01-02",
+			ExpiresAt: time.Now().Add(time.Hour)}); err != nil {
+			t.Fatal(err)
+		}
+
+		types, err := repos.Rooms.GetRoomTypeAvailability(date("2026-12-31"), date("2027-01-01"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(types) != 2 || types[0].RoomType != "DELUXE" || types[0].TotalRooms != 2 || types[0].Available != 0 {
+			t.Fatalf("expected DELUXE sold out with the allowance, got %+v", types)
+		}
+
+		nights, err := repos.Overbooking.GetRoomTypeNights(date("2026-12-30"), date("2027-01-01"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := []model.RoomTypeNight{
+			{Date: "2026-12-30", RoomType: "DELUXE", TotalRooms: 2, Limit: 2, Booked: 1},
+			{Date: "2026-12-30", RoomType: "STANDARD", TotalRooms: 1, Limit: 1},
+			{Date: "2026-12-31", RoomType: "DELUXE", TotalRooms: 2, Limit: 3, Booked: 3},
+			{Date: "2026-12-31", RoomType: "STANDARD", TotalRooms: 1, Limit: 1},
+			{Date: "2027-01-01", RoomType: "DELUXE", TotalRooms: 2, Limit: 2, Held: 1},
+			{Date: "2027-01-01", RoomType: "STANDARD", TotalRooms: 1, Limit: 1},
+		}
+		if len(nights) != len(expected) {
+			t.Fatalf("expected %+v, got %+v", expected, nights)
+		}
+		for i := range expected {
+			if nights[i] != expected[i] {
+				t.Fatalf("expected %+v, got %+v", expected[i], nights[i])
+			}
+		}
+	})
+}
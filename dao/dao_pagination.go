@@ -0,0 +1,135 @@
+package dao
+
+import (
+	"fmt"
+	"hotel-soa/model"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// sortColumn mapeia um campo de ordenação da API para a expressão SQL e o tipo
+// usado ao comparar o valor do cursor
+type sortColumn struct {
+	expr    string
+	cast    string
+	numeric bool
+}
+
+var roomSortColumns = map[string]sortColumn{
+	"number":          {expr: "number", cast: "int", numeric: true},
+	"type":            {expr: "type", cast: "text"},
+	"capacity":        {expr: "capacity", cast: "int", numeric: true},
+	"price_per_night": {expr: "price_per_night", cast: "numeric", numeric: true},
+}
+
+var reservationSortColumns = map[string]sortColumn{
+	"checkin_expected":  {expr: "checkin_expected", cast: "date"},
+	"checkout_expected": {expr: "checkout_expected", cast: "date"},
+	"guest_name":        {expr: "guest_name", cast: "text"},
+	"total_amount":      {expr: "COALESCE(total_amount, 0)", cast: "numeric", numeric: true},
+}
+
+// whereBuilder acumula condições e argumentos numerando os placeholders ($1, $2...)
+type whereBuilder struct {
+	clauses []string
+	args    []any
+}
+
+// add recebe uma condição com um %s por argumento
+func (w *whereBuilder) add(format string, args ...any) {
+	placeholders := make([]any, len(args))
+	for i, arg := range args {
+		w.args = append(w.args, arg)
+		placeholders[i] = "$" + strconv.Itoa(len(w.args))
+	}
+	w.clauses = append(w.clauses, fmt.Sprintf(format, placeholders...))
+}
+
+func (w *whereBuilder) sql() string {
+	if len(w.clauses) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(w.clauses, " AND ")
+}
+
+// addKeyset continua a listagem a partir do cursor, na direção da ordenação
+func (w *whereBuilder) addKeyset(col sortColumn, s model.Sort, cursor *model.Cursor) {
+	if cursor == nil {
+		return
+	}
+	op := ">"
+	if s.Desc {
+		op = "<"
+	}
+	w.add(fmt.Sprintf("(%s, id) %s (%%s::%s, %%s)", col.expr, op, col.cast), cursor.Value, cursor.ID)
+}
+
+// orderBy ordena pelo campo escolhido com o ID como desempate, o que torna o cursor estável
+func orderBy(col sortColumn, s model.Sort) string {
+	dir := "ASC"
+	if s.Desc {
+		dir = "DESC"
+	}
+	return fmt.Sprintf(" ORDER BY %s %s, id %s", col.expr, dir, dir)
+}
+
+// escapeLike protege os curingas de LIKE num prefixo informado pelo usuário
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
+
+// ---------------- MEMORY ----------------
+
+// compareSortValues compara dois valores de ordenação respeitando o tipo da coluna
+func compareSortValues(a, b string, numeric bool) int {
+	if numeric {
+		x, _ := strconv.ParseFloat(a, 64)
+		y, _ := strconv.ParseFloat(b, 64)
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+		return 0
+	}
+	return strings.Compare(a, b)
+}
+
+// paginate ordena os itens, aplica o cursor e corta em limit+1, reproduzindo a
+// consulta keyset do Postgres
+func paginate[T any](items []T, col sortColumn, page model.PageRequest, value func(T, string) string, id func(T) string) []T {
+	compare := func(a, b T) int {
+		c := compareSortValues(value(a, page.Sort.Field), value(b, page.Sort.Field), col.numeric)
+		if c == 0 {
+			c = strings.Compare(id(a), id(b))
+		}
+		if page.Sort.Desc {
+			return -c
+		}
+		return c
+	}
+	sort.Slice(items, func(i, j int) bool { return compare(items[i], items[j]) < 0 })
+
+	var result []T
+	for _, item := range items {
+		if page.Cursor != nil {
+			c := compareSortValues(value(item, page.Sort.Field), page.Cursor.Value, col.numeric)
+			if c == 0 {
+				c = strings.Compare(id(item), page.Cursor.ID)
+			}
+			if page.Sort.Desc {
+				c = -c
+			}
+			if c <= 0 {
+				continue
+			}
+		}
+		result = append(result, item)
+		if len(result) > page.Limit {
+			break
+		}
+	}
+	return result
+}
@@ -0,0 +1,156 @@
+package dao
+
+import (
+	"reflect"
+	"testing"
+
+	"hotel-soa/model"
+)
+
+// listAllRooms percorre as páginas seguindo o cursor como o service faz e devolve os números
+func listAllRooms(t *testing.T, repos Repositories, filter model.RoomFilter) ([]int, int) {
+	t.Helper()
+	var numbers []int
+	var total int
+	for pages := 0; ; pages++ {
+		if pages > 10 {
+			t.Fatal("pagination did not end")
+		}
+		rooms, count, err := repos.Rooms.ListRooms(filter)
+		if err != nil {
+			t.Fatal(err)
+		}
+		total = count
+		more := len(rooms) > filter.Page.Limit
+		if more {
+			rooms = rooms[:filter.Page.Limit]
+		}
+		for _, room := range rooms {
+			numbers = append(numbers, room.Number)
+		}
+		if !more {
+			return numbers, total
+		}
+		last := rooms[len(rooms)-1]
+		filter.Page.Cursor = &model.Cursor{Sort: filter.Page.Sort.String(), Value: last.SortValue(filter.Page.Sort.Field), ID: last.ID}
+	}
+}
+
+func TestListRooms(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, repos Repositories) {
+		for _, room := range []model.Room{
+			{Number: 101, Type: "STANDARD", Capacity: 2, PricePerNight: model.NewMoney(10000), Status: "ATIVO"},
+			{Number: 102, Type: "STANDARD", Capacity: 2, PricePerNight: model.NewMoney(11000), Status: "ATIVO"},
+			{Number: 103, Type: "DELUXE", Capacity: 3, PricePerNight: model.NewMoney(25050), Status: "ATIVO"},
+			{Number: 104, Type: "SUITE", Capacity: 4, PricePerNight: model.NewMoney(40000), Status: "INATIVO"},
+			{Number: 105, Type: "DELUXE", Capacity: 3, PricePerNight: model.NewMoney(9000), Status: "ATIVO"},
+		} {
+			if _, err := repos.Rooms.InsertRoom(room, testActor); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		tests := []struct {
+			name   string
+			filter model.RoomFilter
+			want   []int
+		}{
+			{"all by number", model.RoomFilter{}, []int{101, 102, 103, 104, 105}},
+			{"descending", model.RoomFilter{Page: model.PageRequest{Sort: model.Sort{Field: "number", Desc: true}}}, []int{105, 104, 103, 102, 101}},
+			{"status", model.RoomFilter{Status: "INATIVO"}, []int{104}},
+			{"type", model.RoomFilter{Type: "DELUXE"}, []int{103, 105}},
+			{"capacity range", model.RoomFilter{MinCapacity: 3, MaxCapacity: 3}, []int{103, 105}},
+			{"price range", model.RoomFilter{MinPrice: model.NewMoney(9500), MaxPrice: model.NewMoney(30000)}, []int{101, 102, 103}},
+			// preços numéricos, não texto: 90 < 100 < 110 < 250.5 < 400
+			{"by price", model.RoomFilter{Page: model.PageRequest{Sort: model.Sort{Field: "price_per_night"}}}, []int{105, 101, 102, 103, 104}},
+			// empate no tipo é desfeito pelo ID: cada quarto aparece uma vez entre as páginas
+			{"by type", model.RoomFilter{Type: "STANDARD", Page: model.PageRequest{Sort: model.Sort{Field: "type"}}}, nil},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				if tt.filter.Page.Sort.Field == "" {
+					tt.filter.Page.Sort.Field = "number"
+				}
+				tt.filter.Page.Limit = 2
+				got, total := listAllRooms(t, repos, tt.filter)
+				if tt.want == nil {
+					if len(got) != 2 || total != 2 || got[0] == got[1] {
+						t.Fatalf("expected both STANDARD rooms once, got %v (total %d)", got, total)
+					}
+					return
+				}
+				if !reflect.DeepEqual(got, tt.want) || total != len(tt.want) {
+					t.Fatalf("expected %v (total %d), got %v (total %d)", tt.want, len(tt.want), got, total)
+				}
+			})
+		}
+	})
+}
+
+func TestListReservations(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, repos Repositories) {
+		room := insertTestRoom(t, repos, "STANDARD")
+		other := insertTestRoom(t, repos, "STANDARD")
+		ids := map[string]string{}
+		for _, r := range []struct {
+			name, roomID, checkin, checkout string
+		}{
+			{"Ana Souza", room.ID, "2026-06-01", "2026-06-03"},
+			{"ana_maria", room.ID, "2026-06-05", "2026-06-08"},
+			{"Bruno Lima", other.ID, "2026-06-02", "2026-06-04"},
+			{"Anabela", other.ID, "2026-06-10", "2026-06-12"},
+		} {
+			res := newTestReservation(t, repos, r.roomID, r.checkin, r.checkout)
+			res.GuestName = r.name
+			id, err := repos.Reservations.InsertReservation(res, testActor)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ids[id] = r.name
+		}
+
+		tests := []struct {
+			name   string
+			filter model.ReservationFilter
+			want   []string
+		}{
+			{"all by checkin", model.ReservationFilter{}, []string{"Ana Souza", "Bruno Lima", "ana_maria", "Anabela"}},
+			{"room", model.ReservationFilter{RoomID: other.ID}, []string{"Bruno Lima", "Anabela"}},
+			{"name prefix ignores case", model.ReservationFilter{GuestNamePrefix: "ANA"}, []string{"Ana Souza", "ana_maria", "Anabela"}},
+			// _ é literal, não curinga do LIKE
+			{"name prefix with wildcard", model.ReservationFilter{GuestNamePrefix: "ana_"}, []string{"ana_maria"}},
+			{"stays touching the range", model.ReservationFilter{From: "2026-06-03", To: "2026-06-06"}, []string{"Bruno Lima", "ana_maria"}},
+			{"by checkout descending", model.ReservationFilter{Page: model.PageRequest{Sort: model.Sort{Field: "checkout_expected", Desc: true}}}, []string{"Anabela", "ana_maria", "Bruno Lima", "Ana Souza"}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				if tt.filter.Page.Sort.Field == "" {
+					tt.filter.Page.Sort.Field = "checkin_expected"
+				}
+				tt.filter.Page.Limit = 1
+				var got []string
+				for {
+					list, total, err := repos.Reservations.ListReservations(tt.filter)
+					if err != nil {
+						t.Fatal(err)
+					}
+					if total != len(tt.want) {
+						t.Fatalf("expected total %d, got %d", len(tt.want), total)
+					}
+					if len(list) == 0 {
+						break
+					}
+					res := list[0]
+					got = append(got, ids[res.ID])
+					if len(list) == 1 {
+						break
+					}
+					tt.filter.Page.Cursor = &model.Cursor{Value: res.SortValue(tt.filter.Page.Sort.Field), ID: res.ID}
+				}
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Fatalf("expected %v, got %v", tt.want, got)
+				}
+			})
+		}
+	})
+}
@@ -0,0 +1,119 @@
+package dao
+
+import (
+	"database/sql"
+	"errors"
+	"hotel-soa/model"
+
+	"github.com/google/uuid"
+)
+
+// ErrAuthorizationChanged indica que a autorização mudou de status entre a leitura e a escrita
+var ErrAuthorizationChanged = errors.New("payment authorization changed, reload and try again")
+
+// InsertAuthorization grava a tentativa de pré-autorização. Aprovada, confirma a reserva
+// na mesma transação, o que só vale enquanto ela está CREATED
+func (r *postgresFolioRepository) InsertAuthorization(auth model.PaymentAuthorization, actor model.Actor) (string, error) {
+	auth.ID = uuid.NewString()
+	err := withTx(r.db, func(tx *sql.Tx) error {
+		before, err := lockReservation(tx, auth.ReservationID)
+		if err != nil {
+			return err
+		}
+		if before == nil {
+			return ErrReservationStatusChanged
+		}
+		_, err = tx.Exec(`INSERT INTO payment_authorizations (`+authorizationColumns+`)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13);`,
+			auth.ID, auth.ReservationID, auth.Gateway, auth.Status, auth.Amount, auth.CapturedAmount, auth.RefundedAmount,
+			auth.Reference, auth.CaptureReference, auth.DeclineCode, auth.CreatedAt, auth.CreatedBy, auth.UpdatedAt)
+		if err != nil {
+			return err
+		}
+		if err := auditFolio(tx, auth.ReservationID, model.AuditActionPaymentAuthorization, actor, auth); err != nil {
+			return err
+		}
+		if auth.Status != model.AuthorizationAuthorized {
+			return nil
+		}
+		result, err := tx.Exec(`UPDATE reservations SET status = 'CONFIRMED', confirmed_at = $1
+			WHERE id = $2 AND status = 'CREATED';`, auth.CreatedAt, auth.ReservationID)
+		if err != nil {
+			return err
+		}
+		if err := requireAffected(result); err != nil {
+			return err
+		}
+		return auditReservation(tx, auth.ReservationID, model.AuditActionConfirm, actor, before)
+	})
+	if err != nil {
+		return "", err
+	}
+	return auth.ID, nil
+}
+
+// UpdateAuthorization trava a reserva, como PostPayment, para que capturas e estornos
+// concorrentes não se sobreponham
+func (r *postgresFolioRepository) UpdateAuthorization(auth model.PaymentAuthorization, from string, payment *model.Payment, actor model.Actor) error {
+	return withTx(r.db, func(tx *sql.Tx) error {
+		before, err := lockReservation(tx, auth.ReservationID)
+		if err != nil {
+			return err
+		}
+		if before == nil {
+			return ErrReservationStatusChanged
+		}
+		result, err := tx.Exec(`UPDATE payment_authorizations
+			SET status = $1, captured_amount = $2, refunded_amount = $3, capture_reference = $4, updated_at = $5
+			WHERE id = $6 AND reservation_id = $7 AND status = $8;`,
+			auth.Status, auth.CapturedAmount, auth.RefundedAmount, auth.CaptureReference, auth.UpdatedAt,
+			auth.ID, auth.ReservationID, from)
+		if err != nil {
+			return err
+		}
+		if n, err := result.RowsAffected(); err != nil {
+			return err
+		} else if n == 0 {
+			return ErrAuthorizationChanged
+		}
+		if err := auditFolio(tx, auth.ReservationID, model.AuditActionPaymentAuthorization, actor, auth); err != nil {
+			return err
+		}
+		if payment == nil {
+			return nil
+		}
+		payment.ID = uuid.NewString()
+		return insertPayment(tx, *payment, actor)
+	})
+}
+
+func (r *postgresFolioRepository) GetAuthorizations(reservationID string) ([]model.PaymentAuthorization, error) {
+	return getAuthorizations(r.db, reservationID)
+}
+
+// ---------------- HELPERS ----------------
+
+// authorizationColumns é a lista de colunas lida por getAuthorizations
+const authorizationColumns = `id, reservation_id, gateway, status, amount, captured_amount, refunded_amount,
+	reference, capture_reference, decline_code, created_at, created_by, updated_at`
+
+func getAuthorizations(q rowsQueryer, reservationID string) ([]model.PaymentAuthorization, error) {
+	rows, err := q.Query(`SELECT `+authorizationColumns+` FROM payment_authorizations
+		WHERE reservation_id = $1 ORDER BY created_at, id;`, reservationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var authorizations []model.PaymentAuthorization
+	for rows.Next() {
+		var auth model.PaymentAuthorization
+		if err := rows.Scan(&auth.ID, &auth.ReservationID, &auth.Gateway, &auth.Status, &auth.Amount,
+			&auth.CapturedAmount, &auth.RefundedAmount, &auth.Reference, &auth.CaptureReference,
+			&auth.DeclineCode, &auth.CreatedAt, &auth.CreatedBy, &auth.UpdatedAt); err != nil {
+			return nil, err
+		}
+		authorizations = append(authorizations, auth)
+	}
+	return authorizations, rows.Err()
+}
@@ -0,0 +1,80 @@
+package dao
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"hotel-soa/model"
+)
+
+func TestPaymentAuthorizations(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, repos Repositories) {
+		room := insertTestRoom(t, repos, "STANDARD")
+		res := insertTestReservation(t, repos, room.ID, "2026-06-10", "2026-06-12")
+		at := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+		newAuth := func(status string) model.PaymentAuthorization {
+			return model.PaymentAuthorization{ReservationID: res.ID, Gateway: "stub", Status: status, Amount: model.NewMoney(20000),
+				CapturedAmount: model.NewMoney(0), RefundedAmount: model.NewMoney(0), CreatedAt: at, CreatedBy: "maria", UpdatedAt: at}
+		}
+
+		// a recusa fica registrada e a reserva continua CREATED
+		declined := newAuth(model.AuthorizationDeclined)
+		declined.DeclineCode = "insufficient_funds"
+		if _, err := repos.Folios.InsertAuthorization(declined, testActor); err != nil {
+			t.Fatal(err)
+		}
+		if got, _ := repos.Reservations.GetReservationByID(res.ID); got.Status != "CREATED" || got.ConfirmedAt != nil {
+			t.Fatalf("expected a CREATED reservation after a decline, got %+v", got)
+		}
+
+		approved := newAuth(model.AuthorizationAuthorized)
+		approved.Reference = "stub_auth_1"
+		if approved.ID, _ = repos.Folios.InsertAuthorization(approved, testActor); approved.ID == "" {
+			t.Fatal("expected an authorization id")
+		}
+		got, err := repos.Reservations.GetReservationByID(res.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Status != "CONFIRMED" || got.ConfirmedAt == nil || !got.ConfirmedAt.Equal(at) {
+			t.Fatalf("expected a CONFIRMED reservation, got %+v", got)
+		}
+		// a reserva já não está CREATED
+		if _, err := repos.Folios.InsertAuthorization(newAuth(model.AuthorizationAuthorized), testActor); !errors.Is(err, ErrReservationStatusChanged) {
+			t.Fatalf("expected ErrReservationStatusChanged, got %v", err)
+		}
+
+		auths, err := repos.Folios.GetAuthorizations(res.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(auths) != 2 || auths[0].Status != model.AuthorizationDeclined || auths[0].DeclineCode != "insufficient_funds" ||
+			auths[1].ID != approved.ID || auths[1].Reference != "stub_auth_1" || !auths[1].Amount.Equal(model.NewMoney(20000)) {
+			t.Fatalf("unexpected authorizations %+v", auths)
+		}
+
+		// a captura lança o pagamento na mesma transação
+		captured := auths[1]
+		captured.Status, captured.CapturedAmount, captured.CaptureReference = model.AuthorizationCaptured, model.NewMoney(15000), "stub_cap_1"
+		payment := &model.Payment{ReservationID: res.ID, Type: model.PaymentTypePayment, Method: "CARD", Amount: model.NewMoney(15000),
+			Reference: "stub_cap_1", AuthorizationID: captured.ID, PostedAt: at, PostedBy: "maria"}
+		if err := repos.Folios.UpdateAuthorization(captured, model.AuthorizationAuthorized, payment, testActor); err != nil {
+			t.Fatal(err)
+		}
+		if err := repos.Folios.UpdateAuthorization(captured, model.AuthorizationAuthorized, nil, testActor); !errors.Is(err, ErrAuthorizationChanged) {
+			t.Fatalf("expected ErrAuthorizationChanged capturing twice, got %v", err)
+		}
+		folio, err := repos.Folios.GetFolio(res.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(folio.Payments) != 1 || folio.Payments[0].AuthorizationID != captured.ID || !folio.Payments[0].Amount.Equal(model.NewMoney(15000)) {
+			t.Fatalf("expected the capture as a CARD payment, got %+v", folio.Payments)
+		}
+		if auths, _ := repos.Folios.GetAuthorizations(res.ID); auths[1].Status != model.AuthorizationCaptured || auths[1].CaptureReference != "stub_cap_1" ||
+			!auths[1].Refundable().Equal(model.NewMoney(15000)) {
+			t.Fatalf("expected a CAPTURED authorization, got %+v", auths[1])
+		}
+	})
+}
@@ -0,0 +1,143 @@
+package dao
+
+import (
+	"database/sql"
+	"encoding/json"
+	"hotel-soa/model"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type postgresRatePlanRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresRatePlanRepository cria um RatePlanRepository apoiado no Postgres
+func NewPostgresRatePlanRepository(conn *sql.DB) RatePlanRepository {
+	return &postgresRatePlanRepository{db: conn}
+}
+
+const ratePlanColumns = `id, name, room_type, start_date, end_date, base_rate,
+		weekday_multipliers, min_stay, closed_to_arrival, priority, cancellation_policy_id`
+
+func (r *postgresRatePlanRepository) InsertRatePlan(plan model.RatePlan) (string, error) {
+	id := uuid.NewString()
+	multipliers, closed, err := encodeRatePlanRules(plan)
+	if err != nil {
+		return "", err
+	}
+	query := `INSERT INTO rate_plans (` + ratePlanColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, NULLIF($11, ''));`
+	_, err = r.db.Exec(query, id, plan.Name, plan.RoomType, plan.StartDate, plan.EndDate, plan.BaseRate,
+		multipliers, plan.MinStay, closed, plan.Priority, plan.CancellationPolicyID)
+	if err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+func (r *postgresRatePlanRepository) UpdateRatePlan(plan model.RatePlan) error {
+	multipliers, closed, err := encodeRatePlanRules(plan)
+	if err != nil {
+		return err
+	}
+	query := `UPDATE rate_plans
+		SET name = $1, room_type = $2, start_date = $3, end_date = $4, base_rate = $5,
+		    weekday_multipliers = $6, min_stay = $7, closed_to_arrival = $8, priority = $9,
+		    cancellation_policy_id = NULLIF($10, '')
+		WHERE id = $11;`
+	_, err = r.db.Exec(query, plan.Name, plan.RoomType, plan.StartDate, plan.EndDate, plan.BaseRate,
+		multipliers, plan.MinStay, closed, plan.Priority, plan.CancellationPolicyID, plan.ID)
+	return err
+}
+
+func (r *postgresRatePlanRepository) DeleteRatePlan(id string) error {
+	_, err := r.db.Exec("DELETE FROM rate_plans WHERE id = $1;", id)
+	return err
+}
+
+func (r *postgresRatePlanRepository) GetAllRatePlans() ([]model.RatePlan, error) {
+	query := `SELECT ` + ratePlanColumns + ` FROM rate_plans ORDER BY room_type, start_date;`
+	return r.queryRatePlans(query)
+}
+
+func (r *postgresRatePlanRepository) GetRatePlanByID(id string) (model.RatePlan, error) {
+	query := `SELECT ` + ratePlanColumns + ` FROM rate_plans WHERE id = $1;`
+	plan, err := scanRatePlan(r.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return model.RatePlan{}, nil
+		}
+		return model.RatePlan{}, err
+	}
+	return plan, nil
+}
+
+func (r *postgresRatePlanRepository) GetRatePlansForPeriod(roomType string, from, to time.Time) ([]model.RatePlan, error) {
+	query := `SELECT ` + ratePlanColumns + ` FROM rate_plans
+		WHERE room_type = $1 AND start_date <= $3::date AND end_date >= $2::date
+		ORDER BY priority DESC, start_date DESC;`
+	return r.queryRatePlans(query, roomType, from, to)
+}
+
+// ---------------- HELPERS ----------------
+
+func (r *postgresRatePlanRepository) queryRatePlans(query string, args ...any) ([]model.RatePlan, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var plans []model.RatePlan
+	for rows.Next() {
+		plan, err := scanRatePlan(rows)
+		if err != nil {
+			return nil, err
+		}
+		plans = append(plans, plan)
+	}
+	return plans, rows.Err()
+}
+
+func scanRatePlan(row rowScanner) (model.RatePlan, error) {
+	var plan model.RatePlan
+	var start, end time.Time
+	var multipliers, closed []byte
+	var policyID sql.NullString
+	if err := row.Scan(&plan.ID, &plan.Name, &plan.RoomType, &start, &end, &plan.BaseRate,
+		&multipliers, &plan.MinStay, &closed, &plan.Priority, &policyID); err != nil {
+		return model.RatePlan{}, err
+	}
+	plan.CancellationPolicyID = policyID.String
+	plan.StartDate = start.Format("2006-01-02")
+	plan.EndDate = end.Format("2006-01-02")
+	if err := json.Unmarshal(multipliers, &plan.WeekdayMultipliers); err != nil {
+		return model.RatePlan{}, err
+	}
+	if err := json.Unmarshal(closed, &plan.ClosedToArrival); err != nil {
+		return model.RatePlan{}, err
+	}
+	return plan, nil
+}
+
+// encodeRatePlanRules serializa as regras para as colunas JSONB; o pq envia []byte
+// como bytea, por isso os valores seguem como string
+func encodeRatePlanRules(plan model.RatePlan) (string, string, error) {
+	if plan.WeekdayMultipliers == nil {
+		plan.WeekdayMultipliers = map[string]float64{}
+	}
+	if plan.ClosedToArrival == nil {
+		plan.ClosedToArrival = []string{}
+	}
+	multipliers, err := json.Marshal(plan.WeekdayMultipliers)
+	if err != nil {
+		return "", "", err
+	}
+	closed, err := json.Marshal(plan.ClosedToArrival)
+	if err != nil {
+		return "", "", err
+	}
+	return string(multipliers), string(closed), nil
+}
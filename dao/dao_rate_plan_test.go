@@ -0,0 +1,90 @@
+package dao
+
+import (
+	"testing"
+
+	"hotel-soa/model"
+)
+
+func TestGetRatePlansForPeriod(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, repos Repositories) {
+		plans := []model.RatePlan{
+			{Name: "June", RoomType: "STANDARD", StartDate: "2026-06-01", EndDate: "2026-06-30", BaseRate: model.NewMoney(20000)},
+			{Name: "Holiday", RoomType: "STANDARD", StartDate: "2026-06-04", EndDate: "2026-06-05", BaseRate: model.NewMoney(30000), Priority: 10},
+			{Name: "Late June", RoomType: "STANDARD", StartDate: "2026-06-15", EndDate: "2026-06-30", BaseRate: model.NewMoney(25000)},
+			{Name: "July", RoomType: "STANDARD", StartDate: "2026-07-01", EndDate: "2026-07-31", BaseRate: model.NewMoney(22000)},
+			{Name: "Deluxe June", RoomType: "DELUXE", StartDate: "2026-06-01", EndDate: "2026-06-30", BaseRate: model.NewMoney(90000)},
+		}
+		for _, plan := range plans {
+			if _, err := repos.RatePlans.InsertRatePlan(plan); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		tests := []struct {
+			name     string
+			from, to string
+			expected []string
+		}{
+			// prioridade primeiro, depois o início mais recente
+			{"whole june", "2026-06-01", "2026-06-30", []string{"Holiday", "Late June", "June"}},
+			{"touching the end", "2026-06-30", "2026-07-02", []string{"July", "Late June", "June"}},
+			{"only june", "2026-06-10", "2026-06-12", []string{"June"}},
+			{"no plan", "2026-08-01", "2026-08-05", nil},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				found, err := repos.RatePlans.GetRatePlansForPeriod("STANDARD", date(tt.from), date(tt.to))
+				if err != nil {
+					t.Fatal(err)
+				}
+				var names []string
+				for _, plan := range found {
+					names = append(names, plan.Name)
+				}
+				if len(names) != len(tt.expected) {
+					t.Fatalf("expected %v, got %v", tt.expected, names)
+				}
+				for i := range names {
+					if names[i] != tt.expected[i] {
+						t.Fatalf("expected %v, got %v", tt.expected, names)
+					}
+				}
+			})
+		}
+	})
+}
+
+func TestRatePlanRepository(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, repos Repositories) {
+		plan := model.RatePlan{Name: "June", RoomType: "STANDARD", StartDate: "2026-06-01", EndDate: "2026-06-30", BaseRate: model.NewMoney(20000),
+			WeekdayMultipliers: map[string]float64{"saturday": 1.5}, MinStay: 2, ClosedToArrival: []string{"sunday"}, Priority: 3}
+		id, err := repos.RatePlans.InsertRatePlan(plan)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := repos.RatePlans.GetRatePlanByID(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Name != "June" || got.WeekdayMultipliers["saturday"] != 1.5 || got.MinStay != 2 ||
+			len(got.ClosedToArrival) != 1 || got.Priority != 3 || day(got.StartDate) != "2026-06-01" {
+			t.Fatalf("unexpected plan %+v", got)
+		}
+
+		plan.ID, plan.BaseRate = id, model.NewMoney(21000)
+		if err := repos.RatePlans.UpdateRatePlan(plan); err != nil {
+			t.Fatal(err)
+		}
+		if got, _ := repos.RatePlans.GetRatePlanByID(id); !got.BaseRate.Equal(model.NewMoney(21000)) {
+			t.Fatalf("expected the update to be stored, got %+v", got)
+		}
+
+		if err := repos.RatePlans.DeleteRatePlan(id); err != nil {
+			t.Fatal(err)
+		}
+		if all, _ := repos.RatePlans.GetAllRatePlans(); len(all) != 0 {
+			t.Fatalf("expected no plans after delete, got %+v", all)
+		}
+	})
+}
@@ -0,0 +1,344 @@
+package dao
+
+import (
+	"database/sql"
+	"hotel-soa/model"
+	"time"
+)
+
+// RoomRepository define as operações de persistência de quartos. Toda escrita registra
+// no audit_log, na mesma transação, quem (actor) alterou o quê
+type RoomRepository interface {
+	// InsertRoom e UpdateRoom retornam ErrDuplicateRoomNumber quando outro quarto não
+	// arquivado já usa o número
+	InsertRoom(room model.Room, actor model.Actor) (string, error)
+	// UpdateRoom recusa a troca de tipo com *model.RoomInUseError enquanto reservas
+	// CREATED, CONFIRMED ou CHECKED_IN no quarto ainda são do tipo antigo
+	UpdateRoom(room model.Room, actor model.Actor) error
+	// ArchiveRoom preenche deleted_at; *model.RoomInUseError lista as reservas ativas
+	// que terminam depois de at e impedem o arquivamento
+	ArchiveRoom(id string, at time.Time, actor model.Actor) error
+	// PurgeRoom apaga a linha; *model.RoomInUseError lista qualquer reserva do quarto
+	PurgeRoom(id string, actor model.Actor) error
+	// GetAllRooms e ListRooms ignoram os quartos arquivados; GetRoomByID não
+	GetAllRooms() ([]model.Room, error)
+	// ListRooms retorna até Limit+1 quartos a partir do cursor (o excedente indica que há
+	// próxima página) e o total de quartos que atendem aos filtros
+	ListRooms(filter model.RoomFilter) ([]model.Room, int, error)
+	GetRoomByID(id string) (model.Room, error)
+	// GetAvailableRooms retorna, numa única consulta, os quartos ATIVO com capacidade
+	// suficiente e sem reserva ou bloqueio ativo conflitante no período (mesma regra de
+	// HasReservationConflict), fora os dos tipos sem estoque em alguma noite do período
+	// (mesma regra de GetRoomTypeAvailability)
+	GetAvailableRooms(checkin, checkout time.Time, guests int, roomType string) ([]model.Room, error)
+	// GetRoomTypeAvailability retorna, por tipo, os quartos não arquivados e quantos ainda
+	// podem ser vendidos na noite mais cheia do período (mesma regra de InsertReservation)
+	GetRoomTypeAvailability(checkin, checkout time.Time) ([]model.RoomTypeAvailability, error)
+}
+
+// ReservationRepository define as operações de persistência de reservas; as escritas
+// são auditadas como as de RoomRepository
+type ReservationRepository interface {
+	// InsertReservation e UpdateReservation recusam o período que colide com outra reserva
+	// (*model.ReservationConflictError) ou com um bloqueio ativo (*model.RoomHeldError) do
+	// quarto, e o que esgota o tipo em alguma noite (*model.RoomTypeSoldOutError). Sem
+	// RoomID, a reserva só ocupa o estoque do tipo. Com res.HoldID, InsertReservation
+	// consome o bloqueio na mesma transação e retorna ErrHoldExpired se ele não estiver
+	// mais ativo
+	InsertReservation(res model.Reservation, actor model.Actor) (string, error)
+	// UpdateReservation recalcula total_amount pela conta e retorna ErrNightsClosed quando
+	// a alteração mexe em noites até a última data fechada pelo night audit
+	UpdateReservation(res model.Reservation, actor model.Actor) error
+	// PurgeReservation apaga a reserva de vez; o caminho normal é CancelReservation
+	PurgeReservation(id string, actor model.Actor) error
+	GetAllReservations() ([]model.Reservation, error)
+	// ListReservations segue o mesmo contrato de ListRooms
+	ListReservations(filter model.ReservationFilter) ([]model.Reservation, int, error)
+	GetReservationByID(id string) (model.Reservation, error)
+	GetReservationsByGuest(guestID string) ([]model.Reservation, error)
+	// HasReservationConflict considera as reservas ativas e os bloqueios ainda válidos
+	HasReservationConflict(roomID string, checkin, checkout time.Time, excludeID string) (bool, error)
+	// CheckInReservation marca a reserva CREATED ou CONFIRMED como CHECKED_IN;
+	// ErrReservationStatusChanged indica que ela mudou de status desde a leitura
+	CheckInReservation(id string, at time.Time, operator string, actor model.Actor) error
+	// AssignRoom atribui ou troca o quarto da reserva CREATED ou CONFIRMED com as checagens
+	// de conflito de UpdateReservation; ErrReservationStatusChanged indica que ela mudou
+	// desde a leitura
+	AssignRoom(id, roomID string, actor model.Actor) error
+	// CheckOutReservation fecha a reserva CHECKED_IN gravando a conta final (checkout e
+	// noites de res) com o mesmo contrato de CheckInReservation; *model.BalanceDueError
+	// indica que a conta final não fecha em zero com os pagamentos
+	CheckOutReservation(res model.Reservation, at time.Time, operator string, actor model.Actor) error
+	// CancelReservation marca a reserva CREATED ou CONFIRMED como CANCELED gravando a multa
+	// e o reembolso de quote, com o mesmo contrato de CheckInReservation
+	CancelReservation(id string, at time.Time, reason string, quote model.CancellationQuote, actor model.Actor) error
+	// MarkNoShow marca a reserva CREATED ou CONFIRMED como NO_SHOW gravando a multa e o
+	// reembolso de quote, com o mesmo contrato de CheckInReservation
+	MarkNoShow(id string, at time.Time, quote model.CancellationQuote, actor model.Actor) error
+	// GetUnarrivedReservations retorna, com as noites, as reservas CREATED ou CONFIRMED com
+	// chegada até arrivalBy
+	GetUnarrivedReservations(arrivalBy time.Time) ([]model.Reservation, error)
+	// FlagOverstays sinaliza as reservas CHECKED_IN com saída até checkoutBy que ainda não
+	// foram sinalizadas e retorna quantas foram
+	FlagOverstays(checkoutBy, at time.Time, actor model.Actor) (int, error)
+	// GetOverstayAlerts lista as reservas sinalizadas que continuam CHECKED_IN
+	GetOverstayAlerts() ([]model.ReservationAlert, error)
+	// GetReservationsForDate retorna, com as noites, as reservas de qualquer status cuja
+	// estadia toca a data (chegada até date e saída a partir de date)
+	GetReservationsForDate(date time.Time) ([]model.Reservation, error)
+	// GetUnassignedReservations retorna as reservas CREATED ou CONFIRMED sem quarto com
+	// chegada entre from e to (inclusive), por chegada e das estadias mais longas para as
+	// mais curtas
+	GetUnassignedReservations(from, to time.Time) ([]model.Reservation, error)
+	// GetRoomTypeBookings retorna as reservas ativas do tipo, com ou sem quarto, cuja
+	// estadia toca o intervalo [from, to)
+	GetRoomTypeBookings(roomType string, from, to time.Time) ([]model.Reservation, error)
+}
+
+// RatePlanRepository define as operações de persistência de planos tarifários
+type RatePlanRepository interface {
+	InsertRatePlan(plan model.RatePlan) (string, error)
+	UpdateRatePlan(plan model.RatePlan) error
+	DeleteRatePlan(id string) error
+	GetAllRatePlans() ([]model.RatePlan, error)
+	GetRatePlanByID(id string) (model.RatePlan, error)
+	// GetRatePlansForPeriod retorna os planos do tipo de quarto que tocam o intervalo
+	// [from, to], ordenados do mais para o menos prioritário
+	GetRatePlansForPeriod(roomType string, from, to time.Time) ([]model.RatePlan, error)
+}
+
+// CancellationPolicyRepository define as operações de persistência de políticas de
+// cancelamento; DeleteCancellationPolicy retorna ErrCancellationPolicyInUse quando a
+// política ainda é referenciada
+type CancellationPolicyRepository interface {
+	InsertCancellationPolicy(policy model.CancellationPolicy) (string, error)
+	UpdateCancellationPolicy(policy model.CancellationPolicy) error
+	DeleteCancellationPolicy(id string) error
+	GetAllCancellationPolicies() ([]model.CancellationPolicy, error)
+	GetCancellationPolicyByID(id string) (model.CancellationPolicy, error)
+}
+
+// TaxRepository define as operações de persistência das regras de impostos e a leitura
+// dos impostos já calculados nas reservas; as buscas retornam um TaxRule vazio quando não
+// encontram
+type TaxRepository interface {
+	// InsertTaxRule e UpdateTaxRule retornam ErrDuplicateTaxCode quando o code já existe
+	InsertTaxRule(rule model.TaxRule) (string, error)
+	UpdateTaxRule(rule model.TaxRule) error
+	DeleteTaxRule(id string) error
+	GetAllTaxRules() ([]model.TaxRule, error)
+	GetTaxRuleByID(id string) (model.TaxRule, error)
+	// GetPostedTaxes retorna os impostos das noites entre from e to (inclusive) cuja diária
+	// o night audit já lançou
+	GetPostedTaxes(from, to time.Time) ([]model.TaxLine, error)
+}
+
+// OverbookingRepository define as operações de persistência das regras de overbooking e a
+// leitura da ocupação por tipo; as buscas retornam um OverbookingRule vazio quando não
+// encontram
+type OverbookingRepository interface {
+	// InsertOverbookingRule e UpdateOverbookingRule retornam ErrOverbookingRuleOverlap
+	// quando outra regra do mesmo tipo cobre alguma das noites
+	InsertOverbookingRule(rule model.OverbookingRule) (string, error)
+	UpdateOverbookingRule(rule model.OverbookingRule) error
+	DeleteOverbookingRule(id string) error
+	GetAllOverbookingRules() ([]model.OverbookingRule, error)
+	GetOverbookingRuleByID(id string) (model.OverbookingRule, error)
+	// GetRoomTypeNights retorna a ocupação de cada tipo com quartos em cada noite entre from
+	// e to (inclusive), por noite e tipo
+	GetRoomTypeNights(from, to time.Time) ([]model.RoomTypeNight, error)
+}
+
+// GuestRepository define as operações de persistência de hóspedes
+type GuestRepository interface {
+	InsertGuest(guest model.Guest) (string, error)
+	UpdateGuest(guest model.Guest) error
+	DeleteGuest(id string) error
+	GetAllGuests() ([]model.Guest, error)
+	GetGuestByID(id string) (model.Guest, error)
+}
+
+// UserRepository define as operações de persistência de usuários; as buscas
+// retornam um User vazio quando não encontram
+type UserRepository interface {
+	InsertUser(user model.User) (string, error)
+	GetUserByID(id string) (model.User, error)
+	GetUserByUsername(username string) (model.User, error)
+	GetAllUsers() ([]model.User, error)
+	// UpdatePassword troca o hash e incrementa a versão dos tokens; false quando o
+	// usuário não existe
+	UpdatePassword(id, hash string) (bool, error)
+	// RevokeTokens incrementa a versão dos tokens, invalidando os refresh tokens emitidos
+	RevokeTokens(id string) (bool, error)
+}
+
+// APIKeyRepository define as operações de persistência de API keys
+type APIKeyRepository interface {
+	InsertAPIKey(key model.APIKey) (string, error)
+	GetAPIKeyByHash(hash string) (model.APIKey, error)
+	GetAPIKeysByUser(userID string) ([]model.APIKey, error)
+	// RevokeAPIKey retorna false quando o usuário não tem uma chave ativa com o ID
+	RevokeAPIKey(id, userID string, at time.Time) (bool, error)
+	TouchAPIKey(id string, at time.Time) error
+}
+
+// RoleRepository define as operações de persistência de papéis e da atribuição de
+// papéis a usuários; GetRole retorna um Role vazio quando não encontra
+type RoleRepository interface {
+	GetAllRoles() ([]model.Role, error)
+	GetRole(name string) (model.Role, error)
+	// SaveRole cria o papel ou substitui descrição e permissões de um existente
+	SaveRole(role model.Role) error
+	// DeleteRole retorna false quando o papel não existe; os usuários perdem o papel
+	DeleteRole(name string) (bool, error)
+	GetUserRoles(userID string) ([]model.Role, error)
+	// SetUserRoles substitui todos os papéis do usuário
+	SetUserRoles(userID string, roles []string) error
+}
+
+// AuditRepository lê o audit_log, que só recebe linhas pelas escritas dos outros repositórios
+type AuditRepository interface {
+	// GetAuditLog retorna as entradas da entidade em ordem de gravação
+	GetAuditLog(entity, entityID string) ([]model.AuditEntry, error)
+}
+
+// FolioRepository define as operações de persistência da conta da reserva. total_amount
+// é sempre a soma das noites, dos impostos cobrados à parte e dos lançamentos,
+// recalculada a cada escrita
+type FolioRepository interface {
+	// GetFolio retorna as linhas, o resumo dos impostos e os pagamentos da conta, sem os
+	// totais; vazio quando a reserva não existe
+	GetFolio(reservationID string) (model.Folio, error)
+	// PostCharge retorna ErrFolioClosed se a reserva não aceita lançamentos e
+	// ErrDiscountExceedsTotal se o desconto deixaria o total negativo
+	PostCharge(charge model.FolioCharge, actor model.Actor) (string, error)
+	// PostPayment retorna ErrRefundExceedsPaid quando o estorno passa do valor pago
+	PostPayment(payment model.Payment, actor model.Actor) (string, error)
+	// GetAuthorizations lista as pré-autorizações da reserva, da mais antiga para a mais recente
+	GetAuthorizations(reservationID string) ([]model.PaymentAuthorization, error)
+	// InsertAuthorization grava a resposta do gateway; a autorização aprovada leva a
+	// reserva de CREATED para CONFIRMED, e ErrReservationStatusChanged indica que ela não
+	// estava mais CREATED
+	InsertAuthorization(auth model.PaymentAuthorization, actor model.Actor) (string, error)
+	// UpdateAuthorization grava a captura, o cancelamento ou o estorno da autorização,
+	// desde que ela ainda esteja no status from (senão ErrAuthorizationChanged). payment,
+	// quando presente, é lançado na conta na mesma transação, com as regras de PostPayment
+	UpdateAuthorization(auth model.PaymentAuthorization, from string, payment *model.Payment, actor model.Actor) error
+}
+
+// HoldRepository define as operações de persistência dos bloqueios temporários de
+// quarto; as buscas retornam um RoomHold vazio quando não encontram e trazem também os
+// bloqueios vencidos que ainda não foram apagados
+type HoldRepository interface {
+	// InsertHold recusa o período que colide com uma reserva (*model.ReservationConflictError)
+	// ou com outro bloqueio ativo (*model.RoomHeldError) do quarto, ou que esgota o tipo
+	// (*model.RoomTypeSoldOutError)
+	InsertHold(hold model.RoomHold) (string, error)
+	GetHoldByID(id string) (model.RoomHold, error)
+	GetHoldByTokenHash(hash string) (model.RoomHold, error)
+	// ReleaseHold apaga o bloqueio; false quando não existe
+	ReleaseHold(id string) (bool, error)
+	// DeleteExpiredHolds apaga os bloqueios vencidos até at e retorna quantos eram
+	DeleteExpiredHolds(at time.Time) (int, error)
+}
+
+// NightAuditRepository define as operações de persistência do night audit; as buscas
+// retornam um NightAudit vazio quando a data não foi fechada
+type NightAuditRepository interface {
+	// GetLastClosedDate retorna a última data fechada (YYYY-MM-DD), vazio quando nenhuma foi
+	GetLastClosedDate() (string, error)
+	GetNightAudit(date string) (model.NightAudit, error)
+	// GetNightAudits lista os fechamentos do mais recente para o mais antigo
+	GetNightAudits() ([]model.NightAudit, error)
+	// CloseBusinessDate grava o fechamento e as diárias de uma vez; ErrBusinessDateClosed
+	// indica que a data já foi (ou outra posterior foi) fechada
+	CloseBusinessDate(audit model.NightAudit, charges []model.RoomCharge) error
+}
+
+// GroupRepository define as operações de persistência dos grupos e da conta master.
+// Toda escrita registra no audit_log, na mesma transação, quem (actor) alterou o quê
+type GroupRepository interface {
+	// InsertGroup recusa o bloco que não cabe no estoque de algum tipo em alguma noite
+	// (*model.RoomTypeSoldOutError)
+	InsertGroup(group model.Group, actor model.Actor) (string, error)
+	// GetGroupByID e GetAllGroups trazem os blocos com picked_up; ID vazio quando não existe
+	GetGroupByID(id string) (model.Group, error)
+	GetAllGroups() ([]model.Group, error)
+	// GetGroupsPastCutoff lista os grupos ACTIVE com cutoff antes de date
+	GetGroupsPastCutoff(date time.Time) ([]model.Group, error)
+	// GetGroupReservations lista as reservas do grupo, de qualquer status
+	GetGroupReservations(id string) ([]model.Reservation, error)
+	// ReleaseGroup passa o grupo de ACTIVE para RELEASED; ErrGroupStatusChanged quando
+	// ele já não estava ACTIVE
+	ReleaseGroup(id string, at time.Time, actor model.Actor) error
+	// CancelGroup passa o grupo para CANCELED; ErrGroupStatusChanged quando ele já estava
+	// cancelado. As reservas do grupo são canceladas pelo serviço
+	CancelGroup(id string, at time.Time, reason string, actor model.Actor) error
+	// GetGroupFolio traz as noites e os impostos cobrados à parte das reservas ativas do
+	// grupo e os pagamentos da conta master; GroupID vazio quando o grupo não existe
+	GetGroupFolio(id string) (model.GroupFolio, error)
+	// PostGroupPayment recusa o estorno maior que o valor pago com ErrRefundExceedsPaid
+	PostGroupPayment(payment model.Payment, actor model.Actor) (string, error)
+}
+
+// Repositories agrupa os repositórios de um mesmo backend
+type Repositories struct {
+	Rooms        RoomRepository
+	Reservations ReservationRepository
+	RatePlans    RatePlanRepository
+	Policies     CancellationPolicyRepository
+	Taxes        TaxRepository
+	Overbooking  OverbookingRepository
+	Guests       GuestRepository
+	Users        UserRepository
+	APIKeys      APIKeyRepository
+	Roles        RoleRepository
+	Audit        AuditRepository
+	NightAudits  NightAuditRepository
+	Folios       FolioRepository
+	Holds        HoldRepository
+	Groups       GroupRepository
+}
+
+// NewPostgresRepositories cria os repositórios apoiados no Postgres
+func NewPostgresRepositories(conn *sql.DB) Repositories {
+	return Repositories{
+		Rooms:        NewPostgresRoomRepository(conn),
+		Reservations: NewPostgresReservationRepository(conn),
+		RatePlans:    NewPostgresRatePlanRepository(conn),
+		Policies:     NewPostgresCancellationPolicyRepository(conn),
+		Taxes:        NewPostgresTaxRepository(conn),
+		Overbooking:  NewPostgresOverbookingRepository(conn),
+		Guests:       NewPostgresGuestRepository(conn),
+		Users:        NewPostgresUserRepository(conn),
+		APIKeys:      NewPostgresAPIKeyRepository(conn),
+		Roles:        NewPostgresRoleRepository(conn),
+		Audit:        NewPostgresAuditRepository(conn),
+		NightAudits:  NewPostgresNightAuditRepository(conn),
+		Folios:       NewPostgresFolioRepository(conn),
+		Holds:        NewPostgresHoldRepository(conn),
+		Groups:       NewPostgresGroupRepository(conn),
+	}
+}
+
+// NewMemoryRepositories cria os repositórios em memória, compartilhando o mesmo store
+func NewMemoryRepositories() Repositories {
+	store := NewMemoryStore()
+	return Repositories{
+		Rooms:        store.Rooms(),
+		Reservations: store.Reservations(),
+		RatePlans:    store.RatePlans(),
+		Policies:     store.CancellationPolicies(),
+		Taxes:        store.Taxes(),
+		Overbooking:  store.Overbooking(),
+		Guests:       store.Guests(),
+		Users:        store.Users(),
+		APIKeys:      store.APIKeys(),
+		Roles:        store.Roles(),
+		Audit:        store.Audit(),
+		NightAudits:  store.NightAudits(),
+		Folios:       store.Folios(),
+		Holds:        store.Holds(),
+		Groups:       store.Groups(),
+	}
+}
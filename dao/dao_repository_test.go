@@ -0,0 +1,257 @@
+package dao
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"hotel-soa/db/dbtest"
+	"hotel-soa/migrations"
+	"hotel-soa/model"
+)
+
+// testActor é o autor registrado no audit_log pelas escritas dos testes
+var testActor = model.Actor{Username: "test"}
+
+// testBackend monta os repositórios de um backend para os testes de contrato
+type testBackend struct {
+	name  string
+	repos func(t *testing.T) Repositories
+}
+
+// testBackends lista os backends testados: o em memória roda sempre e o Postgres dos
+// DB_* só com STORAGE_BACKEND=postgres, num schema novo com as migrações aplicadas
+var testBackends = []testBackend{
+	{"memory", func(t *testing.T) Repositories { return NewMemoryRepositories() }},
+	{"postgres", func(t *testing.T) Repositories {
+		conn := dbtest.Open(t)
+		migrator, err := migrations.NewMigrator(conn)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := migrator.Up(); err != nil {
+			t.Fatal(err)
+		}
+		return NewPostgresRepositories(conn)
+	}},
+}
+
+// forEachBackend roda fn como subteste em cada backend
+func forEachBackend(t *testing.T, fn func(t *testing.T, repos Repositories)) {
+	t.Helper()
+	for _, backend := range testBackends {
+		t.Run(backend.name, func(t *testing.T) {
+			fn(t, backend.repos(t))
+		})
+	}
+}
+
+// insertTestRoom grava um quarto ativo com o próximo número livre
+func insertTestRoom(t *testing.T, repos Repositories, roomType string) model.Room {
+	t.Helper()
+	rooms, err := repos.Rooms.GetAllRooms()
+	if err != nil {
+		t.Fatal(err)
+	}
+	room := model.Room{Number: 101 + len(rooms), Type: roomType, Capacity: 2, PricePerNight: model.NewMoney(10000), Status: "ATIVO"}
+	id, err := repos.Rooms.InsertRoom(room, testActor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	room.ID = id
+	return room
+}
+
+// insertTestGuest grava um hóspede só com o nome
+func insertTestGuest(t *testing.T, repos Repositories, name string) string {
+	t.Helper()
+	id, err := repos.Guests.InsertGuest(model.Guest{Name: name})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return id
+}
+
+// newTestReservation monta uma reserva no quarto para um hóspede novo
+func newTestReservation(t *testing.T, repos Repositories, roomID, checkin, checkout string) model.Reservation {
+	t.Helper()
+	room, err := repos.Rooms.GetRoomByID(roomID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return model.Reservation{RoomID: roomID, RoomType: room.Type, GuestID: insertTestGuest(t, repos, "Test Guest"), GuestName: "Test Guest",
+		CheckinExpected: checkin, CheckoutExpected: checkout, Status: "CREATED", TotalAmount: model.NewMoney(20000)}
+}
+
+// insertTestReservation grava uma reserva no quarto para um hóspede novo
+func insertTestReservation(t *testing.T, repos Repositories, roomID, checkin, checkout string) model.Reservation {
+	t.Helper()
+	res := newTestReservation(t, repos, roomID, checkin, checkout)
+	id, err := repos.Reservations.InsertReservation(res, testActor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.ID = id
+	return res
+}
+
+// day corta a hora que o Postgres devolve nas colunas DATE
+func day(value string) string {
+	if len(value) > 10 {
+		return value[:10]
+	}
+	return value
+}
+
+func date(value string) time.Time {
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestRoomRepository(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, repos Repositories) {
+		room := insertTestRoom(t, repos, "STANDARD")
+
+		got, err := repos.Rooms.GetRoomByID(room.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != room {
+			t.Fatalf("expected %+v, got %+v", room, got)
+		}
+
+		room.Type, room.Capacity, room.Status = "DELUXE", 3, "INATIVO"
+		if err := repos.Rooms.UpdateRoom(room, testActor); err != nil {
+			t.Fatal(err)
+		}
+		all, err := repos.Rooms.GetAllRooms()
+		if err != nil {
+			t.Fatal(err)
+		}
+		found := false
+		for _, r := range all {
+			if r.ID == room.ID {
+				found = r == room
+			}
+		}
+		if !found {
+			t.Fatalf("expected the updated room %+v in the list", room)
+		}
+
+		if err := repos.Rooms.PurgeRoom(room.ID, testActor); err != nil {
+			t.Fatal(err)
+		}
+		// quarto inexistente volta vazio, sem erro
+		if got, err := repos.Rooms.GetRoomByID(room.ID); err != nil || got.ID != "" {
+			t.Fatalf("expected an empty room after delete, got %+v, %v", got, err)
+		}
+	})
+}
+
+func TestRoomRepositoryPurgeReferencedRoom(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, repos Repositories) {
+		room := insertTestRoom(t, repos, "STANDARD")
+		res := insertTestReservation(t, repos, room.ID, "2026-06-01", "2026-06-03")
+
+		// até reservas passadas impedem a remoção definitiva
+		var inUse *model.RoomInUseError
+		err := repos.Rooms.PurgeRoom(room.ID, testActor)
+		if !errors.As(err, &inUse) || len(inUse.Reservations) != 1 || inUse.Reservations[0].ID != res.ID {
+			t.Fatalf("expected a RoomInUseError citing %s, got %v", res.ID, err)
+		}
+		if got, _ := repos.Rooms.GetRoomByID(room.ID); got.ID != room.ID {
+			t.Fatalf("expected the room to be kept, got %+v", got)
+		}
+	})
+}
+
+func TestReservationRepository(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, repos Repositories) {
+		room := insertTestRoom(t, repos, "STANDARD")
+		res := insertTestReservation(t, repos, room.ID, "2026-06-01", "2026-06-03")
+
+		got, err := repos.Reservations.GetReservationByID(res.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got.CheckinExpected, got.CheckoutExpected = day(got.CheckinExpected), day(got.CheckoutExpected)
+		if !reflect.DeepEqual(got, res) {
+			t.Fatalf("expected %+v, got %+v", res, got)
+		}
+
+		res.GuestName, res.Status = "Other Guest", "CHECKED_IN"
+		if err := repos.Reservations.UpdateReservation(res, testActor); err != nil {
+			t.Fatal(err)
+		}
+		if got, _ := repos.Reservations.GetReservationByID(res.ID); got.GuestName != "Other Guest" || got.Status != "CHECKED_IN" {
+			t.Fatalf("expected the update to be stored, got %+v", got)
+		}
+
+		if err := repos.Reservations.PurgeReservation(res.ID, testActor); err != nil {
+			t.Fatal(err)
+		}
+		if got, err := repos.Reservations.GetReservationByID(res.ID); err != nil || got.ID != "" {
+			t.Fatalf("expected an empty reservation after delete, got %+v, %v", got, err)
+		}
+	})
+}
+
+func TestReservationRepositoryUnknownRoom(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, repos Repositories) {
+		res := newTestReservation(t, repos, "00000000-0000-0000-0000-000000000000", "2026-06-01", "2026-06-03")
+		id, err := repos.Reservations.InsertReservation(res, testActor)
+		if err == nil {
+			repos.Reservations.PurgeReservation(id, testActor)
+		}
+		var validation *model.ValidationError
+		if !errors.As(err, &validation) || validation.Errors[0].Code != "not_found" {
+			t.Fatalf("expected a not_found validation error for a reservation in an unknown room, got %v", err)
+		}
+		hold := model.RoomHold{RoomID: res.RoomID, TokenHash: "h", CheckinExpected: "2026-06-01", CheckoutExpected: "2026-06-03", ExpiresAt: time.Now().Add(time.Hour)}
+		if _, err := repos.Holds.InsertHold(hold); !errors.As(err, &validation) {
+			t.Fatalf("expected a validation error for a hold on an unknown room, got %v", err)
+		}
+	})
+}
+
+func TestHasReservationConflict(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, repos Repositories) {
+		room := insertTestRoom(t, repos, "STANDARD")
+		booked := insertTestReservation(t, repos, room.ID, "2026-06-10", "2026-06-13")
+		canceled := insertTestReservation(t, repos, room.ID, "2026-06-20", "2026-06-22")
+		canceled.Status = "CANCELED"
+		if err := repos.Reservations.UpdateReservation(canceled, testActor); err != nil {
+			t.Fatal(err)
+		}
+
+		tests := []struct {
+			name              string
+			checkin, checkout string
+			excludeID         string
+			conflict          bool
+		}{
+			{"same dates", "2026-06-10", "2026-06-13", "", true},
+			{"overlapping start", "2026-06-08", "2026-06-11", "", true},
+			{"inside", "2026-06-11", "2026-06-12", "", true},
+			{"checkout on arrival day", "2026-06-08", "2026-06-10", "", false},
+			{"checkin on departure day", "2026-06-13", "2026-06-15", "", false},
+			{"excluding itself", "2026-06-10", "2026-06-13", booked.ID, false},
+			{"canceled reservation", "2026-06-20", "2026-06-22", "", false},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				conflict, err := repos.Reservations.HasReservationConflict(room.ID, date(tt.checkin), date(tt.checkout), tt.excludeID)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if conflict != tt.conflict {
+					t.Fatalf("expected conflict %v, got %v", tt.conflict, conflict)
+				}
+			})
+		}
+	})
+}
@@ -2,81 +2,442 @@ package dao
 
 import (
 	"database/sql"
-	"hotel-soa/db"
+	"errors"
+	"fmt"
 	"hotel-soa/model"
+	"strconv"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
-func InsertReservation(res model.Reservation) (string, error) {
+// ErrReservationStatusChanged indica que a reserva mudou de status entre a leitura e a escrita
+var ErrReservationStatusChanged = errors.New("reservation status changed, reload and try again")
+
+type postgresReservationRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresReservationRepository cria um ReservationRepository apoiado no Postgres
+func NewPostgresReservationRepository(conn *sql.DB) ReservationRepository {
+	return &postgresReservationRepository{db: conn}
+}
+
+// InsertReservation verifica conflitos e insere numa única transação. O lock na linha
+// do quarto serializa reservas concorrentes do mesmo quarto e a constraint
+// reservations_no_overlap garante a regra mesmo fora deste caminho. O bloqueio de
+// res.HoldID, se houver, é apagado na mesma transação.
+func (r *postgresReservationRepository) InsertReservation(res model.Reservation, actor model.Actor) (string, error) {
 	id := uuid.NewString()
-	query := `INSERT INTO reservations 
-		(id, room_id, guest_name, checkin_expected, checkout_expected, status, total_amount)
-		VALUES ($1, $2, $3, $4, $5, $6, $7);`
-	_, err := db.GetDB().Exec(query,
-		id,
-		res.RoomID,
-		res.GuestName,
-		res.CheckinExpected,
-		res.CheckoutExpected,
-		res.Status,
-		res.TotalAmount,
-	)
+	err := r.withRoomLock(res, "", func(tx *sql.Tx) error {
+		if res.HoldID != "" {
+			if err := consumeHold(tx, res.HoldID, res.RoomID); err != nil {
+				return err
+			}
+		}
+		query := `INSERT INTO reservations
+			(id, room_id, room_type, guest_id, guest_name, checkin_expected, checkout_expected, status, total_amount,
+			 cancellation_policy_id, group_id)
+			VALUES ($1, NULLIF($2, ''), $3, $4, $5, $6, $7, $8, $9, NULLIF($10, ''), NULLIF($11, ''));`
+		_, err := tx.Exec(query,
+			id,
+			res.RoomID,
+			res.RoomType,
+			res.GuestID,
+			res.GuestName,
+			res.CheckinExpected,
+			res.CheckoutExpected,
+			res.Status,
+			res.TotalAmount,
+			res.CancellationPolicyID,
+			res.GroupID,
+		)
+		if err != nil {
+			return err
+		}
+		if err := replaceNights(tx, id, res.Nights); err != nil {
+			return err
+		}
+		if err := replaceTaxes(tx, id, res.Taxes); err != nil {
+			return err
+		}
+		return auditReservation(tx, id, model.AuditActionCreate, actor, nil)
+	})
 	if err != nil {
 		return "", err
 	}
 	return id, nil
 }
 
-func UpdateReservation(res model.Reservation) error {
-	query := `UPDATE reservations 
-		SET room_id = $1, guest_name = $2, checkin_expected = $3, 
-		    checkout_expected = $4, status = $5, total_amount = $6
-		WHERE id = $7;`
-	_, err := db.GetDB().Exec(query,
-		res.RoomID,
-		res.GuestName,
-		res.CheckinExpected,
-		res.CheckoutExpected,
-		res.Status,
-		res.TotalAmount,
-		res.ID,
-	)
-	return err
+// UpdateReservation segue o mesmo fluxo transacional de InsertReservation
+func (r *postgresReservationRepository) UpdateReservation(res model.Reservation, actor model.Actor) error {
+	return r.withRoomLock(res, res.ID, func(tx *sql.Tx) error {
+		before, err := lockReservation(tx, res.ID)
+		if err != nil || before == nil {
+			return err
+		}
+		closed, err := sharedLastClosedDate(tx)
+		if err != nil {
+			return err
+		}
+		if err := checkClosedNights(*before, res, closed); err != nil {
+			return err
+		}
+		query := `UPDATE reservations
+			SET room_id = NULLIF($1, ''), guest_id = $2, guest_name = $3, checkin_expected = $4,
+			    checkout_expected = $5, status = $6, total_amount = $7, cancellation_policy_id = NULLIF($8, ''),
+			    overstay_flagged_at = CASE WHEN checkout_expected = $5::date THEN overstay_flagged_at END,
+			    room_type = $10
+			WHERE id = $9;`
+		_, err = tx.Exec(query,
+			res.RoomID,
+			res.GuestID,
+			res.GuestName,
+			res.CheckinExpected,
+			res.CheckoutExpected,
+			res.Status,
+			res.TotalAmount,
+			res.CancellationPolicyID,
+			res.ID,
+			res.RoomType,
+		)
+		if err != nil {
+			return err
+		}
+		if err := replaceNights(tx, res.ID, res.Nights); err != nil {
+			return err
+		}
+		if err := replaceTaxes(tx, res.ID, res.Taxes); err != nil {
+			return err
+		}
+		if _, err := refreshTotal(tx, res.ID); err != nil {
+			return err
+		}
+		return auditReservation(tx, res.ID, model.AuditActionUpdate, actor, before)
+	})
 }
 
-func DeleteReservation(id string) error {
-	query := "DELETE FROM reservations WHERE id = $1;"
-	_, err := db.GetDB().Exec(query, id)
-	return err
+func (r *postgresReservationRepository) CheckInReservation(id string, at time.Time, operator string, actor model.Actor) error {
+	return withTx(r.db, func(tx *sql.Tx) error {
+		before, err := lockReservation(tx, id)
+		if err != nil {
+			return err
+		}
+		result, err := tx.Exec(`UPDATE reservations
+			SET status = 'CHECKED_IN', checked_in_at = $1, checked_in_by = $2
+			WHERE id = $3 AND status IN ('CREATED', 'CONFIRMED');`, at, operator, id)
+		if err != nil {
+			return err
+		}
+		if err := requireAffected(result); err != nil {
+			return err
+		}
+		return auditReservation(tx, id, model.AuditActionCheckIn, actor, before)
+	})
 }
 
-func GetAllReservations() ([]model.Reservation, error) {
-	var reservations []model.Reservation
-	query := `SELECT id, room_id, guest_name, checkin_expected, 
-		checkout_expected, status, total_amount FROM reservations;`
+// AssignRoom relê a reserva para saber o tipo e as datas e passa pelas mesmas checagens
+// de conflito de UpdateReservation; o estoque do tipo não muda
+func (r *postgresReservationRepository) AssignRoom(id, roomID string, actor model.Actor) error {
+	res, err := r.GetReservationByID(id)
+	if err != nil {
+		return err
+	}
+	if res.ID == "" || !awaitingArrival(res.Status) {
+		return ErrReservationStatusChanged
+	}
+	res.RoomID = roomID
+	return r.withRoomLock(res, id, func(tx *sql.Tx) error {
+		before, err := lockReservation(tx, id)
+		if err != nil {
+			return err
+		}
+		// as checagens usaram o tipo e as datas lidos antes do lock
+		if before == nil || before.RoomType != res.RoomType ||
+			before.CheckinExpected != res.CheckinExpected || before.CheckoutExpected != res.CheckoutExpected {
+			return ErrReservationStatusChanged
+		}
+		result, err := tx.Exec(`UPDATE reservations SET room_id = $1
+			WHERE id = $2 AND status IN ('CREATED', 'CONFIRMED');`, roomID, id)
+		if err != nil {
+			return err
+		}
+		if err := requireAffected(result); err != nil {
+			return err
+		}
+		return auditReservation(tx, id, model.AuditActionAssignRoom, actor, before)
+	})
+}
+
+// CheckOutReservation só encurta a estadia, então não precisa da checagem de conflitos.
+// A conta final precisa fechar em zero
+func (r *postgresReservationRepository) CheckOutReservation(res model.Reservation, at time.Time, operator string, actor model.Actor) error {
+	return withTx(r.db, func(tx *sql.Tx) error {
+		before, err := lockReservation(tx, res.ID)
+		if err != nil {
+			return err
+		}
+		result, err := tx.Exec(`UPDATE reservations
+			SET status = 'CHECKED_OUT', checked_out_at = $1, checked_out_by = $2,
+			    checkout_expected = $3, total_amount = $4
+			WHERE id = $5 AND status = 'CHECKED_IN';`,
+			at, operator, res.CheckoutExpected, res.TotalAmount, res.ID)
+		if err != nil {
+			return err
+		}
+		if err := requireAffected(result); err != nil {
+			return err
+		}
+		if err := replaceNights(tx, res.ID, res.Nights); err != nil {
+			return err
+		}
+		if err := replaceTaxes(tx, res.ID, res.Taxes); err != nil {
+			return err
+		}
+		if _, err := refreshTotal(tx, res.ID); err != nil {
+			return err
+		}
+		if err := requireSettled(tx, res.ID); err != nil {
+			return err
+		}
+		return auditReservation(tx, res.ID, model.AuditActionCheckOut, actor, before)
+	})
+}
+
+// CancelReservation cancela a reserva CREATED ou CONFIRMED com o mesmo contrato de CheckInReservation
+func (r *postgresReservationRepository) CancelReservation(id string, at time.Time, reason string, quote model.CancellationQuote, actor model.Actor) error {
+	return withTx(r.db, func(tx *sql.Tx) error {
+		before, err := lockReservation(tx, id)
+		if err != nil {
+			return err
+		}
+		result, err := tx.Exec(`UPDATE reservations
+			SET status = 'CANCELED', canceled_at = $1, canceled_by = $2, cancellation_reason = NULLIF($3, ''),
+			    cancellation_fee = $4, refund_amount = $5
+			WHERE id = $6 AND status IN ('CREATED', 'CONFIRMED');`, at, actor.Username, reason, quote.Fee, quote.RefundAmount, id)
+		if err != nil {
+			return err
+		}
+		if err := requireAffected(result); err != nil {
+			return err
+		}
+		return auditReservation(tx, id, model.AuditActionCancel, actor, before)
+	})
+}
+
+// MarkNoShow marca a reserva CREATED ou CONFIRMED como NO_SHOW com o mesmo contrato de CheckInReservation
+func (r *postgresReservationRepository) MarkNoShow(id string, at time.Time, quote model.CancellationQuote, actor model.Actor) error {
+	return withTx(r.db, func(tx *sql.Tx) error {
+		before, err := lockReservation(tx, id)
+		if err != nil {
+			return err
+		}
+		result, err := tx.Exec(`UPDATE reservations
+			SET status = 'NO_SHOW', no_show_at = $1, no_show_fee = $2, refund_amount = $3
+			WHERE id = $4 AND status IN ('CREATED', 'CONFIRMED');`, at, quote.Fee, quote.RefundAmount, id)
+		if err != nil {
+			return err
+		}
+		if err := requireAffected(result); err != nil {
+			return err
+		}
+		return auditReservation(tx, id, model.AuditActionNoShow, actor, before)
+	})
+}
+
+// FlagOverstays trava e sinaliza, numa transação, as estadias ainda abertas
+func (r *postgresReservationRepository) FlagOverstays(checkoutBy, at time.Time, actor model.Actor) (int, error) {
+	var flagged int
+	err := withTx(r.db, func(tx *sql.Tx) error {
+		rows, err := tx.Query(`SELECT id FROM reservations
+			WHERE status = 'CHECKED_IN' AND checkout_expected <= $1::date AND overstay_flagged_at IS NULL
+			FOR UPDATE;`, checkoutBy)
+		if err != nil {
+			return err
+		}
+		var ids []string
+		for rows.Next() {
+			var id string
+			if err := rows.Scan(&id); err != nil {
+				rows.Close()
+				return err
+			}
+			ids = append(ids, id)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		for _, id := range ids {
+			before, err := lockReservation(tx, id)
+			if err != nil {
+				return err
+			}
+			if _, err := tx.Exec("UPDATE reservations SET overstay_flagged_at = $1 WHERE id = $2;", at, id); err != nil {
+				return err
+			}
+			if err := auditReservation(tx, id, model.AuditActionOverstay, actor, before); err != nil {
+				return err
+			}
+		}
+		flagged = len(ids)
+		return nil
+	})
+	return flagged, err
+}
+
+func (r *postgresReservationRepository) GetUnarrivedReservations(arrivalBy time.Time) ([]model.Reservation, error) {
+	query := `SELECT ` + reservationColumns + ` FROM reservations
+		WHERE status IN ('CREATED', 'CONFIRMED') AND checkin_expected <= $1::date ORDER BY checkin_expected;`
+	reservations, err := r.queryReservations(query, arrivalBy)
+	if err != nil {
+		return nil, err
+	}
+	// as noites entram na multa de políticas por noites
+	if err := loadNights(r.db, reservations); err != nil {
+		return nil, err
+	}
+	return reservations, nil
+}
+
+func (r *postgresReservationRepository) GetReservationsForDate(date time.Time) ([]model.Reservation, error) {
+	query := `SELECT ` + reservationColumns + ` FROM reservations
+		WHERE checkin_expected <= $1::date AND checkout_expected >= $1::date
+		ORDER BY checkin_expected, id;`
+	reservations, err := r.queryReservations(query, date)
+	if err != nil {
+		return nil, err
+	}
+	if err := loadNights(r.db, reservations); err != nil {
+		return nil, err
+	}
+	return reservations, nil
+}
+
+func (r *postgresReservationRepository) GetUnassignedReservations(from, to time.Time) ([]model.Reservation, error) {
+	query := `SELECT ` + reservationColumns + ` FROM reservations
+		WHERE status IN ('CREATED', 'CONFIRMED') AND room_id IS NULL
+		  AND checkin_expected BETWEEN $1::date AND $2::date
+		ORDER BY checkin_expected, checkout_expected DESC, id;`
+	return r.queryReservations(query, from, to)
+}
+
+func (r *postgresReservationRepository) GetRoomTypeBookings(roomType string, from, to time.Time) ([]model.Reservation, error) {
+	query := `SELECT ` + reservationColumns + ` FROM reservations
+		WHERE room_type = $1 AND status NOT IN ('CANCELED', 'NO_SHOW')
+		  AND (checkin_expected, checkout_expected) OVERLAPS ($2::date, $3::date)
+		ORDER BY checkin_expected, id;`
+	return r.queryReservations(query, roomType, from, to)
+}
+
+func (r *postgresReservationRepository) GetOverstayAlerts() ([]model.ReservationAlert, error) {
+	rows, err := r.db.Query(`SELECT id, COALESCE(room_id, ''), guest_name, checkout_expected, overstay_flagged_at
+		FROM reservations
+		WHERE status = 'CHECKED_IN' AND overstay_flagged_at IS NOT NULL
+		ORDER BY overstay_flagged_at, id;`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var alerts []model.ReservationAlert
+	for rows.Next() {
+		alert := model.ReservationAlert{Type: model.AlertOverstay}
+		var checkout time.Time
+		if err := rows.Scan(&alert.ReservationID, &alert.RoomID, &alert.GuestName, &checkout, &alert.FlaggedAt); err != nil {
+			return nil, err
+		}
+		alert.CheckoutExpected = checkout.Format("2006-01-02")
+		alerts = append(alerts, alert)
+	}
+	return alerts, rows.Err()
+}
+
+func (r *postgresReservationRepository) PurgeReservation(id string, actor model.Actor) error {
+	return withTx(r.db, func(tx *sql.Tx) error {
+		before, err := lockReservation(tx, id)
+		if err != nil || before == nil {
+			return err
+		}
+		if _, err := tx.Exec("DELETE FROM reservations WHERE id = $1;", id); err != nil {
+			return err
+		}
+		return auditReservation(tx, id, model.AuditActionPurge, actor, before)
+	})
+}
+
+// reservationColumns é a lista de colunas lida por scanReservation
+const reservationColumns = `id, COALESCE(room_id, ''), room_type, guest_id, guest_name, checkin_expected,
+		checkout_expected, status, total_amount, checked_in_at, COALESCE(checked_in_by, ''),
+		checked_out_at, COALESCE(checked_out_by, ''), canceled_at, COALESCE(canceled_by, ''),
+		COALESCE(cancellation_reason, ''), COALESCE(cancellation_policy_id, ''), cancellation_fee, refund_amount,
+		no_show_at, no_show_fee, overstay_flagged_at, confirmed_at, COALESCE(group_id, '')`
+
+func (r *postgresReservationRepository) GetAllReservations() ([]model.Reservation, error) {
+	query := `SELECT ` + reservationColumns + ` FROM reservations;`
+	return r.queryReservations(query)
+}
+
+func (r *postgresReservationRepository) ListReservations(filter model.ReservationFilter) ([]model.Reservation, int, error) {
+	var where whereBuilder
+	if filter.RoomID != "" {
+		where.add("room_id = %s", filter.RoomID)
+	}
+	if filter.RoomType != "" {
+		where.add("room_type = %s", filter.RoomType)
+	}
+	if filter.Status != "" {
+		where.add("status = %s", filter.Status)
+	}
+	if filter.GuestNamePrefix != "" {
+		where.add(`lower(guest_name) LIKE lower(%s) || '%%'`, escapeLike(filter.GuestNamePrefix))
+	}
+	if filter.From != "" {
+		where.add("checkout_expected > %s::date", filter.From)
+	}
+	if filter.To != "" {
+		where.add("checkin_expected < %s::date", filter.To)
+	}
+
+	var total int
+	if err := r.db.QueryRow("SELECT COUNT(*) FROM reservations"+where.sql()+";", where.args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	col := reservationSortColumns[filter.Page.Sort.Field]
+	where.addKeyset(col, filter.Page.Sort, filter.Page.Cursor)
+	query := "SELECT " + reservationColumns + " FROM reservations" +
+		where.sql() + orderBy(col, filter.Page.Sort) + " LIMIT " + strconv.Itoa(filter.Page.Limit+1) + ";"
+
+	reservations, err := r.queryReservations(query, where.args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	return reservations, total, nil
+}
+
+func (r *postgresReservationRepository) GetReservationsByGuest(guestID string) ([]model.Reservation, error) {
+	query := `SELECT ` + reservationColumns + ` FROM reservations
+		WHERE guest_id = $1 ORDER BY checkin_expected DESC;`
+	return r.queryReservations(query, guestID)
+}
 
-	rows, err := db.GetDB().Query(query)
+func (r *postgresReservationRepository) queryReservations(query string, args ...any) ([]model.Reservation, error) {
+	var reservations []model.Reservation
+	rows, err := r.db.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
 	for rows.Next() {
-		var r model.Reservation
-		if err := rows.Scan(
-			&r.ID,
-			&r.RoomID,
-			&r.GuestName,
-			&r.CheckinExpected,
-			&r.CheckoutExpected,
-			&r.Status,
-			&r.TotalAmount,
-		); err != nil {
+		res, err := scanReservation(rows)
+		if err != nil {
 			return nil, err
 		}
-		reservations = append(reservations, r)
+		reservations = append(reservations, res)
 	}
 	if err := rows.Err(); err != nil {
 		return nil, err
@@ -84,44 +445,529 @@ func GetAllReservations() ([]model.Reservation, error) {
 	return reservations, nil
 }
 
-func GetReservationByID(id string) (model.Reservation, error) {
-	query := `SELECT id, room_id, guest_name, checkin_expected, 
-		checkout_expected, status, total_amount 
-		FROM reservations WHERE id = $1;`
-	row := db.GetDB().QueryRow(query, id)
-
-	var r model.Reservation
-	if err := row.Scan(
-		&r.ID,
-		&r.RoomID,
-		&r.GuestName,
-		&r.CheckinExpected,
-		&r.CheckoutExpected,
-		&r.Status,
-		&r.TotalAmount,
-	); err != nil {
+func (r *postgresReservationRepository) GetReservationByID(id string) (model.Reservation, error) {
+	query := `SELECT ` + reservationColumns + ` FROM reservations WHERE id = $1;`
+	res, err := scanReservation(r.db.QueryRow(query, id))
+	if err != nil {
 		if err == sql.ErrNoRows {
 			return model.Reservation{}, nil
 		}
 		return model.Reservation{}, err
 	}
-	return r, nil
+
+	res.Nights, err = getNights(r.db, id)
+	if err != nil {
+		return model.Reservation{}, err
+	}
+	res.Taxes, err = getTaxes(r.db, id)
+	if err != nil {
+		return model.Reservation{}, err
+	}
+	return res, nil
 }
 
-func HasReservationConflict(roomID string, checkin, checkout time.Time, excludeID string) (bool, error) {
+func (r *postgresReservationRepository) HasReservationConflict(roomID string, checkin, checkout time.Time, excludeID string) (bool, error) {
+	conflictID, err := findReservationConflict(r.db, roomID, checkin, checkout, excludeID)
+	if err != nil || conflictID != "" {
+		return conflictID != "", err
+	}
+	err = checkHoldConflict(r.db, roomID, checkin, checkout, "")
+	var held *model.RoomHeldError
+	if errors.As(err, &held) {
+		return true, nil
+	}
+	return false, err
+}
+
+// ---------------- HELPERS ----------------
+
+// rowScanner é satisfeito tanto por *sql.Row quanto por *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanReservation lê as colunas de reservationColumns, normalizando as datas para YYYY-MM-DD
+func scanReservation(row rowScanner) (model.Reservation, error) {
+	var res model.Reservation
+	var checkin, checkout time.Time
+	var checkedIn, checkedOut, canceled, noShow, overstay, confirmed sql.NullTime
+	var fee, refund, noShowFee sql.Null[model.Money]
+	if err := row.Scan(
+		&res.ID,
+		&res.RoomID,
+		&res.RoomType,
+		&res.GuestID,
+		&res.GuestName,
+		&checkin,
+		&checkout,
+		&res.Status,
+		&res.TotalAmount,
+		&checkedIn,
+		&res.CheckedInBy,
+		&checkedOut,
+		&res.CheckedOutBy,
+		&canceled,
+		&res.CanceledBy,
+		&res.CancellationReason,
+		&res.CancellationPolicyID,
+		&fee,
+		&refund,
+		&noShow,
+		&noShowFee,
+		&overstay,
+		&confirmed,
+		&res.GroupID,
+	); err != nil {
+		return model.Reservation{}, err
+	}
+	res.CheckinExpected = checkin.Format("2006-01-02")
+	res.CheckoutExpected = checkout.Format("2006-01-02")
+	if checkedIn.Valid {
+		res.CheckedInAt = &checkedIn.Time
+	}
+	if checkedOut.Valid {
+		res.CheckedOutAt = &checkedOut.Time
+	}
+	if canceled.Valid {
+		res.CanceledAt = &canceled.Time
+	}
+	if fee.Valid {
+		res.CancellationFee = &fee.V
+	}
+	if refund.Valid {
+		res.RefundAmount = &refund.V
+	}
+	if noShow.Valid {
+		res.NoShowAt = &noShow.Time
+	}
+	if noShowFee.Valid {
+		res.NoShowFee = &noShowFee.V
+	}
+	if overstay.Valid {
+		res.OverstayFlaggedAt = &overstay.Time
+	}
+	if confirmed.Valid {
+		res.ConfirmedAt = &confirmed.Time
+	}
+	return res, nil
+}
+
+func getNights(q rowsQueryer, reservationID string) ([]model.NightlyRate, error) {
+	rows, err := q.Query(`SELECT night, price, COALESCE(rate_plan_id, '') FROM reservation_nights
+		WHERE reservation_id = $1 ORDER BY night;`, reservationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var nights []model.NightlyRate
+	for rows.Next() {
+		var night time.Time
+		var rate model.NightlyRate
+		if err := rows.Scan(&night, &rate.Price, &rate.RatePlanID); err != nil {
+			return nil, err
+		}
+		rate.Date = night.Format("2006-01-02")
+		nights = append(nights, rate)
+	}
+	return nights, rows.Err()
+}
+
+// loadNights preenche as noites de várias reservas numa única consulta, em vez de uma
+// consulta por reserva
+func loadNights(q rowsQueryer, reservations []model.Reservation) error {
+	if len(reservations) == 0 {
+		return nil
+	}
+	index := make(map[string]int, len(reservations))
+	ids := make([]string, len(reservations))
+	for i, res := range reservations {
+		index[res.ID] = i
+		ids[i] = res.ID
+	}
+
+	rows, err := q.Query(`SELECT reservation_id, night, price, COALESCE(rate_plan_id, '') FROM reservation_nights
+		WHERE reservation_id = ANY($1) ORDER BY reservation_id, night;`, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id string
+		var night time.Time
+		var rate model.NightlyRate
+		if err := rows.Scan(&id, &night, &rate.Price, &rate.RatePlanID); err != nil {
+			return err
+		}
+		rate.Date = night.Format("2006-01-02")
+		i := index[id]
+		reservations[i].Nights = append(reservations[i].Nights, rate)
+	}
+	return rows.Err()
+}
+
+// replaceNights regrava o detalhamento por noite; nil mantém o que já está salvo
+func replaceNights(tx *sql.Tx, reservationID string, nights []model.NightlyRate) error {
+	if nights == nil {
+		return nil
+	}
+	if _, err := tx.Exec("DELETE FROM reservation_nights WHERE reservation_id = $1;", reservationID); err != nil {
+		return err
+	}
+	for _, n := range nights {
+		if _, err := tx.Exec("INSERT INTO reservation_nights (reservation_id, night, price, rate_plan_id) VALUES ($1, $2, $3, NULLIF($4, ''));",
+			reservationID, n.Date, n.Price, n.RatePlanID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// withTx executa write numa transação, confirmada só se write não falhar
+func withTx(db *sql.DB, write func(tx *sql.Tx) error) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := write(tx); err != nil {
+		return mapConstraintViolation(err)
+	}
+	return mapConstraintViolation(tx.Commit())
+}
+
+// requireAffected traduz um UPDATE condicionado ao status que não encontrou a linha
+func requireAffected(result sql.Result) error {
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrReservationStatusChanged
+	}
+	return nil
+}
+
+// queryer é satisfeito tanto por *sql.DB quanto por *sql.Tx
+type queryer interface {
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+// rowsQueryer é satisfeito tanto por *sql.DB quanto por *sql.Tx
+type rowsQueryer interface {
+	Query(query string, args ...any) (*sql.Rows, error)
+}
+
+// lockReservation lê a reserva, suas noites e impostos travando a linha até o fim da transação;
+// nil quando não existe
+func lockReservation(tx *sql.Tx, id string) (*model.Reservation, error) {
+	res, err := scanReservation(tx.QueryRow(`SELECT `+reservationColumns+` FROM reservations WHERE id = $1 FOR UPDATE;`, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if res.Nights, err = getNights(tx, id); err != nil {
+		return nil, err
+	}
+	if res.Taxes, err = getTaxes(tx, id); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// auditReservation relê a reserva dentro da transação para registrar o estado gravado
+func auditReservation(tx *sql.Tx, id, action string, actor model.Actor, before *model.Reservation) error {
+	var after *model.Reservation
+	if action != model.AuditActionPurge {
+		var err error
+		if after, err = lockReservation(tx, id); err != nil {
+			return err
+		}
+	}
+	entry, err := newAuditEntry(model.AuditEntityReservation, id, action, actor, before, after)
+	if err != nil {
+		return err
+	}
+	return insertAudit(tx, entry)
+}
+
+// findReservationConflict retorna o ID de uma reserva ativa que colide com o período
+func findReservationConflict(q queryer, roomID string, checkin, checkout any, excludeID string) (string, error) {
 	query := `
-		SELECT COUNT(*) 
-		FROM reservations 
-		WHERE room_id = $1 
+		SELECT id
+		FROM reservations
+		WHERE room_id = $1
 		  AND id != $2
-		  AND status != 'CANCELED'
+		  AND status NOT IN ('CANCELED', 'NO_SHOW')
 		  AND (
 			(checkin_expected, checkout_expected) OVERLAPS ($3::date, $4::date)
-		  );`
-	var count int
-	err := db.GetDB().QueryRow(query, roomID, excludeID, checkin, checkout).Scan(&count)
+		  )
+		ORDER BY checkin_expected
+		LIMIT 1;`
+	var id string
+	err := q.QueryRow(query, roomID, excludeID, checkin, checkout).Scan(&id)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// withRoomLock abre uma transação, trava os quartos do tipo da reserva e recusa a
+// escrita se houver conflito com outra reserva ou com um bloqueio ativo do quarto, ou se
+// o tipo já estiver vendido em alguma noite. A reserva de grupo que cabe no bloco usa os
+// quartos do bloco e não passa pelo estoque do tipo
+func (r *postgresReservationRepository) withRoomLock(res model.Reservation, excludeID string, write func(tx *sql.Tx) error) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockRoomType(tx, res.RoomType, res.RoomID); err != nil {
+		return err
+	}
+
+	if holdsRoom(res.Status) && res.RoomID != "" {
+		conflictID, err := findReservationConflict(tx, res.RoomID, res.CheckinExpected, res.CheckoutExpected, excludeID)
+		if err != nil {
+			return err
+		}
+		if conflictID != "" {
+			return &model.ReservationConflictError{RoomID: res.RoomID, ReservationID: conflictID}
+		}
+		// um bloqueio ativo conta como reserva, menos o que está sendo convertido
+		if err := checkHoldConflict(tx, res.RoomID, res.CheckinExpected, res.CheckoutExpected, res.HoldID); err != nil {
+			return err
+		}
+	}
+	if holdsRoom(res.Status) {
+		// a reserva já contada no estoque com o mesmo tipo e as mesmas datas não é
+		// conferida de novo, para que outras alterações não esbarrem num tipo já cheio
+		counted, err := countedInInventory(tx, res, excludeID)
+		if err != nil {
+			return err
+		}
+		covered := false
+		if !counted {
+			if covered, err = groupAllotment(tx, res, excludeID); err != nil {
+				return err
+			}
+		}
+		if !counted && !covered {
+			if err := checkTypeInventory(tx, res.RoomType, res.CheckinExpected, res.CheckoutExpected, excludeID, res.HoldID); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := write(tx); err != nil {
+		if isExclusionViolation(err) {
+			tx.Rollback()
+			conflictID, _ := findReservationConflict(r.db, res.RoomID, res.CheckinExpected, res.CheckoutExpected, excludeID)
+			return &model.ReservationConflictError{RoomID: res.RoomID, ReservationID: conflictID}
+		}
+		return err
+	}
+	return tx.Commit()
+}
+
+// lockRoomType trava, em ordem de ID, todos os quartos do tipo, o que serializa as
+// escritas que mexem no estoque do tipo sem risco de deadlock entre elas. Com roomID,
+// confere que o quarto existe e é do tipo
+func lockRoomType(tx *sql.Tx, roomType, roomID string) error {
+	if _, err := tx.Exec("SELECT id FROM rooms WHERE type = $1 ORDER BY id FOR UPDATE;", roomType); err != nil {
+		return err
+	}
+	if roomID == "" {
+		return nil
+	}
+	var actual string
+	err := tx.QueryRow("SELECT type FROM rooms WHERE id = $1;", roomID).Scan(&actual)
+	if err == sql.ErrNoRows {
+		return roomMismatch(roomID, "", roomType)
+	}
+	if err != nil {
+		return err
+	}
+	if actual != roomType {
+		return roomMismatch(roomID, actual, roomType)
+	}
+	return nil
+}
+
+// roomMismatch é o erro de validação, igual ao do service, para o quarto apagado
+// (actual vazio) ou que mudou de tipo entre as checagens do service e a escrita
+func roomMismatch(roomID, actual, roomType string) error {
+	var v model.ValidationError
+	if actual == "" {
+		v.Add("room_id", "not_found", fmt.Sprintf("room %s not found", roomID))
+	} else {
+		v.Add("room_id", "type_mismatch", fmt.Sprintf("room %s is a %s room and the reservation is for %s", roomID, actual, roomType))
+	}
+	return v.Err()
+}
+
+// countedInInventory indica se a reserva excludeID já está gravada ocupando o estoque do
+// tipo com as mesmas datas de res
+func countedInInventory(q queryer, res model.Reservation, excludeID string) (bool, error) {
+	if excludeID == "" {
+		return false, nil
+	}
+	var counted bool
+	err := q.QueryRow(`SELECT room_type = $2 AND checkin_expected = $3::date AND checkout_expected = $4::date
+			AND status NOT IN ('CANCELED', 'NO_SHOW')
+		FROM reservations WHERE id = $1;`,
+		excludeID, res.RoomType, res.CheckinExpected, res.CheckoutExpected).Scan(&counted)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	return counted, err
+}
+
+// checkTypeInventory recusa o período em que alguma noite já tem todos os quartos do
+// tipo, somados os extras da regra de overbooking da noite, ocupados por reservas, com ou
+// sem quarto, e bloqueios ativos; excludeID e excludeHoldID são a reserva sendo alterada
+// e o bloqueio sendo convertido
+func checkTypeInventory(q queryer, roomType string, checkin, checkout any, excludeID, excludeHoldID string) error {
+	total, err := roomTypeTotal(q, roomType)
+	if err != nil {
+		return err
+	}
+	free, night, err := typeHeadroom(q, roomType, total, checkin, checkout, excludeID, excludeHoldID)
+	if err != nil {
+		return err
+	}
+	if free <= 0 {
+		return &model.RoomTypeSoldOutError{RoomType: roomType, Date: night}
+	}
+	return nil
+}
+
+// typeHeadroom retorna quantas reservas do tipo ainda cabem na noite mais apertada do
+// período, e que noite é essa: os total quartos mais os extras do overbooking, menos as
+// reservas e bloqueios ativos e os quartos ainda não usados dos blocos de grupo ACTIVE
+func typeHeadroom(q queryer, roomType string, total int, checkin, checkout any, excludeID, excludeHoldID string) (int, string, error) {
+	query := `
+		SELECT d::date, ` + typeFreeSQL("$1", "$6::int", "$4", "$5") + ` AS free
+		FROM generate_series($2::date, $3::date - 1, interval '1 day') d
+		ORDER BY free, d
+		LIMIT 1;`
+	var night time.Time
+	var free int
+	err := q.QueryRow(query, roomType, checkin, checkout, excludeID, excludeHoldID, total).Scan(&night, &free)
+	if err == sql.ErrNoRows {
+		return total, "", nil
+	}
+	if err != nil {
+		return 0, "", err
+	}
+	return free, night.Format("2006-01-02"), nil
+}
+
+// typeFreeSQL é a expressão de typeHeadroom na noite d da generate_series: os total
+// quartos do tipo mais os extras do overbooking, menos as reservas e bloqueios ativos e os
+// quartos ainda não usados dos blocos de grupo
+func typeFreeSQL(roomType, total, excludeID, excludeHoldID string) string {
+	return total + ` + ` + overbookingAllowanceSQL(roomType, total) + `
+		     - (SELECT COUNT(*) FROM reservations
+		         WHERE room_type = ` + roomType + ` AND id != ` + excludeID + ` AND status NOT IN ('CANCELED', 'NO_SHOW')
+		           AND checkin_expected <= d::date AND checkout_expected > d::date)
+		     - (SELECT COUNT(*) FROM room_holds h JOIN rooms r ON r.id = h.room_id
+		         WHERE r.type = ` + roomType + ` AND h.id != ` + excludeHoldID + ` AND h.expires_at > now()
+		           AND h.checkin_expected <= d::date AND h.checkout_expected > d::date)
+		     - ` + groupBlockSQL(roomType, excludeID)
+}
+
+// groupBlockSQL é a expressão dos quartos dos blocos de grupo ACTIVE do tipo ainda não
+// usados por reservas do grupo na noite d da generate_series; excludeID é a reserva sendo
+// alterada
+func groupBlockSQL(roomType, excludeID string) string {
+	return `(SELECT COALESCE(SUM(GREATEST(b.rooms - (SELECT COUNT(*) FROM reservations p
+		           WHERE p.group_id = b.group_id AND p.room_type = b.room_type AND p.id != ` + excludeID + `
+		             AND p.status NOT IN ('CANCELED', 'NO_SHOW')
+		             AND p.checkin_expected <= d::date AND p.checkout_expected > d::date), 0)), 0)
+		         FROM group_blocks b JOIN booking_groups g ON g.id = b.group_id
+		         WHERE b.room_type = ` + roomType + ` AND g.status = 'ACTIVE'
+		           AND g.checkin_expected <= d::date AND g.checkout_expected > d::date)::int`
+}
+
+// groupAllotment indica se a reserva de grupo cabe no bloco: o grupo está ACTIVE, tem
+// quartos do tipo, a estadia está dentro das datas do bloco e nenhuma noite já tem todos
+// os quartos do bloco usados. Trava o grupo para que o cancelamento não perca a reserva,
+// e recusa a reserva nova de um grupo cancelado com ErrGroupCanceled; as que ficaram no
+// grupo cancelado, já hospedadas, passam pelo estoque do tipo
+func groupAllotment(tx *sql.Tx, res model.Reservation, excludeID string) (bool, error) {
+	if res.GroupID == "" {
+		return false, nil
+	}
+	var status string
+	var checkin, checkout time.Time
+	var rooms sql.NullInt64
+	err := tx.QueryRow(`SELECT g.status, g.checkin_expected, g.checkout_expected, b.rooms
+		FROM booking_groups g LEFT JOIN group_blocks b ON b.group_id = g.id AND b.room_type = $2
+		WHERE g.id = $1
+		FOR SHARE OF g;`, res.GroupID, res.RoomType).Scan(&status, &checkin, &checkout, &rooms)
+	if err != nil && err != sql.ErrNoRows {
+		return false, err
+	}
+	if err == sql.ErrNoRows || status == model.GroupCanceled {
+		if excludeID == "" {
+			return false, ErrGroupCanceled
+		}
+		return false, nil
+	}
+	group := model.Group{CheckinExpected: checkin.Format("2006-01-02"), CheckoutExpected: checkout.Format("2006-01-02")}
+	if status != model.GroupActive || !rooms.Valid || !group.Covers(res.CheckinExpected, res.CheckoutExpected) {
+		return false, nil
+	}
+	var used int
+	err = tx.QueryRow(`SELECT COALESCE(MAX((SELECT COUNT(*) FROM reservations
+			WHERE group_id = $1 AND room_type = $2 AND id != $5 AND status NOT IN ('CANCELED', 'NO_SHOW')
+			  AND checkin_expected <= d::date AND checkout_expected > d::date)), 0)
+		FROM generate_series($3::date, $4::date - 1, interval '1 day') d;`,
+		res.GroupID, res.RoomType, res.CheckinExpected, res.CheckoutExpected, excludeID).Scan(&used)
 	if err != nil {
 		return false, err
 	}
-	return count > 0, nil
+	return used < int(rooms.Int64), nil
+}
+
+// holdsRoom indica se a reserva ocupa o quarto; canceladas e no-shows liberam as noites,
+// como no WHERE de reservations_no_overlap
+func holdsRoom(status string) bool {
+	return status != "CANCELED" && status != "NO_SHOW"
+}
+
+// awaitingArrival indica a reserva que ainda espera o hóspede: CREATED ou, com o
+// depósito pré-autorizado, CONFIRMED
+func awaitingArrival(status string) bool {
+	return status == "CREATED" || status == "CONFIRMED"
+}
+
+// folioOpen indica a reserva cuja conta ainda aceita lançamentos
+func folioOpen(status string) bool {
+	return awaitingArrival(status) || status == "CHECKED_IN"
+}
+
+// isExclusionViolation identifica o erro 23P01 (exclusion_violation) do Postgres
+func isExclusionViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23P01"
+}
+
+// mapConstraintViolation embrulha a violação de unicidade (23505) ou de exclusão (23P01)
+// que nenhum erro mais específico traduziu; o erro do driver continua acessível por errors.As
+func mapConstraintViolation(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && (pqErr.Code == "23505" || pqErr.Code == "23P01") {
+		return &model.ConstraintViolationError{Err: err}
+	}
+	return err
 }
@@ -0,0 +1,417 @@
+package dao
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"hotel-soa/model"
+
+	"github.com/lib/pq"
+)
+
+func TestInsertReservationConflict(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, repos Repositories) {
+		room := insertTestRoom(t, repos, "STANDARD")
+		booked := insertTestReservation(t, repos, room.ID, "2026-06-10", "2026-06-13")
+
+		res := newTestReservation(t, repos, room.ID, "2026-06-12", "2026-06-14")
+		_, err := repos.Reservations.InsertReservation(res, testActor)
+		var conflict *model.ReservationConflictError
+		if !errors.As(err, &conflict) || conflict.ReservationID != booked.ID {
+			t.Fatalf("expected a conflict with %s, got %v", booked.ID, err)
+		}
+
+		// cancelada não ocupa o quarto
+		res.Status = "CANCELED"
+		if _, err := repos.Reservations.InsertReservation(res, testActor); err != nil {
+			t.Fatalf("expected a canceled reservation to be stored, got %v", err)
+		}
+	})
+}
+
+func TestUpdateReservationConflict(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, repos Repositories) {
+		room := insertTestRoom(t, repos, "STANDARD")
+		booked := insertTestReservation(t, repos, room.ID, "2026-06-10", "2026-06-13")
+		moving := insertTestReservation(t, repos, room.ID, "2026-06-20", "2026-06-22")
+
+		// a própria reserva não conta como conflito
+		moving.CheckoutExpected = "2026-06-23"
+		if err := repos.Reservations.UpdateReservation(moving, testActor); err != nil {
+			t.Fatal(err)
+		}
+
+		moving.CheckinExpected = "2026-06-12"
+		err := repos.Reservations.UpdateReservation(moving, testActor)
+		var conflict *model.ReservationConflictError
+		if !errors.As(err, &conflict) || conflict.ReservationID != booked.ID {
+			t.Fatalf("expected a conflict with %s, got %v", booked.ID, err)
+		}
+		if got, _ := repos.Reservations.GetReservationByID(moving.ID); day(got.CheckinExpected) != "2026-06-20" {
+			t.Fatalf("expected the refused update to leave the reservation unchanged, got %+v", got)
+		}
+	})
+}
+
+func TestReservationNights(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, repos Repositories) {
+		room := insertTestRoom(t, repos, "STANDARD")
+		res := newTestReservation(t, repos, room.ID, "2026-06-10", "2026-06-12")
+		res.TotalAmount = model.NewMoney(23000)
+		res.Nights = []model.NightlyRate{{Date: "2026-06-10", Price: model.NewMoney(10000)}, {Date: "2026-06-11", Price: model.NewMoney(13000)}}
+		id, err := repos.Reservations.InsertReservation(res, testActor)
+		if err != nil {
+			t.Fatal(err)
+		}
+		nights := func() []model.NightlyRate {
+			got, err := repos.Reservations.GetReservationByID(id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i := range got.Nights {
+				got.Nights[i].Date = day(got.Nights[i].Date)
+			}
+			return got.Nights
+		}
+		if got := nights(); !reflect.DeepEqual(got, res.Nights) {
+			t.Fatalf("expected nights %+v, got %+v", res.Nights, got)
+		}
+
+		// sem noites no update, o detalhamento salvo é mantido
+		res.ID, res.Nights = id, nil
+		res.GuestName = "Other Guest"
+		if err := repos.Reservations.UpdateReservation(res, testActor); err != nil {
+			t.Fatal(err)
+		}
+		if got := nights(); len(got) != 2 {
+			t.Fatalf("expected the stored nights to be kept, got %+v", got)
+		}
+
+		res.CheckoutExpected = "2026-06-11"
+		res.Nights = []model.NightlyRate{{Date: "2026-06-10", Price: model.NewMoney(10000)}}
+		if err := repos.Reservations.UpdateReservation(res, testActor); err != nil {
+			t.Fatal(err)
+		}
+		if got := nights(); !reflect.DeepEqual(got, res.Nights) {
+			t.Fatalf("expected nights %+v, got %+v", res.Nights, got)
+		}
+
+		// a listagem não traz o detalhamento por noite
+		all, err := repos.Reservations.GetAllReservations()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, r := range all {
+			if r.Nights != nil {
+				t.Fatalf("expected no nights in the list, got %+v", r)
+			}
+		}
+	})
+}
+
+func TestCheckInCheckOutReservation(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, repos Repositories) {
+		room := insertTestRoom(t, repos, "STANDARD")
+		res := insertTestReservation(t, repos, room.ID, "2026-06-10", "2026-06-13")
+		at := time.Date(2026, 6, 10, 15, 0, 0, 0, time.UTC)
+
+		if err := repos.Reservations.CheckInReservation(res.ID, at, "maria", testActor); err != nil {
+			t.Fatal(err)
+		}
+		// a segunda tentativa encontra a reserva fora de CREATED
+		if err := repos.Reservations.CheckInReservation(res.ID, at, "joao", testActor); !errors.Is(err, ErrReservationStatusChanged) {
+			t.Fatalf("expected ErrReservationStatusChanged, got %v", err)
+		}
+		got, err := repos.Reservations.GetReservationByID(res.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Status != "CHECKED_IN" || got.CheckedInBy != "maria" || got.CheckedInAt == nil || !got.CheckedInAt.Equal(at) {
+			t.Fatalf("expected the check-in by maria at %v, got %+v", at, got)
+		}
+
+		// o PUT não apaga o registro do check-in
+		got.GuestName = "Other Guest"
+		if err := repos.Reservations.UpdateReservation(got, testActor); err != nil {
+			t.Fatal(err)
+		}
+
+		got.CheckoutExpected, got.TotalAmount = "2026-06-11", model.NewMoney(10000)
+		got.Nights = []model.NightlyRate{{Date: "2026-06-10", Price: model.NewMoney(10000)}}
+		// a conta final precisa estar paga
+		var due *model.BalanceDueError
+		if err := repos.Reservations.CheckOutReservation(got, at.AddDate(0, 0, 1), "joao", testActor); !errors.As(err, &due) || !due.Balance.Equal(model.NewMoney(10000)) {
+			t.Fatalf("expected a balance of 100 due, got %v", err)
+		}
+		if _, err := repos.Folios.PostPayment(model.Payment{ReservationID: res.ID, Type: model.PaymentTypePayment, Method: "CASH",
+			Amount: model.NewMoney(10000), PostedAt: at, PostedBy: "joao"}, testActor); err != nil {
+			t.Fatal(err)
+		}
+		if err := repos.Reservations.CheckOutReservation(got, at.AddDate(0, 0, 1), "joao", testActor); err != nil {
+			t.Fatal(err)
+		}
+		if err := repos.Reservations.CheckOutReservation(got, at, "joao", testActor); !errors.Is(err, ErrReservationStatusChanged) {
+			t.Fatalf("expected ErrReservationStatusChanged, got %v", err)
+		}
+		got, err = repos.Reservations.GetReservationByID(res.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Status != "CHECKED_OUT" || got.CheckedOutBy != "joao" || got.CheckedInBy != "maria" ||
+			day(got.CheckoutExpected) != "2026-06-11" || !got.TotalAmount.Equal(model.NewMoney(10000)) || len(got.Nights) != 1 {
+			t.Fatalf("unexpected reservation after check-out %+v", got)
+		}
+	})
+}
+
+func TestCancelReservation(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, repos Repositories) {
+		room := insertTestRoom(t, repos, "STANDARD")
+		res := insertTestReservation(t, repos, room.ID, "2026-06-10", "2026-06-12")
+		at := time.Date(2026, 6, 1, 9, 0, 0, 0, time.UTC)
+
+		if err := repos.Reservations.CancelReservation(res.ID, at, "guest request", model.CancellationQuote{Fee: model.NewMoney(5000), RefundAmount: model.NewMoney(15000)}, model.Actor{Username: "maria"}); err != nil {
+			t.Fatal(err)
+		}
+		got, err := repos.Reservations.GetReservationByID(res.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Status != "CANCELED" || got.CanceledAt == nil || !got.CanceledAt.Equal(at) ||
+			got.CanceledBy != "maria" || got.CancellationReason != "guest request" ||
+			got.CancellationFee == nil || !got.CancellationFee.Equal(model.NewMoney(5000)) || got.RefundAmount == nil || !got.RefundAmount.Equal(model.NewMoney(15000)) {
+			t.Fatalf("expected the cancellation to be stored, got %+v", got)
+		}
+
+		// só reservas CREATED podem ser canceladas, e as canceladas liberam o período
+		if err := repos.Reservations.CancelReservation(res.ID, at, "", model.CancellationQuote{}, testActor); !errors.Is(err, ErrReservationStatusChanged) {
+			t.Fatalf("expected ErrReservationStatusChanged, got %v", err)
+		}
+		if conflict, err := repos.Reservations.HasReservationConflict(room.ID, date("2026-06-10"), date("2026-06-12"), ""); err != nil || conflict {
+			t.Fatalf("expected no conflict with a canceled reservation, got %v, %v", conflict, err)
+		}
+	})
+}
+
+func TestNoShowAndOverstay(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, repos Repositories) {
+		room := insertTestRoom(t, repos, "STANDARD")
+		other := insertTestRoom(t, repos, "STANDARD")
+		late := insertTestReservation(t, repos, room.ID, "2026-06-10", "2026-06-12")
+		insertTestReservation(t, repos, room.ID, "2026-06-12", "2026-06-14")
+		staying := insertTestReservation(t, repos, other.ID, "2026-06-08", "2026-06-10")
+		at := time.Date(2026, 6, 11, 7, 0, 0, 0, time.UTC)
+		if err := repos.Reservations.CheckInReservation(staying.ID, date("2026-06-08"), "maria", testActor); err != nil {
+			t.Fatal(err)
+		}
+
+		unarrived, err := repos.Reservations.GetUnarrivedReservations(date("2026-06-10"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(unarrived) != 1 || unarrived[0].ID != late.ID {
+			t.Fatalf("expected only %s, got %+v", late.ID, unarrived)
+		}
+
+		quote := model.CancellationQuote{Fee: model.NewMoney(10000), RefundAmount: model.NewMoney(10000)}
+		if err := repos.Reservations.MarkNoShow(late.ID, at, quote, model.SystemActor); err != nil {
+			t.Fatal(err)
+		}
+		got, err := repos.Reservations.GetReservationByID(late.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Status != "NO_SHOW" || got.NoShowAt == nil || !got.NoShowAt.Equal(at) ||
+			got.NoShowFee == nil || !got.NoShowFee.Equal(model.NewMoney(10000)) || got.RefundAmount == nil || !got.RefundAmount.Equal(model.NewMoney(10000)) {
+			t.Fatalf("expected the no-show to be stored, got %+v", got)
+		}
+		if err := repos.Reservations.MarkNoShow(late.ID, at, quote, model.SystemActor); !errors.Is(err, ErrReservationStatusChanged) {
+			t.Fatalf("expected ErrReservationStatusChanged, got %v", err)
+		}
+		// o no-show libera o quarto
+		if conflict, err := repos.Reservations.HasReservationConflict(room.ID, date("2026-06-10"), date("2026-06-12"), ""); err != nil || conflict {
+			t.Fatalf("expected no conflict with a no-show, got %v, %v", conflict, err)
+		}
+
+		// cada hóspede é sinalizado uma vez só
+		for i, expected := range []int{1, 0} {
+			flagged, err := repos.Reservations.FlagOverstays(date("2026-06-10"), at.Add(time.Duration(i)*time.Hour), model.SystemActor)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if flagged != expected {
+				t.Fatalf("run %d: expected %d overstay(s), got %d", i, expected, flagged)
+			}
+		}
+		alerts, err := repos.Reservations.GetOverstayAlerts()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(alerts) != 1 || alerts[0].ReservationID != staying.ID || alerts[0].Type != model.AlertOverstay || !alerts[0].FlaggedAt.Equal(at) {
+			t.Fatalf("expected an overstay alert for %s, got %+v", staying.ID, alerts)
+		}
+
+		// depois do check-out o alerta some
+		staying.CheckoutExpected = "2026-06-11"
+		if err := repos.Reservations.CheckOutReservation(staying, at, "maria", testActor); err != nil {
+			t.Fatal(err)
+		}
+		if alerts, err := repos.Reservations.GetOverstayAlerts(); err != nil || len(alerts) != 0 {
+			t.Fatalf("expected no alerts after check-out, got %+v, %v", alerts, err)
+		}
+	})
+}
+
+func TestRoomTypeInventory(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, repos Repositories) {
+		first := insertTestRoom(t, repos, "DELUXE")
+		second := insertTestRoom(t, repos, "DELUXE")
+		insertTestRoom(t, repos, "STANDARD")
+
+		// reservas só pelo tipo ocupam o estoque sem quarto
+		unassigned := func(checkin, checkout string) model.Reservation {
+			return model.Reservation{RoomType: "DELUXE", GuestID: insertTestGuest(t, repos, "Test Guest"), GuestName: "Test Guest",
+				CheckinExpected: checkin, CheckoutExpected: checkout, Status: "CREATED", TotalAmount: model.NewMoney(20000)}
+		}
+		short := unassigned("2026-06-10", "2026-06-12")
+		id, err := repos.Reservations.InsertReservation(short, testActor)
+		if err != nil {
+			t.Fatal(err)
+		}
+		short.ID = id
+		long := unassigned("2026-06-10", "2026-06-13")
+		if long.ID, err = repos.Reservations.InsertReservation(long, testActor); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = repos.Reservations.InsertReservation(unassigned("2026-06-11", "2026-06-12"), testActor)
+		var soldOut *model.RoomTypeSoldOutError
+		if !errors.As(err, &soldOut) || soldOut.RoomType != "DELUXE" || soldOut.Date != "2026-06-11" {
+			t.Fatalf("expected a RoomTypeSoldOutError for 2026-06-11, got %v", err)
+		}
+		// o quarto está livre, mas o tipo não
+		if _, err := repos.Reservations.InsertReservation(newTestReservation(t, repos, first.ID, "2026-06-11", "2026-06-12"), testActor); !errors.As(err, &soldOut) {
+			t.Fatalf("expected a RoomTypeSoldOutError for an assigned room, got %v", err)
+		}
+		if _, err := repos.Holds.InsertHold(model.RoomHold{RoomID: second.ID, TokenHash: "h", CheckinExpected: "2026-06-11", CheckoutExpected: "2026-06-13",
+			ExpiresAt: time.Now().Add(time.Hour)}); !errors.As(err, &soldOut) || soldOut.Date != "2026-06-11" {
+			t.Fatalf("expected a RoomTypeSoldOutError for a hold, got %v", err)
+		}
+		if _, err := repos.Reservations.InsertReservation(unassigned("2026-06-13", "2026-06-14"), testActor); err != nil {
+			t.Fatalf("expected the type to have rooms after the stays, got %v", err)
+		}
+
+		types, err := repos.Rooms.GetRoomTypeAvailability(date("2026-06-10"), date("2026-06-12"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		available := map[string][2]int{}
+		for _, tt := range types {
+			available[tt.RoomType] = [2]int{tt.TotalRooms, tt.Available}
+		}
+		if len(types) != 2 || available["DELUXE"] != [2]int{2, 0} || available["STANDARD"] != [2]int{1, 1} {
+			t.Fatalf("unexpected availability %+v", types)
+		}
+		// os dois DELUXE estão sem reserva, mas o tipo esgotou
+		rooms, err := repos.Rooms.GetAvailableRooms(date("2026-06-10"), date("2026-06-12"), 1, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(rooms) != 1 || rooms[0].Type != "STANDARD" {
+			t.Fatalf("expected only the STANDARD room, got %+v", rooms)
+		}
+		if rooms, err := repos.Rooms.GetAvailableRooms(date("2026-06-14"), date("2026-06-15"), 1, "DELUXE"); err != nil || len(rooms) != 2 {
+			t.Fatalf("expected both DELUXE rooms after the stays, got %+v, %v", rooms, err)
+		}
+
+		// as pendentes saem por chegada, das estadias mais longas para as mais curtas
+		pending, err := repos.Reservations.GetUnassignedReservations(date("2026-06-10"), date("2026-06-10"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(pending) != 2 || pending[0].ID != long.ID || pending[1].ID != short.ID {
+			t.Fatalf("expected the longer stay first, got %+v", pending)
+		}
+
+		if err := repos.Reservations.AssignRoom(short.ID, first.ID, testActor); err != nil {
+			t.Fatal(err)
+		}
+		var conflict *model.ReservationConflictError
+		if err := repos.Reservations.AssignRoom(long.ID, first.ID, testActor); !errors.As(err, &conflict) || conflict.ReservationID != short.ID {
+			t.Fatalf("expected a ReservationConflictError, got %v", err)
+		}
+		if stored, _ := repos.Reservations.GetReservationByID(short.ID); stored.RoomID != first.ID || stored.RoomType != "DELUXE" {
+			t.Fatalf("expected the room to be assigned, got %+v", stored)
+		}
+		if pending, _ := repos.Reservations.GetUnassignedReservations(date("2026-06-10"), date("2026-06-10")); len(pending) != 1 || pending[0].ID != long.ID {
+			t.Fatalf("expected only the unassigned stay, got %+v", pending)
+		}
+
+		bookings, err := repos.Reservations.GetRoomTypeBookings("DELUXE", date("2026-06-01"), date("2026-06-13"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(bookings) != 2 {
+			t.Fatalf("expected both stays touching the range, got %+v", bookings)
+		}
+
+		if err := repos.Reservations.CancelReservation(long.ID, time.Now(), "", model.CancellationQuote{}, testActor); err != nil {
+			t.Fatal(err)
+		}
+		if err := repos.Reservations.AssignRoom(long.ID, second.ID, testActor); !errors.Is(err, ErrReservationStatusChanged) {
+			t.Fatalf("expected ErrReservationStatusChanged for a cancelled reservation, got %v", err)
+		}
+	})
+}
+
+func TestMapConstraintViolation(t *testing.T) {
+	unique := &pq.Error{Code: "23505", Message: "duplicate key value violates unique constraint"}
+	var constraint *model.ConstraintViolationError
+	if err := mapConstraintViolation(unique); !errors.As(err, &constraint) {
+		t.Fatalf("expected a ConstraintViolationError, got %v", err)
+	}
+	var pqErr *pq.Error
+	if err := mapConstraintViolation(unique); !errors.As(err, &pqErr) || pqErr != unique {
+		t.Fatalf("expected the driver error to stay reachable, got %v", err)
+	}
+	for _, err := range []error{nil, &pq.Error{Code: "23503"}, ErrNightsClosed} {
+		if got := mapConstraintViolation(err); got != err {
+			t.Fatalf("expected %v unchanged, got %v", err, got)
+		}
+	}
+}
+
+func TestRoomTypeStockCountsActiveRooms(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, repos Repositories) {
+		insertTestRoom(t, repos, "DELUXE")
+		inactive := insertTestRoom(t, repos, "DELUXE")
+		suite := insertTestRoom(t, repos, "SUITE")
+		for _, room := range []model.Room{inactive, suite} {
+			room.Status = "INATIVO"
+			if err := repos.Rooms.UpdateRoom(room, testActor); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		// o tipo só com quartos INATIVO continua listado, com estoque zero
+		types, err := repos.Rooms.GetRoomTypeAvailability(date("2026-06-10"), date("2026-06-12"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(types) != 2 || types[0].RoomType != "DELUXE" || types[0].TotalRooms != 1 || types[1].RoomType != "SUITE" || types[1].TotalRooms != 0 || types[1].Available != 0 {
+			t.Fatalf("expected only the ATIVO rooms in stock, got %+v", types)
+		}
+
+		res := model.Reservation{RoomType: "DELUXE", GuestID: insertTestGuest(t, repos, "Test Guest"), GuestName: "Test Guest",
+			CheckinExpected: "2026-06-10", CheckoutExpected: "2026-06-12", Status: "CREATED", TotalAmount: model.NewMoney(20000)}
+		if _, err := repos.Reservations.InsertReservation(res, testActor); err != nil {
+			t.Fatal(err)
+		}
+		var soldOut *model.RoomTypeSoldOutError
+		if _, err := repos.Reservations.InsertReservation(res, testActor); !errors.As(err, &soldOut) {
+			t.Fatalf("expected the INATIVO room to stay out of the stock, got %v", err)
+		}
+	})
+}
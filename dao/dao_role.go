@@ -0,0 +1,119 @@
+package dao
+
+import (
+	"database/sql"
+	"hotel-soa/model"
+)
+
+type postgresRoleRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresRoleRepository cria um RoleRepository apoiado no Postgres
+func NewPostgresRoleRepository(conn *sql.DB) RoleRepository {
+	return &postgresRoleRepository{db: conn}
+}
+
+func (r *postgresRoleRepository) GetAllRoles() ([]model.Role, error) {
+	return r.queryRoles(`SELECT r.name, r.description, r.built_in, rp.permission
+		FROM roles r LEFT JOIN role_permissions rp ON rp.role = r.name
+		ORDER BY r.name, rp.permission;`)
+}
+
+func (r *postgresRoleRepository) GetRole(name string) (model.Role, error) {
+	roles, err := r.queryRoles(`SELECT r.name, r.description, r.built_in, rp.permission
+		FROM roles r LEFT JOIN role_permissions rp ON rp.role = r.name
+		WHERE r.name = $1
+		ORDER BY rp.permission;`, name)
+	if err != nil || len(roles) == 0 {
+		return model.Role{}, err
+	}
+	return roles[0], nil
+}
+
+func (r *postgresRoleRepository) GetUserRoles(userID string) ([]model.Role, error) {
+	return r.queryRoles(`SELECT r.name, r.description, r.built_in, rp.permission
+		FROM user_roles ur
+		JOIN roles r ON r.name = ur.role
+		LEFT JOIN role_permissions rp ON rp.role = r.name
+		WHERE ur.user_id = $1
+		ORDER BY r.name, rp.permission;`, userID)
+}
+
+// queryRoles agrupa as linhas papel × permissão; a consulta precisa vir ordenada por papel
+func (r *postgresRoleRepository) queryRoles(query string, args ...any) ([]model.Role, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var roles []model.Role
+	for rows.Next() {
+		var role model.Role
+		var permission sql.NullString
+		if err := rows.Scan(&role.Name, &role.Description, &role.BuiltIn, &permission); err != nil {
+			return nil, err
+		}
+		if len(roles) == 0 || roles[len(roles)-1].Name != role.Name {
+			role.Permissions = []string{}
+			roles = append(roles, role)
+		}
+		if permission.Valid {
+			last := &roles[len(roles)-1]
+			last.Permissions = append(last.Permissions, permission.String)
+		}
+	}
+	return roles, rows.Err()
+}
+
+func (r *postgresRoleRepository) SaveRole(role model.Role) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`INSERT INTO roles (name, description, built_in) VALUES ($1, $2, $3)
+		ON CONFLICT (name) DO UPDATE SET description = EXCLUDED.description, built_in = EXCLUDED.built_in;`,
+		role.Name, role.Description, role.BuiltIn)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM role_permissions WHERE role = $1;", role.Name); err != nil {
+		return err
+	}
+	for _, permission := range role.Permissions {
+		if _, err := tx.Exec("INSERT INTO role_permissions (role, permission) VALUES ($1, $2);", role.Name, permission); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (r *postgresRoleRepository) DeleteRole(name string) (bool, error) {
+	result, err := r.db.Exec("DELETE FROM roles WHERE name = $1;", name)
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	return n > 0, err
+}
+
+func (r *postgresRoleRepository) SetUserRoles(userID string, roles []string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM user_roles WHERE user_id = $1;", userID); err != nil {
+		return err
+	}
+	for _, role := range roles {
+		if _, err := tx.Exec("INSERT INTO user_roles (user_id, role) VALUES ($1, $2);", userID, role); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
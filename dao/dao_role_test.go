@@ -0,0 +1,106 @@
+package dao
+
+import (
+	"reflect"
+	"testing"
+
+	"hotel-soa/model"
+)
+
+// roleNames devolve só os nomes, na ordem recebida
+func roleNames(roles []model.Role) []string {
+	names := []string{}
+	for _, role := range roles {
+		names = append(names, role.Name)
+	}
+	return names
+}
+
+func TestRoleRepository(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, repos Repositories) {
+		night := model.Role{Name: "night_audit", Description: "Night shift", Permissions: []string{model.PermRoomsRead, model.PermGuestsRead}}
+		if err := repos.Roles.SaveRole(night); err != nil {
+			t.Fatal(err)
+		}
+		if err := repos.Roles.SaveRole(model.Role{Name: "auditor", Permissions: []string{}, BuiltIn: true}); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := repos.Roles.GetRole("night_audit")
+		if err != nil {
+			t.Fatal(err)
+		}
+		// as permissões voltam ordenadas
+		want := model.Role{Name: "night_audit", Description: "Night shift", Permissions: []string{model.PermGuestsRead, model.PermRoomsRead}}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("expected %+v, got %+v", want, got)
+		}
+
+		// salvar de novo substitui descrição e permissões
+		night.Description, night.Permissions = "Audit", []string{model.PermReservationsRead}
+		if err := repos.Roles.SaveRole(night); err != nil {
+			t.Fatal(err)
+		}
+		if got, _ := repos.Roles.GetRole("night_audit"); got.Description != "Audit" || !reflect.DeepEqual(got.Permissions, night.Permissions) {
+			t.Fatalf("expected the role to be replaced, got %+v", got)
+		}
+
+		all, err := repos.Roles.GetAllRoles()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if names := roleNames(all); !reflect.DeepEqual(names, []string{"auditor", "night_audit"}) || !all[0].BuiltIn || len(all[0].Permissions) != 0 {
+			t.Fatalf("unexpected roles %+v", all)
+		}
+
+		if got, err := repos.Roles.GetRole("missing"); err != nil || got.Name != "" {
+			t.Fatalf("expected an empty role, got %+v, %v", got, err)
+		}
+	})
+}
+
+func TestUserRoles(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, repos Repositories) {
+		for _, name := range []string{"front_desk", "night_audit"} {
+			if err := repos.Roles.SaveRole(model.Role{Name: name, Permissions: []string{model.PermRoomsRead}}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		ana := insertTestUser(t, repos, "ana")
+		insertTestUser(t, repos, "bruno")
+
+		if err := repos.Roles.SetUserRoles(ana.ID, []string{"night_audit", "front_desk"}); err != nil {
+			t.Fatal(err)
+		}
+		roles, err := repos.Roles.GetUserRoles(ana.ID)
+		if err != nil || !reflect.DeepEqual(roleNames(roles), []string{"front_desk", "night_audit"}) {
+			t.Fatalf("expected front_desk and night_audit, got %+v, %v", roles, err)
+		}
+		if err := repos.Roles.SetUserRoles(ana.ID, []string{"missing"}); err == nil {
+			t.Fatal("expected an error assigning an unknown role")
+		}
+
+		// remover o papel o tira dos usuários
+		if deleted, err := repos.Roles.DeleteRole("night_audit"); err != nil || !deleted {
+			t.Fatalf("expected the role to be deleted, got %v, %v", deleted, err)
+		}
+		if deleted, err := repos.Roles.DeleteRole("night_audit"); err != nil || deleted {
+			t.Fatalf("expected a second delete to find nothing, got %v, %v", deleted, err)
+		}
+		if roles, _ := repos.Roles.GetUserRoles(ana.ID); !reflect.DeepEqual(roleNames(roles), []string{"front_desk"}) {
+			t.Fatalf("expected only front_desk, got %+v", roles)
+		}
+
+		users, err := repos.Users.GetAllUsers()
+		if err != nil {
+			t.Fatal(err)
+		}
+		usernames := []string{}
+		for _, user := range users {
+			usernames = append(usernames, user.Username)
+		}
+		if !reflect.DeepEqual(usernames, []string{"ana", "bruno"}) {
+			t.Fatalf("expected ana and bruno, got %v", usernames)
+		}
+	})
+}
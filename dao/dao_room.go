@@ -2,51 +2,177 @@ package dao
 
 import (
 	"database/sql"
-	"hotel-soa/db"
+	"errors"
 	"hotel-soa/model"
+	"strconv"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
-func InsertRoom(room model.Room) (string, error) {
-	query := "INSERT INTO rooms (id, number, type, capacity, price_per_night, status) VALUES ($1, $2, $3, $4, $5, $6) ON CONFLICT (number) DO NOTHING;"
-	_, err := db.GetDB().Exec(query, uuid.NewString(), room.Number, room.Type, room.Capacity, room.PricePerNight, room.Status)
+// ErrDuplicateRoomNumber indica que já existe quarto não arquivado com o mesmo número
+var ErrDuplicateRoomNumber = errors.New("room number already taken")
+
+type postgresRoomRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresRoomRepository cria um RoomRepository apoiado no Postgres
+func NewPostgresRoomRepository(conn *sql.DB) RoomRepository {
+	return &postgresRoomRepository{db: conn}
+}
+
+func (r *postgresRoomRepository) InsertRoom(room model.Room, actor model.Actor) (string, error) {
+	id := uuid.NewString()
+	err := withTx(r.db, func(tx *sql.Tx) error {
+		query := `INSERT INTO rooms (id, number, type, capacity, price_per_night, status, cancellation_policy_id)
+			VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, '')) ON CONFLICT (number) WHERE deleted_at IS NULL DO NOTHING;`
+		result, err := tx.Exec(query, id, room.Number, room.Type, room.Capacity, room.PricePerNight, room.Status, room.CancellationPolicyID)
+		if err != nil {
+			return err
+		}
+		n, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return ErrDuplicateRoomNumber
+		}
+		room.ID = id
+		return auditRoom(tx, id, model.AuditActionCreate, actor, nil, &room)
+	})
 	if err != nil {
 		return "", err
 	}
-	return room.ID, nil
+	return id, nil
+}
+
+func (r *postgresRoomRepository) UpdateRoom(room model.Room, actor model.Actor) error {
+	return withTx(r.db, func(tx *sql.Tx) error {
+		before, err := lockRoom(tx, room.ID)
+		if err != nil || before == nil {
+			return err
+		}
+		// as reservas ativas no quarto entram no estoque do tipo antigo
+		if room.Type != before.Type {
+			pinned, err := roomReservations(tx, `room_id = $1 AND room_type = $2 AND status IN ('CREATED', 'CONFIRMED', 'CHECKED_IN')`, room.ID, before.Type)
+			if err != nil {
+				return err
+			}
+			if len(pinned) > 0 {
+				return &model.RoomInUseError{RoomID: room.ID, Reservations: pinned}
+			}
+		}
+		query := `UPDATE rooms SET number = $1, type = $2, capacity = $3, price_per_night = $4, status = $5,
+			cancellation_policy_id = NULLIF($6, '') WHERE id = $7;`
+		_, err = tx.Exec(query, room.Number, room.Type, room.Capacity, room.PricePerNight, room.Status, room.CancellationPolicyID, room.ID)
+		if err != nil {
+			return mapRoomError(err)
+		}
+		return auditRoom(tx, room.ID, model.AuditActionUpdate, actor, before, &room)
+	})
+}
+
+// ArchiveRoom marca o quarto como removido. Com o quarto travado, nenhuma reserva
+// nova entra entre a checagem e o arquivamento
+func (r *postgresRoomRepository) ArchiveRoom(id string, at time.Time, actor model.Actor) error {
+	return withTx(r.db, func(tx *sql.Tx) error {
+		before, err := lockRoom(tx, id)
+		if err != nil || before == nil || before.DeletedAt != nil {
+			return err
+		}
+		blocking, err := roomReservations(tx, `room_id = $1 AND status IN ('CREATED', 'CONFIRMED', 'CHECKED_IN') AND checkout_expected > $2::date`, id, at)
+		if err != nil {
+			return err
+		}
+		if len(blocking) > 0 {
+			return &model.RoomInUseError{RoomID: id, Reservations: blocking}
+		}
+		if _, err := tx.Exec("UPDATE rooms SET deleted_at = $1 WHERE id = $2;", at, id); err != nil {
+			return err
+		}
+		after := *before
+		after.DeletedAt = &at
+		return auditRoom(tx, id, model.AuditActionArchive, actor, before, &after)
+	})
+}
+
+// PurgeRoom apaga o quarto de vez; só é possível sem nenhuma reserva apontando para ele
+func (r *postgresRoomRepository) PurgeRoom(id string, actor model.Actor) error {
+	return withTx(r.db, func(tx *sql.Tx) error {
+		before, err := lockRoom(tx, id)
+		if err != nil || before == nil {
+			return err
+		}
+		referencing, err := roomReservations(tx, "room_id = $1", id)
+		if err != nil {
+			return err
+		}
+		if len(referencing) > 0 {
+			return &model.RoomInUseError{RoomID: id, Reservations: referencing}
+		}
+		if _, err := tx.Exec("DELETE FROM rooms WHERE id = $1;", id); err != nil {
+			return err
+		}
+		return auditRoom(tx, id, model.AuditActionPurge, actor, before, nil)
+	})
 }
 
-func UpdateRoom(room model.Room) error {
-	query := "UPDATE rooms SET number = $1, type = $2, capacity = $3, price_per_night = $4, status = $5 WHERE id = $6;"
-	_, err := db.GetDB().Exec(query, room.Number, room.Type, room.Capacity, room.PricePerNight, room.Status, room.ID)
+// roomReservations lista, na transação, as reservas que atendem ao filtro
+func roomReservations(tx *sql.Tx, where string, args ...any) ([]model.ReservationRef, error) {
+	rows, err := tx.Query(`SELECT id, checkin_expected, checkout_expected, status FROM reservations
+		WHERE `+where+` ORDER BY checkin_expected;`, args...)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	defer rows.Close()
+
+	var refs []model.ReservationRef
+	for rows.Next() {
+		var ref model.ReservationRef
+		var checkin, checkout time.Time
+		if err := rows.Scan(&ref.ID, &checkin, &checkout, &ref.Status); err != nil {
+			return nil, err
+		}
+		ref.CheckinExpected, ref.CheckoutExpected = checkin.Format("2006-01-02"), checkout.Format("2006-01-02")
+		refs = append(refs, ref)
+	}
+	return refs, rows.Err()
+}
+
+// lockRoom lê o quarto travando a linha até o fim da transação; nil quando não existe
+func lockRoom(tx *sql.Tx, id string) (*model.Room, error) {
+	room, err := scanRoom(tx.QueryRow("SELECT "+roomColumns+" FROM rooms WHERE id = $1 FOR UPDATE;", id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
 	}
-	return nil
+	return &room, nil
 }
 
-func DeleteRoom(id string) error {
-	query := "DELETE FROM rooms WHERE id = $1;"
-	_, err := db.GetDB().Exec(query, id)
+func auditRoom(tx *sql.Tx, id, action string, actor model.Actor, before, after *model.Room) error {
+	entry, err := newAuditEntry(model.AuditEntityRoom, id, action, actor, before, after)
 	if err != nil {
 		return err
 	}
-	return nil
+	return insertAudit(tx, entry)
 }
 
-func GetAllRooms() ([]model.Room, error) {
+func (r *postgresRoomRepository) GetAllRooms() ([]model.Room, error) {
 	var rooms []model.Room
-	query := "SELECT id, number, type, capacity, price_per_night, status FROM rooms;"
-	rows, err := db.GetDB().Query(query)
+	query := "SELECT " + roomColumns + " FROM rooms WHERE deleted_at IS NULL;"
+	rows, err := r.db.Query(query)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
 	for rows.Next() {
-		var room model.Room
-		if err := rows.Scan(&room.ID, &room.Number, &room.Type, &room.Capacity, &room.PricePerNight, &room.Status); err != nil {
+		room, err := scanRoom(rows)
+		if err != nil {
 			return nil, err
 		}
 		rooms = append(rooms, room)
@@ -57,11 +183,62 @@ func GetAllRooms() ([]model.Room, error) {
 	return rooms, nil
 }
 
-func GetRoomByID(id string) (model.Room, error) {
-	query := "SELECT id, number, type, capacity, price_per_night, status FROM rooms WHERE id = $1;"
-	row := db.GetDB().QueryRow(query, id)
-	var room model.Room
-	if err := row.Scan(&room.ID, &room.Number, &room.Type, &room.Capacity, &room.PricePerNight, &room.Status); err != nil {
+func (r *postgresRoomRepository) ListRooms(filter model.RoomFilter) ([]model.Room, int, error) {
+	var where whereBuilder
+	where.add("deleted_at IS NULL")
+	if filter.Status != "" {
+		where.add("status = %s", filter.Status)
+	}
+	if filter.Type != "" {
+		where.add("type = %s", filter.Type)
+	}
+	if filter.MinCapacity > 0 {
+		where.add("capacity >= %s", filter.MinCapacity)
+	}
+	if filter.MaxCapacity > 0 {
+		where.add("capacity <= %s", filter.MaxCapacity)
+	}
+	if filter.MinPrice.IsPositive() {
+		where.add("price_per_night >= %s", filter.MinPrice)
+	}
+	if filter.MaxPrice.IsPositive() {
+		where.add("price_per_night <= %s", filter.MaxPrice)
+	}
+
+	var total int
+	if err := r.db.QueryRow("SELECT COUNT(*) FROM rooms"+where.sql()+";", where.args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	col := roomSortColumns[filter.Page.Sort.Field]
+	where.addKeyset(col, filter.Page.Sort, filter.Page.Cursor)
+	query := "SELECT " + roomColumns + " FROM rooms" +
+		where.sql() + orderBy(col, filter.Page.Sort) + " LIMIT " + strconv.Itoa(filter.Page.Limit+1) + ";"
+
+	rows, err := r.db.Query(query, where.args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var rooms []model.Room
+	for rows.Next() {
+		room, err := scanRoom(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		rooms = append(rooms, room)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	return rooms, total, nil
+}
+
+func (r *postgresRoomRepository) GetRoomByID(id string) (model.Room, error) {
+	query := "SELECT " + roomColumns + " FROM rooms WHERE id = $1;"
+	room, err := scanRoom(r.db.QueryRow(query, id))
+	if err != nil {
 		if err == sql.ErrNoRows {
 			return model.Room{}, nil
 		}
@@ -69,3 +246,121 @@ func GetRoomByID(id string) (model.Room, error) {
 	}
 	return room, nil
 }
+
+func (r *postgresRoomRepository) GetAvailableRooms(checkin, checkout time.Time, guests int, roomType string) ([]model.Room, error) {
+	query := `
+		WITH sold_out AS (
+			SELECT t.type FROM (` + roomTypeTotalsSQL + `) t
+			WHERE ($4 = '' OR t.type = $4)
+			  AND EXISTS (
+				SELECT 1 FROM generate_series($1::date, $2::date - 1, interval '1 day') d
+				WHERE ` + typeFreeSQL("t.type", "t.total", "''", "''") + ` <= 0
+			  )
+		)
+		SELECT ` + roomColumns + `
+		FROM rooms r
+		WHERE r.status = 'ATIVO'
+		  AND r.deleted_at IS NULL
+		  AND r.capacity >= $3
+		  AND ($4 = '' OR r.type = $4)
+		  AND r.type NOT IN (SELECT type FROM sold_out)
+		  AND NOT EXISTS (
+			SELECT 1
+			FROM reservations res
+			WHERE res.room_id = r.id
+			  AND res.status NOT IN ('CANCELED', 'NO_SHOW')
+			  AND (res.checkin_expected, res.checkout_expected) OVERLAPS ($1::date, $2::date)
+		  )
+		  AND NOT EXISTS (
+			SELECT 1
+			FROM room_holds h
+			WHERE h.room_id = r.id
+			  AND h.expires_at > now()
+			  AND (h.checkin_expected, h.checkout_expected) OVERLAPS ($1::date, $2::date)
+		  )
+		ORDER BY r.type, r.number;`
+	rows, err := r.db.Query(query, checkin, checkout, guests, roomType)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rooms []model.Room
+	for rows.Next() {
+		room, err := scanRoom(rows)
+		if err != nil {
+			return nil, err
+		}
+		rooms = append(rooms, room)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return rooms, nil
+}
+
+func (r *postgresRoomRepository) GetRoomTypeAvailability(checkin, checkout time.Time) ([]model.RoomTypeAvailability, error) {
+	rows, err := r.db.Query(roomTypeTotalsSQL + " ORDER BY type;")
+	if err != nil {
+		return nil, err
+	}
+	var types []model.RoomTypeAvailability
+	for rows.Next() {
+		var t model.RoomTypeAvailability
+		if err := rows.Scan(&t.RoomType, &t.TotalRooms); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		types = append(types, t)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range types {
+		free, _, err := typeHeadroom(r.db, types[i].RoomType, types[i].TotalRooms, checkin, checkout, "", "")
+		if err != nil {
+			return nil, err
+		}
+		types[i].Available = max(free, 0)
+	}
+	return types, nil
+}
+
+// roomTypeTotalsSQL lista os tipos com quarto não arquivado e quantos quartos de cada um
+// entram no estoque (total): só os ATIVO, os mesmos que o check-in e rankRooms atribuem.
+// Disponibilidade, estoque, overbooking e blocos de grupo contam por aqui
+const roomTypeTotalsSQL = `SELECT type, (COUNT(*) FILTER (WHERE status = 'ATIVO'))::int AS total
+	FROM rooms WHERE deleted_at IS NULL GROUP BY type`
+
+// roomTypeTotal retorna o total de roomTypeTotalsSQL de um tipo
+func roomTypeTotal(q queryer, roomType string) (int, error) {
+	var total int
+	err := q.QueryRow("SELECT COALESCE((SELECT total FROM ("+roomTypeTotalsSQL+") t WHERE type = $1), 0);", roomType).Scan(&total)
+	return total, err
+}
+
+// roomColumns é a lista de colunas lida por scanRoom
+const roomColumns = "id, number, type, capacity, price_per_night, status, deleted_at, COALESCE(cancellation_policy_id, '')"
+
+func scanRoom(row rowScanner) (model.Room, error) {
+	var room model.Room
+	var deleted sql.NullTime
+	if err := row.Scan(&room.ID, &room.Number, &room.Type, &room.Capacity, &room.PricePerNight, &room.Status, &deleted, &room.CancellationPolicyID); err != nil {
+		return model.Room{}, err
+	}
+	if deleted.Valid {
+		room.DeletedAt = &deleted.Time
+	}
+	return room, nil
+}
+
+// mapRoomError traduz a violação de unicidade de number entre os quartos não arquivados
+func mapRoomError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return ErrDuplicateRoomNumber
+	}
+	return err
+}
@@ -0,0 +1,167 @@
+package dao
+
+import (
+	"errors"
+	"testing"
+
+	"hotel-soa/model"
+)
+
+func TestGetAvailableRooms(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, repos Repositories) {
+		ids := map[int]string{}
+		for _, room := range []model.Room{
+			{Number: 101, Type: "STANDARD", Capacity: 2, PricePerNight: model.NewMoney(10000), Status: "ATIVO"},
+			{Number: 102, Type: "STANDARD", Capacity: 1, PricePerNight: model.NewMoney(9000), Status: "ATIVO"},
+			{Number: 103, Type: "STANDARD", Capacity: 2, PricePerNight: model.NewMoney(10000), Status: "INATIVO"},
+			{Number: 104, Type: "STANDARD", Capacity: 2, PricePerNight: model.NewMoney(10000), Status: "ATIVO"},
+			{Number: 201, Type: "DELUXE", Capacity: 3, PricePerNight: model.NewMoney(25000), Status: "ATIVO"},
+		} {
+			id, err := repos.Rooms.InsertRoom(room, testActor)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ids[room.Number] = id
+		}
+		insertTestReservation(t, repos, ids[104], "2026-06-10", "2026-06-13")
+		canceled := insertTestReservation(t, repos, ids[101], "2026-06-10", "2026-06-13")
+		canceled.Status = "CANCELED"
+		if err := repos.Reservations.UpdateReservation(canceled, testActor); err != nil {
+			t.Fatal(err)
+		}
+
+		tests := []struct {
+			name              string
+			checkin, checkout string
+			guests            int
+			roomType          string
+			expected          []int
+		}{
+			// ordenados por tipo e número; inativo e reservado ficam de fora
+			{"booked dates", "2026-06-11", "2026-06-12", 1, "", []int{201, 101, 102}},
+			{"two guests", "2026-06-11", "2026-06-12", 2, "", []int{201, 101}},
+			{"by type", "2026-06-11", "2026-06-12", 1, "STANDARD", []int{101, 102}},
+			{"arrival on the departure day", "2026-06-13", "2026-06-15", 1, "STANDARD", []int{101, 102, 104}},
+			{"too many guests", "2026-06-11", "2026-06-12", 4, "", nil},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				available, err := repos.Rooms.GetAvailableRooms(date(tt.checkin), date(tt.checkout), tt.guests, tt.roomType)
+				if err != nil {
+					t.Fatal(err)
+				}
+				var numbers []int
+				for _, room := range available {
+					numbers = append(numbers, room.Number)
+				}
+				if len(numbers) != len(tt.expected) {
+					t.Fatalf("expected rooms %v, got %v", tt.expected, numbers)
+				}
+				for i := range numbers {
+					if numbers[i] != tt.expected[i] {
+						t.Fatalf("expected rooms %v, got %v", tt.expected, numbers)
+					}
+				}
+			})
+		}
+	})
+}
+
+func TestArchiveRoom(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, repos Repositories) {
+		room := insertTestRoom(t, repos, "STANDARD")
+		past := insertTestReservation(t, repos, room.ID, "2026-05-01", "2026-05-03")
+		future := insertTestReservation(t, repos, room.ID, "2026-06-10", "2026-06-12")
+		at := date("2026-06-01")
+
+		// a reserva já encerrada não bloqueia; a futura sim
+		var inUse *model.RoomInUseError
+		err := repos.Rooms.ArchiveRoom(room.ID, at, testActor)
+		if !errors.As(err, &inUse) || len(inUse.Reservations) != 1 || inUse.Reservations[0].ID != future.ID {
+			t.Fatalf("expected a RoomInUseError citing only %s (not %s), got %v", future.ID, past.ID, err)
+		}
+
+		if err := repos.Reservations.CancelReservation(future.ID, at, "", model.CancellationQuote{}, testActor); err != nil {
+			t.Fatal(err)
+		}
+		if err := repos.Rooms.ArchiveRoom(room.ID, at, testActor); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := repos.Rooms.GetRoomByID(room.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.DeletedAt == nil || !got.DeletedAt.Equal(at) {
+			t.Fatalf("expected deleted_at %v, got %v", at, got.DeletedAt)
+		}
+		all, err := repos.Rooms.GetAllRooms()
+		if err != nil {
+			t.Fatal(err)
+		}
+		listed, total := listAllRooms(t, repos, model.RoomFilter{})
+		available, err := repos.Rooms.GetAvailableRooms(date("2026-07-01"), date("2026-07-02"), 1, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(all) != 0 || len(listed) != 0 || total != 0 || len(available) != 0 {
+			t.Fatalf("expected the archived room to be hidden, got %v, %v (%d), %v", all, listed, total, available)
+		}
+
+		// o número volta a ficar livre
+		if _, err := repos.Rooms.InsertRoom(model.Room{Number: room.Number, Type: "STANDARD", Capacity: 2, PricePerNight: model.NewMoney(10000), Status: "ATIVO"}, testActor); err != nil {
+			t.Fatalf("expected the number of an archived room to be reusable, got %v", err)
+		}
+	})
+}
+
+func TestDuplicateRoomNumber(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, repos Repositories) {
+		room := insertTestRoom(t, repos, "STANDARD")
+		other := insertTestRoom(t, repos, "STANDARD")
+
+		duplicate := room
+		duplicate.ID = ""
+		if _, err := repos.Rooms.InsertRoom(duplicate, testActor); !errors.Is(err, ErrDuplicateRoomNumber) {
+			t.Fatalf("expected ErrDuplicateRoomNumber on insert, got %v", err)
+		}
+		other.Number = room.Number
+		if err := repos.Rooms.UpdateRoom(other, testActor); !errors.Is(err, ErrDuplicateRoomNumber) {
+			t.Fatalf("expected ErrDuplicateRoomNumber on update, got %v", err)
+		}
+
+		// o número de um quarto arquivado pode ser usado de novo
+		if err := repos.Rooms.ArchiveRoom(room.ID, date("2026-06-01"), testActor); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := repos.Rooms.InsertRoom(duplicate, testActor); err != nil {
+			t.Fatalf("expected the archived number to be free, got %v", err)
+		}
+	})
+}
+
+func TestUpdateRoomTypeWithReservations(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, repos Repositories) {
+		room := insertTestRoom(t, repos, "STANDARD")
+		active := insertTestReservation(t, repos, room.ID, "2026-06-10", "2026-06-12")
+
+		room.Type = "DELUXE"
+		var inUse *model.RoomInUseError
+		if err := repos.Rooms.UpdateRoom(room, testActor); !errors.As(err, &inUse) || len(inUse.Reservations) != 1 || inUse.Reservations[0].ID != active.ID {
+			t.Fatalf("expected a RoomInUseError citing %s, got %v", active.ID, err)
+		}
+		// as outras alterações continuam livres
+		room.Type, room.Capacity = "STANDARD", 3
+		if err := repos.Rooms.UpdateRoom(room, testActor); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := repos.Reservations.CancelReservation(active.ID, date("2026-06-01"), "", model.CancellationQuote{}, testActor); err != nil {
+			t.Fatal(err)
+		}
+		room.Type = "DELUXE"
+		if err := repos.Rooms.UpdateRoom(room, testActor); err != nil {
+			t.Fatalf("expected the type to change without active reservations, got %v", err)
+		}
+	})
+}
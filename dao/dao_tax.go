@@ -0,0 +1,191 @@
+package dao
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"hotel-soa/model"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// ErrDuplicateTaxCode indica que já existe regra de imposto com o mesmo code
+var ErrDuplicateTaxCode = errors.New("tax code already registered")
+
+type postgresTaxRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresTaxRepository cria um TaxRepository apoiado no Postgres
+func NewPostgresTaxRepository(conn *sql.DB) TaxRepository {
+	return &postgresTaxRepository{db: conn}
+}
+
+const taxRuleColumns = `id, code, name, calculation, percent, amount, basis, inclusive, exempt_guest_types, active`
+
+func (r *postgresTaxRepository) InsertTaxRule(rule model.TaxRule) (string, error) {
+	id := uuid.NewString()
+	exempt, err := json.Marshal(rule.ExemptGuestTypes)
+	if err != nil {
+		return "", err
+	}
+	query := `INSERT INTO tax_rules (` + taxRuleColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10);`
+	_, err = r.db.Exec(query, id, rule.Code, rule.Name, rule.Calculation, rule.Percent, rule.Amount,
+		rule.Basis, rule.Inclusive, string(exempt), rule.Active)
+	if err != nil {
+		return "", mapTaxRuleError(err)
+	}
+	return id, nil
+}
+
+func (r *postgresTaxRepository) UpdateTaxRule(rule model.TaxRule) error {
+	exempt, err := json.Marshal(rule.ExemptGuestTypes)
+	if err != nil {
+		return err
+	}
+	query := `UPDATE tax_rules
+		SET code = $1, name = $2, calculation = $3, percent = $4, amount = $5, basis = $6,
+		    inclusive = $7, exempt_guest_types = $8, active = $9
+		WHERE id = $10;`
+	_, err = r.db.Exec(query, rule.Code, rule.Name, rule.Calculation, rule.Percent, rule.Amount,
+		rule.Basis, rule.Inclusive, string(exempt), rule.Active, rule.ID)
+	return mapTaxRuleError(err)
+}
+
+// DeleteTaxRule não mexe nos impostos já calculados, que guardam uma cópia da regra
+func (r *postgresTaxRepository) DeleteTaxRule(id string) error {
+	_, err := r.db.Exec("DELETE FROM tax_rules WHERE id = $1;", id)
+	return err
+}
+
+func (r *postgresTaxRepository) GetAllTaxRules() ([]model.TaxRule, error) {
+	rows, err := r.db.Query(`SELECT ` + taxRuleColumns + ` FROM tax_rules ORDER BY code;`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rules []model.TaxRule
+	for rows.Next() {
+		rule, err := scanTaxRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, rows.Err()
+}
+
+func (r *postgresTaxRepository) GetTaxRuleByID(id string) (model.TaxRule, error) {
+	rule, err := scanTaxRule(r.db.QueryRow(`SELECT `+taxRuleColumns+` FROM tax_rules WHERE id = $1;`, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return model.TaxRule{}, nil
+		}
+		return model.TaxRule{}, err
+	}
+	return rule, nil
+}
+
+// GetPostedTaxes considera só as noites cuja diária o night audit já lançou
+func (r *postgresTaxRepository) GetPostedTaxes(from, to time.Time) ([]model.TaxLine, error) {
+	rows, err := r.db.Query(`SELECT t.tax_rule_id, t.code, t.name, t.night, t.amount, t.inclusive
+		FROM reservation_taxes t
+		JOIN room_charges rc ON rc.reservation_id = t.reservation_id AND rc.business_date = t.night
+		WHERE t.night BETWEEN $1::date AND $2::date
+		ORDER BY t.night, t.code;`, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanTaxLines(rows)
+}
+
+// ---------------- HELPERS ----------------
+
+func scanTaxRule(row rowScanner) (model.TaxRule, error) {
+	var rule model.TaxRule
+	var exempt []byte
+	if err := row.Scan(&rule.ID, &rule.Code, &rule.Name, &rule.Calculation, &rule.Percent, &rule.Amount,
+		&rule.Basis, &rule.Inclusive, &exempt, &rule.Active); err != nil {
+		return model.TaxRule{}, err
+	}
+	if err := json.Unmarshal(exempt, &rule.ExemptGuestTypes); err != nil {
+		return model.TaxRule{}, err
+	}
+	return rule, nil
+}
+
+// mapTaxRuleError traduz a violação de unicidade de code
+func mapTaxRuleError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return ErrDuplicateTaxCode
+	}
+	return err
+}
+
+func scanTaxLines(rows *sql.Rows) ([]model.TaxLine, error) {
+	var lines []model.TaxLine
+	for rows.Next() {
+		var line model.TaxLine
+		var night time.Time
+		if err := rows.Scan(&line.TaxRuleID, &line.Code, &line.Name, &night, &line.Amount, &line.Inclusive); err != nil {
+			return nil, err
+		}
+		line.Date = night.Format("2006-01-02")
+		lines = append(lines, line)
+	}
+	return lines, rows.Err()
+}
+
+func getTaxes(q rowsQueryer, reservationID string) ([]model.TaxLine, error) {
+	rows, err := q.Query(`SELECT tax_rule_id, code, name, night, amount, inclusive FROM reservation_taxes
+		WHERE reservation_id = $1 ORDER BY night, code;`, reservationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanTaxLines(rows)
+}
+
+// replaceTaxes regrava os impostos da reserva; nil mantém o que já está salvo
+func replaceTaxes(tx *sql.Tx, reservationID string, taxes []model.TaxLine) error {
+	if taxes == nil {
+		return nil
+	}
+	if _, err := tx.Exec("DELETE FROM reservation_taxes WHERE reservation_id = $1;", reservationID); err != nil {
+		return err
+	}
+	for _, t := range taxes {
+		if _, err := tx.Exec(`INSERT INTO reservation_taxes (reservation_id, tax_rule_id, code, name, night, amount, inclusive)
+			VALUES ($1, $2, $3, $4, $5, $6, $7);`,
+			reservationID, t.TaxRuleID, t.Code, t.Name, t.Date, t.Amount, t.Inclusive); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getTaxSummary soma os impostos da reserva por código
+func getTaxSummary(q rowsQueryer, reservationID string) ([]model.TaxSummary, error) {
+	rows, err := q.Query(`SELECT code, MIN(name), inclusive, SUM(amount) FROM reservation_taxes
+		WHERE reservation_id = $1 GROUP BY code, inclusive ORDER BY code, inclusive;`, reservationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var summary []model.TaxSummary
+	for rows.Next() {
+		var tax model.TaxSummary
+		if err := rows.Scan(&tax.Code, &tax.Name, &tax.Inclusive, &tax.Amount); err != nil {
+			return nil, err
+		}
+		summary = append(summary, tax)
+	}
+	return summary, rows.Err()
+}
@@ -0,0 +1,111 @@
+package dao
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"hotel-soa/model"
+)
+
+func TestTaxRuleRepository(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, repos Repositories) {
+		iss := model.TaxRule{Code: "ISS", Name: "Imposto Sobre Serviços", Calculation: model.TaxPercent, Percent: 5, Amount: model.NewMoney(0),
+			Basis: model.TaxPerNight, Inclusive: true, ExemptGuestTypes: []string{model.GuestDiplomat}, Active: true}
+		id, err := repos.Taxes.InsertTaxRule(iss)
+		if err != nil {
+			t.Fatal(err)
+		}
+		iss.ID = id
+		if got, err := repos.Taxes.GetTaxRuleByID(id); err != nil || !reflect.DeepEqual(got, iss) {
+			t.Fatalf("expected %+v, got %+v, %v", iss, got, err)
+		}
+
+		tourism := model.TaxRule{Code: "TOUR", Name: "Taxa de turismo", Calculation: model.TaxFixed, Amount: model.NewMoney(500),
+			Basis: model.TaxPerStay, ExemptGuestTypes: []string{}, Active: true}
+		if tourism.ID, err = repos.Taxes.InsertTaxRule(tourism); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := repos.Taxes.InsertTaxRule(model.TaxRule{Code: "ISS", Name: "Outro", Calculation: model.TaxPercent, Percent: 2,
+			Basis: model.TaxPerNight, ExemptGuestTypes: []string{}}); !errors.Is(err, ErrDuplicateTaxCode) {
+			t.Fatalf("expected ErrDuplicateTaxCode, got %v", err)
+		}
+		tourism.Code = "ISS"
+		if err := repos.Taxes.UpdateTaxRule(tourism); !errors.Is(err, ErrDuplicateTaxCode) {
+			t.Fatalf("expected ErrDuplicateTaxCode on update, got %v", err)
+		}
+		tourism.Code, tourism.Active = "TOUR", false
+		if err := repos.Taxes.UpdateTaxRule(tourism); err != nil {
+			t.Fatal(err)
+		}
+
+		rules, err := repos.Taxes.GetAllTaxRules()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(rules) != 2 || rules[0].Code != "ISS" || rules[1].Code != "TOUR" || rules[1].Active {
+			t.Fatalf("expected ISS and an inactive TOUR ordered by code, got %+v", rules)
+		}
+
+		if err := repos.Taxes.DeleteTaxRule(tourism.ID); err != nil {
+			t.Fatal(err)
+		}
+		if got, err := repos.Taxes.GetTaxRuleByID(tourism.ID); err != nil || got.ID != "" {
+			t.Fatalf("expected an empty rule after delete, got %+v, %v", got, err)
+		}
+	})
+}
+
+func TestReservationTaxes(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, repos Repositories) {
+		room := insertTestRoom(t, repos, "STANDARD")
+		res := newTestReservation(t, repos, room.ID, "2026-06-10", "2026-06-12")
+		res.Nights = []model.NightlyRate{{Date: "2026-06-10", Price: model.NewMoney(10500)}, {Date: "2026-06-11", Price: model.NewMoney(10500)}}
+		res.Taxes = []model.TaxLine{
+			{TaxRuleID: "00000000-0000-0000-0000-000000000001", Code: "ISS", Name: "ISS", Date: "2026-06-10", Amount: model.NewMoney(500), Inclusive: true},
+			{TaxRuleID: "00000000-0000-0000-0000-000000000002", Code: "TOUR", Name: "Turismo", Date: "2026-06-10", Amount: model.NewMoney(500)},
+			{TaxRuleID: "00000000-0000-0000-0000-000000000001", Code: "ISS", Name: "ISS", Date: "2026-06-11", Amount: model.NewMoney(500), Inclusive: true},
+		}
+		id, err := repos.Reservations.InsertReservation(res, testActor)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := repos.Reservations.GetReservationByID(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got.Taxes) != 3 {
+			t.Fatalf("expected 3 tax lines, got %+v", got.Taxes)
+		}
+		for i, tax := range got.Taxes {
+			tax.Date = day(tax.Date)
+			if tax != res.Taxes[i] {
+				t.Fatalf("expected %+v, got %+v", res.Taxes[i], tax)
+			}
+		}
+
+		// o relatório só considera as noites cuja diária o night audit lançou
+		closedAt := time.Date(2026, 6, 11, 3, 0, 0, 0, time.UTC)
+		charges := []model.RoomCharge{{ReservationID: id, BusinessDate: "2026-06-10", Amount: model.NewMoney(10500), PostedAt: closedAt}}
+		if err := repos.NightAudits.CloseBusinessDate(model.NightAudit{BusinessDate: "2026-06-10", ClosedAt: closedAt, ChargesPosted: 1}, charges); err != nil {
+			t.Fatal(err)
+		}
+		posted, err := repos.Taxes.GetPostedTaxes(time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 6, 30, 0, 0, 0, 0, time.UTC))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(posted) != 2 || posted[0].Code != "ISS" || posted[1].Code != "TOUR" || day(posted[0].Date) != "2026-06-10" {
+			t.Fatalf("expected the taxes of 2026-06-10 only, got %+v", posted)
+		}
+
+		// sem Taxes a atualização mantém os impostos já salvos
+		got.Taxes = nil
+		if err := repos.Reservations.UpdateReservation(got, testActor); err != nil {
+			t.Fatal(err)
+		}
+		if kept, _ := repos.Reservations.GetReservationByID(id); len(kept.Taxes) != 3 {
+			t.Fatalf("expected the taxes to be kept, got %+v", kept.Taxes)
+		}
+	})
+}
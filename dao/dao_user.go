@@ -0,0 +1,171 @@
+package dao
+
+import (
+	"database/sql"
+	"errors"
+	"hotel-soa/model"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// ErrDuplicateUsername indica que já existe usuário com o mesmo username
+var ErrDuplicateUsername = errors.New("username already registered")
+
+const userColumns = `id, username, password_hash, token_version, created_at`
+
+type postgresUserRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresUserRepository cria um UserRepository apoiado no Postgres
+func NewPostgresUserRepository(conn *sql.DB) UserRepository {
+	return &postgresUserRepository{db: conn}
+}
+
+func (r *postgresUserRepository) InsertUser(user model.User) (string, error) {
+	id := uuid.NewString()
+	_, err := r.db.Exec("INSERT INTO users (id, username, password_hash, created_at) VALUES ($1, $2, $3, $4);",
+		id, user.Username, user.PasswordHash, user.CreatedAt)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return "", ErrDuplicateUsername
+		}
+		return "", err
+	}
+	return id, nil
+}
+
+func (r *postgresUserRepository) GetUserByID(id string) (model.User, error) {
+	return r.getUser("id", id)
+}
+
+func (r *postgresUserRepository) GetUserByUsername(username string) (model.User, error) {
+	return r.getUser("username", username)
+}
+
+func (r *postgresUserRepository) GetAllUsers() ([]model.User, error) {
+	rows, err := r.db.Query("SELECT " + userColumns + " FROM users ORDER BY username;")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []model.User
+	for rows.Next() {
+		var user model.User
+		if err := rows.Scan(&user.ID, &user.Username, &user.PasswordHash, &user.TokenVersion, &user.CreatedAt); err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	return users, rows.Err()
+}
+
+func (r *postgresUserRepository) getUser(column, value string) (model.User, error) {
+	var user model.User
+	err := r.db.QueryRow("SELECT "+userColumns+" FROM users WHERE "+column+" = $1;", value).
+		Scan(&user.ID, &user.Username, &user.PasswordHash, &user.TokenVersion, &user.CreatedAt)
+	if err == sql.ErrNoRows {
+		return model.User{}, nil
+	}
+	return user, err
+}
+
+func (r *postgresUserRepository) UpdatePassword(id, hash string) (bool, error) {
+	result, err := r.db.Exec("UPDATE users SET password_hash = $1, token_version = token_version + 1 WHERE id = $2;", hash, id)
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	return n > 0, err
+}
+
+func (r *postgresUserRepository) RevokeTokens(id string) (bool, error) {
+	result, err := r.db.Exec("UPDATE users SET token_version = token_version + 1 WHERE id = $1;", id)
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	return n > 0, err
+}
+
+type postgresAPIKeyRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresAPIKeyRepository cria um APIKeyRepository apoiado no Postgres
+func NewPostgresAPIKeyRepository(conn *sql.DB) APIKeyRepository {
+	return &postgresAPIKeyRepository{db: conn}
+}
+
+const apiKeyColumns = `id, user_id, name, prefix, key_hash, created_at, last_used_at, revoked_at`
+
+func (r *postgresAPIKeyRepository) InsertAPIKey(key model.APIKey) (string, error) {
+	id := uuid.NewString()
+	_, err := r.db.Exec(`INSERT INTO api_keys (id, user_id, name, prefix, key_hash, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6);`, id, key.UserID, key.Name, key.Prefix, key.KeyHash, key.CreatedAt)
+	if err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+func (r *postgresAPIKeyRepository) GetAPIKeyByHash(hash string) (model.APIKey, error) {
+	key, err := scanAPIKey(r.db.QueryRow(`SELECT `+apiKeyColumns+` FROM api_keys WHERE key_hash = $1;`, hash))
+	if err == sql.ErrNoRows {
+		return model.APIKey{}, nil
+	}
+	return key, err
+}
+
+func (r *postgresAPIKeyRepository) GetAPIKeysByUser(userID string) ([]model.APIKey, error) {
+	rows, err := r.db.Query(`SELECT `+apiKeyColumns+` FROM api_keys WHERE user_id = $1 ORDER BY created_at;`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []model.APIKey
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+func (r *postgresAPIKeyRepository) RevokeAPIKey(id, userID string, at time.Time) (bool, error) {
+	result, err := r.db.Exec(`UPDATE api_keys SET revoked_at = $1
+		WHERE id = $2 AND user_id = $3 AND revoked_at IS NULL;`, at, id, userID)
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	return n > 0, err
+}
+
+func (r *postgresAPIKeyRepository) TouchAPIKey(id string, at time.Time) error {
+	_, err := r.db.Exec("UPDATE api_keys SET last_used_at = $1 WHERE id = $2;", at, id)
+	return err
+}
+
+func scanAPIKey(row rowScanner) (model.APIKey, error) {
+	var key model.APIKey
+	var lastUsed, revoked sql.NullTime
+	err := row.Scan(&key.ID, &key.UserID, &key.Name, &key.Prefix, &key.KeyHash, &key.CreatedAt, &lastUsed, &revoked)
+	if err != nil {
+		return model.APIKey{}, err
+	}
+	if lastUsed.Valid {
+		key.LastUsedAt = &lastUsed.Time
+	}
+	if revoked.Valid {
+		key.RevokedAt = &revoked.Time
+	}
+	return key, nil
+}
@@ -0,0 +1,125 @@
+package dao
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"hotel-soa/model"
+)
+
+// insertTestUser grava um usuário com hash de senha fictício
+func insertTestUser(t *testing.T, repos Repositories, username string) model.User {
+	t.Helper()
+	user := model.User{Username: username, PasswordHash: "hash-" + username, CreatedAt: time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)}
+	id, err := repos.Users.InsertUser(user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	user.ID = id
+	return user
+}
+
+func TestUserRepository(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, repos Repositories) {
+		user := insertTestUser(t, repos, "ana")
+		if _, err := repos.Users.InsertUser(model.User{Username: "ana", PasswordHash: "other", CreatedAt: user.CreatedAt}); !errors.Is(err, ErrDuplicateUsername) {
+			t.Fatalf("expected ErrDuplicateUsername, got %v", err)
+		}
+
+		for _, got := range []func() (model.User, error){
+			func() (model.User, error) { return repos.Users.GetUserByID(user.ID) },
+			func() (model.User, error) { return repos.Users.GetUserByUsername("ana") },
+		} {
+			found, err := got()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if found.ID != user.ID || found.Username != "ana" || found.PasswordHash != user.PasswordHash || !found.CreatedAt.Equal(user.CreatedAt) {
+				t.Fatalf("expected %+v, got %+v", user, found)
+			}
+		}
+		if found, err := repos.Users.GetUserByUsername("nobody"); err != nil || found.ID != "" {
+			t.Fatalf("expected an empty user, got %+v, %v", found, err)
+		}
+	})
+}
+
+func TestUserTokenVersion(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, repos Repositories) {
+		user := insertTestUser(t, repos, "ana")
+		if found, err := repos.Users.GetUserByID(user.ID); err != nil || found.TokenVersion != 0 {
+			t.Fatalf("expected token version 0, got %+v, %v", found, err)
+		}
+
+		// cada logout e cada troca de senha incrementam a versão
+		if found, err := repos.Users.RevokeTokens(user.ID); err != nil || !found {
+			t.Fatalf("expected the tokens to be revoked, got %v, %v", found, err)
+		}
+		if found, err := repos.Users.UpdatePassword(user.ID, "new-hash"); err != nil || !found {
+			t.Fatalf("expected the password to be updated, got %v, %v", found, err)
+		}
+		found, err := repos.Users.GetUserByUsername("ana")
+		if err != nil || found.TokenVersion != 2 || found.PasswordHash != "new-hash" {
+			t.Fatalf("expected token version 2 and the new hash, got %+v, %v", found, err)
+		}
+		if users, err := repos.Users.GetAllUsers(); err != nil || len(users) != 1 || users[0].TokenVersion != 2 {
+			t.Fatalf("expected the listed user to carry token version 2, got %+v, %v", users, err)
+		}
+
+		missing := "00000000-0000-0000-0000-000000000000"
+		if found, err := repos.Users.RevokeTokens(missing); err != nil || found {
+			t.Fatalf("expected false for a missing user, got %v, %v", found, err)
+		}
+		if found, err := repos.Users.UpdatePassword(missing, "hash"); err != nil || found {
+			t.Fatalf("expected false for a missing user, got %v, %v", found, err)
+		}
+	})
+}
+
+func TestAPIKeyRepository(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, repos Repositories) {
+		ana := insertTestUser(t, repos, "ana")
+		bruno := insertTestUser(t, repos, "bruno")
+		created := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+		key := model.APIKey{UserID: ana.ID, Name: "pms", Prefix: "hk_12345678", KeyHash: "hash-1", CreatedAt: created}
+		id, err := repos.APIKeys.InsertAPIKey(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		found, err := repos.APIKeys.GetAPIKeyByHash("hash-1")
+		if err != nil || found.ID != id || found.UserID != ana.ID || found.LastUsedAt != nil || found.RevokedAt != nil {
+			t.Fatalf("expected key %s, got %+v, %v", id, found, err)
+		}
+		if found, err := repos.APIKeys.GetAPIKeyByHash("hash-2"); err != nil || found.ID != "" {
+			t.Fatalf("expected an empty key, got %+v, %v", found, err)
+		}
+
+		used := created.Add(time.Hour)
+		if err := repos.APIKeys.TouchAPIKey(id, used); err != nil {
+			t.Fatal(err)
+		}
+		// só o dono revoga, e só uma vez
+		if revoked, err := repos.APIKeys.RevokeAPIKey(id, bruno.ID, used); err != nil || revoked {
+			t.Fatalf("expected another user not to revoke the key, got %v, %v", revoked, err)
+		}
+		if revoked, err := repos.APIKeys.RevokeAPIKey(id, ana.ID, used); err != nil || !revoked {
+			t.Fatalf("expected the owner to revoke the key, got %v, %v", revoked, err)
+		}
+		if revoked, err := repos.APIKeys.RevokeAPIKey(id, ana.ID, used); err != nil || revoked {
+			t.Fatalf("expected a revoked key not to be revoked again, got %v, %v", revoked, err)
+		}
+
+		keys, err := repos.APIKeys.GetAPIKeysByUser(ana.ID)
+		if err != nil || len(keys) != 1 {
+			t.Fatalf("expected one key, got %+v, %v", keys, err)
+		}
+		if keys[0].LastUsedAt == nil || !keys[0].LastUsedAt.Equal(used) || keys[0].RevokedAt == nil {
+			t.Fatalf("expected last use and revocation to be stored, got %+v", keys[0])
+		}
+		if keys, err := repos.APIKeys.GetAPIKeysByUser(bruno.ID); err != nil || len(keys) != 0 {
+			t.Fatalf("expected no keys for bruno, got %+v, %v", keys, err)
+		}
+	})
+}
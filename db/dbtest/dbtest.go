@@ -0,0 +1,46 @@
+// Package dbtest prepara um Postgres isolado para os testes que precisam do banco
+package dbtest
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"hotel-soa/helper"
+
+	_ "github.com/lib/pq"
+)
+
+// Open conecta no Postgres dos DB_* usando um schema novo e vazio, apagado no fim do teste.
+// Sem STORAGE_BACKEND=postgres o teste é pulado
+func Open(t testing.TB) *sql.DB {
+	t.Helper()
+	if os.Getenv("STORAGE_BACKEND") != "postgres" {
+		t.Skip("set STORAGE_BACKEND=postgres to run against the Postgres from the DB_* envs")
+	}
+
+	dsn := helper.GetPostgresConnectionString()
+	admin, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	schema := fmt.Sprintf("test_%d", time.Now().UnixNano())
+	if _, err := admin.Exec("CREATE SCHEMA " + schema); err != nil {
+		admin.Close()
+		t.Fatal(err)
+	}
+
+	// public continua no search_path por causa das extensões já instaladas no banco
+	conn, err := sql.Open("postgres", dsn+" search_path="+schema+",public")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		conn.Close()
+		admin.Exec("DROP SCHEMA " + schema + " CASCADE")
+		admin.Close()
+	})
+	return conn
+}
@@ -0,0 +1,43 @@
+package gateway
+
+import (
+	"errors"
+	"hotel-soa/model"
+)
+
+// ErrTimeout indica que o gateway não respondeu a tempo. O resultado da operação é
+// desconhecido; quem chama trata como falha e não reserva nada no cartão
+var ErrTimeout = errors.New("payment gateway did not respond in time")
+
+// PaymentGateway é a integração com o adquirente. Uma recusa não é erro: volta como
+// Result com Approved false e o motivo em DeclineCode. O erro fica para falhas de
+// comunicação, ErrTimeout quando o gateway não respondeu. Um adaptador real implementa
+// esta interface e é escolhido em hotel-server.go pelo PAYMENT_GATEWAY
+type PaymentGateway interface {
+	// Name identifica o gateway nas autorizações gravadas
+	Name() string
+	// Authorize reserva amount no cartão sem cobrar
+	Authorize(req AuthorizeRequest) (Result, error)
+	// Capture cobra até o valor autorizado; o restante da autorização é liberado
+	Capture(authorization string, amount model.Money) (Result, error)
+	// Void libera a autorização sem cobrar
+	Void(authorization string) (Result, error)
+	// Refund devolve parte ou todo o valor de uma captura
+	Refund(capture string, amount model.Money) (Result, error)
+}
+
+// AuthorizeRequest é a pré-autorização de um depósito. ReservationID vai como
+// referência do lojista, para conciliação no painel do gateway
+type AuthorizeRequest struct {
+	ReservationID string
+	CardToken     string
+	Amount        model.Money
+}
+
+// Result é a resposta do gateway. Reference identifica a operação aprovada, usada nas
+// chamadas seguintes: a autorização em Capture e Void e a captura em Refund
+type Result struct {
+	Approved    bool
+	Reference   string
+	DeclineCode string
+}
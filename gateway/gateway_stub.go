@@ -0,0 +1,69 @@
+package gateway
+
+import (
+	"hotel-soa/model"
+
+	"github.com/google/uuid"
+)
+
+// Tokens de cartão com resposta fixa no StubGateway; qualquer outro token é aprovado
+const (
+	StubTokenDeclined          = "tok_declined"
+	StubTokenInsufficientFunds = "tok_insufficient_funds"
+	StubTokenExpired           = "tok_expired"
+	StubTokenTimeout           = "tok_timeout"
+)
+
+// StubGateway simula um adquirente para desenvolvimento e testes, sem rede. A resposta
+// da autorização depende do token do cartão; captura, cancelamento e estorno são sempre
+// aprovados, já que os valores são conferidos antes pelo serviço
+type StubGateway struct{}
+
+// NewStubGateway cria o gateway simulado
+func NewStubGateway() PaymentGateway {
+	return StubGateway{}
+}
+
+func (StubGateway) Name() string {
+	return "stub"
+}
+
+func (StubGateway) Authorize(req AuthorizeRequest) (Result, error) {
+	switch req.CardToken {
+	case StubTokenDeclined:
+		return Result{DeclineCode: "card_declined"}, nil
+	case StubTokenInsufficientFunds:
+		return Result{DeclineCode: "insufficient_funds"}, nil
+	case StubTokenExpired:
+		return Result{DeclineCode: "expired_card"}, nil
+	case StubTokenTimeout:
+		return Result{}, ErrTimeout
+	}
+	if !req.Amount.IsPositive() {
+		return Result{DeclineCode: "invalid_amount"}, nil
+	}
+	return approved("auth"), nil
+}
+
+func (StubGateway) Capture(authorization string, amount model.Money) (Result, error) {
+	if !amount.IsPositive() {
+		return Result{DeclineCode: "invalid_amount"}, nil
+	}
+	return approved("cap"), nil
+}
+
+func (StubGateway) Void(authorization string) (Result, error) {
+	return approved("void"), nil
+}
+
+func (StubGateway) Refund(capture string, amount model.Money) (Result, error) {
+	if !amount.IsPositive() {
+		return Result{DeclineCode: "invalid_amount"}, nil
+	}
+	return approved("ref"), nil
+}
+
+// approved monta a resposta aprovada com uma referência nova, no formato stub_<op>_<uuid>
+func approved(operation string) Result {
+	return Result{Approved: true, Reference: "stub_" + operation + "_" + uuid.NewString()}
+}
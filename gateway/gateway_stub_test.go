@@ -0,0 +1,52 @@
+package gateway
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"hotel-soa/model"
+)
+
+func TestStubGatewayAuthorize(t *testing.T) {
+	g := NewStubGateway()
+	tests := []struct {
+		token       string
+		approved    bool
+		declineCode string
+		err         error
+	}{
+		{"tok_visa", true, "", nil},
+		{StubTokenDeclined, false, "card_declined", nil},
+		{StubTokenInsufficientFunds, false, "insufficient_funds", nil},
+		{StubTokenExpired, false, "expired_card", nil},
+		{StubTokenTimeout, false, "", ErrTimeout},
+	}
+	for _, tt := range tests {
+		t.Run(tt.token, func(t *testing.T) {
+			result, err := g.Authorize(AuthorizeRequest{ReservationID: "r1", CardToken: tt.token, Amount: model.NewMoney(10000)})
+			if !errors.Is(err, tt.err) || result.Approved != tt.approved || result.DeclineCode != tt.declineCode {
+				t.Fatalf("unexpected result %+v, %v", result, err)
+			}
+			if tt.approved && !strings.HasPrefix(result.Reference, "stub_auth_") {
+				t.Fatalf("expected a stub_auth_ reference, got %q", result.Reference)
+			}
+		})
+	}
+	if result, _ := g.Authorize(AuthorizeRequest{CardToken: "tok_visa", Amount: model.NewMoney(0)}); result.Approved {
+		t.Fatal("expected a zero amount to be declined")
+	}
+}
+
+func TestStubGatewayOperations(t *testing.T) {
+	g := NewStubGateway()
+	if result, err := g.Capture("stub_auth_1", model.NewMoney(5000)); err != nil || !result.Approved || !strings.HasPrefix(result.Reference, "stub_cap_") {
+		t.Fatalf("expected an approved capture, got %+v, %v", result, err)
+	}
+	if result, err := g.Void("stub_auth_1"); err != nil || !result.Approved {
+		t.Fatalf("expected an approved void, got %+v, %v", result, err)
+	}
+	if result, err := g.Refund("stub_cap_1", model.NewMoney(-1)); err != nil || result.Approved || result.DeclineCode != "invalid_amount" {
+		t.Fatalf("expected a negative refund to be declined, got %+v, %v", result, err)
+	}
+}
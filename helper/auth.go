@@ -0,0 +1,34 @@
+package helper
+
+import (
+	"os"
+	"time"
+)
+
+// GetJWTSecret retorna a chave HMAC usada para assinar os tokens (JWT_SECRET)
+func GetJWTSecret() string {
+	return os.Getenv("JWT_SECRET")
+}
+
+// GetAccessTokenTTL retorna a validade do access token (JWT_ACCESS_TTL, padrão 15m)
+func GetAccessTokenTTL() time.Duration {
+	return getDuration("JWT_ACCESS_TTL", 15*time.Minute)
+}
+
+// GetRefreshTokenTTL retorna a validade do refresh token (JWT_REFRESH_TTL, padrão 7 dias)
+func GetRefreshTokenTTL() time.Duration {
+	return getDuration("JWT_REFRESH_TTL", 7*24*time.Hour)
+}
+
+// GetAdminCredentials retorna o usuário criado na subida quando ainda não existe
+// (ADMIN_USERNAME e ADMIN_PASSWORD); vazio desliga a criação
+func GetAdminCredentials() (string, string) {
+	return os.Getenv("ADMIN_USERNAME"), os.Getenv("ADMIN_PASSWORD")
+}
+
+func getDuration(key string, fallback time.Duration) time.Duration {
+	if d, err := time.ParseDuration(os.Getenv(key)); err == nil && d > 0 {
+		return d
+	}
+	return fallback
+}
@@ -16,3 +16,12 @@ func GetPostgresConnectionString() string {
 		DB_USER, DB_PASSWORD, DB_NAME, DB_HOST, DB_PORT,
 	)
 }
+
+// GetStorageBackend retorna o backend de persistência configurado (postgres por padrão)
+func GetStorageBackend() string {
+	backend := os.Getenv("STORAGE_BACKEND")
+	if backend == "" {
+		return "postgres"
+	}
+	return backend
+}
@@ -0,0 +1,9 @@
+package helper
+
+import "time"
+
+// GetGroupReleaseInterval retorna o intervalo entre os releases dos grupos com o cutoff
+// vencido (GROUP_RELEASE_INTERVAL, padrão 1h)
+func GetGroupReleaseInterval() time.Duration {
+	return getDuration("GROUP_RELEASE_INTERVAL", time.Hour)
+}
@@ -0,0 +1,17 @@
+package helper
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGroupReleaseInterval(t *testing.T) {
+	t.Setenv("GROUP_RELEASE_INTERVAL", "")
+	if got := GetGroupReleaseInterval(); got != time.Hour {
+		t.Fatalf("expected the default interval of 1h, got %v", got)
+	}
+	t.Setenv("GROUP_RELEASE_INTERVAL", "15m")
+	if got := GetGroupReleaseInterval(); got != 15*time.Minute {
+		t.Fatalf("expected 15m, got %v", got)
+	}
+}
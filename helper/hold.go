@@ -0,0 +1,15 @@
+package helper
+
+import "time"
+
+// GetHoldTTL retorna a validade de um bloqueio de quarto criado sem ttl_seconds
+// (HOLD_TTL, padrão 10m)
+func GetHoldTTL() time.Duration {
+	return getDuration("HOLD_TTL", 10*time.Minute)
+}
+
+// GetHoldSweepInterval retorna o intervalo entre as limpezas dos bloqueios vencidos
+// (HOLD_SWEEP_INTERVAL, padrão 1m)
+func GetHoldSweepInterval() time.Duration {
+	return getDuration("HOLD_SWEEP_INTERVAL", time.Minute)
+}
@@ -0,0 +1,21 @@
+package helper
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHoldEnvs(t *testing.T) {
+	t.Setenv("HOLD_TTL", "")
+	if got := GetHoldTTL(); got != 10*time.Minute {
+		t.Fatalf("expected the default TTL of 10m, got %v", got)
+	}
+	t.Setenv("HOLD_TTL", "15m")
+	if got := GetHoldTTL(); got != 15*time.Minute {
+		t.Fatalf("expected 15m, got %v", got)
+	}
+	t.Setenv("HOLD_SWEEP_INTERVAL", "")
+	if got := GetHoldSweepInterval(); got != time.Minute {
+		t.Fatalf("expected the default interval of 1m, got %v", got)
+	}
+}
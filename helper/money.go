@@ -0,0 +1,12 @@
+package helper
+
+import "os"
+
+// GetCurrency retorna a moeda ISO 4217 da propriedade (CURRENCY, padrão BRL)
+func GetCurrency() string {
+	currency := os.Getenv("CURRENCY")
+	if currency == "" {
+		return "BRL"
+	}
+	return currency
+}
@@ -0,0 +1,14 @@
+package helper
+
+import "testing"
+
+func TestGetCurrency(t *testing.T) {
+	t.Setenv("CURRENCY", "")
+	if got := GetCurrency(); got != "BRL" {
+		t.Fatalf("expected the default BRL, got %s", got)
+	}
+	t.Setenv("CURRENCY", "USD")
+	if got := GetCurrency(); got != "USD" {
+		t.Fatalf("expected USD, got %s", got)
+	}
+}
@@ -0,0 +1,24 @@
+package helper
+
+import (
+	"os"
+	"strconv"
+)
+
+// GetPaymentGateway retorna o gateway de pagamento configurado (PAYMENT_GATEWAY, padrão stub)
+func GetPaymentGateway() string {
+	gateway := os.Getenv("PAYMENT_GATEWAY")
+	if gateway == "" {
+		return "stub"
+	}
+	return gateway
+}
+
+// GetDepositPercent retorna o percentual do total da reserva pré-autorizado como
+// depósito (DEPOSIT_PERCENT, maior que 0 e até 100, padrão 100)
+func GetDepositPercent() float64 {
+	if p, err := strconv.ParseFloat(os.Getenv("DEPOSIT_PERCENT"), 64); err == nil && p > 0 && p <= 100 {
+		return p
+	}
+	return 100
+}
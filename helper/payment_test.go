@@ -0,0 +1,30 @@
+package helper
+
+import "testing"
+
+func TestPaymentEnvs(t *testing.T) {
+	tests := []struct {
+		value   string
+		percent float64
+	}{
+		{"", 100},
+		{"30", 30},
+		{"12.5", 12.5},
+		{"0", 100},
+		{"101", 100},
+		{"all", 100},
+	}
+	for _, tt := range tests {
+		t.Run("DEPOSIT_PERCENT="+tt.value, func(t *testing.T) {
+			t.Setenv("DEPOSIT_PERCENT", tt.value)
+			if got := GetDepositPercent(); got != tt.percent {
+				t.Fatalf("expected %v, got %v", tt.percent, got)
+			}
+		})
+	}
+
+	t.Setenv("PAYMENT_GATEWAY", "")
+	if got := GetPaymentGateway(); got != "stub" {
+		t.Fatalf("expected the stub gateway by default, got %s", got)
+	}
+}
@@ -0,0 +1,32 @@
+package helper
+
+import (
+	"os"
+	"strconv"
+	"time"
+)
+
+// GetNoShowCutoffHour retorna a hora do dia seguinte à chegada a partir da qual uma
+// reserva ainda CREATED vira NO_SHOW (NO_SHOW_CUTOFF_HOUR, padrão 6)
+func GetNoShowCutoffHour() int {
+	return getHour("NO_SHOW_CUTOFF_HOUR", 6)
+}
+
+// GetCheckoutDeadlineHour retorna a hora do dia de saída a partir da qual um hóspede
+// ainda CHECKED_IN é sinalizado como overstay (CHECKOUT_DEADLINE_HOUR, padrão 12)
+func GetCheckoutDeadlineHour() int {
+	return getHour("CHECKOUT_DEADLINE_HOUR", 12)
+}
+
+// GetStayMonitorInterval retorna o intervalo entre as execuções do agendador de
+// no-show e overstay (STAY_MONITOR_INTERVAL, padrão 15m)
+func GetStayMonitorInterval() time.Duration {
+	return getDuration("STAY_MONITOR_INTERVAL", 15*time.Minute)
+}
+
+func getHour(key string, fallback int) int {
+	if h, err := strconv.Atoi(os.Getenv(key)); err == nil && h >= 0 && h <= 23 {
+		return h
+	}
+	return fallback
+}
@@ -0,0 +1,37 @@
+package helper
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSchedulerEnvs(t *testing.T) {
+	tests := []struct {
+		value  string
+		cutoff int
+	}{
+		{"", 6},
+		{"0", 0},
+		{"23", 23},
+		{"24", 6},
+		{"-1", 6},
+		{"six", 6},
+	}
+	for _, tt := range tests {
+		t.Run("NO_SHOW_CUTOFF_HOUR="+tt.value, func(t *testing.T) {
+			t.Setenv("NO_SHOW_CUTOFF_HOUR", tt.value)
+			if got := GetNoShowCutoffHour(); got != tt.cutoff {
+				t.Fatalf("expected %d, got %d", tt.cutoff, got)
+			}
+		})
+	}
+
+	t.Setenv("CHECKOUT_DEADLINE_HOUR", "")
+	if got := GetCheckoutDeadlineHour(); got != 12 {
+		t.Fatalf("expected the default deadline 12, got %d", got)
+	}
+	t.Setenv("STAY_MONITOR_INTERVAL", "1m")
+	if got := GetStayMonitorInterval(); got != time.Minute {
+		t.Fatalf("expected 1m, got %v", got)
+	}
+}
@@ -3,12 +3,28 @@
 // @description API de exemplo com Gin + Swagger
 // @host localhost:8080
 // @BasePath /
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description Access token no formato "Bearer <token>", obtido em /auth/login
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name X-API-Key
+// @description Chave de API criada em /auth/api-keys
 package main
 
 import (
 	"hotel-soa/controller"
+	"hotel-soa/dao"
+	"hotel-soa/db"
+	"hotel-soa/gateway"
+	"hotel-soa/helper"
+	"hotel-soa/migrations"
+	"hotel-soa/model"
 	"hotel-soa/service"
+	"log"
 	"net/http"
+	"time"
 
 	_ "hotel-soa/docs"
 
@@ -22,11 +38,42 @@ import (
 // @contact.email
 func main() {
 	gin.SetMode(gin.ReleaseMode)
+	if err := model.SetCurrency(helper.GetCurrency()); err != nil {
+		log.Fatalf("CURRENCY: %v", err)
+	}
 
 	r := gin.Default()
+	r.Use(controller.RequestID())
+
+	repos := newRepositories()
+	payments := newPaymentGateway()
+	go runStayMonitor(service.NewStayMonitor(repos.Reservations, repos.Policies, repos.Folios, payments, helper.GetNoShowCutoffHour(), helper.GetCheckoutDeadlineHour()))
+
+	holdService := service.NewHoldService(repos.Holds, repos.Rooms, repos.RatePlans, helper.GetHoldTTL())
+	go runHoldSweeper(holdService)
+
+	reservationService := service.NewReservationService(repos.Reservations, repos.Rooms, repos.RatePlans, repos.Guests, repos.Policies, repos.Taxes, repos.Folios, repos.Holds, payments, helper.GetDepositPercent())
+	groupService := service.NewGroupService(repos.Groups, reservationService)
+	go runGroupReleaser(groupService)
 
-	roomController := controller.NewRoomController(service.NewRoomService())
-	reservationController := controller.NewReservationController(service.NewReservationService())
+	roomController := controller.NewRoomController(service.NewRoomService(repos.Rooms, repos.Policies))
+	reservationController := controller.NewReservationController(reservationService)
+	groupController := controller.NewGroupController(groupService)
+	ratePlanController := controller.NewRatePlanController(service.NewRatePlanService(repos.RatePlans, repos.Policies))
+	policyController := controller.NewCancellationPolicyController(service.NewCancellationPolicyService(repos.Policies))
+	taxController := controller.NewTaxController(service.NewTaxService(repos.Taxes))
+	overbookingController := controller.NewOverbookingController(service.NewOverbookingService(repos.Overbooking, repos.Reservations))
+	availabilityController := controller.NewAvailabilityController(service.NewAvailabilityService(repos.Rooms, repos.RatePlans))
+	guestController := controller.NewGuestController(service.NewGuestService(repos.Guests, repos.Reservations))
+	roleService := service.NewRoleService(repos.Roles, repos.Users)
+	roleController := controller.NewRoleController(roleService)
+	auditController := controller.NewAuditController(service.NewAuditService(repos.Audit))
+	folioController := controller.NewFolioController(service.NewFolioService(repos.Folios, repos.Reservations, payments))
+	holdController := controller.NewHoldController(holdService)
+	nightAuditController := controller.NewNightAuditController(service.NewNightAuditService(repos.NightAudits, repos.Reservations, repos.Rooms))
+	authController := controller.NewAuthController(newAuthService(repos, roleService))
+	requireAuth := authController.RequireAuth()
+	can := controller.RequirePermission
 
 	// Swagger UI
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
@@ -36,25 +83,249 @@ func main() {
 		c.Redirect(http.StatusSeeOther, "/swagger/index.html")
 	})
 
+	// login e refresh são públicos; o restante de /auth exige autenticação
+	auth := r.Group("/auth")
+	{
+		auth.POST("/login", authController.Login)
+		auth.POST("/refresh", authController.Refresh)
+		auth.GET("/me", requireAuth, authController.Me)
+		auth.POST("/logout", requireAuth, authController.Logout)
+		auth.PUT("/password", requireAuth, authController.ChangePassword)
+		auth.POST("/api-keys", requireAuth, authController.CreateAPIKey)
+		auth.GET("/api-keys", requireAuth, authController.ListAPIKeys)
+		auth.DELETE("/api-keys/:id", requireAuth, authController.RevokeAPIKey)
+
+		auth.GET("/users", requireAuth, can(model.PermUsersManage), authController.ListUsers)
+		auth.POST("/users", requireAuth, can(model.PermUsersManage), authController.CreateUser)
+		auth.PUT("/users/:id/roles", requireAuth, can(model.PermUsersManage), roleController.SetUserRoles)
+
+		auth.GET("/permissions", requireAuth, roleController.GetPermissions)
+		auth.GET("/roles", requireAuth, roleController.GetAll)
+		auth.GET("/roles/:name", requireAuth, roleController.GetByName)
+		auth.PUT("/roles/:name", requireAuth, can(model.PermRolesManage), roleController.Save)
+		auth.DELETE("/roles/:name", requireAuth, can(model.PermRolesManage), roleController.Delete)
+	}
+
 	// URI e handlers para rooms
-	rooms := r.Group("/rooms")
+	rooms := r.Group("/rooms", requireAuth)
+	{
+		rooms.POST("/", can(model.PermRoomsCreate), roomController.Create)
+		rooms.PUT("/:id", can(model.PermRoomsUpdate), roomController.Update)
+		rooms.DELETE("/:id", can(model.PermRoomsDelete), roomController.Delete)
+		rooms.DELETE("/:id/purge", can(model.PermRecordsPurge), roomController.Purge)
+		rooms.GET("/:id", can(model.PermRoomsRead), roomController.GetByID)
+		rooms.GET("/", can(model.PermRoomsRead), roomController.GetAll)
+	}
+
+	reservation := r.Group("/reservation", requireAuth)
+	{
+		reservation.POST("/", can(model.PermReservationsCreate), reservationController.Create)
+		reservation.PUT("/:id", can(model.PermReservationsUpdate), reservationController.Update)
+		reservation.DELETE("/:id", can(model.PermReservationsDelete), reservationController.Delete)
+		reservation.DELETE("/:id/purge", can(model.PermRecordsPurge), reservationController.Purge)
+		reservation.GET("/alerts", can(model.PermReservationsRead), reservationController.Alerts)
+		reservation.GET("/:id", can(model.PermReservationsRead), reservationController.GetByID)
+		reservation.GET("/:id/cancellation-quote", can(model.PermReservationsRead), reservationController.CancellationQuote)
+		reservation.GET("/", can(model.PermReservationsRead), reservationController.GetAll)
+		reservation.POST("/:id/check-in", can(model.PermReservationsCheckIn), reservationController.CheckIn)
+		reservation.PUT("/:id/room", can(model.PermReservationsUpdate), reservationController.AssignRoom)
+		reservation.POST("/room-assignments", can(model.PermReservationsUpdate), reservationController.AutoAssign)
+		reservation.POST("/:id/check-out", can(model.PermReservationsCheckOut), reservationController.CheckOut)
+		reservation.GET("/:id/folio", can(model.PermReservationsRead), folioController.Get)
+		reservation.POST("/:id/folio/charges", can(model.PermFolioWrite), folioController.PostCharge)
+		reservation.POST("/:id/folio/payments", can(model.PermFolioWrite), folioController.PostPayment)
+		reservation.POST("/:id/payment-authorizations", can(model.PermFolioWrite), reservationController.Authorize)
+	}
+
+	// bloqueios são o primeiro passo de uma reserva e usam as mesmas permissões
+	holds := r.Group("/holds", requireAuth)
+	{
+		holds.POST("/", can(model.PermReservationsCreate), holdController.Create)
+		holds.GET("/:id", can(model.PermReservationsRead), holdController.GetByID)
+		holds.DELETE("/:id", can(model.PermReservationsCreate), holdController.Delete)
+	}
+
+	// grupos são reservas em bloco e usam as permissões de reservas; a conta master, as de folio
+	groups := r.Group("/groups", requireAuth)
+	{
+		groups.POST("/", can(model.PermReservationsCreate), groupController.Create)
+		groups.GET("/", can(model.PermReservationsRead), groupController.GetAll)
+		groups.GET("/:id", can(model.PermReservationsRead), groupController.GetByID)
+		groups.GET("/:id/reservations", can(model.PermReservationsRead), groupController.GetReservations)
+		groups.POST("/:id/reservations", can(model.PermReservationsCreate), groupController.PickUp)
+		groups.POST("/:id/rooming-list", can(model.PermReservationsCreate), groupController.RoomingList)
+		groups.POST("/:id/release", can(model.PermReservationsUpdate), groupController.Release)
+		groups.POST("/:id/cancel", can(model.PermReservationsDelete), groupController.Cancel)
+		groups.GET("/:id/folio", can(model.PermReservationsRead), groupController.Folio)
+		groups.POST("/:id/folio/payments", can(model.PermFolioWrite), groupController.PostPayment)
+	}
+
+	guests := r.Group("/guests", requireAuth)
+	{
+		guests.POST("/", can(model.PermGuestsWrite), guestController.Create)
+		guests.PUT("/:id", can(model.PermGuestsWrite), guestController.Update)
+		guests.DELETE("/:id", can(model.PermGuestsWrite), guestController.Delete)
+		guests.GET("/:id", can(model.PermGuestsRead), guestController.GetByID)
+		guests.GET("/:id/reservations", can(model.PermGuestsRead), guestController.GetReservations)
+		guests.GET("/", can(model.PermGuestsRead), guestController.GetAll)
+	}
+
+	r.GET("/availability", availabilityController.Search)
+	r.GET("/availability/room-types", availabilityController.RoomTypes)
+
+	r.GET("/audit", requireAuth, can(model.PermAuditRead), auditController.List)
+
+	ratePlans := r.Group("/rate-plans", requireAuth)
+	{
+		ratePlans.POST("/", can(model.PermRatePlansWrite), ratePlanController.Create)
+		ratePlans.PUT("/:id", can(model.PermRatePlansWrite), ratePlanController.Update)
+		ratePlans.DELETE("/:id", can(model.PermRatePlansWrite), ratePlanController.Delete)
+		ratePlans.GET("/:id", can(model.PermRatePlansRead), ratePlanController.GetByID)
+		ratePlans.GET("/", can(model.PermRatePlansRead), ratePlanController.GetAll)
+	}
+
+	// políticas de cancelamento fazem parte da configuração tarifária e usam as mesmas permissões
+	policies := r.Group("/cancellation-policies", requireAuth)
+	{
+		policies.POST("/", can(model.PermRatePlansWrite), policyController.Create)
+		policies.PUT("/:id", can(model.PermRatePlansWrite), policyController.Update)
+		policies.DELETE("/:id", can(model.PermRatePlansWrite), policyController.Delete)
+		policies.GET("/:id", can(model.PermRatePlansRead), policyController.GetByID)
+		policies.GET("/", can(model.PermRatePlansRead), policyController.GetAll)
+	}
+
+	// impostos também são configuração tarifária; o relatório exige reports:read
+	taxRules := r.Group("/tax-rules", requireAuth)
 	{
-		rooms.POST("/", roomController.Create)
-		rooms.PUT("/:id", roomController.Update)
-		rooms.DELETE("/:id", roomController.Delete)
-		rooms.GET("/:id", roomController.GetByID)
-		rooms.GET("/", roomController.GetAll)
+		taxRules.POST("/", can(model.PermRatePlansWrite), taxController.Create)
+		taxRules.PUT("/:id", can(model.PermRatePlansWrite), taxController.Update)
+		taxRules.DELETE("/:id", can(model.PermRatePlansWrite), taxController.Delete)
+		taxRules.GET("/:id", can(model.PermRatePlansRead), taxController.GetByID)
+		taxRules.GET("/", can(model.PermRatePlansRead), taxController.GetAll)
 	}
 
-	reservation := r.Group("/reservation")
+	// overbooking é gestão de receita, com as mesmas permissões dos planos tarifários
+	overbookingRules := r.Group("/overbooking-rules", requireAuth)
 	{
-		reservation.POST("/", reservationController.Create)
-		reservation.PUT("/:id", reservationController.Update)
-		reservation.DELETE("/:id", reservationController.Delete)
-		reservation.GET("/:id", reservationController.GetByID)
-		reservation.GET("/", reservationController.GetAll)
+		overbookingRules.POST("/", can(model.PermRatePlansWrite), overbookingController.Create)
+		overbookingRules.PUT("/:id", can(model.PermRatePlansWrite), overbookingController.Update)
+		overbookingRules.DELETE("/:id", can(model.PermRatePlansWrite), overbookingController.Delete)
+		overbookingRules.GET("/:id", can(model.PermRatePlansRead), overbookingController.GetByID)
+		overbookingRules.GET("/", can(model.PermRatePlansRead), overbookingController.GetAll)
+	}
+
+	reports := r.Group("/reports", requireAuth, can(model.PermReportsRead))
+	{
+		reports.GET("/taxes", taxController.Report)
+		reports.GET("/walk-list", overbookingController.WalkList)
+		reports.GET("/overbooking-alerts", overbookingController.Alerts)
+	}
+
+	nightAudit := r.Group("/night-audit", requireAuth, can(model.PermNightAuditRun))
+	{
+		nightAudit.POST("/", nightAuditController.Run)
+		nightAudit.GET("/", nightAuditController.GetAll)
+		nightAudit.GET("/:date", nightAuditController.GetByDate)
 	}
 
 	// Inicia o servidor
 	r.Run("0.0.0.0:8080")
 }
+
+// newAuthService monta o serviço de autenticação, cria os papéis padrão e garante o
+// admin de ADMIN_USERNAME/ADMIN_PASSWORD
+func newAuthService(repos dao.Repositories, roleService service.RoleService) service.AuthService {
+	if err := roleService.EnsureDefaults(); err != nil {
+		log.Fatalf("creating default roles: %v", err)
+	}
+
+	secret := helper.GetJWTSecret()
+	if secret == "" {
+		log.Println("JWT_SECRET not set: using a random secret, tokens will not survive a restart")
+	}
+	authService := service.NewAuthService(repos.Users, repos.APIKeys, repos.Roles, secret, helper.GetAccessTokenTTL(), helper.GetRefreshTokenTTL())
+
+	if username, password := helper.GetAdminCredentials(); username != "" {
+		if err := authService.EnsureAdmin(username, password); err != nil {
+			log.Fatalf("creating admin user: %v", err)
+		}
+	}
+	return authService
+}
+
+// runStayMonitor executa o StayMonitor na subida e depois a cada STAY_MONITOR_INTERVAL.
+// Uma execução com erro é só registrada: a próxima tenta de novo
+func runStayMonitor(monitor service.StayMonitor) {
+	ticker := time.NewTicker(helper.GetStayMonitorInterval())
+	defer ticker.Stop()
+	for {
+		run, err := monitor.Run(time.Now())
+		if err != nil {
+			log.Printf("stay monitor: %v", err)
+		} else if run.NoShows > 0 || run.Overstays > 0 {
+			log.Printf("stay monitor: %d no-show(s), %d overstay(s)", run.NoShows, run.Overstays)
+		}
+		<-ticker.C
+	}
+}
+
+// runHoldSweeper apaga os bloqueios vencidos a cada HOLD_SWEEP_INTERVAL. Vencidos eles
+// já não bloqueiam nada, então um erro é só registrado
+func runHoldSweeper(holds service.HoldService) {
+	ticker := time.NewTicker(helper.GetHoldSweepInterval())
+	defer ticker.Stop()
+	for range ticker.C {
+		swept, err := holds.Sweep(time.Now())
+		if err != nil {
+			log.Printf("hold sweeper: %v", err)
+		} else if swept > 0 {
+			log.Printf("hold sweeper: %d expired hold(s) removed", swept)
+		}
+	}
+}
+
+// runGroupReleaser faz o release dos grupos com o cutoff vencido na subida e depois a cada
+// GROUP_RELEASE_INTERVAL. Uma execução com erro é só registrada: a próxima tenta de novo
+func runGroupReleaser(groups service.GroupService) {
+	ticker := time.NewTicker(helper.GetGroupReleaseInterval())
+	defer ticker.Stop()
+	for {
+		released, err := groups.ReleaseExpired(time.Now())
+		if err != nil {
+			log.Printf("group releaser: %v", err)
+		} else if released > 0 {
+			log.Printf("group releaser: %d group(s) released after the cutoff", released)
+		}
+		<-ticker.C
+	}
+}
+
+// newPaymentGateway escolhe o gateway de pagamento a partir de PAYMENT_GATEWAY. Por
+// enquanto só há o stub; um adaptador real entra aqui como mais um case
+func newPaymentGateway() gateway.PaymentGateway {
+	switch name := helper.GetPaymentGateway(); name {
+	case "stub":
+		return gateway.NewStubGateway()
+	default:
+		log.Fatalf("PAYMENT_GATEWAY: unknown payment gateway %q", name)
+		return nil
+	}
+}
+
+// newRepositories escolhe o backend de persistência a partir de STORAGE_BACKEND.
+// No Postgres o servidor se recusa a subir se houver migrações pendentes.
+func newRepositories() dao.Repositories {
+	if helper.GetStorageBackend() == "memory" {
+		return dao.NewMemoryRepositories()
+	}
+
+	conn := db.GetDB()
+	migrator, err := migrations.NewMigrator(conn)
+	if err != nil {
+		log.Fatalf("loading migrations: %v", err)
+	}
+	if err := migrator.EnsureUpToDate(); err != nil {
+		log.Fatalf("refusing to start: %v", err)
+	}
+	return dao.NewPostgresRepositories(conn)
+}
@@ -0,0 +1,451 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"hotel-soa/db/dbtest"
+)
+
+// ---------------- FAKE DRIVER ----------------
+
+// fakeDB simula o suficiente do Postgres para o Migrator: a tabela schema_migrations,
+// transações e scripts, que são divididos em comandos e falham no comando que contém FAIL
+type fakeDB struct {
+	mu       sync.Mutex
+	table    bool
+	versions map[int]time.Time
+	executed []string
+}
+
+func newFakeDB() *fakeDB {
+	return &fakeDB{versions: map[int]time.Time{}}
+}
+
+func (f *fakeDB) open() *sql.DB {
+	return sql.OpenDB(fakeConnector{db: f})
+}
+
+type fakeConnector struct{ db *fakeDB }
+
+func (c fakeConnector) Connect(context.Context) (driver.Conn, error) {
+	return &fakeConn{db: c.db}, nil
+}
+
+func (c fakeConnector) Driver() driver.Driver { return fakeDriver{} }
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(string) (driver.Conn, error) {
+	return nil, errors.New("open through the connector")
+}
+
+type fakeConn struct {
+	db *fakeDB
+	tx *fakeTx
+}
+
+// fakeTx guarda o que a transação fez até o commit
+type fakeTx struct {
+	conn     *fakeConn
+	inserted []int
+	deleted  []int
+	executed []string
+}
+
+func (c *fakeConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("prepared statements are not supported")
+}
+
+func (c *fakeConn) Close() error { return nil }
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	c.tx = &fakeTx{conn: c}
+	return c.tx, nil
+}
+
+func (tx *fakeTx) Commit() error {
+	db := tx.conn.db
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	for _, version := range tx.inserted {
+		db.versions[version] = time.Now()
+	}
+	for _, version := range tx.deleted {
+		delete(db.versions, version)
+	}
+	db.executed = append(db.executed, tx.executed...)
+	tx.conn.tx = nil
+	return nil
+}
+
+func (tx *fakeTx) Rollback() error {
+	tx.conn.tx = nil
+	return nil
+}
+
+func (c *fakeConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+
+	switch {
+	case strings.HasPrefix(query, "SELECT pg_advisory_"):
+	case strings.HasPrefix(query, "CREATE TABLE IF NOT EXISTS schema_migrations"):
+		c.db.table = true
+	case strings.HasPrefix(query, "INSERT INTO schema_migrations"):
+		version := int(args[0].Value.(int64))
+		if _, ok := c.db.versions[version]; ok {
+			return nil, fmt.Errorf("duplicate key value violates unique constraint (version)=(%d)", version)
+		}
+		c.tx.inserted = append(c.tx.inserted, version)
+	case strings.HasPrefix(query, "DELETE FROM schema_migrations"):
+		c.tx.deleted = append(c.tx.deleted, int(args[0].Value.(int64)))
+	default:
+		if c.tx == nil {
+			return nil, errors.New("scripts must run inside a transaction")
+		}
+		for _, statement := range strings.Split(query, ";") {
+			statement = strings.TrimSpace(statement)
+			if statement == "" {
+				continue
+			}
+			if strings.Contains(statement, "FAIL") {
+				return nil, fmt.Errorf("syntax error at %q", statement)
+			}
+			c.tx.executed = append(c.tx.executed, statement)
+		}
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (c *fakeConn) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+
+	switch {
+	case strings.HasPrefix(query, "SELECT COALESCE(MAX(version), 0)"):
+		max := 0
+		for version := range c.db.versions {
+			if version > max {
+				max = version
+			}
+		}
+		return &fakeRows{columns: []string{"max"}, values: [][]driver.Value{{int64(max)}}}, nil
+	case strings.HasPrefix(query, "SELECT to_regclass"):
+		return &fakeRows{columns: []string{"exists"}, values: [][]driver.Value{{c.db.table}}}, nil
+	case strings.HasPrefix(query, "SELECT version, applied_at FROM schema_migrations"):
+		rows := &fakeRows{columns: []string{"version", "applied_at"}}
+		for version, at := range c.db.versions {
+			rows.values = append(rows.values, []driver.Value{int64(version), at})
+		}
+		return rows, nil
+	}
+	return nil, fmt.Errorf("unexpected query %q", query)
+}
+
+type fakeRows struct {
+	columns []string
+	values  [][]driver.Value
+	next    int
+}
+
+func (r *fakeRows) Columns() []string { return r.columns }
+
+func (r *fakeRows) Close() error { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.next == len(r.values) {
+		return io.EOF
+	}
+	copy(dest, r.values[r.next])
+	r.next++
+	return nil
+}
+
+// ---------------- TESTS ----------------
+
+func testMigrations() []Migration {
+	return []Migration{
+		{Version: 1, Name: "create_a", Up: "CREATE TABLE a", Down: "DROP TABLE a"},
+		{Version: 2, Name: "create_b", Up: "CREATE TABLE b", Down: "DROP TABLE b"},
+		{Version: 10, Name: "alter_b", Up: "ALTER TABLE b ADD c INT", Down: "ALTER TABLE b DROP c"},
+	}
+}
+
+func appliedVersions(t *testing.T, m *Migrator) []int {
+	t.Helper()
+	status, err := m.Status()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var versions []int
+	for _, s := range status {
+		if s.Applied {
+			versions = append(versions, s.Version)
+		}
+	}
+	return versions
+}
+
+func TestLoadSortsVersionsNumerically(t *testing.T) {
+	fsys := fstest.MapFS{
+		"sql/10_alter_b.up.sql":    {Data: []byte("ALTER TABLE b ADD c INT")},
+		"sql/10_alter_b.down.sql":  {Data: []byte("ALTER TABLE b DROP c")},
+		"sql/2_create_b.up.sql":    {Data: []byte("CREATE TABLE b")},
+		"sql/2_create_b.down.sql":  {Data: []byte("DROP TABLE b")},
+		"sql/01_create_a.up.sql":   {Data: []byte("CREATE TABLE a")},
+		"sql/01_create_a.down.sql": {Data: []byte("DROP TABLE a")},
+	}
+	migrations, err := load(fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(migrations, testMigrations()) {
+		t.Fatalf("expected %+v, got %+v", testMigrations(), migrations)
+	}
+}
+
+func TestLoadRejectsBrokenFiles(t *testing.T) {
+	tests := []struct {
+		name string
+		fsys fstest.MapFS
+	}{
+		{"missing down", fstest.MapFS{
+			"sql/0001_create_a.up.sql": {Data: []byte("CREATE TABLE a")},
+		}},
+		{"mismatched names", fstest.MapFS{
+			"sql/0001_create_a.up.sql":   {Data: []byte("CREATE TABLE a")},
+			"sql/0001_create_b.down.sql": {Data: []byte("DROP TABLE b")},
+		}},
+		{"invalid name", fstest.MapFS{
+			"sql/create_a.sql": {Data: []byte("CREATE TABLE a")},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := load(tt.fsys); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
+
+func TestEmbeddedMigrationsLoad(t *testing.T) {
+	migrations, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, m := range migrations {
+		if m.Version != i+1 {
+			t.Fatalf("expected migration %d to have version %d, got %04d_%s", i, i+1, m.Version, m.Name)
+		}
+	}
+}
+
+func TestUpRunsVersionsInOrder(t *testing.T) {
+	fake := newFakeDB()
+	m := &Migrator{db: fake.open(), migrations: testMigrations()}
+
+	if err := m.To(2); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Up(); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.To(0); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"CREATE TABLE a", "CREATE TABLE b", "ALTER TABLE b ADD c INT",
+		"ALTER TABLE b DROP c", "DROP TABLE b", "DROP TABLE a",
+	}
+	if !reflect.DeepEqual(fake.executed, expected) {
+		t.Fatalf("expected %q, got %q", expected, fake.executed)
+	}
+}
+
+func TestUpTwiceIsNoOp(t *testing.T) {
+	fake := newFakeDB()
+	m := &Migrator{db: fake.open(), migrations: testMigrations()}
+
+	if err := m.Up(); err != nil {
+		t.Fatal(err)
+	}
+	executed := len(fake.executed)
+	if err := m.Up(); err != nil {
+		t.Fatalf("expected the second run to succeed, got %v", err)
+	}
+	if len(fake.executed) != executed {
+		t.Fatalf("expected no script to run again, got %q", fake.executed[executed:])
+	}
+	if versions := appliedVersions(t, m); !reflect.DeepEqual(versions, []int{1, 2, 10}) {
+		t.Fatalf("expected versions 1, 2 and 10 applied, got %v", versions)
+	}
+	if err := m.EnsureUpToDate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFailingMigrationRollsBack(t *testing.T) {
+	fake := newFakeDB()
+	migrations := testMigrations()
+	migrations[1].Up = "CREATE TABLE b; INSERT INTO b FAIL"
+	m := &Migrator{db: fake.open(), migrations: migrations}
+
+	err := m.Up()
+	if err == nil || !strings.Contains(err.Error(), "0002_create_b (up)") {
+		t.Fatalf("expected migration 0002 to fail, got %v", err)
+	}
+	// o CREATE TABLE b saiu junto com a transação e a versão 10 nem rodou
+	if !reflect.DeepEqual(fake.executed, []string{"CREATE TABLE a"}) {
+		t.Fatalf("expected only migration 1 to be kept, got %q", fake.executed)
+	}
+	if versions := appliedVersions(t, m); !reflect.DeepEqual(versions, []int{1}) {
+		t.Fatalf("expected only version 1 applied, got %v", versions)
+	}
+	if err := m.EnsureUpToDate(); err == nil {
+		t.Fatal("expected pending migrations to be reported")
+	}
+
+	// corrigido o script, a próxima execução continua de onde parou
+	m.migrations[1].Up = "CREATE TABLE b"
+	if err := m.Up(); err != nil {
+		t.Fatal(err)
+	}
+	if versions := appliedVersions(t, m); !reflect.DeepEqual(versions, []int{1, 2, 10}) {
+		t.Fatalf("expected versions 1, 2 and 10 applied, got %v", versions)
+	}
+}
+
+func TestDownRevertsLastVersion(t *testing.T) {
+	fake := newFakeDB()
+	m := &Migrator{db: fake.open(), migrations: testMigrations()}
+
+	if err := m.Up(); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Down(); err != nil {
+		t.Fatal(err)
+	}
+	if versions := appliedVersions(t, m); !reflect.DeepEqual(versions, []int{1, 2}) {
+		t.Fatalf("expected versions 1 and 2 applied, got %v", versions)
+	}
+	if err := m.To(5); err == nil {
+		t.Fatal("expected an unknown version to be refused")
+	}
+}
+
+// TestEmbeddedMigrationsPostgres aplica, reaplica e reverte as migrações reais num schema
+// descartável do Postgres dos DB_*
+func TestEmbeddedMigrationsPostgres(t *testing.T) {
+	m, err := NewMigrator(dbtest.Open(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Up(); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Up(); err != nil {
+		t.Fatalf("expected the second run to be a no-op, got %v", err)
+	}
+	if err := m.EnsureUpToDate(); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.To(0); err != nil {
+		t.Fatal(err)
+	}
+	if versions := appliedVersions(t, m); len(versions) != 0 {
+		t.Fatalf("expected every migration rolled back, got %v", versions)
+	}
+	if err := m.Up(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestGuestsMigrationPostgres confere que a 0007 transforma os nomes livres das reservas
+// em hóspedes, um por nome distinto ignorando caixa e espaços
+func TestGuestsMigrationPostgres(t *testing.T) {
+	conn := dbtest.Open(t)
+	m, err := NewMigrator(conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.To(6); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := conn.Exec(`INSERT INTO rooms (id, number, type, capacity, price_per_night, status)
+		VALUES ('room-1', 101, 'STANDARD', 2, 100, 'ATIVO');`); err != nil {
+		t.Fatal(err)
+	}
+	for i, name := range []string{"Ana Souza", " ana souza ", "Bruno Lima"} {
+		if _, err := conn.Exec(`INSERT INTO reservations (id, room_id, guest_name, checkin_expected, checkout_expected, status, total_amount)
+			VALUES ($1, 'room-1', $2, $3::date, $3::date + 1, 'CREATED', 100);`, fmt.Sprintf("res-%d", i), name, fmt.Sprintf("2026-06-%02d", i+1)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := m.To(7); err != nil {
+		t.Fatal(err)
+	}
+	var guests, linked, anaStays int
+	if err := conn.QueryRow("SELECT COUNT(*) FROM guests;").Scan(&guests); err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.QueryRow("SELECT COUNT(*) FROM reservations WHERE guest_id IS NOT NULL;").Scan(&linked); err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.QueryRow(`SELECT COUNT(*) FROM reservations r JOIN guests g ON g.id = r.guest_id
+		WHERE g.name = 'Ana Souza';`).Scan(&anaStays); err != nil {
+		t.Fatal(err)
+	}
+	if guests != 2 || linked != 3 || anaStays != 2 {
+		t.Fatalf("expected 2 guests linked to 3 reservations (2 for Ana), got %d, %d and %d", guests, linked, anaStays)
+	}
+}
+
+// TestSoftDeleteRollbackPostgres confere que o down da 0012 para, sem apagar quartos,
+// quando um número foi reusado depois de arquivado
+func TestSoftDeleteRollbackPostgres(t *testing.T) {
+	conn := dbtest.Open(t)
+	m, err := NewMigrator(conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.To(12); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := conn.Exec(`INSERT INTO rooms (id, number, type, capacity, price_per_night, status, deleted_at)
+		VALUES ('room-old', 101, 'STANDARD', 2, 100, 'ATIVO', now()), ('room-new', 101, 'STANDARD', 2, 100, 'ATIVO', NULL);`); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.To(11); err == nil {
+		t.Fatal("expected the rollback to stop over a reused room number")
+	}
+	var rooms int
+	if err := conn.QueryRow("SELECT COUNT(*) FROM rooms;").Scan(&rooms); err != nil {
+		t.Fatal(err)
+	}
+	if versions := appliedVersions(t, m); rooms != 2 || versions[len(versions)-1] != 12 {
+		t.Fatalf("expected both rooms kept at version 12, got %d rooms and %v", rooms, versions)
+	}
+
+	// sem o arquivado repetido, o down volta ao UNIQUE (number)
+	if _, err := conn.Exec("DELETE FROM rooms WHERE id = 'room-old';"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.To(11); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS rooms (
+	id CHAR(36) PRIMARY KEY,
+	number INT NOT NULL UNIQUE,
+	type VARCHAR(20) NOT NULL,
+	capacity INT NOT NULL,
+	price_per_night DECIMAL(10,2) NOT NULL,
+	status VARCHAR(20) NOT NULL
+);
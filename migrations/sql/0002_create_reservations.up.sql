@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS reservations (
+	id CHAR(36) PRIMARY KEY,
+	room_id CHAR(36) NOT NULL,
+	guest_name VARCHAR(120) NOT NULL,
+	checkin_expected DATE NOT NULL,
+	checkout_expected DATE NOT NULL,
+	status VARCHAR(20) NOT NULL,
+	total_amount DECIMAL(10,2),
+	CONSTRAINT fk_reservation_room FOREIGN KEY (room_id) REFERENCES rooms(id)
+);
@@ -0,0 +1,2 @@
+ALTER TABLE reservations DROP CONSTRAINT IF EXISTS reservations_no_overlap;
+ALTER TABLE reservations DROP CONSTRAINT IF EXISTS reservations_dates_check;
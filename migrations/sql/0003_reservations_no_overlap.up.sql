@@ -0,0 +1,13 @@
+CREATE EXTENSION IF NOT EXISTS btree_gist;
+
+ALTER TABLE reservations
+	ADD CONSTRAINT reservations_dates_check
+	CHECK (checkout_expected > checkin_expected);
+
+ALTER TABLE reservations
+	ADD CONSTRAINT reservations_no_overlap
+	EXCLUDE USING gist (
+		room_id WITH =,
+		daterange(checkin_expected, checkout_expected) WITH &&
+	)
+	WHERE (status <> 'CANCELED');
@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS reservation_nights (
+	reservation_id CHAR(36) NOT NULL,
+	night DATE NOT NULL,
+	price DECIMAL(10,2) NOT NULL,
+	PRIMARY KEY (reservation_id, night),
+	CONSTRAINT fk_night_reservation FOREIGN KEY (reservation_id) REFERENCES reservations(id) ON DELETE CASCADE
+);
@@ -0,0 +1,2 @@
+ALTER TABLE reservation_nights DROP COLUMN IF EXISTS rate_plan_id;
+DROP TABLE IF EXISTS rate_plans;
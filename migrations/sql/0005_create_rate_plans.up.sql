@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS rate_plans (
+	id CHAR(36) PRIMARY KEY,
+	name VARCHAR(120) NOT NULL,
+	room_type VARCHAR(20) NOT NULL,
+	start_date DATE NOT NULL,
+	end_date DATE NOT NULL,
+	base_rate DECIMAL(10,2) NOT NULL DEFAULT 0,
+	weekday_multipliers JSONB NOT NULL DEFAULT '{}',
+	min_stay INT NOT NULL DEFAULT 0,
+	closed_to_arrival JSONB NOT NULL DEFAULT '[]',
+	priority INT NOT NULL DEFAULT 0,
+	CONSTRAINT rate_plans_dates_check CHECK (end_date >= start_date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_rate_plans_room_type_dates ON rate_plans (room_type, start_date, end_date);
+
+ALTER TABLE reservation_nights ADD COLUMN IF NOT EXISTS rate_plan_id CHAR(36);
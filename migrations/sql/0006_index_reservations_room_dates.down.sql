@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_reservations_room_dates;
@@ -0,0 +1,3 @@
+CREATE INDEX IF NOT EXISTS idx_reservations_room_dates
+	ON reservations (room_id, checkin_expected, checkout_expected)
+	WHERE status <> 'CANCELED';
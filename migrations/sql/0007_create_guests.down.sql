@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_reservations_guest;
+ALTER TABLE reservations DROP CONSTRAINT IF EXISTS fk_reservation_guest;
+ALTER TABLE reservations DROP COLUMN IF EXISTS guest_id;
+DROP TABLE IF EXISTS guests;
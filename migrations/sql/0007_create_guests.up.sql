@@ -0,0 +1,34 @@
+CREATE TABLE IF NOT EXISTS guests (
+	id CHAR(36) PRIMARY KEY,
+	name VARCHAR(120) NOT NULL,
+	document_number VARCHAR(40) UNIQUE,
+	email VARCHAR(160),
+	phone VARCHAR(40),
+	nationality VARCHAR(60),
+	preferences TEXT,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+ALTER TABLE reservations ADD COLUMN IF NOT EXISTS guest_id CHAR(36);
+
+-- um hóspede por nome distinto (ignorando caixa e espaços nas pontas)
+INSERT INTO guests (id, name)
+SELECT gen_random_uuid()::text, name
+FROM (
+	SELECT DISTINCT ON (lower(trim(guest_name))) trim(guest_name) AS name
+	FROM reservations
+	WHERE guest_id IS NULL
+	ORDER BY lower(trim(guest_name)), guest_name
+) AS distinct_names;
+
+UPDATE reservations r
+SET guest_id = g.id
+FROM guests g
+WHERE r.guest_id IS NULL
+  AND lower(trim(r.guest_name)) = lower(g.name);
+
+ALTER TABLE reservations ALTER COLUMN guest_id SET NOT NULL;
+ALTER TABLE reservations
+	ADD CONSTRAINT fk_reservation_guest FOREIGN KEY (guest_id) REFERENCES guests(id);
+
+CREATE INDEX IF NOT EXISTS idx_reservations_guest ON reservations (guest_id);
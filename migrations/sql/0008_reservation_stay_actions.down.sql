@@ -0,0 +1,4 @@
+ALTER TABLE reservations DROP COLUMN IF EXISTS checked_out_by;
+ALTER TABLE reservations DROP COLUMN IF EXISTS checked_out_at;
+ALTER TABLE reservations DROP COLUMN IF EXISTS checked_in_by;
+ALTER TABLE reservations DROP COLUMN IF EXISTS checked_in_at;
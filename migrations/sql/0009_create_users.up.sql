@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS users (
+	id CHAR(36) PRIMARY KEY,
+	username VARCHAR(60) NOT NULL UNIQUE,
+	password_hash VARCHAR(100) NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- a chave só é guardada como hash SHA-256; prefix identifica a chave nas listagens
+CREATE TABLE IF NOT EXISTS api_keys (
+	id CHAR(36) PRIMARY KEY,
+	user_id CHAR(36) NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	name VARCHAR(120) NOT NULL,
+	prefix VARCHAR(20) NOT NULL,
+	key_hash CHAR(64) NOT NULL UNIQUE,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+	last_used_at TIMESTAMPTZ,
+	revoked_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_api_keys_user ON api_keys (user_id);
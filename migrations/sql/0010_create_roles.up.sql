@@ -0,0 +1,18 @@
+-- papéis e suas permissões; os papéis padrão são criados pelo servidor na subida
+CREATE TABLE IF NOT EXISTS roles (
+	name VARCHAR(40) PRIMARY KEY,
+	description VARCHAR(200) NOT NULL DEFAULT '',
+	built_in BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+	role VARCHAR(40) NOT NULL REFERENCES roles (name) ON DELETE CASCADE,
+	permission VARCHAR(60) NOT NULL,
+	PRIMARY KEY (role, permission)
+);
+
+CREATE TABLE IF NOT EXISTS user_roles (
+	user_id CHAR(36) NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	role VARCHAR(40) NOT NULL REFERENCES roles (name) ON DELETE CASCADE,
+	PRIMARY KEY (user_id, role)
+);
@@ -0,0 +1,31 @@
+-- histórico de alterações de quartos e reservas, gravado na mesma transação da alteração
+CREATE TABLE IF NOT EXISTS audit_log (
+	id BIGSERIAL PRIMARY KEY,
+	entity VARCHAR(30) NOT NULL,
+	entity_id CHAR(36) NOT NULL,
+	action VARCHAR(30) NOT NULL,
+	actor_id VARCHAR(36) NOT NULL DEFAULT '',
+	actor VARCHAR(100) NOT NULL DEFAULT '',
+	request_id VARCHAR(100) NOT NULL DEFAULT '',
+	at TIMESTAMPTZ NOT NULL DEFAULT now(),
+	before JSONB,
+	after JSONB,
+	changes JSONB NOT NULL DEFAULT '{}'
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_entity ON audit_log (entity, entity_id, id);
+
+-- o log é só de inserção: UPDATE, DELETE e TRUNCATE são recusados
+CREATE OR REPLACE FUNCTION audit_log_append_only() RETURNS trigger AS $$
+BEGIN
+	RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_log_no_change
+	BEFORE UPDATE OR DELETE ON audit_log
+	FOR EACH ROW EXECUTE FUNCTION audit_log_append_only();
+
+CREATE TRIGGER audit_log_no_truncate
+	BEFORE TRUNCATE ON audit_log
+	FOR EACH STATEMENT EXECUTE FUNCTION audit_log_append_only();
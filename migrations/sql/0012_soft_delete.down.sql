@@ -0,0 +1,18 @@
+-- o UNIQUE (number) original não cabe depois que um número foi reusado por um quarto novo:
+-- nesse caso o down é irreversível e para aqui, antes de mexer no schema, sem apagar nenhum
+-- quarto. Apague (purge) ou renumere os quartos arquivados repetidos e rode de novo
+DO $$
+BEGIN
+	IF EXISTS (SELECT 1 FROM rooms GROUP BY number HAVING COUNT(*) > 1) THEN
+		RAISE EXCEPTION 'cannot revert 0012_soft_delete: room numbers were reused after archiving, purge or renumber the archived duplicates first';
+	END IF;
+END;
+$$;
+
+DROP INDEX IF EXISTS rooms_number_active;
+ALTER TABLE rooms ADD CONSTRAINT rooms_number_key UNIQUE (number);
+ALTER TABLE rooms DROP COLUMN IF EXISTS deleted_at;
+
+ALTER TABLE reservations DROP COLUMN IF EXISTS cancellation_reason;
+ALTER TABLE reservations DROP COLUMN IF EXISTS canceled_by;
+ALTER TABLE reservations DROP COLUMN IF EXISTS canceled_at;
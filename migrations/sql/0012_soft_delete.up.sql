@@ -0,0 +1,9 @@
+ALTER TABLE reservations ADD COLUMN IF NOT EXISTS canceled_at TIMESTAMPTZ;
+ALTER TABLE reservations ADD COLUMN IF NOT EXISTS canceled_by VARCHAR(100);
+ALTER TABLE reservations ADD COLUMN IF NOT EXISTS cancellation_reason VARCHAR(200);
+
+ALTER TABLE rooms ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+-- o número só precisa ser único entre os quartos ativos: um quarto arquivado libera o número
+ALTER TABLE rooms DROP CONSTRAINT IF EXISTS rooms_number_key;
+CREATE UNIQUE INDEX IF NOT EXISTS rooms_number_active ON rooms (number) WHERE deleted_at IS NULL;
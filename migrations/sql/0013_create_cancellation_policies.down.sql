@@ -0,0 +1,7 @@
+ALTER TABLE reservations DROP COLUMN IF EXISTS refund_amount;
+ALTER TABLE reservations DROP COLUMN IF EXISTS cancellation_fee;
+ALTER TABLE reservations DROP COLUMN IF EXISTS cancellation_policy_id;
+ALTER TABLE rate_plans DROP COLUMN IF EXISTS cancellation_policy_id;
+ALTER TABLE rooms DROP COLUMN IF EXISTS cancellation_policy_id;
+
+DROP TABLE IF EXISTS cancellation_policies;
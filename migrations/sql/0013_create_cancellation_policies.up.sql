@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS cancellation_policies (
+	id CHAR(36) PRIMARY KEY,
+	name VARCHAR(120) NOT NULL,
+	free_until_days INT NOT NULL DEFAULT 0,
+	penalty_type VARCHAR(10) NOT NULL DEFAULT 'PERCENT',
+	penalty_value DECIMAL(10,2) NOT NULL DEFAULT 0,
+	non_refundable BOOLEAN NOT NULL DEFAULT FALSE,
+	CONSTRAINT cancellation_policies_penalty_type_check CHECK (penalty_type IN ('PERCENT', 'NIGHTS'))
+);
+
+ALTER TABLE rooms ADD COLUMN IF NOT EXISTS cancellation_policy_id CHAR(36)
+	REFERENCES cancellation_policies (id);
+ALTER TABLE rate_plans ADD COLUMN IF NOT EXISTS cancellation_policy_id CHAR(36)
+	REFERENCES cancellation_policies (id);
+
+-- a política vale a partir da reserva: trocar a política do quarto ou do plano não muda reservas já feitas
+ALTER TABLE reservations ADD COLUMN IF NOT EXISTS cancellation_policy_id CHAR(36)
+	REFERENCES cancellation_policies (id);
+ALTER TABLE reservations ADD COLUMN IF NOT EXISTS cancellation_fee DECIMAL(10,2);
+ALTER TABLE reservations ADD COLUMN IF NOT EXISTS refund_amount DECIMAL(10,2);
@@ -0,0 +1,20 @@
+DROP INDEX IF EXISTS idx_reservations_status_dates;
+
+DROP INDEX IF EXISTS idx_reservations_room_dates;
+CREATE INDEX IF NOT EXISTS idx_reservations_room_dates
+	ON reservations (room_id, checkin_expected, checkout_expected)
+	WHERE status <> 'CANCELED';
+
+-- falha se um no-show se sobrepõe a outra reserva do quarto; resolva antes de reverter
+ALTER TABLE reservations DROP CONSTRAINT IF EXISTS reservations_no_overlap;
+ALTER TABLE reservations
+	ADD CONSTRAINT reservations_no_overlap
+	EXCLUDE USING gist (
+		room_id WITH =,
+		daterange(checkin_expected, checkout_expected) WITH &&
+	)
+	WHERE (status <> 'CANCELED');
+
+ALTER TABLE reservations DROP COLUMN IF EXISTS overstay_flagged_at;
+ALTER TABLE reservations DROP COLUMN IF EXISTS no_show_fee;
+ALTER TABLE reservations DROP COLUMN IF EXISTS no_show_at;
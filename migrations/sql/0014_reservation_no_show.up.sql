@@ -0,0 +1,22 @@
+ALTER TABLE reservations ADD COLUMN IF NOT EXISTS no_show_at TIMESTAMPTZ;
+ALTER TABLE reservations ADD COLUMN IF NOT EXISTS no_show_fee DECIMAL(10,2);
+ALTER TABLE reservations ADD COLUMN IF NOT EXISTS overstay_flagged_at TIMESTAMPTZ;
+
+-- um no-show libera o quarto para as noites restantes, como um cancelamento
+ALTER TABLE reservations DROP CONSTRAINT IF EXISTS reservations_no_overlap;
+ALTER TABLE reservations
+	ADD CONSTRAINT reservations_no_overlap
+	EXCLUDE USING gist (
+		room_id WITH =,
+		daterange(checkin_expected, checkout_expected) WITH &&
+	)
+	WHERE (status NOT IN ('CANCELED', 'NO_SHOW'));
+
+DROP INDEX IF EXISTS idx_reservations_room_dates;
+CREATE INDEX IF NOT EXISTS idx_reservations_room_dates
+	ON reservations (room_id, checkin_expected, checkout_expected)
+	WHERE status NOT IN ('CANCELED', 'NO_SHOW');
+
+-- o agendador procura chegadas pendentes e saídas atrasadas
+CREATE INDEX IF NOT EXISTS idx_reservations_status_dates
+	ON reservations (status, checkin_expected, checkout_expected);
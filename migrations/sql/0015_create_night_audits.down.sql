@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS room_charges;
+DROP TABLE IF EXISTS night_audits;
@@ -0,0 +1,20 @@
+-- um dia fechado pelo night audit; as noites até a última data fechada não podem mais mudar
+CREATE TABLE IF NOT EXISTS night_audits (
+	business_date DATE PRIMARY KEY,
+	closed_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+	closed_by VARCHAR(100) NOT NULL DEFAULT '',
+	charges_posted INT NOT NULL DEFAULT 0,
+	summary JSONB NOT NULL DEFAULT '{}'
+);
+
+-- diárias lançadas pelo night audit, uma por reserva e data
+CREATE TABLE IF NOT EXISTS room_charges (
+	reservation_id CHAR(36) NOT NULL REFERENCES reservations (id) ON DELETE CASCADE,
+	business_date DATE NOT NULL REFERENCES night_audits (business_date),
+	amount DECIMAL(10,2) NOT NULL,
+	rate_plan_id CHAR(36),
+	posted_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+	PRIMARY KEY (reservation_id, business_date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_room_charges_business_date ON room_charges (business_date);
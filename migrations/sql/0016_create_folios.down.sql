@@ -0,0 +1,3 @@
+-- as noites rateadas pela migração continuam: são indistinguíveis das demais e batem com total_amount
+DROP TABLE IF EXISTS folio_payments;
+DROP TABLE IF EXISTS folio_charges;
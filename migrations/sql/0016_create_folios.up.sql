@@ -0,0 +1,41 @@
+-- lançamentos da conta além das noites: consumos, taxas e descontos (negativos)
+CREATE TABLE IF NOT EXISTS folio_charges (
+	id CHAR(36) PRIMARY KEY,
+	reservation_id CHAR(36) NOT NULL REFERENCES reservations (id) ON DELETE CASCADE,
+	type VARCHAR(20) NOT NULL CHECK (type IN ('MINIBAR', 'RESTAURANT', 'TAX', 'DISCOUNT', 'OTHER')),
+	description VARCHAR(200) NOT NULL DEFAULT '',
+	business_date DATE NOT NULL,
+	amount DECIMAL(10,2) NOT NULL,
+	posted_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+	posted_by VARCHAR(100) NOT NULL DEFAULT '',
+	CONSTRAINT folio_charges_amount_sign CHECK ((type = 'DISCOUNT' AND amount < 0) OR (type <> 'DISCOUNT' AND amount > 0))
+);
+
+CREATE INDEX IF NOT EXISTS idx_folio_charges_reservation ON folio_charges (reservation_id, posted_at);
+
+-- pagamentos e estornos; amount é sempre positivo
+CREATE TABLE IF NOT EXISTS folio_payments (
+	id CHAR(36) PRIMARY KEY,
+	reservation_id CHAR(36) NOT NULL REFERENCES reservations (id) ON DELETE CASCADE,
+	type VARCHAR(10) NOT NULL CHECK (type IN ('PAYMENT', 'REFUND')),
+	method VARCHAR(20) NOT NULL,
+	amount DECIMAL(10,2) NOT NULL CHECK (amount > 0),
+	reference VARCHAR(100) NOT NULL DEFAULT '',
+	posted_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+	posted_by VARCHAR(100) NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS idx_folio_payments_reservation ON folio_payments (reservation_id, posted_at);
+
+-- total_amount passa a ser a soma das noites e dos lançamentos. As reservas anteriores ao
+-- detalhamento por noite recebem noites com o total rateado; a última absorve o arredondamento
+INSERT INTO reservation_nights (reservation_id, night, price)
+SELECT r.id, d::date,
+	CASE WHEN d::date = r.checkout_expected - 1
+		THEN r.total_amount - ROUND(r.total_amount / (r.checkout_expected - r.checkin_expected), 2) * (r.checkout_expected - r.checkin_expected - 1)
+		ELSE ROUND(r.total_amount / (r.checkout_expected - r.checkin_expected), 2)
+	END
+FROM reservations r
+CROSS JOIN LATERAL generate_series(r.checkin_expected, r.checkout_expected - 1, INTERVAL '1 day') AS d
+WHERE r.checkout_expected > r.checkin_expected
+  AND NOT EXISTS (SELECT 1 FROM reservation_nights n WHERE n.reservation_id = r.id);
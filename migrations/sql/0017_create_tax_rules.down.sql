@@ -0,0 +1,9 @@
+-- total_amount volta a ser noites mais lançamentos; os impostos à parte saem do total
+UPDATE reservations r SET total_amount = r.total_amount - t.amount
+FROM (SELECT reservation_id, SUM(amount) AS amount FROM reservation_taxes
+	WHERE NOT inclusive GROUP BY reservation_id) t
+WHERE t.reservation_id = r.id;
+
+ALTER TABLE guests DROP COLUMN IF EXISTS guest_type;
+DROP TABLE IF EXISTS reservation_taxes;
+DROP TABLE IF EXISTS tax_rules;
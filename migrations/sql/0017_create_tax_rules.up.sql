@@ -0,0 +1,35 @@
+-- impostos e taxas da propriedade: PERCENT sobre o preço das noites ou FIXED por noite
+-- ou por estadia; inclusive indica que o imposto já está no preço do quarto
+CREATE TABLE IF NOT EXISTS tax_rules (
+	id CHAR(36) PRIMARY KEY,
+	code VARCHAR(20) NOT NULL UNIQUE,
+	name VARCHAR(120) NOT NULL,
+	calculation VARCHAR(10) NOT NULL CHECK (calculation IN ('PERCENT', 'FIXED')),
+	percent DECIMAL(6,3) NOT NULL DEFAULT 0,
+	amount DECIMAL(10,2) NOT NULL DEFAULT 0,
+	basis VARCHAR(10) NOT NULL CHECK (basis IN ('PER_NIGHT', 'PER_STAY')),
+	inclusive BOOLEAN NOT NULL DEFAULT FALSE,
+	exempt_guest_types JSONB NOT NULL DEFAULT '[]',
+	active BOOLEAN NOT NULL DEFAULT TRUE,
+	CONSTRAINT tax_rules_inclusive_percent CHECK (NOT inclusive OR calculation = 'PERCENT')
+);
+
+-- impostos calculados para cada noite da reserva; os PER_STAY ficam na noite de chegada.
+-- code, name e inclusive são copiados da regra para que alterar ou apagar a regra não
+-- mude o que já foi cobrado
+CREATE TABLE IF NOT EXISTS reservation_taxes (
+	reservation_id CHAR(36) NOT NULL REFERENCES reservations (id) ON DELETE CASCADE,
+	tax_rule_id CHAR(36) NOT NULL,
+	code VARCHAR(20) NOT NULL,
+	name VARCHAR(120) NOT NULL,
+	night DATE NOT NULL,
+	amount DECIMAL(10,2) NOT NULL,
+	inclusive BOOLEAN NOT NULL DEFAULT FALSE,
+	PRIMARY KEY (reservation_id, tax_rule_id, night)
+);
+
+CREATE INDEX IF NOT EXISTS idx_reservation_taxes_night ON reservation_taxes (night);
+
+-- o tipo do hóspede decide as isenções
+ALTER TABLE guests ADD COLUMN IF NOT EXISTS guest_type VARCHAR(20) NOT NULL DEFAULT 'INDIVIDUAL'
+	CHECK (guest_type IN ('INDIVIDUAL', 'CORPORATE', 'GOVERNMENT', 'DIPLOMAT'));
@@ -0,0 +1,4 @@
+UPDATE reservations SET status = 'CREATED' WHERE status = 'CONFIRMED';
+ALTER TABLE reservations DROP COLUMN IF EXISTS confirmed_at;
+ALTER TABLE folio_payments DROP COLUMN IF EXISTS authorization_id;
+DROP TABLE IF EXISTS payment_authorizations;
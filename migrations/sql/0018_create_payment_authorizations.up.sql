@@ -0,0 +1,26 @@
+-- pré-autorizações de depósito no cartão, feitas pelo gateway de pagamento
+CREATE TABLE IF NOT EXISTS payment_authorizations (
+	id CHAR(36) PRIMARY KEY,
+	reservation_id CHAR(36) NOT NULL REFERENCES reservations (id) ON DELETE CASCADE,
+	gateway VARCHAR(40) NOT NULL,
+	status VARCHAR(20) NOT NULL CHECK (status IN ('AUTHORIZED', 'DECLINED', 'FAILED', 'CAPTURED', 'VOIDED')),
+	amount DECIMAL(10,2) NOT NULL CHECK (amount > 0),
+	captured_amount DECIMAL(10,2) NOT NULL DEFAULT 0,
+	refunded_amount DECIMAL(10,2) NOT NULL DEFAULT 0,
+	reference VARCHAR(100) NOT NULL DEFAULT '',
+	capture_reference VARCHAR(100) NOT NULL DEFAULT '',
+	decline_code VARCHAR(60) NOT NULL DEFAULT '',
+	created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+	created_by VARCHAR(100) NOT NULL DEFAULT '',
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+	CONSTRAINT payment_authorizations_amounts CHECK (captured_amount <= amount AND refunded_amount <= captured_amount)
+);
+
+CREATE INDEX IF NOT EXISTS idx_payment_authorizations_reservation ON payment_authorizations (reservation_id, created_at);
+
+-- capturas e estornos feitos pelo gateway apontam para a pré-autorização
+ALTER TABLE folio_payments
+	ADD COLUMN IF NOT EXISTS authorization_id CHAR(36) REFERENCES payment_authorizations (id) ON DELETE CASCADE;
+
+-- CONFIRMED é a reserva com depósito pré-autorizado; confirmed_at registra quando
+ALTER TABLE reservations ADD COLUMN IF NOT EXISTS confirmed_at TIMESTAMPTZ;
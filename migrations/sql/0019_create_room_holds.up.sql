@@ -0,0 +1,15 @@
+-- bloqueios temporários de quarto; só valem até expires_at e o token é guardado como hash SHA-256
+CREATE TABLE IF NOT EXISTS room_holds (
+	id CHAR(36) PRIMARY KEY,
+	room_id CHAR(36) NOT NULL REFERENCES rooms (id) ON DELETE CASCADE,
+	checkin_expected DATE NOT NULL,
+	checkout_expected DATE NOT NULL,
+	token_hash CHAR(64) NOT NULL UNIQUE,
+	expires_at TIMESTAMPTZ NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+	created_by VARCHAR(100) NOT NULL DEFAULT '',
+	CONSTRAINT room_holds_dates CHECK (checkout_expected > checkin_expected)
+);
+
+CREATE INDEX IF NOT EXISTS idx_room_holds_room_dates ON room_holds (room_id, checkin_expected, checkout_expected);
+CREATE INDEX IF NOT EXISTS idx_room_holds_expires_at ON room_holds (expires_at);
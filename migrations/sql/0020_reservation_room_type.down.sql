@@ -0,0 +1,4 @@
+-- falha se ainda houver reservas sem quarto, mesmo canceladas; atribua ou apague antes de reverter
+DROP INDEX IF EXISTS idx_reservations_type_dates;
+ALTER TABLE reservations ALTER COLUMN room_id SET NOT NULL;
+ALTER TABLE reservations DROP COLUMN IF EXISTS room_type;
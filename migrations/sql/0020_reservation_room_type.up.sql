@@ -0,0 +1,10 @@
+-- a reserva vende um tipo de quarto; room_id fica vazio até a atribuição
+ALTER TABLE reservations ADD COLUMN IF NOT EXISTS room_type VARCHAR(20);
+UPDATE reservations res SET room_type = r.type FROM rooms r WHERE r.id = res.room_id AND res.room_type IS NULL;
+ALTER TABLE reservations ALTER COLUMN room_type SET NOT NULL;
+ALTER TABLE reservations ALTER COLUMN room_id DROP NOT NULL;
+
+-- o estoque do tipo é conferido noite a noite contra as reservas ativas do tipo
+CREATE INDEX IF NOT EXISTS idx_reservations_type_dates
+	ON reservations (room_type, checkin_expected, checkout_expected)
+	WHERE status NOT IN ('CANCELED', 'NO_SHOW');
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS overbooking_rules (
+	id CHAR(36) PRIMARY KEY,
+	name VARCHAR(120) NOT NULL,
+	room_type VARCHAR(20) NOT NULL,
+	start_date DATE NOT NULL,
+	end_date DATE NOT NULL,
+	percent NUMERIC(5,2) NOT NULL,
+	CONSTRAINT overbooking_rules_dates_check CHECK (end_date >= start_date),
+	CONSTRAINT overbooking_rules_percent_check CHECK (percent > 0 AND percent <= 50),
+	-- no máximo uma regra por tipo em cada noite
+	CONSTRAINT overbooking_rules_no_overlap
+	EXCLUDE USING gist (
+		room_type WITH =,
+		daterange(start_date, end_date, '[]') WITH &&
+	)
+);
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS group_payments;
+DROP INDEX IF EXISTS idx_reservations_group;
+ALTER TABLE reservations DROP COLUMN IF EXISTS group_id;
+DROP TABLE IF EXISTS group_blocks;
+DROP TABLE IF EXISTS booking_groups;
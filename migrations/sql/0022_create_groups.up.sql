@@ -0,0 +1,50 @@
+-- blocos de quartos de eventos e operadoras; enquanto ACTIVE, os quartos do bloco não
+-- usados pelas reservas do grupo saem do estoque do tipo nas noites do bloco
+CREATE TABLE IF NOT EXISTS booking_groups (
+	id CHAR(36) PRIMARY KEY,
+	name VARCHAR(120) NOT NULL,
+	contact_name VARCHAR(120) NOT NULL,
+	contact_email VARCHAR(120) NOT NULL DEFAULT '',
+	contact_phone VARCHAR(40) NOT NULL DEFAULT '',
+	checkin_expected DATE NOT NULL,
+	checkout_expected DATE NOT NULL,
+	cutoff_date DATE NOT NULL,
+	status VARCHAR(20) NOT NULL DEFAULT 'ACTIVE' CHECK (status IN ('ACTIVE', 'RELEASED', 'CANCELED')),
+	created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+	created_by VARCHAR(100) NOT NULL DEFAULT '',
+	released_at TIMESTAMPTZ,
+	canceled_at TIMESTAMPTZ,
+	canceled_by VARCHAR(100),
+	cancellation_reason VARCHAR(200),
+	CONSTRAINT booking_groups_dates_check CHECK (checkout_expected > checkin_expected),
+	CONSTRAINT booking_groups_cutoff_check CHECK (cutoff_date <= checkin_expected)
+);
+
+CREATE INDEX IF NOT EXISTS idx_booking_groups_cutoff ON booking_groups (cutoff_date) WHERE status = 'ACTIVE';
+
+CREATE TABLE IF NOT EXISTS group_blocks (
+	group_id CHAR(36) NOT NULL REFERENCES booking_groups (id) ON DELETE CASCADE,
+	room_type VARCHAR(20) NOT NULL,
+	rooms INT NOT NULL CHECK (rooms > 0),
+	PRIMARY KEY (group_id, room_type)
+);
+
+CREATE INDEX IF NOT EXISTS idx_group_blocks_type ON group_blocks (room_type);
+
+-- as reservas da rooming list apontam para o grupo
+ALTER TABLE reservations ADD COLUMN IF NOT EXISTS group_id CHAR(36) REFERENCES booking_groups (id);
+CREATE INDEX IF NOT EXISTS idx_reservations_group ON reservations (group_id) WHERE group_id IS NOT NULL;
+
+-- pagamentos e estornos da conta master do grupo
+CREATE TABLE IF NOT EXISTS group_payments (
+	id CHAR(36) PRIMARY KEY,
+	group_id CHAR(36) NOT NULL REFERENCES booking_groups (id) ON DELETE CASCADE,
+	type VARCHAR(10) NOT NULL CHECK (type IN ('PAYMENT', 'REFUND')),
+	method VARCHAR(20) NOT NULL,
+	amount DECIMAL(10,2) NOT NULL CHECK (amount > 0),
+	reference VARCHAR(100) NOT NULL DEFAULT '',
+	posted_at TIMESTAMPTZ NOT NULL,
+	posted_by VARCHAR(100) NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_group_payments_group ON group_payments (group_id, posted_at);
@@ -0,0 +1,2 @@
+-- refresh tokens levam a versão do usuário; trocar a senha ou sair incrementa e invalida os emitidos
+ALTER TABLE users ADD COLUMN IF NOT EXISTS token_version INT NOT NULL DEFAULT 0;
@@ -0,0 +1,68 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Entidades e ações registradas no audit_log
+const (
+	AuditEntityRoom        = "room"
+	AuditEntityReservation = "reservation"
+	AuditEntityGroup       = "group"
+
+	AuditActionCreate   = "create"
+	AuditActionUpdate   = "update"
+	AuditActionCheckIn  = "check_in"
+	AuditActionCheckOut = "check_out"
+	AuditActionCancel   = "cancel"
+	AuditActionArchive  = "archive"
+	// AuditActionPurge é a remoção definitiva, restrita ao admin
+	AuditActionPurge = "purge"
+	// AuditActionNoShow e AuditActionOverstay são gravadas pelo agendador
+	AuditActionNoShow   = "no_show"
+	AuditActionOverstay = "overstay"
+	// AuditActionPostCharge e AuditActionPostPayment registram os lançamentos na conta
+	AuditActionPostCharge  = "post_charge"
+	AuditActionPostPayment = "post_payment"
+	// AuditActionConfirm é a reserva confirmada pela pré-autorização do depósito e
+	// AuditActionPaymentAuthorization registra cada mudança da autorização no gateway
+	AuditActionConfirm              = "confirm"
+	AuditActionPaymentAuthorization = "payment_authorization"
+	// AuditActionAssignRoom é a atribuição ou troca do quarto de uma reserva
+	AuditActionAssignRoom = "assign_room"
+	// AuditActionRelease é o bloco do grupo devolvido ao estoque no cutoff
+	AuditActionRelease = "release"
+)
+
+// SystemActor identifica as alterações feitas pelo próprio servidor, como as do agendador
+var SystemActor = Actor{Username: "system"}
+
+// Actor identifica quem fez uma alteração e em qual requisição; é gravado no audit_log
+type Actor struct {
+	UserID    string
+	Username  string
+	RequestID string
+}
+
+// AuditEntry é uma linha do audit_log. Before é nulo na criação e After no purge;
+// Changes traz só os campos que mudaram
+type AuditEntry struct {
+	ID        int64                  `json:"id"`
+	Entity    string                 `json:"entity" example:"reservation"`
+	EntityID  string                 `json:"entity_id"`
+	Action    string                 `json:"action" example:"update"`
+	ActorID   string                 `json:"actor_id"`
+	Actor     string                 `json:"actor" example:"recepcao"`
+	RequestID string                 `json:"request_id"`
+	At        time.Time              `json:"at"`
+	Before    json.RawMessage        `json:"before,omitempty" swaggertype:"object"`
+	After     json.RawMessage        `json:"after,omitempty" swaggertype:"object"`
+	Changes   map[string]FieldChange `json:"changes"`
+}
+
+// FieldChange é o valor de um campo antes e depois da alteração
+type FieldChange struct {
+	From any `json:"from"`
+	To   any `json:"to"`
+}
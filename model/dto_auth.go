@@ -0,0 +1,108 @@
+package model
+
+import "time"
+
+// User é uma conta de acesso à API. O hash da senha nunca é serializado; TokenVersion
+// vai nos refresh tokens e muda quando a senha é trocada ou o usuário sai
+type User struct {
+	ID           string    `json:"id"`
+	Username     string    `json:"username"`
+	PasswordHash string    `json:"-"`
+	TokenVersion int       `json:"-"`
+	CreatedAt    time.Time `json:"created_at"`
+	Roles        []string  `json:"roles"`
+}
+
+// UserRequest cria um usuário; sem roles ele recebe read_only
+type UserRequest struct {
+	Username string   `json:"username" binding:"required"`
+	Password string   `json:"password" binding:"required"`
+	Roles    []string `json:"roles" example:"front_desk"`
+}
+
+// Validate retorna um *ValidationError com todos os campos inválidos
+func (r *UserRequest) Validate() error {
+	var v ValidationError
+	if len(r.Username) < 3 || len(r.Username) > 60 {
+		v.Add("username", "invalid_length", "must have between 3 and 60 characters")
+	}
+	if len(r.Password) < 8 || len(r.Password) > 72 {
+		v.Add("password", "invalid_length", "must have between 8 and 72 characters")
+	}
+	return v.Err()
+}
+
+type LoginRequest struct {
+	Username string `json:"username" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// PasswordChangeRequest troca a senha do usuário autenticado
+type PasswordChangeRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required"`
+}
+
+// Validate retorna um *ValidationError com todos os campos inválidos
+func (r *PasswordChangeRequest) Validate() error {
+	var v ValidationError
+	if len(r.NewPassword) < 8 || len(r.NewPassword) > 72 {
+		v.Add("new_password", "invalid_length", "must have between 8 and 72 characters")
+	}
+	return v.Err()
+}
+
+// TokenPair é a resposta de login e refresh; expires_in é a validade do access token em segundos
+type TokenPair struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type" example:"Bearer"`
+	ExpiresIn    int    `json:"expires_in" example:"900"`
+}
+
+// APIKey é uma chave de longa duração para integrações. A chave em si só é
+// exibida na criação; depois disso fica apenas o prefixo
+type APIKey struct {
+	ID         string     `json:"id"`
+	UserID     string     `json:"user_id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	KeyHash    string     `json:"-"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+type APIKeyRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+// APIKeyCreated é devolvido uma única vez, com a chave completa
+type APIKeyCreated struct {
+	APIKey
+	Key string `json:"key"`
+}
+
+// Principal é quem está autenticado na requisição
+type Principal struct {
+	UserID   string `json:"user_id"`
+	Username string `json:"username"`
+	// Method é "jwt" ou "api_key"
+	Method      string   `json:"method"`
+	Roles       []string `json:"roles"`
+	Permissions []string `json:"permissions"`
+}
+
+// Can indica se o principal tem a permissão
+func (p Principal) Can(permission string) bool {
+	for _, granted := range p.Permissions {
+		if granted == permission {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,27 @@
+package model
+
+// AvailabilityQuery são os filtros de GET /availability
+type AvailabilityQuery struct {
+	Checkin  string
+	Checkout string
+	Guests   int
+	Type     string
+}
+
+// AvailableRoom é um quarto livre no período com o preço calculado para a estadia
+type AvailableRoom struct {
+	Room        Room          `json:"room"`
+	TotalAmount Money         `json:"total_amount"`
+	Nights      []NightlyRate `json:"nights"`
+}
+
+// RoomTypeAvailability é o estoque de um tipo de quarto no período: Available é o total
+// de quartos, mais os extras do overbooking, menos as reservas e bloqueios da noite mais
+// apertada, com ou sem quarto atribuído
+type RoomTypeAvailability struct {
+	RoomType    string        `json:"room_type" example:"DELUXE"`
+	TotalRooms  int           `json:"total_rooms"`
+	Available   int           `json:"available"`
+	TotalAmount *Money        `json:"total_amount,omitempty"`
+	Nights      []NightlyRate `json:"nights,omitempty"`
+}
@@ -0,0 +1,79 @@
+package model
+
+import "math"
+
+// Tipos de multa aceitos em penalty_type
+const (
+	PenaltyPercent = "PERCENT"
+	PenaltyNights  = "NIGHTS"
+)
+
+// CancellationPolicy define quanto custa cancelar uma reserva. O cancelamento é grátis
+// até free_until_days dias antes da chegada; depois disso é cobrada a multa de
+// penalty_type: PERCENT cobra penalty_value % do total e NIGHTS cobra as primeiras
+// penalty_value noites. Uma política non_refundable cobra o total em qualquer momento.
+type CancellationPolicy struct {
+	ID            string  `json:"id"`
+	Name          string  `json:"name"`
+	FreeUntilDays int     `json:"free_until_days"`
+	PenaltyType   string  `json:"penalty_type"`
+	PenaltyValue  float64 `json:"penalty_value"`
+	NonRefundable bool    `json:"non_refundable"`
+}
+
+type CancellationPolicyRequest struct {
+	ID            string  `json:"id"`
+	Name          string  `json:"name" binding:"required"`
+	FreeUntilDays int     `json:"free_until_days"`
+	PenaltyType   string  `json:"penalty_type"`
+	PenaltyValue  float64 `json:"penalty_value"`
+	NonRefundable bool    `json:"non_refundable"`
+}
+
+func (r *CancellationPolicyRequest) CancellationPolicy() *CancellationPolicy {
+	policy := &CancellationPolicy{
+		ID:            r.ID,
+		Name:          r.Name,
+		FreeUntilDays: r.FreeUntilDays,
+		PenaltyType:   r.PenaltyType,
+		PenaltyValue:  r.PenaltyValue,
+		NonRefundable: r.NonRefundable,
+	}
+	if policy.PenaltyType == "" {
+		policy.PenaltyType = PenaltyPercent
+	}
+	return policy
+}
+
+// Validate retorna um *ValidationError com todos os campos inválidos
+func (p *CancellationPolicy) Validate() error {
+	var v ValidationError
+	if p.Name == "" || len(p.Name) > 120 {
+		v.Add("name", "invalid_length", "must have 1 to 120 characters")
+	}
+	if p.FreeUntilDays < 0 {
+		v.Add("free_until_days", "out_of_range", "must not be negative")
+	}
+	switch p.PenaltyType {
+	case PenaltyPercent:
+		if p.PenaltyValue < 0 || p.PenaltyValue > 100 {
+			v.Add("penalty_value", "out_of_range", "must be between 0 and 100 for PERCENT")
+		}
+	case PenaltyNights:
+		if p.PenaltyValue < 0 || p.PenaltyValue != math.Trunc(p.PenaltyValue) {
+			v.Add("penalty_value", "out_of_range", "must be a whole number of nights for NIGHTS")
+		}
+	default:
+		v.Add("penalty_type", "invalid_value", "must be one of: PERCENT, NIGHTS")
+	}
+	return v.Err()
+}
+
+// CancellationQuote é o resultado da política aplicada a um cancelamento: a multa
+// retida e o valor a devolver do total da reserva
+type CancellationQuote struct {
+	CancellationPolicyID string `json:"cancellation_policy_id,omitempty"`
+	DaysBeforeArrival    int    `json:"days_before_arrival"`
+	Fee                  Money  `json:"cancellation_fee"`
+	RefundAmount         Money  `json:"refund_amount"`
+}
@@ -0,0 +1,38 @@
+package model
+
+import "testing"
+
+func TestCancellationPolicyValidate(t *testing.T) {
+	valid := func() CancellationPolicy {
+		return CancellationPolicy{Name: "Flexible", FreeUntilDays: 2, PenaltyType: PenaltyPercent, PenaltyValue: 50}
+	}
+	tests := []struct {
+		name   string
+		change func(p *CancellationPolicy)
+		valid  bool
+	}{
+		{"valid", func(p *CancellationPolicy) {}, true},
+		{"full percent", func(p *CancellationPolicy) { p.PenaltyValue = 100 }, true},
+		{"nights", func(p *CancellationPolicy) { p.PenaltyType, p.PenaltyValue = PenaltyNights, 1 }, true},
+		{"missing name", func(p *CancellationPolicy) { p.Name = "" }, false},
+		{"negative free days", func(p *CancellationPolicy) { p.FreeUntilDays = -1 }, false},
+		{"percent above 100", func(p *CancellationPolicy) { p.PenaltyValue = 101 }, false},
+		{"fractional nights", func(p *CancellationPolicy) { p.PenaltyType, p.PenaltyValue = PenaltyNights, 1.5 }, false},
+		{"unknown penalty type", func(p *CancellationPolicy) { p.PenaltyType = "FIXED" }, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := valid()
+			tt.change(&policy)
+			if err := policy.Validate(); (err == nil) != tt.valid {
+				t.Fatalf("expected valid=%v, got %v", tt.valid, err)
+			}
+		})
+	}
+
+	// sem penalty_type a política cobra percentual
+	req := CancellationPolicyRequest{Name: "Default"}
+	if policy := req.CancellationPolicy(); policy.PenaltyType != PenaltyPercent {
+		t.Fatalf("expected penalty type %s, got %s", PenaltyPercent, policy.PenaltyType)
+	}
+}
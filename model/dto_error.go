@@ -1,8 +1,141 @@
 package model
 
-// NOT USED
-// Just to illustrate how to create a DTO for error responses
+import (
+	"fmt"
+	"strings"
+	"time"
+)
 
-type ErrorResponse struct {
-	Error string `json:"error"`
+// ProblemContentType é o media type das respostas de erro (RFC 7807)
+const ProblemContentType = "application/problem+json"
+
+// Problem é o corpo de toda resposta de erro da API, no formato RFC 7807.
+// Code é estável e é nele que os clientes devem se basear; Detail é texto livre
+type Problem struct {
+	Type     string       `json:"type" example:"urn:hotel-soa:problem:validation_failed"`
+	Title    string       `json:"title" example:"Bad Request"`
+	Status   int          `json:"status" example:"400"`
+	Detail   string       `json:"detail,omitempty"`
+	Instance string       `json:"instance,omitempty" example:"/rooms/"`
+	Code     string       `json:"code" example:"validation_failed"`
+	Errors   []FieldError `json:"errors,omitempty"`
+	// ConflictingReservationID acompanha o code reservation_conflict
+	ConflictingReservationID string `json:"conflicting_reservation_id,omitempty"`
+	// Reservations acompanha o code room_has_reservations
+	Reservations []ReservationRef `json:"reservations,omitempty"`
+	// MissingPermission acompanha o code missing_permission
+	MissingPermission string `json:"missing_permission,omitempty" example:"rooms:delete"`
+	// Balance acompanha o code folio_balance_due
+	Balance *Money `json:"balance,omitempty" example:"150"`
+	// ReservationID e AuthorizationID acompanham os codes payment_declined e
+	// payment_gateway_timeout; DeclineCode é o motivo da recusa dado pelo gateway
+	ReservationID   string `json:"reservation_id,omitempty"`
+	AuthorizationID string `json:"authorization_id,omitempty"`
+	DeclineCode     string `json:"decline_code,omitempty" example:"insufficient_funds"`
+	// ConflictingHoldID e HoldExpiresAt acompanham o code room_on_hold
+	ConflictingHoldID string     `json:"conflicting_hold_id,omitempty"`
+	HoldExpiresAt     *time.Time `json:"hold_expires_at,omitempty"`
+	// RoomType e SoldOutDate acompanham o code room_type_sold_out
+	RoomType    string `json:"room_type,omitempty" example:"DELUXE"`
+	SoldOutDate string `json:"sold_out_date,omitempty" example:"2025-01-10"`
+}
+
+// FieldError descreve o problema de um campo numa falha de validação
+type FieldError struct {
+	Field   string `json:"field" example:"capacity"`
+	Code    string `json:"code" example:"out_of_range"`
+	Message string `json:"message" example:"must be greater than 0"`
+}
+
+// ValidationError reúne os problemas de validação encontrados, um por campo
+type ValidationError struct {
+	Errors []FieldError
+}
+
+// Add registra o problema de um campo
+func (e *ValidationError) Add(field, code, message string) {
+	e.Errors = append(e.Errors, FieldError{Field: field, Code: code, Message: message})
+}
+
+// Err retorna nil quando nenhum problema foi registrado
+func (e *ValidationError) Err() error {
+	if len(e.Errors) == 0 {
+		return nil
+	}
+	return e
+}
+
+func (e *ValidationError) Error() string {
+	parts := make([]string, len(e.Errors))
+	for i, fe := range e.Errors {
+		parts[i] = fe.Field + ": " + fe.Message
+	}
+	return "validation failed: " + strings.Join(parts, "; ")
+}
+
+// ReservationConflictError indica que o quarto já está reservado no período
+type ReservationConflictError struct {
+	RoomID        string
+	ReservationID string
+}
+
+func (e *ReservationConflictError) Error() string {
+	if e.ReservationID == "" {
+		return fmt.Sprintf("room %s is not available for the selected dates", e.RoomID)
+	}
+	return fmt.Sprintf("room %s is not available for the selected dates (conflicts with reservation %s)", e.RoomID, e.ReservationID)
+}
+
+// RoomTypeSoldOutError indica que todos os quartos do tipo já estão vendidos numa noite
+// do período, contando as reservas ainda sem quarto e os bloqueios ativos
+type RoomTypeSoldOutError struct {
+	RoomType string
+	Date     string
+}
+
+func (e *RoomTypeSoldOutError) Error() string {
+	return fmt.Sprintf("no %s rooms left for the night of %s", e.RoomType, e.Date)
+}
+
+// ConstraintViolationError indica que o banco recusou a escrita por uma restrição de
+// unicidade ou de exclusão sem erro mais específico. Err é o erro do driver, que fica
+// no servidor
+type ConstraintViolationError struct {
+	Err error
+}
+
+func (e *ConstraintViolationError) Error() string {
+	return "the change conflicts with data already stored"
+}
+
+func (e *ConstraintViolationError) Unwrap() error {
+	return e.Err
+}
+
+// PermissionError indica que o usuário autenticado não tem a permissão exigida
+type PermissionError struct {
+	Permission string
+}
+
+func (e *PermissionError) Error() string {
+	return fmt.Sprintf("missing permission %s", e.Permission)
+}
+
+// RoomInUseError indica que o quarto não pode ser arquivado ou removido porque ainda
+// há reservas que dependem dele
+type RoomInUseError struct {
+	RoomID       string
+	Reservations []ReservationRef
+}
+
+func (e *RoomInUseError) Error() string {
+	return fmt.Sprintf("room %s still has %d reservation(s)", e.RoomID, len(e.Reservations))
+}
+
+// ReservationRef resume uma reserva citada num erro
+type ReservationRef struct {
+	ID               string `json:"id"`
+	CheckinExpected  string `json:"checkin_expected"`
+	CheckoutExpected string `json:"checkout_expected"`
+	Status           string `json:"status"`
 }
@@ -0,0 +1,155 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// Tipos de lançamento da conta. ROOM são as noites da reserva; os demais são lançados
+// pela recepção, e DISCOUNT entra com valor negativo
+const (
+	FolioRoom       = "ROOM"
+	FolioMinibar    = "MINIBAR"
+	FolioRestaurant = "RESTAURANT"
+	FolioTax        = "TAX"
+	FolioDiscount   = "DISCOUNT"
+	FolioOther      = "OTHER"
+)
+
+// Tipos de pagamento: PAYMENT abate o saldo e REFUND devolve parte do que foi pago
+const (
+	PaymentTypePayment = "PAYMENT"
+	PaymentTypeRefund  = "REFUND"
+)
+
+// FolioChargeTypes lista os tipos que podem ser lançados pela API
+var FolioChargeTypes = []string{FolioMinibar, FolioRestaurant, FolioTax, FolioDiscount, FolioOther}
+
+// PaymentMethods lista as formas de pagamento aceitas
+var PaymentMethods = []string{"CASH", "CARD", "PIX", "TRANSFER"}
+
+// Folio é a conta da reserva. TotalCharges é o total_amount da reserva, TotalPaid os
+// pagamentos menos os estornos e Balance o que falta pagar (negativo quando há crédito).
+// Taxes resume os impostos da estadia; os cobrados à parte também aparecem como linhas TAX
+// em Charges. Authorizations são as pré-autorizações de cartão; só as capturas entram em
+// Payments. Numa reserva de grupo, as linhas ROOM e TAX das noites são cobradas na conta
+// master do grupo: aparecem em Charges, mas ficam fora dos totais
+type Folio struct {
+	ReservationID  string                 `json:"reservation_id"`
+	GroupID        string                 `json:"group_id,omitempty"`
+	Status         string                 `json:"status"`
+	Currency       string                 `json:"currency" example:"BRL"`
+	Charges        []FolioCharge          `json:"charges"`
+	Payments       []Payment              `json:"payments"`
+	Taxes          []TaxSummary           `json:"taxes"`
+	Authorizations []PaymentAuthorization `json:"authorizations"`
+	TotalCharges   Money                  `json:"total_charges"`
+	TotalPaid      Money                  `json:"total_paid"`
+	Balance        Money                  `json:"balance"`
+}
+
+// FolioCharge é uma linha da conta. As linhas ROOM vêm das noites da reserva, não têm
+// ID e só recebem PostedAt quando o night audit fecha a noite
+type FolioCharge struct {
+	ID            string `json:"id,omitempty"`
+	ReservationID string `json:"reservation_id"`
+	Type          string `json:"type" example:"MINIBAR"`
+	Description   string `json:"description"`
+	// Date é a noite das linhas ROOM e o dia do lançamento das demais
+	Date     string     `json:"date" example:"2026-10-16"`
+	Amount   Money      `json:"amount"`
+	PostedAt *time.Time `json:"posted_at,omitempty"`
+	PostedBy string     `json:"posted_by,omitempty"`
+}
+
+// Payment é um pagamento ou estorno da conta; Amount é sempre positivo. AuthorizationID
+// liga as capturas e estornos feitos pelo gateway à pré-autorização. Os pagamentos da
+// conta master levam GroupID em vez de ReservationID
+type Payment struct {
+	ID              string    `json:"id"`
+	ReservationID   string    `json:"reservation_id,omitempty"`
+	GroupID         string    `json:"group_id,omitempty"`
+	Type            string    `json:"type" example:"PAYMENT"`
+	Method          string    `json:"method" example:"CARD"`
+	Amount          Money     `json:"amount"`
+	Reference       string    `json:"reference,omitempty"`
+	AuthorizationID string    `json:"authorization_id,omitempty"`
+	PostedAt        time.Time `json:"posted_at"`
+	PostedBy        string    `json:"posted_by"`
+}
+
+// FolioChargeRequest é o corpo do lançamento na conta; amount é sempre positivo,
+// inclusive nos descontos
+type FolioChargeRequest struct {
+	Type        string `json:"type" binding:"required" example:"MINIBAR"`
+	Description string `json:"description" example:"2x água"`
+	Amount      Money  `json:"amount" binding:"required" example:"12.5"`
+}
+
+// Validate confere o tipo, o valor e o tamanho da descrição
+func (r *FolioChargeRequest) Validate() error {
+	var v ValidationError
+	if !r.Amount.IsPositive() {
+		v.Add("amount", "out_of_range", "must be greater than 0")
+	}
+	if !contains(FolioChargeTypes, r.Type) {
+		v.Add("type", "invalid_value", "must be one of: MINIBAR, RESTAURANT, TAX, DISCOUNT, OTHER")
+	}
+	if len(r.Description) > 200 {
+		v.Add("description", "out_of_range", "must be at most 200 characters")
+	}
+	return v.Err()
+}
+
+// PaymentRequest é o corpo do pagamento; sem type é um PAYMENT. Um REFUND com
+// authorization_id é estornado pelo gateway, contra a captura da pré-autorização
+type PaymentRequest struct {
+	Type            string `json:"type" example:"PAYMENT"`
+	Method          string `json:"method" binding:"required" example:"CARD"`
+	Amount          Money  `json:"amount" binding:"required" example:"300"`
+	Reference       string `json:"reference" example:"NSU 123456"`
+	AuthorizationID string `json:"authorization_id"`
+}
+
+// Validate confere o tipo, a forma de pagamento, o valor e o tamanho da referência
+func (r *PaymentRequest) Validate() error {
+	var v ValidationError
+	if !r.Amount.IsPositive() {
+		v.Add("amount", "out_of_range", "must be greater than 0")
+	}
+	if r.Type != "" && r.Type != PaymentTypePayment && r.Type != PaymentTypeRefund {
+		v.Add("type", "invalid_value", "must be one of: PAYMENT, REFUND")
+	}
+	if !contains(PaymentMethods, r.Method) {
+		v.Add("method", "invalid_value", "must be one of: CASH, CARD, PIX, TRANSFER")
+	}
+	if len(r.Reference) > 100 {
+		v.Add("reference", "out_of_range", "must be at most 100 characters")
+	}
+	if r.AuthorizationID != "" && (r.Type != PaymentTypeRefund || r.Method != "CARD") {
+		v.Add("authorization_id", "not_allowed", "only CARD refunds can be made through the gateway")
+	}
+	return v.Err()
+}
+
+// BalanceDueError indica que a conta precisa estar zerada para a ação, como o check-out
+type BalanceDueError struct {
+	ReservationID string
+	Balance       Money
+}
+
+func (e *BalanceDueError) Error() string {
+	if e.Balance.IsNegative() {
+		return fmt.Sprintf("folio of reservation %s has a credit of %s %s to refund", e.ReservationID, e.Balance.Neg(), e.Balance.Currency())
+	}
+	return fmt.Sprintf("folio of reservation %s has a balance of %s %s to pay", e.ReservationID, e.Balance, e.Balance.Currency())
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,43 @@
+package model
+
+import "testing"
+
+func TestFolioRequestsValidate(t *testing.T) {
+	charges := []struct {
+		name  string
+		req   FolioChargeRequest
+		valid bool
+	}{
+		{"minibar", FolioChargeRequest{Type: FolioMinibar, Description: "2x água", Amount: NewMoney(1250)}, true},
+		{"discount", FolioChargeRequest{Type: FolioDiscount, Amount: NewMoney(1000)}, true},
+		{"room charges come from the nights", FolioChargeRequest{Type: FolioRoom, Amount: NewMoney(10000)}, false},
+		{"unknown type", FolioChargeRequest{Type: "SPA", Amount: NewMoney(10000)}, false},
+		{"long description", FolioChargeRequest{Type: FolioOther, Description: string(make([]byte, 201)), Amount: NewMoney(100)}, false},
+	}
+	for _, tt := range charges {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.req.Validate(); (err == nil) != tt.valid {
+				t.Fatalf("expected valid=%v, got %v", tt.valid, err)
+			}
+		})
+	}
+
+	payments := []struct {
+		name  string
+		req   PaymentRequest
+		valid bool
+	}{
+		{"payment without type", PaymentRequest{Method: "CARD", Amount: NewMoney(10000)}, true},
+		{"refund", PaymentRequest{Type: PaymentTypeRefund, Method: "PIX", Amount: NewMoney(1000)}, true},
+		{"unknown type", PaymentRequest{Type: "CHARGEBACK", Method: "CARD", Amount: NewMoney(1000)}, false},
+		{"unknown method", PaymentRequest{Method: "CHEQUE", Amount: NewMoney(1000)}, false},
+		{"long reference", PaymentRequest{Method: "CARD", Amount: NewMoney(1000), Reference: string(make([]byte, 101))}, false},
+	}
+	for _, tt := range payments {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.req.Validate(); (err == nil) != tt.valid {
+				t.Fatalf("expected valid=%v, got %v", tt.valid, err)
+			}
+		})
+	}
+}
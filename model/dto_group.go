@@ -0,0 +1,205 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// Status de um grupo. ACTIVE segura os quartos do bloco; RELEASED devolveu ao estoque os
+// que não foram usados até o cutoff; CANCELED cancelou o grupo e as suas reservas
+const (
+	GroupActive   = "ACTIVE"
+	GroupReleased = "RELEASED"
+	GroupCanceled = "CANCELED"
+)
+
+// MaxGroupBlockRooms limita os quartos de um tipo num bloco
+const MaxGroupBlockRooms = 500
+
+// Group é um bloco de quartos reservado por um evento ou operadora, com um contato, as
+// datas do bloco e um cutoff. Enquanto ACTIVE, os quartos do bloco saem do estoque do tipo
+// em todas as noites entre checkin_expected e checkout_expected, e as reservas do grupo
+// (a rooming list) usam esses quartos. As noites e os impostos das reservas do grupo são
+// cobrados na conta master do grupo
+type Group struct {
+	ID               string       `json:"id"`
+	Name             string       `json:"name" example:"Congresso de Cardiologia"`
+	ContactName      string       `json:"contact_name" example:"Ana Souza"`
+	ContactEmail     string       `json:"contact_email,omitempty" example:"ana@eventos.com"`
+	ContactPhone     string       `json:"contact_phone,omitempty"`
+	CheckinExpected  string       `json:"checkin_expected" example:"2026-11-10"`
+	CheckoutExpected string       `json:"checkout_expected" example:"2026-11-13"`
+	CutoffDate       string       `json:"cutoff_date" example:"2026-10-27"`
+	Status           string       `json:"status" example:"ACTIVE"`
+	Blocks           []GroupBlock `json:"blocks"`
+	CreatedAt        time.Time    `json:"created_at"`
+	CreatedBy        string       `json:"created_by"`
+	ReleasedAt       *time.Time   `json:"released_at,omitempty"`
+	CanceledAt       *time.Time   `json:"canceled_at,omitempty"`
+	CanceledBy       string       `json:"canceled_by,omitempty"`
+	// CancellationReason é o motivo informado no cancelamento
+	CancellationReason string `json:"cancellation_reason,omitempty"`
+}
+
+// GroupBlock são os quartos de um tipo segurados pelo grupo; picked_up conta as reservas
+// ativas do grupo no tipo
+type GroupBlock struct {
+	RoomType string `json:"room_type" example:"DELUXE"`
+	Rooms    int    `json:"rooms" example:"20"`
+	PickedUp int    `json:"picked_up" example:"12"`
+}
+
+// Block retorna o bloco do tipo; false quando o grupo não tem quartos do tipo
+func (g *Group) Block(roomType string) (GroupBlock, bool) {
+	for _, block := range g.Blocks {
+		if block.RoomType == roomType {
+			return block, true
+		}
+	}
+	return GroupBlock{}, false
+}
+
+// Covers indica se a estadia está dentro das datas do bloco
+func (g *Group) Covers(checkin, checkout string) bool {
+	return checkin >= g.CheckinExpected && checkout <= g.CheckoutExpected
+}
+
+// GroupBlockRequest pede rooms quartos de um tipo
+type GroupBlockRequest struct {
+	RoomType string `json:"room_type" binding:"required" example:"DELUXE"`
+	Rooms    int    `json:"rooms" binding:"required" example:"20"`
+}
+
+type GroupRequest struct {
+	Name             string              `json:"name" binding:"required" example:"Congresso de Cardiologia"`
+	ContactName      string              `json:"contact_name" binding:"required" example:"Ana Souza"`
+	ContactEmail     string              `json:"contact_email" example:"ana@eventos.com"`
+	ContactPhone     string              `json:"contact_phone"`
+	CheckinExpected  string              `json:"checkin_expected" binding:"required" example:"2026-11-10"`
+	CheckoutExpected string              `json:"checkout_expected" binding:"required" example:"2026-11-13"`
+	CutoffDate       string              `json:"cutoff_date" binding:"required" example:"2026-10-27"`
+	Blocks           []GroupBlockRequest `json:"blocks" binding:"required"`
+}
+
+func (r *GroupRequest) Group() *Group {
+	group := &Group{
+		Name:             r.Name,
+		ContactName:      r.ContactName,
+		ContactEmail:     r.ContactEmail,
+		ContactPhone:     r.ContactPhone,
+		CheckinExpected:  r.CheckinExpected,
+		CheckoutExpected: r.CheckoutExpected,
+		CutoffDate:       r.CutoffDate,
+		Blocks:           []GroupBlock{},
+	}
+	for _, block := range r.Blocks {
+		group.Blocks = append(group.Blocks, GroupBlock{RoomType: block.RoomType, Rooms: block.Rooms})
+	}
+	return group
+}
+
+// Validate retorna um *ValidationError com todos os campos inválidos
+func (g *Group) Validate() error {
+	var v ValidationError
+	if g.Name == "" {
+		v.Add("name", "required", "must not be empty")
+	}
+	if g.ContactName == "" {
+		v.Add("contact_name", "required", "must not be empty")
+	}
+	checkin, checkinErr := time.Parse("2006-01-02", g.CheckinExpected)
+	checkout, checkoutErr := time.Parse("2006-01-02", g.CheckoutExpected)
+	cutoff, cutoffErr := time.Parse("2006-01-02", g.CutoffDate)
+	if checkinErr != nil {
+		v.Add("checkin_expected", "invalid_format", "expected YYYY-MM-DD")
+	}
+	if checkoutErr != nil {
+		v.Add("checkout_expected", "invalid_format", "expected YYYY-MM-DD")
+	} else if checkinErr == nil && !checkout.After(checkin) {
+		v.Add("checkout_expected", "out_of_range", "must be after checkin_expected")
+	}
+	if cutoffErr != nil {
+		v.Add("cutoff_date", "invalid_format", "expected YYYY-MM-DD")
+	} else if checkinErr == nil && cutoff.After(checkin) {
+		v.Add("cutoff_date", "out_of_range", "must not be after checkin_expected")
+	}
+	if len(g.Blocks) == 0 {
+		v.Add("blocks", "required", "must have at least one room type")
+	}
+	seen := map[string]bool{}
+	for i, block := range g.Blocks {
+		field := fmt.Sprintf("blocks[%d]", i)
+		if !ValidRoomType(block.RoomType) {
+			v.Add(field+".room_type", "invalid_value", "must be one of: STANDARD, DELUXE, SUITE")
+		} else if seen[block.RoomType] {
+			v.Add(field+".room_type", "invalid_value", "must not repeat a room type")
+		}
+		seen[block.RoomType] = true
+		if block.Rooms <= 0 || block.Rooms > MaxGroupBlockRooms {
+			v.Add(field+".rooms", "out_of_range", fmt.Sprintf("must be from 1 to %d", MaxGroupBlockRooms))
+		}
+	}
+	return v.Err()
+}
+
+// GroupPickupRequest é uma linha da rooming list: uma reserva do grupo para um hóspede.
+// Sem datas, vale a estadia do bloco; room_type pode ser omitido quando o bloco tem um só tipo
+type GroupPickupRequest struct {
+	GuestID          string `json:"guest_id"`
+	GuestName        string `json:"guest_name" example:"João Lima"`
+	RoomType         string `json:"room_type" example:"DELUXE"`
+	CheckinExpected  string `json:"checkin_expected" example:"2026-11-10"`
+	CheckoutExpected string `json:"checkout_expected" example:"2026-11-13"`
+}
+
+// RoomingListRequest traz as reservas a criar no grupo, processadas na ordem
+type RoomingListRequest struct {
+	Guests []GroupPickupRequest `json:"guests" binding:"required"`
+}
+
+// Validate limita a lista a MaxGroupBlockRooms linhas
+func (r *RoomingListRequest) Validate() error {
+	var v ValidationError
+	if len(r.Guests) == 0 || len(r.Guests) > MaxGroupBlockRooms {
+		v.Add("guests", "out_of_range", fmt.Sprintf("must have from 1 to %d guests", MaxGroupBlockRooms))
+	}
+	return v.Err()
+}
+
+// RoomingListError é a linha da rooming list que não virou reserva; index é a posição em
+// guests e code, detail e errors vêm do mesmo Problem que a criação individual devolveria
+type RoomingListError struct {
+	Index  int          `json:"index"`
+	Code   string       `json:"code" example:"room_type_sold_out"`
+	Detail string       `json:"detail,omitempty"`
+	Errors []FieldError `json:"errors,omitempty"`
+}
+
+// RoomingListResult traz as reservas criadas e as linhas recusadas da rooming list
+type RoomingListResult struct {
+	Reservations []Reservation      `json:"reservations"`
+	Errors       []RoomingListError `json:"errors"`
+}
+
+// GroupCancellation resume o cancelamento do grupo: Canceled são as reservas que
+// esperavam o hóspede e foram canceladas; Kept as que já tinham feito check-in ou
+// check-out e ficaram como estavam
+type GroupCancellation struct {
+	Group    Group    `json:"group"`
+	Canceled []string `json:"canceled"`
+	Kept     []string `json:"kept"`
+}
+
+// GroupFolio é a conta master do grupo: as noites e os impostos cobrados à parte de
+// todas as reservas ativas do grupo, com o reservation_id de cada linha, e os pagamentos
+// feitos ao grupo. Balance é o que falta pagar (negativo quando há crédito)
+type GroupFolio struct {
+	GroupID      string        `json:"group_id"`
+	Status       string        `json:"status"`
+	Currency     string        `json:"currency" example:"BRL"`
+	Charges      []FolioCharge `json:"charges"`
+	Payments     []Payment     `json:"payments"`
+	TotalCharges Money         `json:"total_charges"`
+	TotalPaid    Money         `json:"total_paid"`
+	Balance      Money         `json:"balance"`
+}
@@ -0,0 +1,82 @@
+package model
+
+import "testing"
+
+func TestGroupValidate(t *testing.T) {
+	valid := func() Group {
+		return Group{Name: "Congresso", ContactName: "Ana", CheckinExpected: "2026-11-10", CheckoutExpected: "2026-11-13", CutoffDate: "2026-10-27",
+			Blocks: []GroupBlock{{RoomType: "DELUXE", Rooms: 20}, {RoomType: "STANDARD", Rooms: 5}}}
+	}
+	tests := []struct {
+		name   string
+		change func(g *Group)
+		fields []string
+	}{
+		{"valid", func(g *Group) {}, nil},
+		{"cutoff on arrival", func(g *Group) { g.CutoffDate = g.CheckinExpected }, nil},
+		{"cutoff after arrival", func(g *Group) { g.CutoffDate = "2026-11-11" }, []string{"cutoff_date"}},
+		{"checkout on arrival", func(g *Group) { g.CheckoutExpected = g.CheckinExpected }, []string{"checkout_expected"}},
+		{"no blocks", func(g *Group) { g.Blocks = nil }, []string{"blocks"}},
+		{"repeated type", func(g *Group) { g.Blocks[1].RoomType = "DELUXE" }, []string{"blocks[1].room_type"}},
+		{"invalid block", func(g *Group) { g.Blocks[0] = GroupBlock{RoomType: "PENTHOUSE", Rooms: MaxGroupBlockRooms + 1} }, []string{"blocks[0].room_type", "blocks[0].rooms"}},
+		{"missing fields", func(g *Group) { *g = Group{Blocks: g.Blocks} }, []string{"name", "contact_name", "checkin_expected", "checkout_expected", "cutoff_date"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			group := valid()
+			tt.change(&group)
+			err := group.Validate()
+			if tt.fields == nil {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				return
+			}
+			v, ok := err.(*ValidationError)
+			if !ok || len(v.Errors) != len(tt.fields) {
+				t.Fatalf("expected errors for %v, got %v", tt.fields, err)
+			}
+			for i, field := range tt.fields {
+				if v.Errors[i].Field != field {
+					t.Fatalf("expected errors for %v, got %+v", tt.fields, v.Errors)
+				}
+			}
+		})
+	}
+}
+
+func TestGroupBlockAndCovers(t *testing.T) {
+	group := Group{CheckinExpected: "2026-11-10", CheckoutExpected: "2026-11-13", Blocks: []GroupBlock{{RoomType: "DELUXE", Rooms: 20}}}
+	if block, ok := group.Block("DELUXE"); !ok || block.Rooms != 20 {
+		t.Fatalf("expected the DELUXE block, got %+v", block)
+	}
+	if _, ok := group.Block("SUITE"); ok {
+		t.Fatal("expected no SUITE block")
+	}
+	tests := []struct {
+		checkin, checkout string
+		covers            bool
+	}{
+		{"2026-11-10", "2026-11-13", true},
+		{"2026-11-11", "2026-11-12", true},
+		{"2026-11-09", "2026-11-12", false},
+		{"2026-11-12", "2026-11-14", false},
+	}
+	for _, tt := range tests {
+		if got := group.Covers(tt.checkin, tt.checkout); got != tt.covers {
+			t.Errorf("Covers(%s, %s) = %v, expected %v", tt.checkin, tt.checkout, got, tt.covers)
+		}
+	}
+}
+
+func TestRoomingListRequestValidate(t *testing.T) {
+	if err := (&RoomingListRequest{Guests: []GroupPickupRequest{{GuestName: "Ana"}}}).Validate(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := (&RoomingListRequest{}).Validate(); err == nil {
+		t.Fatal("expected an error for an empty list")
+	}
+	if err := (&RoomingListRequest{Guests: make([]GroupPickupRequest, MaxGroupBlockRooms+1)}).Validate(); err == nil {
+		t.Fatal("expected an error for a list over the limit")
+	}
+}
@@ -0,0 +1,77 @@
+package model
+
+import "strings"
+
+// Tipos de hóspede. O tipo decide as isenções de impostos; sem tipo, o hóspede é INDIVIDUAL
+const (
+	GuestIndividual = "INDIVIDUAL"
+	GuestCorporate  = "CORPORATE"
+	GuestGovernment = "GOVERNMENT"
+	GuestDiplomat   = "DIPLOMAT"
+)
+
+// GuestTypes lista os tipos de hóspede aceitos
+var GuestTypes = []string{GuestIndividual, GuestCorporate, GuestGovernment, GuestDiplomat}
+
+type Guest struct {
+	ID             string `json:"id"`
+	Name           string `json:"name"`
+	DocumentNumber string `json:"document_number"`
+	Email          string `json:"email"`
+	Phone          string `json:"phone"`
+	Nationality    string `json:"nationality"`
+	Preferences    string `json:"preferences"`
+	Type           string `json:"type" example:"INDIVIDUAL"`
+}
+
+type GuestRequest struct {
+	ID             string `json:"id"`
+	Name           string `json:"name" binding:"required"`
+	DocumentNumber string `json:"document_number"`
+	Email          string `json:"email"`
+	Phone          string `json:"phone"`
+	Nationality    string `json:"nationality"`
+	Preferences    string `json:"preferences"`
+	Type           string `json:"type" example:"INDIVIDUAL"`
+}
+
+func (r *GuestRequest) Guest() *Guest {
+	guestType := strings.ToUpper(strings.TrimSpace(r.Type))
+	if guestType == "" {
+		guestType = GuestIndividual
+	}
+	return &Guest{
+		ID:             r.ID,
+		Name:           strings.TrimSpace(r.Name),
+		DocumentNumber: strings.TrimSpace(r.DocumentNumber),
+		Email:          strings.TrimSpace(r.Email),
+		Phone:          strings.TrimSpace(r.Phone),
+		Nationality:    strings.TrimSpace(r.Nationality),
+		Preferences:    r.Preferences,
+		Type:           guestType,
+	}
+}
+
+// Validate retorna um *ValidationError com todos os campos inválidos
+func (g *Guest) Validate() error {
+	var v ValidationError
+	if g.Name == "" || len(g.Name) > 120 {
+		v.Add("name", "invalid_length", "must have between 1 and 120 characters")
+	}
+	if len(g.DocumentNumber) > 40 {
+		v.Add("document_number", "invalid_length", "must have at most 40 characters")
+	}
+	if g.Email != "" && (!strings.Contains(g.Email, "@") || len(g.Email) > 160) {
+		v.Add("email", "invalid_format", "must be a valid email address")
+	}
+	if len(g.Phone) > 40 {
+		v.Add("phone", "invalid_length", "must have at most 40 characters")
+	}
+	if len(g.Nationality) > 60 {
+		v.Add("nationality", "invalid_length", "must have at most 60 characters")
+	}
+	if g.Type != "" && !contains(GuestTypes, g.Type) {
+		v.Add("type", "invalid_value", "must be one of: INDIVIDUAL, CORPORATE, GOVERNMENT, DIPLOMAT")
+	}
+	return v.Err()
+}
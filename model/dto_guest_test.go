@@ -0,0 +1,40 @@
+package model
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGuestValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		guest Guest
+		valid bool
+	}{
+		{"name only", Guest{Name: "Ana"}, true},
+		{"full profile", Guest{Name: "Ana", DocumentNumber: "123.456.789-00", Email: "ana@example.com", Phone: "+55 11 99999-0000", Nationality: "BR"}, true},
+		{"missing name", Guest{}, false},
+		{"long name", Guest{Name: strings.Repeat("a", 121)}, false},
+		{"long document", Guest{Name: "Ana", DocumentNumber: strings.Repeat("1", 41)}, false},
+		{"invalid email", Guest{Name: "Ana", Email: "ana.example.com"}, false},
+		{"long phone", Guest{Name: "Ana", Phone: strings.Repeat("9", 41)}, false},
+		{"long nationality", Guest{Name: "Ana", Nationality: strings.Repeat("B", 61)}, false},
+		{"diplomat", Guest{Name: "Ana", Type: GuestDiplomat}, true},
+		{"unknown type", Guest{Name: "Ana", Type: "VIP"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.guest.Validate(); (err == nil) != tt.valid {
+				t.Fatalf("expected valid=%v, got %v", tt.valid, err)
+			}
+		})
+	}
+}
+
+func TestGuestRequestTrimsFields(t *testing.T) {
+	req := GuestRequest{Name: "  Ana  ", DocumentNumber: " AB123 ", Email: " ana@example.com "}
+	guest := req.Guest()
+	if guest.Name != "Ana" || guest.DocumentNumber != "AB123" || guest.Email != "ana@example.com" {
+		t.Fatalf("expected trimmed fields, got %+v", guest)
+	}
+}
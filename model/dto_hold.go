@@ -0,0 +1,66 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// Limites de ttl_seconds num bloqueio
+const (
+	MinHoldTTL = time.Minute
+	MaxHoldTTL = time.Hour
+)
+
+// RoomHold bloqueia um quarto no período enquanto o hóspede paga. Até expires_at ele
+// conta como uma reserva nas checagens de conflito e na disponibilidade; depois disso
+// deixa de valer e é apagado pelo agendador. O token só é guardado como hash
+type RoomHold struct {
+	ID               string    `json:"id"`
+	RoomID           string    `json:"room_id"`
+	CheckinExpected  string    `json:"checkin_expected"`
+	CheckoutExpected string    `json:"checkout_expected"`
+	TokenHash        string    `json:"-"`
+	ExpiresAt        time.Time `json:"expires_at"`
+	CreatedAt        time.Time `json:"created_at"`
+	CreatedBy        string    `json:"created_by"`
+}
+
+// Active indica se o bloqueio ainda vale em at
+func (h *RoomHold) Active(at time.Time) bool {
+	return h.ExpiresAt.After(at)
+}
+
+// RoomHoldCreated é devolvido uma única vez, com o token que converte o bloqueio em reserva
+type RoomHoldCreated struct {
+	RoomHold
+	Token string `json:"token"`
+}
+
+// HoldRequest pede um bloqueio; sem ttl_seconds vale o HOLD_TTL do servidor
+type HoldRequest struct {
+	RoomID           string `json:"room_id" binding:"required"`
+	CheckinExpected  string `json:"checkin_expected" binding:"required"`
+	CheckoutExpected string `json:"checkout_expected" binding:"required"`
+	TTLSeconds       int    `json:"ttl_seconds" example:"600"`
+}
+
+// Validate confere a validade pedida; as datas são conferidas pelo service
+func (r *HoldRequest) Validate() error {
+	var v ValidationError
+	ttl := time.Duration(r.TTLSeconds) * time.Second
+	if r.TTLSeconds != 0 && (ttl < MinHoldTTL || ttl > MaxHoldTTL) {
+		v.Add("ttl_seconds", "out_of_range", fmt.Sprintf("must be between %d and %d", int(MinHoldTTL.Seconds()), int(MaxHoldTTL.Seconds())))
+	}
+	return v.Err()
+}
+
+// RoomHeldError indica que o quarto está bloqueado no período por um hold ainda ativo
+type RoomHeldError struct {
+	RoomID    string
+	HoldID    string
+	ExpiresAt time.Time
+}
+
+func (e *RoomHeldError) Error() string {
+	return fmt.Sprintf("room %s is on hold for the selected dates until %s", e.RoomID, e.ExpiresAt.UTC().Format(time.RFC3339))
+}
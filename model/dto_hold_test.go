@@ -0,0 +1,34 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHoldRequestValidate(t *testing.T) {
+	tests := []struct {
+		ttlSeconds int
+		valid      bool
+	}{
+		{0, true},
+		{60, true},
+		{3600, true},
+		{59, false},
+		{3601, false},
+		{-1, false},
+	}
+	for _, tt := range tests {
+		req := HoldRequest{RoomID: "r1", CheckinExpected: "2026-06-10", CheckoutExpected: "2026-06-12", TTLSeconds: tt.ttlSeconds}
+		if err := req.Validate(); (err == nil) != tt.valid {
+			t.Fatalf("ttl_seconds %d: expected valid %v, got %v", tt.ttlSeconds, tt.valid, err)
+		}
+	}
+}
+
+func TestRoomHoldActive(t *testing.T) {
+	expires := time.Date(2026, 6, 1, 12, 10, 0, 0, time.UTC)
+	hold := RoomHold{ExpiresAt: expires}
+	if !hold.Active(expires.Add(-time.Second)) || hold.Active(expires) {
+		t.Fatal("expected the hold to be active until expires_at, exclusive")
+	}
+}
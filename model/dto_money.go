@@ -0,0 +1,278 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// DefaultCurrency é a moeda da propriedade quando CURRENCY não é configurada
+const DefaultCurrency = "BRL"
+
+// currencyExponents são as moedas ISO 4217 aceitas e as casas decimais de cada uma.
+// As colunas de valor são DECIMAL(10,2), então não entram moedas com mais de 2 casas
+var currencyExponents = map[string]int{
+	"ARS": 2, "AUD": 2, "BRL": 2, "CAD": 2, "CHF": 2, "CNY": 2, "COP": 2, "EUR": 2,
+	"GBP": 2, "MXN": 2, "PEN": 2, "USD": 2, "UYU": 2,
+	"CLP": 0, "JPY": 0, "KRW": 0, "PYG": 0,
+}
+
+// propertyCurrency é a moeda de todos os valores da propriedade
+var propertyCurrency = DefaultCurrency
+
+// SetCurrency define a moeda da propriedade. É chamada uma vez na inicialização, antes
+// de qualquer valor ser lido; trocar a moeda não converte os valores já gravados
+func SetCurrency(code string) error {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if _, ok := currencyExponents[code]; !ok {
+		return fmt.Errorf("unsupported currency %q", code)
+	}
+	propertyCurrency = code
+	return nil
+}
+
+// Currency retorna a moeda da propriedade
+func Currency() string {
+	return propertyCurrency
+}
+
+// Rounding é a regra que leva um valor calculado a um número inteiro de unidades menores
+type Rounding int
+
+const (
+	// RoundHalfUp leva a metade para longe do zero: 10,005 vira 10,01
+	RoundHalfUp Rounding = iota
+	// RoundHalfEven leva a metade para o vizinho par: 10,005 vira 10,00 e 10,015 vira 10,02
+	RoundHalfEven
+)
+
+// Regras de arredondamento de cada cálculo. Somas e subtrações são sempre exatas
+const (
+	// NightlyRounding vale para o preço da noite: tarifa base vezes o multiplicador do dia
+	NightlyRounding = RoundHalfUp
+	// FeeRounding vale para multas percentuais de cancelamento e no-show
+	FeeRounding = RoundHalfUp
+	// DepositRounding vale para o depósito percentual pré-autorizado no cartão
+	DepositRounding = RoundHalfUp
+	// TaxRounding vale para impostos calculados sobre um valor
+	TaxRounding = RoundHalfEven
+	// AverageRounding vale para médias, como a diária média do night audit
+	AverageRounding = RoundHalfUp
+)
+
+// Money é um valor monetário exato: um inteiro de unidades menores (centavos, no BRL) e
+// a moeda ISO 4217. No JSON continua sendo um número, com as casas decimais da moeda, e
+// no banco é lido e gravado como DECIMAL sem passar por float. O valor zero é zero na
+// moeda da propriedade
+type Money struct {
+	minor    int64
+	currency string
+}
+
+// NewMoney cria um valor na moeda da propriedade a partir das unidades menores
+func NewMoney(minor int64) Money {
+	return Money{minor: minor, currency: propertyCurrency}
+}
+
+// ParseMoney lê um decimal como "120.50" na moeda da propriedade. Casas além das da
+// moeda só são aceitas quando são zeros
+func ParseMoney(value string) (Money, error) {
+	value = strings.TrimSpace(value)
+	exponent := currencyExponents[propertyCurrency]
+	amount, ok := new(big.Rat).SetString(value)
+	if !ok || strings.Contains(value, "/") {
+		return Money{}, fmt.Errorf("%q is not a decimal amount", value)
+	}
+	amount.Mul(amount, new(big.Rat).SetInt(pow10(exponent)))
+	if !amount.IsInt() {
+		return Money{}, fmt.Errorf("%s has more than %d decimal places for %s", value, exponent, propertyCurrency)
+	}
+	if !amount.Num().IsInt64() {
+		return Money{}, fmt.Errorf("%s is out of range", value)
+	}
+	return NewMoney(amount.Num().Int64()), nil
+}
+
+// Minor retorna o valor em unidades menores
+func (m Money) Minor() int64 {
+	return m.minor
+}
+
+// Currency retorna a moeda do valor
+func (m Money) Currency() string {
+	if m.currency == "" {
+		return propertyCurrency
+	}
+	return m.currency
+}
+
+func (m Money) IsZero() bool     { return m.minor == 0 }
+func (m Money) IsPositive() bool { return m.minor > 0 }
+func (m Money) IsNegative() bool { return m.minor < 0 }
+
+// Equal compara valor e moeda; use no lugar de ==, que distingue o valor zero de NewMoney(0)
+func (m Money) Equal(other Money) bool {
+	return m.minor == other.minor && m.Currency() == other.Currency()
+}
+
+// Cmp retorna -1, 0 ou 1 conforme m é menor, igual ou maior que other
+func (m Money) Cmp(other Money) int {
+	switch {
+	case m.minor < other.minor:
+		return -1
+	case m.minor > other.minor:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Os valores de uma propriedade estão todos na mesma moeda, então as operações não
+// convertem: o resultado fica na moeda de m
+
+func (m Money) Add(other Money) Money {
+	return Money{minor: m.minor + other.minor, currency: m.Currency()}
+}
+
+func (m Money) Sub(other Money) Money {
+	return Money{minor: m.minor - other.minor, currency: m.Currency()}
+}
+
+func (m Money) Neg() Money {
+	return Money{minor: -m.minor, currency: m.Currency()}
+}
+
+// Mul multiplica por um fator, como o multiplicador do dia da semana. O fator é lido
+// pela sua menor representação decimal (1.1 é 11/10, não o binário mais próximo), e o
+// produto exato é arredondado uma única vez
+func (m Money) Mul(factor float64, rounding Rounding) Money {
+	return m.mulRat(decimalRat(factor), rounding)
+}
+
+// Percent calcula percent% do valor
+func (m Money) Percent(percent float64, rounding Rounding) Money {
+	return m.Ratio(percent, 100, rounding)
+}
+
+// Ratio multiplica por num/den com um único arredondamento, como o imposto embutido que
+// é p/(100+soma dos percentuais embutidos) do preço; den deve ser positivo
+func (m Money) Ratio(num, den float64, rounding Rounding) Money {
+	return m.mulRat(new(big.Rat).Quo(decimalRat(num), decimalRat(den)), rounding)
+}
+
+// Div divide o valor em n partes iguais; n deve ser positivo
+func (m Money) Div(n int64, rounding Rounding) Money {
+	return m.mulRat(big.NewRat(1, n), rounding)
+}
+
+func (m Money) mulRat(factor *big.Rat, rounding Rounding) Money {
+	product := new(big.Rat).Mul(new(big.Rat).SetInt64(m.minor), factor)
+	return Money{minor: round(product, rounding), currency: m.Currency()}
+}
+
+// String formata o valor com as casas decimais da moeda, como "120.50"
+func (m Money) String() string {
+	exponent := currencyExponents[m.Currency()]
+	sign, minor := "", m.minor
+	if minor < 0 {
+		sign, minor = "-", -minor
+	}
+	if exponent == 0 {
+		return sign + strconv.FormatInt(minor, 10)
+	}
+	unit := pow10(exponent).Int64()
+	return fmt.Sprintf("%s%d.%0*d", sign, minor/unit, exponent, minor%unit)
+}
+
+// MarshalJSON grava o valor como número JSON, compatível com o float64 de antes
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalJSON aceita um número ou uma string decimal. Um valor com mais casas que a
+// moeda é recusado como *json.UnmarshalTypeError, para virar erro do campo
+func (m *Money) UnmarshalJSON(data []byte) error {
+	text := string(data)
+	if text == "null" {
+		return nil
+	}
+	if unquoted, err := strconv.Unquote(text); err == nil {
+		text = unquoted
+	}
+	parsed, err := ParseMoney(text)
+	if err != nil {
+		return &json.UnmarshalTypeError{Value: "number " + text, Type: reflect.TypeOf(Money{})}
+	}
+	*m = parsed
+	return nil
+}
+
+// Scan lê uma coluna DECIMAL, que o driver entrega como texto
+func (m *Money) Scan(src any) error {
+	switch v := src.(type) {
+	case []byte:
+		return m.scanText(string(v))
+	case string:
+		return m.scanText(v)
+	case int64:
+		return m.scanText(strconv.FormatInt(v, 10))
+	case nil:
+		*m = NewMoney(0)
+		return nil
+	default:
+		return fmt.Errorf("cannot scan %T into Money", src)
+	}
+}
+
+func (m *Money) scanText(text string) error {
+	parsed, err := ParseMoney(text)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// Value grava o valor como texto decimal, sem perda
+func (m Money) Value() (driver.Value, error) {
+	return m.String(), nil
+}
+
+// DecimalPlaces retorna as casas decimais da moeda da propriedade
+func DecimalPlaces() int {
+	return currencyExponents[propertyCurrency]
+}
+
+// round arredonda um racional para inteiro pela regra dada
+func round(value *big.Rat, rounding Rounding) int64 {
+	num, den := value.Num(), value.Denom()
+	quo, rem := new(big.Int).QuoRem(num, den, new(big.Int))
+	twice := new(big.Int).Abs(rem)
+	twice.Lsh(twice, 1)
+	switch c := twice.Cmp(den); {
+	case c > 0, c == 0 && (rounding == RoundHalfUp || quo.Bit(0) == 1):
+		if num.Sign() < 0 {
+			quo.Sub(quo, big.NewInt(1))
+		} else {
+			quo.Add(quo, big.NewInt(1))
+		}
+	}
+	return quo.Int64()
+}
+
+// decimalRat lê um float pela sua menor representação decimal
+func decimalRat(value float64) *big.Rat {
+	rat, ok := new(big.Rat).SetString(strconv.FormatFloat(value, 'f', -1, 64))
+	if !ok {
+		return new(big.Rat)
+	}
+	return rat
+}
+
+func pow10(exponent int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(exponent)), nil)
+}
@@ -0,0 +1,133 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		value   string
+		minor   int64
+		invalid bool
+	}{
+		{"120.50", 12050, false},
+		{"120.5", 12050, false},
+		{"120", 12000, false},
+		{"0.01", 1, false},
+		{"-3.10", -310, false},
+		{"10.000", 1000, false},
+		{" 7.25 ", 725, false},
+		{"10.005", 0, true},
+		{"1/3", 0, true},
+		{"abc", 0, true},
+		{"", 0, true},
+		{"92233720368547758.08", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			money, err := ParseMoney(tt.value)
+			if tt.invalid {
+				if err == nil {
+					t.Fatalf("expected an error, got %s", money)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if money.Minor() != tt.minor {
+				t.Fatalf("expected %d minor units, got %d", tt.minor, money.Minor())
+			}
+		})
+	}
+}
+
+func TestMoneyRounding(t *testing.T) {
+	tests := []struct {
+		name   string
+		got    Money
+		expect int64
+	}{
+		// 1000,5 centavos: a metade vai para longe do zero no half up e para o par no half even
+		{"percent half up", NewMoney(2001).Percent(50, RoundHalfUp), 1001},
+		{"percent half even down", NewMoney(2001).Percent(50, RoundHalfEven), 1000},
+		{"percent half even up", NewMoney(2003).Percent(50, RoundHalfEven), 1002},
+		{"negative half up", NewMoney(-2001).Percent(50, RoundHalfUp), -1001},
+		{"negative half even", NewMoney(-2001).Percent(50, RoundHalfEven), -1000},
+		{"below half", NewMoney(1001).Percent(10, RoundHalfUp), 100},
+		{"above half", NewMoney(1006).Percent(10, RoundHalfEven), 101},
+		// 1.1 é lido como 11/10, não como o binário 1.100000000000000088...
+		{"decimal factor", NewMoney(1005).Mul(1.1, RoundHalfUp), 1106},
+		{"decimal factor half even", NewMoney(1015).Mul(1.1, RoundHalfEven), 1116},
+		{"inclusive tax ratio", NewMoney(10500).Ratio(5, 105, RoundHalfEven), 500},
+		{"average", NewMoney(1000).Div(3, RoundHalfUp), 333},
+		{"average half", NewMoney(1001).Div(2, RoundHalfUp), 501},
+		{"average half even", NewMoney(1001).Div(2, RoundHalfEven), 500},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got.Minor() != tt.expect {
+				t.Fatalf("expected %d minor units, got %d", tt.expect, tt.got.Minor())
+			}
+		})
+	}
+}
+
+func TestMoneyString(t *testing.T) {
+	tests := []struct {
+		currency string
+		minor    int64
+		expect   string
+	}{
+		{"BRL", 12050, "120.50"},
+		{"BRL", 5, "0.05"},
+		{"BRL", -5, "-0.05"},
+		{"BRL", 0, "0.00"},
+		{"JPY", 1200, "1200"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.currency+" "+tt.expect, func(t *testing.T) {
+			if got := (Money{minor: tt.minor, currency: tt.currency}).String(); got != tt.expect {
+				t.Fatalf("expected %s, got %s", tt.expect, got)
+			}
+		})
+	}
+}
+
+func TestMoneyJSON(t *testing.T) {
+	var body struct {
+		Price Money `json:"price"`
+	}
+	if err := json.Unmarshal([]byte(`{"price":"89.9"}`), &body); err != nil || body.Price.Minor() != 8990 {
+		t.Fatalf("expected 8990 minor units from a string, got %d, %v", body.Price.Minor(), err)
+	}
+	if err := json.Unmarshal([]byte(`{"price":120.5}`), &body); err != nil || body.Price.Minor() != 12050 {
+		t.Fatalf("expected 12050 minor units from a number, got %d, %v", body.Price.Minor(), err)
+	}
+	var typeErr *json.UnmarshalTypeError
+	if err := json.Unmarshal([]byte(`{"price":10.005}`), &body); !errors.As(err, &typeErr) {
+		t.Fatalf("expected an UnmarshalTypeError, got %v", err)
+	}
+	out, err := json.Marshal(body)
+	if err != nil || string(out) != `{"price":120.50}` {
+		t.Fatalf("expected the price as a JSON number, got %s, %v", out, err)
+	}
+}
+
+func TestSetCurrency(t *testing.T) {
+	t.Cleanup(func() { _ = SetCurrency(DefaultCurrency) })
+	if err := SetCurrency("xyz"); err == nil {
+		t.Fatal("expected an unsupported currency to be refused")
+	}
+	if err := SetCurrency(" jpy "); err != nil || Currency() != "JPY" || DecimalPlaces() != 0 {
+		t.Fatalf("expected JPY with no decimals, got %s %d, %v", Currency(), DecimalPlaces(), err)
+	}
+	if _, err := ParseMoney("10.50"); err == nil {
+		t.Fatal("expected cents to be refused in JPY")
+	}
+	if money, err := ParseMoney("1200"); err != nil || money.String() != "1200" {
+		t.Fatalf("expected 1200, got %s, %v", money, err)
+	}
+}
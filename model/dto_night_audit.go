@@ -0,0 +1,42 @@
+package model
+
+import "time"
+
+// NightAudit é o fechamento de um dia de operação (business date). Depois dele as
+// diárias da data estão lançadas e as noites até ela não podem mais ser alteradas
+type NightAudit struct {
+	BusinessDate  string       `json:"business_date" example:"2026-10-16"`
+	ClosedAt      time.Time    `json:"closed_at"`
+	ClosedBy      string       `json:"closed_by"`
+	ChargesPosted int          `json:"charges_posted"`
+	Summary       DailySummary `json:"summary"`
+}
+
+// DailySummary é a foto do dia gravada pelo night audit
+type DailySummary struct {
+	RoomsAvailable int     `json:"rooms_available"`
+	RoomsOccupied  int     `json:"rooms_occupied"`
+	OccupancyRate  float64 `json:"occupancy_rate"`
+	RoomRevenue    Money   `json:"room_revenue"`
+	// ADR é a diária média dos quartos ocupados (average daily rate)
+	ADR        Money `json:"adr"`
+	Arrivals   int   `json:"arrivals"`
+	Departures int   `json:"departures"`
+	NoShows    int   `json:"no_shows"`
+}
+
+// RoomCharge é a diária de uma noite lançada pelo night audit; há no máximo uma por
+// reserva e data
+type RoomCharge struct {
+	ReservationID string    `json:"reservation_id"`
+	BusinessDate  string    `json:"business_date"`
+	Amount        Money     `json:"amount"`
+	RatePlanID    string    `json:"rate_plan_id,omitempty"`
+	PostedAt      time.Time `json:"posted_at"`
+}
+
+// NightAuditRequest é o corpo opcional do night audit; sem business_date fecha o
+// próximo dia em aberto
+type NightAuditRequest struct {
+	BusinessDate string `json:"business_date" example:"2026-10-16"`
+}
@@ -0,0 +1,118 @@
+package model
+
+import (
+	"math"
+	"time"
+)
+
+// MaxOverbookingPercent limita quanto um tipo de quarto pode ser vendido além do físico
+const MaxOverbookingPercent = 50
+
+// OverbookingRule permite vender percent% a mais dos quartos de um tipo nas noites entre
+// start_date e end_date (inclusivos). Os quartos extras são floor(quartos * percent / 100)
+// e valem só para o estoque do tipo: um quarto específico nunca recebe duas reservas.
+// Regras do mesmo tipo não podem se sobrepor
+type OverbookingRule struct {
+	ID        string  `json:"id"`
+	Name      string  `json:"name" example:"Réveillon"`
+	RoomType  string  `json:"room_type" example:"DELUXE"`
+	StartDate string  `json:"start_date" example:"2026-12-28"`
+	EndDate   string  `json:"end_date" example:"2027-01-02"`
+	Percent   float64 `json:"percent" example:"5"`
+}
+
+type OverbookingRuleRequest struct {
+	ID        string  `json:"id"`
+	Name      string  `json:"name" binding:"required" example:"Réveillon"`
+	RoomType  string  `json:"room_type" binding:"required" example:"DELUXE"`
+	StartDate string  `json:"start_date" binding:"required" example:"2026-12-28"`
+	EndDate   string  `json:"end_date" binding:"required" example:"2027-01-02"`
+	Percent   float64 `json:"percent" example:"5"`
+}
+
+func (r *OverbookingRuleRequest) OverbookingRule() *OverbookingRule {
+	return &OverbookingRule{
+		ID:        r.ID,
+		Name:      r.Name,
+		RoomType:  r.RoomType,
+		StartDate: r.StartDate,
+		EndDate:   r.EndDate,
+		Percent:   r.Percent,
+	}
+}
+
+// Validate retorna um *ValidationError com todos os campos inválidos
+func (r *OverbookingRule) Validate() error {
+	var v ValidationError
+	if r.Name == "" {
+		v.Add("name", "required", "must not be empty")
+	}
+	if !ValidRoomType(r.RoomType) {
+		v.Add("room_type", "invalid_value", "must be one of: STANDARD, DELUXE, SUITE")
+	}
+	start, startErr := time.Parse("2006-01-02", r.StartDate)
+	end, endErr := time.Parse("2006-01-02", r.EndDate)
+	if startErr != nil {
+		v.Add("start_date", "invalid_format", "expected YYYY-MM-DD")
+	}
+	if endErr != nil {
+		v.Add("end_date", "invalid_format", "expected YYYY-MM-DD")
+	} else if startErr == nil && end.Before(start) {
+		v.Add("end_date", "out_of_range", "must not be before start_date")
+	}
+	if r.Percent <= 0 || r.Percent > MaxOverbookingPercent {
+		v.Add("percent", "out_of_range", "must be greater than 0 and at most 50")
+	}
+	return v.Err()
+}
+
+// OverbookingAllowance é quantos quartos a regra permite vender além dos rooms físicos,
+// arredondado para baixo. percent é considerado com duas casas, como na coluna NUMERIC(5,2)
+func OverbookingAllowance(rooms int, percent float64) int {
+	return rooms * int(math.Round(percent*100)) / 10000
+}
+
+// RoomTypeNight é a ocupação de um tipo de quarto numa noite: booked conta as reservas
+// ativas, com ou sem quarto, held os bloqueios ativos e blocked os quartos ainda não
+// usados dos blocos de grupo. limit é total_rooms mais os quartos extras da regra de
+// overbooking da noite. Também é o alerta de overbooking, quando booked, held e blocked
+// somados chegam ao limit de uma noite com quartos extras
+type RoomTypeNight struct {
+	Date       string `json:"date" example:"2026-12-31"`
+	RoomType   string `json:"room_type" example:"DELUXE"`
+	TotalRooms int    `json:"total_rooms" example:"20"`
+	Limit      int    `json:"limit" example:"21"`
+	Booked     int    `json:"booked" example:"21"`
+	Held       int    `json:"held" example:"0"`
+	Blocked    int    `json:"blocked" example:"0"`
+}
+
+// WalkCandidate é uma reserva que pode ser realocada em outro hotel numa noite vendida
+// acima do físico
+type WalkCandidate struct {
+	ReservationID    string `json:"reservation_id"`
+	GuestName        string `json:"guest_name"`
+	Status           string `json:"status" example:"CREATED"`
+	RoomID           string `json:"room_id,omitempty"`
+	CheckinExpected  string `json:"checkin_expected"`
+	CheckoutExpected string `json:"checkout_expected"`
+}
+
+// WalkListNight é uma noite em que as reservas do tipo passam dos quartos físicos.
+// excess é quantos hóspedes ficarão sem quarto se todos chegarem; candidates traz, em
+// ordem de preferência, as reservas a realocar primeiro
+type WalkListNight struct {
+	Date       string          `json:"date" example:"2026-12-31"`
+	RoomType   string          `json:"room_type" example:"DELUXE"`
+	TotalRooms int             `json:"total_rooms" example:"20"`
+	Booked     int             `json:"booked" example:"21"`
+	Excess     int             `json:"excess" example:"1"`
+	Candidates []WalkCandidate `json:"candidates"`
+}
+
+// WalkListReport é o relatório de walk list entre from e to (inclusive)
+type WalkListReport struct {
+	From   string          `json:"from" example:"2026-12-28"`
+	To     string          `json:"to" example:"2027-01-02"`
+	Nights []WalkListNight `json:"nights"`
+}
@@ -0,0 +1,64 @@
+package model
+
+import "testing"
+
+func TestOverbookingAllowance(t *testing.T) {
+	tests := []struct {
+		rooms   int
+		percent float64
+		expect  int
+	}{
+		{20, 0, 0},
+		{20, 5, 1},
+		{20, 4.99, 0},
+		{20, 10, 2},
+		{19, 10, 1},
+		{10, 15, 1},
+		{3, 33.33, 0},
+		{3, 33.34, 1},
+		{7, 100, 7},
+		{0, 50, 0},
+		// percent é lido com duas casas, como a coluna NUMERIC(5,2)
+		{100, 0.1 + 0.2, 0},
+		{1000, 0.1 + 0.2, 3},
+	}
+	for _, tt := range tests {
+		if got := OverbookingAllowance(tt.rooms, tt.percent); got != tt.expect {
+			t.Errorf("OverbookingAllowance(%d, %v) = %d, expected %d", tt.rooms, tt.percent, got, tt.expect)
+		}
+	}
+}
+
+func TestOverbookingRuleValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		rule   OverbookingRule
+		fields []string
+	}{
+		{"valid", OverbookingRule{Name: "Réveillon", RoomType: "DELUXE", StartDate: "2026-12-28", EndDate: "2027-01-02", Percent: 5}, nil},
+		{"single night at the maximum", OverbookingRule{Name: "Show", RoomType: "SUITE", StartDate: "2026-12-31", EndDate: "2026-12-31", Percent: MaxOverbookingPercent}, nil},
+		{"percent out of range", OverbookingRule{Name: "Réveillon", RoomType: "DELUXE", StartDate: "2026-12-28", EndDate: "2027-01-02", Percent: 50.01}, []string{"percent"}},
+		{"end before start", OverbookingRule{Name: "Réveillon", RoomType: "DELUXE", StartDate: "2027-01-02", EndDate: "2026-12-28", Percent: 5}, []string{"end_date"}},
+		{"invalid values", OverbookingRule{RoomType: "PENTHOUSE", StartDate: "28/12/2026", EndDate: "2027-01-02"}, []string{"name", "room_type", "start_date", "percent"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.rule.Validate()
+			if tt.fields == nil {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				return
+			}
+			v, ok := err.(*ValidationError)
+			if !ok || len(v.Errors) != len(tt.fields) {
+				t.Fatalf("expected errors for %v, got %v", tt.fields, err)
+			}
+			for i, field := range tt.fields {
+				if v.Errors[i].Field != field {
+					t.Fatalf("expected errors for %v, got %+v", tt.fields, v.Errors)
+				}
+			}
+		})
+	}
+}
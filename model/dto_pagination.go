@@ -0,0 +1,205 @@
+package model
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	DefaultPageLimit = 50
+	MaxPageLimit     = 200
+)
+
+// Cursor marca a última linha entregue numa página (valor do campo de ordenação + ID)
+type Cursor struct {
+	Sort  string `json:"s"`
+	Value string `json:"v"`
+	ID    string `json:"id"`
+}
+
+// Encode serializa o cursor num token opaco para next_cursor
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor lê um token de next_cursor; token vazio retorna nil
+func DecodeCursor(token string) (*Cursor, error) {
+	if token == "" {
+		return nil, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, errors.New("invalid cursor")
+	}
+	var c Cursor
+	if err := json.Unmarshal(data, &c); err != nil || c.ID == "" {
+		return nil, errors.New("invalid cursor")
+	}
+	return &c, nil
+}
+
+// SortKind é o tipo do valor de um campo de ordenação, conferido no cursor antes de
+// o valor chegar à consulta
+type SortKind int
+
+const (
+	SortText SortKind = iota
+	SortInt
+	SortMoney
+	SortDate
+)
+
+// Check confere o cursor contra o tipo do campo de ordenação e reescreve valor e ID na
+// forma que SortValue e o banco produzem; um cursor forjado falha aqui e não na consulta
+func (c *Cursor) Check(kind SortKind) error {
+	id, err := uuid.Parse(c.ID)
+	if err != nil {
+		return errors.New("invalid cursor id")
+	}
+	c.ID = id.String()
+
+	switch kind {
+	case SortInt:
+		n, err := strconv.ParseInt(c.Value, 10, 32)
+		if err != nil {
+			return errors.New("invalid cursor value, must be an integer")
+		}
+		c.Value = strconv.FormatInt(n, 10)
+	case SortMoney:
+		amount, err := ParseMoney(c.Value)
+		if err != nil {
+			return errors.New("invalid cursor value, must be a decimal amount")
+		}
+		c.Value = amount.String()
+	case SortDate:
+		date, err := time.Parse("2006-01-02", c.Value)
+		if err != nil || date.Year() < 1 {
+			return errors.New("invalid cursor value, must be a date (YYYY-MM-DD)")
+		}
+	default:
+		// o Postgres não aceita NUL em text
+		if strings.ContainsRune(c.Value, 0) {
+			return errors.New("invalid cursor value")
+		}
+	}
+	return nil
+}
+
+// Sort é um campo de ordenação já validado contra a lista permitida
+type Sort struct {
+	Field string
+	Desc  bool
+}
+
+func (s Sort) String() string {
+	if s.Desc {
+		return "-" + s.Field
+	}
+	return s.Field
+}
+
+// ParseSort interpreta "campo" ou "-campo" aceitando apenas os campos permitidos
+func ParseSort(value, fallback string, allowed []string) (Sort, error) {
+	if value == "" {
+		value = fallback
+	}
+	sort := Sort{Field: strings.TrimPrefix(value, "-"), Desc: strings.HasPrefix(value, "-")}
+	for _, field := range allowed {
+		if sort.Field == field {
+			return sort, nil
+		}
+	}
+	return Sort{}, fmt.Errorf("invalid sort field %q, must be one of: %s (prefix with - for descending)", sort.Field, strings.Join(allowed, ", "))
+}
+
+// PageRequest reúne limite, cursor e ordenação de uma listagem
+type PageRequest struct {
+	Limit  int
+	Cursor *Cursor
+	Sort   Sort
+}
+
+// ---------------- ROOMS ----------------
+
+// RoomSortFields são os campos aceitos em sort na listagem de quartos
+var RoomSortFields = []string{"number", "type", "capacity", "price_per_night"}
+
+// RoomSortKinds é o tipo de cada campo de RoomSortFields
+var RoomSortKinds = map[string]SortKind{"number": SortInt, "type": SortText, "capacity": SortInt, "price_per_night": SortMoney}
+
+// RoomFilter são os filtros de GET /rooms
+type RoomFilter struct {
+	Status      string
+	Type        string
+	MinCapacity int
+	MaxCapacity int
+	MinPrice    Money
+	MaxPrice    Money
+	Page        PageRequest
+}
+
+// SortValue retorna o valor do campo de ordenação como texto, para montar o cursor
+func (r Room) SortValue(field string) string {
+	switch field {
+	case "type":
+		return r.Type
+	case "capacity":
+		return strconv.Itoa(r.Capacity)
+	case "price_per_night":
+		return r.PricePerNight.String()
+	default:
+		return strconv.Itoa(r.Number)
+	}
+}
+
+type RoomPage struct {
+	Data       []Room `json:"data"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// ---------------- RESERVATIONS ----------------
+
+// ReservationSortFields são os campos aceitos em sort na listagem de reservas
+var ReservationSortFields = []string{"checkin_expected", "checkout_expected", "guest_name", "total_amount"}
+
+// ReservationSortKinds é o tipo de cada campo de ReservationSortFields
+var ReservationSortKinds = map[string]SortKind{"checkin_expected": SortDate, "checkout_expected": SortDate, "guest_name": SortText, "total_amount": SortMoney}
+
+// ReservationFilter são os filtros de GET /reservation. From e To selecionam as
+// reservas cuja estadia toca o intervalo [From, To)
+type ReservationFilter struct {
+	RoomID          string
+	RoomType        string
+	Status          string
+	GuestNamePrefix string
+	From            string
+	To              string
+	Page            PageRequest
+}
+
+// SortValue retorna o valor do campo de ordenação como texto, para montar o cursor
+func (r Reservation) SortValue(field string) string {
+	switch field {
+	case "checkout_expected":
+		return r.CheckoutExpected
+	case "guest_name":
+		return r.GuestName
+	case "total_amount":
+		return r.TotalAmount.String()
+	default:
+		return r.CheckinExpected
+	}
+}
+
+type ReservationPage struct {
+	Data       []Reservation `json:"data"`
+	NextCursor string        `json:"next_cursor,omitempty"`
+}
@@ -0,0 +1,88 @@
+package model
+
+import "testing"
+
+func TestCursorRoundTrip(t *testing.T) {
+	cursor := Cursor{Sort: "-price_per_night", Value: "250.5", ID: "abc"}
+	got, err := DecodeCursor(cursor.Encode())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got == nil || *got != cursor {
+		t.Fatalf("expected %+v, got %+v", cursor, got)
+	}
+
+	if got, err := DecodeCursor(""); got != nil || err != nil {
+		t.Fatalf("expected no cursor for an empty token, got %+v, %v", got, err)
+	}
+	for _, token := range []string{"not base64!", "bm90IGpzb24", Cursor{Sort: "number", Value: "1"}.Encode()} {
+		if _, err := DecodeCursor(token); err == nil {
+			t.Fatalf("expected an error for token %q", token)
+		}
+	}
+}
+
+func TestCursorCheck(t *testing.T) {
+	id := "0F8FAD5B-D9CB-469F-A165-70867728950E"
+	tests := []struct {
+		name  string
+		kind  SortKind
+		value string
+		want  string
+		valid bool
+	}{
+		{"integer", SortInt, "+101", "101", true},
+		{"not an integer", SortInt, "abc", "", false},
+		{"integer out of range", SortInt, "99999999999", "", false},
+		{"money", SortMoney, "250.5", NewMoney(25050).String(), true},
+		{"money with exponent", SortMoney, "1e2", NewMoney(10000).String(), true},
+		{"not money", SortMoney, "cheap", "", false},
+		{"date", SortDate, "2026-06-10", "2026-06-10", true},
+		{"not a date", SortDate, "2026-13-01", "", false},
+		{"year zero", SortDate, "0000-01-01", "", false},
+		{"text", SortText, "O'Brien", "O'Brien", true},
+		{"text with NUL", SortText, "A\x00", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cursor := Cursor{Value: tt.value, ID: id}
+			err := cursor.Check(tt.kind)
+			if (err == nil) != tt.valid {
+				t.Fatalf("expected valid=%v, got %v", tt.valid, err)
+			}
+			if tt.valid && (cursor.Value != tt.want || cursor.ID != "0f8fad5b-d9cb-469f-a165-70867728950e") {
+				t.Fatalf("expected %q and a lowercase id, got %+v", tt.want, cursor)
+			}
+		})
+	}
+
+	cursor := Cursor{Value: "101", ID: "x"}
+	if err := cursor.Check(SortInt); err == nil {
+		t.Fatal("expected an error for an id that is not a UUID")
+	}
+}
+
+func TestParseSort(t *testing.T) {
+	tests := []struct {
+		value string
+		want  Sort
+		valid bool
+	}{
+		{"", Sort{Field: "number"}, true},
+		{"capacity", Sort{Field: "capacity"}, true},
+		{"-price_per_night", Sort{Field: "price_per_night", Desc: true}, true},
+		{"status", Sort{}, false},
+		{"--number", Sort{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseSort(tt.value, "number", RoomSortFields)
+			if (err == nil) != tt.valid {
+				t.Fatalf("expected valid=%v, got %v", tt.valid, err)
+			}
+			if got != tt.want {
+				t.Fatalf("expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
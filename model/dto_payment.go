@@ -0,0 +1,80 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// Status de uma autorização de cartão. DECLINED e FAILED (o gateway não respondeu) não
+// reservam nada no cartão; AUTHORIZED vira CAPTURED no check-out ou VOIDED quando a
+// reserva é cancelada ou marcada como NO_SHOW
+const (
+	AuthorizationAuthorized = "AUTHORIZED"
+	AuthorizationDeclined   = "DECLINED"
+	AuthorizationFailed     = "FAILED"
+	AuthorizationCaptured   = "CAPTURED"
+	AuthorizationVoided     = "VOIDED"
+)
+
+// PaymentAuthorization é a pré-autorização do depósito no cartão do hóspede. Reference é
+// o identificador da autorização no gateway e CaptureReference o da captura, contra a
+// qual os estornos são feitos
+type PaymentAuthorization struct {
+	ID               string    `json:"id"`
+	ReservationID    string    `json:"reservation_id"`
+	Gateway          string    `json:"gateway" example:"stub"`
+	Status           string    `json:"status" example:"AUTHORIZED"`
+	Amount           Money     `json:"amount"`
+	CapturedAmount   Money     `json:"captured_amount"`
+	RefundedAmount   Money     `json:"refunded_amount"`
+	Reference        string    `json:"reference,omitempty"`
+	CaptureReference string    `json:"capture_reference,omitempty"`
+	DeclineCode      string    `json:"decline_code,omitempty" example:"insufficient_funds"`
+	CreatedAt        time.Time `json:"created_at"`
+	CreatedBy        string    `json:"created_by"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// Refundable é o que ainda pode ser estornado da captura
+func (a *PaymentAuthorization) Refundable() Money {
+	return a.CapturedAmount.Sub(a.RefundedAmount)
+}
+
+// CardPaymentRequest é o cartão usado na pré-autorização do depósito. card_token vem da
+// tokenização feita pelo gateway no cliente; o número do cartão nunca passa pela API
+type CardPaymentRequest struct {
+	CardToken string `json:"card_token" binding:"required" example:"tok_visa"`
+}
+
+// Validate confere o tamanho do token
+func (r *CardPaymentRequest) Validate() error {
+	var v ValidationError
+	if r.CardToken == "" || len(r.CardToken) > 100 {
+		v.Add("card_token", "invalid_length", "must have 1 to 100 characters")
+	}
+	return v.Err()
+}
+
+// PaymentDeclinedError indica que o gateway recusou a operação no cartão. A reserva
+// continua como estava: uma autorização recusada pode ser refeita com outro cartão
+type PaymentDeclinedError struct {
+	ReservationID   string
+	AuthorizationID string
+	DeclineCode     string
+}
+
+func (e *PaymentDeclinedError) Error() string {
+	return fmt.Sprintf("payment for reservation %s was declined by the gateway (%s)", e.ReservationID, e.DeclineCode)
+}
+
+// PaymentTimeoutError indica que o gateway não respondeu e a operação pode ser refeita.
+// Uma pré-autorização sem resposta fica registrada como FAILED; numa captura ou estorno,
+// a autorização continua como estava
+type PaymentTimeoutError struct {
+	ReservationID   string
+	AuthorizationID string
+}
+
+func (e *PaymentTimeoutError) Error() string {
+	return fmt.Sprintf("payment gateway did not respond for reservation %s, try again", e.ReservationID)
+}
@@ -0,0 +1,30 @@
+package model
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCardPaymentRequestValidate(t *testing.T) {
+	tests := []struct {
+		token string
+		valid bool
+	}{
+		{"tok_visa", true},
+		{"", false},
+		{strings.Repeat("t", 101), false},
+	}
+	for _, tt := range tests {
+		req := CardPaymentRequest{CardToken: tt.token}
+		if err := req.Validate(); (err == nil) != tt.valid {
+			t.Fatalf("%q: expected valid %v, got %v", tt.token, tt.valid, err)
+		}
+	}
+}
+
+func TestPaymentAuthorizationRefundable(t *testing.T) {
+	auth := PaymentAuthorization{CapturedAmount: NewMoney(15000), RefundedAmount: NewMoney(4000)}
+	if !auth.Refundable().Equal(NewMoney(11000)) {
+		t.Fatalf("expected 110.00 left to refund, got %s", auth.Refundable())
+	}
+}
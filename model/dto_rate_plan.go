@@ -0,0 +1,138 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Weekdays são as chaves aceitas em weekday_multipliers e closed_to_arrival
+var Weekdays = []string{"sunday", "monday", "tuesday", "wednesday", "thursday", "friday", "saturday"}
+
+// RatePlan define o preço de um tipo de quarto num intervalo de datas (start_date e
+// end_date inclusivos). Quando vários planos cobrem a mesma noite vence o de maior priority.
+// A política de cancelamento do plano da noite de chegada tem precedência sobre a do quarto.
+type RatePlan struct {
+	ID                 string             `json:"id"`
+	Name               string             `json:"name"`
+	RoomType           string             `json:"room_type"`
+	StartDate          string             `json:"start_date"`
+	EndDate            string             `json:"end_date"`
+	BaseRate           Money              `json:"base_rate"`
+	WeekdayMultipliers map[string]float64 `json:"weekday_multipliers"`
+	MinStay            int                `json:"min_stay"`
+	ClosedToArrival    []string           `json:"closed_to_arrival"`
+	Priority           int                `json:"priority"`
+	// CancellationPolicyID é opcional; um plano não reembolsável aponta para uma política non_refundable
+	CancellationPolicyID string `json:"cancellation_policy_id,omitempty"`
+}
+
+type RatePlanRequest struct {
+	ID                 string             `json:"id"`
+	Name               string             `json:"name" binding:"required"`
+	RoomType           string             `json:"room_type" binding:"required"`
+	StartDate          string             `json:"start_date" binding:"required"`
+	EndDate            string             `json:"end_date" binding:"required"`
+	BaseRate           Money              `json:"base_rate"`
+	WeekdayMultipliers map[string]float64 `json:"weekday_multipliers"`
+	MinStay            int                `json:"min_stay"`
+	ClosedToArrival    []string           `json:"closed_to_arrival"`
+	Priority           int                `json:"priority"`
+	// CancellationPolicyID é opcional
+	CancellationPolicyID string `json:"cancellation_policy_id"`
+}
+
+func (r *RatePlanRequest) RatePlan() *RatePlan {
+	return &RatePlan{
+		ID:                 r.ID,
+		Name:               r.Name,
+		RoomType:           r.RoomType,
+		StartDate:          r.StartDate,
+		EndDate:            r.EndDate,
+		BaseRate:           r.BaseRate,
+		WeekdayMultipliers: r.WeekdayMultipliers,
+		MinStay:            r.MinStay,
+		ClosedToArrival:    r.ClosedToArrival,
+		Priority:           r.Priority,
+
+		CancellationPolicyID: r.CancellationPolicyID,
+	}
+}
+
+// Validate retorna um *ValidationError com todos os campos inválidos
+func (r *RatePlan) Validate() error {
+	var v ValidationError
+	if r.Name == "" {
+		v.Add("name", "required", "must not be empty")
+	}
+	switch r.RoomType {
+	case "STANDARD", "DELUXE", "SUITE":
+		break
+	default:
+		v.Add("room_type", "invalid_value", "must be one of: STANDARD, DELUXE, SUITE")
+	}
+	start, startErr := time.Parse("2006-01-02", r.StartDate)
+	end, endErr := time.Parse("2006-01-02", r.EndDate)
+	if startErr != nil {
+		v.Add("start_date", "invalid_format", "expected YYYY-MM-DD")
+	}
+	if endErr != nil {
+		v.Add("end_date", "invalid_format", "expected YYYY-MM-DD")
+	} else if startErr == nil && end.Before(start) {
+		v.Add("end_date", "out_of_range", "must not be before start_date")
+	}
+	if r.BaseRate.IsNegative() {
+		v.Add("base_rate", "out_of_range", "must not be negative")
+	}
+	if r.MinStay < 0 {
+		v.Add("min_stay", "out_of_range", "must not be negative")
+	}
+	for day, multiplier := range r.WeekdayMultipliers {
+		field := "weekday_multipliers." + day
+		if !isWeekday(day) {
+			v.Add(field, "invalid_value", "key must be one of: "+strings.Join(Weekdays, ", "))
+		}
+		if multiplier <= 0 {
+			v.Add(field, "out_of_range", "must be greater than 0")
+		}
+	}
+	for i, rule := range r.ClosedToArrival {
+		if _, err := time.Parse("2006-01-02", rule); err != nil && !isWeekday(rule) {
+			v.Add(fmt.Sprintf("closed_to_arrival[%d]", i), "invalid_value", "must be a weekday or a YYYY-MM-DD date")
+		}
+	}
+	return v.Err()
+}
+
+// Multiplier retorna o multiplicador do dia da semana, 1 quando não configurado
+func (r *RatePlan) Multiplier(day time.Weekday) float64 {
+	if m, ok := r.WeekdayMultipliers[Weekdays[day]]; ok {
+		return m
+	}
+	return 1
+}
+
+// IsClosedToArrival indica se o plano proíbe chegadas na data
+func (r *RatePlan) IsClosedToArrival(date time.Time) bool {
+	for _, rule := range r.ClosedToArrival {
+		if rule == Weekdays[date.Weekday()] || rule == date.Format("2006-01-02") {
+			return true
+		}
+	}
+	return false
+}
+
+// Covers indica se a noite está dentro do intervalo do plano
+func (r *RatePlan) Covers(night time.Time) bool {
+	date := night.Format("2006-01-02")
+	return date >= r.StartDate && date <= r.EndDate
+}
+
+func isWeekday(value string) bool {
+	for _, day := range Weekdays {
+		if value == day {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,66 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRatePlanValidate(t *testing.T) {
+	valid := func() RatePlan {
+		return RatePlan{Name: "June", RoomType: "STANDARD", StartDate: "2026-06-01", EndDate: "2026-06-30", BaseRate: NewMoney(20000),
+			WeekdayMultipliers: map[string]float64{"saturday": 1.5}, ClosedToArrival: []string{"sunday", "2026-06-12"}}
+	}
+	tests := []struct {
+		name   string
+		change func(p *RatePlan)
+		valid  bool
+	}{
+		{"valid", func(p *RatePlan) {}, true},
+		{"single day", func(p *RatePlan) { p.EndDate = p.StartDate }, true},
+		{"missing name", func(p *RatePlan) { p.Name = "" }, false},
+		{"unknown room type", func(p *RatePlan) { p.RoomType = "PENTHOUSE" }, false},
+		{"bad date", func(p *RatePlan) { p.StartDate = "01/06/2026" }, false},
+		{"end before start", func(p *RatePlan) { p.EndDate = "2026-05-31" }, false},
+		{"negative base rate", func(p *RatePlan) { p.BaseRate = NewMoney(-100) }, false},
+		{"negative min stay", func(p *RatePlan) { p.MinStay = -1 }, false},
+		{"unknown weekday", func(p *RatePlan) { p.WeekdayMultipliers = map[string]float64{"funday": 1.2} }, false},
+		{"zero multiplier", func(p *RatePlan) { p.WeekdayMultipliers = map[string]float64{"monday": 0} }, false},
+		{"bad closed to arrival", func(p *RatePlan) { p.ClosedToArrival = []string{"someday"} }, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := valid()
+			tt.change(&plan)
+			if err := plan.Validate(); (err == nil) != tt.valid {
+				t.Fatalf("expected valid=%v, got %v", tt.valid, err)
+			}
+		})
+	}
+}
+
+func TestRatePlanNightRules(t *testing.T) {
+	plan := RatePlan{StartDate: "2026-06-01", EndDate: "2026-06-30",
+		WeekdayMultipliers: map[string]float64{"saturday": 1.5}, ClosedToArrival: []string{"sunday", "2026-06-12"}}
+	day := func(value string) time.Time {
+		d, _ := time.Parse("2006-01-02", value)
+		return d
+	}
+
+	if m := plan.Multiplier(time.Saturday); m != 1.5 {
+		t.Errorf("expected 1.5 on saturday, got %v", m)
+	}
+	if m := plan.Multiplier(time.Monday); m != 1 {
+		t.Errorf("expected 1 on an unset weekday, got %v", m)
+	}
+
+	for date, closed := range map[string]bool{"2026-06-07": true, "2026-06-12": true, "2026-06-11": false} {
+		if got := plan.IsClosedToArrival(day(date)); got != closed {
+			t.Errorf("expected closed=%v on %s, got %v", closed, date, got)
+		}
+	}
+	for date, covered := range map[string]bool{"2026-05-31": false, "2026-06-01": true, "2026-06-30": true, "2026-07-01": false} {
+		if got := plan.Covers(day(date)); got != covered {
+			t.Errorf("expected covers=%v on %s, got %v", covered, date, got)
+		}
+	}
+}
@@ -1,34 +1,150 @@
 package model
 
-import (
-	"errors"
-	"net/http"
-)
+import "time"
 
+// Reservation reserva um quarto ou, só com room_type, um quarto qualquer do tipo. Nesse
+// caso room_id fica vazio até a atribuição, manual ou no check-in
 type Reservation struct {
-	ID               string  `json:"id"`
-	RoomID           string  `json:"room_id"`
-	GuestName        string  `json:"guest_name"`
-	CheckinExpected  string  `json:"checkin_expected"`
-	CheckoutExpected string  `json:"checkout_expected"`
-	Status           string  `json:"status"`
-	TotalAmount      float64 `json:"total_amount"`
+	ID               string        `json:"id"`
+	RoomID           string        `json:"room_id"`
+	RoomType         string        `json:"room_type"`
+	GuestID          string        `json:"guest_id"`
+	GuestName        string        `json:"guest_name"`
+	CheckinExpected  string        `json:"checkin_expected"`
+	CheckoutExpected string        `json:"checkout_expected"`
+	Status           string        `json:"status"`
+	TotalAmount      Money         `json:"total_amount"`
+	Nights           []NightlyRate `json:"nights,omitempty"`
+	// Taxes detalha os impostos da estadia. Os inclusive já estão no preço das noites;
+	// os demais somam ao total_amount
+	Taxes []TaxLine `json:"taxes,omitempty"`
+	// ConfirmedAt é quando a pré-autorização do depósito foi aprovada e a reserva passou
+	// de CREATED para CONFIRMED
+	ConfirmedAt  *time.Time `json:"confirmed_at,omitempty"`
+	CheckedInAt  *time.Time `json:"checked_in_at,omitempty"`
+	CheckedInBy  string     `json:"checked_in_by,omitempty"`
+	CheckedOutAt *time.Time `json:"checked_out_at,omitempty"`
+	CheckedOutBy string     `json:"checked_out_by,omitempty"`
+	CanceledAt   *time.Time `json:"canceled_at,omitempty"`
+	CanceledBy   string     `json:"canceled_by,omitempty"`
+	// CancellationReason é o motivo informado no cancelamento
+	CancellationReason string `json:"cancellation_reason,omitempty"`
+	// CancellationPolicyID é a política fixada na reserva, vinda do plano da noite de
+	// chegada ou do quarto
+	CancellationPolicyID string `json:"cancellation_policy_id,omitempty"`
+	// CancellationFee e RefundAmount são calculados no cancelamento
+	CancellationFee *Money `json:"cancellation_fee,omitempty"`
+	RefundAmount    *Money `json:"refund_amount,omitempty"`
+	// NoShowAt e NoShowFee são gravados quando o agendador marca a reserva como NO_SHOW;
+	// a multa vem da política de cancelamento e RefundAmount recebe o restante
+	NoShowAt  *time.Time `json:"no_show_at,omitempty"`
+	NoShowFee *Money     `json:"no_show_fee,omitempty"`
+	// OverstayFlaggedAt marca o hóspede que continua CHECKED_IN depois do horário de saída
+	OverstayFlaggedAt *time.Time `json:"overstay_flagged_at,omitempty"`
+	// GroupID é o grupo da reserva feita pela rooming list; as noites e os impostos vão
+	// para a conta master do grupo
+	GroupID string `json:"group_id,omitempty"`
+	// HoldID é o bloqueio convertido na criação, consumido na mesma transação; não é gravado
+	HoldID string `json:"-"`
 }
 
+// AlertOverstay é o único tipo de alerta por enquanto: hóspede que passou do horário de saída
+const AlertOverstay = "OVERSTAY"
+
+// ReservationAlert é uma reserva sinalizada pelo agendador que pede ação da recepção
+type ReservationAlert struct {
+	Type             string    `json:"type" example:"OVERSTAY"`
+	ReservationID    string    `json:"reservation_id"`
+	RoomID           string    `json:"room_id"`
+	GuestName        string    `json:"guest_name"`
+	CheckoutExpected string    `json:"checkout_expected"`
+	FlaggedAt        time.Time `json:"flagged_at"`
+}
+
+// StayMonitorRun resume uma execução do agendador de no-show e overstay
+type StayMonitorRun struct {
+	NoShows   int `json:"no_shows"`
+	Overstays int `json:"overstays"`
+}
+
+// CancelRequest é o corpo opcional do cancelamento
+type CancelRequest struct {
+	Reason string `json:"reason" example:"guest request"`
+}
+
+// StayActionRequest é o corpo de check-in e check-out: quem está executando a ação
+type StayActionRequest struct {
+	Operator string `json:"operator" binding:"required"`
+}
+
+// CheckInRequest é o corpo do check-in. RoomID escolhe o quarto de uma reserva ainda sem
+// quarto ou troca o atribuído; sem ele, a reserva sem quarto recebe um automaticamente
+type CheckInRequest struct {
+	Operator string `json:"operator" binding:"required"`
+	RoomID   string `json:"room_id"`
+}
+
+// RoomAssignmentRequest atribui ou troca o quarto de uma reserva que ainda espera o hóspede
+type RoomAssignmentRequest struct {
+	RoomID string `json:"room_id" binding:"required"`
+}
+
+// AutoAssignRequest delimita as chegadas, de From a To (inclusive), cujas reservas sem
+// quarto recebem um automaticamente; vazio é hoje
+type AutoAssignRequest struct {
+	From string `json:"from" example:"2025-01-10"`
+	To   string `json:"to" example:"2025-01-12"`
+}
+
+// RoomAssignment é um quarto atribuído pela atribuição automática
+type RoomAssignment struct {
+	ReservationID string `json:"reservation_id"`
+	RoomID        string `json:"room_id"`
+	RoomNumber    int    `json:"room_number"`
+}
+
+// RoomAssignmentRun resume uma atribuição automática; Unassigned são as reservas para as
+// quais nenhum quarto do tipo estava livre na estadia inteira
+type RoomAssignmentRun struct {
+	Assigned   []RoomAssignment `json:"assigned"`
+	Unassigned []string         `json:"unassigned"`
+}
+
+// NightlyRate é o preço cobrado por uma noite da estadia e o plano tarifário usado
+type NightlyRate struct {
+	Date       string `json:"date"`
+	Price      Money  `json:"price"`
+	RatePlanID string `json:"rate_plan_id,omitempty"`
+}
+
+// ReservationResponse é o corpo recebido na criação e atualização de reservas.
+// total_amount é calculado pelo servidor e qualquer valor enviado é sobrescrito.
+// Sem guest_id, um novo hóspede é cadastrado com o guest_name informado. Com payment, o
+// depósito é pré-autorizado no cartão e a reserva já nasce CONFIRMED. Com hold_token, o
+// bloqueio de POST /holds vira a reserva, que precisa ter o mesmo quarto e as mesmas datas.
+// Só com room_type, a reserva fica com um quarto qualquer do tipo, atribuído depois.
+// Sem status, a reserva nova nasce CREATED e a atualizada mantém o status atual.
 type ReservationResponse struct {
-	ID               string  `json:"id"`
-	RoomID           string  `json:"room_id" binding:"required"`
-	GuestName        string  `json:"guest_name" binding:"required"`
-	CheckinExpected  string  `json:"checkin_expected" binding:"required"`
-	CheckoutExpected string  `json:"checkout_expected" binding:"required"`
-	Status           string  `json:"status" binding:"required"`
-	TotalAmount      float64 `json:"total_amount" binding:"required,gt=0"`
+	ID               string `json:"id"`
+	RoomID           string `json:"room_id"`
+	RoomType         string `json:"room_type" example:"DELUXE"`
+	GuestID          string `json:"guest_id"`
+	GuestName        string `json:"guest_name"`
+	CheckinExpected  string `json:"checkin_expected" binding:"required"`
+	CheckoutExpected string `json:"checkout_expected" binding:"required"`
+	Status           string `json:"status" example:"CREATED"`
+	TotalAmount      Money  `json:"total_amount"`
+	// HoldToken só é lido na criação; Payment também no PUT que leva a reserva a CONFIRMED
+	Payment   *CardPaymentRequest `json:"payment,omitempty"`
+	HoldToken string              `json:"hold_token,omitempty"`
 }
 
 func (r *ReservationResponse) Reservation() *Reservation {
 	return &Reservation{
 		ID:               r.ID,
 		RoomID:           r.RoomID,
+		RoomType:         r.RoomType,
+		GuestID:          r.GuestID,
 		GuestName:        r.GuestName,
 		CheckinExpected:  r.CheckinExpected,
 		CheckoutExpected: r.CheckoutExpected,
@@ -37,24 +153,20 @@ func (r *ReservationResponse) Reservation() *Reservation {
 	}
 }
 
-func (r *Reservation) Validate() (int, error) {
-	if r.RoomID == "" {
-		return http.StatusBadRequest, errors.New("room_id is required")
+// Validate retorna um *ValidationError com todos os campos obrigatórios ausentes
+func (r *Reservation) Validate() error {
+	var v ValidationError
+	if r.RoomID == "" && r.RoomType == "" {
+		v.Add("room_id", "required", "room_id or room_type is required")
 	}
-	if r.GuestName == "" {
-		return http.StatusBadRequest, errors.New("guest_name is required")
+	if r.GuestID == "" && r.GuestName == "" {
+		v.Add("guest_id", "required", "guest_id or guest_name is required")
 	}
 	if r.CheckinExpected == "" {
-		return http.StatusBadRequest, errors.New("checkin_expected is required")
+		v.Add("checkin_expected", "required", "is required")
 	}
 	if r.CheckoutExpected == "" {
-		return http.StatusBadRequest, errors.New("checkout_expected is required")
-	}
-	if r.Status == "" {
-		return http.StatusBadRequest, errors.New("status is required")
-	}
-	if r.TotalAmount <= 0 {
-		return http.StatusBadRequest, errors.New("total_amount must be greater than 0")
+		v.Add("checkout_expected", "required", "is required")
 	}
-	return http.StatusOK, nil
+	return v.Err()
 }
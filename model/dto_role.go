@@ -0,0 +1,165 @@
+package model
+
+import (
+	"regexp"
+	"sort"
+)
+
+// Permissões verificadas pelos controllers. O formato é <recurso>:<ação>
+const (
+	PermRoomsRead        = "rooms:read"
+	PermRoomsCreate      = "rooms:create"
+	PermRoomsUpdate      = "rooms:update"
+	PermRoomsUpdatePrice = "rooms:update_price"
+	PermRoomsDelete      = "rooms:delete"
+
+	PermReservationsRead     = "reservations:read"
+	PermReservationsCreate   = "reservations:create"
+	PermReservationsUpdate   = "reservations:update"
+	PermReservationsDelete   = "reservations:delete"
+	PermReservationsCheckIn  = "reservations:check_in"
+	PermReservationsCheckOut = "reservations:check_out"
+	// PermFolioWrite permite lançar consumos, descontos, pagamentos e estornos na conta
+	PermFolioWrite = "folio:write"
+
+	PermGuestsRead     = "guests:read"
+	PermGuestsWrite    = "guests:write"
+	PermRatePlansRead  = "rate_plans:read"
+	PermRatePlansWrite = "rate_plans:write"
+
+	PermAuditRead = "audit:read"
+	// PermRecordsPurge permite apagar de vez quartos e reservas
+	PermRecordsPurge = "records:purge"
+	// PermNightAuditRun permite fechar o dia e consultar os fechamentos
+	PermNightAuditRun = "night_audit:run"
+	// PermReportsRead permite consultar os relatórios contábeis, como o de impostos
+	PermReportsRead = "reports:read"
+
+	PermUsersManage = "users:manage"
+	PermRolesManage = "roles:manage"
+)
+
+// Papéis padrão, criados na subida do servidor
+const (
+	RoleAdmin        = "admin"
+	RoleManager      = "manager"
+	RoleFrontDesk    = "front_desk"
+	RoleHousekeeping = "housekeeping"
+	RoleReadOnly     = "read_only"
+)
+
+// Permissions lista todas as permissões conhecidas, na ordem em que são exibidas
+var Permissions = []string{
+	PermRoomsRead, PermRoomsCreate, PermRoomsUpdate, PermRoomsUpdatePrice, PermRoomsDelete,
+	PermReservationsRead, PermReservationsCreate, PermReservationsUpdate, PermReservationsDelete,
+	PermReservationsCheckIn, PermReservationsCheckOut, PermFolioWrite,
+	PermGuestsRead, PermGuestsWrite, PermRatePlansRead, PermRatePlansWrite,
+	PermAuditRead, PermRecordsPurge, PermNightAuditRun, PermReportsRead, PermUsersManage, PermRolesManage,
+}
+
+// Role é um conjunto nomeado de permissões. Papéis padrão (BuiltIn) não podem ser
+// removidos e o admin sempre tem todas as permissões
+type Role struct {
+	Name        string   `json:"name" example:"front_desk"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"`
+	BuiltIn     bool     `json:"built_in"`
+}
+
+// DefaultRoles é a matriz de permissões inicial
+func DefaultRoles() []Role {
+	return []Role{
+		{
+			Name:        RoleAdmin,
+			Description: "Full access, including users and roles",
+			Permissions: append([]string(nil), Permissions...),
+		},
+		{
+			Name:        RoleManager,
+			Description: "Revenue and operations management: rooms, prices, rate plans and reservations",
+			Permissions: []string{
+				PermRoomsRead, PermRoomsCreate, PermRoomsUpdate, PermRoomsUpdatePrice, PermRoomsDelete,
+				PermReservationsRead, PermReservationsCreate, PermReservationsUpdate, PermReservationsDelete,
+				PermFolioWrite,
+				PermGuestsRead, PermGuestsWrite, PermRatePlansRead, PermRatePlansWrite,
+				PermAuditRead, PermNightAuditRun, PermReportsRead,
+			},
+		},
+		{
+			Name:        RoleFrontDesk,
+			Description: "Reception: bookings, check-in, check-out and guests",
+			Permissions: []string{
+				PermRoomsRead, PermRoomsUpdate,
+				PermReservationsRead, PermReservationsCreate, PermReservationsUpdate,
+				PermReservationsCheckIn, PermReservationsCheckOut, PermFolioWrite,
+				PermGuestsRead, PermGuestsWrite, PermRatePlansRead,
+			},
+		},
+		{
+			Name:        RoleHousekeeping,
+			Description: "Room status and the reservations that affect it",
+			Permissions: []string{PermRoomsRead, PermRoomsUpdate, PermReservationsRead},
+		},
+		{
+			Name:        RoleReadOnly,
+			Description: "Read access to every resource",
+			Permissions: []string{PermRoomsRead, PermReservationsRead, PermGuestsRead, PermRatePlansRead},
+		},
+	}
+}
+
+// Has indica se o papel concede a permissão
+func (r Role) Has(permission string) bool {
+	for _, p := range r.Permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+var roleNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{1,39}$`)
+
+type RoleRequest struct {
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions" binding:"required"`
+}
+
+// Role monta o papel com as permissões ordenadas e sem repetição
+func (r *RoleRequest) Role(name string) Role {
+	seen := make(map[string]bool)
+	var permissions []string
+	for _, p := range r.Permissions {
+		if !seen[p] {
+			seen[p] = true
+			permissions = append(permissions, p)
+		}
+	}
+	sort.Strings(permissions)
+	return Role{Name: name, Description: r.Description, Permissions: permissions}
+}
+
+// Validate retorna um *ValidationError com todos os campos inválidos
+func (r *Role) Validate() error {
+	var v ValidationError
+	if !roleNamePattern.MatchString(r.Name) {
+		v.Add("name", "invalid_format", "must be 2 to 40 lowercase letters, digits or underscores, starting with a letter")
+	}
+	if len(r.Description) > 200 {
+		v.Add("description", "invalid_length", "must have at most 200 characters")
+	}
+	known := make(map[string]bool, len(Permissions))
+	for _, p := range Permissions {
+		known[p] = true
+	}
+	for _, p := range r.Permissions {
+		if !known[p] {
+			v.Add("permissions", "invalid_value", "unknown permission "+p)
+		}
+	}
+	return v.Err()
+}
+
+type UserRolesRequest struct {
+	Roles []string `json:"roles" binding:"required"`
+}
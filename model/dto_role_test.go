@@ -0,0 +1,63 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRoleValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		role  Role
+		valid bool
+	}{
+		{"valid", Role{Name: "night_audit", Permissions: []string{PermReservationsRead}}, true},
+		{"no permissions", Role{Name: "nobody"}, true},
+		{"uppercase name", Role{Name: "Night"}, false},
+		{"name starting with digit", Role{Name: "1st_shift"}, false},
+		{"one letter", Role{Name: "a"}, false},
+		{"unknown permission", Role{Name: "night_audit", Permissions: []string{"rooms:burn"}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.role.Validate(); (err == nil) != tt.valid {
+				t.Fatalf("expected valid=%v, got %v", tt.valid, err)
+			}
+		})
+	}
+}
+
+func TestRoleRequestSortsAndDedupes(t *testing.T) {
+	req := RoleRequest{Description: "Night shift", Permissions: []string{PermRoomsRead, PermGuestsRead, PermRoomsRead}}
+	role := req.Role("night_audit")
+	if want := []string{PermGuestsRead, PermRoomsRead}; !reflect.DeepEqual(role.Permissions, want) {
+		t.Fatalf("expected %v, got %v", want, role.Permissions)
+	}
+	if role.Name != "night_audit" || role.BuiltIn {
+		t.Fatalf("unexpected role %+v", role)
+	}
+}
+
+func TestDefaultRoles(t *testing.T) {
+	roles := map[string]Role{}
+	for _, role := range DefaultRoles() {
+		if err := role.Validate(); err != nil {
+			t.Fatalf("default role %s is invalid: %v", role.Name, err)
+		}
+		roles[role.Name] = role
+	}
+	for _, p := range Permissions {
+		if !roles[RoleAdmin].Has(p) {
+			t.Fatalf("expected admin to have %s", p)
+		}
+	}
+	// só o admin administra usuários e papéis, e a recepção não muda preços
+	for _, name := range []string{RoleManager, RoleFrontDesk, RoleHousekeeping, RoleReadOnly} {
+		if roles[name].Has(PermUsersManage) || roles[name].Has(PermRolesManage) {
+			t.Fatalf("expected %s not to manage users or roles", name)
+		}
+	}
+	if roles[RoleFrontDesk].Has(PermRoomsUpdatePrice) || !roles[RoleFrontDesk].Has(PermReservationsCheckIn) {
+		t.Fatalf("unexpected front desk permissions %v", roles[RoleFrontDesk].Permissions)
+	}
+}
@@ -1,23 +1,31 @@
 package model
 
-import "fmt"
+import "time"
 
+// Room é um quarto. DeletedAt marca um quarto arquivado: ele some das listagens e da
+// disponibilidade, mas continua acessível pelo ID por causa do histórico de reservas.
+// CancellationPolicyID vale para as reservas cujo plano tarifário não define política
 type Room struct {
-	ID            string  `json:"id"`
-	Number        int     `json:"number"`
-	Type          string  `json:"type"`
-	Capacity      int     `json:"capacity"`
-	PricePerNight float64 `json:"price_per_night"`
-	Status        string  `json:"status"`
+	ID            string     `json:"id"`
+	Number        int        `json:"number"`
+	Type          string     `json:"type"`
+	Capacity      int        `json:"capacity"`
+	PricePerNight Money      `json:"price_per_night"`
+	Status        string     `json:"status"`
+	DeletedAt     *time.Time `json:"deleted_at,omitempty"`
+	// CancellationPolicyID é a política padrão do quarto; vazio cancela sem custo
+	CancellationPolicyID string `json:"cancellation_policy_id,omitempty"`
 }
 
 type RoomRequest struct {
-	ID            string  `json:"id"`
-	Number        int     `json:"number" binding:"required"`
-	Type          string  `json:"type" binding:"required"`
-	Capacity      int     `json:"capacity" binding:"required"`
-	PricePerNight float64 `json:"price_per_night" binding:"required,gt=0"`
-	Status        string  `json:"status" binding:"required"`
+	ID            string `json:"id"`
+	Number        int    `json:"number" binding:"required"`
+	Type          string `json:"type" binding:"required"`
+	Capacity      int    `json:"capacity" binding:"required"`
+	PricePerNight Money  `json:"price_per_night" binding:"required"`
+	Status        string `json:"status" binding:"required"`
+	// CancellationPolicyID é opcional
+	CancellationPolicyID string `json:"cancellation_policy_id"`
 }
 
 func (r *RoomRequest) Room() *Room {
@@ -28,38 +36,43 @@ func (r *RoomRequest) Room() *Room {
 		Capacity:      r.Capacity,
 		PricePerNight: r.PricePerNight,
 		Status:        r.Status,
+
+		CancellationPolicyID: r.CancellationPolicyID,
 	}
 }
 
+// Validate retorna um *ValidationError com todos os campos inválidos
 func (r *Room) Validate() error {
-
-	var errs []error
+	var v ValidationError
 	if r.Number <= 0 {
-		errs = append(errs, fmt.Errorf("invalid number must be greater than 0"))
+		v.Add("number", "out_of_range", "must be greater than 0")
 	}
 	if r.Capacity <= 0 {
-		errs = append(errs, fmt.Errorf("invalid capacity must be greater than 0"))
+		v.Add("capacity", "out_of_range", "must be greater than 0")
 	}
-	if r.PricePerNight <= 0 {
-		errs = append(errs, fmt.Errorf("invalid price must be greater than 0"))
+	if !r.PricePerNight.IsPositive() {
+		v.Add("price_per_night", "out_of_range", "must be greater than 0")
 	}
 
-	switch r.Type {
-	case "STANDARD", "DELUXE", "SUITE":
-		break
-	default:
-		errs = append(errs, fmt.Errorf("invalid type field, must be one of: STANDARD, DELUXE, SUITE"))
+	if !ValidRoomType(r.Type) {
+		v.Add("type", "invalid_value", "must be one of: STANDARD, DELUXE, SUITE")
 	}
 
 	switch r.Status {
 	case "ATIVO", "INATIVO":
 		break
 	default:
-		errs = append(errs, fmt.Errorf("invalid status field, must be one of: ATIVO, INATIVO"))
+		v.Add("status", "invalid_value", "must be one of: ATIVO, INATIVO")
 	}
 
-	if len(errs) > 0 {
-		return fmt.Errorf("validation errors: %v", errs)
+	return v.Err()
+}
+
+// ValidRoomType indica se t é um dos tipos de quarto do hotel
+func ValidRoomType(t string) bool {
+	switch t {
+	case "STANDARD", "DELUXE", "SUITE":
+		return true
 	}
-	return nil
+	return false
 }
@@ -0,0 +1,197 @@
+package model
+
+import "sort"
+
+// Formas de cálculo de um imposto
+const (
+	TaxPercent = "PERCENT"
+	TaxFixed   = "FIXED"
+)
+
+// Bases de um imposto: PER_NIGHT incide em cada noite e PER_STAY uma vez na estadia
+const (
+	TaxPerNight = "PER_NIGHT"
+	TaxPerStay  = "PER_STAY"
+)
+
+// Agrupamentos aceitos no relatório de impostos
+const (
+	TaxGroupDay   = "day"
+	TaxGroupMonth = "month"
+)
+
+// TaxRule é um imposto ou taxa da propriedade, como ISS, taxa de turismo ou taxa de
+// serviço. PERCENT cobra percent% do preço das noites e FIXED cobra amount por noite ou
+// por estadia. Um imposto inclusive já está dentro do preço do quarto e só aparece no
+// detalhamento; os demais somam ao total da reserva. Hóspedes dos tipos em
+// exempt_guest_types não pagam o imposto
+type TaxRule struct {
+	ID               string   `json:"id"`
+	Code             string   `json:"code" example:"ISS"`
+	Name             string   `json:"name" example:"Imposto Sobre Serviços"`
+	Calculation      string   `json:"calculation" example:"PERCENT"`
+	Percent          float64  `json:"percent" example:"5"`
+	Amount           Money    `json:"amount"`
+	Basis            string   `json:"basis" example:"PER_NIGHT"`
+	Inclusive        bool     `json:"inclusive"`
+	ExemptGuestTypes []string `json:"exempt_guest_types"`
+	Active           bool     `json:"active"`
+}
+
+type TaxRuleRequest struct {
+	ID               string   `json:"id"`
+	Code             string   `json:"code" binding:"required" example:"ISS"`
+	Name             string   `json:"name" binding:"required" example:"Imposto Sobre Serviços"`
+	Calculation      string   `json:"calculation" example:"PERCENT"`
+	Percent          float64  `json:"percent" example:"5"`
+	Amount           Money    `json:"amount"`
+	Basis            string   `json:"basis" example:"PER_NIGHT"`
+	Inclusive        bool     `json:"inclusive"`
+	ExemptGuestTypes []string `json:"exempt_guest_types"`
+	// Active é true quando omitido
+	Active *bool `json:"active"`
+}
+
+func (r *TaxRuleRequest) TaxRule() *TaxRule {
+	rule := &TaxRule{
+		ID:               r.ID,
+		Code:             r.Code,
+		Name:             r.Name,
+		Calculation:      r.Calculation,
+		Percent:          r.Percent,
+		Amount:           r.Amount,
+		Basis:            r.Basis,
+		Inclusive:        r.Inclusive,
+		ExemptGuestTypes: r.ExemptGuestTypes,
+		Active:           r.Active == nil || *r.Active,
+	}
+	if rule.Calculation == "" {
+		rule.Calculation = TaxPercent
+	}
+	if rule.Basis == "" {
+		rule.Basis = TaxPerNight
+	}
+	if rule.ExemptGuestTypes == nil {
+		rule.ExemptGuestTypes = []string{}
+	}
+	return rule
+}
+
+// Validate retorna um *ValidationError com todos os campos inválidos
+func (t *TaxRule) Validate() error {
+	var v ValidationError
+	if t.Code == "" || len(t.Code) > 20 {
+		v.Add("code", "invalid_length", "must have 1 to 20 characters")
+	}
+	if t.Name == "" || len(t.Name) > 120 {
+		v.Add("name", "invalid_length", "must have 1 to 120 characters")
+	}
+	switch t.Calculation {
+	case TaxPercent:
+		if t.Percent <= 0 || t.Percent > 100 {
+			v.Add("percent", "out_of_range", "must be greater than 0 and at most 100 for PERCENT")
+		}
+		if !t.Amount.IsZero() {
+			v.Add("amount", "not_allowed", "must be omitted for PERCENT")
+		}
+	case TaxFixed:
+		if !t.Amount.IsPositive() {
+			v.Add("amount", "out_of_range", "must be greater than 0 for FIXED")
+		}
+		if t.Percent != 0 {
+			v.Add("percent", "not_allowed", "must be omitted for FIXED")
+		}
+		if t.Inclusive {
+			v.Add("inclusive", "not_allowed", "only PERCENT taxes can be included in the room price")
+		}
+	default:
+		v.Add("calculation", "invalid_value", "must be one of: PERCENT, FIXED")
+	}
+	if t.Basis != TaxPerNight && t.Basis != TaxPerStay {
+		v.Add("basis", "invalid_value", "must be one of: PER_NIGHT, PER_STAY")
+	}
+	for _, guestType := range t.ExemptGuestTypes {
+		if !contains(GuestTypes, guestType) {
+			v.Add("exempt_guest_types", "invalid_value", "must contain only: INDIVIDUAL, CORPORATE, GOVERNMENT, DIPLOMAT")
+			break
+		}
+	}
+	return v.Err()
+}
+
+// Exempts diz se o tipo de hóspede é isento do imposto; sem tipo, o hóspede é INDIVIDUAL
+func (t *TaxRule) Exempts(guestType string) bool {
+	if guestType == "" {
+		guestType = GuestIndividual
+	}
+	return contains(t.ExemptGuestTypes, guestType)
+}
+
+// TaxLine é o imposto de uma regra sobre uma noite da reserva. Os impostos PER_STAY têm
+// uma única linha, com a data da noite de chegada
+type TaxLine struct {
+	TaxRuleID string `json:"tax_rule_id"`
+	Code      string `json:"code" example:"ISS"`
+	Name      string `json:"name"`
+	Date      string `json:"date" example:"2026-10-16"`
+	Amount    Money  `json:"amount"`
+	// Inclusive indica que o valor já está no preço da noite e não soma ao total
+	Inclusive bool `json:"inclusive"`
+}
+
+// TaxSummary é o total de um imposto na reserva ou na conta
+type TaxSummary struct {
+	Code      string `json:"code" example:"ISS"`
+	Name      string `json:"name"`
+	Inclusive bool   `json:"inclusive"`
+	Amount    Money  `json:"amount"`
+}
+
+// SummarizeTaxes soma as linhas por código e inclusive, ordenadas por código
+func SummarizeTaxes(lines []TaxLine) []TaxSummary {
+	var summary []TaxSummary
+	type key struct {
+		code      string
+		inclusive bool
+	}
+	index := map[key]int{}
+	for _, line := range lines {
+		k := key{line.Code, line.Inclusive}
+		i, ok := index[k]
+		if !ok {
+			i = len(summary)
+			index[k] = i
+			summary = append(summary, TaxSummary{Code: line.Code, Name: line.Name, Inclusive: line.Inclusive, Amount: NewMoney(0)})
+		}
+		summary[i].Amount = summary[i].Amount.Add(line.Amount)
+	}
+	sort.Slice(summary, func(i, j int) bool {
+		if summary[i].Code != summary[j].Code {
+			return summary[i].Code < summary[j].Code
+		}
+		return !summary[i].Inclusive && summary[j].Inclusive
+	})
+	return summary
+}
+
+// TaxReport soma os impostos das noites lançadas pelo night audit entre From e To
+// (inclusive), por período e código, para a contabilidade
+type TaxReport struct {
+	From     string          `json:"from" example:"2026-10-01"`
+	To       string          `json:"to" example:"2026-10-31"`
+	Currency string          `json:"currency" example:"BRL"`
+	GroupBy  string          `json:"group_by,omitempty" example:"month"`
+	Lines    []TaxReportLine `json:"lines"`
+	// Total soma apenas os impostos cobrados à parte; os inclusive já estão na receita de diárias
+	Total          Money `json:"total"`
+	TotalInclusive Money `json:"total_inclusive"`
+}
+
+// TaxReportLine é o total de um imposto num período; Period fica vazio sem group_by
+type TaxReportLine struct {
+	Period    string `json:"period,omitempty" example:"2026-10"`
+	Code      string `json:"code" example:"ISS"`
+	Name      string `json:"name"`
+	Inclusive bool   `json:"inclusive"`
+	Amount    Money  `json:"amount"`
+}
@@ -0,0 +1,70 @@
+package model
+
+import "testing"
+
+func TestTaxRuleValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		rule   TaxRule
+		fields []string
+	}{
+		{"percent", TaxRule{Code: "ISS", Name: "ISS", Calculation: TaxPercent, Percent: 5, Basis: TaxPerNight, Inclusive: true}, nil},
+		{"fixed per stay", TaxRule{Code: "TOUR", Name: "Turismo", Calculation: TaxFixed, Amount: NewMoney(500), Basis: TaxPerStay}, nil},
+		{"percent out of range", TaxRule{Code: "ISS", Name: "ISS", Calculation: TaxPercent, Percent: 101, Basis: TaxPerNight}, []string{"percent"}},
+		{"percent with amount", TaxRule{Code: "ISS", Name: "ISS", Calculation: TaxPercent, Percent: 5, Amount: NewMoney(100), Basis: TaxPerNight}, []string{"amount"}},
+		{"fixed inclusive", TaxRule{Code: "TOUR", Name: "Turismo", Calculation: TaxFixed, Amount: NewMoney(500), Basis: TaxPerStay, Inclusive: true}, []string{"inclusive"}},
+		{"fixed without amount", TaxRule{Code: "TOUR", Name: "Turismo", Calculation: TaxFixed, Percent: 5, Basis: TaxPerStay}, []string{"amount", "percent"}},
+		{"unknown values", TaxRule{Code: "", Name: "X", Calculation: "TABLE", Basis: "PER_ROOM", ExemptGuestTypes: []string{"VIP"}},
+			[]string{"code", "calculation", "basis", "exempt_guest_types"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.rule.Validate()
+			if tt.fields == nil {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				return
+			}
+			v, ok := err.(*ValidationError)
+			if !ok || len(v.Errors) != len(tt.fields) {
+				t.Fatalf("expected errors for %v, got %v", tt.fields, err)
+			}
+			for i, field := range tt.fields {
+				if v.Errors[i].Field != field {
+					t.Fatalf("expected errors for %v, got %+v", tt.fields, v.Errors)
+				}
+			}
+		})
+	}
+}
+
+func TestTaxRuleRequestDefaults(t *testing.T) {
+	rule := (&TaxRuleRequest{Code: "ISS", Name: "ISS", Percent: 5}).TaxRule()
+	if rule.Calculation != TaxPercent || rule.Basis != TaxPerNight || !rule.Active || rule.ExemptGuestTypes == nil {
+		t.Fatalf("expected PERCENT, PER_NIGHT, active and no exemptions, got %+v", rule)
+	}
+	inactive := false
+	if rule := (&TaxRuleRequest{Code: "ISS", Name: "ISS", Active: &inactive}).TaxRule(); rule.Active {
+		t.Fatal("expected an inactive rule")
+	}
+	// sem tipo, o hóspede é INDIVIDUAL
+	rule.ExemptGuestTypes = []string{GuestIndividual}
+	if !rule.Exempts("") || rule.Exempts(GuestCorporate) {
+		t.Fatalf("expected only INDIVIDUAL guests to be exempt, got %+v", rule)
+	}
+}
+
+func TestSummarizeTaxes(t *testing.T) {
+	lines := []TaxLine{
+		{Code: "SERV", Date: "2026-06-10", Amount: NewMoney(1000)},
+		{Code: "ISS", Date: "2026-06-10", Amount: NewMoney(500), Inclusive: true},
+		{Code: "SERV", Date: "2026-06-11", Amount: NewMoney(1000)},
+		{Code: "ISS", Date: "2026-06-11", Amount: NewMoney(500), Inclusive: true},
+	}
+	summary := SummarizeTaxes(lines)
+	if len(summary) != 2 || summary[0].Code != "ISS" || summary[0].Amount.Minor() != 1000 || !summary[0].Inclusive ||
+		summary[1].Code != "SERV" || summary[1].Amount.Minor() != 2000 {
+		t.Fatalf("expected ISS 10.00 and SERV 20.00, got %+v", summary)
+	}
+}
@@ -0,0 +1,42 @@
+package service
+
+import (
+	"hotel-soa/dao"
+	"hotel-soa/model"
+)
+
+type AuditService interface {
+	List(entity, entityID string) ([]model.AuditEntry, error)
+}
+
+type auditService struct {
+	audit dao.AuditRepository
+}
+
+func NewAuditService(audit dao.AuditRepository) AuditService {
+	return &auditService{audit: audit}
+}
+
+// List retorna o histórico de uma entidade, do registro mais antigo ao mais novo
+func (s *auditService) List(entity, entityID string) ([]model.AuditEntry, error) {
+	var v model.ValidationError
+	switch entity {
+	case model.AuditEntityRoom, model.AuditEntityReservation, model.AuditEntityGroup:
+	case "":
+		v.Add("entity", "required", "is required")
+	default:
+		v.Add("entity", "invalid_value", "must be one of: room, reservation, group")
+	}
+	if entityID == "" {
+		v.Add("id", "required", "is required")
+	}
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	entries, err := s.audit.GetAuditLog(entity, entityID)
+	if entries == nil {
+		entries = []model.AuditEntry{}
+	}
+	return entries, err
+}
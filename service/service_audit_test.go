@@ -0,0 +1,31 @@
+package service
+
+import (
+	"testing"
+
+	"hotel-soa/dao"
+	"hotel-soa/model"
+)
+
+func TestAuditServiceList(t *testing.T) {
+	repos := dao.NewMemoryRepositories()
+	audit := NewAuditService(repos.Audit)
+	roomID, err := NewRoomService(repos.Rooms, repos.Policies).Create(model.Room{Number: 101, Type: "STANDARD", Capacity: 2, PricePerNight: model.NewMoney(10000), Status: "ATIVO"}, testActor)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := audit.List(model.AuditEntityRoom, roomID)
+	if err != nil || len(entries) != 1 || entries[0].Actor != testActor.Username {
+		t.Fatalf("expected the create entry, got %+v, %v", entries, err)
+	}
+	// entidade sem histórico volta lista vazia, não nil
+	if entries, err := audit.List(model.AuditEntityReservation, roomID); err != nil || entries == nil || len(entries) != 0 {
+		t.Fatalf("expected an empty list, got %+v, %v", entries, err)
+	}
+	for _, query := range [][2]string{{"", roomID}, {"guest", roomID}, {model.AuditEntityRoom, ""}} {
+		if _, err := audit.List(query[0], query[1]); !isValidationError(err) {
+			t.Fatalf("expected a validation error for %v, got %v", query, err)
+		}
+	}
+}
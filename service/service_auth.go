@@ -0,0 +1,320 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"hotel-soa/dao"
+	"hotel-soa/model"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// apiKeyPrefix identifica as chaves da API; o prefixo exibido nas listagens
+// inclui os primeiros caracteres aleatórios
+const (
+	apiKeyPrefix       = "hk_"
+	apiKeyPrefixLength = len(apiKeyPrefix) + 8
+)
+
+// dummyPasswordHash é o hash bcrypt, com o custo padrão, de uma senha aleatória descartada.
+// O login de um username desconhecido compara a senha com ele, para levar o mesmo tempo
+// de um username existente e não revelar quais usernames existem
+const dummyPasswordHash = "$2a$10$nveOvz2U09qR5UnlX1ENcuXJEzo6/2tMVXU7MtQtEPggVb2LXvRhG"
+
+type AuthService interface {
+	Login(username, password string) (model.TokenPair, error)
+	Refresh(refreshToken string) (model.TokenPair, error)
+	// Logout invalida os refresh tokens já emitidos para o usuário; os access tokens
+	// valem até expirar
+	Logout(userID string) error
+	// ChangePassword confere a senha atual, grava a nova e invalida os refresh tokens
+	ChangePassword(userID string, req model.PasswordChangeRequest) error
+	Authenticate(accessToken string) (model.Principal, error)
+	AuthenticateAPIKey(key string) (model.Principal, error)
+	CreateUser(req model.UserRequest) (model.User, error)
+	ListUsers() ([]model.User, error)
+	EnsureAdmin(username, password string) error
+	CreateAPIKey(userID, name string) (model.APIKeyCreated, error)
+	ListAPIKeys(userID string) ([]model.APIKey, error)
+	RevokeAPIKey(userID, id string) error
+}
+
+type authService struct {
+	users   dao.UserRepository
+	apiKeys dao.APIKeyRepository
+	roles   dao.RoleRepository
+	tokens  *tokenSigner
+	now     func() time.Time
+}
+
+// NewAuthService cria o serviço de autenticação. Sem secret, uma chave aleatória é
+// gerada e os tokens deixam de valer quando o processo reinicia
+func NewAuthService(users dao.UserRepository, apiKeys dao.APIKeyRepository, roles dao.RoleRepository, secret string, accessTTL, refreshTTL time.Duration) AuthService {
+	if secret == "" {
+		secret = randomHex(32)
+	}
+	return &authService{
+		users:   users,
+		apiKeys: apiKeys,
+		roles:   roles,
+		tokens:  &tokenSigner{secret: []byte(secret), accessTTL: accessTTL, refreshTTL: refreshTTL},
+		now:     time.Now,
+	}
+}
+
+// ---------------- TOKENS ----------------
+
+// Login confere a senha e emite access e refresh tokens
+func (s *authService) Login(username, password string) (model.TokenPair, error) {
+	user, err := s.users.GetUserByUsername(username)
+	if err != nil {
+		return model.TokenPair{}, err
+	}
+	hash := user.PasswordHash
+	if user.ID == "" {
+		hash = dummyPasswordHash
+	}
+	if bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) != nil || user.ID == "" {
+		return model.TokenPair{}, unauthorized("invalid_credentials", "invalid username or password")
+	}
+	return s.issue(user)
+}
+
+// Refresh troca um refresh token válido por um novo par de tokens. O token emitido antes
+// de uma troca de senha ou de um logout carrega uma versão antiga e é recusado
+func (s *authService) Refresh(refreshToken string) (model.TokenPair, error) {
+	claims, err := s.tokens.parse(refreshToken, refreshTokenType, s.now())
+	if err != nil {
+		return model.TokenPair{}, err
+	}
+	user, err := s.users.GetUserByID(claims.Subject)
+	if err != nil {
+		return model.TokenPair{}, err
+	}
+	if user.ID == "" {
+		return model.TokenPair{}, unauthorized("invalid_token", "user no longer exists")
+	}
+	if claims.Version != user.TokenVersion {
+		return model.TokenPair{}, unauthorized("token_revoked", "refresh token was revoked")
+	}
+	return s.issue(user)
+}
+
+func (s *authService) Logout(userID string) error {
+	found, err := s.users.RevokeTokens(userID)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return notFound("user_not_found", "user %s not found", userID)
+	}
+	return nil
+}
+
+func (s *authService) ChangePassword(userID string, req model.PasswordChangeRequest) error {
+	if err := req.Validate(); err != nil {
+		return err
+	}
+	user, err := s.users.GetUserByID(userID)
+	if err != nil {
+		return err
+	}
+	if user.ID == "" {
+		return notFound("user_not_found", "user %s not found", userID)
+	}
+	if bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.CurrentPassword)) != nil {
+		return unauthorized("invalid_credentials", "current password is incorrect")
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	found, err := s.users.UpdatePassword(userID, string(hash))
+	if err != nil {
+		return err
+	}
+	if !found {
+		return notFound("user_not_found", "user %s not found", userID)
+	}
+	return nil
+}
+
+// Authenticate valida um access token. Os papéis são lidos do banco a cada requisição,
+// então uma mudança de papel vale sem esperar o token expirar
+func (s *authService) Authenticate(accessToken string) (model.Principal, error) {
+	claims, err := s.tokens.parse(accessToken, accessTokenType, s.now())
+	if err != nil {
+		return model.Principal{}, err
+	}
+	return s.principal(claims.Subject, claims.Username, "jwt")
+}
+
+func (s *authService) principal(userID, username, method string) (model.Principal, error) {
+	roles, permissions, err := userAccess(s.roles, userID)
+	if err != nil {
+		return model.Principal{}, err
+	}
+	return model.Principal{UserID: userID, Username: username, Method: method, Roles: roles, Permissions: permissions}, nil
+}
+
+func (s *authService) issue(user model.User) (model.TokenPair, error) {
+	now := s.now()
+	access, err := s.tokens.sign(tokenClaims{
+		Subject: user.ID, Username: user.Username, Type: accessTokenType,
+		IssuedAt: now.Unix(), ExpiresAt: now.Add(s.tokens.accessTTL).Unix(), ID: randomHex(16),
+	})
+	if err != nil {
+		return model.TokenPair{}, err
+	}
+	refresh, err := s.tokens.sign(tokenClaims{
+		Subject: user.ID, Username: user.Username, Type: refreshTokenType,
+		IssuedAt: now.Unix(), ExpiresAt: now.Add(s.tokens.refreshTTL).Unix(), ID: randomHex(16),
+		Version: user.TokenVersion,
+	})
+	if err != nil {
+		return model.TokenPair{}, err
+	}
+	return model.TokenPair{
+		AccessToken:  access,
+		RefreshToken: refresh,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(s.tokens.accessTTL.Seconds()),
+	}, nil
+}
+
+// ---------------- USERS ----------------
+
+// CreateUser cadastra o usuário com os papéis pedidos, ou read_only quando não há nenhum
+func (s *authService) CreateUser(req model.UserRequest) (model.User, error) {
+	if err := req.Validate(); err != nil {
+		return model.User{}, err
+	}
+	if len(req.Roles) == 0 {
+		req.Roles = []string{model.RoleReadOnly}
+	}
+	roles, err := checkRoles(s.roles, req.Roles)
+	if err != nil {
+		return model.User{}, err
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return model.User{}, err
+	}
+
+	user := model.User{Username: req.Username, PasswordHash: string(hash), CreatedAt: s.now().UTC()}
+	user.ID, err = s.users.InsertUser(user)
+	if err != nil {
+		if errors.Is(err, dao.ErrDuplicateUsername) {
+			return model.User{}, conflict("duplicate_username", "%s", err.Error())
+		}
+		return model.User{}, err
+	}
+	if err := s.roles.SetUserRoles(user.ID, roles); err != nil {
+		return model.User{}, err
+	}
+	user.Roles = roles
+	return user, nil
+}
+
+func (s *authService) ListUsers() ([]model.User, error) {
+	users, err := s.users.GetAllUsers()
+	if err != nil {
+		return nil, err
+	}
+	for i := range users {
+		users[i].Roles, _, err = userAccess(s.roles, users[i].ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if users == nil {
+		users = []model.User{}
+	}
+	return users, nil
+}
+
+// EnsureAdmin cria o usuário com o papel admin se o username ainda não existir. Um
+// usuário que já existe fica como está, inclusive os papéis que um admin lhe deu
+func (s *authService) EnsureAdmin(username, password string) error {
+	user, err := s.users.GetUserByUsername(username)
+	if err != nil || user.ID != "" {
+		return err
+	}
+	_, err = s.CreateUser(model.UserRequest{Username: username, Password: password, Roles: []string{model.RoleAdmin}})
+	return err
+}
+
+// ---------------- API KEYS ----------------
+
+// CreateAPIKey gera uma chave nova; só o hash é guardado
+func (s *authService) CreateAPIKey(userID, name string) (model.APIKeyCreated, error) {
+	if name == "" || len(name) > 120 {
+		var v model.ValidationError
+		v.Add("name", "invalid_length", "must have between 1 and 120 characters")
+		return model.APIKeyCreated{}, v.Err()
+	}
+
+	secret := apiKeyPrefix + randomHex(24)
+	key := model.APIKey{
+		UserID:    userID,
+		Name:      name,
+		Prefix:    secret[:apiKeyPrefixLength],
+		KeyHash:   hashAPIKey(secret),
+		CreatedAt: s.now().UTC(),
+	}
+	id, err := s.apiKeys.InsertAPIKey(key)
+	if err != nil {
+		return model.APIKeyCreated{}, err
+	}
+	key.ID = id
+	return model.APIKeyCreated{APIKey: key, Key: secret}, nil
+}
+
+func (s *authService) ListAPIKeys(userID string) ([]model.APIKey, error) {
+	keys, err := s.apiKeys.GetAPIKeysByUser(userID)
+	if keys == nil {
+		keys = []model.APIKey{}
+	}
+	return keys, err
+}
+
+func (s *authService) RevokeAPIKey(userID, id string) error {
+	revoked, err := s.apiKeys.RevokeAPIKey(id, userID, s.now().UTC())
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return notFound("api_key_not_found", "active api key %s not found", id)
+	}
+	return nil
+}
+
+// AuthenticateAPIKey resolve a chave para o usuário dono e registra o uso
+func (s *authService) AuthenticateAPIKey(secret string) (model.Principal, error) {
+	key, err := s.apiKeys.GetAPIKeyByHash(hashAPIKey(secret))
+	if err != nil {
+		return model.Principal{}, err
+	}
+	if key.ID == "" || key.RevokedAt != nil {
+		return model.Principal{}, unauthorized("invalid_api_key", "invalid or revoked api key")
+	}
+	user, err := s.users.GetUserByID(key.UserID)
+	if err != nil {
+		return model.Principal{}, err
+	}
+	if user.ID == "" {
+		return model.Principal{}, unauthorized("invalid_api_key", "invalid or revoked api key")
+	}
+	if err := s.apiKeys.TouchAPIKey(key.ID, s.now().UTC()); err != nil {
+		return model.Principal{}, err
+	}
+	return s.principal(user.ID, user.Username, "api_key")
+}
+
+// hashAPIKey usa SHA-256: as chaves têm entropia alta e a busca é pelo hash
+func hashAPIKey(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
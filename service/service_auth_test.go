@@ -0,0 +1,238 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"hotel-soa/dao"
+	"hotel-soa/model"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// newAuthTestService monta o serviço de autenticação com os papéis padrão e o relógio
+// controlado por now
+func newAuthTestService(t *testing.T, repos dao.Repositories, now *time.Time) *authService {
+	t.Helper()
+	if err := NewRoleService(repos.Roles, repos.Users).EnsureDefaults(); err != nil {
+		t.Fatal(err)
+	}
+	s := NewAuthService(repos.Users, repos.APIKeys, repos.Roles, "test-secret", 15*time.Minute, 24*time.Hour).(*authService)
+	s.now = func() time.Time { return *now }
+	return s
+}
+
+// unauthorizedCode retorna o code de um erro ErrUnauthorized, ou "" para outros erros
+func unauthorizedCode(err error) string {
+	var serviceErr *Error
+	if !errors.Is(err, ErrUnauthorized) || !errors.As(err, &serviceErr) {
+		return ""
+	}
+	return serviceErr.Code
+}
+
+func TestAuthLoginAndRefresh(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	s := newAuthTestService(t, dao.NewMemoryRepositories(), &now)
+	user, err := s.CreateUser(model.UserRequest{Username: "ana", Password: "correct horse"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.CreateUser(model.UserRequest{Username: "ana", Password: "other password"}); !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected ErrConflict for a duplicate username, got %v", err)
+	}
+	if _, err := s.CreateUser(model.UserRequest{Username: "bo", Password: "short"}); !isValidationError(err) {
+		t.Fatalf("expected a validation error, got %v", err)
+	}
+
+	for _, creds := range [][2]string{{"ana", "wrong password"}, {"nobody", "correct horse"}} {
+		if _, err := s.Login(creds[0], creds[1]); unauthorizedCode(err) != "invalid_credentials" {
+			t.Fatalf("expected invalid_credentials for %v, got %v", creds, err)
+		}
+	}
+
+	tokens, err := s.Login("ana", "correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	principal, err := s.Authenticate(tokens.AccessToken)
+	if err != nil || principal.UserID != user.ID || principal.Username != "ana" || principal.Method != "jwt" {
+		t.Fatalf("expected ana authenticated by jwt, got %+v, %v", principal, err)
+	}
+	if _, err := s.Authenticate(tokens.RefreshToken); unauthorizedCode(err) != "invalid_token" {
+		t.Fatalf("expected the refresh token to be refused as access token, got %v", err)
+	}
+	if _, err := s.Refresh(tokens.AccessToken); unauthorizedCode(err) != "invalid_token" {
+		t.Fatalf("expected the access token to be refused as refresh token, got %v", err)
+	}
+
+	// depois de expirar o access token o refresh ainda renova o par
+	now = now.Add(time.Hour)
+	if _, err := s.Authenticate(tokens.AccessToken); unauthorizedCode(err) != "token_expired" {
+		t.Fatalf("expected token_expired, got %v", err)
+	}
+	renewed, err := s.Refresh(tokens.RefreshToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Authenticate(renewed.AccessToken); err != nil {
+		t.Fatalf("expected the renewed access token to be valid, got %v", err)
+	}
+}
+
+// TestDummyPasswordHash garante que o login de um username desconhecido paga o mesmo custo
+// bcrypt que o de um username existente
+func TestDummyPasswordHash(t *testing.T) {
+	cost, err := bcrypt.Cost([]byte(dummyPasswordHash))
+	if err != nil {
+		t.Fatalf("expected a valid bcrypt hash, got %v", err)
+	}
+	if cost != bcrypt.DefaultCost {
+		t.Fatalf("expected cost %d, the one CreateUser hashes with, got %d", bcrypt.DefaultCost, cost)
+	}
+}
+
+func TestRefreshRevocation(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	s := newAuthTestService(t, dao.NewMemoryRepositories(), &now)
+	user, err := s.CreateUser(model.UserRequest{Username: "ana", Password: "correct horse"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, err := s.Login("ana", "correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := s.Login("ana", "correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// o logout derruba todos os refresh tokens emitidos até ali, mas não o access token
+	if err := s.Logout(user.ID); err != nil {
+		t.Fatal(err)
+	}
+	for _, tokens := range []model.TokenPair{first, second} {
+		if _, err := s.Refresh(tokens.RefreshToken); unauthorizedCode(err) != "token_revoked" {
+			t.Fatalf("expected token_revoked after logout, got %v", err)
+		}
+	}
+	if _, err := s.Authenticate(first.AccessToken); err != nil {
+		t.Fatalf("expected the access token to stay valid until it expires, got %v", err)
+	}
+	if err := s.Logout("00000000-0000-0000-0000-000000000000"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	// um par emitido depois do logout continua renovando
+	third, err := s.Login("ana", "correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	renewed, err := s.Refresh(third.RefreshToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.ChangePassword(user.ID, model.PasswordChangeRequest{CurrentPassword: "wrong password", NewPassword: "battery staple"}); unauthorizedCode(err) != "invalid_credentials" {
+		t.Fatalf("expected invalid_credentials, got %v", err)
+	}
+	if err := s.ChangePassword(user.ID, model.PasswordChangeRequest{CurrentPassword: "correct horse", NewPassword: "short"}); !isValidationError(err) {
+		t.Fatalf("expected a validation error, got %v", err)
+	}
+	if err := s.ChangePassword(user.ID, model.PasswordChangeRequest{CurrentPassword: "correct horse", NewPassword: "battery staple"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Refresh(renewed.RefreshToken); unauthorizedCode(err) != "token_revoked" {
+		t.Fatalf("expected token_revoked after the password change, got %v", err)
+	}
+	if _, err := s.Login("ana", "correct horse"); unauthorizedCode(err) != "invalid_credentials" {
+		t.Fatalf("expected the old password to be refused, got %v", err)
+	}
+	if _, err := s.Login("ana", "battery staple"); err != nil {
+		t.Fatalf("expected the new password to work, got %v", err)
+	}
+}
+
+func TestEnsureAdmin(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	s := newAuthTestService(t, dao.NewMemoryRepositories(), &now)
+	if err := s.EnsureAdmin("admin", "first password"); err != nil {
+		t.Fatal(err)
+	}
+	// um segundo bootstrap não troca a senha de quem já existe
+	if err := s.EnsureAdmin("admin", "second password"); err != nil {
+		t.Fatal(err)
+	}
+	tokens, err := s.Login("admin", "first password")
+	if err != nil {
+		t.Fatalf("expected the original password to be kept, got %v", err)
+	}
+	principal, err := s.Authenticate(tokens.AccessToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !principal.Can(model.PermRolesManage) || !principal.Can(model.PermUsersManage) {
+		t.Fatalf("expected the admin to have every permission, got %+v", principal)
+	}
+
+	// os papéis trocados por um admin sobrevivem ao próximo bootstrap
+	if _, err := NewRoleService(s.roles, s.users).SetUserRoles(principal.UserID, []string{model.RoleReadOnly}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.EnsureAdmin("admin", "first password"); err != nil {
+		t.Fatal(err)
+	}
+	roles, _, err := userAccess(s.roles, principal.UserID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(roles) != 1 || roles[0] != model.RoleReadOnly {
+		t.Fatalf("expected the roles to be left untouched, got %v", roles)
+	}
+}
+
+func TestAPIKeys(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	repos := dao.NewMemoryRepositories()
+	s := newAuthTestService(t, repos, &now)
+	user, err := s.CreateUser(model.UserRequest{Username: "ana", Password: "correct horse"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.CreateAPIKey(user.ID, ""); !isValidationError(err) {
+		t.Fatalf("expected a validation error for an empty name, got %v", err)
+	}
+	created, err := s.CreateAPIKey(user.ID, "channel manager")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.Key[:len(created.Prefix)] != created.Prefix || created.KeyHash == created.Key {
+		t.Fatalf("unexpected key %+v", created)
+	}
+
+	now = now.Add(time.Minute)
+	principal, err := s.AuthenticateAPIKey(created.Key)
+	if err != nil || principal.UserID != user.ID || principal.Method != "api_key" {
+		t.Fatalf("expected ana authenticated by api key, got %+v, %v", principal, err)
+	}
+	keys, err := s.ListAPIKeys(user.ID)
+	if err != nil || len(keys) != 1 || keys[0].LastUsedAt == nil || !keys[0].LastUsedAt.Equal(now) {
+		t.Fatalf("expected the key use to be recorded, got %+v, %v", keys, err)
+	}
+
+	if err := s.RevokeAPIKey("someone-else", created.ID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound revoking another user's key, got %v", err)
+	}
+	if err := s.RevokeAPIKey(user.ID, created.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.AuthenticateAPIKey(created.Key); unauthorizedCode(err) != "invalid_api_key" {
+		t.Fatalf("expected invalid_api_key after revoking, got %v", err)
+	}
+	if err := s.RevokeAPIKey(user.ID, created.ID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound revoking twice, got %v", err)
+	}
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"errors"
+	"hotel-soa/dao"
+	"hotel-soa/model"
+)
+
+type AvailabilityService interface {
+	Search(query model.AvailabilityQuery) ([]model.AvailableRoom, error)
+	// RoomTypes retorna o estoque de cada tipo no período, com o preço de uma reserva feita
+	// só pelo tipo
+	RoomTypes(query model.AvailabilityQuery) ([]model.RoomTypeAvailability, error)
+}
+
+type availabilityService struct {
+	rooms   dao.RoomRepository
+	pricing *pricingEngine
+}
+
+func NewAvailabilityService(rooms dao.RoomRepository, ratePlans dao.RatePlanRepository) AvailabilityService {
+	return &availabilityService{rooms: rooms, pricing: newPricingEngine(ratePlans)}
+}
+
+// Search busca os quartos livres numa única consulta, que já deixa de fora os tipos cujo
+// estoque as reservas sem quarto esgotaram, e precifica cada um. Os planos são carregados
+// uma vez por tipo de quarto, e quartos barrados por restrição do plano (estadia mínima,
+// chegada fechada) ficam de fora.
+func (s *availabilityService) Search(query model.AvailabilityQuery) ([]model.AvailableRoom, error) {
+	checkin, checkout, err := parseStay("checkin", query.Checkin, "checkout", query.Checkout)
+	if err != nil {
+		return nil, err
+	}
+	if query.Guests <= 0 {
+		query.Guests = 1
+	}
+
+	rooms, err := s.rooms.GetAvailableRooms(checkin, checkout, query.Guests, query.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	plansByType := map[string][]model.RatePlan{}
+	available := []model.AvailableRoom{}
+	for _, room := range rooms {
+		plans, ok := plansByType[room.Type]
+		if !ok {
+			plans, err = s.pricing.plansFor(room.Type, checkin, checkout)
+			if err != nil {
+				return nil, err
+			}
+			plansByType[room.Type] = plans
+		}
+
+		total, nights, err := quoteWithPlans(room, plans, checkin, checkout)
+		if err != nil {
+			continue
+		}
+		available = append(available, model.AvailableRoom{Room: room, TotalAmount: total, Nights: nights})
+	}
+	return available, nil
+}
+
+// RoomTypes precifica cada tipo pelo rackRoom, o mesmo preço que uma reserva feita só pelo
+// tipo recebe; o tipo barrado por restrição do plano vem sem preço
+func (s *availabilityService) RoomTypes(query model.AvailabilityQuery) ([]model.RoomTypeAvailability, error) {
+	checkin, checkout, err := parseStay("checkin", query.Checkin, "checkout", query.Checkout)
+	if err != nil {
+		return nil, err
+	}
+
+	types, err := s.rooms.GetRoomTypeAvailability(checkin, checkout)
+	if err != nil {
+		return nil, err
+	}
+	rooms, err := s.rooms.GetAllRooms()
+	if err != nil {
+		return nil, err
+	}
+	available := []model.RoomTypeAvailability{}
+	for _, t := range types {
+		if query.Type != "" && t.RoomType != query.Type {
+			continue
+		}
+		if total, nights, err := s.pricing.Quote(rackRoom(rooms, t.RoomType), checkin, checkout); err == nil {
+			t.TotalAmount, t.Nights = &total, nights
+		} else if !errors.Is(err, ErrInvalid) {
+			return nil, err
+		}
+		available = append(available, t)
+	}
+	return available, nil
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"hotel-soa/dao"
+	"hotel-soa/model"
+)
+
+// countingRooms conta as buscas de disponibilidade e de estoque feitas no repositório
+type countingRooms struct {
+	dao.RoomRepository
+	searches  int
+	typeScans int
+}
+
+func (r *countingRooms) GetAvailableRooms(checkin, checkout time.Time, guests int, roomType string) ([]model.Room, error) {
+	r.searches++
+	return r.RoomRepository.GetAvailableRooms(checkin, checkout, guests, roomType)
+}
+
+func (r *countingRooms) GetRoomTypeAvailability(checkin, checkout time.Time) ([]model.RoomTypeAvailability, error) {
+	r.typeScans++
+	return r.RoomRepository.GetRoomTypeAvailability(checkin, checkout)
+}
+
+// countingRatePlans conta as cargas de planos por tipo de quarto
+type countingRatePlans struct {
+	dao.RatePlanRepository
+	loads map[string]int
+}
+
+func (r *countingRatePlans) GetRatePlansForPeriod(roomType string, from, to time.Time) ([]model.RatePlan, error) {
+	r.loads[roomType]++
+	return r.RatePlanRepository.GetRatePlansForPeriod(roomType, from, to)
+}
+
+func TestAvailabilitySearch(t *testing.T) {
+	repos := dao.NewMemoryRepositories()
+	for _, room := range []model.Room{
+		{Number: 101, Type: "STANDARD", Capacity: 2, PricePerNight: model.NewMoney(10000), Status: "ATIVO"},
+		{Number: 102, Type: "STANDARD", Capacity: 2, PricePerNight: model.NewMoney(12000), Status: "ATIVO"},
+		{Number: 201, Type: "DELUXE", Capacity: 2, PricePerNight: model.NewMoney(25000), Status: "ATIVO"},
+	} {
+		if _, err := repos.Rooms.InsertRoom(room, testActor); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// o plano do DELUXE exige duas noites, então ele some das buscas de uma noite
+	if _, err := repos.RatePlans.InsertRatePlan(model.RatePlan{Name: "Deluxe", RoomType: "DELUXE", StartDate: "2026-06-01", EndDate: "2026-06-30", BaseRate: model.NewMoney(30000), MinStay: 2}); err != nil {
+		t.Fatal(err)
+	}
+	rooms := &countingRooms{RoomRepository: repos.Rooms}
+	ratePlans := &countingRatePlans{RatePlanRepository: repos.RatePlans, loads: map[string]int{}}
+	availability := NewAvailabilityService(rooms, ratePlans)
+
+	found, err := availability.Search(model.AvailabilityQuery{Checkin: "2026-06-10", Checkout: "2026-06-12", Guests: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	totals := map[int]int64{}
+	for _, room := range found {
+		totals[room.Room.Number] = room.TotalAmount.Minor()
+	}
+	if len(found) != 3 || totals[101] != 20000 || totals[102] != 24000 || totals[201] != 60000 {
+		t.Fatalf("unexpected rooms %+v", found)
+	}
+	// uma consulta de quartos, que já traz o estoque dos tipos, e uma carga de planos por
+	// tipo, não por quarto
+	if rooms.searches != 1 || rooms.typeScans != 0 || ratePlans.loads["STANDARD"] != 1 || ratePlans.loads["DELUXE"] != 1 {
+		t.Fatalf("expected one room search and one plan load per type, got %d, %d and %v", rooms.searches, rooms.typeScans, ratePlans.loads)
+	}
+
+	found, err = availability.Search(model.AvailabilityQuery{Checkin: "2026-06-10", Checkout: "2026-06-11"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, room := range found {
+		if room.Room.Type == "DELUXE" {
+			t.Fatalf("expected the minimum stay to hide the deluxe room, got %+v", found)
+		}
+	}
+
+	for _, query := range []model.AvailabilityQuery{
+		{Checkin: "2026-06-10", Checkout: "2026-06-10"},
+		{Checkin: "10/06/2026", Checkout: "2026-06-12"},
+	} {
+		if _, err := availability.Search(query); !isValidationError(err) {
+			t.Fatalf("expected a validation error for %+v, got %v", query, err)
+		}
+	}
+}
+
+func TestAvailabilityRoomTypes(t *testing.T) {
+	repos := dao.NewMemoryRepositories()
+	insertRooms(t, repos,
+		model.Room{Number: 101, Type: "STANDARD", Capacity: 2, PricePerNight: model.NewMoney(12000), Status: "ATIVO"},
+		model.Room{Number: 102, Type: "STANDARD", Capacity: 2, PricePerNight: model.NewMoney(10000), Status: "ATIVO"},
+		model.Room{Number: 201, Type: "DELUXE", Capacity: 2, PricePerNight: model.NewMoney(25000), Status: "ATIVO"},
+	)
+	// o plano do DELUXE exige duas noites, então o tipo vem sem preço numa noite só
+	if _, err := repos.RatePlans.InsertRatePlan(model.RatePlan{Name: "Deluxe", RoomType: "DELUXE", StartDate: "2026-06-01", EndDate: "2026-06-30", BaseRate: model.NewMoney(30000), MinStay: 2}); err != nil {
+		t.Fatal(err)
+	}
+	reservations := newStayTestService(repos, date("2026-06-01"))
+	if _, err := reservations.Create(model.Reservation{RoomType: "DELUXE", GuestName: "Ana", CheckinExpected: "2026-06-10", CheckoutExpected: "2026-06-12"}, "", nil, testActor); err != nil {
+		t.Fatal(err)
+	}
+	availability := NewAvailabilityService(repos.Rooms, repos.RatePlans)
+
+	types, err := availability.RoomTypes(model.AvailabilityQuery{Checkin: "2026-06-11", Checkout: "2026-06-12"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(types) != 2 || types[0].RoomType != "DELUXE" || types[0].Available != 0 || types[0].TotalAmount != nil {
+		t.Fatalf("expected a sold out DELUXE without a price, got %+v", types)
+	}
+	if standard := types[1]; standard.TotalRooms != 2 || standard.Available != 2 || !standard.TotalAmount.Equal(model.NewMoney(10000)) || len(standard.Nights) != 1 {
+		t.Fatalf("expected two STANDARD rooms priced by the cheapest, got %+v", standard)
+	}
+
+	// o DELUXE sem quarto atribuído esgota o tipo, então a 201 some da busca por quarto
+	found, err := availability.Search(model.AvailabilityQuery{Checkin: "2026-06-10", Checkout: "2026-06-12", Guests: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, room := range found {
+		if room.Room.Type == "DELUXE" {
+			t.Fatalf("expected the sold out DELUXE to be left out, got %+v", found)
+		}
+	}
+	if len(found) != 2 {
+		t.Fatalf("expected the two STANDARD rooms, got %+v", found)
+	}
+
+	types, err = availability.RoomTypes(model.AvailabilityQuery{Checkin: "2026-06-12", Checkout: "2026-06-14", Type: "DELUXE"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(types) != 1 || types[0].Available != 1 || !types[0].TotalAmount.Equal(model.NewMoney(60000)) {
+		t.Fatalf("expected one DELUXE at 600, got %+v", types)
+	}
+	if _, err := availability.RoomTypes(model.AvailabilityQuery{Checkin: "2026-06-14", Checkout: "2026-06-12"}); !isValidationError(err) {
+		t.Fatalf("expected a validation error, got %v", err)
+	}
+}
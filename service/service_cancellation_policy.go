@@ -0,0 +1,124 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"hotel-soa/dao"
+	"hotel-soa/model"
+	"math"
+	"time"
+)
+
+type CancellationPolicyService interface {
+	Create(policy model.CancellationPolicy) (string, error)
+	Update(policy model.CancellationPolicy) error
+	Delete(id string) error
+	GetByID(id string) (model.CancellationPolicy, error)
+	GetAll() ([]model.CancellationPolicy, error)
+}
+
+type cancellationPolicyService struct {
+	policies dao.CancellationPolicyRepository
+}
+
+func NewCancellationPolicyService(policies dao.CancellationPolicyRepository) CancellationPolicyService {
+	return &cancellationPolicyService{policies: policies}
+}
+
+func (s *cancellationPolicyService) Create(policy model.CancellationPolicy) (string, error) {
+	return s.policies.InsertCancellationPolicy(policy)
+}
+
+// Update vale também para as reservas que já usam a política e ainda não foram canceladas
+func (s *cancellationPolicyService) Update(policy model.CancellationPolicy) error {
+	if _, err := s.GetByID(policy.ID); err != nil {
+		return err
+	}
+	return s.policies.UpdateCancellationPolicy(policy)
+}
+
+func (s *cancellationPolicyService) Delete(id string) error {
+	if _, err := s.GetByID(id); err != nil {
+		return err
+	}
+	err := s.policies.DeleteCancellationPolicy(id)
+	if errors.Is(err, dao.ErrCancellationPolicyInUse) {
+		return conflict("cancellation_policy_in_use", "%s", err.Error())
+	}
+	return err
+}
+
+func (s *cancellationPolicyService) GetByID(id string) (model.CancellationPolicy, error) {
+	policy, err := s.policies.GetCancellationPolicyByID(id)
+	if err != nil {
+		return model.CancellationPolicy{}, err
+	}
+	if policy.ID == "" {
+		return model.CancellationPolicy{}, notFound("cancellation_policy_not_found", "cancellation policy %s not found", id)
+	}
+	return policy, nil
+}
+
+func (s *cancellationPolicyService) GetAll() ([]model.CancellationPolicy, error) {
+	return s.policies.GetAllCancellationPolicies()
+}
+
+// ---------------- HELPERS ----------------
+
+// checkPolicyExists valida o cancellation_policy_id de quartos e planos tarifários; vazio é aceito
+func checkPolicyExists(policies dao.CancellationPolicyRepository, id string) error {
+	if id == "" {
+		return nil
+	}
+	policy, err := policies.GetCancellationPolicyByID(id)
+	if err != nil {
+		return err
+	}
+	if policy.ID == "" {
+		var v model.ValidationError
+		v.Add("cancellation_policy_id", "not_found", fmt.Sprintf("cancellation policy %s not found", id))
+		return v.Err()
+	}
+	return nil
+}
+
+// quoteCancellation aplica a política ao cancelamento feito em at. Sem política o
+// cancelamento é grátis; a multa nunca passa do total da reserva
+func quoteCancellation(res model.Reservation, policy *model.CancellationPolicy, at time.Time) (model.CancellationQuote, error) {
+	checkin, _, err := parseStay("checkin_expected", res.CheckinExpected, "checkout_expected", res.CheckoutExpected)
+	if err != nil {
+		return model.CancellationQuote{}, err
+	}
+	today := time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, time.UTC)
+	quote := model.CancellationQuote{DaysBeforeArrival: int(math.Round(checkin.Sub(today).Hours() / 24))}
+
+	fee := model.NewMoney(0)
+	if policy != nil {
+		quote.CancellationPolicyID = policy.ID
+		switch {
+		case policy.NonRefundable:
+			fee = res.TotalAmount
+		case quote.DaysBeforeArrival >= policy.FreeUntilDays:
+			fee = model.NewMoney(0)
+		case policy.PenaltyType == model.PenaltyNights:
+			fee = nightsFee(res, int(policy.PenaltyValue))
+		default:
+			fee = res.TotalAmount.Percent(policy.PenaltyValue, model.FeeRounding)
+		}
+	}
+	if fee.Cmp(res.TotalAmount) > 0 {
+		fee = res.TotalAmount
+	}
+	quote.Fee, quote.RefundAmount = fee, res.TotalAmount.Sub(fee)
+	return quote, nil
+}
+
+// nightsFee soma o preço das primeiras noites da estadia; desde a migração 0016 toda
+// reserva tem o detalhamento por noite
+func nightsFee(res model.Reservation, count int) model.Money {
+	fee := model.NewMoney(0)
+	for i := 0; i < count && i < len(res.Nights); i++ {
+		fee = fee.Add(res.Nights[i].Price)
+	}
+	return fee
+}
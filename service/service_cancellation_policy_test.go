@@ -0,0 +1,143 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"hotel-soa/dao"
+	"hotel-soa/model"
+)
+
+func TestQuoteCancellation(t *testing.T) {
+	// três noites de 100,00, 120,00 e 130,05 com chegada em 10/06
+	res := model.Reservation{
+		CheckinExpected:  "2026-06-10",
+		CheckoutExpected: "2026-06-13",
+		TotalAmount:      model.NewMoney(35005),
+		Nights: []model.NightlyRate{
+			{Date: "2026-06-10", Price: model.NewMoney(10000)},
+			{Date: "2026-06-11", Price: model.NewMoney(12000)},
+			{Date: "2026-06-12", Price: model.NewMoney(13005)},
+		},
+	}
+	percent := &model.CancellationPolicy{ID: "p", FreeUntilDays: 7, PenaltyType: model.PenaltyPercent, PenaltyValue: 50}
+	nights := &model.CancellationPolicy{ID: "n", FreeUntilDays: 2, PenaltyType: model.PenaltyNights, PenaltyValue: 2}
+	tooManyNights := &model.CancellationPolicy{ID: "n5", FreeUntilDays: 2, PenaltyType: model.PenaltyNights, PenaltyValue: 5}
+	nonRefundable := &model.CancellationPolicy{ID: "nr", FreeUntilDays: 30, NonRefundable: true}
+
+	tests := []struct {
+		name   string
+		policy *model.CancellationPolicy
+		at     time.Time
+		days   int
+		fee    int64
+	}{
+		{"no policy", nil, time.Date(2026, 6, 9, 23, 0, 0, 0, time.UTC), 1, 0},
+		{"before the free window", percent, time.Date(2026, 5, 1, 10, 0, 0, 0, time.UTC), 40, 0},
+		{"last free day", percent, time.Date(2026, 6, 3, 23, 59, 0, 0, time.UTC), 7, 0},
+		// 50% de 350,05 é 175,025, arredondado para cima
+		{"first day with fee", percent, time.Date(2026, 6, 4, 0, 0, 0, 0, time.UTC), 6, 17503},
+		{"arrival day", percent, time.Date(2026, 6, 10, 8, 0, 0, 0, time.UTC), 0, 17503},
+		{"after arrival", percent, time.Date(2026, 6, 11, 8, 0, 0, 0, time.UTC), -1, 17503},
+		{"nights outside the window", nights, time.Date(2026, 6, 8, 8, 0, 0, 0, time.UTC), 2, 0},
+		{"first nights", nights, time.Date(2026, 6, 9, 8, 0, 0, 0, time.UTC), 1, 22000},
+		{"nights capped at the stay", tooManyNights, time.Date(2026, 6, 9, 8, 0, 0, 0, time.UTC), 1, 35005},
+		{"non refundable", nonRefundable, time.Date(2026, 1, 1, 8, 0, 0, 0, time.UTC), 160, 35005},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quote, err := quoteCancellation(res, tt.policy, tt.at)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if quote.DaysBeforeArrival != tt.days {
+				t.Errorf("expected %d days before arrival, got %d", tt.days, quote.DaysBeforeArrival)
+			}
+			if quote.Fee.Minor() != tt.fee {
+				t.Errorf("expected fee %d, got %d", tt.fee, quote.Fee.Minor())
+			}
+			if refund := res.TotalAmount.Sub(quote.Fee); !quote.RefundAmount.Equal(refund) {
+				t.Errorf("expected refund %s, got %s", refund, quote.RefundAmount)
+			}
+		})
+	}
+}
+
+func TestReservationCancellationPolicy(t *testing.T) {
+	repos := dao.NewMemoryRepositories()
+	policies := NewCancellationPolicyService(repos.Policies)
+	roomPolicy, err := policies.Create(model.CancellationPolicy{Name: "Room", FreeUntilDays: 2, PenaltyType: model.PenaltyNights, PenaltyValue: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	planPolicy, err := policies.Create(model.CancellationPolicy{Name: "Non refundable", NonRefundable: true, PenaltyType: model.PenaltyPercent})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rooms := NewRoomService(repos.Rooms, repos.Policies)
+	if _, err := rooms.Create(model.Room{Number: 100, Type: "STANDARD", Capacity: 2, PricePerNight: model.NewMoney(10000), Status: "ATIVO",
+		CancellationPolicyID: "00000000-0000-0000-0000-000000000000"}, testActor); !isValidationError(err) {
+		t.Fatalf("expected a validation error for an unknown policy, got %v", err)
+	}
+	roomID, err := rooms.Create(model.Room{Number: 101, Type: "STANDARD", Capacity: 2, PricePerNight: model.NewMoney(10000), Status: "ATIVO",
+		CancellationPolicyID: roomPolicy}, testActor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewRatePlanService(repos.RatePlans, repos.Policies).Create(model.RatePlan{Name: "July", RoomType: "STANDARD",
+		StartDate: "2026-07-01", EndDate: "2026-07-31", BaseRate: model.NewMoney(15000), CancellationPolicyID: planPolicy}); err != nil {
+		t.Fatal(err)
+	}
+
+	s := newStayTestService(repos, date("2026-06-09").Add(10*time.Hour))
+	book := func(checkin, checkout string) model.Reservation {
+		t.Helper()
+		res, err := s.Create(model.Reservation{RoomID: roomID, GuestName: "Ana", CheckinExpected: checkin, CheckoutExpected: checkout}, "", nil, testActor)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res
+	}
+
+	// sem plano vale a política do quarto; com plano, a do plano
+	june := book("2026-06-10", "2026-06-12")
+	july := book("2026-07-10", "2026-07-12")
+	if june.CancellationPolicyID != roomPolicy || july.CancellationPolicyID != planPolicy {
+		t.Fatalf("expected policies %s and %s, got %s and %s", roomPolicy, planPolicy, june.CancellationPolicyID, july.CancellationPolicyID)
+	}
+
+	quote, err := s.QuoteCancellation(june.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !quote.Fee.Equal(model.NewMoney(10000)) || !quote.RefundAmount.Equal(model.NewMoney(10000)) || quote.DaysBeforeArrival != 1 {
+		t.Fatalf("expected a fee of one night, got %+v", quote)
+	}
+	canceled, err := s.Cancel(june.ID, "", testActor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !canceled.CancellationFee.Equal(model.NewMoney(10000)) || !canceled.RefundAmount.Equal(model.NewMoney(10000)) {
+		t.Fatalf("expected the quoted fee and refund, got %v and %v", *canceled.CancellationFee, *canceled.RefundAmount)
+	}
+	if _, err := s.QuoteCancellation(june.ID); !errors.Is(err, ErrInvalidTransition) {
+		t.Fatalf("expected ErrInvalidTransition quoting a canceled reservation, got %v", err)
+	}
+
+	// a política pode mudar até o cancelamento
+	if err := policies.Update(model.CancellationPolicy{ID: planPolicy, Name: "Free", FreeUntilDays: 0, PenaltyType: model.PenaltyPercent}); err != nil {
+		t.Fatal(err)
+	}
+	if quote, err := s.QuoteCancellation(july.ID); err != nil || !quote.Fee.IsZero() || !quote.RefundAmount.Equal(july.TotalAmount) {
+		t.Fatalf("expected a free cancellation after the update, got %+v, %v", quote, err)
+	}
+
+	if err := policies.Delete(planPolicy); !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected ErrConflict deleting a policy in use, got %v", err)
+	}
+	if err := policies.Delete("00000000-0000-0000-0000-000000000000"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Categorias de erro da camada de serviço. O controller traduz cada uma para um
+// status HTTP; use errors.Is para testar a categoria de um erro retornado
+var (
+	ErrInvalid           = errors.New("invalid request")
+	ErrNotFound          = errors.New("not found")
+	ErrConflict          = errors.New("conflict")
+	ErrInvalidTransition = errors.New("invalid status transition")
+	ErrUnauthorized      = errors.New("unauthorized")
+)
+
+// Error é um erro de regra de negócio: Kind é uma das categorias acima e Code o
+// identificador estável devolvido ao cliente
+type Error struct {
+	Kind   error
+	Code   string
+	Detail string
+}
+
+func (e *Error) Error() string {
+	return e.Detail
+}
+
+func (e *Error) Unwrap() error {
+	return e.Kind
+}
+
+func newError(kind error, code, format string, args ...any) error {
+	return &Error{Kind: kind, Code: code, Detail: fmt.Sprintf(format, args...)}
+}
+
+func invalid(code, format string, args ...any) error {
+	return newError(ErrInvalid, code, format, args...)
+}
+
+func notFound(code, format string, args ...any) error {
+	return newError(ErrNotFound, code, format, args...)
+}
+
+func conflict(code, format string, args ...any) error {
+	return newError(ErrConflict, code, format, args...)
+}
+
+func invalidTransition(code, format string, args ...any) error {
+	return newError(ErrInvalidTransition, code, format, args...)
+}
+
+func unauthorized(code, format string, args ...any) error {
+	return newError(ErrUnauthorized, code, format, args...)
+}
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"errors"
+	"hotel-soa/dao"
+	"hotel-soa/gateway"
+	"hotel-soa/model"
+	"time"
+)
+
+type FolioService interface {
+	Get(reservationID string) (model.Folio, error)
+	// PostCharge lança consumo, taxa ou desconto; o desconto é gravado com valor negativo
+	PostCharge(reservationID string, req model.FolioChargeRequest, actor model.Actor) (model.FolioCharge, error)
+	// PostPayment registra um pagamento ou estorno feito fora do sistema; o REFUND com
+	// authorization_id é feito pelo gateway, contra a captura da pré-autorização
+	PostPayment(reservationID string, req model.PaymentRequest, actor model.Actor) (model.Payment, error)
+}
+
+type folioService struct {
+	folios       dao.FolioRepository
+	reservations dao.ReservationRepository
+	payments     *cardPayments
+	now          func() time.Time
+}
+
+func NewFolioService(folios dao.FolioRepository, reservations dao.ReservationRepository, payments gateway.PaymentGateway) FolioService {
+	return &folioService{
+		folios:       folios,
+		reservations: reservations,
+		payments:     newCardPayments(payments, folios, 0),
+		now:          time.Now,
+	}
+}
+
+func (s *folioService) Get(reservationID string) (model.Folio, error) {
+	folio, err := s.folios.GetFolio(reservationID)
+	if err != nil {
+		return model.Folio{}, err
+	}
+	if folio.ReservationID == "" {
+		return model.Folio{}, notFound("reservation_not_found", "reservation %s not found", reservationID)
+	}
+	if folio.Charges == nil {
+		folio.Charges = []model.FolioCharge{}
+	}
+	if folio.Payments == nil {
+		folio.Payments = []model.Payment{}
+	}
+	if folio.Taxes == nil {
+		folio.Taxes = []model.TaxSummary{}
+	}
+	if folio.Authorizations == nil {
+		folio.Authorizations = []model.PaymentAuthorization{}
+	}
+	sumFolio(&folio)
+	return folio, nil
+}
+
+func (s *folioService) PostCharge(reservationID string, req model.FolioChargeRequest, actor model.Actor) (model.FolioCharge, error) {
+	if err := req.Validate(); err != nil {
+		return model.FolioCharge{}, err
+	}
+	if err := s.requireReservation(reservationID); err != nil {
+		return model.FolioCharge{}, err
+	}
+
+	now := s.now()
+	charge := model.FolioCharge{
+		ReservationID: reservationID,
+		Type:          req.Type,
+		Description:   req.Description,
+		Date:          now.Format("2006-01-02"),
+		Amount:        req.Amount,
+		PostedAt:      &now,
+		PostedBy:      actor.Username,
+	}
+	if charge.Type == model.FolioDiscount {
+		charge.Amount = charge.Amount.Neg()
+	}
+
+	id, err := s.folios.PostCharge(charge, actor)
+	switch {
+	case errors.Is(err, dao.ErrFolioClosed):
+		return model.FolioCharge{}, conflict("folio_closed", "%s", err.Error())
+	case errors.Is(err, dao.ErrDiscountExceedsTotal):
+		return model.FolioCharge{}, conflict("discount_exceeds_total", "%s", err.Error())
+	case err != nil:
+		return model.FolioCharge{}, err
+	}
+	charge.ID = id
+	return charge, nil
+}
+
+func (s *folioService) PostPayment(reservationID string, req model.PaymentRequest, actor model.Actor) (model.Payment, error) {
+	if err := req.Validate(); err != nil {
+		return model.Payment{}, err
+	}
+	if err := s.requireReservation(reservationID); err != nil {
+		return model.Payment{}, err
+	}
+	if req.AuthorizationID != "" {
+		payment, err := s.payments.refund(reservationID, req.AuthorizationID, req.Amount, actor)
+		return payment, mapPaymentError(err, reservationID)
+	}
+
+	payment := model.Payment{
+		ReservationID: reservationID,
+		Type:          req.Type,
+		Method:        req.Method,
+		Amount:        req.Amount,
+		Reference:     req.Reference,
+		PostedAt:      s.now(),
+		PostedBy:      actor.Username,
+	}
+	if payment.Type == "" {
+		payment.Type = model.PaymentTypePayment
+	}
+
+	id, err := s.folios.PostPayment(payment, actor)
+	if err != nil {
+		return model.Payment{}, mapPaymentError(err, reservationID)
+	}
+	payment.ID = id
+	return payment, nil
+}
+
+// ---------------- HELPERS ----------------
+
+// sumFolio preenche a moeda e os totais da conta. As linhas sem ID de uma reserva de
+// grupo são da conta master e não entram em TotalCharges
+func sumFolio(folio *model.Folio) {
+	folio.Currency = model.Currency()
+	folio.TotalCharges = model.NewMoney(0)
+	for _, charge := range folio.Charges {
+		if folio.GroupID != "" && charge.ID == "" {
+			continue
+		}
+		folio.TotalCharges = folio.TotalCharges.Add(charge.Amount)
+	}
+	folio.TotalPaid = totalPaid(folio.Payments)
+	folio.Balance = folio.TotalCharges.Sub(folio.TotalPaid)
+}
+
+// totalPaid soma os pagamentos menos os estornos
+func totalPaid(payments []model.Payment) model.Money {
+	paid := model.NewMoney(0)
+	for _, payment := range payments {
+		if payment.Type == model.PaymentTypeRefund {
+			paid = paid.Sub(payment.Amount)
+		} else {
+			paid = paid.Add(payment.Amount)
+		}
+	}
+	return paid
+}
+
+// mapPaymentError traduz as recusas do repositório ao gravar um pagamento ou estorno
+func mapPaymentError(err error, reservationID string) error {
+	switch {
+	case errors.Is(err, dao.ErrRefundExceedsPaid):
+		return conflict("refund_exceeds_paid", "%s", err.Error())
+	case errors.Is(err, dao.ErrReservationStatusChanged):
+		return notFound("reservation_not_found", "reservation %s not found", reservationID)
+	}
+	return err
+}
+
+func (s *folioService) requireReservation(id string) error {
+	res, err := s.reservations.GetReservationByID(id)
+	if err != nil {
+		return err
+	}
+	if res.ID == "" {
+		return notFound("reservation_not_found", "reservation %s not found", id)
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"hotel-soa/dao"
+	"hotel-soa/gateway"
+	"hotel-soa/model"
+)
+
+func TestFolioService(t *testing.T) {
+	repos := dao.NewMemoryRepositories()
+	roomID, err := repos.Rooms.InsertRoom(model.Room{Number: 101, Type: "STANDARD", Capacity: 2, PricePerNight: model.NewMoney(10000), Status: "ATIVO"}, testActor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reservations := newStayTestService(repos, date("2026-06-10").Add(14*time.Hour))
+	res, err := reservations.Create(model.Reservation{RoomID: roomID, GuestName: "Ana", CheckinExpected: "2026-06-10", CheckoutExpected: "2026-06-12"}, "", nil, testActor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	folios := NewFolioService(repos.Folios, repos.Reservations, gateway.NewStubGateway()).(*folioService)
+	folios.now = func() time.Time { return date("2026-06-10").Add(20 * time.Hour) }
+	maria := model.Actor{Username: "maria"}
+
+	minibar, err := folios.PostCharge(res.ID, model.FolioChargeRequest{Type: model.FolioMinibar, Description: "água", Amount: model.NewMoney(1235)}, maria)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if minibar.ID == "" || !minibar.Amount.Equal(model.NewMoney(1235)) || minibar.Date != "2026-06-10" || minibar.PostedBy != "maria" {
+		t.Fatalf("unexpected charge %+v", minibar)
+	}
+	// o desconto é enviado positivo e gravado negativo
+	discount, err := folios.PostCharge(res.ID, model.FolioChargeRequest{Type: model.FolioDiscount, Amount: model.NewMoney(235)}, maria)
+	if err != nil || !discount.Amount.Equal(model.NewMoney(-235)) {
+		t.Fatalf("expected a discount of -2.35, got %+v, %v", discount, err)
+	}
+	payment, err := folios.PostPayment(res.ID, model.PaymentRequest{Method: "CARD", Amount: model.NewMoney(25000)}, maria)
+	if err != nil || payment.Type != model.PaymentTypePayment {
+		t.Fatalf("expected a PAYMENT, got %+v, %v", payment, err)
+	}
+
+	folio, err := folios.Get(res.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !folio.TotalCharges.Equal(model.NewMoney(21000)) || !folio.TotalPaid.Equal(model.NewMoney(25000)) || !folio.Balance.Equal(model.NewMoney(-4000)) {
+		t.Fatalf("expected 210 charged, 250 paid and a credit of 40, got %+v", folio)
+	}
+	if _, err := folios.PostPayment(res.ID, model.PaymentRequest{Type: model.PaymentTypeRefund, Method: "CARD", Amount: model.NewMoney(4000)}, maria); err != nil {
+		t.Fatal(err)
+	}
+	if folio, _ := folios.Get(res.ID); !folio.Balance.Equal(model.NewMoney(0)) {
+		t.Fatalf("expected a settled folio, got %+v", folio)
+	}
+
+	tests := []struct {
+		name    string
+		call    func() error
+		wantErr error
+	}{
+		{"unknown reservation", func() error { _, err := folios.Get("00000000-0000-0000-0000-000000000000"); return err }, ErrNotFound},
+		{"charge on unknown reservation", func() error {
+			_, err := folios.PostCharge("00000000-0000-0000-0000-000000000000", model.FolioChargeRequest{Type: model.FolioOther, Amount: model.NewMoney(100)}, maria)
+			return err
+		}, ErrNotFound},
+		{"discount above the total", func() error {
+			_, err := folios.PostCharge(res.ID, model.FolioChargeRequest{Type: model.FolioDiscount, Amount: model.NewMoney(100000)}, maria)
+			return err
+		}, ErrConflict},
+		{"refund above the paid", func() error {
+			_, err := folios.PostPayment(res.ID, model.PaymentRequest{Type: model.PaymentTypeRefund, Method: "CARD", Amount: model.NewMoney(100000)}, maria)
+			return err
+		}, ErrConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+	if _, err := folios.PostCharge(res.ID, model.FolioChargeRequest{Type: "SPA", Amount: model.NewMoney(100)}, maria); !isValidationError(err) {
+		t.Fatalf("expected a validation error, got %v", err)
+	}
+
+	// com a conta paga o check-out fecha a conta para novos lançamentos
+	if _, err := reservations.CheckIn(res.ID, "maria", "", testActor); err != nil {
+		t.Fatal(err)
+	}
+	reservations.now = func() time.Time { return date("2026-06-12").Add(10 * time.Hour) }
+	if _, err := reservations.CheckOut(res.ID, "joao", testActor); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := folios.PostCharge(res.ID, model.FolioChargeRequest{Type: model.FolioOther, Amount: model.NewMoney(100)}, maria); !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected ErrConflict posting to a closed folio, got %v", err)
+	}
+}
@@ -0,0 +1,278 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"hotel-soa/dao"
+	"hotel-soa/model"
+	"strings"
+	"time"
+)
+
+type GroupService interface {
+	// Create segura os quartos do bloco no estoque de cada tipo; o bloco que não cabe em
+	// alguma noite volta como *model.RoomTypeSoldOutError
+	Create(group model.Group, actor model.Actor) (model.Group, error)
+	GetByID(id string) (model.Group, error)
+	GetAll() ([]model.Group, error)
+	Reservations(id string) ([]model.Reservation, error)
+	// PickUp cria uma reserva do grupo pelo tipo. Enquanto o grupo está ACTIVE e o bloco
+	// tem quarto livre em todas as noites da estadia, ela usa um quarto do bloco; depois
+	// do release, ou além do bloco, passa pelo estoque do tipo como qualquer reserva
+	PickUp(id string, req model.GroupPickupRequest, actor model.Actor) (model.Reservation, error)
+	// Release devolve ao estoque os quartos do bloco ainda não usados
+	Release(id string, actor model.Actor) (model.Group, error)
+	// ReleaseExpired faz o release dos grupos ACTIVE cujo cutoff passou e retorna quantos eram
+	ReleaseExpired(now time.Time) (int, error)
+	// Cancel cancela o grupo e as reservas do grupo que ainda esperam o hóspede, com a
+	// política de cancelamento de cada uma; as já hospedadas ficam como estão. Repetido
+	// num grupo cancelado, só termina de cancelar as reservas que tenham ficado para trás
+	Cancel(id, reason string, actor model.Actor) (model.GroupCancellation, error)
+	Folio(id string) (model.GroupFolio, error)
+	PostPayment(id string, req model.PaymentRequest, actor model.Actor) (model.Payment, error)
+}
+
+type groupService struct {
+	groups       dao.GroupRepository
+	reservations ReservationService
+	now          func() time.Time
+}
+
+// NewGroupService cria o GroupService; as reservas do grupo são criadas e canceladas pelo
+// ReservationService, com o mesmo preço, impostos e política das demais
+func NewGroupService(groups dao.GroupRepository, reservations ReservationService) GroupService {
+	return &groupService{groups: groups, reservations: reservations, now: time.Now}
+}
+
+func (s *groupService) Create(group model.Group, actor model.Actor) (model.Group, error) {
+	if err := group.Validate(); err != nil {
+		return model.Group{}, err
+	}
+	group.Status, group.CreatedAt, group.CreatedBy = model.GroupActive, s.now().UTC(), actor.Username
+	id, err := s.groups.InsertGroup(group, actor)
+	if err != nil {
+		return model.Group{}, err
+	}
+	return s.GetByID(id)
+}
+
+func (s *groupService) GetByID(id string) (model.Group, error) {
+	group, err := s.groups.GetGroupByID(id)
+	if err != nil {
+		return model.Group{}, err
+	}
+	if group.ID == "" {
+		return model.Group{}, notFound("group_not_found", "group %s not found", id)
+	}
+	return group, nil
+}
+
+func (s *groupService) GetAll() ([]model.Group, error) {
+	return s.groups.GetAllGroups()
+}
+
+func (s *groupService) Reservations(id string) ([]model.Reservation, error) {
+	if _, err := s.GetByID(id); err != nil {
+		return nil, err
+	}
+	reservations, err := s.groups.GetGroupReservations(id)
+	if reservations == nil {
+		reservations = []model.Reservation{}
+	}
+	return reservations, err
+}
+
+// ---------------- PICK-UP ----------------
+func (s *groupService) PickUp(id string, req model.GroupPickupRequest, actor model.Actor) (model.Reservation, error) {
+	group, err := s.GetByID(id)
+	if err != nil {
+		return model.Reservation{}, err
+	}
+	if group.Status == model.GroupCanceled {
+		return model.Reservation{}, conflict("group_canceled", "group %s was canceled", id)
+	}
+
+	// tipo e datas do bloco quando não informados
+	if req.RoomType == "" && len(group.Blocks) == 1 {
+		req.RoomType = group.Blocks[0].RoomType
+	}
+	if _, ok := group.Block(req.RoomType); !ok {
+		types := make([]string, 0, len(group.Blocks))
+		for _, block := range group.Blocks {
+			types = append(types, block.RoomType)
+		}
+		var v model.ValidationError
+		v.Add("room_type", "invalid_value", fmt.Sprintf("must be one of the group's room types: %s", strings.Join(types, ", ")))
+		return model.Reservation{}, v.Err()
+	}
+	if req.CheckinExpected == "" {
+		req.CheckinExpected = group.CheckinExpected
+	}
+	if req.CheckoutExpected == "" {
+		req.CheckoutExpected = group.CheckoutExpected
+	}
+
+	res := model.Reservation{
+		GroupID:          id,
+		GuestID:          req.GuestID,
+		GuestName:        req.GuestName,
+		RoomType:         req.RoomType,
+		CheckinExpected:  req.CheckinExpected,
+		CheckoutExpected: req.CheckoutExpected,
+	}
+	return s.reservations.Create(res, "", nil, actor)
+}
+
+// ---------------- RELEASE ----------------
+func (s *groupService) Release(id string, actor model.Actor) (model.Group, error) {
+	group, err := s.GetByID(id)
+	if err != nil {
+		return model.Group{}, err
+	}
+	if group.Status != model.GroupActive {
+		return model.Group{}, invalidTransition("invalid_transition", "group is %s, only ACTIVE groups can be released", group.Status)
+	}
+	if err := s.groups.ReleaseGroup(id, s.now().UTC(), actor); err != nil {
+		return model.Group{}, mapGroupStatusChanged(err)
+	}
+	return s.GetByID(id)
+}
+
+func (s *groupService) ReleaseExpired(now time.Time) (int, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	groups, err := s.groups.GetGroupsPastCutoff(today)
+	if err != nil {
+		return 0, err
+	}
+	released := 0
+	for _, group := range groups {
+		// o grupo liberado ou cancelado entre a busca e o update é ignorado
+		err := s.groups.ReleaseGroup(group.ID, now.UTC(), model.SystemActor)
+		if errors.Is(err, dao.ErrGroupStatusChanged) {
+			continue
+		}
+		if err != nil {
+			return released, err
+		}
+		released++
+	}
+	return released, nil
+}
+
+// ---------------- CANCEL ----------------
+func (s *groupService) Cancel(id, reason string, actor model.Actor) (model.GroupCancellation, error) {
+	if len(reason) > 200 {
+		var v model.ValidationError
+		v.Add("reason", "invalid_length", "must have at most 200 characters")
+		return model.GroupCancellation{}, v.Err()
+	}
+	group, err := s.GetByID(id)
+	if err != nil {
+		return model.GroupCancellation{}, err
+	}
+
+	// 1. Grupo: a partir daqui nenhuma reserva nova entra no grupo
+	if group.Status != model.GroupCanceled {
+		err := s.groups.CancelGroup(id, s.now().UTC(), reason, actor)
+		if err != nil && !errors.Is(err, dao.ErrGroupStatusChanged) {
+			return model.GroupCancellation{}, err
+		}
+	}
+
+	// 2. Reservas que ainda esperam o hóspede
+	reservations, err := s.groups.GetGroupReservations(id)
+	if err != nil {
+		return model.GroupCancellation{}, err
+	}
+	result := model.GroupCancellation{Canceled: []string{}, Kept: []string{}}
+	for _, res := range reservations {
+		switch {
+		case awaitingArrival(res.Status):
+			if _, err := s.reservations.Cancel(res.ID, reason, actor); err != nil {
+				return model.GroupCancellation{}, err
+			}
+			result.Canceled = append(result.Canceled, res.ID)
+		case res.Status == "CHECKED_IN" || res.Status == "CHECKED_OUT":
+			result.Kept = append(result.Kept, res.ID)
+		}
+	}
+	if result.Group, err = s.GetByID(id); err != nil {
+		return model.GroupCancellation{}, err
+	}
+	return result, nil
+}
+
+// ---------------- FOLIO ----------------
+func (s *groupService) Folio(id string) (model.GroupFolio, error) {
+	folio, err := s.groups.GetGroupFolio(id)
+	if err != nil {
+		return model.GroupFolio{}, err
+	}
+	if folio.GroupID == "" {
+		return model.GroupFolio{}, notFound("group_not_found", "group %s not found", id)
+	}
+	if folio.Charges == nil {
+		folio.Charges = []model.FolioCharge{}
+	}
+	if folio.Payments == nil {
+		folio.Payments = []model.Payment{}
+	}
+	folio.Currency = model.Currency()
+	folio.TotalCharges = model.NewMoney(0)
+	for _, charge := range folio.Charges {
+		folio.TotalCharges = folio.TotalCharges.Add(charge.Amount)
+	}
+	folio.TotalPaid = totalPaid(folio.Payments)
+	folio.Balance = folio.TotalCharges.Sub(folio.TotalPaid)
+	return folio, nil
+}
+
+func (s *groupService) PostPayment(id string, req model.PaymentRequest, actor model.Actor) (model.Payment, error) {
+	if err := req.Validate(); err != nil {
+		return model.Payment{}, err
+	}
+	if req.AuthorizationID != "" {
+		var v model.ValidationError
+		v.Add("authorization_id", "not_allowed", "group payments are not made through the gateway")
+		return model.Payment{}, v.Err()
+	}
+	if _, err := s.GetByID(id); err != nil {
+		return model.Payment{}, err
+	}
+
+	payment := model.Payment{
+		GroupID:   id,
+		Type:      req.Type,
+		Method:    req.Method,
+		Amount:    req.Amount,
+		Reference: req.Reference,
+		PostedAt:  s.now(),
+		PostedBy:  actor.Username,
+	}
+	if payment.Type == "" {
+		payment.Type = model.PaymentTypePayment
+	}
+
+	id, err := s.groups.PostGroupPayment(payment, actor)
+	switch {
+	case errors.Is(err, dao.ErrRefundExceedsPaid):
+		return model.Payment{}, conflict("refund_exceeds_paid", "%s", err.Error())
+	case errors.Is(err, dao.ErrGroupStatusChanged):
+		return model.Payment{}, notFound("group_not_found", "group %s not found", payment.GroupID)
+	case err != nil:
+		return model.Payment{}, err
+	}
+	payment.ID = id
+	return payment, nil
+}
+
+// ---------------- HELPERS ----------------
+
+// mapGroupStatusChanged traduz a escrita recusada porque o grupo mudou de status entre a
+// leitura e o update
+func mapGroupStatusChanged(err error) error {
+	if errors.Is(err, dao.ErrGroupStatusChanged) {
+		return conflict("status_changed", "%s", err.Error())
+	}
+	return err
+}
@@ -0,0 +1,179 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"hotel-soa/dao"
+	"hotel-soa/gateway"
+	"hotel-soa/model"
+)
+
+// newTestGroupRequest monta um grupo de 10 a 13 com cutoff em 1º de junho
+func newTestGroupRequest(blocks ...model.GroupBlock) model.Group {
+	return model.Group{Name: "Congresso", ContactName: "Ana", CheckinExpected: "2026-06-10", CheckoutExpected: "2026-06-13", CutoffDate: "2026-06-01", Blocks: blocks}
+}
+
+func TestGroupPickUpAndFolio(t *testing.T) {
+	repos := dao.NewMemoryRepositories()
+	insertRooms(t, repos,
+		model.Room{Number: 201, Type: "DELUXE", Capacity: 2, PricePerNight: model.NewMoney(25000), Status: "ATIVO"},
+		model.Room{Number: 202, Type: "DELUXE", Capacity: 2, PricePerNight: model.NewMoney(25000), Status: "ATIVO"},
+	)
+	reservations := newStayTestService(repos, date("2026-05-20").Add(12*time.Hour))
+	groups := NewGroupService(repos.Groups, reservations).(*groupService)
+	groups.now = reservations.now
+
+	if _, err := groups.Create(newTestGroupRequest(), testActor); !isValidationError(err) {
+		t.Fatalf("expected a validation error without blocks, got %v", err)
+	}
+	group, err := groups.Create(newTestGroupRequest(model.GroupBlock{RoomType: "DELUXE", Rooms: 1}), testActor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if group.Status != model.GroupActive || group.CreatedBy != testActor.Username {
+		t.Fatalf("expected an ACTIVE group, got %+v", group)
+	}
+
+	// sem tipo e datas, vale o único tipo do bloco e a estadia do grupo
+	res, err := groups.PickUp(group.ID, model.GroupPickupRequest{GuestName: "João"}, testActor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.GroupID != group.ID || res.RoomType != "DELUXE" || res.CheckinExpected != "2026-06-10" || res.CheckoutExpected != "2026-06-13" || !res.TotalAmount.Equal(model.NewMoney(75000)) {
+		t.Fatalf("expected a group reservation for the block's stay, got %+v", res)
+	}
+	if _, err := groups.PickUp(group.ID, model.GroupPickupRequest{GuestName: "João", RoomType: "STANDARD"}, testActor); !isValidationError(err) {
+		t.Fatalf("expected a validation error for a type outside the block, got %v", err)
+	}
+	if _, err := groups.PickUp("00000000-0000-0000-0000-000000000000", model.GroupPickupRequest{GuestName: "João"}, testActor); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+	if group, _ = groups.GetByID(group.ID); group.Blocks[0].PickedUp != 1 {
+		t.Fatalf("expected one room picked up, got %+v", group.Blocks)
+	}
+
+	// as noites vão para a conta master; a conta da reserva fica sem saldo
+	master, err := groups.Folio(group.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(master.Charges) != 3 || !master.TotalCharges.Equal(model.NewMoney(75000)) || !master.Balance.Equal(model.NewMoney(75000)) {
+		t.Fatalf("expected the three nights in the master folio, got %+v", master)
+	}
+	folio, err := NewFolioService(repos.Folios, repos.Reservations, gateway.NewStubGateway()).Get(res.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if folio.GroupID != group.ID || len(folio.Charges) != 3 || !folio.TotalCharges.IsZero() || !folio.Balance.IsZero() {
+		t.Fatalf("expected the nights out of the reservation totals, got %+v", folio)
+	}
+
+	if _, err := groups.PostPayment(group.ID, model.PaymentRequest{Method: "TRANSFER", Amount: model.NewMoney(75000)}, testActor); err != nil {
+		t.Fatal(err)
+	}
+	if master, _ := groups.Folio(group.ID); !master.Balance.IsZero() || !master.TotalPaid.Equal(model.NewMoney(75000)) {
+		t.Fatalf("expected a settled master folio, got %+v", master)
+	}
+	refund := model.PaymentRequest{Type: model.PaymentTypeRefund, Method: "TRANSFER", Amount: model.NewMoney(75001)}
+	if _, err := groups.PostPayment(group.ID, refund, testActor); !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected ErrConflict refunding more than paid, got %v", err)
+	}
+	refund.Amount, refund.AuthorizationID = model.NewMoney(100), "00000000-0000-0000-0000-000000000000"
+	if _, err := groups.PostPayment(group.ID, refund, testActor); !isValidationError(err) {
+		t.Fatalf("expected a validation error for a gateway refund, got %v", err)
+	}
+
+	// o check-out da reserva do grupo não cobra as noites de novo
+	reservations.now = func() time.Time { return date("2026-06-10").Add(14 * time.Hour) }
+	if _, err := reservations.CheckIn(res.ID, "maria", "", testActor); err != nil {
+		t.Fatal(err)
+	}
+	reservations.now = func() time.Time { return date("2026-06-13").Add(10 * time.Hour) }
+	if _, err := reservations.CheckOut(res.ID, "joao", testActor); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGroupReleaseAndCancel(t *testing.T) {
+	repos := dao.NewMemoryRepositories()
+	insertRooms(t, repos,
+		model.Room{Number: 201, Type: "DELUXE", Capacity: 2, PricePerNight: model.NewMoney(25000), Status: "ATIVO"},
+		model.Room{Number: 202, Type: "DELUXE", Capacity: 2, PricePerNight: model.NewMoney(25000), Status: "ATIVO"},
+		model.Room{Number: 203, Type: "DELUXE", Capacity: 2, PricePerNight: model.NewMoney(25000), Status: "ATIVO"},
+	)
+	reservations := newStayTestService(repos, date("2026-05-20").Add(12*time.Hour))
+	groups := NewGroupService(repos.Groups, reservations).(*groupService)
+	groups.now = reservations.now
+
+	group, err := groups.Create(newTestGroupRequest(model.GroupBlock{RoomType: "DELUXE", Rooms: 2}), testActor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	late := newTestGroupRequest(model.GroupBlock{RoomType: "DELUXE", Rooms: 1})
+	late.CheckinExpected, late.CheckoutExpected, late.CutoffDate = "2026-07-10", "2026-07-12", "2026-07-01"
+	lateGroup, err := groups.Create(late, testActor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// os dois quartos do bloco saem do estoque: sobra um DELUXE para os demais
+	var soldOut *model.RoomTypeSoldOutError
+	if _, err := groups.Create(newTestGroupRequest(model.GroupBlock{RoomType: "DELUXE", Rooms: 2}), testActor); !errors.As(err, &soldOut) {
+		t.Fatalf("expected a RoomTypeSoldOutError, got %v", err)
+	}
+
+	// só o grupo com o cutoff vencido é liberado
+	released, err := groups.ReleaseExpired(date("2026-06-02").Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if released != 1 {
+		t.Fatalf("expected one group released, got %d", released)
+	}
+	if group, _ = groups.GetByID(group.ID); group.Status != model.GroupReleased || group.ReleasedAt == nil {
+		t.Fatalf("expected a RELEASED group, got %+v", group)
+	}
+	if _, err := groups.Release(group.ID, testActor); !errors.Is(err, ErrInvalidTransition) {
+		t.Fatalf("expected ErrInvalidTransition releasing twice, got %v", err)
+	}
+	if lateGroup, err = groups.Release(lateGroup.ID, testActor); err != nil || lateGroup.Status != model.GroupReleased {
+		t.Fatalf("expected the manual release, got %+v (%v)", lateGroup, err)
+	}
+
+	// depois do release as reservas do grupo passam pelo estoque do tipo
+	arrived, err := groups.PickUp(group.ID, model.GroupPickupRequest{GuestName: "João"}, testActor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	waiting, err := groups.PickUp(group.ID, model.GroupPickupRequest{GuestName: "Maria", CheckinExpected: "2026-06-11"}, testActor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reservations.now = func() time.Time { return date("2026-06-10").Add(14 * time.Hour) }
+	if _, err := reservations.CheckIn(arrived.ID, "maria", "", testActor); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := groups.Cancel(group.ID, strings.Repeat("x", 201), testActor); !isValidationError(err) {
+		t.Fatalf("expected a validation error for a long reason, got %v", err)
+	}
+	result, err := groups.Cancel(group.ID, "evento adiado", testActor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Group.Status != model.GroupCanceled || len(result.Canceled) != 1 || result.Canceled[0] != waiting.ID || len(result.Kept) != 1 || result.Kept[0] != arrived.ID {
+		t.Fatalf("expected the waiting reservation canceled and the arrived one kept, got %+v", result)
+	}
+	if stored, _ := reservations.GetByID(waiting.ID); stored.Status != "CANCELED" {
+		t.Fatalf("expected a CANCELED reservation, got %+v", stored)
+	}
+	// repetido, o cancelamento não falha e não encontra mais nada para cancelar
+	if again, err := groups.Cancel(group.ID, "", testActor); err != nil || len(again.Canceled) != 0 {
+		t.Fatalf("expected nothing left to cancel, got %+v (%v)", again, err)
+	}
+	if _, err := groups.PickUp(group.ID, model.GroupPickupRequest{GuestName: "Bia"}, testActor); !isServiceError(err, "group_canceled") {
+		t.Fatalf("expected group_canceled, got %v", err)
+	}
+}
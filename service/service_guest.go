@@ -0,0 +1,75 @@
+package service
+
+import (
+	"errors"
+	"hotel-soa/dao"
+	"hotel-soa/model"
+)
+
+type GuestService interface {
+	Create(guest model.Guest) (string, error)
+	Update(guest model.Guest) error
+	Delete(id string) error
+	GetByID(id string) (model.Guest, error)
+	GetAll() ([]model.Guest, error)
+	GetReservations(id string) ([]model.Reservation, error)
+}
+
+type guestService struct {
+	guests       dao.GuestRepository
+	reservations dao.ReservationRepository
+}
+
+func NewGuestService(guests dao.GuestRepository, reservations dao.ReservationRepository) GuestService {
+	return &guestService{guests: guests, reservations: reservations}
+}
+
+func (s *guestService) Create(guest model.Guest) (string, error) {
+	id, err := s.guests.InsertGuest(guest)
+	if err != nil {
+		return "", mapDuplicateDocument(err)
+	}
+	return id, nil
+}
+
+func (s *guestService) Update(guest model.Guest) error {
+	if _, err := s.GetByID(guest.ID); err != nil {
+		return err
+	}
+	return mapDuplicateDocument(s.guests.UpdateGuest(guest))
+}
+
+func (s *guestService) Delete(id string) error {
+	return s.guests.DeleteGuest(id)
+}
+
+func (s *guestService) GetByID(id string) (model.Guest, error) {
+	guest, err := s.guests.GetGuestByID(id)
+	if err != nil {
+		return model.Guest{}, err
+	}
+	if guest.ID == "" {
+		return model.Guest{}, notFound("guest_not_found", "guest %s not found", id)
+	}
+	return guest, nil
+}
+
+func (s *guestService) GetAll() ([]model.Guest, error) {
+	return s.guests.GetAllGuests()
+}
+
+// GetReservations retorna o histórico de estadias do hóspede, da mais recente para a mais antiga
+func (s *guestService) GetReservations(id string) ([]model.Reservation, error) {
+	if _, err := s.GetByID(id); err != nil {
+		return nil, err
+	}
+	return s.reservations.GetReservationsByGuest(id)
+}
+
+// mapDuplicateDocument traduz a violação de unicidade de document_number
+func mapDuplicateDocument(err error) error {
+	if errors.Is(err, dao.ErrDuplicateDocument) {
+		return conflict("duplicate_document", "%s", err.Error())
+	}
+	return err
+}
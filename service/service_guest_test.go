@@ -0,0 +1,90 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"hotel-soa/dao"
+	"hotel-soa/gateway"
+	"hotel-soa/model"
+)
+
+func TestReservationGuest(t *testing.T) {
+	repos := dao.NewMemoryRepositories()
+	roomID, err := repos.Rooms.InsertRoom(model.Room{Number: 101, Type: "STANDARD", Capacity: 2, PricePerNight: model.NewMoney(10000), Status: "ATIVO"}, testActor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	anaID, err := repos.Guests.InsertGuest(model.Guest{Name: "Ana Souza", DocumentNumber: "AB123"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	reservations := NewReservationService(repos.Reservations, repos.Rooms, repos.RatePlans, repos.Guests, repos.Policies, repos.Taxes, repos.Folios, repos.Holds, gateway.NewStubGateway(), 30)
+	stay := func(checkin, checkout string) model.Reservation {
+		return model.Reservation{RoomID: roomID, CheckinExpected: checkin, CheckoutExpected: checkout}
+	}
+
+	// com guest_id o nome vem do cadastro
+	res := stay("2026-06-01", "2026-06-03")
+	res.GuestID, res.GuestName = anaID, "Someone Else"
+	booked, err := reservations.Create(res, "", nil, testActor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if booked.GuestID != anaID || booked.GuestName != "Ana Souza" {
+		t.Fatalf("expected the guest record name, got %+v", booked)
+	}
+
+	// só com o nome um hóspede novo é cadastrado
+	res = stay("2026-06-05", "2026-06-07")
+	res.GuestName = "  Bruno Lima "
+	walkIn, err := reservations.Create(res, "", nil, testActor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	guest, _ := repos.Guests.GetGuestByID(walkIn.GuestID)
+	if walkIn.GuestID == "" || walkIn.GuestID == anaID || guest.Name != "Bruno Lima" {
+		t.Fatalf("expected a new guest named Bruno Lima, got %+v and %+v", walkIn, guest)
+	}
+
+	// no update, sem guest_id e com o mesmo nome, o hóspede é mantido
+	walkIn.GuestID = ""
+	updated, err := reservations.Update(walkIn, nil, testActor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.GuestID != guest.ID {
+		t.Fatalf("expected guest %s to be kept, got %+v", guest.ID, updated)
+	}
+
+	res = stay("2026-06-10", "2026-06-12")
+	res.GuestID = "missing"
+	if _, err := reservations.Create(res, "", nil, testActor); !isValidationError(err) {
+		t.Fatalf("expected a validation error for an unknown guest, got %v", err)
+	}
+	if _, err := reservations.Create(stay("2026-06-10", "2026-06-12"), "", nil, testActor); !isValidationError(err) {
+		t.Fatalf("expected a validation error without guest, got %v", err)
+	}
+}
+
+func TestGuestService(t *testing.T) {
+	repos := dao.NewMemoryRepositories()
+	guests := NewGuestService(repos.Guests, repos.Reservations)
+
+	id, err := guests.Create(model.Guest{Name: "Ana", DocumentNumber: "AB123"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := guests.Create(model.Guest{Name: "Other Ana", DocumentNumber: "AB123"}); !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected ErrConflict for a duplicate document, got %v", err)
+	}
+	if err := guests.Update(model.Guest{ID: "missing", Name: "Nobody"}); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound updating an unknown guest, got %v", err)
+	}
+	if _, err := guests.GetReservations("missing"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound for the history of an unknown guest, got %v", err)
+	}
+	if history, err := guests.GetReservations(id); err != nil || len(history) != 0 {
+		t.Fatalf("expected an empty history, got %v %v", history, err)
+	}
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hotel-soa/dao"
+	"hotel-soa/model"
+	"time"
+)
+
+// holdTokenPrefix identifica os tokens de bloqueio
+const holdTokenPrefix = "hold_"
+
+type HoldService interface {
+	// Create bloqueia o quarto no período por ttl_seconds (ou o TTL padrão) e devolve o
+	// token que converte o bloqueio em reserva; o token não pode ser lido de novo
+	Create(req model.HoldRequest, actor model.Actor) (model.RoomHoldCreated, error)
+	GetByID(id string) (model.RoomHold, error)
+	// Release libera o quarto antes do vencimento
+	Release(id string) error
+	// Sweep apaga os bloqueios vencidos até now e retorna quantos eram. Vencidos, eles já
+	// não bloqueiam nada; a limpeza só evita que se acumulem
+	Sweep(now time.Time) (int, error)
+}
+
+type holdService struct {
+	holds   dao.HoldRepository
+	rooms   dao.RoomRepository
+	pricing *pricingEngine
+	ttl     time.Duration
+	now     func() time.Time
+}
+
+// NewHoldService cria o HoldService; ttl é a validade de um bloqueio sem ttl_seconds
+func NewHoldService(holds dao.HoldRepository, rooms dao.RoomRepository, ratePlans dao.RatePlanRepository, ttl time.Duration) HoldService {
+	return &holdService{holds: holds, rooms: rooms, pricing: newPricingEngine(ratePlans), ttl: ttl, now: time.Now}
+}
+
+// ---------------- CREATE ----------------
+func (s *holdService) Create(req model.HoldRequest, actor model.Actor) (model.RoomHoldCreated, error) {
+	// 1. Validação de datas e da validade
+	checkin, checkout, err := parseStay("checkin_expected", req.CheckinExpected, "checkout_expected", req.CheckoutExpected)
+	if err != nil {
+		return model.RoomHoldCreated{}, err
+	}
+	if err := req.Validate(); err != nil {
+		return model.RoomHoldCreated{}, err
+	}
+	ttl := s.ttl
+	if req.TTLSeconds != 0 {
+		ttl = time.Duration(req.TTLSeconds) * time.Second
+	}
+
+	// 2. Quarto reservável e sem restrição do plano tarifário, para que o bloqueio possa
+	// virar reserva com as mesmas datas
+	room, err := s.rooms.GetRoomByID(req.RoomID)
+	if err != nil {
+		return model.RoomHoldCreated{}, err
+	}
+	if room.ID == "" {
+		var v model.ValidationError
+		v.Add("room_id", "not_found", fmt.Sprintf("room %s not found", req.RoomID))
+		return model.RoomHoldCreated{}, v.Err()
+	}
+	if room.DeletedAt != nil {
+		var v model.ValidationError
+		v.Add("room_id", "archived", fmt.Sprintf("room %d is archived", room.Number))
+		return model.RoomHoldCreated{}, v.Err()
+	}
+	// o check-in não usa quarto INATIVO
+	if room.Status != "ATIVO" {
+		return model.RoomHoldCreated{}, conflict("room_inactive", "room %d is inactive", room.Number)
+	}
+	if _, _, err := s.pricing.Quote(room, checkin, checkout); err != nil {
+		return model.RoomHoldCreated{}, err
+	}
+
+	// 3. Persistência: conflitos com reservas e outros bloqueios são checados na mesma
+	// transação do insert
+	now := s.now().UTC()
+	token := holdTokenPrefix + randomHex(24)
+	hold := model.RoomHold{
+		RoomID:           req.RoomID,
+		CheckinExpected:  checkin.Format("2006-01-02"),
+		CheckoutExpected: checkout.Format("2006-01-02"),
+		TokenHash:        hashHoldToken(token),
+		ExpiresAt:        now.Add(ttl),
+		CreatedAt:        now,
+		CreatedBy:        actor.Username,
+	}
+	id, err := s.holds.InsertHold(hold)
+	if err != nil {
+		return model.RoomHoldCreated{}, err
+	}
+	hold.ID = id
+	return model.RoomHoldCreated{RoomHold: hold, Token: token}, nil
+}
+
+// ---------------- GET BY ID ----------------
+
+// GetByID trata um bloqueio vencido, ainda não apagado, como inexistente
+func (s *holdService) GetByID(id string) (model.RoomHold, error) {
+	hold, err := s.holds.GetHoldByID(id)
+	if err != nil {
+		return model.RoomHold{}, err
+	}
+	if hold.ID == "" || !hold.Active(s.now()) {
+		return model.RoomHold{}, notFound("hold_not_found", "hold %s not found or expired", id)
+	}
+	return hold, nil
+}
+
+// ---------------- RELEASE ----------------
+func (s *holdService) Release(id string) error {
+	released, err := s.holds.ReleaseHold(id)
+	if err != nil {
+		return err
+	}
+	if !released {
+		return notFound("hold_not_found", "hold %s not found or expired", id)
+	}
+	return nil
+}
+
+// ---------------- SWEEP ----------------
+func (s *holdService) Sweep(now time.Time) (int, error) {
+	return s.holds.DeleteExpiredHolds(now)
+}
+
+// ---------------- HELPERS ----------------
+
+// hashHoldToken é o que fica guardado do token, como nas API keys
+func hashHoldToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
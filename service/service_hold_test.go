@@ -0,0 +1,192 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"hotel-soa/dao"
+	"hotel-soa/model"
+)
+
+func TestHoldCreateTTLAndToken(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		ttlSeconds int
+		expires    time.Time
+		invalid    bool
+	}{
+		{"default ttl", 0, now.Add(10 * time.Minute), false},
+		{"shortest ttl", 60, now.Add(time.Minute), false},
+		{"longest ttl", 3600, now.Add(time.Hour), false},
+		{"ttl too short", 59, time.Time{}, true},
+		{"ttl too long", 3601, time.Time{}, true},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repos := dao.NewMemoryRepositories()
+			roomID, err := repos.Rooms.InsertRoom(model.Room{Number: 100 + i, Type: "STANDARD", Capacity: 2, PricePerNight: model.NewMoney(10000), Status: "ATIVO"}, model.SystemActor)
+			if err != nil {
+				t.Fatal(err)
+			}
+			holds := NewHoldService(repos.Holds, repos.Rooms, repos.RatePlans, 10*time.Minute).(*holdService)
+			holds.now = func() time.Time { return now }
+
+			created, err := holds.Create(model.HoldRequest{RoomID: roomID, CheckinExpected: "2026-06-01", CheckoutExpected: "2026-06-03", TTLSeconds: tt.ttlSeconds}, model.SystemActor)
+			if tt.invalid {
+				var validation *model.ValidationError
+				if !errors.As(err, &validation) {
+					t.Fatalf("expected a validation error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !created.ExpiresAt.Equal(tt.expires) {
+				t.Fatalf("expected expires_at %s, got %s", tt.expires, created.ExpiresAt)
+			}
+			// só o hash do token é guardado
+			if !strings.HasPrefix(created.Token, holdTokenPrefix) || created.TokenHash != hashHoldToken(created.Token) || created.TokenHash == created.Token {
+				t.Fatalf("unexpected token %q with hash %q", created.Token, created.TokenHash)
+			}
+
+			// o bloqueio vale até expires_at, exclusive
+			holds.now = func() time.Time { return tt.expires.Add(-time.Second) }
+			if _, err := holds.GetByID(created.ID); err != nil {
+				t.Fatalf("expected the hold to be active, got %v", err)
+			}
+			holds.now = func() time.Time { return tt.expires }
+			if _, err := holds.GetByID(created.ID); !errors.Is(err, ErrNotFound) {
+				t.Fatalf("expected the expired hold to be not found, got %v", err)
+			}
+		})
+	}
+}
+
+func TestHoldConversion(t *testing.T) {
+	repos := dao.NewMemoryRepositories()
+	roomID, err := repos.Rooms.InsertRoom(model.Room{Number: 101, Type: "STANDARD", Capacity: 2, PricePerNight: model.NewMoney(10000), Status: "ATIVO"}, model.SystemActor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherID, err := repos.Rooms.InsertRoom(model.Room{Number: 102, Type: "STANDARD", Capacity: 2, PricePerNight: model.NewMoney(10000), Status: "ATIVO"}, model.SystemActor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a validade do bloqueio é conferida no relógio real pelo repositório
+	now := time.Now().UTC()
+	holds := NewHoldService(repos.Holds, repos.Rooms, repos.RatePlans, 10*time.Minute).(*holdService)
+	holds.now = func() time.Time { return now }
+	reservations := newStayTestService(repos, now)
+
+	request := model.HoldRequest{RoomID: roomID, CheckinExpected: "2026-06-10", CheckoutExpected: "2026-06-12"}
+	hold, err := holds.Create(request, testActor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var held *model.RoomHeldError
+	if _, err := holds.Create(request, testActor); !errors.As(err, &held) || held.HoldID != hold.ID {
+		t.Fatalf("expected a RoomHeldError for %s, got %v", hold.ID, err)
+	}
+	stay := model.Reservation{RoomID: roomID, GuestName: "Ana", CheckinExpected: "2026-06-10", CheckoutExpected: "2026-06-12"}
+	if _, err := reservations.Create(stay, "", nil, testActor); !errors.As(err, &held) {
+		t.Fatalf("expected a RoomHeldError without the token, got %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		res   model.Reservation
+		token string
+		check func(err error) bool
+	}{
+		{"unknown token", stay, "hold_unknown", func(err error) bool { return errors.Is(err, ErrConflict) }},
+		{"other room", model.Reservation{RoomID: otherID, GuestName: "Ana", CheckinExpected: "2026-06-10", CheckoutExpected: "2026-06-12"}, hold.Token, isValidationError},
+		{"other dates", model.Reservation{RoomID: roomID, GuestName: "Ana", CheckinExpected: "2026-06-10", CheckoutExpected: "2026-06-13"}, hold.Token, isValidationError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := reservations.Create(tt.res, tt.token, nil, testActor); !tt.check(err) {
+				t.Fatalf("unexpected error %v", err)
+			}
+		})
+	}
+
+	res, err := reservations.Create(stay, hold.Token, nil, testActor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.ID == "" || res.Status != "CREATED" {
+		t.Fatalf("expected a CREATED reservation, got %+v", res)
+	}
+	if _, err := holds.GetByID(hold.ID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected the hold to be consumed, got %v", err)
+	}
+	// o token só vale uma vez
+	stay.CheckinExpected, stay.CheckoutExpected = "2026-06-20", "2026-06-22"
+	if _, err := reservations.Create(stay, hold.Token, nil, testActor); !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected ErrConflict reusing the token, got %v", err)
+	}
+}
+
+func TestHoldReleaseAndSweep(t *testing.T) {
+	repos := dao.NewMemoryRepositories()
+	roomID, err := repos.Rooms.InsertRoom(model.Room{Number: 101, Type: "STANDARD", Capacity: 2, PricePerNight: model.NewMoney(10000), Status: "ATIVO"}, model.SystemActor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	archived, err := repos.Rooms.InsertRoom(model.Room{Number: 102, Type: "STANDARD", Capacity: 2, PricePerNight: model.NewMoney(10000), Status: "ATIVO"}, model.SystemActor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := repos.Rooms.ArchiveRoom(archived, time.Now(), testActor); err != nil {
+		t.Fatal(err)
+	}
+	holds := NewHoldService(repos.Holds, repos.Rooms, repos.RatePlans, 10*time.Minute)
+
+	for _, req := range []model.HoldRequest{
+		{RoomID: "00000000-0000-0000-0000-000000000000", CheckinExpected: "2026-06-10", CheckoutExpected: "2026-06-12"},
+		{RoomID: archived, CheckinExpected: "2026-06-10", CheckoutExpected: "2026-06-12"},
+		{RoomID: roomID, CheckinExpected: "2026-06-12", CheckoutExpected: "2026-06-10"},
+	} {
+		if _, err := holds.Create(req, testActor); !isValidationError(err) {
+			t.Fatalf("%+v: expected a validation error, got %v", req, err)
+		}
+	}
+
+	hold, err := holds.Create(model.HoldRequest{RoomID: roomID, CheckinExpected: "2026-06-10", CheckoutExpected: "2026-06-12"}, testActor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := holds.Release(hold.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := holds.Release(hold.ID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound releasing twice, got %v", err)
+	}
+
+	if _, err := holds.Create(model.HoldRequest{RoomID: roomID, CheckinExpected: "2026-06-10", CheckoutExpected: "2026-06-12", TTLSeconds: 60}, testActor); err != nil {
+		t.Fatal(err)
+	}
+	if swept, err := holds.Sweep(time.Now()); err != nil || swept != 0 {
+		t.Fatalf("expected nothing to sweep yet, got %d, %v", swept, err)
+	}
+	if swept, err := holds.Sweep(time.Now().Add(2 * time.Minute)); err != nil || swept != 1 {
+		t.Fatalf("expected 1 expired hold, got %d, %v", swept, err)
+	}
+}
+
+func TestHoldCreateInactiveRoom(t *testing.T) {
+	repos := dao.NewMemoryRepositories()
+	roomID, err := repos.Rooms.InsertRoom(model.Room{Number: 100, Type: "STANDARD", Capacity: 2, PricePerNight: model.NewMoney(10000), Status: "INATIVO"}, model.SystemActor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	holds := NewHoldService(repos.Holds, repos.Rooms, repos.RatePlans, 10*time.Minute)
+	_, err = holds.Create(model.HoldRequest{RoomID: roomID, CheckinExpected: "2026-06-01", CheckoutExpected: "2026-06-03"}, model.SystemActor)
+	if !isServiceError(err, "room_inactive") {
+		t.Fatalf("expected room_inactive, got %v", err)
+	}
+}
@@ -0,0 +1,215 @@
+package service
+
+import (
+	"errors"
+	"hotel-soa/dao"
+	"hotel-soa/model"
+	"math"
+	"time"
+)
+
+type NightAuditService interface {
+	// Run fecha a data de negócio; vazia fecha o próximo dia em aberto. Repetir uma data
+	// já fechada devolve o fechamento gravado com closed=false
+	Run(businessDate string, actor model.Actor) (audit model.NightAudit, closed bool, err error)
+	GetByDate(date string) (model.NightAudit, error)
+	GetAll() ([]model.NightAudit, error)
+}
+
+type nightAuditService struct {
+	audits       dao.NightAuditRepository
+	reservations dao.ReservationRepository
+	rooms        dao.RoomRepository
+	now          func() time.Time
+}
+
+func NewNightAuditService(audits dao.NightAuditRepository, reservations dao.ReservationRepository, rooms dao.RoomRepository) NightAuditService {
+	return &nightAuditService{
+		audits:       audits,
+		reservations: reservations,
+		rooms:        rooms,
+		now:          time.Now,
+	}
+}
+
+func (s *nightAuditService) Run(businessDate string, actor model.Actor) (model.NightAudit, bool, error) {
+	// 1. Data de negócio: a informada ou o dia seguinte ao último fechamento
+	last, err := s.audits.GetLastClosedDate()
+	if err != nil {
+		return model.NightAudit{}, false, err
+	}
+	date, err := s.resolveDate(businessDate, last)
+	if err != nil {
+		return model.NightAudit{}, false, err
+	}
+	day := date.Format("2006-01-02")
+
+	// 2. Idempotência: a data já fechada devolve o que foi gravado
+	if audit, err := s.audits.GetNightAudit(day); err != nil || audit.BusinessDate != "" {
+		return audit, false, err
+	}
+
+	// 3. Os dias são fechados em sequência e nunca antes de começarem
+	if date.After(s.now().UTC()) {
+		var v model.ValidationError
+		v.Add("business_date", "out_of_range", "must not be in the future")
+		return model.NightAudit{}, false, v.Err()
+	}
+	if last != "" {
+		next := addDays(last, 1)
+		if day < last {
+			return model.NightAudit{}, false, conflict("business_date_closed", "business dates up to %s are already closed", last)
+		}
+		if day > next {
+			return model.NightAudit{}, false, conflict("night_audit_out_of_order", "business date %s must be closed first", next)
+		}
+	}
+
+	// 4. Diárias e resumo do dia
+	now := s.now()
+	audit, charges, err := s.close(date, now)
+	if err != nil {
+		return model.NightAudit{}, false, err
+	}
+	audit.ClosedAt, audit.ClosedBy = now, actor.Username
+
+	// 5. Persistência: outra execução pode ter fechado a data desde a leitura
+	err = s.audits.CloseBusinessDate(audit, charges)
+	if errors.Is(err, dao.ErrBusinessDateClosed) {
+		stored, err := s.audits.GetNightAudit(day)
+		if err != nil {
+			return model.NightAudit{}, false, err
+		}
+		if stored.BusinessDate == "" {
+			return model.NightAudit{}, false, conflict("business_date_closed", "a later business date was closed concurrently")
+		}
+		return stored, false, nil
+	}
+	if err != nil {
+		return model.NightAudit{}, false, err
+	}
+	return audit, true, nil
+}
+
+func (s *nightAuditService) GetByDate(date string) (model.NightAudit, error) {
+	if _, err := parseBusinessDate(date); err != nil {
+		return model.NightAudit{}, err
+	}
+	audit, err := s.audits.GetNightAudit(date)
+	if err != nil {
+		return model.NightAudit{}, err
+	}
+	if audit.BusinessDate == "" {
+		return model.NightAudit{}, notFound("night_audit_not_found", "business date %s has not been closed", date)
+	}
+	return audit, nil
+}
+
+func (s *nightAuditService) GetAll() ([]model.NightAudit, error) {
+	audits, err := s.audits.GetNightAudits()
+	if audits == nil {
+		audits = []model.NightAudit{}
+	}
+	return audits, err
+}
+
+// ---------------- HELPERS ----------------
+
+// resolveDate interpreta a data pedida; sem data, é o dia seguinte ao último fechamento
+// ou, no primeiro fechamento, o dia de hoje
+func (s *nightAuditService) resolveDate(businessDate, last string) (time.Time, error) {
+	if businessDate != "" {
+		return parseBusinessDate(businessDate)
+	}
+	if last != "" {
+		return parseBusinessDate(addDays(last, 1))
+	}
+	now := s.now().UTC()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC), nil
+}
+
+// close lança a diária das estadias em casa na noite de date e monta o resumo do dia.
+// Estão em casa as reservas CHECKED_IN, ou já CHECKED_OUT se o fechamento atrasou,
+// cuja estadia inclui a noite
+func (s *nightAuditService) close(date time.Time, postedAt time.Time) (model.NightAudit, []model.RoomCharge, error) {
+	day := date.Format("2006-01-02")
+	audit := model.NightAudit{BusinessDate: day}
+
+	stays, err := s.reservations.GetReservationsForDate(date)
+	if err != nil {
+		return audit, nil, err
+	}
+	var charges []model.RoomCharge
+	occupied := make(map[string]bool)
+	summary := &audit.Summary
+	summary.RoomRevenue, summary.ADR = model.NewMoney(0), model.NewMoney(0)
+	for _, res := range stays {
+		inHouse := res.Status == "CHECKED_IN" || res.Status == "CHECKED_OUT"
+		switch {
+		case inHouse && res.CheckinExpected == day:
+			summary.Arrivals++
+		case res.Status == "NO_SHOW" && res.CheckinExpected == day:
+			summary.NoShows++
+		}
+		if res.Status == "CHECKED_OUT" && res.CheckoutExpected == day {
+			summary.Departures++
+		}
+		if !inHouse || res.CheckoutExpected == day {
+			continue
+		}
+
+		charge := nightCharge(res, day)
+		charge.PostedAt = postedAt
+		charges = append(charges, charge)
+		occupied[res.RoomID] = true
+		summary.RoomRevenue = summary.RoomRevenue.Add(charge.Amount)
+	}
+
+	rooms, err := s.rooms.GetAllRooms()
+	if err != nil {
+		return audit, nil, err
+	}
+	for _, room := range rooms {
+		if room.Status == "ATIVO" {
+			summary.RoomsAvailable++
+		}
+	}
+	summary.RoomsOccupied = len(occupied)
+	if summary.RoomsAvailable > 0 {
+		summary.OccupancyRate = math.Round(float64(summary.RoomsOccupied)*10000/float64(summary.RoomsAvailable)) / 100
+	}
+	if summary.RoomsOccupied > 0 {
+		summary.ADR = summary.RoomRevenue.Div(int64(summary.RoomsOccupied), model.AverageRounding)
+	}
+	audit.ChargesPosted = len(charges)
+	return audit, charges, nil
+}
+
+// nightCharge é a diária da noite day; desde a migração 0016 toda reserva tem o
+// detalhamento por noite
+func nightCharge(res model.Reservation, day string) model.RoomCharge {
+	charge := model.RoomCharge{ReservationID: res.ID, BusinessDate: day, Amount: model.NewMoney(0)}
+	for _, night := range res.Nights {
+		if night.Date == day {
+			charge.Amount, charge.RatePlanID = night.Price, night.RatePlanID
+		}
+	}
+	return charge
+}
+
+// parseBusinessDate valida uma data de negócio no formato YYYY-MM-DD
+func parseBusinessDate(value string) (time.Time, error) {
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		var v model.ValidationError
+		v.Add("business_date", "invalid_format", "expected YYYY-MM-DD")
+		return time.Time{}, v.Err()
+	}
+	return date, nil
+}
+
+// addDays soma dias a uma data YYYY-MM-DD já validada
+func addDays(date string, days int) string {
+	t, _ := time.Parse("2006-01-02", date)
+	return t.AddDate(0, 0, days).Format("2006-01-02")
+}
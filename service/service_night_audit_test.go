@@ -0,0 +1,101 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"hotel-soa/dao"
+	"hotel-soa/model"
+)
+
+func TestNightAuditRun(t *testing.T) {
+	repos := dao.NewMemoryRepositories()
+	var roomIDs []string
+	for _, room := range []model.Room{
+		{Number: 101, Type: "STANDARD", Capacity: 2, PricePerNight: model.NewMoney(10000), Status: "ATIVO"},
+		{Number: 102, Type: "STANDARD", Capacity: 2, PricePerNight: model.NewMoney(15000), Status: "ATIVO"},
+		{Number: 103, Type: "STANDARD", Capacity: 2, PricePerNight: model.NewMoney(10000), Status: "ATIVO"},
+		{Number: 104, Type: "STANDARD", Capacity: 2, PricePerNight: model.NewMoney(10000), Status: "INATIVO"},
+	} {
+		id, err := repos.Rooms.InsertRoom(room, testActor)
+		if err != nil {
+			t.Fatal(err)
+		}
+		roomIDs = append(roomIDs, id)
+	}
+	reservations := newStayTestService(repos, date("2026-06-10").Add(15*time.Hour))
+	book := func(roomID, checkin, checkout string) model.Reservation {
+		t.Helper()
+		res, err := reservations.Create(model.Reservation{RoomID: roomID, GuestName: "Ana", CheckinExpected: checkin, CheckoutExpected: checkout}, "", nil, testActor)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res
+	}
+	arriving := book(roomIDs[0], "2026-06-10", "2026-06-12")
+	staying := book(roomIDs[1], "2026-06-09", "2026-06-11")
+	book(roomIDs[2], "2026-06-10", "2026-06-11") // não chegou
+	for _, id := range []string{arriving.ID, staying.ID} {
+		if err := repos.Reservations.CheckInReservation(id, date("2026-06-10"), "maria", testActor); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s := NewNightAuditService(repos.NightAudits, repos.Reservations, repos.Rooms).(*nightAuditService)
+	s.now = func() time.Time { return date("2026-06-11").Add(2 * time.Hour) }
+
+	if _, _, err := s.Run("2026-06-12", testActor); !isValidationError(err) {
+		t.Fatalf("expected a validation error for a future date, got %v", err)
+	}
+	if _, _, err := s.Run("11/06/2026", testActor); !isValidationError(err) {
+		t.Fatalf("expected a validation error for a bad date, got %v", err)
+	}
+
+	// o primeiro fechamento sem data fecha o dia de hoje; aqui, 10/06 é pedido explicitamente
+	audit, closed, err := s.Run("2026-06-10", model.Actor{Username: "maria"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := model.DailySummary{RoomsAvailable: 3, RoomsOccupied: 2, OccupancyRate: 66.67, RoomRevenue: model.NewMoney(25000), ADR: model.NewMoney(12500), Arrivals: 1}
+	if !closed || audit.ClosedBy != "maria" || audit.ChargesPosted != 2 || audit.Summary != expected {
+		t.Fatalf("expected %+v, got closed=%v %+v", expected, closed, audit)
+	}
+
+	// repetir a data devolve o fechamento gravado
+	again, closed, err := s.Run("2026-06-10", testActor)
+	if err != nil || closed || again.ClosedBy != "maria" {
+		t.Fatalf("expected the stored audit, got closed=%v %+v, %v", closed, again, err)
+	}
+
+	if _, _, err := s.Run("2026-06-09", testActor); !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected ErrConflict before the last closed date, got %v", err)
+	}
+
+	// sem data fecha o dia seguinte ao último fechamento
+	s.now = func() time.Time { return date("2026-06-13").Add(2 * time.Hour) }
+	if _, _, err := s.Run("2026-06-12", testActor); !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected ErrConflict skipping 2026-06-11, got %v", err)
+	}
+	next, closed, err := s.Run("", testActor)
+	if err != nil || !closed || next.BusinessDate != "2026-06-11" {
+		t.Fatalf("expected 2026-06-11 to be closed, got closed=%v %+v, %v", closed, next, err)
+	}
+
+	// a noite fechada não muda mais pelo PUT
+	moved, err := reservations.GetByID(arriving.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	moved.CheckinExpected = "2026-06-11"
+	if _, err := reservations.Update(moved, nil, testActor); !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected ErrConflict moving a closed night, got %v", err)
+	}
+
+	if _, err := s.GetByDate("2026-06-12"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound for an open date, got %v", err)
+	}
+	if audits, err := s.GetAll(); err != nil || len(audits) != 2 {
+		t.Fatalf("expected 2 audits, got %+v, %v", audits, err)
+	}
+}
@@ -0,0 +1,202 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"hotel-soa/dao"
+	"hotel-soa/model"
+	"sort"
+	"time"
+)
+
+// alertHorizon é o período padrão dos alertas de overbooking a partir de hoje
+const alertHorizon = 30
+
+// maxOverbookingRange limita o período do walk list e dos alertas
+const maxOverbookingRange = 92 * 24 * time.Hour
+
+type OverbookingService interface {
+	// Create e Update valem na hora para as reservas e bloqueios novos; o que já foi
+	// vendido acima de um limite reduzido aparece no walk list
+	Create(rule model.OverbookingRule) (string, error)
+	Update(rule model.OverbookingRule) error
+	Delete(id string) error
+	GetByID(id string) (model.OverbookingRule, error)
+	GetAll() ([]model.OverbookingRule, error)
+	// WalkList lista as noites entre from e to em que as reservas de um tipo passam dos
+	// quartos físicos, com as reservas a realocar
+	WalkList(from, to string) (model.WalkListReport, error)
+	// Alerts lista as noites entre from e to em que um tipo com overbooking chegou ao
+	// limite; sem datas, de hoje até alertHorizon dias depois
+	Alerts(from, to string) ([]model.RoomTypeNight, error)
+}
+
+type overbookingService struct {
+	rules        dao.OverbookingRepository
+	reservations dao.ReservationRepository
+	now          func() time.Time
+}
+
+func NewOverbookingService(rules dao.OverbookingRepository, reservations dao.ReservationRepository) OverbookingService {
+	return &overbookingService{rules: rules, reservations: reservations, now: time.Now}
+}
+
+func (s *overbookingService) Create(rule model.OverbookingRule) (string, error) {
+	id, err := s.rules.InsertOverbookingRule(rule)
+	if err != nil {
+		return "", mapOverbookingOverlap(err)
+	}
+	return id, nil
+}
+
+func (s *overbookingService) Update(rule model.OverbookingRule) error {
+	if _, err := s.GetByID(rule.ID); err != nil {
+		return err
+	}
+	return mapOverbookingOverlap(s.rules.UpdateOverbookingRule(rule))
+}
+
+func (s *overbookingService) Delete(id string) error {
+	if _, err := s.GetByID(id); err != nil {
+		return err
+	}
+	return s.rules.DeleteOverbookingRule(id)
+}
+
+func (s *overbookingService) GetByID(id string) (model.OverbookingRule, error) {
+	rule, err := s.rules.GetOverbookingRuleByID(id)
+	if err != nil {
+		return model.OverbookingRule{}, err
+	}
+	if rule.ID == "" {
+		return model.OverbookingRule{}, notFound("overbooking_rule_not_found", "overbooking rule %s not found", id)
+	}
+	return rule, nil
+}
+
+func (s *overbookingService) GetAll() ([]model.OverbookingRule, error) {
+	return s.rules.GetAllOverbookingRules()
+}
+
+func (s *overbookingService) WalkList(from, to string) (model.WalkListReport, error) {
+	start, end, err := parseOverbookingPeriod(from, to)
+	if err != nil {
+		return model.WalkListReport{}, err
+	}
+	nights, err := s.rules.GetRoomTypeNights(start, end)
+	if err != nil {
+		return model.WalkListReport{}, err
+	}
+
+	report := model.WalkListReport{From: from, To: to, Nights: []model.WalkListNight{}}
+	for _, night := range nights {
+		if night.Booked <= night.TotalRooms {
+			continue
+		}
+		date, _ := time.Parse("2006-01-02", night.Date)
+		bookings, err := s.reservations.GetRoomTypeBookings(night.RoomType, date, date.AddDate(0, 0, 1))
+		if err != nil {
+			return model.WalkListReport{}, err
+		}
+		excess := night.Booked - night.TotalRooms
+		report.Nights = append(report.Nights, model.WalkListNight{
+			Date:       night.Date,
+			RoomType:   night.RoomType,
+			TotalRooms: night.TotalRooms,
+			Booked:     night.Booked,
+			Excess:     excess,
+			Candidates: walkCandidates(bookings, excess),
+		})
+	}
+	return report, nil
+}
+
+func (s *overbookingService) Alerts(from, to string) ([]model.RoomTypeNight, error) {
+	if from == "" {
+		from = s.now().Format("2006-01-02")
+	}
+	if to == "" {
+		if start, err := time.Parse("2006-01-02", from); err == nil {
+			to = start.AddDate(0, 0, alertHorizon).Format("2006-01-02")
+		}
+	}
+	start, end, err := parseOverbookingPeriod(from, to)
+	if err != nil {
+		return nil, err
+	}
+	nights, err := s.rules.GetRoomTypeNights(start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	alerts := []model.RoomTypeNight{}
+	for _, night := range nights {
+		if night.Limit > night.TotalRooms && night.Booked+night.Held+night.Blocked >= night.Limit {
+			alerts = append(alerts, night)
+		}
+	}
+	return alerts, nil
+}
+
+// ---------------- HELPERS ----------------
+
+// mapOverbookingOverlap traduz a sobreposição com outra regra do mesmo tipo
+func mapOverbookingOverlap(err error) error {
+	if errors.Is(err, dao.ErrOverbookingRuleOverlap) {
+		return conflict("overbooking_rule_overlap", "%s", err.Error())
+	}
+	return err
+}
+
+// parseOverbookingPeriod valida from e to como parseReportPeriod, limitando o período a
+// maxOverbookingRange
+func parseOverbookingPeriod(from, to string) (time.Time, time.Time, error) {
+	start, end, err := parseReportPeriod(from, to)
+	if err != nil {
+		return start, end, err
+	}
+	if end.Sub(start) > maxOverbookingRange {
+		var v model.ValidationError
+		v.Add("to", "out_of_range", fmt.Sprintf("must be at most %d days after from", int(maxOverbookingRange.Hours()/24)))
+		return start, end, v.Err()
+	}
+	return start, end, nil
+}
+
+// walkCandidates escolhe até excess reservas a realocar entre as que ainda esperam o
+// hóspede; quem já fez check-in tem quarto. Primeiro as sem quarto atribuído, depois as
+// CREATED, sem depósito, antes das CONFIRMED, e por fim as de chegada mais tarde
+func walkCandidates(bookings []model.Reservation, excess int) []model.WalkCandidate {
+	var pending []model.Reservation
+	for _, res := range bookings {
+		if res.Status == "CREATED" || res.Status == "CONFIRMED" {
+			pending = append(pending, res)
+		}
+	}
+	sort.SliceStable(pending, func(i, j int) bool {
+		a, b := pending[i], pending[j]
+		if (a.RoomID == "") != (b.RoomID == "") {
+			return a.RoomID == ""
+		}
+		if a.Status != b.Status {
+			return a.Status == "CREATED"
+		}
+		if a.CheckinExpected != b.CheckinExpected {
+			return a.CheckinExpected > b.CheckinExpected
+		}
+		return a.ID < b.ID
+	})
+
+	candidates := []model.WalkCandidate{}
+	for _, res := range pending[:min(excess, len(pending))] {
+		candidates = append(candidates, model.WalkCandidate{
+			ReservationID:    res.ID,
+			GuestName:        res.GuestName,
+			Status:           res.Status,
+			RoomID:           res.RoomID,
+			CheckinExpected:  res.CheckinExpected,
+			CheckoutExpected: res.CheckoutExpected,
+		})
+	}
+	return candidates
+}
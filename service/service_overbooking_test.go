@@ -0,0 +1,112 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"hotel-soa/dao"
+	"hotel-soa/model"
+)
+
+func TestOverbookingService(t *testing.T) {
+	repos := dao.NewMemoryRepositories()
+	rooms := insertRooms(t, repos,
+		model.Room{Number: 201, Type: "DELUXE", Capacity: 2, PricePerNight: model.NewMoney(25000), Status: "ATIVO"},
+		model.Room{Number: 202, Type: "DELUXE", Capacity: 2, PricePerNight: model.NewMoney(25000), Status: "ATIVO"},
+	)
+	s := NewOverbookingService(repos.Overbooking, repos.Reservations).(*overbookingService)
+	s.now = func() time.Time { return date("2026-12-20").Add(9 * time.Hour) }
+
+	ruleID, err := s.Create(model.OverbookingRule{Name: "Réveillon", RoomType: "DELUXE", StartDate: "2026-12-31", EndDate: "2026-12-31", Percent: 50})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Create(model.OverbookingRule{Name: "Outra", RoomType: "DELUXE", StartDate: "2026-12-30", EndDate: "2027-01-01", Percent: 10}); !isServiceError(err, "overbooking_rule_overlap") {
+		t.Fatalf("expected overbooking_rule_overlap, got %v", err)
+	}
+	if _, err := s.GetByID("missing"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	// o quarto extra da regra deixa vender a terceira reserva da noite de 31
+	reservations := newStayTestService(repos, s.now())
+	book := func(res model.Reservation) model.Reservation {
+		t.Helper()
+		res.GuestName = "Ana"
+		created, err := reservations.Create(res, "", nil, testActor)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return created
+	}
+	book(model.Reservation{RoomID: rooms[201], CheckinExpected: "2026-12-31", CheckoutExpected: "2027-01-01"})
+	book(model.Reservation{RoomType: "DELUXE", CheckinExpected: "2026-12-30", CheckoutExpected: "2027-01-01"})
+	late := book(model.Reservation{RoomType: "DELUXE", CheckinExpected: "2026-12-31", CheckoutExpected: "2027-01-01"})
+	var soldOut *model.RoomTypeSoldOutError
+	if _, err := reservations.Create(model.Reservation{RoomType: "DELUXE", GuestName: "Ana", CheckinExpected: "2026-12-31", CheckoutExpected: "2027-01-01"}, "", nil, testActor); !errors.As(err, &soldOut) {
+		t.Fatalf("expected a RoomTypeSoldOutError past the allowance, got %v", err)
+	}
+
+	// sem datas, os alertas vão de hoje até alertHorizon dias depois
+	alerts, err := s.Alerts("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(alerts) != 1 || alerts[0].Date != "2026-12-31" || alerts[0].Limit != 3 || alerts[0].Booked != 3 {
+		t.Fatalf("expected an alert for 2026-12-31, got %+v", alerts)
+	}
+
+	report, err := s.WalkList("2026-12-28", "2027-01-02")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Nights) != 1 {
+		t.Fatalf("expected one night over the physical rooms, got %+v", report)
+	}
+	night := report.Nights[0]
+	if night.Date != "2026-12-31" || night.Excess != 1 || len(night.Candidates) != 1 || night.Candidates[0].ReservationID != late.ID {
+		t.Fatalf("expected the latest unassigned arrival to be walked, got %+v", night)
+	}
+
+	// sem a regra não há alerta, mas o que já foi vendido continua no walk list
+	if err := s.Delete(ruleID); err != nil {
+		t.Fatal(err)
+	}
+	if alerts, _ := s.Alerts("2026-12-28", "2027-01-02"); len(alerts) != 0 {
+		t.Fatalf("expected no alerts without the rule, got %+v", alerts)
+	}
+	if report, _ := s.WalkList("2026-12-28", "2027-01-02"); len(report.Nights) != 1 {
+		t.Fatalf("expected the oversold night to stay in the walk list, got %+v", report)
+	}
+
+	for _, period := range [][2]string{{"2026-12-28", "2026-12-01"}, {"2026-01-01", "2026-12-31"}, {"", "2026-12-31"}} {
+		if _, err := s.WalkList(period[0], period[1]); !isValidationError(err) {
+			t.Fatalf("expected a validation error for %v, got %v", period, err)
+		}
+	}
+}
+
+func TestWalkCandidates(t *testing.T) {
+	bookings := []model.Reservation{
+		{ID: "checked-in", Status: "CHECKED_IN", RoomID: "r1", CheckinExpected: "2026-12-30"},
+		{ID: "confirmed-room", Status: "CONFIRMED", RoomID: "r2", CheckinExpected: "2026-12-31"},
+		{ID: "created-room", Status: "CREATED", RoomID: "r3", CheckinExpected: "2026-12-30"},
+		{ID: "confirmed", Status: "CONFIRMED", CheckinExpected: "2026-12-31"},
+		{ID: "created-early", Status: "CREATED", CheckinExpected: "2026-12-30"},
+		{ID: "created-late", Status: "CREATED", CheckinExpected: "2026-12-31"},
+	}
+	candidates := walkCandidates(bookings, 5)
+	expected := []string{"created-late", "created-early", "confirmed", "created-room", "confirmed-room"}
+	if len(candidates) != len(expected) {
+		t.Fatalf("expected %v, got %+v", expected, candidates)
+	}
+	for i, id := range expected {
+		if candidates[i].ReservationID != id {
+			t.Fatalf("expected %v, got %+v", expected, candidates)
+		}
+	}
+	if candidates := walkCandidates(bookings, 10); len(candidates) != 5 {
+		t.Fatalf("expected the checked-in guest to be left out, got %+v", candidates)
+	}
+}
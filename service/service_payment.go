@@ -0,0 +1,256 @@
+package service
+
+import (
+	"errors"
+	"hotel-soa/dao"
+	"hotel-soa/gateway"
+	"hotel-soa/model"
+	"time"
+)
+
+// cardPayments faz as chamadas ao gateway de pagamento e grava a resposta de cada uma
+// nas autorizações e na conta da reserva. O gateway é chamado fora da transação, então
+// uma gravação que falha depois de uma autorização aprovada libera a autorização
+type cardPayments struct {
+	gateway        gateway.PaymentGateway
+	folios         dao.FolioRepository
+	depositPercent float64
+	now            func() time.Time
+}
+
+func newCardPayments(g gateway.PaymentGateway, folios dao.FolioRepository, depositPercent float64) *cardPayments {
+	return &cardPayments{gateway: g, folios: folios, depositPercent: depositPercent, now: time.Now}
+}
+
+// authorize pré-autoriza no cartão depositPercent% do total da reserva. A aprovação
+// confirma a reserva; recusa e falta de resposta também ficam registradas e voltam como
+// *model.PaymentDeclinedError e *model.PaymentTimeoutError
+func (p *cardPayments) authorize(res model.Reservation, cardToken string, actor model.Actor) (model.PaymentAuthorization, error) {
+	now := p.now()
+	auth := model.PaymentAuthorization{
+		ReservationID:  res.ID,
+		Gateway:        p.gateway.Name(),
+		Amount:         res.TotalAmount.Percent(p.depositPercent, model.DepositRounding),
+		CapturedAmount: model.NewMoney(0),
+		RefundedAmount: model.NewMoney(0),
+		CreatedAt:      now,
+		CreatedBy:      actor.Username,
+		UpdatedAt:      now,
+	}
+	if !auth.Amount.IsPositive() {
+		return model.PaymentAuthorization{}, conflict("nothing_to_authorize", "reservation %s has no amount to authorize", res.ID)
+	}
+
+	result, err := p.gateway.Authorize(gateway.AuthorizeRequest{ReservationID: res.ID, CardToken: cardToken, Amount: auth.Amount})
+	switch {
+	case errors.Is(err, gateway.ErrTimeout):
+		auth.Status, auth.DeclineCode = model.AuthorizationFailed, "gateway_timeout"
+	case err != nil:
+		return model.PaymentAuthorization{}, err
+	case result.Approved:
+		auth.Status, auth.Reference = model.AuthorizationAuthorized, result.Reference
+	default:
+		auth.Status, auth.DeclineCode = model.AuthorizationDeclined, result.DeclineCode
+	}
+
+	id, err := p.folios.InsertAuthorization(auth, actor)
+	if err != nil {
+		// a autorização aprovada não foi gravada: a reserva mudou enquanto o gateway
+		// respondia ou a escrita falhou, e o valor não pode ficar preso no cartão
+		if auth.Status == model.AuthorizationAuthorized {
+			p.gateway.Void(auth.Reference)
+		}
+		return model.PaymentAuthorization{}, mapStatusChanged(err)
+	}
+	auth.ID = id
+
+	switch auth.Status {
+	case model.AuthorizationFailed:
+		return auth, &model.PaymentTimeoutError{ReservationID: res.ID, AuthorizationID: id}
+	case model.AuthorizationDeclined:
+		return auth, &model.PaymentDeclinedError{ReservationID: res.ID, AuthorizationID: id, DeclineCode: auth.DeclineCode}
+	}
+	return auth, nil
+}
+
+// authorized soma o que as autorizações ainda abertas da reserva podem capturar
+func (p *cardPayments) authorized(reservationID string) (model.Money, error) {
+	auths, err := p.folios.GetAuthorizations(reservationID)
+	if err != nil {
+		return model.Money{}, err
+	}
+	total := model.NewMoney(0)
+	for _, auth := range auths {
+		if auth.Status == model.AuthorizationAuthorized {
+			total = total.Add(auth.Amount)
+		}
+	}
+	return total, nil
+}
+
+// capture cobra as autorizações da reserva até balance, o saldo da conta final, e lança
+// cada captura como pagamento CARD. O que passa do saldo é liberado pelo gateway na
+// própria captura; sem saldo, a autorização é cancelada. Retorna as autorizações
+// capturadas, também quando uma captura seguinte falha, para reverse
+func (p *cardPayments) capture(reservationID string, balance model.Money, actor model.Actor) ([]model.PaymentAuthorization, error) {
+	auths, err := p.folios.GetAuthorizations(reservationID)
+	if err != nil {
+		return nil, err
+	}
+	var captured []model.PaymentAuthorization
+	for _, auth := range auths {
+		if auth.Status != model.AuthorizationAuthorized {
+			continue
+		}
+		if !balance.IsPositive() {
+			if err := p.void(auth, actor); err != nil {
+				return captured, err
+			}
+			continue
+		}
+
+		amount := auth.Amount
+		if balance.Cmp(amount) < 0 {
+			amount = balance
+		}
+		result, err := p.gateway.Capture(auth.Reference, amount)
+		if err := declined(auth, result, err); err != nil {
+			return captured, err
+		}
+		now := p.now()
+		auth.Status, auth.CapturedAmount, auth.CaptureReference, auth.UpdatedAt = model.AuthorizationCaptured, amount, result.Reference, now
+		payment := &model.Payment{
+			ReservationID:   reservationID,
+			Type:            model.PaymentTypePayment,
+			Method:          "CARD",
+			Amount:          amount,
+			Reference:       result.Reference,
+			AuthorizationID: auth.ID,
+			PostedAt:        now,
+			PostedBy:        actor.Username,
+		}
+		if err := p.folios.UpdateAuthorization(auth, model.AuthorizationAuthorized, payment, actor); err != nil {
+			return captured, mapAuthorizationChanged(err)
+		}
+		captured = append(captured, auth)
+		balance = balance.Sub(amount)
+	}
+	return captured, nil
+}
+
+// reverse estorna pelo gateway as capturas de uma operação que não terminou. Um estorno
+// que o gateway recusa deixa a captura na conta como pagamento CARD, onde pode ser
+// estornada depois
+func (p *cardPayments) reverse(reservationID string, captured []model.PaymentAuthorization, actor model.Actor) {
+	for _, auth := range captured {
+		p.refund(reservationID, auth.ID, auth.CapturedAmount, actor)
+	}
+}
+
+// chargeFee cobra do depósito pré-autorizado a multa de cancelamento ou no-show da
+// reserva, que terminou sem estadia: captura até o que foi autorizado e libera o resto.
+// Se uma captura falha, as autorizações que sobraram são liberadas
+func (p *cardPayments) chargeFee(reservationID string, fee model.Money, actor model.Actor) error {
+	_, err := p.capture(reservationID, fee, actor)
+	if err != nil {
+		p.release(reservationID, actor)
+	}
+	return err
+}
+
+// release cancela no gateway as autorizações ainda abertas da reserva. Uma autorização que o gateway não liberou continua AUTHORIZED e expira no
+// emissor do cartão
+func (p *cardPayments) release(reservationID string, actor model.Actor) error {
+	auths, err := p.folios.GetAuthorizations(reservationID)
+	if err != nil {
+		return err
+	}
+	var failed error
+	for _, auth := range auths {
+		if auth.Status != model.AuthorizationAuthorized {
+			continue
+		}
+		if err := p.void(auth, actor); err != nil && failed == nil {
+			failed = err
+		}
+	}
+	return failed
+}
+
+// refund estorna amount da captura da autorização pelo gateway e lança o estorno na conta
+func (p *cardPayments) refund(reservationID, authorizationID string, amount model.Money, actor model.Actor) (model.Payment, error) {
+	auths, err := p.folios.GetAuthorizations(reservationID)
+	if err != nil {
+		return model.Payment{}, err
+	}
+	var auth model.PaymentAuthorization
+	for _, a := range auths {
+		if a.ID == authorizationID {
+			auth = a
+		}
+	}
+	if auth.ID == "" {
+		var v model.ValidationError
+		v.Add("authorization_id", "not_found", "authorization "+authorizationID+" not found in this reservation")
+		return model.Payment{}, v.Err()
+	}
+	if auth.Status != model.AuthorizationCaptured {
+		return model.Payment{}, conflict("authorization_not_captured", "authorization %s is %s, only CAPTURED authorizations can be refunded", auth.ID, auth.Status)
+	}
+	if amount.Cmp(auth.Refundable()) > 0 {
+		return model.Payment{}, conflict("refund_exceeds_captured", "refund is larger than the %s %s left to refund on authorization %s", auth.Refundable(), auth.Refundable().Currency(), auth.ID)
+	}
+
+	result, err := p.gateway.Refund(auth.CaptureReference, amount)
+	if err := declined(auth, result, err); err != nil {
+		return model.Payment{}, err
+	}
+	now := p.now()
+	auth.RefundedAmount, auth.UpdatedAt = auth.RefundedAmount.Add(amount), now
+	payment := model.Payment{
+		ReservationID:   reservationID,
+		Type:            model.PaymentTypeRefund,
+		Method:          "CARD",
+		Amount:          amount,
+		Reference:       result.Reference,
+		AuthorizationID: auth.ID,
+		PostedAt:        now,
+		PostedBy:        actor.Username,
+	}
+	if err := p.folios.UpdateAuthorization(auth, model.AuthorizationCaptured, &payment, actor); err != nil {
+		return model.Payment{}, mapAuthorizationChanged(err)
+	}
+	return payment, nil
+}
+
+// void cancela uma autorização no gateway e grava o cancelamento
+func (p *cardPayments) void(auth model.PaymentAuthorization, actor model.Actor) error {
+	result, err := p.gateway.Void(auth.Reference)
+	if err := declined(auth, result, err); err != nil {
+		return err
+	}
+	auth.Status, auth.UpdatedAt = model.AuthorizationVoided, p.now()
+	return mapAuthorizationChanged(p.folios.UpdateAuthorization(auth, model.AuthorizationAuthorized, nil, actor))
+}
+
+// declined traduz a falha de uma operação sobre uma autorização existente: falta de
+// resposta, erro de comunicação ou recusa
+func declined(auth model.PaymentAuthorization, result gateway.Result, err error) error {
+	switch {
+	case errors.Is(err, gateway.ErrTimeout):
+		return &model.PaymentTimeoutError{ReservationID: auth.ReservationID, AuthorizationID: auth.ID}
+	case err != nil:
+		return err
+	case !result.Approved:
+		return &model.PaymentDeclinedError{ReservationID: auth.ReservationID, AuthorizationID: auth.ID, DeclineCode: result.DeclineCode}
+	}
+	return nil
+}
+
+// mapAuthorizationChanged traduz a gravação recusada porque a autorização mudou desde a leitura
+func mapAuthorizationChanged(err error) error {
+	if errors.Is(err, dao.ErrAuthorizationChanged) {
+		return conflict("authorization_changed", "%s", err.Error())
+	}
+	return err
+}
@@ -0,0 +1,256 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"hotel-soa/dao"
+	"hotel-soa/gateway"
+	"hotel-soa/model"
+)
+
+// authorizations lê as pré-autorizações da reserva
+func authorizations(t *testing.T, repos dao.Repositories, reservationID string) []model.PaymentAuthorization {
+	t.Helper()
+	auths, err := repos.Folios.GetAuthorizations(reservationID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return auths
+}
+
+func TestDepositAuthorization(t *testing.T) {
+	repos := dao.NewMemoryRepositories()
+	roomID, err := repos.Rooms.InsertRoom(model.Room{Number: 101, Type: "STANDARD", Capacity: 2, PricePerNight: model.NewMoney(10000), Status: "ATIVO"}, testActor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := newStayTestService(repos, date("2026-06-01").Add(12*time.Hour))
+	stay := func(checkin string) model.Reservation {
+		return model.Reservation{RoomID: roomID, GuestName: "Ana", CheckinExpected: checkin, CheckoutExpected: date(checkin).AddDate(0, 0, 2).Format("2006-01-02")}
+	}
+
+	// aprovado, o depósito de 30% do total confirma a reserva
+	res, err := s.Create(stay("2026-06-10"), "", &model.CardPaymentRequest{CardToken: "tok_visa"}, testActor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Status != "CONFIRMED" || res.ConfirmedAt == nil {
+		t.Fatalf("expected a CONFIRMED reservation, got %+v", res)
+	}
+	if auths := authorizations(t, repos, res.ID); len(auths) != 1 || auths[0].Status != model.AuthorizationAuthorized || !auths[0].Amount.Equal(model.NewMoney(6000)) {
+		t.Fatalf("expected an authorization of 60.00, got %+v", auths)
+	}
+	if _, err := s.Authorize(res.ID, model.CardPaymentRequest{CardToken: "tok_visa"}, testActor); !errors.Is(err, ErrInvalidTransition) {
+		t.Fatalf("expected ErrInvalidTransition authorizing a CONFIRMED reservation, got %v", err)
+	}
+
+	// recusado, a reserva fica gravada como CREATED e pode ser autorizada de novo
+	_, err = s.Create(stay("2026-06-13"), "", &model.CardPaymentRequest{CardToken: gateway.StubTokenInsufficientFunds}, testActor)
+	var declinedErr *model.PaymentDeclinedError
+	if !errors.As(err, &declinedErr) || declinedErr.DeclineCode != "insufficient_funds" || declinedErr.ReservationID == "" {
+		t.Fatalf("expected a PaymentDeclinedError, got %v", err)
+	}
+	declined, _ := s.GetByID(declinedErr.ReservationID)
+	if declined.Status != "CREATED" {
+		t.Fatalf("expected the declined reservation to stay CREATED, got %+v", declined)
+	}
+	_, err = s.Authorize(declined.ID, model.CardPaymentRequest{CardToken: gateway.StubTokenTimeout}, testActor)
+	var timeoutErr *model.PaymentTimeoutError
+	if !errors.As(err, &timeoutErr) || timeoutErr.AuthorizationID == "" {
+		t.Fatalf("expected a PaymentTimeoutError, got %v", err)
+	}
+	auth, err := s.Authorize(declined.ID, model.CardPaymentRequest{CardToken: "tok_visa"}, testActor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if auth.Status != model.AuthorizationAuthorized || auth.Reference == "" {
+		t.Fatalf("expected an approved authorization, got %+v", auth)
+	}
+	auths := authorizations(t, repos, declined.ID)
+	if len(auths) != 3 || auths[0].Status != model.AuthorizationDeclined || auths[1].Status != model.AuthorizationFailed || auths[1].DeclineCode != "gateway_timeout" {
+		t.Fatalf("expected every attempt to be recorded, got %+v", auths)
+	}
+	if confirmed, _ := s.GetByID(declined.ID); confirmed.Status != "CONFIRMED" {
+		t.Fatalf("expected a CONFIRMED reservation, got %+v", confirmed)
+	}
+
+	if _, err := s.Authorize(declined.ID, model.CardPaymentRequest{}, testActor); !isValidationError(err) {
+		t.Fatalf("expected a validation error without a card token, got %v", err)
+	}
+
+	// o cancelamento libera o depósito
+	if _, err := s.Cancel(res.ID, "", testActor); err != nil {
+		t.Fatal(err)
+	}
+	if auths := authorizations(t, repos, res.ID); auths[0].Status != model.AuthorizationVoided {
+		t.Fatalf("expected a VOIDED authorization, got %+v", auths)
+	}
+}
+
+func TestDepositCaptureAndRefund(t *testing.T) {
+	repos := dao.NewMemoryRepositories()
+	roomID, err := repos.Rooms.InsertRoom(model.Room{Number: 101, Type: "STANDARD", Capacity: 2, PricePerNight: model.NewMoney(10000), Status: "ATIVO"}, testActor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := newStayTestService(repos, date("2026-06-10").Add(14*time.Hour))
+	res, err := s.Create(model.Reservation{RoomID: roomID, GuestName: "Ana", CheckinExpected: "2026-06-10", CheckoutExpected: "2026-06-12"},
+		"", &model.CardPaymentRequest{CardToken: "tok_visa"}, testActor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.CheckIn(res.ID, "maria", "", testActor); err != nil {
+		t.Fatal(err)
+	}
+
+	// com 150,00 pagos em dinheiro, a captura cobra só os 50,00 que faltam do depósito de 60,00
+	payFolio(t, repos, res.ID, model.NewMoney(15000))
+	s.now = func() time.Time { return date("2026-06-12").Add(10 * time.Hour) }
+	if _, err := s.CheckOut(res.ID, "joao", testActor); err != nil {
+		t.Fatal(err)
+	}
+	auths := authorizations(t, repos, res.ID)
+	if auths[0].Status != model.AuthorizationCaptured || !auths[0].CapturedAmount.Equal(model.NewMoney(5000)) || auths[0].CaptureReference == "" {
+		t.Fatalf("expected a capture of 50.00, got %+v", auths[0])
+	}
+	folios := NewFolioService(repos.Folios, repos.Reservations, gateway.NewStubGateway())
+	folio, err := folios.Get(res.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(folio.Payments) != 2 || folio.Payments[1].Method != "CARD" || folio.Payments[1].AuthorizationID != auths[0].ID || !folio.Balance.IsZero() {
+		t.Fatalf("expected the capture as a CARD payment and a settled folio, got %+v", folio)
+	}
+
+	// o estorno pelo gateway vai contra a captura
+	refund := model.PaymentRequest{Type: model.PaymentTypeRefund, Method: "CARD", Amount: model.NewMoney(2000), AuthorizationID: auths[0].ID}
+	payment, err := folios.PostPayment(res.ID, refund, testActor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if payment.Type != model.PaymentTypeRefund || payment.Reference == "" || payment.AuthorizationID != auths[0].ID {
+		t.Fatalf("expected a CARD refund, got %+v", payment)
+	}
+	refund.Amount = model.NewMoney(3001)
+	if _, err := folios.PostPayment(res.ID, refund, testActor); !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected ErrConflict refunding more than captured, got %v", err)
+	}
+	refund.AuthorizationID = "00000000-0000-0000-0000-000000000000"
+	if _, err := folios.PostPayment(res.ID, refund, testActor); !isValidationError(err) {
+		t.Fatalf("expected a validation error for an unknown authorization, got %v", err)
+	}
+}
+
+// racingCheckOut simula outra escrita que muda a reserva entre a captura e o check-out
+type racingCheckOut struct {
+	dao.ReservationRepository
+}
+
+func (r racingCheckOut) CheckOutReservation(res model.Reservation, at time.Time, operator string, actor model.Actor) error {
+	return dao.ErrReservationStatusChanged
+}
+
+func TestCheckOutCapturesOnlyWhenSettled(t *testing.T) {
+	repos := dao.NewMemoryRepositories()
+	roomID, err := repos.Rooms.InsertRoom(model.Room{Number: 101, Type: "STANDARD", Capacity: 2, PricePerNight: model.NewMoney(10000), Status: "ATIVO"}, testActor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := newStayTestService(repos, date("2026-06-10").Add(14*time.Hour))
+	res, err := s.Create(model.Reservation{RoomID: roomID, GuestName: "Ana", CheckinExpected: "2026-06-10", CheckoutExpected: "2026-06-12"},
+		"", &model.CardPaymentRequest{CardToken: "tok_visa"}, testActor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.CheckIn(res.ID, "maria", "", testActor); err != nil {
+		t.Fatal(err)
+	}
+	s.now = func() time.Time { return date("2026-06-12").Add(10 * time.Hour) }
+
+	// o depósito de 60,00 não cobre os 200,00 da conta, então nada é capturado
+	var due *model.BalanceDueError
+	if _, err := s.CheckOut(res.ID, "joao", testActor); !errors.As(err, &due) || !due.Balance.Equal(model.NewMoney(14000)) {
+		t.Fatalf("expected 140.00 due after the deposit, got %v", err)
+	}
+	if auths := authorizations(t, repos, res.ID); auths[0].Status != model.AuthorizationAuthorized {
+		t.Fatalf("expected the deposit to stay authorized, got %+v", auths[0])
+	}
+
+	// a captura feita antes de um check-out recusado é estornada
+	payFolio(t, repos, res.ID, model.NewMoney(14000))
+	racing := *s
+	racing.reservations = racingCheckOut{repos.Reservations}
+	if _, err := racing.CheckOut(res.ID, "joao", testActor); !isServiceError(err, "status_changed") {
+		t.Fatalf("expected status_changed, got %v", err)
+	}
+	auths := authorizations(t, repos, res.ID)
+	if auths[0].Status != model.AuthorizationCaptured || !auths[0].RefundedAmount.Equal(auths[0].CapturedAmount) {
+		t.Fatalf("expected the capture to be refunded, got %+v", auths[0])
+	}
+	folio, err := NewFolioService(repos.Folios, repos.Reservations, gateway.NewStubGateway()).Get(res.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !folio.Balance.Equal(model.NewMoney(6000)) {
+		t.Fatalf("expected the refunded 60.00 back in the balance, got %+v", folio)
+	}
+}
+
+func TestFeesChargedToDeposit(t *testing.T) {
+	repos := dao.NewMemoryRepositories()
+	policies := NewCancellationPolicyService(repos.Policies)
+	percent, err := policies.Create(model.CancellationPolicy{Name: "10%", FreeUntilDays: 30, PenaltyType: model.PenaltyPercent, PenaltyValue: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	nonRefundable, err := policies.Create(model.CancellationPolicy{Name: "Non refundable", NonRefundable: true, PenaltyType: model.PenaltyPercent})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rooms := insertRooms(t, repos,
+		model.Room{Number: 101, Type: "STANDARD", Capacity: 2, PricePerNight: model.NewMoney(10000), Status: "ATIVO", CancellationPolicyID: percent},
+		model.Room{Number: 102, Type: "STANDARD", Capacity: 2, PricePerNight: model.NewMoney(10000), Status: "ATIVO", CancellationPolicyID: nonRefundable},
+		model.Room{Number: 103, Type: "STANDARD", Capacity: 2, PricePerNight: model.NewMoney(10000), Status: "ATIVO", CancellationPolicyID: percent},
+	)
+	s := newStayTestService(repos, date("2026-06-01").Add(12*time.Hour))
+	// cada reserva de 200,00 pré-autoriza um depósito de 60,00
+	book := func(number int) model.Reservation {
+		t.Helper()
+		res, err := s.Create(model.Reservation{RoomID: rooms[number], GuestName: "Ana", CheckinExpected: "2026-06-10", CheckoutExpected: "2026-06-12"},
+			"", &model.CardPaymentRequest{CardToken: "tok_visa"}, testActor)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res
+	}
+	captured := func(reservationID string, expected int64) {
+		t.Helper()
+		auths := authorizations(t, repos, reservationID)
+		if len(auths) != 1 || auths[0].Status != model.AuthorizationCaptured || !auths[0].CapturedAmount.Equal(model.NewMoney(expected)) {
+			t.Fatalf("expected a capture of %d, got %+v", expected, auths)
+		}
+	}
+
+	// a multa de 20,00 sai do depósito e o resto é liberado na própria captura
+	partial := book(101)
+	if _, err := s.Cancel(partial.ID, "", testActor); err != nil {
+		t.Fatal(err)
+	}
+	captured(partial.ID, 2000)
+
+	// a multa de 200,00 passa do depósito, que é capturado inteiro
+	full := book(102)
+	if _, err := s.Cancel(full.ID, "", testActor); err != nil {
+		t.Fatal(err)
+	}
+	captured(full.ID, 6000)
+
+	unarrived := book(103)
+	monitor := NewStayMonitor(repos.Reservations, repos.Policies, repos.Folios, gateway.NewStubGateway(), 6, 12)
+	if _, err := monitor.Run(time.Date(2026, 6, 11, 6, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatal(err)
+	}
+	captured(unarrived.ID, 2000)
+}
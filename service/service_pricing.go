@@ -0,0 +1,96 @@
+package service
+
+import (
+	"fmt"
+	"hotel-soa/dao"
+	"hotel-soa/model"
+	"time"
+)
+
+// pricingEngine calcula o preço de uma estadia noite a noite a partir dos planos
+// tarifários do tipo de quarto, caindo no price_per_night quando nenhum plano cobre a noite
+type pricingEngine struct {
+	ratePlans dao.RatePlanRepository
+}
+
+func newPricingEngine(ratePlans dao.RatePlanRepository) *pricingEngine {
+	return &pricingEngine{ratePlans: ratePlans}
+}
+
+// Quote retorna o total e o preço de cada noite. A noite de checkout não é cobrada.
+// As restrições de estadia mínima e chegada fechada vêm do plano da noite de chegada.
+func (p *pricingEngine) Quote(room model.Room, checkin, checkout time.Time) (model.Money, []model.NightlyRate, error) {
+	plans, err := p.plansFor(room.Type, checkin, checkout)
+	if err != nil {
+		return model.Money{}, nil, err
+	}
+	total, nights, err := quoteWithPlans(room, plans, checkin, checkout)
+	if err != nil {
+		return model.Money{}, nil, invalid("rate_plan_restriction", "%s", err.Error())
+	}
+	return total, nights, nil
+}
+
+// plansFor carrega os planos do tipo de quarto que cobrem alguma noite da estadia
+func (p *pricingEngine) plansFor(roomType string, checkin, checkout time.Time) ([]model.RatePlan, error) {
+	return p.ratePlans.GetRatePlansForPeriod(roomType, checkin, checkout.AddDate(0, 0, -1))
+}
+
+// quoteWithPlans precifica a estadia com planos já carregados; o erro indica uma
+// restrição do plano de chegada. Cada noite é arredondada por model.NightlyRounding e o
+// total é a soma exata das noites
+func quoteWithPlans(room model.Room, plans []model.RatePlan, checkin, checkout time.Time) (model.Money, []model.NightlyRate, error) {
+	total := model.NewMoney(0)
+	var nights []model.NightlyRate
+	for night := checkin; night.Before(checkout); night = night.AddDate(0, 0, 1) {
+		rate := model.NightlyRate{Date: night.Format("2006-01-02"), Price: room.PricePerNight}
+		if plan := planForNight(plans, night); plan != nil {
+			base := room.PricePerNight
+			if plan.BaseRate.IsPositive() {
+				base = plan.BaseRate
+			}
+			rate.Price = base.Mul(plan.Multiplier(night.Weekday()), model.NightlyRounding)
+			rate.RatePlanID = plan.ID
+		}
+		nights = append(nights, rate)
+		total = total.Add(rate.Price)
+	}
+
+	if arrival := planForNight(plans, checkin); arrival != nil {
+		if arrival.IsClosedToArrival(checkin) {
+			return model.Money{}, nil, fmt.Errorf("arrival on %s is closed by rate plan %s", checkin.Format("2006-01-02"), arrival.Name)
+		}
+		if arrival.MinStay > len(nights) {
+			return model.Money{}, nil, fmt.Errorf("rate plan %s requires a minimum stay of %d nights", arrival.Name, arrival.MinStay)
+		}
+	}
+
+	return total, nights, nil
+}
+
+// planForNight escolhe o plano de maior prioridade que cobre a noite; os planos já vêm ordenados
+func planForNight(plans []model.RatePlan, night time.Time) *model.RatePlan {
+	for i := range plans {
+		if plans[i].Covers(night) {
+			return &plans[i]
+		}
+	}
+	return nil
+}
+
+// rackRoom é o quarto que dá o preço de uma reserva feita só pelo tipo: o não arquivado
+// mais barato do tipo, e entre os de mesmo preço o de menor número. Vazio quando o hotel
+// não tem quartos do tipo
+func rackRoom(rooms []model.Room, roomType string) model.Room {
+	var rack model.Room
+	for _, room := range rooms {
+		if room.Type != roomType || room.DeletedAt != nil {
+			continue
+		}
+		if rack.ID == "" || room.PricePerNight.Cmp(rack.PricePerNight) < 0 ||
+			(room.PricePerNight.Equal(rack.PricePerNight) && room.Number < rack.Number) {
+			rack = room
+		}
+	}
+	return rack
+}
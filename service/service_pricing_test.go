@@ -0,0 +1,96 @@
+package service
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"hotel-soa/dao"
+	"hotel-soa/model"
+)
+
+func TestPricingEngineQuote(t *testing.T) {
+	repos := dao.NewMemoryRepositories()
+	plans := []model.RatePlan{
+		// junho com fim de semana 50% mais caro e chegada fechada aos domingos
+		{Name: "June", RoomType: "STANDARD", StartDate: "2026-06-01", EndDate: "2026-06-30", BaseRate: model.NewMoney(20000),
+			WeekdayMultipliers: map[string]float64{"friday": 1.5, "saturday": 1.5}, ClosedToArrival: []string{"sunday"}},
+		// feriado de maior prioridade por cima do plano de junho, com estadia mínima
+		{Name: "Holiday", RoomType: "STANDARD", StartDate: "2026-06-04", EndDate: "2026-06-05", BaseRate: model.NewMoney(30000), MinStay: 2, Priority: 10},
+		{Name: "Deluxe June", RoomType: "DELUXE", StartDate: "2026-06-01", EndDate: "2026-06-30", BaseRate: model.NewMoney(90000)},
+	}
+	ids := map[string]string{}
+	for _, plan := range plans {
+		id, err := repos.RatePlans.InsertRatePlan(plan)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids[plan.Name] = id
+	}
+	engine := newPricingEngine(repos.RatePlans)
+	room := model.Room{Type: "STANDARD", PricePerNight: model.NewMoney(10000)}
+
+	tests := []struct {
+		name              string
+		checkin, checkout string
+		total             model.Money
+		nights            []model.NightlyRate
+		wantErr           error
+	}{
+		{"no plan uses the room price", "2026-05-10", "2026-05-12", model.NewMoney(20000), []model.NightlyRate{
+			{Date: "2026-05-10", Price: model.NewMoney(10000)}, {Date: "2026-05-11", Price: model.NewMoney(10000)},
+		}, nil},
+		{"plan base rate and weekend multiplier", "2026-06-11", "2026-06-14", model.NewMoney(80000), []model.NightlyRate{
+			{Date: "2026-06-11", Price: model.NewMoney(20000), RatePlanID: ids["June"]},
+			{Date: "2026-06-12", Price: model.NewMoney(30000), RatePlanID: ids["June"]},
+			{Date: "2026-06-13", Price: model.NewMoney(30000), RatePlanID: ids["June"]},
+		}, nil},
+		{"stay across the plan start", "2026-05-31", "2026-06-02", model.NewMoney(30000), []model.NightlyRate{
+			{Date: "2026-05-31", Price: model.NewMoney(10000)},
+			{Date: "2026-06-01", Price: model.NewMoney(20000), RatePlanID: ids["June"]},
+		}, nil},
+		{"higher priority wins", "2026-06-04", "2026-06-06", model.NewMoney(60000), []model.NightlyRate{
+			{Date: "2026-06-04", Price: model.NewMoney(30000), RatePlanID: ids["Holiday"]},
+			{Date: "2026-06-05", Price: model.NewMoney(30000), RatePlanID: ids["Holiday"]},
+		}, nil},
+		{"minimum stay of the arrival plan", "2026-06-04", "2026-06-05", model.NewMoney(0), nil, ErrInvalid},
+		{"closed to arrival", "2026-06-07", "2026-06-09", model.NewMoney(0), nil, ErrInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkin, checkout, err := parseStay("checkin", tt.checkin, "checkout", tt.checkout)
+			if err != nil {
+				t.Fatal(err)
+			}
+			total, nights, err := engine.Quote(room, checkin, checkout)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if !total.Equal(tt.total) {
+				t.Errorf("expected total %v, got %v", tt.total, total)
+			}
+			if !reflect.DeepEqual(nights, tt.nights) {
+				t.Errorf("expected nights %+v, got %+v", tt.nights, nights)
+			}
+		})
+	}
+}
+
+func TestPricingEngineRoundsToCents(t *testing.T) {
+	repos := dao.NewMemoryRepositories()
+	if _, err := repos.RatePlans.InsertRatePlan(model.RatePlan{Name: "Thirds", RoomType: "STANDARD", StartDate: "2026-06-01", EndDate: "2026-06-30",
+		BaseRate: model.NewMoney(10000), WeekdayMultipliers: map[string]float64{"monday": 1.0 / 3}}); err != nil {
+		t.Fatal(err)
+	}
+	checkin, checkout, _ := parseStay("checkin", "2026-06-08", "checkout", "2026-06-09")
+	total, nights, err := newPricingEngine(repos.RatePlans).Quote(model.Room{Type: "STANDARD", PricePerNight: model.NewMoney(10000)}, checkin, checkout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !total.Equal(model.NewMoney(3333)) || !nights[0].Price.Equal(model.NewMoney(3333)) {
+		t.Fatalf("expected 33.33, got %v and %+v", total, nights)
+	}
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"hotel-soa/dao"
+	"hotel-soa/model"
+)
+
+type RatePlanService interface {
+	Create(plan model.RatePlan) (string, error)
+	Update(plan model.RatePlan) error
+	Delete(id string) error
+	GetByID(id string) (model.RatePlan, error)
+	GetAll() ([]model.RatePlan, error)
+}
+
+type ratePlanService struct {
+	ratePlans dao.RatePlanRepository
+	policies  dao.CancellationPolicyRepository
+}
+
+func NewRatePlanService(ratePlans dao.RatePlanRepository, policies dao.CancellationPolicyRepository) RatePlanService {
+	return &ratePlanService{ratePlans: ratePlans, policies: policies}
+}
+
+func (s *ratePlanService) Create(plan model.RatePlan) (string, error) {
+	if err := checkPolicyExists(s.policies, plan.CancellationPolicyID); err != nil {
+		return "", err
+	}
+	return s.ratePlans.InsertRatePlan(plan)
+}
+
+func (s *ratePlanService) Update(plan model.RatePlan) error {
+	if _, err := s.GetByID(plan.ID); err != nil {
+		return err
+	}
+	if err := checkPolicyExists(s.policies, plan.CancellationPolicyID); err != nil {
+		return err
+	}
+	return s.ratePlans.UpdateRatePlan(plan)
+}
+
+func (s *ratePlanService) Delete(id string) error {
+	return s.ratePlans.DeleteRatePlan(id)
+}
+
+func (s *ratePlanService) GetByID(id string) (model.RatePlan, error) {
+	plan, err := s.ratePlans.GetRatePlanByID(id)
+	if err != nil {
+		return model.RatePlan{}, err
+	}
+	if plan.ID == "" {
+		return model.RatePlan{}, notFound("rate_plan_not_found", "rate plan %s not found", id)
+	}
+	return plan, nil
+}
+
+func (s *ratePlanService) GetAll() ([]model.RatePlan, error) {
+	return s.ratePlans.GetAllRatePlans()
+}
@@ -4,150 +4,954 @@ import (
 	"errors"
 	"fmt"
 	"hotel-soa/dao"
+	"hotel-soa/gateway"
 	"hotel-soa/model"
-	"net/http"
+	"sort"
+	"strings"
 	"time"
 )
 
 type ReservationService interface {
-	Create(res model.Reservation) (string, int, error)
-	Update(res model.Reservation) (int, error)
-	Delete(id string) error
+	// Create com holdToken converte o bloqueio do quarto na reserva. Com payment,
+	// pré-autoriza o depósito; a reserva recusada pelo gateway fica gravada como CREATED e
+	// o erro traz o seu ID, para uma nova tentativa com Authorize
+	Create(res model.Reservation, holdToken string, payment *model.CardPaymentRequest, actor model.Actor) (model.Reservation, error)
+	// Update com um status novo aplica os demais campos e depois leva a reserva ao status
+	// pela ação própria dele; CONFIRMED pré-autoriza o depósito com payment
+	Update(res model.Reservation, payment *model.CardPaymentRequest, actor model.Actor) (model.Reservation, error)
+	// Cancel é o que o DELETE faz: a reserva fica com status CANCELED, motivo, horário e
+	// a multa e o reembolso da política de cancelamento
+	Cancel(id, reason string, actor model.Actor) (model.Reservation, error)
+	// QuoteCancellation calcula a multa e o reembolso de um cancelamento feito agora, sem cancelar
+	QuoteCancellation(id string) (model.CancellationQuote, error)
+	// Purge apaga a reserva de vez, para registros lançados por engano
+	Purge(id string, actor model.Actor) error
 	GetByID(id string) (model.Reservation, error)
-	GetAll() ([]model.Reservation, error)
+	List(filter model.ReservationFilter) (model.ReservationPage, int, error)
+	// CheckIn com roomID atribui ou troca o quarto na chegada; sem ele, a reserva feita só
+	// pelo tipo recebe o quarto livre que melhor encaixa a estadia
+	CheckIn(id, operator, roomID string, actor model.Actor) (model.Reservation, error)
+	CheckOut(id, operator string, actor model.Actor) (model.Reservation, error)
+	// Alerts lista os hóspedes sinalizados como overstay pelo StayMonitor
+	Alerts() ([]model.ReservationAlert, error)
+	// Authorize pré-autoriza o depósito de uma reserva CREATED e, aprovado, a confirma
+	Authorize(id string, payment model.CardPaymentRequest, actor model.Actor) (model.PaymentAuthorization, error)
+	// AssignRoom atribui ou troca o quarto, do mesmo tipo, de uma reserva CREATED ou CONFIRMED
+	AssignRoom(id, roomID string, actor model.Actor) (model.Reservation, error)
+	// AutoAssign atribui quartos às reservas sem quarto com chegada entre from e to
+	AutoAssign(req model.AutoAssignRequest, actor model.Actor) (model.RoomAssignmentRun, error)
 }
 
-type reservationService struct{}
+type reservationService struct {
+	reservations dao.ReservationRepository
+	rooms        dao.RoomRepository
+	guests       dao.GuestRepository
+	ratePlans    dao.RatePlanRepository
+	policies     dao.CancellationPolicyRepository
+	folios       dao.FolioRepository
+	holds        dao.HoldRepository
+	pricing      *pricingEngine
+	taxes        *taxEngine
+	payments     *cardPayments
+	now          func() time.Time
+}
 
-func NewReservationService() ReservationService {
-	return &reservationService{}
+// NewReservationService cria o ReservationService. O depósito pré-autorizado no cartão é
+// depositPercent% do total da reserva
+func NewReservationService(reservations dao.ReservationRepository, rooms dao.RoomRepository, ratePlans dao.RatePlanRepository, guests dao.GuestRepository, policies dao.CancellationPolicyRepository, taxes dao.TaxRepository, folios dao.FolioRepository, holds dao.HoldRepository, payments gateway.PaymentGateway, depositPercent float64) ReservationService {
+	return &reservationService{
+		reservations: reservations,
+		rooms:        rooms,
+		guests:       guests,
+		ratePlans:    ratePlans,
+		policies:     policies,
+		folios:       folios,
+		holds:        holds,
+		pricing:      newPricingEngine(ratePlans),
+		taxes:        newTaxEngine(taxes, guests),
+		payments:     newCardPayments(payments, folios, depositPercent),
+		now:          time.Now,
+	}
 }
 
-// regras de transição de status válidas
+// regras de transição de status válidas via PUT; cada mudança é feita pela ação própria
+// do status (pré-autorização, check-in, check-out, cancelamento e no-show)
 var validTransitions = map[string][]string{
-	"CREATED":    {"CHECKED_IN", "CANCELED"},
+	"CREATED":    {"CONFIRMED", "CHECKED_IN", "CANCELED", "NO_SHOW"},
+	"CONFIRMED":  {"CHECKED_IN", "CANCELED", "NO_SHOW"},
 	"CHECKED_IN": {"CHECKED_OUT"},
 }
 
 // ---------------- CREATE ----------------
-func (s *reservationService) Create(res model.Reservation) (string, int, error) {
-	// 1. Validação de datas
-	checkin, checkout, err := parseDates(res.CheckinExpected, res.CheckoutExpected)
+func (s *reservationService) Create(res model.Reservation, holdToken string, payment *model.CardPaymentRequest, actor model.Actor) (model.Reservation, error) {
+	// 1. Validação de datas, do bloqueio e do cartão
+	checkin, checkout, err := parseStay("checkin_expected", res.CheckinExpected, "checkout_expected", res.CheckoutExpected)
 	if err != nil {
-		return "", http.StatusConflict, err
+		return model.Reservation{}, err
 	}
-	if !checkout.After(checkin) {
-		return "", http.StatusConflict, errors.New("checkout_expected must be after checkin_expected")
+	if holdToken != "" {
+		if err := s.resolveHold(&res, holdToken); err != nil {
+			return model.Reservation{}, err
+		}
+	}
+	if payment != nil {
+		if err := payment.Validate(); err != nil {
+			return model.Reservation{}, err
+		}
 	}
 
-	// 2. Disponibilidade de quarto
-	conflict, err := dao.HasReservationConflict(res.RoomID, checkin, checkout, "")
+	// 2. Status inicial
+	if res.Status == "" {
+		res.Status = "CREATED"
+	}
+	if res.Status != "CREATED" {
+		var v model.ValidationError
+		v.Add("status", "invalid_value", "new reservations must have status CREATED")
+		return model.Reservation{}, v.Err()
+	}
+
+	// 3. Preço calculado no servidor pelo quarto ou, sem ele, pelo tipo; o total enviado
+	// pelo cliente é ignorado
+	room, err := s.pricingRoom(&res)
 	if err != nil {
-		return "", http.StatusConflict, err
+		return model.Reservation{}, err
 	}
-	if conflict {
-		return "", http.StatusConflict, fmt.Errorf("room %s is not available for the selected dates", res.RoomID)
+	if err := s.applyPrice(&res, room, checkin, checkout); err != nil {
+		return model.Reservation{}, err
 	}
 
-	// 3. Status inicial
-	if res.Status == "" {
-		res.Status = "CREATED"
+	// 4. Hóspede
+	if err := s.resolveGuest(&res, model.Reservation{}); err != nil {
+		return model.Reservation{}, err
+	}
+
+	// 5. Impostos pelo tipo do hóspede; os cobrados à parte somam ao total
+	if err := s.taxes.Apply(&res); err != nil {
+		return model.Reservation{}, err
 	}
+	res.TotalAmount = res.TotalAmount.Add(exclusiveTaxes(res.Taxes))
 
-	// 4. Persistência
-	id, err := dao.InsertReservation(res)
-	return id, http.StatusCreated, err
+	// 6. Persistência: a checagem de disponibilidade do quarto e do tipo e o consumo do
+	// bloqueio acontecem na mesma transação do insert; um conflito volta como
+	// *model.ReservationConflictError, *model.RoomHeldError ou *model.RoomTypeSoldOutError
+	id, err := s.reservations.InsertReservation(res, actor)
+	if errors.Is(err, dao.ErrHoldExpired) {
+		return model.Reservation{}, conflict("hold_expired", "%s", err.Error())
+	}
+	if errors.Is(err, dao.ErrGroupCanceled) {
+		return model.Reservation{}, conflict("group_canceled", "%s", err.Error())
+	}
+	if err != nil {
+		return model.Reservation{}, err
+	}
+	res.ID = id
+
+	// 7. Depósito: a pré-autorização aprovada confirma a reserva
+	if payment == nil {
+		return res, nil
+	}
+	auth, err := s.payments.authorize(res, payment.CardToken, actor)
+	if err != nil {
+		return model.Reservation{}, err
+	}
+	res.Status, res.ConfirmedAt = "CONFIRMED", &auth.CreatedAt
+	return res, nil
 }
 
 // ---------------- UPDATE ----------------
-func (s *reservationService) Update(res model.Reservation) (int, error) {
+func (s *reservationService) Update(res model.Reservation, payment *model.CardPaymentRequest, actor model.Actor) (model.Reservation, error) {
 	// 1. Buscar reserva atual
-	current, err := dao.GetReservationByID(res.ID)
+	current, err := s.GetByID(res.ID)
 	if err != nil {
-		return http.StatusNotFound, err
-	}
-	if current.ID == "" {
-		return http.StatusNotFound, errors.New("reservation not found")
+		return model.Reservation{}, err
 	}
 
-	// 2. Validar fluxo de status
-	if err := validateStatusTransition(current.Status, res.Status); err != nil {
-		return http.StatusBadRequest, err
+	// o grupo é definido na criação pela rooming list e não muda
+	res.GroupID = current.GroupID
+
+	// 2. Validar fluxo de status; sem status, fica o atual. Os demais campos são gravados
+	// com o status atual e a mudança é feita depois, por changeStatus
+	next := res.Status
+	if next == "" {
+		next = current.Status
+	}
+	if err := validateStatusTransition(current.Status, next); err != nil {
+		return model.Reservation{}, err
 	}
+	if next == "CONFIRMED" && next != current.Status {
+		if err := validatePayment(payment); err != nil {
+			return model.Reservation{}, err
+		}
+	}
+	res.Status = current.Status
 
 	// 3. Validar datas se alteradas
-	checkin, checkout, err := parseDates(res.CheckinExpected, res.CheckoutExpected)
+	checkin, checkout, err := parseStay("checkin_expected", res.CheckinExpected, "checkout_expected", res.CheckoutExpected)
+	if err != nil {
+		return model.Reservation{}, err
+	}
+
+	// 4. Quarto e tipo; depois da chegada a reserva não fica sem quarto
+	room, err := s.pricingRoom(&res)
 	if err != nil {
-		return http.StatusConflict, err
+		return model.Reservation{}, err
 	}
-	if !checkout.After(checkin) {
-		return http.StatusConflict, errors.New("checkout_expected must be after checkin_expected")
+	if res.RoomID == "" && !awaitingArrival(current.Status) {
+		var v model.ValidationError
+		v.Add("room_id", "required", "is required after check-in")
+		return model.Reservation{}, v.Err()
 	}
 
-	// 4. Checar conflitos se mudou datas ou quarto
-	if res.RoomID != current.RoomID ||
+	// 5. Recalcular o preço e a política de cancelamento apenas se mudou datas, tipo ou
+	// quarto; atribuir um quarto a uma reserva sem quarto não muda o preço
+	repriced := res.RoomType != current.RoomType ||
+		(res.RoomID != "" && current.RoomID != "" && res.RoomID != current.RoomID) ||
 		res.CheckinExpected != current.CheckinExpected ||
-		res.CheckoutExpected != current.CheckoutExpected {
+		res.CheckoutExpected != current.CheckoutExpected
+	if repriced && closedStatus(current.Status) {
+		// a taxa, o estorno e a conta fechada foram calculados sobre as datas e o preço atuais
+		return model.Reservation{}, invalidTransition("invalid_transition", "reservation is %s, its dates, room and price can no longer change", current.Status)
+	}
+	if repriced {
+		if err := s.applyPrice(&res, room, checkin, checkout); err != nil {
+			return model.Reservation{}, err
+		}
+	} else {
+		res.TotalAmount = current.TotalAmount
+		res.Nights = nil
+		res.CancellationPolicyID = current.CancellationPolicyID
+	}
 
-		conflict, err := dao.HasReservationConflict(res.RoomID, checkin, checkout, res.ID)
+	// 6. Hóspede
+	if err := s.resolveGuest(&res, current); err != nil {
+		return model.Reservation{}, err
+	}
+
+	// 7. Impostos recalculados com o preço ou com a troca de hóspede antes da chegada;
+	// depois dela os impostos das noites já lançadas não mudam
+	switch {
+	case repriced:
+		if err := s.taxes.Apply(&res); err != nil {
+			return model.Reservation{}, err
+		}
+	case res.GuestID != current.GuestID && awaitingArrival(current.Status):
+		res.Nights = current.Nights
+		if err := s.taxes.Apply(&res); err != nil {
+			return model.Reservation{}, err
+		}
+		res.Nights = nil
+	default:
+		res.Taxes = nil
+	}
+
+	// 8. Persistência: conflitos de datas ou quarto, o estoque do tipo e as noites já
+	// fechadas pelo night audit são checados na mesma transação do update
+	err = s.reservations.UpdateReservation(res, actor)
+	if errors.Is(err, dao.ErrNightsClosed) {
+		return model.Reservation{}, conflict("business_date_closed", "%s", err.Error())
+	}
+	if err != nil {
+		return model.Reservation{}, err
+	}
+	if next != current.Status {
+		return s.changeStatus(res.ID, next, payment, actor)
+	}
+
+	// total_amount é recalculado pela conta, que pode ter lançamentos além das noites
+	return s.GetByID(res.ID)
+}
+
+// changeStatus leva a reserva ao status pedido no PUT pela mesma ação do endpoint próprio
+// do status, com as mesmas regras; o operador do check-in e do check-out é o autor do PUT
+func (s *reservationService) changeStatus(id, status string, payment *model.CardPaymentRequest, actor model.Actor) (model.Reservation, error) {
+	var err error
+	switch status {
+	case "CONFIRMED":
+		_, err = s.Authorize(id, *payment, actor)
+	case "CHECKED_IN":
+		_, err = s.CheckIn(id, actor.Username, "", actor)
+	case "CHECKED_OUT":
+		_, err = s.CheckOut(id, actor.Username, actor)
+	case "CANCELED":
+		_, err = s.Cancel(id, "", actor)
+	case "NO_SHOW":
+		_, err = s.noShow(id, actor)
+	}
+	if err != nil {
+		return model.Reservation{}, err
+	}
+	return s.GetByID(id)
+}
+
+// ---------------- NO-SHOW ----------------
+
+// noShow fecha como NO_SHOW, com a multa de no-show da política, a reserva cujo dia de
+// chegada já terminou sem check-in, como o StayMonitor faz depois do corte
+func (s *reservationService) noShow(id string, actor model.Actor) (model.Reservation, error) {
+	res, err := s.getForAction(id, "marked as no-show", "CREATED", "CONFIRMED")
+	if err != nil {
+		return model.Reservation{}, err
+	}
+	now := s.now()
+	if now.Format("2006-01-02") <= res.CheckinExpected {
+		return model.Reservation{}, conflict("no_show_before_arrival", "no-show is not allowed before the end of the arrival date %s", res.CheckinExpected)
+	}
+
+	quote, err := s.quote(res, now)
+	if err != nil {
+		return model.Reservation{}, err
+	}
+	if err := s.reservations.MarkNoShow(id, now, quote, actor); err != nil {
+		return model.Reservation{}, mapStatusChanged(err)
+	}
+	s.payments.chargeFee(id, quote.Fee, actor)
+	return s.GetByID(id)
+}
+
+// ---------------- CHECK-IN ----------------
+func (s *reservationService) CheckIn(id, operator, roomID string, actor model.Actor) (model.Reservation, error) {
+	res, err := s.getForAction(id, "checked in", "CREATED", "CONFIRMED")
+	if err != nil {
+		return model.Reservation{}, err
+	}
+
+	// 1. Só a partir do dia de chegada e antes do dia de saída
+	now := s.now()
+	today := now.Format("2006-01-02")
+	if today < res.CheckinExpected {
+		return model.Reservation{}, conflict("checkin_before_arrival", "check-in is not allowed before the arrival date %s", res.CheckinExpected)
+	}
+	if today >= res.CheckoutExpected {
+		return model.Reservation{}, conflict("checkin_after_departure", "check-in is not allowed on or after the departure date %s", res.CheckoutExpected)
+	}
+
+	// 2. Quarto: o escolhido na recepção, o já atribuído ou, sem nenhum, o quarto livre
+	// do tipo que melhor encaixa a estadia
+	switch {
+	case roomID != "" && roomID != res.RoomID:
+		room, err := s.assignableRoom(res, roomID)
+		if err != nil {
+			return model.Reservation{}, err
+		}
+		if room.Status == "INATIVO" {
+			return model.Reservation{}, conflict("room_inactive", "room %d is inactive", room.Number)
+		}
+		if err := s.reservations.AssignRoom(id, roomID, actor); err != nil {
+			return model.Reservation{}, mapStatusChanged(err)
+		}
+		res.RoomID = roomID
+	case res.RoomID == "":
+		rooms, err := s.rooms.GetAllRooms()
+		if err != nil {
+			return model.Reservation{}, err
+		}
+		room, err := s.autoAssign(res, rooms, actor)
 		if err != nil {
-			return http.StatusConflict, err
+			return model.Reservation{}, err
 		}
-		if conflict {
-			return http.StatusConflict, fmt.Errorf("room %s is not available for the selected dates", res.RoomID)
+		if room.ID == "" {
+			return model.Reservation{}, conflict("no_room_available", "no %s room is free for the whole stay", res.RoomType)
+		}
+		res.RoomID = room.ID
+	}
+
+	// 3. Quarto ativo
+	room, err := s.rooms.GetRoomByID(res.RoomID)
+	if err != nil {
+		return model.Reservation{}, err
+	}
+	if room.Status == "INATIVO" {
+		return model.Reservation{}, conflict("room_inactive", "room %d is inactive", room.Number)
+	}
+
+	// 4. Persistência condicionada ao status ainda ser CREATED ou CONFIRMED
+	if err := s.reservations.CheckInReservation(id, now, operator, actor); err != nil {
+		return model.Reservation{}, mapStatusChanged(err)
+	}
+	res.Status, res.CheckedInAt, res.CheckedInBy = "CHECKED_IN", &now, operator
+	return res, nil
+}
+
+// ---------------- CHECK-OUT ----------------
+func (s *reservationService) CheckOut(id, operator string, actor model.Actor) (model.Reservation, error) {
+	res, err := s.getForAction(id, "checked out", "CHECKED_IN")
+	if err != nil {
+		return model.Reservation{}, err
+	}
+
+	// 1. Saída antecipada: a estadia termina hoje (mínimo de uma noite) e só as
+	// noites ocupadas são cobradas, pelo preço fechado na reserva
+	now := s.now()
+	checkin, checkout, err := parseStay("checkin_expected", res.CheckinExpected, "checkout_expected", res.CheckoutExpected)
+	if err != nil {
+		return model.Reservation{}, err
+	}
+	departure := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if !departure.After(checkin) {
+		departure = checkin.AddDate(0, 0, 1)
+	}
+	booked := stayCharges(res)
+	if departure.Before(checkout) {
+		res.TotalAmount, res.Nights = chargeNights(res, departure)
+		res.Taxes = chargeTaxes(res, departure)
+		res.CheckoutExpected = departure.Format("2006-01-02")
+	}
+
+	// 2. Saldo da conta final: o saldo atual sem as noites e impostos que a saída
+	// antecipada deixou de cobrar. Numa reserva de grupo, as noites já estão fora do saldo,
+	// na conta master
+	folio, err := s.folios.GetFolio(id)
+	if err != nil {
+		return model.Reservation{}, err
+	}
+	sumFolio(&folio)
+	balance := folio.Balance
+	if res.GroupID == "" {
+		balance = balance.Sub(booked).Add(stayCharges(res))
+	}
+
+	// 3. A conta precisa fechar em zero com o depósito antes de qualquer captura
+	authorized, err := s.payments.authorized(id)
+	if err != nil {
+		return model.Reservation{}, err
+	}
+	due := balance
+	if due.IsPositive() {
+		due = due.Sub(authorized)
+		if due.IsNegative() {
+			due = model.NewMoney(0)
 		}
 	}
+	if !due.IsZero() {
+		return model.Reservation{}, &model.BalanceDueError{ReservationID: id, Balance: due}
+	}
+
+	// 4. Captura do depósito pré-autorizado até o saldo
+	captured, err := s.payments.capture(id, balance, actor)
+	if err != nil {
+		s.payments.reverse(id, captured, actor)
+		return model.Reservation{}, err
+	}
+
+	// 5. Persistência condicionada ao status ainda ser CHECKED_IN e à conta final, com os
+	// impostos e os lançamentos da conta, fechar em zero; a conta pode ter mudado desde a
+	// leitura, e então as capturas são estornadas
+	if err := s.reservations.CheckOutReservation(res, now, operator, actor); err != nil {
+		s.payments.reverse(id, captured, actor)
+		return model.Reservation{}, mapStatusChanged(err)
+	}
+	return s.GetByID(id)
+}
+
+// ---------------- CANCEL ----------------
+func (s *reservationService) Cancel(id, reason string, actor model.Actor) (model.Reservation, error) {
+	if len(reason) > 200 {
+		var v model.ValidationError
+		v.Add("reason", "invalid_length", "must have at most 200 characters")
+		return model.Reservation{}, v.Err()
+	}
+	res, err := s.getForAction(id, "canceled", "CREATED", "CONFIRMED")
+	if err != nil {
+		return model.Reservation{}, err
+	}
 
-	err = dao.UpdateReservation(res)
+	// 1. Multa e reembolso pela política fixada na reserva
+	now := s.now()
+	quote, err := s.quote(res, now)
 	if err != nil {
-		return http.StatusInternalServerError, err
+		return model.Reservation{}, err
+	}
+
+	// 2. Persistência condicionada ao status ainda ser CREATED ou CONFIRMED
+	if err := s.reservations.CancelReservation(id, now, reason, quote, actor); err != nil {
+		return model.Reservation{}, mapStatusChanged(err)
 	}
 
-	return http.StatusOK, nil
+	// 3. A multa é cobrada do depósito pré-autorizado e o resto é liberado; a reserva já
+	// está cancelada mesmo que o gateway não responda, e a autorização então expira no emissor
+	s.payments.chargeFee(id, quote.Fee, actor)
+	res.Status, res.CanceledAt, res.CanceledBy, res.CancellationReason = "CANCELED", &now, actor.Username, reason
+	res.CancellationFee, res.RefundAmount = &quote.Fee, &quote.RefundAmount
+	return res, nil
+}
+
+func (s *reservationService) QuoteCancellation(id string) (model.CancellationQuote, error) {
+	res, err := s.getForAction(id, "canceled", "CREATED", "CONFIRMED")
+	if err != nil {
+		return model.CancellationQuote{}, err
+	}
+	return s.quote(res, s.now())
 }
 
-// ---------------- DELETE ----------------
-func (s *reservationService) Delete(id string) error {
-	return dao.DeleteReservation(id)
+// ---------------- PURGE ----------------
+func (s *reservationService) Purge(id string, actor model.Actor) error {
+	if _, err := s.GetByID(id); err != nil {
+		return err
+	}
+	return s.reservations.PurgeReservation(id, actor)
 }
 
 // ---------------- GET BY ID ----------------
 func (s *reservationService) GetByID(id string) (model.Reservation, error) {
-	return dao.GetReservationByID(id)
+	res, err := s.reservations.GetReservationByID(id)
+	if err != nil {
+		return model.Reservation{}, err
+	}
+	if res.ID == "" {
+		return model.Reservation{}, notFound("reservation_not_found", "reservation %s not found", id)
+	}
+	return res, nil
+}
+
+// ---------------- LIST ----------------
+func (s *reservationService) List(filter model.ReservationFilter) (model.ReservationPage, int, error) {
+	reservations, total, err := s.reservations.ListReservations(filter)
+	if err != nil {
+		return model.ReservationPage{}, 0, err
+	}
+
+	page := model.ReservationPage{Data: reservations}
+	if len(reservations) > filter.Page.Limit {
+		page.Data = reservations[:filter.Page.Limit]
+		last := page.Data[len(page.Data)-1]
+		page.NextCursor = model.Cursor{Sort: filter.Page.Sort.String(), Value: last.SortValue(filter.Page.Sort.Field), ID: last.ID}.Encode()
+	}
+	if page.Data == nil {
+		page.Data = []model.Reservation{}
+	}
+	return page, total, nil
 }
 
-// ---------------- GET ALL ----------------
-func (s *reservationService) GetAll() ([]model.Reservation, error) {
-	return dao.GetAllReservations()
+// ---------------- ALERTS ----------------
+func (s *reservationService) Alerts() ([]model.ReservationAlert, error) {
+	alerts, err := s.reservations.GetOverstayAlerts()
+	if err != nil {
+		return nil, err
+	}
+	if alerts == nil {
+		alerts = []model.ReservationAlert{}
+	}
+	return alerts, nil
+}
+
+// ---------------- AUTHORIZE ----------------
+func (s *reservationService) Authorize(id string, payment model.CardPaymentRequest, actor model.Actor) (model.PaymentAuthorization, error) {
+	if err := payment.Validate(); err != nil {
+		return model.PaymentAuthorization{}, err
+	}
+	res, err := s.getForAction(id, "authorized", "CREATED")
+	if err != nil {
+		return model.PaymentAuthorization{}, err
+	}
+	return s.payments.authorize(res, payment.CardToken, actor)
+}
+
+// ---------------- ROOM ASSIGNMENT ----------------
+func (s *reservationService) AssignRoom(id, roomID string, actor model.Actor) (model.Reservation, error) {
+	res, err := s.getForAction(id, "assigned a room", "CREATED", "CONFIRMED")
+	if err != nil {
+		return model.Reservation{}, err
+	}
+	if _, err := s.assignableRoom(res, roomID); err != nil {
+		return model.Reservation{}, err
+	}
+	if roomID == res.RoomID {
+		return res, nil
+	}
+
+	// conflitos com as reservas e bloqueios do quarto são checados na mesma transação
+	if err := s.reservations.AssignRoom(id, roomID, actor); err != nil {
+		return model.Reservation{}, mapStatusChanged(err)
+	}
+	return s.GetByID(id)
+}
+
+// AutoAssign percorre as chegadas por data, das estadias mais longas para as mais curtas,
+// que são as mais difíceis de encaixar, e atribui a cada uma o quarto que melhor a encaixa
+// entre os já atribuídos pelas anteriores
+func (s *reservationService) AutoAssign(req model.AutoAssignRequest, actor model.Actor) (model.RoomAssignmentRun, error) {
+	today := s.now().Format("2006-01-02")
+	if req.From == "" {
+		req.From = today
+	}
+	if req.To == "" {
+		req.To = req.From
+	}
+	// ao contrário de uma estadia, from e to podem ser o mesmo dia
+	var v model.ValidationError
+	from, err := time.Parse("2006-01-02", req.From)
+	if err != nil {
+		v.Add("from", "invalid_format", "expected YYYY-MM-DD")
+	}
+	to, err := time.Parse("2006-01-02", req.To)
+	if err != nil {
+		v.Add("to", "invalid_format", "expected YYYY-MM-DD")
+	}
+	if len(v.Errors) == 0 && (to.Before(from) || to.Sub(from) > maxAssignmentRange) {
+		v.Add("to", "out_of_range", fmt.Sprintf("must be from 0 to %d days after from", int(maxAssignmentRange.Hours()/24)))
+	}
+	if err := v.Err(); err != nil {
+		return model.RoomAssignmentRun{}, err
+	}
+
+	pending, err := s.reservations.GetUnassignedReservations(from, to)
+	if err != nil {
+		return model.RoomAssignmentRun{}, err
+	}
+	rooms, err := s.rooms.GetAllRooms()
+	if err != nil {
+		return model.RoomAssignmentRun{}, err
+	}
+	run := model.RoomAssignmentRun{Assigned: []model.RoomAssignment{}, Unassigned: []string{}}
+	for _, res := range pending {
+		room, err := s.autoAssign(res, rooms, actor)
+		var e *Error
+		if errors.As(err, &e) && e.Code == "status_changed" {
+			// cancelada ou atribuída enquanto a rotina rodava
+			continue
+		}
+		if err != nil {
+			return run, err
+		}
+		if room.ID == "" {
+			run.Unassigned = append(run.Unassigned, res.ID)
+			continue
+		}
+		run.Assigned = append(run.Assigned, model.RoomAssignment{ReservationID: res.ID, RoomID: room.ID, RoomNumber: room.Number})
+	}
+	return run, nil
 }
 
 // ---------------- HELPERS ----------------
 
-func parseDates(checkinStr, checkoutStr string) (time.Time, time.Time, error) {
+// getForAction carrega a reserva e exige um dos status de onde a ação parte
+func (s *reservationService) getForAction(id, action string, from ...string) (model.Reservation, error) {
+	res, err := s.GetByID(id)
+	if err != nil {
+		return model.Reservation{}, err
+	}
+	for _, status := range from {
+		if res.Status == status {
+			return res, nil
+		}
+	}
+	return model.Reservation{}, invalidTransition("invalid_transition", "reservation is %s, only %s reservations can be %s", res.Status, strings.Join(from, " or "), action)
+}
+
+// resolveHold troca o token pelo bloqueio, que precisa estar ativo e ser do mesmo quarto
+// e das mesmas datas da reserva
+func (s *reservationService) resolveHold(res *model.Reservation, token string) error {
+	hold, err := s.holds.GetHoldByTokenHash(hashHoldToken(token))
+	if err != nil {
+		return err
+	}
+	if hold.ID == "" || !hold.Active(s.now()) {
+		return conflict("hold_expired", "%s", dao.ErrHoldExpired.Error())
+	}
+	if hold.RoomID != res.RoomID || hold.CheckinExpected != res.CheckinExpected || hold.CheckoutExpected != res.CheckoutExpected {
+		var v model.ValidationError
+		v.Add("hold_token", "mismatch", fmt.Sprintf("hold is for room %s from %s to %s", hold.RoomID, hold.CheckinExpected, hold.CheckoutExpected))
+		return v.Err()
+	}
+	res.HoldID = hold.ID
+	return nil
+}
+
+// awaitingArrival indica a reserva que ainda espera o hóspede: CREATED ou CONFIRMED
+func awaitingArrival(status string) bool {
+	return status == "CREATED" || status == "CONFIRMED"
+}
+
+// closedStatus indica a reserva encerrada: cancelada, com check-out ou no-show
+func closedStatus(status string) bool {
+	return status == "CANCELED" || status == "CHECKED_OUT" || status == "NO_SHOW"
+}
+
+// stayCharges soma as noites e os impostos cobrados à parte da reserva
+func stayCharges(res model.Reservation) model.Money {
+	total := exclusiveTaxes(res.Taxes)
+	for _, night := range res.Nights {
+		total = total.Add(night.Price)
+	}
+	return total
+}
+
+// quote carrega a política da reserva e calcula o cancelamento em at
+func (s *reservationService) quote(res model.Reservation, at time.Time) (model.CancellationQuote, error) {
+	policy, err := loadPolicy(s.policies, res.CancellationPolicyID)
+	if err != nil {
+		return model.CancellationQuote{}, err
+	}
+	return quoteCancellation(res, policy, at)
+}
+
+// mapStatusChanged traduz a escrita recusada porque o status mudou entre a leitura e o update
+func mapStatusChanged(err error) error {
+	if errors.Is(err, dao.ErrReservationStatusChanged) {
+		return conflict("status_changed", "%s", err.Error())
+	}
+	return err
+}
+
+// chargeNights recalcula as noites até a saída. Desde a migração 0016 toda reserva tem
+// o detalhamento por noite, e o total_amount é refeito pela conta na gravação
+func chargeNights(res model.Reservation, departure time.Time) (model.Money, []model.NightlyRate) {
+	end := departure.Format("2006-01-02")
+	total := model.NewMoney(0)
+	nights := []model.NightlyRate{}
+	for _, night := range res.Nights {
+		if night.Date < end {
+			nights = append(nights, night)
+			total = total.Add(night.Price)
+		}
+	}
+	return total, nights
+}
+
+// resolveGuest vincula a reserva a um hóspede. Com guest_id, o nome vem do cadastro;
+// sem ele, mantém o hóspede atual se o nome não mudou ou cadastra um novo hóspede
+func (s *reservationService) resolveGuest(res *model.Reservation, current model.Reservation) error {
+	if res.GuestID == "" {
+		name := strings.TrimSpace(res.GuestName)
+		if current.GuestID != "" && (name == "" || name == current.GuestName) {
+			res.GuestID, res.GuestName = current.GuestID, current.GuestName
+			return nil
+		}
+
+		guest := model.Guest{Name: name}
+		if err := guest.Validate(); err != nil {
+			var v model.ValidationError
+			v.Add("guest_name", "invalid_length", "guest_id or a guest_name of 1 to 120 characters is required")
+			return v.Err()
+		}
+		id, err := s.guests.InsertGuest(guest)
+		if err != nil {
+			return err
+		}
+		res.GuestID, res.GuestName = id, name
+		return nil
+	}
+
+	guest, err := s.guests.GetGuestByID(res.GuestID)
+	if err != nil {
+		return err
+	}
+	if guest.ID == "" {
+		var v model.ValidationError
+		v.Add("guest_id", "not_found", fmt.Sprintf("guest %s not found", res.GuestID))
+		return v.Err()
+	}
+	res.GuestName = guest.Name
+	return nil
+}
+
+// pricingRoom carrega o quarto da reserva e fixa room_type pelo dele. Sem quarto, a
+// reserva é do tipo informado e o preço vem do rackRoom do tipo
+func (s *reservationService) pricingRoom(res *model.Reservation) (model.Room, error) {
+	var v model.ValidationError
+	if res.RoomID == "" {
+		switch {
+		case res.RoomType == "":
+			v.Add("room_id", "required", "room_id or room_type is required")
+			return model.Room{}, v.Err()
+		case !model.ValidRoomType(res.RoomType):
+			v.Add("room_type", "invalid_value", "must be one of: STANDARD, DELUXE, SUITE")
+			return model.Room{}, v.Err()
+		}
+		rooms, err := s.rooms.GetAllRooms()
+		if err != nil {
+			return model.Room{}, err
+		}
+		room := rackRoom(rooms, res.RoomType)
+		if room.ID == "" {
+			v.Add("room_type", "not_found", fmt.Sprintf("the hotel has no %s rooms", res.RoomType))
+			return model.Room{}, v.Err()
+		}
+		return room, nil
+	}
+
+	room, err := s.rooms.GetRoomByID(res.RoomID)
+	if err != nil {
+		return model.Room{}, err
+	}
+	switch {
+	case room.ID == "":
+		v.Add("room_id", "not_found", fmt.Sprintf("room %s not found", res.RoomID))
+	case room.DeletedAt != nil:
+		v.Add("room_id", "archived", fmt.Sprintf("room %d is archived", room.Number))
+	case res.RoomType != "" && res.RoomType != room.Type:
+		v.Add("room_type", "mismatch", fmt.Sprintf("room %d is a %s room", room.Number, room.Type))
+	}
+	if err := v.Err(); err != nil {
+		return model.Room{}, err
+	}
+	res.RoomType = room.Type
+	return room, nil
+}
+
+// assignableRoom carrega o quarto que vai ser atribuído à reserva: não arquivado e do
+// tipo reservado
+func (s *reservationService) assignableRoom(res model.Reservation, roomID string) (model.Room, error) {
+	room, err := s.rooms.GetRoomByID(roomID)
+	if err != nil {
+		return model.Room{}, err
+	}
+	var v model.ValidationError
+	switch {
+	case room.ID == "":
+		v.Add("room_id", "not_found", fmt.Sprintf("room %s not found", roomID))
+	case room.DeletedAt != nil:
+		v.Add("room_id", "archived", fmt.Sprintf("room %d is archived", room.Number))
+	case room.Type != res.RoomType:
+		v.Add("room_id", "type_mismatch", fmt.Sprintf("room %d is a %s room and the reservation is for %s", room.Number, room.Type, res.RoomType))
+	}
+	return room, v.Err()
+}
+
+// autoAssign tenta os quartos na ordem de rankRooms até um ser atribuído; outro pedido
+// pode ter ocupado o quarto desde a leitura, e então vale o próximo. Um Room vazio indica
+// que nenhum quarto do tipo está livre na estadia inteira
+func (s *reservationService) autoAssign(res model.Reservation, rooms []model.Room, actor model.Actor) (model.Room, error) {
+	checkin, checkout, err := parseStay("checkin_expected", res.CheckinExpected, "checkout_expected", res.CheckoutExpected)
+	if err != nil {
+		return model.Room{}, err
+	}
+	bookings, err := s.reservations.GetRoomTypeBookings(res.RoomType, checkin.AddDate(0, 0, -assignmentHorizon), checkout.AddDate(0, 0, assignmentHorizon))
+	if err != nil {
+		return model.Room{}, err
+	}
+	for _, room := range rankRooms(res, checkin, checkout, rooms, bookings) {
+		err := s.reservations.AssignRoom(res.ID, room.ID, actor)
+		var taken *model.ReservationConflictError
+		var held *model.RoomHeldError
+		if errors.As(err, &taken) || errors.As(err, &held) {
+			continue
+		}
+		if err != nil {
+			return model.Room{}, mapStatusChanged(err)
+		}
+		return room, nil
+	}
+	return model.Room{}, nil
+}
+
+// Limites da atribuição automática: quantos dias antes e depois da estadia contam para o
+// tamanho de um buraco e quantos dias de chegadas uma execução cobre
+const (
+	assignmentHorizon  = 30
+	maxAssignmentRange = 31 * 24 * time.Hour
+)
+
+// rankRooms ordena os quartos ATIVO do tipo livres na estadia pelo buraco que ela deixaria
+// entre as reservas já atribuídas a cada um (best fit): as noites vazias antes da
+// chegada mais as noites vazias depois da saída, cada lado limitado a
+// assignmentHorizon. O quarto em que a estadia encaixa sem sobras vem primeiro, e os
+// períodos livres mais longos ficam para as estadias longas; no empate, o menor número
+func rankRooms(res model.Reservation, checkin, checkout time.Time, rooms []model.Room, bookings []model.Reservation) []model.Room {
+	type candidate struct {
+		room model.Room
+		gap  int
+	}
+	var candidates []candidate
+	for _, room := range rooms {
+		if room.Type != res.RoomType || room.Status != "ATIVO" || room.DeletedAt != nil {
+			continue
+		}
+		before, after, free := assignmentHorizon, assignmentHorizon, true
+		for _, b := range bookings {
+			if b.RoomID != room.ID || b.ID == res.ID {
+				continue
+			}
+			start, end, err := parseStay("checkin_expected", b.CheckinExpected, "checkout_expected", b.CheckoutExpected)
+			if err != nil {
+				continue
+			}
+			switch {
+			case start.Before(checkout) && checkin.Before(end):
+				free = false
+			case !end.After(checkin):
+				before = min(before, int(checkin.Sub(end).Hours()/24))
+			default:
+				after = min(after, int(start.Sub(checkout).Hours()/24))
+			}
+		}
+		if free {
+			candidates = append(candidates, candidate{room: room, gap: before + after})
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].gap != candidates[j].gap {
+			return candidates[i].gap < candidates[j].gap
+		}
+		return candidates[i].room.Number < candidates[j].room.Number
+	})
+	ranked := make([]model.Room, len(candidates))
+	for i, c := range candidates {
+		ranked[i] = c.room
+	}
+	return ranked
+}
+
+// applyPrice preenche, pelo preço de room, total_amount, o detalhamento por noite e a
+// política de cancelamento: a do plano da noite de chegada ou, sem ela, a do quarto
+func (s *reservationService) applyPrice(res *model.Reservation, room model.Room, checkin, checkout time.Time) error {
+	total, nights, err := s.pricing.Quote(room, checkin, checkout)
+	if err != nil {
+		return err
+	}
+	res.TotalAmount, res.Nights = total, nights
+
+	res.CancellationPolicyID = room.CancellationPolicyID
+	if len(nights) > 0 && nights[0].RatePlanID != "" {
+		plan, err := s.ratePlans.GetRatePlanByID(nights[0].RatePlanID)
+		if err != nil {
+			return err
+		}
+		if plan.CancellationPolicyID != "" {
+			res.CancellationPolicyID = plan.CancellationPolicyID
+		}
+	}
+	return nil
+}
+
+// parseStay valida o formato e a ordem das datas da estadia; os nomes dos campos
+// vão nos erros de validação
+func parseStay(checkinField, checkinStr, checkoutField, checkoutStr string) (time.Time, time.Time, error) {
+	var v model.ValidationError
 	layout := "2006-01-02"
 	checkin, err := time.Parse(layout, checkinStr)
 	if err != nil {
-		return time.Time{}, time.Time{}, fmt.Errorf("invalid checkin_expected format (expected YYYY-MM-DD)")
+		v.Add(checkinField, "invalid_format", "expected YYYY-MM-DD")
 	}
 	checkout, err := time.Parse(layout, checkoutStr)
 	if err != nil {
-		return time.Time{}, time.Time{}, fmt.Errorf("invalid checkout_expected format (expected YYYY-MM-DD)")
+		v.Add(checkoutField, "invalid_format", "expected YYYY-MM-DD")
+	}
+	if len(v.Errors) == 0 && !checkout.After(checkin) {
+		v.Add(checkoutField, "out_of_range", "must be after "+checkinField)
 	}
-	return checkin, checkout, nil
+	return checkin, checkout, v.Err()
 }
 
+// validateStatusTransition aceita pelo PUT só as mudanças de validTransitions
 func validateStatusTransition(current, next string) error {
 	if current == next {
 		return nil
 	}
-	validNext, ok := validTransitions[current]
-	if !ok {
-		return fmt.Errorf("invalid current status: %s", current)
-	}
-	for _, allowed := range validNext {
+	for _, allowed := range validTransitions[current] {
 		if next == allowed {
 			return nil
 		}
 	}
-	return fmt.Errorf("invalid status transition: %s → %s", current, next)
+	return invalidTransition("invalid_transition", "invalid status transition: %s → %s", current, next)
+}
+
+// validatePayment exige o cartão da pré-autorização que confirma a reserva pelo PUT
+func validatePayment(payment *model.CardPaymentRequest) error {
+	if payment == nil {
+		var v model.ValidationError
+		v.Add("payment", "required", "is required to confirm a reservation")
+		return v.Err()
+	}
+	return payment.Validate()
 }
@@ -0,0 +1,221 @@
+package service
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"hotel-soa/dao"
+	"hotel-soa/db/dbtest"
+	"hotel-soa/gateway"
+	"hotel-soa/migrations"
+	"hotel-soa/model"
+)
+
+// raceWorkers é o número de reservas disparadas ao mesmo tempo para o mesmo quarto
+const raceWorkers = 50
+
+// testActor é o autor registrado no audit_log pelas escritas dos testes
+var testActor = model.Actor{Username: "test"}
+
+// testBackends lista os backends dos testes de serviço: o em memória roda sempre e o
+// Postgres dos DB_* só com STORAGE_BACKEND=postgres, num schema novo já migrado
+var testBackends = []struct {
+	name  string
+	repos func(t *testing.T) dao.Repositories
+}{
+	{"memory", func(t *testing.T) dao.Repositories { return dao.NewMemoryRepositories() }},
+	{"postgres", func(t *testing.T) dao.Repositories {
+		conn := dbtest.Open(t)
+		migrator, err := migrations.NewMigrator(conn)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := migrator.Up(); err != nil {
+			t.Fatal(err)
+		}
+		return dao.NewPostgresRepositories(conn)
+	}},
+}
+
+// isValidationError indica se err traz os erros por campo de model.ValidationError
+func isValidationError(err error) bool {
+	var validation *model.ValidationError
+	return errors.As(err, &validation)
+}
+
+// TestCreateConcurrentBookings dispara reservas simultâneas para o mesmo quarto e as
+// mesmas datas e confere que exatamente uma é aceita
+func TestCreateConcurrentBookings(t *testing.T) {
+	for _, backend := range testBackends {
+		t.Run(backend.name, func(t *testing.T) {
+			repos := backend.repos(t)
+
+			room := model.Room{Number: 101, Type: "STANDARD", Capacity: 1, PricePerNight: model.NewMoney(10000), Status: "ATIVO"}
+			roomID, err := NewRoomService(repos.Rooms, repos.Policies).Create(room, testActor)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			guestID, err := repos.Guests.InsertGuest(model.Guest{Name: "Race Test"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			reservations := NewReservationService(repos.Reservations, repos.Rooms, repos.RatePlans, repos.Guests, repos.Policies, repos.Taxes, repos.Folios, repos.Holds, gateway.NewStubGateway(), 30)
+			checkin := time.Now().AddDate(1, 0, 0)
+			res := model.Reservation{
+				RoomID:           roomID,
+				GuestID:          guestID,
+				CheckinExpected:  checkin.Format("2006-01-02"),
+				CheckoutExpected: checkin.AddDate(0, 0, 3).Format("2006-01-02"),
+			}
+
+			var (
+				wg       sync.WaitGroup
+				mu       sync.Mutex
+				created  []string
+				failures []error
+			)
+			start := make(chan struct{})
+			for i := 0; i < raceWorkers; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					<-start
+					booked, err := reservations.Create(res, "", nil, testActor)
+					var conflict *model.ReservationConflictError
+					mu.Lock()
+					defer mu.Unlock()
+					switch {
+					case err == nil:
+						created = append(created, booked.ID)
+					case errors.As(err, &conflict):
+					default:
+						failures = append(failures, err)
+					}
+				}()
+			}
+			close(start)
+			wg.Wait()
+
+			if len(failures) > 0 {
+				t.Fatalf("unexpected errors: %v", failures)
+			}
+			if len(created) != 1 {
+				t.Fatalf("expected exactly one booking to succeed, got %d", len(created))
+			}
+		})
+	}
+}
+
+func TestReservationPricing(t *testing.T) {
+	repos := dao.NewMemoryRepositories()
+	standard, err := repos.Rooms.InsertRoom(model.Room{Number: 101, Type: "STANDARD", Capacity: 2, PricePerNight: model.NewMoney(10000), Status: "ATIVO"}, testActor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	deluxe, err := repos.Rooms.InsertRoom(model.Room{Number: 201, Type: "DELUXE", Capacity: 2, PricePerNight: model.NewMoney(25000), Status: "ATIVO"}, testActor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reservations := NewReservationService(repos.Reservations, repos.Rooms, repos.RatePlans, repos.Guests, repos.Policies, repos.Taxes, repos.Folios, repos.Holds, gateway.NewStubGateway(), 30)
+
+	// o total enviado pelo cliente é ignorado
+	res, err := reservations.Create(model.Reservation{RoomID: standard, GuestName: "Ana", CheckinExpected: "2026-06-10", CheckoutExpected: "2026-06-12", TotalAmount: model.NewMoney(100)}, "", nil, testActor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.TotalAmount.Equal(model.NewMoney(20000)) || len(res.Nights) != 2 {
+		t.Fatalf("expected 200 over 2 nights, got %+v", res)
+	}
+
+	tests := []struct {
+		name     string
+		change   func(res *model.Reservation)
+		total    int64
+		nights   int
+		newPrice int64
+	}{
+		// o preço do quarto muda, mas a reserva mantém o total já fechado
+		{"same dates and room", func(res *model.Reservation) { res.GuestName = "Ana Maria" }, 20000, 2, 15000},
+		{"new checkout", func(res *model.Reservation) { res.CheckoutExpected = "2026-06-13" }, 45000, 3, 15000},
+		{"new room", func(res *model.Reservation) { res.RoomID, res.RoomType = deluxe, "DELUXE" }, 75000, 3, 15000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			room, _ := repos.Rooms.GetRoomByID(standard)
+			room.PricePerNight = model.NewMoney(tt.newPrice)
+			if err := repos.Rooms.UpdateRoom(room, testActor); err != nil {
+				t.Fatal(err)
+			}
+			tt.change(&res)
+			res.TotalAmount = model.NewMoney(100)
+			updated, err := reservations.Update(res, nil, testActor)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !updated.TotalAmount.Equal(model.NewMoney(tt.total)) || len(updated.Nights) != tt.nights {
+				t.Fatalf("expected %v over %d nights, got %+v", tt.total, tt.nights, updated)
+			}
+			stored, _ := reservations.GetByID(res.ID)
+			if !stored.TotalAmount.Equal(model.NewMoney(tt.total)) || len(stored.Nights) != tt.nights {
+				t.Fatalf("expected the stored reservation to match, got %+v", stored)
+			}
+			res = updated
+		})
+	}
+
+	unknown := model.Reservation{RoomID: "missing", GuestName: "Ana", CheckinExpected: "2026-07-10", CheckoutExpected: "2026-07-12"}
+	if _, err := reservations.Create(unknown, "", nil, testActor); !isValidationError(err) {
+		t.Fatalf("expected a validation error for an unknown room, got %v", err)
+	}
+}
+
+// TestUpdateClosedReservation confere que o PUT não muda datas nem preço de uma reserva
+// cancelada, mas continua aceitando os demais campos
+func TestUpdateClosedReservation(t *testing.T) {
+	repos := dao.NewMemoryRepositories()
+	roomID, err := NewRoomService(repos.Rooms, repos.Policies).Create(model.Room{Number: 101, Type: "STANDARD", Capacity: 2, PricePerNight: model.NewMoney(10000), Status: "ATIVO"}, testActor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reservations := NewReservationService(repos.Reservations, repos.Rooms, repos.RatePlans, repos.Guests, repos.Policies, repos.Taxes, repos.Folios, repos.Holds, gateway.NewStubGateway(), 30)
+	checkin := time.Now().AddDate(0, 1, 0)
+	res, err := reservations.Create(model.Reservation{
+		RoomID:           roomID,
+		GuestName:        "Closed Test",
+		CheckinExpected:  checkin.Format("2006-01-02"),
+		CheckoutExpected: checkin.AddDate(0, 0, 2).Format("2006-01-02"),
+	}, "", nil, testActor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	canceled, err := reservations.Cancel(res.ID, "", testActor)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		checkout string
+		wantErr  error
+	}{
+		{"new dates", checkin.AddDate(0, 0, 5).Format("2006-01-02"), ErrInvalidTransition},
+		{"same dates", res.CheckoutExpected, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			update := canceled
+			update.GuestName, update.CheckoutExpected = "Renamed", tt.checkout
+			updated, err := reservations.Update(update, nil, testActor)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if err == nil && !updated.TotalAmount.Equal(canceled.TotalAmount) {
+				t.Fatalf("total changed from %s to %s", canceled.TotalAmount, updated.TotalAmount)
+			}
+		})
+	}
+}
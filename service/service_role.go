@@ -0,0 +1,167 @@
+package service
+
+import (
+	"hotel-soa/dao"
+	"hotel-soa/model"
+	"sort"
+)
+
+type RoleService interface {
+	List() ([]model.Role, error)
+	GetByName(name string) (model.Role, error)
+	Save(role model.Role) (model.Role, error)
+	Delete(name string) error
+	SetUserRoles(userID string, roles []string) (model.User, error)
+	// EnsureDefaults cria os papéis padrão que faltam e mantém o admin com todas
+	// as permissões; papéis padrão já existentes preservam as edições
+	EnsureDefaults() error
+}
+
+type roleService struct {
+	roles dao.RoleRepository
+	users dao.UserRepository
+}
+
+func NewRoleService(roles dao.RoleRepository, users dao.UserRepository) RoleService {
+	return &roleService{roles: roles, users: users}
+}
+
+func (s *roleService) List() ([]model.Role, error) {
+	roles, err := s.roles.GetAllRoles()
+	if roles == nil {
+		roles = []model.Role{}
+	}
+	return roles, err
+}
+
+func (s *roleService) GetByName(name string) (model.Role, error) {
+	role, err := s.roles.GetRole(name)
+	if err != nil {
+		return model.Role{}, err
+	}
+	if role.Name == "" {
+		return model.Role{}, notFound("role_not_found", "role %s not found", name)
+	}
+	return role, nil
+}
+
+// Save cria ou substitui um papel; o admin não pode ser alterado para que sempre
+// exista quem administre usuários e papéis
+func (s *roleService) Save(role model.Role) (model.Role, error) {
+	if err := role.Validate(); err != nil {
+		return model.Role{}, err
+	}
+	if role.Name == model.RoleAdmin {
+		return model.Role{}, conflict("built_in_role", "role %s always has every permission", role.Name)
+	}
+
+	existing, err := s.roles.GetRole(role.Name)
+	if err != nil {
+		return model.Role{}, err
+	}
+	role.BuiltIn = existing.BuiltIn
+	if role.Permissions == nil {
+		role.Permissions = []string{}
+	}
+	if err := s.roles.SaveRole(role); err != nil {
+		return model.Role{}, err
+	}
+	return role, nil
+}
+
+func (s *roleService) Delete(name string) error {
+	role, err := s.GetByName(name)
+	if err != nil {
+		return err
+	}
+	if role.BuiltIn {
+		return conflict("built_in_role", "role %s is built in and cannot be deleted", name)
+	}
+	_, err = s.roles.DeleteRole(name)
+	return err
+}
+
+func (s *roleService) SetUserRoles(userID string, names []string) (model.User, error) {
+	user, err := s.users.GetUserByID(userID)
+	if err != nil {
+		return model.User{}, err
+	}
+	if user.ID == "" {
+		return model.User{}, notFound("user_not_found", "user %s not found", userID)
+	}
+
+	names, err = checkRoles(s.roles, names)
+	if err != nil {
+		return model.User{}, err
+	}
+	if err := s.roles.SetUserRoles(userID, names); err != nil {
+		return model.User{}, err
+	}
+	user.Roles = names
+	return user, nil
+}
+
+func (s *roleService) EnsureDefaults() error {
+	for _, role := range model.DefaultRoles() {
+		existing, err := s.roles.GetRole(role.Name)
+		if err != nil {
+			return err
+		}
+		if existing.Name != "" && role.Name != model.RoleAdmin {
+			continue
+		}
+		role.BuiltIn = true
+		if err := s.roles.SaveRole(role); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkRoles remove repetições e confirma que todos os papéis existem
+func checkRoles(repo dao.RoleRepository, names []string) ([]string, error) {
+	var v model.ValidationError
+	seen := make(map[string]bool)
+	unique := []string{}
+	for _, name := range names {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		role, err := repo.GetRole(name)
+		if err != nil {
+			return nil, err
+		}
+		if role.Name == "" {
+			v.Add("roles", "not_found", "role "+name+" does not exist")
+			continue
+		}
+		unique = append(unique, name)
+	}
+	sort.Strings(unique)
+	return unique, v.Err()
+}
+
+// userAccess junta os nomes dos papéis do usuário e a união das suas permissões
+func userAccess(repo dao.RoleRepository, userID string) ([]string, []string, error) {
+	roles, err := repo.GetUserRoles(userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	names := []string{}
+	granted := make(map[string]bool)
+	for _, role := range roles {
+		names = append(names, role.Name)
+		for _, p := range role.Permissions {
+			granted[p] = true
+		}
+	}
+	permissions := []string{}
+	for _, p := range model.Permissions {
+		if granted[p] {
+			permissions = append(permissions, p)
+		}
+	}
+	return names, permissions, nil
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"hotel-soa/dao"
+	"hotel-soa/model"
+)
+
+func TestRoleService(t *testing.T) {
+	repos := dao.NewMemoryRepositories()
+	roles := NewRoleService(repos.Roles, repos.Users)
+	if err := roles.EnsureDefaults(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		run     func() error
+		wantErr error
+	}{
+		{"edit admin", func() error {
+			_, err := roles.Save(model.Role{Name: model.RoleAdmin, Permissions: []string{model.PermRoomsRead}})
+			return err
+		}, ErrConflict},
+		{"delete built in", func() error { return roles.Delete(model.RoleFrontDesk) }, ErrConflict},
+		{"delete missing", func() error { return roles.Delete("night_audit") }, ErrNotFound},
+		{"roles of missing user", func() error {
+			_, err := roles.SetUserRoles("missing", []string{model.RoleReadOnly})
+			return err
+		}, ErrNotFound},
+		{"create", func() error {
+			_, err := roles.Save(model.Role{Name: "night_audit", Permissions: []string{model.PermReservationsRead}})
+			return err
+		}, nil},
+		{"delete custom", func() error { return roles.Delete("night_audit") }, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.run(); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	// um papel padrão editado continua padrão e mantém a edição depois de EnsureDefaults
+	edited, err := roles.Save(model.Role{Name: model.RoleHousekeeping, Permissions: []string{model.PermRoomsRead}})
+	if err != nil || !edited.BuiltIn {
+		t.Fatalf("expected housekeeping to stay built in, got %+v, %v", edited, err)
+	}
+	if err := roles.EnsureDefaults(); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := roles.GetByName(model.RoleHousekeeping); !reflect.DeepEqual(got.Permissions, []string{model.PermRoomsRead}) {
+		t.Fatalf("expected the edit to be kept, got %+v", got)
+	}
+}
+
+func TestRoleChangesApplyToIssuedTokens(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	repos := dao.NewMemoryRepositories()
+	auth := newAuthTestService(t, repos, &now)
+	roles := NewRoleService(repos.Roles, repos.Users)
+
+	user, err := auth.CreateUser(model.UserRequest{Username: "ana", Password: "correct horse"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(user.Roles, []string{model.RoleReadOnly}) {
+		t.Fatalf("expected read_only by default, got %v", user.Roles)
+	}
+	if _, err := roles.SetUserRoles(user.ID, []string{"missing"}); !isValidationError(err) {
+		t.Fatalf("expected a validation error for an unknown role, got %v", err)
+	}
+
+	tokens, err := auth.Login("ana", "correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	can := func(permission string) bool {
+		principal, err := auth.Authenticate(tokens.AccessToken)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return principal.Can(permission)
+	}
+	if can(model.PermReservationsCheckIn) {
+		t.Fatal("expected read_only not to check guests in")
+	}
+	// o mesmo token passa a valer o papel novo
+	if _, err := roles.SetUserRoles(user.ID, []string{model.RoleFrontDesk, model.RoleFrontDesk}); err != nil {
+		t.Fatal(err)
+	}
+	if !can(model.PermReservationsCheckIn) || can(model.PermRoomsUpdatePrice) {
+		t.Fatal("expected the front desk permissions to apply to the issued token")
+	}
+}
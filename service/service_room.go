@@ -1,40 +1,110 @@
 package service
 
 import (
+	"errors"
 	"hotel-soa/dao"
 	"hotel-soa/model"
+	"time"
 )
 
 type RoomService interface {
-	Create(room model.Room) (string, error)
-	Update(room model.Room) error
-	Delete(id string) error
+	Create(room model.Room, actor model.Actor) (string, error)
+	Update(room model.Room, actor model.Actor) error
+	// Archive é o que o DELETE faz: o quarto some das listagens, mas fica no banco
+	Archive(id string, actor model.Actor) error
+	// Purge apaga o quarto de vez, para registros lançados por engano
+	Purge(id string, actor model.Actor) error
 	GetByID(id string) (model.Room, error)
-	GetAll() ([]model.Room, error)
+	List(filter model.RoomFilter) (model.RoomPage, int, error)
 }
 
-type roomService struct{}
+type roomService struct {
+	rooms    dao.RoomRepository
+	policies dao.CancellationPolicyRepository
+	now      func() time.Time
+}
+
+func NewRoomService(rooms dao.RoomRepository, policies dao.CancellationPolicyRepository) RoomService {
+	return &roomService{rooms: rooms, policies: policies, now: time.Now}
+}
 
-func NewRoomService() RoomService {
-	return &roomService{}
+func (s *roomService) Create(room model.Room, actor model.Actor) (string, error) {
+	if err := checkPolicyExists(s.policies, room.CancellationPolicyID); err != nil {
+		return "", err
+	}
+	id, err := s.rooms.InsertRoom(room, actor)
+	return id, mapDuplicateRoomNumber(err)
 }
 
-func (s *roomService) Create(room model.Room) (string, error) {
-	return dao.InsertRoom(room)
+func (s *roomService) Update(room model.Room, actor model.Actor) error {
+	current, err := s.GetByID(room.ID)
+	if err != nil {
+		return err
+	}
+	if current.DeletedAt != nil {
+		return conflict("room_archived", "room %d is archived", current.Number)
+	}
+	if err := checkPolicyExists(s.policies, room.CancellationPolicyID); err != nil {
+		return err
+	}
+	return mapDuplicateRoomNumber(s.rooms.UpdateRoom(room, actor))
 }
 
-func (s *roomService) Update(room model.Room) error {
-	return dao.UpdateRoom(room)
+// Archive recusa quartos com reservas ativas que ainda não terminaram; o erro
+// *model.RoomInUseError lista essas reservas
+func (s *roomService) Archive(id string, actor model.Actor) error {
+	room, err := s.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if room.DeletedAt != nil {
+		return conflict("room_archived", "room %d is already archived", room.Number)
+	}
+	return s.rooms.ArchiveRoom(id, s.now(), actor)
 }
 
-func (s *roomService) Delete(id string) error {
-	return dao.DeleteRoom(id)
+// Purge só apaga quartos sem nenhuma reserva, nem mesmo passada ou cancelada
+func (s *roomService) Purge(id string, actor model.Actor) error {
+	if _, err := s.GetByID(id); err != nil {
+		return err
+	}
+	return s.rooms.PurgeRoom(id, actor)
 }
 
 func (s *roomService) GetByID(id string) (model.Room, error) {
-	return dao.GetRoomByID(id)
+	room, err := s.rooms.GetRoomByID(id)
+	if err != nil {
+		return model.Room{}, err
+	}
+	if room.ID == "" {
+		return model.Room{}, notFound("room_not_found", "room %s not found", id)
+	}
+	return room, nil
+}
+
+// List retorna uma página de quartos e o total que atende aos filtros
+func (s *roomService) List(filter model.RoomFilter) (model.RoomPage, int, error) {
+	rooms, total, err := s.rooms.ListRooms(filter)
+	if err != nil {
+		return model.RoomPage{}, 0, err
+	}
+
+	page := model.RoomPage{Data: rooms}
+	if len(rooms) > filter.Page.Limit {
+		page.Data = rooms[:filter.Page.Limit]
+		last := page.Data[len(page.Data)-1]
+		page.NextCursor = model.Cursor{Sort: filter.Page.Sort.String(), Value: last.SortValue(filter.Page.Sort.Field), ID: last.ID}.Encode()
+	}
+	if page.Data == nil {
+		page.Data = []model.Room{}
+	}
+	return page, total, nil
 }
 
-func (s *roomService) GetAll() ([]model.Room, error) {
-	return dao.GetAllRooms()
+// mapDuplicateRoomNumber traduz o número já usado por outro quarto não arquivado
+func mapDuplicateRoomNumber(err error) error {
+	if errors.Is(err, dao.ErrDuplicateRoomNumber) {
+		return conflict("room_number_taken", "%s", err.Error())
+	}
+	return err
 }
@@ -0,0 +1,214 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"hotel-soa/dao"
+	"hotel-soa/model"
+)
+
+// isServiceError indica se err é um *Error com o code informado
+func isServiceError(err error, code string) bool {
+	var e *Error
+	return errors.As(err, &e) && e.Code == code
+}
+
+// insertRooms grava os quartos e devolve os IDs por número
+func insertRooms(t *testing.T, repos dao.Repositories, rooms ...model.Room) map[int]string {
+	t.Helper()
+	ids := map[int]string{}
+	for _, room := range rooms {
+		id, err := repos.Rooms.InsertRoom(room, testActor)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids[room.Number] = id
+	}
+	return ids
+}
+
+func TestBookByRoomType(t *testing.T) {
+	repos := dao.NewMemoryRepositories()
+	rooms := insertRooms(t, repos,
+		model.Room{Number: 101, Type: "STANDARD", Capacity: 2, PricePerNight: model.NewMoney(12000), Status: "ATIVO"},
+		model.Room{Number: 102, Type: "STANDARD", Capacity: 2, PricePerNight: model.NewMoney(10000), Status: "ATIVO"},
+		model.Room{Number: 201, Type: "DELUXE", Capacity: 2, PricePerNight: model.NewMoney(25000), Status: "ATIVO"},
+	)
+	s := newStayTestService(repos, date("2026-06-01").Add(12*time.Hour))
+	stay := func(roomType, checkin, checkout string) model.Reservation {
+		return model.Reservation{RoomType: roomType, GuestName: "Ana", CheckinExpected: checkin, CheckoutExpected: checkout}
+	}
+
+	// sem quarto, o preço é o do quarto mais barato do tipo
+	res, err := s.Create(stay("STANDARD", "2026-06-10", "2026-06-12"), "", nil, testActor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.RoomID != "" || res.RoomType != "STANDARD" || !res.TotalAmount.Equal(model.NewMoney(20000)) {
+		t.Fatalf("expected an unassigned STANDARD reservation of 200, got %+v", res)
+	}
+	// com quarto, o tipo vem dele
+	assigned, err := s.Create(model.Reservation{RoomID: rooms[101], GuestName: "Bia", CheckinExpected: "2026-06-12", CheckoutExpected: "2026-06-14"}, "", nil, testActor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if assigned.RoomType != "STANDARD" {
+		t.Fatalf("expected the room type from the room, got %+v", assigned)
+	}
+	short, err := s.Create(stay("STANDARD", "2026-06-10", "2026-06-11"), "", nil, testActor)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// as duas reservas sem quarto esgotam o STANDARD na noite de 10
+	_, err = s.Create(stay("STANDARD", "2026-06-10", "2026-06-11"), "", nil, testActor)
+	var soldOut *model.RoomTypeSoldOutError
+	if !errors.As(err, &soldOut) || soldOut.RoomType != "STANDARD" || soldOut.Date != "2026-06-10" {
+		t.Fatalf("expected a RoomTypeSoldOutError, got %v", err)
+	}
+
+	for _, tt := range []struct {
+		name string
+		res  model.Reservation
+	}{
+		{"no room nor type", stay("", "2026-07-10", "2026-07-12")},
+		{"unknown type", stay("PENTHOUSE", "2026-07-10", "2026-07-12")},
+		{"type without rooms", stay("SUITE", "2026-07-10", "2026-07-12")},
+		{"room of another type", model.Reservation{RoomID: rooms[201], RoomType: "STANDARD", GuestName: "Ana", CheckinExpected: "2026-07-10", CheckoutExpected: "2026-07-12"}},
+	} {
+		if _, err := s.Create(tt.res, "", nil, testActor); !isValidationError(err) {
+			t.Fatalf("%s: expected a validation error, got %v", tt.name, err)
+		}
+	}
+
+	if _, err := s.AssignRoom(res.ID, rooms[201], testActor); !isValidationError(err) {
+		t.Fatalf("expected a validation error assigning a DELUXE room, got %v", err)
+	}
+	if _, err := s.AssignRoom(short.ID, rooms[101], testActor); err != nil {
+		t.Fatal(err)
+	}
+	var taken *model.ReservationConflictError
+	if _, err := s.AssignRoom(res.ID, rooms[101], testActor); !errors.As(err, &taken) {
+		t.Fatalf("expected a ReservationConflictError, got %v", err)
+	}
+
+	// atribuir o quarto não muda o preço
+	moved, err := s.AssignRoom(res.ID, rooms[102], testActor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if moved.RoomID != rooms[102] || !moved.TotalAmount.Equal(res.TotalAmount) {
+		t.Fatalf("expected room 102 at the same price, got %+v", moved)
+	}
+}
+
+func TestAutoAssign(t *testing.T) {
+	repos := dao.NewMemoryRepositories()
+	rooms := insertRooms(t, repos,
+		model.Room{Number: 101, Type: "STANDARD", Capacity: 2, PricePerNight: model.NewMoney(10000), Status: "ATIVO"},
+		model.Room{Number: 102, Type: "STANDARD", Capacity: 2, PricePerNight: model.NewMoney(10000), Status: "ATIVO"},
+		model.Room{Number: 103, Type: "STANDARD", Capacity: 2, PricePerNight: model.NewMoney(10000), Status: "ATIVO"},
+	)
+	s := newStayTestService(repos, date("2026-06-10").Add(8*time.Hour))
+	create := func(res model.Reservation) model.Reservation {
+		t.Helper()
+		res.GuestName = "Ana"
+		created, err := s.Create(res, "", nil, testActor)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return created
+	}
+
+	// a 102 tem uma reserva a partir de 12, então a estadia de 10 a 12 encaixa nela sem sobras
+	create(model.Reservation{RoomID: rooms[102], CheckinExpected: "2026-06-12", CheckoutExpected: "2026-06-14"})
+	create(model.Reservation{RoomID: rooms[103], CheckinExpected: "2026-06-10", CheckoutExpected: "2026-06-11"})
+	long := create(model.Reservation{RoomType: "STANDARD", CheckinExpected: "2026-06-10", CheckoutExpected: "2026-06-12"})
+	short := create(model.Reservation{RoomType: "STANDARD", CheckinExpected: "2026-06-10", CheckoutExpected: "2026-06-11"})
+	later := create(model.Reservation{RoomType: "STANDARD", CheckinExpected: "2026-06-20", CheckoutExpected: "2026-06-21"})
+
+	// sem corpo, vale a chegada de hoje
+	run, err := s.AutoAssign(model.AutoAssignRequest{}, testActor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []model.RoomAssignment{
+		{ReservationID: long.ID, RoomID: rooms[102], RoomNumber: 102},
+		{ReservationID: short.ID, RoomID: rooms[101], RoomNumber: 101},
+	}
+	if len(run.Assigned) != 2 || run.Assigned[0] != expected[0] || run.Assigned[1] != expected[1] || len(run.Unassigned) != 0 {
+		t.Fatalf("expected %+v, got %+v", expected, run)
+	}
+	if stored, _ := s.GetByID(later.ID); stored.RoomID != "" {
+		t.Fatalf("expected the later arrival to stay unassigned, got %+v", stored)
+	}
+
+	for _, req := range []model.AutoAssignRequest{
+		{From: "2026-06-10", To: "2026-06-09"},
+		{From: "2026-06-10", To: "2026-07-20"},
+		{From: "10/06/2026"},
+	} {
+		if _, err := s.AutoAssign(req, testActor); !isValidationError(err) {
+			t.Fatalf("expected a validation error for %+v, got %v", req, err)
+		}
+	}
+}
+
+func TestCheckInAssignsRoom(t *testing.T) {
+	repos := dao.NewMemoryRepositories()
+	rooms := insertRooms(t, repos,
+		model.Room{Number: 101, Type: "STANDARD", Capacity: 2, PricePerNight: model.NewMoney(10000), Status: "ATIVO"},
+		model.Room{Number: 102, Type: "STANDARD", Capacity: 2, PricePerNight: model.NewMoney(10000), Status: "ATIVO"},
+		model.Room{Number: 201, Type: "DELUXE", Capacity: 2, PricePerNight: model.NewMoney(25000), Status: "ATIVO"},
+	)
+	s := newStayTestService(repos, date("2026-06-10").Add(14*time.Hour))
+	create := func() model.Reservation {
+		t.Helper()
+		res, err := s.Create(model.Reservation{RoomType: "STANDARD", GuestName: "Ana", CheckinExpected: "2026-06-10", CheckoutExpected: "2026-06-12"}, "", nil, testActor)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res
+	}
+
+	// sem room_id, o empate fica com o menor número
+	first := create()
+	checkedIn, err := s.CheckIn(first.ID, "maria", "", testActor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if checkedIn.Status != "CHECKED_IN" || checkedIn.RoomID != rooms[101] {
+		t.Fatalf("expected a check-in in room 101, got %+v", checkedIn)
+	}
+	// depois da chegada a reserva não fica sem quarto
+	checkedIn.RoomID = ""
+	if _, err := s.Update(checkedIn, nil, testActor); !isValidationError(err) {
+		t.Fatalf("expected a validation error removing the room after check-in, got %v", err)
+	}
+
+	second := create()
+	var taken *model.ReservationConflictError
+	if _, err := s.CheckIn(second.ID, "maria", rooms[101], testActor); !errors.As(err, &taken) {
+		t.Fatalf("expected a ReservationConflictError, got %v", err)
+	}
+	if _, err := s.CheckIn(second.ID, "maria", rooms[201], testActor); !isValidationError(err) {
+		t.Fatalf("expected a validation error for a DELUXE room, got %v", err)
+	}
+
+	room, _ := repos.Rooms.GetRoomByID(rooms[102])
+	room.Status = "INATIVO"
+	if err := repos.Rooms.UpdateRoom(room, testActor); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.CheckIn(second.ID, "maria", rooms[102], testActor); !isServiceError(err, "room_inactive") {
+		t.Fatalf("expected room_inactive, got %v", err)
+	}
+	if _, err := s.CheckIn(second.ID, "maria", "", testActor); !isServiceError(err, "no_room_available") {
+		t.Fatalf("expected no_room_available, got %v", err)
+	}
+	if stored, _ := s.GetByID(second.ID); stored.Status != "CREATED" || stored.RoomID != "" {
+		t.Fatalf("expected the reservation to stay unassigned, got %+v", stored)
+	}
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"hotel-soa/dao"
+	"hotel-soa/model"
+)
+
+func TestRoomArchiveAndPurge(t *testing.T) {
+	repos := dao.NewMemoryRepositories()
+	rooms := NewRoomService(repos.Rooms, repos.Policies).(*roomService)
+	rooms.now = func() time.Time { return date("2026-06-01") }
+	reservations := newStayTestService(repos, date("2026-06-01"))
+
+	room := model.Room{Number: 101, Type: "STANDARD", Capacity: 2, PricePerNight: model.NewMoney(10000), Status: "ATIVO"}
+	roomID, err := rooms.Create(room, testActor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	room.ID = roomID
+	res, err := reservations.Create(model.Reservation{RoomID: roomID, GuestName: "Ana", CheckinExpected: "2026-06-10", CheckoutExpected: "2026-06-12"}, "", nil, testActor)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var inUse *model.RoomInUseError
+	if err := rooms.Archive(roomID, testActor); !errors.As(err, &inUse) || inUse.Reservations[0].ID != res.ID {
+		t.Fatalf("expected a RoomInUseError citing %s, got %v", res.ID, err)
+	}
+	if _, err := reservations.Cancel(res.ID, "", testActor); err != nil {
+		t.Fatal(err)
+	}
+	if err := rooms.Archive(roomID, testActor); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		call    func() error
+		wantErr error
+	}{
+		{"archive twice", func() error { return rooms.Archive(roomID, testActor) }, ErrConflict},
+		{"update archived", func() error { return rooms.Update(room, testActor) }, ErrConflict},
+		{"archive unknown", func() error { return rooms.Archive("00000000-0000-0000-0000-000000000000", testActor) }, ErrNotFound},
+		{"purge unknown", func() error { return rooms.Purge("00000000-0000-0000-0000-000000000000", testActor) }, ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	// quarto arquivado não aceita reservas novas
+	if _, err := reservations.Create(model.Reservation{RoomID: roomID, GuestName: "Bia", CheckinExpected: "2026-07-10", CheckoutExpected: "2026-07-12"}, "", nil, testActor); !isValidationError(err) {
+		t.Fatalf("expected a validation error booking an archived room, got %v", err)
+	}
+
+	// a reserva cancelada ainda impede a remoção definitiva; sem ela o quarto sai de vez
+	if err := rooms.Purge(roomID, testActor); !errors.As(err, &inUse) {
+		t.Fatalf("expected a RoomInUseError, got %v", err)
+	}
+	if err := reservations.Purge(res.ID, testActor); err != nil {
+		t.Fatal(err)
+	}
+	if err := rooms.Purge(roomID, testActor); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rooms.GetByID(roomID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound after purge, got %v", err)
+	}
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"errors"
+	"hotel-soa/dao"
+	"hotel-soa/gateway"
+	"hotel-soa/model"
+	"time"
+)
+
+// StayMonitor é o trabalho periódico que fecha as chegadas que não aconteceram e
+// sinaliza os hóspedes que passaram do horário de saída
+type StayMonitor interface {
+	// Run marca como NO_SHOW as reservas CREATED ou CONFIRMED cuja chegada passou do corte,
+	// cobrando a multa do depósito pré-autorizado e liberando o resto, e sinaliza os
+	// overstays; pode ser executado várias vezes, cada reserva é tratada uma vez
+	Run(now time.Time) (model.StayMonitorRun, error)
+}
+
+type stayMonitor struct {
+	reservations dao.ReservationRepository
+	policies     dao.CancellationPolicyRepository
+	payments     *cardPayments
+	cutoffHour   int
+	deadlineHour int
+}
+
+// NewStayMonitor cria o StayMonitor. Uma reserva vira NO_SHOW a partir de cutoffHour do
+// dia seguinte à chegada e um hóspede é overstay a partir de deadlineHour do dia de saída
+func NewStayMonitor(reservations dao.ReservationRepository, policies dao.CancellationPolicyRepository, folios dao.FolioRepository, payments gateway.PaymentGateway, cutoffHour, deadlineHour int) StayMonitor {
+	return &stayMonitor{
+		reservations: reservations,
+		policies:     policies,
+		payments:     newCardPayments(payments, folios, 0),
+		cutoffHour:   cutoffHour,
+		deadlineHour: deadlineHour,
+	}
+}
+
+func (m *stayMonitor) Run(now time.Time) (model.StayMonitorRun, error) {
+	var run model.StayMonitorRun
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	// 1. No-show: antes do corte, a chegada de ontem ainda não conta
+	arrivalBy := today.AddDate(0, 0, -1)
+	if now.Hour() < m.cutoffHour {
+		arrivalBy = arrivalBy.AddDate(0, 0, -1)
+	}
+	unarrived, err := m.reservations.GetUnarrivedReservations(arrivalBy)
+	if err != nil {
+		return run, err
+	}
+	for _, res := range unarrived {
+		policy, err := loadPolicy(m.policies, res.CancellationPolicyID)
+		if err != nil {
+			return run, err
+		}
+		quote, err := quoteCancellation(res, policy, now)
+		if err != nil {
+			return run, err
+		}
+		// outra instância ou a recepção pode ter mexido na reserva desde a leitura
+		err = m.reservations.MarkNoShow(res.ID, now, quote, model.SystemActor)
+		if errors.Is(err, dao.ErrReservationStatusChanged) {
+			continue
+		}
+		if err != nil {
+			return run, err
+		}
+		run.NoShows++
+		// como no cancelamento, a multa sai do depósito e a autorização que o gateway não
+		// liberou expira no emissor
+		m.payments.chargeFee(res.ID, quote.Fee, model.SystemActor)
+	}
+
+	// 2. Overstay: antes do horário limite, a saída de hoje ainda não conta
+	checkoutBy := today
+	if now.Hour() < m.deadlineHour {
+		checkoutBy = checkoutBy.AddDate(0, 0, -1)
+	}
+	run.Overstays, err = m.reservations.FlagOverstays(checkoutBy, now, model.SystemActor)
+	return run, err
+}
+
+// loadPolicy busca a política fixada na reserva; nil quando não há política
+func loadPolicy(policies dao.CancellationPolicyRepository, id string) (*model.CancellationPolicy, error) {
+	if id == "" {
+		return nil, nil
+	}
+	policy, err := policies.GetCancellationPolicyByID(id)
+	if err != nil || policy.ID == "" {
+		return nil, err
+	}
+	return &policy, nil
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"hotel-soa/dao"
+	"hotel-soa/gateway"
+	"hotel-soa/model"
+)
+
+func TestStayMonitorRun(t *testing.T) {
+	repos := dao.NewMemoryRepositories()
+	policy, err := repos.Policies.InsertCancellationPolicy(model.CancellationPolicy{Name: "One night", FreeUntilDays: 1, PenaltyType: model.PenaltyNights, PenaltyValue: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	roomID, err := repos.Rooms.InsertRoom(model.Room{Number: 101, Type: "STANDARD", Capacity: 2, PricePerNight: model.NewMoney(10000), Status: "ATIVO", CancellationPolicyID: policy}, testActor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherID, err := repos.Rooms.InsertRoom(model.Room{Number: 102, Type: "STANDARD", Capacity: 2, PricePerNight: model.NewMoney(10000), Status: "ATIVO"}, testActor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := newStayTestService(repos, date("2026-06-08").Add(14*time.Hour))
+	late, err := s.Create(model.Reservation{RoomID: roomID, GuestName: "Ana", CheckinExpected: "2026-06-10", CheckoutExpected: "2026-06-12"}, "", nil, testActor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	staying, err := s.Create(model.Reservation{RoomID: otherID, GuestName: "Bia", CheckinExpected: "2026-06-08", CheckoutExpected: "2026-06-11"}, "", nil, testActor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.CheckIn(staying.ID, "maria", "", testActor); err != nil {
+		t.Fatal(err)
+	}
+
+	monitor := NewStayMonitor(repos.Reservations, repos.Policies, repos.Folios, gateway.NewStubGateway(), 6, 12)
+	tests := []struct {
+		name     string
+		now      time.Time
+		expected model.StayMonitorRun
+	}{
+		// a chegada de 10/06 só vira no-show às 6h de 11/06 e a saída de 11/06 só atrasa às 12h
+		{"arrival day", time.Date(2026, 6, 10, 23, 0, 0, 0, time.UTC), model.StayMonitorRun{}},
+		{"before the cutoff", time.Date(2026, 6, 11, 5, 59, 0, 0, time.UTC), model.StayMonitorRun{}},
+		{"after the cutoff", time.Date(2026, 6, 11, 6, 0, 0, 0, time.UTC), model.StayMonitorRun{NoShows: 1}},
+		{"after the deadline", time.Date(2026, 6, 11, 12, 0, 0, 0, time.UTC), model.StayMonitorRun{Overstays: 1}},
+		{"nothing left", time.Date(2026, 6, 11, 13, 0, 0, 0, time.UTC), model.StayMonitorRun{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			run, err := monitor.Run(tt.now)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if run != tt.expected {
+				t.Fatalf("expected %+v, got %+v", tt.expected, run)
+			}
+		})
+	}
+
+	// a multa do no-show segue a política fixada na reserva
+	noShow, err := s.GetByID(late.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if noShow.Status != "NO_SHOW" || !noShow.NoShowFee.Equal(model.NewMoney(10000)) || !noShow.RefundAmount.Equal(model.NewMoney(10000)) {
+		t.Fatalf("expected a no-show with a fee of one night, got %+v", noShow)
+	}
+	alerts, err := s.Alerts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(alerts) != 1 || alerts[0].ReservationID != staying.ID {
+		t.Fatalf("expected an alert for %s, got %+v", staying.ID, alerts)
+	}
+
+	// o PUT não leva uma reserva a NO_SHOW
+	noShow.Status = "CREATED"
+	if _, err := s.Update(noShow, nil, testActor); err == nil {
+		t.Fatal("expected an error reopening a no-show")
+	}
+}
@@ -0,0 +1,294 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"hotel-soa/dao"
+	"hotel-soa/gateway"
+	"hotel-soa/model"
+)
+
+// newStayTestService monta o serviço de reservas com o relógio fixo em now
+func newStayTestService(repos dao.Repositories, now time.Time) *reservationService {
+	s := NewReservationService(repos.Reservations, repos.Rooms, repos.RatePlans, repos.Guests, repos.Policies, repos.Taxes, repos.Folios, repos.Holds, gateway.NewStubGateway(), 30).(*reservationService)
+	s.now = func() time.Time { return now }
+	return s
+}
+
+// payFolio paga amount na conta da reserva em dinheiro
+func payFolio(t *testing.T, repos dao.Repositories, reservationID string, amount model.Money) {
+	t.Helper()
+	if _, err := NewFolioService(repos.Folios, repos.Reservations, gateway.NewStubGateway()).PostPayment(reservationID, model.PaymentRequest{Method: "CASH", Amount: amount}, testActor); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCheckIn(t *testing.T) {
+	tests := []struct {
+		name       string
+		today      string
+		roomStatus string
+		wantErr    error
+	}{
+		{"on arrival day", "2026-06-10", "ATIVO", nil},
+		{"during the stay", "2026-06-11", "ATIVO", nil},
+		{"before arrival", "2026-06-09", "ATIVO", ErrConflict},
+		{"on departure day", "2026-06-12", "ATIVO", ErrConflict},
+		{"inactive room", "2026-06-10", "INATIVO", ErrConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repos := dao.NewMemoryRepositories()
+			room := model.Room{Number: 101, Type: "STANDARD", Capacity: 2, PricePerNight: model.NewMoney(10000), Status: "ATIVO"}
+			roomID, err := repos.Rooms.InsertRoom(room, testActor)
+			if err != nil {
+				t.Fatal(err)
+			}
+			s := newStayTestService(repos, date(tt.today).Add(14*time.Hour))
+			res, err := s.Create(model.Reservation{RoomID: roomID, GuestName: "Ana", CheckinExpected: "2026-06-10", CheckoutExpected: "2026-06-12"}, "", nil, testActor)
+			if err != nil {
+				t.Fatal(err)
+			}
+			room.ID, room.Status = roomID, tt.roomStatus
+			if err := repos.Rooms.UpdateRoom(room, testActor); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := s.CheckIn(res.ID, "maria", "", testActor)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if err == nil && (got.Status != "CHECKED_IN" || got.CheckedInBy != "maria" || got.CheckedInAt == nil) {
+				t.Fatalf("unexpected reservation %+v", got)
+			}
+		})
+	}
+}
+
+func TestCheckOut(t *testing.T) {
+	tests := []struct {
+		name     string
+		today    string
+		checkout string
+		total    model.Money
+		nights   int
+	}{
+		{"on departure day", "2026-06-13", "2026-06-13", model.NewMoney(30000), 3},
+		{"late", "2026-06-14", "2026-06-13", model.NewMoney(30000), 3},
+		{"one night early", "2026-06-12", "2026-06-12", model.NewMoney(20000), 2},
+		// no mesmo dia do check-in a primeira noite ainda é cobrada
+		{"same day", "2026-06-10", "2026-06-11", model.NewMoney(10000), 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repos := dao.NewMemoryRepositories()
+			roomID, err := repos.Rooms.InsertRoom(model.Room{Number: 101, Type: "STANDARD", Capacity: 2, PricePerNight: model.NewMoney(10000), Status: "ATIVO"}, testActor)
+			if err != nil {
+				t.Fatal(err)
+			}
+			s := newStayTestService(repos, date("2026-06-10").Add(14*time.Hour))
+			res, err := s.Create(model.Reservation{RoomID: roomID, GuestName: "Ana", CheckinExpected: "2026-06-10", CheckoutExpected: "2026-06-13"}, "", nil, testActor)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := s.CheckOut(res.ID, "joao", testActor); !errors.Is(err, ErrInvalidTransition) {
+				t.Fatalf("expected ErrInvalidTransition checking out a reservation that is not checked in, got %v", err)
+			}
+			if _, err := s.CheckIn(res.ID, "maria", "", testActor); err != nil {
+				t.Fatal(err)
+			}
+
+			s.now = func() time.Time { return date(tt.today).Add(11 * time.Hour) }
+			var due *model.BalanceDueError
+			if _, err := s.CheckOut(res.ID, "joao", testActor); !errors.As(err, &due) || !due.Balance.Equal(tt.total) {
+				t.Fatalf("expected a balance of %v due, got %v", tt.total, err)
+			}
+			payFolio(t, repos, res.ID, tt.total)
+			got, err := s.CheckOut(res.ID, "joao", testActor)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Status != "CHECKED_OUT" || got.CheckoutExpected != tt.checkout || !got.TotalAmount.Equal(tt.total) || len(got.Nights) != tt.nights {
+				t.Fatalf("expected checkout %s, total %v over %d nights, got %+v", tt.checkout, tt.total, tt.nights, got)
+			}
+			stored, _ := s.GetByID(res.ID)
+			if !stored.TotalAmount.Equal(tt.total) || stored.CheckedInBy != "maria" || stored.CheckedOutBy != "joao" {
+				t.Fatalf("expected the final bill to be stored, got %+v", stored)
+			}
+		})
+	}
+}
+
+func TestValidateStatusTransition(t *testing.T) {
+	tests := []struct {
+		current, next string
+		allowed       bool
+	}{
+		{"CREATED", "CREATED", true},
+		{"CANCELED", "CANCELED", true},
+		{"NO_SHOW", "NO_SHOW", true},
+		{"CONFIRMED", "CONFIRMED", true},
+		{"CREATED", "CONFIRMED", true},
+		{"CREATED", "CANCELED", true},
+		{"CREATED", "CHECKED_IN", true},
+		{"CONFIRMED", "NO_SHOW", true},
+		{"CHECKED_IN", "CHECKED_OUT", true},
+		{"CREATED", "NO_SHOW", true},
+		{"CONFIRMED", "CREATED", false},
+		{"CHECKED_IN", "CANCELED", false},
+		{"CREATED", "CHECKED_OUT", false},
+		{"NO_SHOW", "CREATED", false},
+		{"CANCELED", "CREATED", false},
+		{"CHECKED_OUT", "CREATED", false},
+		{"CREATED", "PENDING", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.current+"->"+tt.next, func(t *testing.T) {
+			err := validateStatusTransition(tt.current, tt.next)
+			if tt.allowed && err != nil {
+				t.Fatalf("expected the transition to be allowed, got %v", err)
+			}
+			if !tt.allowed && !errors.Is(err, ErrInvalidTransition) {
+				t.Fatalf("expected ErrInvalidTransition, got %v", err)
+			}
+		})
+	}
+}
+
+// TestUpdateStatusActions confere que o PUT leva a reserva a cada status pela ação própria
+// dele, com as mesmas regras
+func TestUpdateStatusActions(t *testing.T) {
+	repos := dao.NewMemoryRepositories()
+	rooms := insertRooms(t, repos,
+		model.Room{Number: 101, Type: "STANDARD", Capacity: 2, PricePerNight: model.NewMoney(10000), Status: "ATIVO"},
+		model.Room{Number: 102, Type: "STANDARD", Capacity: 2, PricePerNight: model.NewMoney(10000), Status: "ATIVO"},
+		model.Room{Number: 103, Type: "STANDARD", Capacity: 2, PricePerNight: model.NewMoney(10000), Status: "ATIVO"},
+	)
+	s := newStayTestService(repos, date("2026-06-09").Add(14*time.Hour))
+	create := func(roomID string) model.Reservation {
+		t.Helper()
+		res, err := s.Create(model.Reservation{RoomID: roomID, GuestName: "Ana", CheckinExpected: "2026-06-10", CheckoutExpected: "2026-06-12"}, "", nil, testActor)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res
+	}
+	withStatus := func(res model.Reservation, status string) model.Reservation {
+		res.Status = status
+		return res
+	}
+	staying, canceled, unarrived := create(rooms[101]), create(rooms[102]), create(rooms[103])
+
+	// CONFIRMED pré-autoriza o depósito com o cartão do corpo
+	if _, err := s.Update(withStatus(staying, "CONFIRMED"), nil, testActor); !isValidationError(err) {
+		t.Fatalf("expected a validation error without a card, got %v", err)
+	}
+	confirmed, err := s.Update(withStatus(staying, "CONFIRMED"), &model.CardPaymentRequest{CardToken: "tok_visa"}, testActor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if confirmed.Status != "CONFIRMED" || len(authorizations(t, repos, staying.ID)) != 1 {
+		t.Fatalf("expected a CONFIRMED reservation with one authorization, got %+v", confirmed)
+	}
+
+	// os demais campos são gravados antes da ação, que mantém as suas regras
+	if _, err := s.Update(withStatus(confirmed, "CHECKED_IN"), nil, testActor); !isServiceError(err, "checkin_before_arrival") {
+		t.Fatalf("expected checkin_before_arrival, got %v", err)
+	}
+	if _, err := s.Update(withStatus(unarrived, "NO_SHOW"), nil, testActor); !isServiceError(err, "no_show_before_arrival") {
+		t.Fatalf("expected no_show_before_arrival, got %v", err)
+	}
+	cancel := withStatus(canceled, "CANCELED")
+	cancel.CheckoutExpected = "2026-06-13"
+	canceled, err = s.Update(cancel, nil, testActor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if canceled.Status != "CANCELED" || canceled.CanceledBy != testActor.Username || canceled.CheckoutExpected != "2026-06-13" {
+		t.Fatalf("expected a CANCELED reservation until 2026-06-13, got %+v", canceled)
+	}
+
+	s.now = func() time.Time { return date("2026-06-10").Add(14 * time.Hour) }
+	checkedIn, err := s.Update(withStatus(confirmed, "CHECKED_IN"), nil, testActor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if checkedIn.Status != "CHECKED_IN" || checkedIn.CheckedInBy != testActor.Username {
+		t.Fatalf("expected a check-in by %s, got %+v", testActor.Username, checkedIn)
+	}
+	if _, err := s.Update(withStatus(checkedIn, "CANCELED"), nil, testActor); !errors.Is(err, ErrInvalidTransition) {
+		t.Fatalf("expected ErrInvalidTransition canceling a CHECKED_IN reservation, got %v", err)
+	}
+
+	s.now = func() time.Time { return date("2026-06-12").Add(10 * time.Hour) }
+	payFolio(t, repos, staying.ID, model.NewMoney(14000))
+	checkedOut, err := s.Update(withStatus(checkedIn, "CHECKED_OUT"), nil, testActor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if checkedOut.Status != "CHECKED_OUT" || checkedOut.CheckedOutBy != testActor.Username {
+		t.Fatalf("expected a check-out by %s, got %+v", testActor.Username, checkedOut)
+	}
+
+	noShow, err := s.Update(withStatus(unarrived, "NO_SHOW"), nil, testActor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if noShow.Status != "NO_SHOW" || noShow.NoShowAt == nil {
+		t.Fatalf("expected a NO_SHOW reservation, got %+v", noShow)
+	}
+}
+
+func date(value string) time.Time {
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestCancel(t *testing.T) {
+	repos := dao.NewMemoryRepositories()
+	roomID, err := repos.Rooms.InsertRoom(model.Room{Number: 101, Type: "STANDARD", Capacity: 2, PricePerNight: model.NewMoney(10000), Status: "ATIVO"}, testActor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := date("2026-06-01").Add(9 * time.Hour)
+	s := newStayTestService(repos, now)
+	res, err := s.Create(model.Reservation{RoomID: roomID, GuestName: "Ana", CheckinExpected: "2026-06-10", CheckoutExpected: "2026-06-12"}, "", nil, testActor)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	long := make([]byte, 201)
+	for i := range long {
+		long[i] = 'a'
+	}
+	if _, err := s.Cancel(res.ID, string(long), testActor); !isValidationError(err) {
+		t.Fatalf("expected a validation error for a long reason, got %v", err)
+	}
+	if _, err := s.Cancel("00000000-0000-0000-0000-000000000000", "", testActor); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	canceled, err := s.Cancel(res.ID, "guest request", model.Actor{Username: "maria"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if canceled.Status != "CANCELED" || canceled.CanceledAt == nil || !canceled.CanceledAt.Equal(now) ||
+		canceled.CanceledBy != "maria" || canceled.CancellationReason != "guest request" {
+		t.Fatalf("unexpected reservation %+v", canceled)
+	}
+	if stored, _ := s.GetByID(res.ID); stored.Status != "CANCELED" {
+		t.Fatalf("expected the reservation to be kept as CANCELED, got %+v", stored)
+	}
+
+	if _, err := s.Cancel(res.ID, "", testActor); !errors.Is(err, ErrInvalidTransition) {
+		t.Fatalf("expected ErrInvalidTransition canceling twice, got %v", err)
+	}
+	if _, err := s.CheckIn(res.ID, "maria", "", testActor); !errors.Is(err, ErrInvalidTransition) {
+		t.Fatalf("expected ErrInvalidTransition checking in a canceled reservation, got %v", err)
+	}
+}
@@ -0,0 +1,274 @@
+package service
+
+import (
+	"errors"
+	"hotel-soa/dao"
+	"hotel-soa/model"
+	"sort"
+	"time"
+)
+
+type TaxService interface {
+	Create(rule model.TaxRule) (string, error)
+	// Update vale para as reservas criadas ou recalculadas depois; os impostos já
+	// calculados ficam como estão
+	Update(rule model.TaxRule) error
+	Delete(id string) error
+	GetByID(id string) (model.TaxRule, error)
+	GetAll() ([]model.TaxRule, error)
+	// Report soma os impostos das noites lançadas pelo night audit entre from e to, por
+	// código e, com groupBy day ou month, por período
+	Report(from, to, groupBy string) (model.TaxReport, error)
+}
+
+type taxService struct {
+	taxes dao.TaxRepository
+}
+
+func NewTaxService(taxes dao.TaxRepository) TaxService {
+	return &taxService{taxes: taxes}
+}
+
+func (s *taxService) Create(rule model.TaxRule) (string, error) {
+	id, err := s.taxes.InsertTaxRule(rule)
+	if err != nil {
+		return "", mapDuplicateTaxCode(err)
+	}
+	return id, nil
+}
+
+func (s *taxService) Update(rule model.TaxRule) error {
+	if _, err := s.GetByID(rule.ID); err != nil {
+		return err
+	}
+	return mapDuplicateTaxCode(s.taxes.UpdateTaxRule(rule))
+}
+
+func (s *taxService) Delete(id string) error {
+	if _, err := s.GetByID(id); err != nil {
+		return err
+	}
+	return s.taxes.DeleteTaxRule(id)
+}
+
+func (s *taxService) GetByID(id string) (model.TaxRule, error) {
+	rule, err := s.taxes.GetTaxRuleByID(id)
+	if err != nil {
+		return model.TaxRule{}, err
+	}
+	if rule.ID == "" {
+		return model.TaxRule{}, notFound("tax_rule_not_found", "tax rule %s not found", id)
+	}
+	return rule, nil
+}
+
+func (s *taxService) GetAll() ([]model.TaxRule, error) {
+	return s.taxes.GetAllTaxRules()
+}
+
+func (s *taxService) Report(from, to, groupBy string) (model.TaxReport, error) {
+	start, end, err := parseReportPeriod(from, to)
+	if err != nil {
+		return model.TaxReport{}, err
+	}
+	if groupBy != "" && groupBy != model.TaxGroupDay && groupBy != model.TaxGroupMonth {
+		var v model.ValidationError
+		v.Add("group_by", "invalid_value", "must be one of: day, month")
+		return model.TaxReport{}, v.Err()
+	}
+
+	lines, err := s.taxes.GetPostedTaxes(start, end)
+	if err != nil {
+		return model.TaxReport{}, err
+	}
+
+	report := model.TaxReport{
+		From:           from,
+		To:             to,
+		Currency:       model.Currency(),
+		GroupBy:        groupBy,
+		Lines:          []model.TaxReportLine{},
+		Total:          model.NewMoney(0),
+		TotalInclusive: model.NewMoney(0),
+	}
+	type key struct {
+		period, code string
+		inclusive    bool
+	}
+	index := map[key]int{}
+	for _, line := range lines {
+		period := reportPeriod(line.Date, groupBy)
+		k := key{period, line.Code, line.Inclusive}
+		i, ok := index[k]
+		if !ok {
+			i = len(report.Lines)
+			index[k] = i
+			report.Lines = append(report.Lines, model.TaxReportLine{
+				Period: period, Code: line.Code, Name: line.Name, Inclusive: line.Inclusive, Amount: model.NewMoney(0),
+			})
+		}
+		report.Lines[i].Amount = report.Lines[i].Amount.Add(line.Amount)
+		if line.Inclusive {
+			report.TotalInclusive = report.TotalInclusive.Add(line.Amount)
+		} else {
+			report.Total = report.Total.Add(line.Amount)
+		}
+	}
+	sort.SliceStable(report.Lines, func(i, j int) bool {
+		a, b := report.Lines[i], report.Lines[j]
+		if a.Period != b.Period {
+			return a.Period < b.Period
+		}
+		if a.Code != b.Code {
+			return a.Code < b.Code
+		}
+		return !a.Inclusive && b.Inclusive
+	})
+	return report, nil
+}
+
+// ---------------- HELPERS ----------------
+
+// mapDuplicateTaxCode traduz a violação de unicidade de code
+func mapDuplicateTaxCode(err error) error {
+	if errors.Is(err, dao.ErrDuplicateTaxCode) {
+		return conflict("duplicate_tax_code", "%s", err.Error())
+	}
+	return err
+}
+
+// parseReportPeriod valida from e to (YYYY-MM-DD, from até to) do relatório
+func parseReportPeriod(from, to string) (time.Time, time.Time, error) {
+	var v model.ValidationError
+	start, err := time.Parse("2006-01-02", from)
+	if err != nil {
+		v.Add("from", "invalid_format", "expected YYYY-MM-DD")
+	}
+	end, err := time.Parse("2006-01-02", to)
+	if err != nil {
+		v.Add("to", "invalid_format", "expected YYYY-MM-DD")
+	}
+	if len(v.Errors) == 0 && end.Before(start) {
+		v.Add("to", "out_of_range", "must not be before from")
+	}
+	return start, end, v.Err()
+}
+
+// reportPeriod é o período da linha no relatório: o dia, o mês ou vazio sem agrupamento
+func reportPeriod(date, groupBy string) string {
+	switch groupBy {
+	case model.TaxGroupDay:
+		return date
+	case model.TaxGroupMonth:
+		return date[:7]
+	}
+	return ""
+}
+
+// taxEngine calcula os impostos de uma estadia pelas regras ativas e pelo tipo do hóspede
+type taxEngine struct {
+	rules  dao.TaxRepository
+	guests dao.GuestRepository
+}
+
+func newTaxEngine(rules dao.TaxRepository, guests dao.GuestRepository) *taxEngine {
+	return &taxEngine{rules: rules, guests: guests}
+}
+
+// Apply preenche os impostos das noites de res para o seu hóspede. Sempre grava um slice
+// não nil, para que o repositório substitua os impostos anteriores
+func (t *taxEngine) Apply(res *model.Reservation) error {
+	rules, err := t.rules.GetAllTaxRules()
+	if err != nil {
+		return err
+	}
+	guest, err := t.guests.GetGuestByID(res.GuestID)
+	if err != nil {
+		return err
+	}
+	res.Taxes = computeTaxes(rules, guest.Type, res.Nights)
+	return nil
+}
+
+// computeTaxes aplica as regras ativas às noites. O preço das noites já traz os impostos
+// inclusive, então todo imposto percentual incide sobre o preço sem eles: p/(100+soma
+// dos percentuais inclusive) do preço, arredondado por model.TaxRounding. O divisor
+// considera todas as regras inclusive ativas, inclusive as de que o hóspede é isento, e
+// por isso a isenção não muda o preço do quarto. Impostos PER_STAY ficam na noite de
+// chegada
+func computeTaxes(rules []model.TaxRule, guestType string, nights []model.NightlyRate) []model.TaxLine {
+	taxes := []model.TaxLine{}
+	if len(nights) == 0 {
+		return taxes
+	}
+
+	divisor := 100.0
+	for _, rule := range rules {
+		if rule.Active && rule.Inclusive && rule.Calculation == model.TaxPercent {
+			divisor += rule.Percent
+		}
+	}
+	stay := model.NewMoney(0)
+	for _, night := range nights {
+		stay = stay.Add(night.Price)
+	}
+
+	for _, rule := range rules {
+		if !rule.Active || rule.Exempts(guestType) {
+			continue
+		}
+		line := model.TaxLine{TaxRuleID: rule.ID, Code: rule.Code, Name: rule.Name, Inclusive: rule.Inclusive}
+		add := func(date string, amount model.Money) {
+			if !amount.IsZero() {
+				line.Date, line.Amount = date, amount
+				taxes = append(taxes, line)
+			}
+		}
+		switch {
+		case rule.Basis == model.TaxPerStay && rule.Calculation == model.TaxPercent:
+			add(nights[0].Date, stay.Ratio(rule.Percent, divisor, model.TaxRounding))
+		case rule.Basis == model.TaxPerStay:
+			add(nights[0].Date, rule.Amount)
+		case rule.Calculation == model.TaxPercent:
+			for _, night := range nights {
+				add(night.Date, night.Price.Ratio(rule.Percent, divisor, model.TaxRounding))
+			}
+		default:
+			for _, night := range nights {
+				add(night.Date, rule.Amount)
+			}
+		}
+	}
+	// mesma ordem em que o repositório devolve os impostos: noite e código
+	sort.SliceStable(taxes, func(i, j int) bool {
+		if taxes[i].Date != taxes[j].Date {
+			return taxes[i].Date < taxes[j].Date
+		}
+		return taxes[i].Code < taxes[j].Code
+	})
+	return taxes
+}
+
+// exclusiveTaxes soma os impostos cobrados à parte, que entram no total_amount
+func exclusiveTaxes(taxes []model.TaxLine) model.Money {
+	total := model.NewMoney(0)
+	for _, tax := range taxes {
+		if !tax.Inclusive {
+			total = total.Add(tax.Amount)
+		}
+	}
+	return total
+}
+
+// chargeTaxes mantém os impostos das noites até a saída, pelos valores calculados na
+// reserva. Os PER_STAY estão na noite de chegada e continuam cobrados por inteiro
+func chargeTaxes(res model.Reservation, departure time.Time) []model.TaxLine {
+	end := departure.Format("2006-01-02")
+	taxes := []model.TaxLine{}
+	for _, tax := range res.Taxes {
+		if tax.Date < end {
+			taxes = append(taxes, tax)
+		}
+	}
+	return taxes
+}
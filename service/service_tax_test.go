@@ -0,0 +1,158 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"hotel-soa/dao"
+	"hotel-soa/model"
+)
+
+// insertTaxRules grava ISS de 5% embutido, serviço de 10% à parte (isento para
+// diplomatas) e taxa de turismo de 5,00 por estadia
+func insertTaxRules(t *testing.T, taxes TaxService) {
+	t.Helper()
+	rules := []model.TaxRule{
+		{Code: "ISS", Name: "ISS", Calculation: model.TaxPercent, Percent: 5, Basis: model.TaxPerNight, Inclusive: true, ExemptGuestTypes: []string{}, Active: true},
+		{Code: "SERV", Name: "Serviço", Calculation: model.TaxPercent, Percent: 10, Basis: model.TaxPerNight, ExemptGuestTypes: []string{model.GuestDiplomat}, Active: true},
+		{Code: "TOUR", Name: "Turismo", Calculation: model.TaxFixed, Amount: model.NewMoney(500), Basis: model.TaxPerStay, ExemptGuestTypes: []string{}, Active: true},
+		{Code: "OLD", Name: "Inativo", Calculation: model.TaxFixed, Amount: model.NewMoney(9900), Basis: model.TaxPerStay, ExemptGuestTypes: []string{}},
+	}
+	for _, rule := range rules {
+		if _, err := taxes.Create(rule); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestReservationTaxes(t *testing.T) {
+	repos := dao.NewMemoryRepositories()
+	insertTaxRules(t, NewTaxService(repos.Taxes))
+	roomID, err := repos.Rooms.InsertRoom(model.Room{Number: 101, Type: "STANDARD", Capacity: 2, PricePerNight: model.NewMoney(10500), Status: "ATIVO"}, testActor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	diplomatID, err := repos.Guests.InsertGuest(model.Guest{Name: "Embaixada", Type: model.GuestDiplomat})
+	if err != nil {
+		t.Fatal(err)
+	}
+	reservations := newStayTestService(repos, date("2026-06-10").Add(14*time.Hour))
+
+	tests := []struct {
+		name    string
+		guest   model.Reservation
+		total   int64
+		summary map[string]int64
+	}{
+		// 105,00 por noite já com 5% de ISS: 5,00 de ISS e 10,00 de serviço por noite
+		{"individual", model.Reservation{GuestName: "Ana"}, 23500, map[string]int64{"ISS": 1000, "SERV": 2000, "TOUR": 500}},
+		// a isenção tira o serviço, mas o ISS embutido continua no preço
+		{"diplomat", model.Reservation{GuestID: diplomatID}, 21500, map[string]int64{"ISS": 1000, "TOUR": 500}},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := tt.guest
+			res.RoomID = roomID
+			res.CheckinExpected = date("2026-07-01").AddDate(0, 0, 3*i).Format("2006-01-02")
+			res.CheckoutExpected = date("2026-07-01").AddDate(0, 0, 3*i+2).Format("2006-01-02")
+			created, err := reservations.Create(res, "", nil, testActor)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if created.TotalAmount.Minor() != tt.total {
+				t.Fatalf("expected a total of %d, got %s", tt.total, created.TotalAmount)
+			}
+			summary := model.SummarizeTaxes(created.Taxes)
+			if len(summary) != len(tt.summary) {
+				t.Fatalf("expected %v, got %+v", tt.summary, summary)
+			}
+			for _, tax := range summary {
+				if tax.Amount.Minor() != tt.summary[tax.Code] || tax.Inclusive != (tax.Code == "ISS") {
+					t.Fatalf("unexpected %+v, expected %v", tax, tt.summary)
+				}
+			}
+			if stored, _ := reservations.GetByID(created.ID); !stored.TotalAmount.Equal(created.TotalAmount) || len(stored.Taxes) != len(created.Taxes) {
+				t.Fatalf("expected the stored reservation to match, got %+v", stored)
+			}
+		})
+	}
+}
+
+func TestTaxService(t *testing.T) {
+	repos := dao.NewMemoryRepositories()
+	taxes := NewTaxService(repos.Taxes)
+	insertTaxRules(t, taxes)
+
+	rules, err := taxes.GetAll()
+	if err != nil || len(rules) != 4 {
+		t.Fatalf("expected 4 rules, got %+v, %v", rules, err)
+	}
+	if _, err := taxes.Create(model.TaxRule{Code: "ISS", Name: "Outro", Calculation: model.TaxPercent, Percent: 2, Basis: model.TaxPerNight}); !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected ErrConflict for a duplicate code, got %v", err)
+	}
+	if err := taxes.Update(model.TaxRule{ID: "00000000-0000-0000-0000-000000000000", Code: "X"}); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+	if err := taxes.Delete(rules[0].ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := taxes.GetByID(rules[0].ID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound after delete, got %v", err)
+	}
+}
+
+func TestTaxReport(t *testing.T) {
+	repos := dao.NewMemoryRepositories()
+	taxes := NewTaxService(repos.Taxes)
+	insertTaxRules(t, taxes)
+	roomID, err := repos.Rooms.InsertRoom(model.Room{Number: 101, Type: "STANDARD", Capacity: 2, PricePerNight: model.NewMoney(10500), Status: "ATIVO"}, testActor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reservations := newStayTestService(repos, date("2026-06-01"))
+	res, err := reservations.Create(model.Reservation{RoomID: roomID, GuestName: "Ana", CheckinExpected: "2026-06-30", CheckoutExpected: "2026-07-02"}, "", nil, testActor)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// só as noites lançadas pelo night audit entram no relatório
+	for _, night := range []string{"2026-06-30", "2026-07-01"} {
+		closedAt := date(night).Add(27 * time.Hour)
+		charges := []model.RoomCharge{{ReservationID: res.ID, BusinessDate: night, Amount: model.NewMoney(10500), PostedAt: closedAt}}
+		if err := repos.NightAudits.CloseBusinessDate(model.NightAudit{BusinessDate: night, ClosedAt: closedAt, ChargesPosted: 1}, charges); err != nil {
+			t.Fatal(err)
+		}
+	}
+	report, err := taxes.Report("2026-06-01", "2026-07-31", model.TaxGroupMonth)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []model.TaxReportLine{
+		{Period: "2026-06", Code: "ISS", Name: "ISS", Inclusive: true, Amount: model.NewMoney(500)},
+		{Period: "2026-06", Code: "SERV", Name: "Serviço", Amount: model.NewMoney(1000)},
+		{Period: "2026-06", Code: "TOUR", Name: "Turismo", Amount: model.NewMoney(500)},
+		{Period: "2026-07", Code: "ISS", Name: "ISS", Inclusive: true, Amount: model.NewMoney(500)},
+		{Period: "2026-07", Code: "SERV", Name: "Serviço", Amount: model.NewMoney(1000)},
+	}
+	if len(report.Lines) != len(expected) {
+		t.Fatalf("expected %+v, got %+v", expected, report.Lines)
+	}
+	for i, line := range report.Lines {
+		if line.Period != expected[i].Period || line.Code != expected[i].Code || line.Inclusive != expected[i].Inclusive || !line.Amount.Equal(expected[i].Amount) {
+			t.Fatalf("line %d: expected %+v, got %+v", i, expected[i], line)
+		}
+	}
+	if report.Total.Minor() != 2500 || report.TotalInclusive.Minor() != 1000 || report.Currency != model.Currency() {
+		t.Fatalf("expected 25.00 charged apart and 10.00 inclusive, got %+v", report)
+	}
+
+	if report, _ := taxes.Report("2026-07-01", "2026-07-01", ""); len(report.Lines) != 2 || report.Lines[0].Period != "" {
+		t.Fatalf("expected two lines without a period, got %+v", report.Lines)
+	}
+	for _, args := range [][3]string{{"2026-07-02", "2026-07-01", ""}, {"julho", "2026-07-01", ""}, {"2026-07-01", "2026-07-01", "week"}} {
+		if _, err := taxes.Report(args[0], args[1], args[2]); !isValidationError(err) {
+			t.Fatalf("%v: expected a validation error, got %v", args, err)
+		}
+	}
+}
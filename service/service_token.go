@@ -0,0 +1,91 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+const (
+	accessTokenType  = "access"
+	refreshTokenType = "refresh"
+)
+
+// jwtHeader é fixo: só emitimos e aceitamos HS256
+var jwtHeader = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+// tokenClaims são as claims dos JWT emitidos pela API; Type separa access de refresh e
+// Version, só nos refresh tokens, é a versão de tokens do usuário na emissão
+type tokenClaims struct {
+	Subject   string `json:"sub"`
+	Username  string `json:"name"`
+	Type      string `json:"typ"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+	ID        string `json:"jti"`
+	Version   int    `json:"ver,omitempty"`
+}
+
+// tokenSigner emite e valida JWT HS256
+type tokenSigner struct {
+	secret     []byte
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+}
+
+func (t *tokenSigner) sign(claims tokenClaims) (string, error) {
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	unsigned := jwtHeader + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return unsigned + "." + t.signature(unsigned), nil
+}
+
+// parse confere assinatura, validade e tipo do token
+func (t *tokenSigner) parse(token, tokenType string, now time.Time) (tokenClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || parts[0] != jwtHeader {
+		return tokenClaims{}, unauthorized("invalid_token", "malformed token")
+	}
+	expected := t.signature(parts[0] + "." + parts[1])
+	if !hmac.Equal([]byte(parts[2]), []byte(expected)) {
+		return tokenClaims{}, unauthorized("invalid_token", "invalid token signature")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return tokenClaims{}, unauthorized("invalid_token", "malformed token")
+	}
+	var claims tokenClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return tokenClaims{}, unauthorized("invalid_token", "malformed token")
+	}
+	if claims.Type != tokenType {
+		return tokenClaims{}, unauthorized("invalid_token", "token type must be %s", tokenType)
+	}
+	if now.Unix() >= claims.ExpiresAt {
+		return tokenClaims{}, unauthorized("token_expired", "token expired")
+	}
+	return claims, nil
+}
+
+func (t *tokenSigner) signature(unsigned string) string {
+	mac := hmac.New(sha256.New, t.secret)
+	mac.Write([]byte(unsigned))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// randomHex gera n bytes aleatórios codificados em hex
+func randomHex(n int) string {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
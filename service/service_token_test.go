@@ -0,0 +1,61 @@
+package service
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTokenSignerParse(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	signer := &tokenSigner{secret: []byte("test-secret")}
+	sign := func(t *testing.T, s *tokenSigner, claims tokenClaims) string {
+		t.Helper()
+		token, err := s.sign(claims)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+	valid := tokenClaims{Subject: "user-1", Username: "ana", Type: accessTokenType, IssuedAt: now.Unix(), ExpiresAt: now.Add(15 * time.Minute).Unix(), ID: "jti"}
+	token := sign(t, signer, valid)
+	parts := strings.Split(token, ".")
+
+	tests := []struct {
+		name      string
+		token     string
+		tokenType string
+		at        time.Time
+		code      string
+	}{
+		{"valid", token, accessTokenType, now, ""},
+		{"last valid second", token, accessTokenType, now.Add(15*time.Minute - time.Second), ""},
+		{"expired", token, accessTokenType, now.Add(15 * time.Minute), "token_expired"},
+		{"refresh used as access", sign(t, signer, tokenClaims{Subject: "user-1", Type: refreshTokenType, ExpiresAt: valid.ExpiresAt}), accessTokenType, now, "invalid_token"},
+		{"other secret", sign(t, &tokenSigner{secret: []byte("other-secret")}, valid), accessTokenType, now, "invalid_token"},
+		{"tampered payload", parts[0] + "." + base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"admin","typ":"access","exp":9999999999}`)) + "." + parts[2], accessTokenType, now, "invalid_token"},
+		{"other algorithm", base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","typ":"JWT"}`)) + "." + parts[1] + ".", accessTokenType, now, "invalid_token"},
+		{"malformed", "not-a-token", accessTokenType, now, "invalid_token"},
+		{"empty", "", accessTokenType, now, "invalid_token"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := signer.parse(tt.token, tt.tokenType, tt.at)
+			if tt.code == "" {
+				if err != nil {
+					t.Fatalf("expected a valid token, got %v", err)
+				}
+				if claims != valid {
+					t.Fatalf("expected claims %+v, got %+v", valid, claims)
+				}
+				return
+			}
+			var serviceErr *Error
+			if !errors.As(err, &serviceErr) || !errors.Is(err, ErrUnauthorized) || serviceErr.Code != tt.code {
+				t.Fatalf("expected unauthorized %s, got %v", tt.code, err)
+			}
+		})
+	}
+}